@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"grveyard/db"
+	"grveyard/pkg/admin"
+	"grveyard/pkg/config"
+	"grveyard/pkg/quota"
+	"grveyard/pkg/testhelpers/seed"
+	"grveyard/pkg/users"
+)
+
+// commandContext is cancelled on SIGINT/SIGTERM so long statements are abandoned.
+func commandContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	force := fs.Bool("force", false, "confirm that \"down\" may drop every table and its data")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: server migrate up|down [-force]")
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		return errors.New("missing direction")
+	}
+	direction := args[0]
+	fs.Parse(args[1:])
+
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	switch direction {
+	case "up":
+		pool := db.Open()
+		defer pool.Close()
+		return db.ApplySchema(ctx, pool)
+	case "down":
+		if !*force {
+			return errors.New("refusing to drop all tables without -force")
+		}
+		pool := db.Open()
+		defer pool.Close()
+		return db.RevertSchema(ctx, pool)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown direction %q", direction)
+	}
+}
+
+func runSeed(args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	generate := fs.Int("users", 0, "also generate this many random users with listings, chats and sales")
+	rngSeed := fs.Uint64("seed", 1, "random seed for -users; the same seed always generates the same data")
+	fs.Parse(args)
+
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	pool := db.Open()
+	defer pool.Close()
+	if err := db.ApplySeed(ctx, pool); err != nil {
+		return err
+	}
+	if *generate <= 0 {
+		return nil
+	}
+
+	// One transaction, so an interrupted run leaves nothing behind to block a retry
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	sum, err := seed.Generate(ctx, tx, seed.Config{Seed: *rngSeed, Users: *generate})
+	if errors.Is(err, seed.ErrAlreadyGenerated) {
+		fmt.Printf("Data for seed %d already exists; pass a different -seed to add more\n", *rngSeed)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	fmt.Printf("Generated %d users, %d startups, %d assets, %d messages, %d sales\n",
+		sum.Users, sum.Startups, sum.Assets, sum.Messages, sum.Transactions)
+	return nil
+}
+
+func runCreateAdmin(args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	name := fs.String("name", "", "display name (required)")
+	email := fs.String("email", "", "login email (required)")
+	password := fs.String("password", "", "password; defaults to ADMIN_PASSWORD, then a line read from stdin")
+	fs.Parse(args)
+
+	if *name == "" || *email == "" {
+		fs.Usage()
+		return errors.New("-name and -email are required")
+	}
+	if *password == "" {
+		*password = os.Getenv("ADMIN_PASSWORD")
+	}
+	if *password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("read password: %w", err)
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	pool := db.Open()
+	defer pool.Close()
+
+	passwordCfg, err := config.LoadPassword()
+	if err != nil {
+		return err
+	}
+	passwords := users.NewPasswordHasher(passwordCfg.Algo, passwordCfg.BcryptCost)
+	u, err := users.NewUserService(users.NewPostgresUserRepository(pool), passwords).CreateAdmin(ctx, *name, strings.ToLower(*email), *password)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created admin %s (uuid %s)\n", u.Email, u.UUID)
+	return nil
+}
+
+func runCreateAPIKey(args []string) error {
+	fs := flag.NewFlagSet("create-api-key", flag.ExitOnError)
+	user := fs.String("user", "", "UUID of the user the key belongs to (required)")
+	tier := fs.String("tier", string(quota.TierPartner), "quota tier: buyer, founder, verified_founder, partner or admin")
+	fs.Parse(args)
+
+	if *user == "" {
+		fs.Usage()
+		return errors.New("-user is required")
+	}
+	plans, err := quota.ParsePlans(os.Getenv("API_QUOTAS"))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	pool := db.Open()
+	defer pool.Close()
+
+	key, created, err := quota.NewService(quota.NewPostgresUsageRepository(pool), plans).CreateKey(ctx, *user, quota.Tier(*tier))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created %s key %d for %s. It is shown only once:\n%s\n", created.Tier, created.ID, created.UserUUID, key)
+	return nil
+}
+
+func runPurgeSoftDeleted(args []string) error {
+	fs := flag.NewFlagSet("purge-soft-deleted", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "report what would be removed without deleting")
+	fs.Parse(args)
+
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	pool := db.Open()
+	defer pool.Close()
+
+	counts, err := admin.PurgeSoftDeleted(ctx, pool, *dryRun)
+	if err != nil {
+		return err
+	}
+	verb := "Purged"
+	if *dryRun {
+		verb = "Would purge"
+	}
+	fmt.Printf("%s %d assets, %d startups, %d users\n", verb, counts.Assets, counts.Startups, counts.Users)
+	return nil
+}
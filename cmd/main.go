@@ -2,155 +2,65 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
-	"net/http"
 	"os"
-	"os/signal"
-	"strings"
-	"time"
 
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 
-	"grveyard/db"
-	_ "grveyard/docs"
-	"grveyard/pkg/assets"
-	"grveyard/pkg/buy"
-	"grveyard/pkg/chat"
-	"grveyard/pkg/otp"
-	"grveyard/pkg/sendemail"
-	"grveyard/pkg/startups"
-	"grveyard/pkg/users"
+	"grveyard/pkg/config"
 )
 
-// @title           Graveyard API
-// @version         1.0
-// @description     REST API for failed startup marketplace - buy and sell startup assets
+const usage = `Usage: server [command] [flags]
 
-// @contact.email  virajrathod631@gmail.com
+Commands:
+  serve                 Run the HTTP API and background jobs (default)
+  migrate up|down       Apply the schema, or drop every table (down needs -force)
+  seed                  Load demo data from db/seed.sql; -users N adds generated data
+  create-admin          Create a verified admin account
+  create-api-key        Issue an API key with its own request quota to a user
+  purge-soft-deleted    Permanently remove soft-deleted assets, startups and users
 
-// @host      grveyard-backend.onrender.com
-// @BasePath  /
-
-// @schemes   http https
+Run "server <command> -h" for a command's flags.
+`
 
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using environment variables")
 	}
 
-	pool := db.Connect()
-	defer pool.Close()
-
-	emailService := sendemail.NewEmailService()
-
-	startupsRepo := startups.NewPostgresStartupRepository(pool)
-	startupsService := startups.NewStartupService(startupsRepo)
-	startupsHandler := startups.NewStartupHandler(startupsService)
-
-	assetsRepo := assets.NewPostgresAssetRepository(pool)
-	assetsService := assets.NewAssetService(assetsRepo)
-	assetsHandler := assets.NewAssetHandler(assetsService)
-
-	buyRepo := buy.NewPostgresBuyRepository(pool)
-	buyService := buy.NewBuyService(buyRepo)
-	buyHandler := buy.NewBuyHandler(buyService)
-
-	usersRepo := users.NewPostgresUserRepository(pool)
-	usersService := users.NewUserService(usersRepo)
-	usersHandler := users.NewUserHandler(usersService)
-
-	otpRepo := otp.NewPostgresOTPRepository(pool)
-	otpService := otp.NewOTPService(otpRepo, usersRepo, emailService)
-	otpHandler := otp.NewOTPHandler(otpService)
-
-	// Chat setup
-	chatManager := chat.NewConnectionManager()
-	chatHandler := chat.NewHandler(chatManager)
-	// Inject message store for persistence
-	msgRepo := chat.NewPostgresMessageStore(pool)
-	chatHandler.SetRepository(msgRepo)
-
-	router := gin.New()
-	router.Use(gin.Logger(), gin.Recovery())
-
-	// CORS configuration
-	allowedOrigins := os.Getenv("CORS_ALLOWED_ORIGINS")
-	var origins []string
-	if allowedOrigins == "" {
-		origins = []string{"*"}
-	} else {
-		parts := strings.Split(allowedOrigins, ",")
-		origins = make([]string, 0, len(parts))
-		for _, p := range parts {
-			o := strings.TrimSpace(p)
-			if o != "" {
-				origins = append(origins, o)
-			}
-		}
-		if len(origins) == 0 {
-			origins = []string{"*"}
-		}
-	}
-
-	allowCreds := strings.EqualFold(os.Getenv("CORS_ALLOW_CREDENTIALS"), "true")
-
-	corsCfg := cors.Config{
-		AllowOrigins:     origins,
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: allowCreds,
-		MaxAge:           12 * time.Hour,
+	// Swap vault:// and aws-sm:// references in the environment for the real secrets
+	// before anything reads them
+	secrets, err := config.LoadSecrets(context.Background())
+	if err != nil {
+		log.Fatal("Failed to load secrets:", err)
 	}
-	// If wildcard '*' is used with credentials=false, it's valid; otherwise list explicit origins
-	router.Use(cors.New(corsCfg))
-
-	startupsHandler.RegisterRoutes(router)
-	assetsHandler.RegisterRoutes(router)
-	buyHandler.RegisterRoutes(router)
-	usersHandler.RegisterRoutes(router)
-	otpHandler.RegisterRoutes(router)
-
-	// WebSocket chat endpoint (uses UUID for user_id)
-	router.GET("/ws/chat", chatHandler.HandleWebSocketGin)
-
-	// Status endpoint for online users (proxy to handler)
-	router.GET("/chat/status", chatHandler.GetStatusGin)
-
-	router.GET("/messages", chatHandler.GetMessagesGin)
 
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	port := os.Getenv("SERVER_PORT")
-	if port == "" {
-		port = "8080"
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
 	}
 
-	srv := &http.Server{
-		Addr:    ":" + port,
-		Handler: router,
+	switch command {
+	case "serve":
+		serve(secrets)
+	case "migrate":
+		err = runMigrate(args)
+	case "seed":
+		err = runSeed(args)
+	case "create-admin":
+		err = runCreateAdmin(args)
+	case "create-api-key":
+		err = runCreateAPIKey(args)
+	case "purge-soft-deleted":
+		err = runPurgeSoftDeleted(args)
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
 	}
-
-	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("listen: %v", err)
-		}
-	}()
-
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
-	<-quit
-	log.Println("Shutting down server...")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+	if err != nil {
+		log.Fatalf("%s: %v", command, err)
 	}
-
-	log.Println("Server exiting")
 }
@@ -0,0 +1,687 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"golang.org/x/crypto/acme/autocert"
+
+	"grveyard/db"
+	"grveyard/pkg/activity"
+	"grveyard/pkg/admin"
+	"grveyard/pkg/agreements"
+	"grveyard/pkg/analytics"
+	"grveyard/pkg/assets"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/bookmarks"
+	"grveyard/pkg/buy"
+	"grveyard/pkg/chat"
+	"grveyard/pkg/compress"
+	"grveyard/pkg/config"
+	"grveyard/pkg/corspolicy"
+	"grveyard/pkg/currency"
+	"grveyard/pkg/dashboard"
+	"grveyard/pkg/datapreview"
+	"grveyard/pkg/digest"
+	"grveyard/pkg/documents"
+	"grveyard/pkg/errorreport"
+	"grveyard/pkg/favorites"
+	"grveyard/pkg/github"
+	"grveyard/pkg/idempotency"
+	"grveyard/pkg/images"
+	"grveyard/pkg/invoices"
+	"grveyard/pkg/jobs"
+	"grveyard/pkg/linkpreview"
+	"grveyard/pkg/notifications"
+	"grveyard/pkg/oauth"
+	"grveyard/pkg/offers"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/otp"
+	"grveyard/pkg/quota"
+	"grveyard/pkg/reports"
+	"grveyard/pkg/requestid"
+	"grveyard/pkg/response"
+	"grveyard/pkg/search"
+	"grveyard/pkg/sendemail"
+	"grveyard/pkg/seo"
+	"grveyard/pkg/startups"
+	"grveyard/pkg/storage"
+	"grveyard/pkg/suggest"
+	"grveyard/pkg/tax"
+	"grveyard/pkg/telemetry"
+	"grveyard/pkg/users"
+	"grveyard/pkg/validation"
+)
+
+var apiInfo = openapi.Info{
+	Title:       "Graveyard API",
+	Version:     "1.0",
+	Description: "REST API for failed startup marketplace - buy and sell startup assets",
+	Contact:     &openapi.Contact{Email: "virajrathod631@gmail.com"},
+}
+
+// apiServers lists API_BASE_URL in the spec; without it clients use the spec's own host.
+func apiServers() []openapi.Server {
+	if u := os.Getenv("API_BASE_URL"); u != "" {
+		return []openapi.Server{{URL: u}}
+	}
+	return nil
+}
+
+// serve runs the HTTP API and background jobs until SIGINT/SIGTERM. secrets is
+// re-resolved periodically so rotated credentials are picked up without a restart.
+func serve(secrets *config.Secrets) {
+	// Tracing must be installed before the pool so DB spans use the real provider
+	shutdownTracing, err := telemetry.Setup(context.Background())
+	if err != nil {
+		log.Fatal("Failed to set up tracing:", err)
+	}
+	flushErrorReports, err := errorreport.Setup()
+	if err != nil {
+		log.Fatal("Failed to set up error reporting:", err)
+	}
+	response.HideInternalErrors(os.Getenv("APP_ENV") == "production")
+
+	pool := db.Connect()
+
+	storageCfg, err := config.LoadStorage()
+	if err != nil {
+		log.Fatal("Invalid storage config:", err)
+	}
+	blobStore, err := storage.New(storageCfg)
+	if err != nil {
+		log.Fatal("Failed to set up storage:", err)
+	}
+	analyticsCfg, err := config.LoadAnalytics()
+	if err != nil {
+		log.Fatal("Invalid analytics config:", err)
+	}
+
+	suppressionRepo := sendemail.NewPostgresSuppressionRepository(pool)
+	// EMAIL_MODE=sandbox logs emails instead of sending them through SendGrid
+	var baseEmailService sendemail.EmailService
+	var emailSandbox *sendemail.SandboxEmailService
+	if strings.EqualFold(os.Getenv("EMAIL_MODE"), "sandbox") {
+		emailSandbox = sendemail.NewSandboxEmailService(os.Getenv("EMAIL_SANDBOX_DIR"))
+		baseEmailService = emailSandbox
+		log.Println("Email sandbox enabled; emails will not be delivered")
+	} else {
+		baseEmailService = sendemail.NewEmailService()
+	}
+	emailService := sendemail.WithSuppression(baseEmailService, suppressionRepo)
+	emailWebhookHandler, err := sendemail.NewWebhookHandler(suppressionRepo, os.Getenv("SENDGRID_WEBHOOK_VERIFICATION_KEY"))
+	if err != nil {
+		log.Fatal("Invalid SENDGRID_WEBHOOK_VERIFICATION_KEY:", err)
+	}
+
+	// Background workers and jobs share a context cancelled on shutdown
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	// Chat setup
+	chatManager := chat.NewConnectionManager()
+	chatHandler := chat.NewHandler(chatManager)
+	wsCfg := chat.DefaultWSConfig()
+	if v, err := strconv.ParseInt(os.Getenv("CHAT_MAX_MESSAGE_BYTES"), 10, 64); err == nil && v > 0 {
+		wsCfg.MaxMessageBytes = v
+	}
+	wsCfg.Compression = !strings.EqualFold(os.Getenv("CHAT_COMPRESSION"), "false")
+	if v, err := strconv.Atoi(os.Getenv("CHAT_COMPRESSION_MIN_BYTES")); err == nil && v >= 0 {
+		wsCfg.CompressMinBytes = v
+	}
+	chatHandler.SetWSConfig(wsCfg)
+	// Inject message store for persistence
+	msgRepo := chat.NewPostgresMessageStore(pool)
+	// Write-behind batching groups concurrent inserts; off unless CHAT_BATCH_WINDOW is set
+	if window := getEnvDuration("CHAT_BATCH_WINDOW", 0); window > 0 {
+		batchCfg := chat.DefaultBatchConfig()
+		batchCfg.Window = window
+		if v, err := strconv.Atoi(os.Getenv("CHAT_BATCH_SIZE")); err == nil && v > 0 {
+			batchCfg.MaxSize = v
+		}
+		msgRepo.EnableBatching(batchCfg)
+	}
+	chatHandler.SetRepository(msgRepo)
+
+	// Notifications fan out to email and push (via chat websocket)
+	unsubscribeSigner := notifications.NewUnsubscribeSigner(os.Getenv("UNSUBSCRIBE_SECRET"), os.Getenv("PUBLIC_BASE_URL"))
+	if unsubscribeSigner == nil {
+		log.Println("UNSUBSCRIBE_SECRET not set; emails will not include unsubscribe links")
+	}
+	inboxRepo := notifications.NewPostgresInboxRepository(pool)
+	notifier := notifications.NewOrchestrator(
+		notifications.NewPostgresRecipientRepository(pool),
+		notifications.NewEmailChannel(emailService, unsubscribeSigner),
+		notifications.NewPushChannel(chatManager),
+		notifications.NewInAppChannel(inboxRepo),
+	)
+	notifier.Start(jobsCtx)
+	chatHandler.SetNotifier(notifier)
+
+	// Optional search engine; without it /assets/search and /startups/search use SQL
+	var searchIndex search.Index
+	if meili := search.NewMeilisearch(os.Getenv("MEILISEARCH_URL"), os.Getenv("MEILISEARCH_API_KEY")); meili != nil {
+		if err := meili.Configure(context.Background()); err != nil {
+			log.Printf("Meilisearch not configured, continuing with SQL search fallback: %v", err)
+		}
+		searchIndex = meili
+	}
+
+	// New listings and sales are recorded for the public activity feed
+	activityService := activity.NewService(activity.NewPostgresActivityRepository(pool))
+	activityHandler := activity.NewActivityHandler(activityService)
+
+	// Users who bookmarked a startup hear about its status changes
+	bookmarksService := bookmarks.NewService(bookmarks.NewPostgresBookmarkRepository(pool), notifier)
+	bookmarksHandler := bookmarks.NewBookmarkHandler(bookmarksService)
+	// Users who favorited an asset hear about price changes and sales
+	favoritesService := favorites.NewService(favorites.NewPostgresFavoriteRepository(pool), notifier)
+	favoritesHandler := favorites.NewFavoriteHandler(favoritesService)
+
+	usersRepo := users.NewPostgresUserRepository(pool)
+	// PASSWORD_HASH_ALGO=argon2id hashes new passwords with Argon2id; older hashes are
+	// upgraded as their owners log in
+	passwordCfg, err := config.LoadPassword()
+	if err != nil {
+		log.Fatal("Invalid password hashing config:", err)
+	}
+	passwords := users.NewPasswordHasher(passwordCfg.Algo, passwordCfg.BcryptCost)
+	usersService := users.NewUserService(usersRepo, passwords)
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		jwtSecret = auth.RandomSecret()
+		log.Println("JWT_SECRET not set; signing tokens with a random secret, so they will not survive a restart")
+	}
+	tokenIssuer := auth.NewIssuer(jwtSecret, getEnvDuration("ACCESS_TOKEN_TTL", auth.DefaultAccessTTL))
+	authService := auth.NewService(auth.NewPostgresRefreshRepository(pool), tokenIssuer, getEnvDuration("REFRESH_TOKEN_TTL", auth.DefaultRefreshTTL))
+	authHandler := auth.NewAuthHandler(authService)
+	usersHandler := users.NewUserHandler(usersService, authService)
+	// Logins, logouts, OTP verifications and password resets go to the user's security log
+	authEvents := auth.NewEventLog(auth.NewPostgresEventRepository(pool))
+	securityLogHandler := auth.NewEventsHandler(authEvents)
+	authHandler.SetEvents(authEvents)
+	usersHandler.SetEvents(authEvents)
+	// Users who turn on TOTP two-factor authentication need a code at password login
+	twoFactorService := users.NewTwoFactorService(users.NewPostgresTwoFactorRepository(pool), usersRepo, "Graveyard")
+	twoFactorHandler := users.NewTwoFactorHandler(twoFactorService)
+	usersHandler.SetTwoFactor(twoFactorService)
+	blockService := users.NewBlockService(users.NewPostgresBlockRepository(pool), usersRepo)
+	blockHandler := users.NewBlockHandler(blockService)
+	statsHandler := users.NewStatsHandler(users.NewStatsService(users.NewPostgresStatsRepository(pool), usersRepo))
+	chatHandler.SetBlocks(blockService)
+	// Signups with an invite code in referred_by are credited to the code's owner
+	referralService := users.NewReferralService(users.NewPostgresReferralRepository(pool))
+	referralHandler := users.NewReferralHandler(referralService)
+	usersHandler.SetReferrals(referralService)
+	// Admins can ban users: their sessions end, listings are hidden and chat drops them
+	banService := users.NewBanService(users.NewPostgresBanRepository(pool), usersRepo, authService, chatManager)
+	chatHandler.SetBans(banService)
+	// Support staff can act as a user for a few minutes; what they do is audited
+	auditLog := auth.NewAuditLog(auth.NewPostgresAuditRepository(pool))
+	impersonationService := users.NewImpersonationService(usersRepo, tokenIssuer)
+	// Optional Sign in with Google; GOOGLE_CLIENT_ID lists our apps' OAuth client IDs
+	var oauthHandler *oauth.OAuthHandler
+	if ids := strings.Fields(strings.ReplaceAll(os.Getenv("GOOGLE_CLIENT_ID"), ",", " ")); len(ids) > 0 {
+		oauthHandler = oauth.NewOAuthHandler(oauth.NewService(oauth.NewGoogle(ids...), usersService, authService))
+		oauthHandler.SetEvents(authEvents)
+	}
+	// Optional GitHub OAuth app so founders can show their GitHub account on their profile
+	var identityHandler *oauth.IdentityHandler
+	if id := os.Getenv("GITHUB_OAUTH_CLIENT_ID"); id != "" {
+		githubOAuth := oauth.NewGitHub(id, os.Getenv("GITHUB_OAUTH_CLIENT_SECRET"))
+		identityHandler = oauth.NewIdentityHandler(oauth.NewIdentityService(githubOAuth, oauth.NewPostgresIdentityRepository(pool), usersService))
+	}
+
+	// Listing requires a recent OTP verification
+	startupsRepo := startups.NewPostgresStartupRepository(pool)
+	startupsService := startups.NewStartupService(startupsRepo, searchIndex, activityService, bookmarksService, usersService)
+	startupsHandler := startups.NewStartupHandler(startupsService)
+	categoryHandler := startups.NewCategoryHandler(startups.NewCategoryService(startups.NewPostgresCategoryRepository(pool), startupsRepo))
+	transferHandler := startups.NewTransferHandler(startups.NewTransferService(startups.NewPostgresTransferRepository(pool), startupsRepo, searchIndex))
+	verificationService := startups.NewVerificationService(startups.NewPostgresVerificationRepository(pool), startupsRepo)
+	verificationHandler := startups.NewVerificationHandler(verificationService)
+	memberHandler := startups.NewMemberHandler(startups.NewMemberService(startups.NewPostgresMemberRepository(pool), startupsRepo))
+	viewService := startups.NewViewService(startups.NewPostgresViewRepository(pool))
+	startupsHandler.SetViews(viewService)
+	viewHandler := startups.NewViewHandler(viewService)
+
+	assetsRepo := assets.NewPostgresAssetRepository(pool)
+	assetsService := assets.NewAssetService(assetsRepo, searchIndex, activityService, usersService, favoritesService)
+	assetsHandler := assets.NewAssetHandler(assetsService)
+	// Optional exchange rates for display_currency on asset lists
+	if provider, err := currency.NewProvider(os.Getenv("EXCHANGE_RATES_PROVIDER"), os.Getenv("OPENEXCHANGERATES_APP_ID")); err != nil {
+		log.Printf("Exchange rates disabled: %v", err)
+	} else if provider != nil {
+		assetsHandler.SetRates(currency.NewService(provider))
+	}
+
+	// Optional GitHub App for linking private repositories to codebase assets
+	var repoAccess buy.RepoAccess
+	var githubHandler *github.GitHubHandler
+	if appID := os.Getenv("GITHUB_APP_ID"); appID != "" {
+		app, err := github.NewApp(appID, os.Getenv("GITHUB_APP_PRIVATE_KEY"))
+		if err != nil {
+			log.Fatal("Invalid GitHub App config:", err)
+		}
+		githubService := github.NewService(github.NewPostgresLinkRepository(pool), app)
+		repoAccess = githubService
+		githubHandler = github.NewGitHubHandler(githubService)
+	}
+	// Buyers sign a listing's NDA or transfer agreement before deliverables and checkout
+	agreementsService := agreements.NewService(agreements.NewPostgresAgreementRepository(pool))
+	agreementsHandler := agreements.NewAgreementHandler(agreementsService)
+	offersHandler := offers.NewOfferHandler(offers.NewService(offers.NewPostgresOfferRepository(pool), notifier, chatHandler, blockService))
+	taxRules, err := tax.ParseRules(os.Getenv("TAX_RULES"))
+	if err != nil {
+		log.Fatal("Invalid TAX_RULES:", err)
+	}
+	quotaPlans, err := quota.ParsePlans(os.Getenv("API_QUOTAS"))
+	if err != nil {
+		log.Fatal("Invalid API_QUOTAS:", err)
+	}
+	quotaService := quota.NewService(quota.NewPostgresUsageRepository(pool), quotaPlans)
+	quotaHandler := quota.NewQuotaHandler(quotaService)
+	buyRepo := buy.NewPostgresBuyRepository(pool)
+	buyService := buy.NewBuyService(buyRepo, notifier, activityService, bookmarksService, favoritesService, taxRules, repoAccess, agreementsService)
+	buyHandler := buy.NewBuyHandler(buyService)
+	invoiceHandler := invoices.NewInvoiceHandler(invoices.NewService(invoices.NewPostgresInvoiceRepository(pool)))
+
+	otpRepo := otp.NewPostgresOTPRepository(pool)
+	otpService := otp.NewOTPService(otpRepo, usersRepo, emailService)
+	otpHandler := otp.NewOTPHandler(otpService)
+	otpHandler.SetEvents(authEvents)
+	// Without PASSWORD_RESET_URL the email carries the bare token for the client to submit
+	passwordResetService := users.NewPasswordResetService(users.NewPostgresPasswordResetRepository(pool), usersRepo, emailService, os.Getenv("PASSWORD_RESET_URL"), authService)
+	passwordResetService.SetPasswordHasher(passwords)
+	passwordResetHandler := users.NewPasswordResetHandler(passwordResetService)
+	passwordResetHandler.SetEvents(authEvents)
+
+	// Uploaded images are resized into variants by a background worker
+	imageCfg := images.DefaultConfig()
+	imageCfg.MaxBytes = int64(storageCfg.MaxUploadBytes)
+	if v, err := strconv.Atoi(os.Getenv("IMAGE_MAX_PIXELS")); err == nil && v > 0 {
+		imageCfg.MaxPixels = v
+	}
+	imageCfg.PollInterval = getEnvDuration("IMAGE_POLL_INTERVAL", imageCfg.PollInterval)
+	imageService := images.NewService(images.NewPostgresImageRepository(pool), blobStore, imageCfg)
+	imageService.Start(jobsCtx)
+	imageHandler := images.NewImageHandler(imageService)
+
+	inboxHandler := notifications.NewInboxHandler(notifications.NewInbox(inboxRepo))
+	// Email and push channels and the digest read these before sending
+	preferencesRepo := notifications.NewPostgresPreferencesRepository(pool)
+	preferencesHandler := notifications.NewPreferencesHandler(preferencesRepo)
+	reportsService := reports.NewService(reports.NewPostgresReportRepository(pool), notifier)
+	reportsHandler := reports.NewReportHandler(reportsService)
+	eventsHandler := analytics.NewEventsHandler(analytics.NewService(analytics.NewSink(analyticsCfg, pool)))
+	dashboardHandler := dashboard.NewDashboardHandler(dashboard.NewService(dashboard.NewPostgresDashboardRepository(pool)))
+	suggestHandler := suggest.NewSuggestHandler(suggest.NewService(suggest.NewPostgresSuggestRepository(pool)))
+	linkPreviewHandler := linkpreview.NewLinkPreviewHandler(linkpreview.NewService(linkpreview.NewFetcher()))
+	documentHandler := documents.NewDocumentHandler(documents.NewService(documents.NewPostgresDocumentRepository(pool), blobStore))
+	dataPreviewHandler := datapreview.NewDataPreviewHandler(datapreview.NewService(datapreview.NewPostgresPreviewRepository(pool), blobStore, agreementsService))
+
+	// Background jobs
+	scheduler := jobs.NewScheduler()
+	digestRepo := digest.NewPostgresDigestRepository(pool)
+	digestService := digest.NewDigestService(digestRepo, chatManager, emailService, unsubscribeSigner, getEnvDuration("DIGEST_UNREAD_AFTER", 6*time.Hour))
+	scheduler.Every("unread-digest", getEnvDuration("DIGEST_INTERVAL", time.Hour), func(ctx context.Context) error {
+		sent, err := digestService.SendPendingDigests(ctx)
+		if sent > 0 {
+			log.Printf("sent %d unread message digests", sent)
+		}
+		return err
+	})
+	scheduler.Every("query-stats", getEnvDuration("DB_QUERY_STATS_INTERVAL", 15*time.Minute), func(ctx context.Context) error {
+		db.Queries.LogStats(10)
+		return nil
+	})
+	idempotencyStore := idempotency.NewPostgresStore(pool)
+	scheduler.Every("idempotency-cleanup", getEnvDuration("IDEMPOTENCY_CLEANUP_INTERVAL", time.Hour), func(ctx context.Context) error {
+		_, err := idempotencyStore.DeleteExpired(ctx)
+		return err
+	})
+	scheduler.Every("quota-cleanup", getEnvDuration("QUOTA_CLEANUP_INTERVAL", 24*time.Hour), func(ctx context.Context) error {
+		_, err := quotaService.Prune(ctx)
+		return err
+	})
+	scheduler.Every("refresh-token-cleanup", getEnvDuration("REFRESH_TOKEN_CLEANUP_INTERVAL", 24*time.Hour), func(ctx context.Context) error {
+		_, err := authService.Prune(ctx)
+		return err
+	})
+	scheduler.Every("account-purge", getEnvDuration("ACCOUNT_PURGE_INTERVAL", 24*time.Hour), func(ctx context.Context) error {
+		_, err := usersService.PurgeDeletedAccounts(ctx)
+		return err
+	})
+	scheduler.Every("secrets-reload", getEnvDuration("SECRETS_RELOAD_INTERVAL", 15*time.Minute), secrets.Resolve)
+	statsService := admin.NewStatsService(admin.NewPostgresStatsRepository(pool))
+	// Re-aggregate yesterday too so late-arriving rows (and the day boundary) are captured
+	scheduler.Every("stats-rollup", getEnvDuration("ADMIN_STATS_ROLLUP_INTERVAL", time.Hour), func(ctx context.Context) error {
+		return statsService.RefreshRecent(ctx, 2)
+	})
+	// Startup views are buffered in memory and written in batches
+	scheduler.Every("startup-views-flush", getEnvDuration("STARTUP_VIEWS_FLUSH_INTERVAL", 30*time.Second), viewService.Flush)
+	scheduler.Every("startup-views-prune", getEnvDuration("STARTUP_VIEWS_PRUNE_INTERVAL", 24*time.Hour), func(ctx context.Context) error {
+		_, err := viewService.PruneViews(ctx)
+		return err
+	})
+	// Sitemap and OpenGraph URLs point at the public site, not this API
+	siteURL := os.Getenv("SITE_BASE_URL")
+	if siteURL == "" {
+		siteURL = os.Getenv("PUBLIC_BASE_URL")
+	}
+	var seoService *seo.Service
+	if siteURL != "" {
+		seoService = seo.NewService(seo.NewPostgresSEORepository(pool), siteURL)
+		scheduler.Every("sitemap", getEnvDuration("SITEMAP_INTERVAL", time.Hour), seoService.Refresh)
+	} else {
+		log.Println("SITE_BASE_URL not set; /sitemap.xml and /assets/:id/meta are disabled")
+	}
+	scheduler.Start(jobsCtx)
+
+	router := gin.New()
+	router.Use(otelgin.Middleware(telemetry.ServiceName), requestid.Middleware(), gin.LoggerWithFormatter(accessLogFormatter), errorreport.Recovery())
+
+	// CORS policies differ per route group: public listings, credentialed user/chat
+	// routes and server-to-server webhooks
+	router.Use(corspolicy.Middleware(config.LoadCORS(), corspolicy.Headers{
+		Allow:  []string{"Origin", "Content-Type", "Accept", "Authorization", "If-None-Match", idempotency.Header, requestid.Header, quota.KeyHeader},
+		Expose: []string{"Content-Length", "ETag", idempotency.ReplayedHeader, requestid.Header, quota.LimitHeader, quota.RemainingHeader, quota.ResetHeader, "Retry-After"},
+	}))
+
+	// Identifies the caller from their access token; before quotas, which bill by user
+	// tier, and idempotency, which scopes keys per user. Routes opt in to requiring it
+	router.Use(auth.Authenticate(tokenIssuer))
+	router.Use(auth.RecordImpersonation(auditLog))
+
+	// Daily and monthly request quotas per API key, user tier or client IP. Docs,
+	// websocket upgrades and provider webhooks are not counted
+	router.Use(quota.Middleware(quotaService, quota.Config{
+		ExcludedPaths: []string{"/ws/", "/openapi.json", "/swagger/", "/webhooks/"},
+	}))
+
+	// Request bodies are capped and must be JSON, except the form-posted one-click
+	// unsubscribe and local file uploads, which enforce their own limit
+	maxBody := int64(validation.DefaultMaxBody)
+	if v, err := strconv.ParseInt(os.Getenv("MAX_BODY_BYTES"), 10, 64); err == nil && v > 0 {
+		maxBody = v
+	}
+	router.Use(validation.BodyLimit(maxBody, storage.RoutePrefix), validation.RequireJSON("/email/unsubscribe", storage.RoutePrefix))
+
+	// Response compression; websocket routes are excluded because upgrades hijack the connection
+	if !strings.EqualFold(os.Getenv("COMPRESSION_ENABLED"), "false") {
+		compressCfg := compress.DefaultConfig()
+		compressCfg.ExcludedPaths = []string{"/ws/"}
+		if v, err := strconv.Atoi(os.Getenv("COMPRESSION_MIN_BYTES")); err == nil && v >= 0 {
+			compressCfg.MinSize = v
+		}
+		router.Use(compress.Middleware(compressCfg))
+	}
+
+	// Retried POSTs carrying an Idempotency-Key replay the first response instead of
+	// creating duplicates; registered after compression so snapshots are stored plain
+	idempotencyCfg := idempotency.DefaultConfig()
+	idempotencyCfg.TTL = getEnvDuration("IDEMPOTENCY_TTL", idempotencyCfg.TTL)
+	idempotencyCfg.MaxBody = maxBody
+	router.Use(idempotency.Middleware(idempotencyStore, idempotencyCfg))
+
+	startupsHandler.RegisterRoutes(router)
+	categoryHandler.RegisterRoutes(router)
+	transferHandler.RegisterRoutes(router)
+	verificationHandler.RegisterRoutes(router)
+	memberHandler.RegisterRoutes(router)
+	viewHandler.RegisterRoutes(router)
+	assetsHandler.RegisterRoutes(router)
+	buyHandler.RegisterRoutes(router)
+	invoiceHandler.RegisterRoutes(router)
+	usersHandler.RegisterRoutes(router)
+	authHandler.RegisterRoutes(router)
+	securityLogHandler.RegisterRoutes(router)
+	passwordResetHandler.RegisterRoutes(router)
+	twoFactorHandler.RegisterRoutes(router)
+	blockHandler.RegisterRoutes(router)
+	statsHandler.RegisterRoutes(router)
+	referralHandler.RegisterRoutes(router)
+	otpHandler.RegisterRoutes(router)
+	emailWebhookHandler.RegisterRoutes(router)
+	imageHandler.RegisterRoutes(router)
+	inboxHandler.RegisterRoutes(router)
+	preferencesHandler.RegisterRoutes(router)
+	activityHandler.RegisterRoutes(router)
+	reportsHandler.RegisterRoutes(router)
+	eventsHandler.RegisterRoutes(router)
+	dashboardHandler.RegisterRoutes(router)
+	bookmarksHandler.RegisterRoutes(router)
+	favoritesHandler.RegisterRoutes(router)
+	linkPreviewHandler.RegisterRoutes(router)
+	dataPreviewHandler.RegisterRoutes(router)
+	documentHandler.RegisterRoutes(router)
+	agreementsHandler.RegisterRoutes(router)
+	offersHandler.RegisterRoutes(router)
+	suggestHandler.RegisterRoutes(router)
+	quotaHandler.RegisterRoutes(router)
+	apiDocs := []openapi.Describer{startupsHandler, categoryHandler, transferHandler, verificationHandler, memberHandler, viewHandler, assetsHandler, buyHandler, invoiceHandler, usersHandler, authHandler, securityLogHandler, passwordResetHandler, twoFactorHandler, blockHandler, statsHandler, referralHandler, otpHandler, emailWebhookHandler, imageHandler, inboxHandler, preferencesHandler, activityHandler, reportsHandler, eventsHandler, dashboardHandler, bookmarksHandler, favoritesHandler, linkPreviewHandler, dataPreviewHandler, documentHandler, agreementsHandler, offersHandler, suggestHandler, quotaHandler, chatHandler}
+	// With STORAGE_DRIVER=s3 clients talk to the bucket directly
+	if local, ok := blobStore.(*storage.LocalStorage); ok {
+		local.RegisterRoutes(router)
+		apiDocs = append(apiDocs, local)
+	}
+	if unsubscribeSigner != nil {
+		unsubscribeHandler := notifications.NewUnsubscribeHandler(unsubscribeSigner, preferencesRepo)
+		unsubscribeHandler.RegisterRoutes(router)
+		apiDocs = append(apiDocs, unsubscribeHandler)
+	}
+	// Staff whose role has the permission use their own access token on these, so
+	// they are served even when no shared ADMIN_API_TOKEN is configured
+	adminToken := os.Getenv("ADMIN_API_TOKEN")
+	banHandler := users.NewBanHandler(banService, adminToken)
+	banHandler.RegisterRoutes(router)
+	apiDocs = append(apiDocs, banHandler)
+	impersonationHandler := users.NewImpersonationHandler(impersonationService, auditLog, adminToken)
+	impersonationHandler.RegisterRoutes(router)
+	apiDocs = append(apiDocs, impersonationHandler)
+	verificationReviewHandler := startups.NewVerificationReviewHandler(verificationService, adminToken)
+	verificationReviewHandler.RegisterRoutes(router)
+	apiDocs = append(apiDocs, verificationReviewHandler)
+	deletedStartupsHandler := startups.NewDeletedHandler(startupsService, adminToken)
+	deletedStartupsHandler.RegisterRoutes(router)
+	apiDocs = append(apiDocs, deletedStartupsHandler)
+	reviewHandler := reports.NewReviewHandler(reportsService, adminToken)
+	reviewHandler.RegisterRoutes(router)
+	apiDocs = append(apiDocs, reviewHandler)
+	if adminToken != "" {
+		adminHandler := admin.NewAdminHandler(statsService, adminToken)
+		adminHandler.RegisterRoutes(router)
+		assetsBulkHandler := assets.NewBulkHandler(assetsService, adminToken)
+		assetsBulkHandler.RegisterRoutes(router)
+		startupsBulkHandler := startups.NewBulkHandler(startupsService, adminToken)
+		startupsBulkHandler.RegisterRoutes(router)
+		apiDocs = append(apiDocs, adminHandler, assetsBulkHandler, startupsBulkHandler)
+	}
+	if oauthHandler != nil {
+		oauthHandler.RegisterRoutes(router)
+		apiDocs = append(apiDocs, oauthHandler)
+	}
+	if identityHandler != nil {
+		identityHandler.RegisterRoutes(router)
+		apiDocs = append(apiDocs, identityHandler)
+	}
+	if githubHandler != nil {
+		githubHandler.RegisterRoutes(router)
+		apiDocs = append(apiDocs, githubHandler)
+	}
+	if seoService != nil {
+		seoHandler := seo.NewSEOHandler(seoService)
+		seoHandler.RegisterRoutes(router)
+		apiDocs = append(apiDocs, seoHandler)
+	}
+	if emailSandbox != nil {
+		devHandler := sendemail.NewDevHandler(emailSandbox)
+		devHandler.RegisterRoutes(router)
+		apiDocs = append(apiDocs, devHandler)
+	}
+
+	// WebSocket chat endpoint for the signed-in user
+	router.GET("/ws/chat", auth.Required(), chatHandler.HandleWebSocketGin)
+
+	// Status endpoint for online users (proxy to handler)
+	router.GET("/chat/status", chatHandler.GetStatusGin)
+
+	router.GET("/messages", auth.Required(), chatHandler.GetMessagesGin)
+	// Read receipts for clients without an open websocket
+	router.POST("/messages/read", auth.Required(), chatHandler.MarkReadGin)
+
+	// The spec is built from the routes registered above, so it must come last
+	spec := openapi.Build(apiInfo, apiServers(), router.Routes(), apiDocs...)
+	router.GET("/openapi.json", openapi.Handler(spec))
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/openapi.json")))
+
+	acmeCfg, err := config.LoadACME()
+	if err != nil {
+		log.Fatal("Invalid ACME configuration:", err)
+	}
+
+	port := os.Getenv("SERVER_PORT")
+	if port == "" {
+		port = "8080"
+		if acmeCfg.Enabled {
+			port = "443"
+		}
+	}
+
+	serverCfg := config.LoadServer()
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           router,
+		ReadHeaderTimeout: serverCfg.ReadHeaderTimeout,
+		ReadTimeout:       serverCfg.ReadTimeout,
+		WriteTimeout:      serverCfg.WriteTimeout,
+		IdleTimeout:       serverCfg.IdleTimeout,
+		MaxHeaderBytes:    serverCfg.MaxHeaderBytes,
+	}
+
+	// With ACME, certificates are obtained on the first TLS handshake for each domain;
+	// the plain HTTP listener answers HTTP-01 challenges and redirects to HTTPS
+	var challengeSrv *http.Server
+	if acmeCfg.Enabled {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(acmeCfg.Domains...),
+			Cache:      autocert.DirCache(acmeCfg.CacheDir),
+			Email:      acmeCfg.Email,
+		}
+		srv.TLSConfig = manager.TLSConfig()
+		challengeSrv = &http.Server{
+			Addr:              ":" + acmeCfg.HTTPPort,
+			Handler:           manager.HTTPHandler(nil),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := challengeSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("ACME challenge listener: %v", err)
+			}
+		}()
+		log.Printf("ACME enabled for %s", strings.Join(acmeCfg.Domains, ", "))
+	}
+
+	go func() {
+		var err error
+		if acmeCfg.Enabled {
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatalf("listen: %v", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server...")
+
+	// Order matters: stop taking traffic, drain sockets, stop workers, then close the
+	// pool they all depend on.
+	ctx, cancel := context.WithTimeout(context.Background(), getEnvDuration("SHUTDOWN_TIMEOUT", 10*time.Second))
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("HTTP server shutdown: %v", err)
+	}
+	if challengeSrv != nil {
+		if err := challengeSrv.Shutdown(ctx); err != nil {
+			log.Printf("ACME challenge listener shutdown: %v", err)
+		}
+	}
+	if err := chatHandler.Shutdown(ctx); err != nil {
+		log.Printf("Chat connections not drained: %v", err)
+	}
+	if err := msgRepo.Close(ctx); err != nil {
+		log.Printf("Chat messages not flushed: %v", err)
+	}
+
+	stopJobs()
+	if err := scheduler.Wait(ctx); err != nil {
+		log.Printf("Background jobs still running: %v", err)
+	}
+	if err := viewService.Flush(ctx); err != nil {
+		log.Printf("Startup views not flushed: %v", err)
+	}
+	if err := notifier.Wait(ctx); err != nil {
+		log.Printf("Notification worker still running: %v", err)
+	}
+	if err := imageService.Wait(ctx); err != nil {
+		log.Printf("Image worker still running: %v", err)
+	}
+
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
+	}
+	if err := flushErrorReports(ctx); err != nil {
+		log.Printf("Failed to flush error reports: %v", err)
+	}
+	pool.Close()
+
+	log.Println("Server exiting")
+}
+
+// accessLogFormatter is gin's default access log line prefixed with the request ID.
+func accessLogFormatter(param gin.LogFormatterParams) string {
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+	return fmt.Sprintf("[GIN] %v | %s | %3d | %13v | %15s | %-7s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		param.Keys[requestid.ContextKey],
+		param.StatusCode,
+		param.Latency,
+		param.ClientIP,
+		param.Method,
+		param.Path,
+		param.ErrorMessage,
+	)
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(valueStr)
+	if err != nil {
+		log.Printf("Invalid duration for %s, using default: %s", key, defaultValue)
+		return defaultValue
+	}
+	return d
+}
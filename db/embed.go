@@ -0,0 +1,31 @@
+package db
+
+import (
+	"embed"
+	"fmt"
+	"os"
+)
+
+// sqlFiles are compiled into the binary so the server does not depend on its working
+// directory (the Docker image ships the binary alone).
+//
+//go:embed schema.sql schema_update.sql schema_down.sql seed.sql
+var sqlFiles embed.FS
+
+// readSQL returns the embedded file name, or the file at $envKey when that is set.
+// source describes where the SQL came from, for logs and errors.
+func readSQL(envKey, name string) (source string, b []byte, err error) {
+	if path := os.Getenv(envKey); path != "" {
+		b, err = os.ReadFile(path)
+		if err != nil {
+			return path, nil, fmt.Errorf("read %s: %w", path, err)
+		}
+		return path, b, nil
+	}
+
+	b, err = sqlFiles.ReadFile(name)
+	if err != nil {
+		return name, nil, fmt.Errorf("read embedded %s: %w", name, err)
+	}
+	return "embedded " + name, b, nil
+}
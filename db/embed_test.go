@@ -0,0 +1,45 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSQL_Embedded(t *testing.T) {
+	t.Setenv("SCHEMA_PATH", "")
+
+	source, b, err := readSQL("SCHEMA_PATH", "schema.sql")
+
+	require.NoError(t, err)
+	require.Equal(t, "embedded schema.sql", source)
+	require.Contains(t, string(b), "CREATE TABLE IF NOT EXISTS users")
+}
+
+func TestReadSQL_EnvOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.sql")
+	require.NoError(t, os.WriteFile(path, []byte("SELECT 1;"), 0o600))
+	t.Setenv("SCHEMA_PATH", path)
+
+	source, b, err := readSQL("SCHEMA_PATH", "schema.sql")
+
+	require.NoError(t, err)
+	require.Equal(t, path, source)
+	require.Equal(t, "SELECT 1;", string(b))
+}
+
+func TestSchemaDown_DropsEveryTable(t *testing.T) {
+	schema, err := sqlFiles.ReadFile("schema.sql")
+	require.NoError(t, err)
+	down, err := sqlFiles.ReadFile("schema_down.sql")
+	require.NoError(t, err)
+
+	created := regexp.MustCompile(`CREATE TABLE IF NOT EXISTS (\w+)`).FindAllStringSubmatch(string(schema), -1)
+	require.NotEmpty(t, created)
+	for _, m := range created {
+		require.Regexp(t, `DROP TABLE IF EXISTS `+m[1]+`;`, string(down))
+	}
+}
@@ -0,0 +1,11 @@
+package db
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// EscapeLike escapes LIKE wildcards so user input matches literally. Postgres uses
+// backslash as the default LIKE escape character, so no ESCAPE clause is needed.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,12 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeLike(t *testing.T) {
+	require.Equal(t, `50\% off\_now \\ later`, EscapeLike(`50% off_now \ later`))
+	require.Equal(t, "plain", EscapeLike("plain"))
+}
@@ -9,10 +9,28 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Connect opens the pool and applies the schema unless APPLY_SCHEMA_ON_START=false.
 func Connect() *pgxpool.Pool {
+	DB := Open()
+
+	// Apply schema on startup unless explicitly disabled
+	if !strings.EqualFold(os.Getenv("APPLY_SCHEMA_ON_START"), "false") {
+		schemaCtx, cancelSchema := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancelSchema()
+		if err := ApplySchema(schemaCtx, DB); err != nil {
+			log.Fatal("Failed to apply schema:", err)
+		}
+	}
+
+	return DB
+}
+
+// Open connects to DATABASE_URL without touching the schema.
+func Open() *pgxpool.Pool {
 	dsn := os.Getenv("DATABASE_URL")
 	if dsn == "" {
 		log.Fatal("DATABASE_URL environment variable not set")
@@ -27,6 +45,22 @@ func Connect() *pgxpool.Pool {
 	config.MinConns = int32(getEnvAsInt("DB_MIN_CONNS", 2))
 	idleTime := getEnvAsDuration("DB_MAX_CONN_IDLE_TIME", "5m")
 	config.MaxConnIdleTime = idleTime
+	config.ConnConfig.Tracer = newConnTracer()
+	// DATABASE_URL may be rewritten when secrets are reloaded; new connections pick up
+	// rotated credentials while existing ones keep working until they are recycled
+	config.BeforeConnect = func(ctx context.Context, cc *pgx.ConnConfig) error {
+		current := os.Getenv("DATABASE_URL")
+		if current == dsn {
+			return nil
+		}
+		rotated, err := pgx.ParseConfig(current)
+		if err != nil {
+			return fmt.Errorf("parse rotated DATABASE_URL: %w", err)
+		}
+		cc.User = rotated.User
+		cc.Password = rotated.Password
+		return nil
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -41,16 +75,6 @@ func Connect() *pgxpool.Pool {
 	}
 
 	log.Println("Connected to PostgreSQL")
-
-	// Apply schema on startup unless explicitly disabled
-	if !strings.EqualFold(os.Getenv("APPLY_SCHEMA_ON_START"), "false") {
-		schemaCtx, cancelSchema := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancelSchema()
-		if err := ApplySchema(schemaCtx, DB); err != nil {
-			log.Fatal("Failed to apply schema:", err)
-		}
-	}
-
 	return DB
 }
 
@@ -79,42 +103,44 @@ func getEnvAsDuration(key, defaultValue string) time.Duration {
 	return duration
 }
 
+// ApplySchema runs schema.sql and then schema_update.sql. Both are embedded in the
+// binary; SCHEMA_PATH and SCHEMA_UPDATE_PATH override them with files on disk.
 func ApplySchema(ctx context.Context, pool *pgxpool.Pool) error {
-	schemaPath := os.Getenv("SCHEMA_PATH")
-	if schemaPath == "" {
-		schemaPath = "db/schema.sql"
+	if err := execSQL(ctx, pool, "SCHEMA_PATH", "schema.sql"); err != nil {
+		return err
 	}
+	if err := execSQL(ctx, pool, "SCHEMA_UPDATE_PATH", "schema_update.sql"); err != nil {
+		return err
+	}
+	log.Println("Database schema is up to date")
+	return nil
+}
+
+// RevertSchema drops everything ApplySchema creates (schema_down.sql, or
+// SCHEMA_DOWN_PATH). All data is lost.
+func RevertSchema(ctx context.Context, pool *pgxpool.Pool) error {
+	return execSQL(ctx, pool, "SCHEMA_DOWN_PATH", "schema_down.sql")
+}
+
+// ApplySeed loads demo data (seed.sql, or SEED_PATH). The seed is idempotent, so
+// running it twice leaves a single copy of each row.
+func ApplySeed(ctx context.Context, pool *pgxpool.Pool) error {
+	return execSQL(ctx, pool, "SEED_PATH", "seed.sql")
+}
 
-	bytes, err := os.ReadFile(schemaPath)
+func execSQL(ctx context.Context, pool *pgxpool.Pool, envKey, name string) error {
+	source, b, err := readSQL(envKey, name)
 	if err != nil {
-		return fmt.Errorf("read schema file: %w", err)
+		return err
 	}
-
-	sql := strings.TrimSpace(string(bytes))
+	sql := strings.TrimSpace(string(b))
 	if sql == "" {
-		return fmt.Errorf("schema file is empty: %s", schemaPath)
+		return fmt.Errorf("%s is empty", source)
 	}
 
 	if _, err := pool.Exec(ctx, sql); err != nil {
-		return fmt.Errorf("execute schema: %w", err)
-	}
-
-	log.Println("Schema applied from", schemaPath)
-
-	// Optionally apply updates/migrations
-	updatePath := os.Getenv("SCHEMA_UPDATE_PATH")
-	if updatePath == "" {
-		updatePath = "db/schema_update.sql"
+		return fmt.Errorf("execute %s: %w", source, err)
 	}
-	if b, err := os.ReadFile(updatePath); err == nil {
-		upd := strings.TrimSpace(string(b))
-		if upd != "" {
-			if _, err := pool.Exec(ctx, upd); err != nil {
-				return fmt.Errorf("execute schema update: %w", err)
-			}
-			log.Println("Schema updates applied from", updatePath)
-		}
-	}
-	log.Println("Database schema is up to date")
+	log.Println("Applied", source)
 	return nil
 }
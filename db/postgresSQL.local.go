@@ -31,6 +31,7 @@ func ConnectToLocal() *pgxpool.Pool {
 	config.MinConns = int32(getEnvAsInt("DB_MIN_CONNS", 2))
 	idleTime := getEnvAsDuration("DB_MAX_CONN_IDLE_TIME", "5m")
 	config.MaxConnIdleTime = idleTime
+	config.ConnConfig.Tracer = newConnTracer()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
+
+	"grveyard/pkg/requestid"
+)
+
+// FamilyStats aggregates every execution of one statement family, e.g. "SELECT assets".
+type FamilyStats struct {
+	Family    string        `json:"family"`
+	Count     int64         `json:"count"`
+	SlowCount int64         `json:"slow_count"`
+	Errors    int64         `json:"errors"`
+	Total     time.Duration `json:"total"`
+	Max       time.Duration `json:"max"`
+}
+
+// QueryTracer logs statements slower than a threshold and counts executions per
+// statement family. Query arguments are never logged; only the SQL text with its $n
+// placeholders, so user data and secrets stay out of the logs.
+type QueryTracer struct {
+	threshold time.Duration // 0 disables slow-query logging
+	logger    interface {
+		Printf(string, ...interface{})
+	}
+
+	mu    sync.Mutex
+	stats map[string]*FamilyStats
+}
+
+// NewQueryTracer creates a tracer logging statements that take longer than threshold.
+func NewQueryTracer(threshold time.Duration) *QueryTracer {
+	return &QueryTracer{
+		threshold: threshold,
+		logger:    log.New(log.Writer(), "[db] ", log.LstdFlags),
+		stats:     make(map[string]*FamilyStats),
+	}
+}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	sql     string
+	argsLen int
+	at      time.Time
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{sql: data.SQL, argsLen: len(data.Args), at: time.Now()})
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(start.at)
+	family := statementFamily(start.sql)
+	slow := t.threshold > 0 && elapsed >= t.threshold
+
+	t.mu.Lock()
+	s, ok := t.stats[family]
+	if !ok {
+		s = &FamilyStats{Family: family}
+		t.stats[family] = s
+	}
+	s.Count++
+	s.Total += elapsed
+	if elapsed > s.Max {
+		s.Max = elapsed
+	}
+	if slow {
+		s.SlowCount++
+	}
+	if data.Err != nil {
+		s.Errors++
+	}
+	t.mu.Unlock()
+
+	if slow {
+		t.logger.Printf("[%s] slow query (%s) took %dms [%d args redacted]: %s",
+			requestid.FromContext(ctx), family, elapsed.Milliseconds(), start.argsLen, compactSQL(start.sql))
+	}
+}
+
+// Stats returns a snapshot of the per-family counters, busiest first.
+func (t *QueryTracer) Stats() []FamilyStats {
+	t.mu.Lock()
+	out := make([]FamilyStats, 0, len(t.stats))
+	for _, s := range t.stats {
+		out = append(out, *s)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Total > out[j].Total })
+	return out
+}
+
+// LogStats writes the top n families (by total time) to the log.
+func (t *QueryTracer) LogStats(n int) {
+	stats := t.Stats()
+	if len(stats) > n {
+		stats = stats[:n]
+	}
+	for _, s := range stats {
+		avg := time.Duration(0)
+		if s.Count > 0 {
+			avg = s.Total / time.Duration(s.Count)
+		}
+		t.logger.Printf("query stats %-32s count=%d slow=%d errors=%d avg=%s max=%s",
+			s.Family, s.Count, s.SlowCount, s.Errors, avg.Round(time.Microsecond), s.Max.Round(time.Microsecond))
+	}
+}
+
+// Queries is the tracer installed by Connect/ConnectToLocal; nil before connecting.
+var Queries *QueryTracer
+
+// newConnTracer combines OpenTelemetry spans with slow-query logging.
+func newConnTracer() pgx.QueryTracer {
+	Queries = NewQueryTracer(time.Duration(getEnvAsInt("DB_SLOW_QUERY_MS", 200)) * time.Millisecond)
+	return multitracer.New(otelpgx.NewTracer(), Queries)
+}
+
+var (
+	whitespace  = regexp.MustCompile(`\s+`)
+	tableTarget = regexp.MustCompile(`(?i)\b(?:FROM|INTO|UPDATE|TABLE(?: IF (?:NOT )?EXISTS)?)\s+([a-zA-Z_][a-zA-Z0-9_.]*)`)
+)
+
+// statementFamily reduces SQL to "<VERB> <first table>", e.g. "SELECT assets".
+func statementFamily(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return ""
+	}
+	verb := strings.ToUpper(fields[0])
+	if m := tableTarget.FindStringSubmatch(sql); m != nil {
+		return verb + " " + strings.ToLower(m[1])
+	}
+	return verb
+}
+
+func compactSQL(sql string) string {
+	sql = whitespace.ReplaceAllString(strings.TrimSpace(sql), " ")
+	const maxLen = 500
+	if len(sql) > maxLen {
+		sql = sql[:maxLen] + "..."
+	}
+	return sql
+}
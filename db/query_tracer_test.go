@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementFamily(t *testing.T) {
+	tests := map[string]string{
+		"SELECT id, title FROM assets WHERE id = $1":                   "SELECT assets",
+		"\n\t\tINSERT INTO messages (sender_id) VALUES ($1)":           "INSERT messages",
+		"UPDATE users SET name = $1 WHERE uuid = $2":                   "UPDATE users",
+		"DELETE FROM startups WHERE id = $1":                           "DELETE startups",
+		"CREATE TABLE IF NOT EXISTS notification_preferences (id INT)": "CREATE notification_preferences",
+		"select count(*) from Users":                                   "SELECT users",
+		"BEGIN":                                                        "BEGIN",
+	}
+	for sql, want := range tests {
+		require.Equal(t, want, statementFamily(sql), sql)
+	}
+}
+
+func TestQueryTracer_CountsAndFlagsSlowQueries(t *testing.T) {
+	tracer := NewQueryTracer(time.Nanosecond)
+	var logged []string
+	tracer.logger = loggerFunc(func(format string, args ...interface{}) { logged = append(logged, format) })
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT * FROM assets WHERE id = $1", Args: []any{"secret"}})
+	time.Sleep(time.Millisecond)
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT * FROM assets"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+
+	stats := tracer.Stats()
+	require.Len(t, stats, 1)
+	require.Equal(t, "SELECT assets", stats[0].Family)
+	require.EqualValues(t, 2, stats[0].Count)
+	require.EqualValues(t, 2, stats[0].SlowCount)
+	require.EqualValues(t, 1, stats[0].Errors)
+	require.Len(t, logged, 2)
+}
+
+type loggerFunc func(string, ...interface{})
+
+func (f loggerFunc) Printf(format string, args ...interface{}) { f(format, args...) }
@@ -2,13 +2,21 @@ CREATE TABLE IF NOT EXISTS users (
     id SERIAL PRIMARY KEY,
     name TEXT NOT NULL,
     email TEXT UNIQUE,
-    role TEXT NOT NULL CHECK (role IN ('buyer', 'founder')),
+    role TEXT NOT NULL CHECK (role IN ('buyer', 'founder', 'moderator', 'admin')),   -- admins are created with the CLI only
     password_hash TEXT NOT NULL,
     profile_pic_url TEXT,
     uuid TEXT UNIQUE NOT NULL,
+    handle TEXT NULL,   -- lower-case; profile URL /u/:handle
+    locale TEXT NOT NULL DEFAULT 'en',
+    country TEXT NOT NULL DEFAULT '',   -- ISO 3166-1 alpha-2, '' when unknown
+    region TEXT NOT NULL DEFAULT '',    -- ISO 3166-2, e.g. IN-MH
     verified_at TIMESTAMP NULL,
     last_active_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
     is_deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    deletion_requested_at TIMESTAMP NULL,   -- purged 30 days later unless the user logs in
+    banned_at TIMESTAMP NULL,               -- suspended by an admin; cannot log in
+    ban_reason TEXT NOT NULL DEFAULT '',
+    invite_code TEXT NULL,                  -- generated on first GET /users/:uuid/invite-code
     created_at TIMESTAMP NOT NULL DEFAULT NOW()
 );
 
@@ -17,12 +25,27 @@ CREATE INDEX IF NOT EXISTS idx_users_is_deleted ON users(is_deleted);
 CREATE TABLE IF NOT EXISTS startups (
     id SERIAL PRIMARY KEY,
     name TEXT NOT NULL,
+    slug TEXT NOT NULL,           -- unique, from the name at creation; public URLs use it instead of the id
     description TEXT,
     logo_url TEXT,                -- image stored as string
     owner_uuid TEXT NOT NULL,
     status TEXT NOT NULL CHECK (status IN ('active', 'failed', 'sold')) DEFAULT 'failed',
+    country TEXT NOT NULL DEFAULT '',   -- ISO 3166-1 alpha-2 where the entity is registered
+    region TEXT NOT NULL DEFAULT '',    -- ISO 3166-2
+    failure_reason TEXT NOT NULL DEFAULT '',   -- post-mortem, filled in by the founder
+    lifespan_months INT NULL CHECK (lifespan_months >= 0),
+    peak_mrr NUMERIC(12,2) NULL CHECK (peak_mrr >= 0),   -- USD
+    users_at_shutdown BIGINT NULL CHECK (users_at_shutdown >= 0),
+    sold_at TIMESTAMP NULL,
+    verified_at TIMESTAMP NULL,   -- verification badge, set when an admin approves a startup_verifications request
+    deleted_at TIMESTAMP NULL,   -- when is_deleted was set; POST /startups/:id/restore clears both
     is_deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    hidden_by_ban BOOLEAN NOT NULL DEFAULT FALSE,   -- soft-deleted when the owner was banned; restored on unban
     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', name), 'A') || setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+    ) STORED,   -- GET /startups/search when Meilisearch is not configured
     -- revenue NUMERIC(12,2) DEFAULT 0.00,
     -- profit NUMERIC(12,2) DEFAULT 0.00,
     -- priority SMALLINT NOT NULL DEFAULT 0,
@@ -36,6 +59,7 @@ CREATE TABLE IF NOT EXISTS startups (
 
 CREATE INDEX IF NOT EXISTS idx_startups_owner_uuid ON startups(owner_uuid);
 CREATE INDEX IF NOT EXISTS idx_startups_is_deleted ON startups(is_deleted);
+CREATE INDEX IF NOT EXISTS idx_startups_name_prefix ON startups(lower(name) text_pattern_ops); -- search suggestions
 
 CREATE TABLE IF NOT EXISTS assets (
     id SERIAL PRIMARY KEY,
@@ -49,9 +73,14 @@ CREATE TABLE IF NOT EXISTS assets (
     price NUMERIC(12,2),
     is_negotiable BOOLEAN NOT NULL DEFAULT TRUE,
     is_sold BOOLEAN NOT NULL DEFAULT FALSE,
+    sold_at TIMESTAMP NULL,
     is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    country TEXT NOT NULL DEFAULT '',   -- ISO 3166-1 alpha-2 jurisdiction of the asset
+    region TEXT NOT NULL DEFAULT '',    -- ISO 3166-2
     is_deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    hidden_by_ban BOOLEAN NOT NULL DEFAULT FALSE,   -- soft-deleted when the owner was banned; restored on unban
     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
     -- priority SMALLINT NOT NULL DEFAULT 0,
     -- interested_buyers INT NOT NULL DEFAULT 0,
 
@@ -65,6 +94,7 @@ CREATE INDEX IF NOT EXISTS idx_assets_user_uuid ON assets(user_uuid);
 CREATE INDEX IF NOT EXISTS idx_assets_is_sold ON assets(is_sold);
 CREATE INDEX IF NOT EXISTS idx_assets_is_active ON assets(is_active);
 CREATE INDEX IF NOT EXISTS idx_assets_is_deleted ON assets(is_deleted);
+CREATE INDEX IF NOT EXISTS idx_assets_title_prefix ON assets(lower(title) text_pattern_ops); -- search suggestions
 
 CREATE TABLE IF NOT EXISTS messages (
     id BIGSERIAL PRIMARY KEY,
@@ -84,6 +114,8 @@ CREATE TABLE IF NOT EXISTS messages (
 
     messaged_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
 
+    offer_id INT NULL,                  -- offers.id a system message summarizes
+
     CONSTRAINT fk_messages_sender
         FOREIGN KEY (sender_id)
         REFERENCES users(id)
@@ -110,6 +142,11 @@ CREATE TABLE IF NOT EXISTS transactions (
     asset_id INT NOT NULL,
     buyer_id INT NOT NULL,
     final_price NUMERIC(12,2),
+    seller_country TEXT NOT NULL DEFAULT '',    -- countries the tax rule was chosen by
+    buyer_country TEXT NOT NULL DEFAULT '',
+    tax_name TEXT NOT NULL DEFAULT '',          -- e.g. GST, VAT; '' when no tax applied
+    tax_rate NUMERIC(5,2) NOT NULL DEFAULT 0,   -- percent
+    tax_amount NUMERIC(12,2) NOT NULL DEFAULT 0,
     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
 
     CONSTRAINT fk_transactions_asset
@@ -132,3 +169,653 @@ CREATE TABLE IF NOT EXISTS otps (
 
 CREATE INDEX IF NOT EXISTS idx_otps_email ON otps(email);
 CREATE INDEX IF NOT EXISTS idx_otps_expires_at ON otps(expires_at);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_uuid TEXT PRIMARY KEY,
+    email_digest BOOLEAN NOT NULL DEFAULT TRUE,
+    email_on_message BOOLEAN NOT NULL DEFAULT FALSE,
+    email_on_offer BOOLEAN NOT NULL DEFAULT TRUE,
+    email_on_sale BOOLEAN NOT NULL DEFAULT TRUE,
+    push_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    quiet_hours_start SMALLINT CHECK (quiet_hours_start BETWEEN 0 AND 23), -- UTC hour
+    quiet_hours_end SMALLINT CHECK (quiet_hours_end BETWEEN 0 AND 23),     -- UTC hour
+    last_digest_at BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_notification_preferences_user
+        FOREIGN KEY (user_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_messages_unread_receiver ON messages(receiver_id, messaged_at) WHERE is_read = FALSE;
+
+CREATE TABLE IF NOT EXISTS email_suppressions (
+    email TEXT PRIMARY KEY,       -- stored lower-cased
+    event_type TEXT NOT NULL CHECK (event_type IN ('bounce', 'spamreport')),
+    reason TEXT,
+    event_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Response snapshots for requests sent with an Idempotency-Key header
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope TEXT NOT NULL,           -- user UUID, or client IP for anonymous requests
+    idempotency_key TEXT NOT NULL,
+    route TEXT NOT NULL,           -- "POST /startups"
+    request_hash TEXT NOT NULL,    -- sha256 of the request body
+    status_code INT,               -- NULL while the original request is in flight
+    content_type TEXT,
+    response_body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (scope, idempotency_key, route)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+-- Per-day aggregates for the admin dashboard, refreshed by the stats-rollup job
+CREATE TABLE IF NOT EXISTS daily_stats (
+    day DATE PRIMARY KEY,           -- UTC
+    signups BIGINT NOT NULL DEFAULT 0,
+    new_assets BIGINT NOT NULL DEFAULT 0,
+    new_startups BIGINT NOT NULL DEFAULT 0,
+    assets_sold BIGINT NOT NULL DEFAULT 0,
+    startups_sold BIGINT NOT NULL DEFAULT 0,
+    sales_volume NUMERIC(14,2) NOT NULL DEFAULT 0,
+    active_chats BIGINT NOT NULL DEFAULT 0,
+    otps_sent BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Uploaded images; the image worker turns each original into resized variants
+CREATE TABLE IF NOT EXISTS images (
+    id UUID PRIMARY KEY,
+    owner_uuid TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('avatar', 'asset', 'logo')),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'queued', 'processing', 'ready', 'failed')),
+    source_key TEXT NOT NULL,       -- original upload, deleted once processed
+    variant_keys JSONB NOT NULL DEFAULT '{}',   -- variant name -> storage key
+    width INT,
+    height INT,
+    error TEXT,
+    attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_images_queue ON images(status, updated_at) WHERE status IN ('queued', 'processing');
+
+-- In-app notification inbox, filled by the notification orchestrator
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_uuid TEXT NOT NULL,
+    type TEXT NOT NULL,
+    actor_uuid TEXT,
+    entity_id BIGINT,
+    title TEXT NOT NULL DEFAULT '',
+    amount NUMERIC(12,2),
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_notifications_user
+        FOREIGN KEY (user_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_uuid, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_uuid) WHERE read_at IS NULL;
+
+-- Public marketplace activity feed (GET /activity); title and price are read from
+-- the asset or startup at query time
+CREATE TABLE IF NOT EXISTS activities (
+    id BIGSERIAL PRIMARY KEY,
+    type TEXT NOT NULL CHECK (type IN ('asset_listed', 'asset_sold', 'startup_listed', 'startup_sold')),
+    entity_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_activities_type ON activities(type, id DESC);
+
+-- Abuse reports against users, startups, assets and chat messages. target_id is a
+-- user UUID or a numeric row ID, depending on target_type. A reporter has at most
+-- one open report per target.
+CREATE TABLE IF NOT EXISTS reports (
+    id BIGSERIAL PRIMARY KEY,
+    reporter_uuid TEXT NOT NULL,
+    target_type TEXT NOT NULL CHECK (target_type IN ('user', 'startup', 'asset', 'message')),
+    target_id TEXT NOT NULL,
+    reason TEXT NOT NULL CHECK (reason IN ('spam', 'scam', 'abuse', 'inappropriate', 'other')),
+    details TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'reviewing', 'resolved', 'dismissed')),
+    resolution_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_reports_reporter
+        FOREIGN KEY (reporter_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_open_target ON reports(reporter_uuid, target_type, target_id) WHERE status IN ('open', 'reviewing');
+CREATE INDEX IF NOT EXISTS idx_reports_reporter ON reports(reporter_uuid, id DESC);
+CREATE INDEX IF NOT EXISTS idx_reports_status ON reports(status, id);
+
+-- Append-only client analytics events from POST /events (when ANALYTICS_SINK is
+-- postgres). user_uuid is not a foreign key so events outlive their users.
+CREATE TABLE IF NOT EXISTS events (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    user_uuid TEXT,
+    session_id TEXT,
+    properties JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMP NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_name_occurred ON events(name, occurred_at);
+
+-- Startups a user follows; kept apart from asset favorites. Followers are notified
+-- in-app when the startup's status changes.
+CREATE TABLE IF NOT EXISTS startup_bookmarks (
+    user_uuid TEXT NOT NULL,
+    startup_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_uuid, startup_id),
+
+    CONSTRAINT fk_startup_bookmarks_user
+        FOREIGN KEY (user_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_startup_bookmarks_startup
+        FOREIGN KEY (startup_id)
+        REFERENCES startups(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_startup_bookmarks_startup ON startup_bookmarks(startup_id);
+
+-- Assets on a user's watchlist. Watchers are notified in-app when the asset's price
+-- changes or it sells, and the buyer dashboard lists those updates.
+CREATE TABLE IF NOT EXISTS asset_favorites (
+    user_uuid TEXT NOT NULL,
+    asset_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_uuid, asset_id),
+
+    CONSTRAINT fk_asset_favorites_user
+        FOREIGN KEY (user_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_asset_favorites_asset
+        FOREIGN KEY (asset_id)
+        REFERENCES assets(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_favorites_asset ON asset_favorites(asset_id);
+
+-- Opt-in public read-only link to a user's favorite assets at /lists/:token. Deleting
+-- the row revokes the link; publishing again issues a new token.
+CREATE TABLE IF NOT EXISTS favorite_shares (
+    user_uuid TEXT PRIMARY KEY,
+    token TEXT UNIQUE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_favorite_shares_user
+        FOREIGN KEY (user_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE
+);
+
+-- Private GitHub repository behind a codebase asset, read through the seller's GitHub
+-- App installation. stats caches the last read for display.
+CREATE TABLE IF NOT EXISTS asset_repositories (
+    asset_id INT PRIMARY KEY,
+    installation_id BIGINT NOT NULL,
+    repo_full_name TEXT NOT NULL,       -- owner/name
+    stats JSONB NULL,
+    stats_fetched_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_asset_repositories_asset
+        FOREIGN KEY (asset_id)
+        REFERENCES assets(id)
+        ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS asset_data_previews (
+    asset_id INT PRIMARY KEY,
+    sample_key TEXT NOT NULL,           -- redacted CSV in blob storage
+    columns JSONB NOT NULL,             -- [{name, type, nulls}]
+    sample_rows INT NOT NULL,
+    total_rows BIGINT NULL,             -- declared by the seller
+    preview_rows JSONB NOT NULL,        -- first rows of the sample
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_asset_data_previews_asset
+        FOREIGN KEY (asset_id)
+        REFERENCES assets(id)
+        ON DELETE CASCADE
+);
+
+-- NDA or transfer agreement buyers sign before seeing deliverables or buying. version
+-- is bumped whenever the text changes.
+CREATE TABLE IF NOT EXISTS asset_agreements (
+    asset_id INT PRIMARY KEY,
+    kind TEXT NOT NULL CHECK (kind IN ('nda', 'transfer')),
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_asset_agreements_asset
+        FOREIGN KEY (asset_id)
+        REFERENCES assets(id)
+        ON DELETE CASCADE
+);
+
+-- Signed copies keep the text as signed, so they outlive edits to the template.
+CREATE TABLE IF NOT EXISTS agreement_signatures (
+    id SERIAL PRIMARY KEY,
+    asset_id INT NOT NULL,
+    seller_uuid TEXT NOT NULL,
+    signer_uuid TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    version INT NOT NULL,
+    signed_name TEXT NOT NULL,          -- typed by the signer
+    ip_address TEXT NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    signed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    UNIQUE (asset_id, signer_uuid, version),
+
+    CONSTRAINT fk_agreement_signatures_asset
+        FOREIGN KEY (asset_id)
+        REFERENCES assets(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_agreement_signatures_signer
+        FOREIGN KEY (signer_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_agreement_signatures_signer ON agreement_signatures(signer_uuid, signed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_agreement_signatures_seller ON agreement_signatures(seller_uuid, signed_at DESC);
+
+CREATE TABLE IF NOT EXISTS offers (
+    id SERIAL PRIMARY KEY,
+    asset_id INT NOT NULL,
+    buyer_uuid TEXT NOT NULL,
+    amount NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    message TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'rejected')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_offers_asset
+        FOREIGN KEY (asset_id)
+        REFERENCES assets(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_offers_buyer
+        FOREIGN KEY (buyer_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_offers_asset ON offers(asset_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_offers_buyer ON offers(buyer_uuid, created_at DESC);
+
+-- Seller's private negotiation bounds per listing; never shown to buyers.
+CREATE TABLE IF NOT EXISTS asset_offer_rules (
+    asset_id INT PRIMARY KEY,
+    min_price NUMERIC(12,2) NULL,       -- offers below are rejected automatically
+    auto_accept_asking BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_asset_offer_rules_asset
+        FOREIGN KEY (asset_id)
+        REFERENCES assets(id)
+        ON DELETE CASCADE
+);
+
+-- API keys for integrators. Only a SHA-256 hash of the key is stored; prefix is its
+-- first characters so owners can tell keys apart. Keys carry their own quota tier.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    user_uuid TEXT NOT NULL,
+    key_hash TEXT UNIQUE NOT NULL,
+    prefix TEXT NOT NULL,
+    tier TEXT NOT NULL DEFAULT 'partner',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP NULL,
+
+    CONSTRAINT fk_api_keys_user
+        FOREIGN KEY (user_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys(user_uuid);
+
+-- Request counts per quota subject ("ip:<addr>", "user:<uuid>" or "key:<id>") and
+-- UTC day or month. Windows before the previous month are pruned.
+CREATE TABLE IF NOT EXISTS api_usage (
+    subject TEXT NOT NULL,
+    period TEXT NOT NULL CHECK (period IN ('day', 'month')),
+    period_start DATE NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (subject, period, period_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_usage_period_start ON api_usage(period_start);
+
+-- Refresh tokens, stored hashed. Each login starts a family; a refresh revokes the
+-- presented token and adds its successor to the family, and presenting a revoked
+-- token again revokes the whole family.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    user_uuid TEXT NOT NULL,
+    family_id TEXT NOT NULL,
+    token_hash TEXT UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP NULL,
+
+    CONSTRAINT fk_refresh_tokens_user
+        FOREIGN KEY (user_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens(user_uuid) WHERE revoked_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    email TEXT NOT NULL,
+    token_hash TEXT UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_email ON password_reset_tokens(email, created_at DESC);
+
+-- External accounts linked to a profile; one per provider per user
+CREATE TABLE IF NOT EXISTS user_identities (
+    id BIGSERIAL PRIMARY KEY,
+    user_uuid TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    provider_user_id TEXT NOT NULL,
+    username TEXT NOT NULL,
+    profile_url TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_user_identities_user
+        FOREIGN KEY (user_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE,
+    UNIQUE (provider, provider_user_id),
+    UNIQUE (user_uuid, provider)
+);
+
+-- TOTP second factor; logins need a code once enabled_at is set
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_uuid TEXT PRIMARY KEY,
+    secret TEXT NOT NULL,
+    enabled_at TIMESTAMP NULL,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_user_totp_user
+        FOREIGN KEY (user_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE
+);
+
+-- One row per login, shared by the refresh tokens rotated from it (family_id)
+CREATE TABLE IF NOT EXISTS sessions (
+    id BIGSERIAL PRIMARY KEY,
+    user_uuid TEXT NOT NULL,
+    family_id TEXT UNIQUE NOT NULL,
+    device_name TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_sessions_user
+        FOREIGN KEY (user_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_uuid ON sessions(user_uuid);
+
+-- blocker stops all chat with blocked and hides their listings from them
+CREATE TABLE IF NOT EXISTS blocked_users (
+    blocker_uuid TEXT NOT NULL,
+    blocked_uuid TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (blocker_uuid, blocked_uuid),
+    CONSTRAINT fk_blocked_users_blocker
+        FOREIGN KEY (blocker_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_blocked_users_blocked
+        FOREIGN KEY (blocked_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE,
+    CHECK (blocker_uuid <> blocked_uuid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_blocked_users_blocked_uuid ON blocked_users(blocked_uuid);
+
+CREATE TABLE IF NOT EXISTS auth_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_uuid TEXT NOT NULL,
+    event_type TEXT NOT NULL,   -- login, login_failed, logout, otp_verified, password_changed
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_auth_events_user
+        FOREIGN KEY (user_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_events_user ON auth_events(user_uuid, created_at DESC);
+
+-- Requests made by support staff while impersonating a user. Kept when either
+-- account is deleted.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_uuid TEXT NOT NULL,   -- admin UUID, or admin_api_token
+    user_uuid TEXT NOT NULL,    -- impersonated user
+    method TEXT NOT NULL,
+    path TEXT NOT NULL,         -- route pattern
+    status INT NOT NULL,
+    ip TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_user ON audit_log(user_uuid, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at DESC);
+
+-- Signups made with another user's invite code. A user is referred at most once.
+CREATE TABLE IF NOT EXISTS referrals (
+    referred_uuid TEXT PRIMARY KEY,
+    referrer_uuid TEXT NOT NULL,
+    invite_code TEXT NOT NULL,   -- the code used, in case the referrer's changes
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_referrals_referred
+        FOREIGN KEY (referred_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_referrals_referrer
+        FOREIGN KEY (referrer_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer ON referrals(referrer_uuid, created_at DESC);
+
+-- Industry taxonomy startups are tagged with; GET /categories lists it.
+CREATE TABLE IF NOT EXISTS categories (
+    slug TEXT PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+INSERT INTO categories (slug, name) VALUES
+    ('saas', 'SaaS'),
+    ('fintech', 'Fintech'),
+    ('e-commerce', 'E-commerce'),
+    ('marketplace', 'Marketplace'),
+    ('edtech', 'Edtech'),
+    ('healthtech', 'Healthtech'),
+    ('ai', 'AI / ML'),
+    ('developer-tools', 'Developer tools'),
+    ('consumer', 'Consumer apps'),
+    ('media', 'Media & content'),
+    ('gaming', 'Gaming'),
+    ('hardware', 'Hardware'),
+    ('crypto', 'Crypto / Web3'),
+    ('other', 'Other')
+ON CONFLICT (slug) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS startup_categories (
+    startup_id INT NOT NULL,
+    category_slug TEXT NOT NULL,
+
+    PRIMARY KEY (startup_id, category_slug),
+
+    CONSTRAINT fk_startup_categories_startup
+        FOREIGN KEY (startup_id)
+        REFERENCES startups(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_startup_categories_category
+        FOREIGN KEY (category_slug)
+        REFERENCES categories(slug)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_startup_categories_category ON startup_categories(category_slug);
+
+-- Due-diligence files (pitch decks, financials) sellers attach to a startup. Rows
+-- start pending and are listed once the upload is completed.
+CREATE TABLE IF NOT EXISTS startup_documents (
+    id BIGSERIAL PRIMARY KEY,
+    startup_id INT NOT NULL,
+    title TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('pitch_deck', 'financials', 'legal', 'other')),
+    content_type TEXT NOT NULL,
+    storage_key TEXT UNIQUE NOT NULL,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_startup_documents_startup
+        FOREIGN KEY (startup_id)
+        REFERENCES startups(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_startup_documents_startup ON startup_documents(startup_id, created_at DESC);
+
+-- Ownership history: one row per POST /startups/:id/transfer. The user columns have
+-- no foreign key so the history outlives purged accounts.
+CREATE TABLE IF NOT EXISTS startup_ownership_transfers (
+    id BIGSERIAL PRIMARY KEY,
+    startup_id INT NOT NULL,
+    from_uuid TEXT NOT NULL,
+    to_uuid TEXT NOT NULL,
+    transferred_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_startup_ownership_transfers_startup
+        FOREIGN KEY (startup_id)
+        REFERENCES startups(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_startup_ownership_transfers_startup ON startup_ownership_transfers(startup_id, transferred_at);
+
+-- Startup page views, one row per viewer per UTC day; flushed in batches and pruned
+-- after 90 days. viewer is a user UUID or a hash of an anonymous client.
+CREATE TABLE IF NOT EXISTS startup_views (
+    startup_id INT NOT NULL,
+    viewer TEXT NOT NULL,
+    day DATE NOT NULL,
+
+    PRIMARY KEY (startup_id, viewer, day),
+
+    CONSTRAINT fk_startup_views_startup
+        FOREIGN KEY (startup_id)
+        REFERENCES startups(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_startup_views_day ON startup_views(day, startup_id);
+
+-- Founders' requests for the verified badge. A startup has at most one pending
+-- request; admins approve or reject it from /admin/startups.
+CREATE TABLE IF NOT EXISTS startup_verifications (
+    id BIGSERIAL PRIMARY KEY,
+    startup_id INT NOT NULL,
+    submitted_by TEXT NOT NULL,
+    proof_url TEXT NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    review_note TEXT NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_startup_verifications_startup
+        FOREIGN KEY (startup_id)
+        REFERENCES startups(id)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_startup_verifications_pending ON startup_verifications(startup_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_startup_verifications_startup ON startup_verifications(startup_id, id DESC);
+
+-- Collaborators on a startup. The owner is startups.owner_uuid and has no row here;
+-- editors may update the startup and viewers only see its team.
+CREATE TABLE IF NOT EXISTS startup_members (
+    startup_id INT NOT NULL,
+    user_uuid TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('editor', 'viewer')),
+    added_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (startup_id, user_uuid),
+
+    CONSTRAINT fk_startup_members_startup
+        FOREIGN KEY (startup_id)
+        REFERENCES startups(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_startup_members_user
+        FOREIGN KEY (user_uuid)
+        REFERENCES users(uuid)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_startup_members_user ON startup_members(user_uuid);
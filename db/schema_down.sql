@@ -0,0 +1,43 @@
+-- Reverts schema.sql and schema_update.sql ("server migrate down"). Drops all data.
+DROP TABLE IF EXISTS startup_members;
+DROP TABLE IF EXISTS startup_verifications;
+DROP TABLE IF EXISTS startup_views;
+DROP TABLE IF EXISTS startup_ownership_transfers;
+DROP TABLE IF EXISTS startup_documents;
+DROP TABLE IF EXISTS startup_categories;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS referrals;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS auth_events;
+DROP TABLE IF EXISTS blocked_users;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS user_totp;
+DROP TABLE IF EXISTS user_identities;
+DROP TABLE IF EXISTS password_reset_tokens;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS api_usage;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS asset_offer_rules;
+DROP TABLE IF EXISTS offers;
+DROP TABLE IF EXISTS agreement_signatures;
+DROP TABLE IF EXISTS asset_agreements;
+DROP TABLE IF EXISTS asset_data_previews;
+DROP TABLE IF EXISTS asset_repositories;
+DROP TABLE IF EXISTS startup_bookmarks;
+DROP TABLE IF EXISTS favorite_shares;
+DROP TABLE IF EXISTS asset_favorites;
+DROP TABLE IF EXISTS events;
+DROP TABLE IF EXISTS reports;
+DROP TABLE IF EXISTS activities;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS images;
+DROP TABLE IF EXISTS daily_stats;
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS email_suppressions;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS otps;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS messages;
+DROP TABLE IF EXISTS assets;
+DROP TABLE IF EXISTS startups;
+DROP TABLE IF EXISTS users;
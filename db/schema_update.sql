@@ -7,4 +7,139 @@
 -- ALTER TABLE assets
 --     ADD COLUMN IF NOT EXISTS priority SMALLINT NOT NULL DEFAULT 0,
 --     ADD COLUMN IF NOT EXISTS interested_buyers INT NOT NULL DEFAULT 0;
-    
\ No newline at end of file
+    
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS locale TEXT NOT NULL DEFAULT 'en';
+
+ALTER TABLE notification_preferences
+    ADD COLUMN IF NOT EXISTS email_on_message BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS email_on_offer BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS email_on_sale BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS push_enabled BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE assets
+    ADD COLUMN IF NOT EXISTS sold_at TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+ALTER TABLE startups
+    ADD COLUMN IF NOT EXISTS sold_at TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+-- Admin accounts (created with "server create-admin")
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('buyer', 'founder', 'admin'));
+
+-- ISO 3166 location of users and listings
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS country TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS region TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE startups
+    ADD COLUMN IF NOT EXISTS country TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS region TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE assets
+    ADD COLUMN IF NOT EXISTS country TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS region TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_startups_country ON startups(country, region);
+CREATE INDEX IF NOT EXISTS idx_assets_country ON assets(country, region);
+
+-- Tax breakdown of each sale
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS seller_country TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS buyer_country TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tax_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tax_rate NUMERIC(5,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_amount NUMERIC(12,2) NOT NULL DEFAULT 0;
+
+-- System messages summarizing an offer link to it
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS offer_id INT NULL;
+
+-- Prefix lookups for GET /search/suggest
+CREATE INDEX IF NOT EXISTS idx_assets_title_prefix ON assets(lower(title) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_startups_name_prefix ON startups(lower(name) text_pattern_ops);
+
+-- Moderators review reports
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('buyer', 'founder', 'moderator', 'admin'));
+
+-- Account deletion grace period
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMP NULL;
+CREATE INDEX IF NOT EXISTS idx_users_deletion_requested_at ON users(deletion_requested_at) WHERE deletion_requested_at IS NOT NULL;
+
+-- Profile handles
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS handle TEXT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_handle ON users(handle);
+
+-- Admin bans
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS banned_at TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS ban_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE assets
+    ADD COLUMN IF NOT EXISTS hidden_by_ban BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE startups
+    ADD COLUMN IF NOT EXISTS hidden_by_ban BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Invite codes
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS invite_code TEXT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_invite_code ON users(invite_code);
+
+-- Startup full-text search
+ALTER TABLE startups
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', name), 'A') || setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_startups_search_vector ON startups USING GIN (search_vector);
+
+-- Startup post-mortems
+ALTER TABLE startups
+    ADD COLUMN IF NOT EXISTS failure_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS lifespan_months INT NULL CHECK (lifespan_months >= 0),
+    ADD COLUMN IF NOT EXISTS peak_mrr NUMERIC(12,2) NULL CHECK (peak_mrr >= 0),
+    ADD COLUMN IF NOT EXISTS users_at_shutdown BIGINT NULL CHECK (users_at_shutdown >= 0);
+
+-- Startup slugs. Existing rows get their name lower-cased with non-alphanumeric runs
+-- turned into hyphens, numbered with the first free suffix when the slug is taken. A
+-- name can already end in a number ("Foo 2"), so suffixes are checked against the
+-- slugs given out so far rather than counted per name.
+ALTER TABLE startups
+    ADD COLUMN IF NOT EXISTS slug TEXT NULL;
+DO $$
+DECLARE
+    r RECORD;
+    candidate TEXT;
+    n INT;
+BEGIN
+    FOR r IN
+        SELECT id, COALESCE(NULLIF(left(trim(BOTH '-' FROM regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g')), 60), ''), 'startup') AS base
+        FROM startups
+        WHERE slug IS NULL
+        ORDER BY id
+    LOOP
+        candidate := r.base;
+        n := 1;
+        WHILE EXISTS (SELECT 1 FROM startups WHERE slug = candidate) LOOP
+            n := n + 1;
+            candidate := r.base || '-' || n;
+        END LOOP;
+        UPDATE startups SET slug = candidate WHERE id = r.id;
+    END LOOP;
+END $$;
+ALTER TABLE startups
+    ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_startups_slug ON startups(slug);
+
+-- Startup verification badge
+ALTER TABLE startups
+    ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP NULL;
+
+-- Startup restore. Startups deleted before this have no deletion time recorded; their
+-- last update is the closest there is.
+ALTER TABLE startups
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+UPDATE startups SET deleted_at = updated_at WHERE is_deleted = true AND hidden_by_ban = false AND deleted_at IS NULL;
@@ -0,0 +1,25 @@
+-- Demo data for local development ("server seed"). Safe to run repeatedly.
+INSERT INTO users (name, email, role, password_hash, uuid, verified_at)
+VALUES
+    -- password for both accounts: graveyard
+    ('Demo Founder', 'founder@example.com', 'founder', '$2a$10$Pb0sHpllSrgsoqC/yCfsV.coHXJoIazmsCK7obzZbh7PTNqkyXJgC', 'seed-founder-0001', NOW()),
+    ('Demo Buyer', 'buyer@example.com', 'buyer', '$2a$10$Pb0sHpllSrgsoqC/yCfsV.coHXJoIazmsCK7obzZbh7PTNqkyXJgC', 'seed-buyer-0001', NOW())
+ON CONFLICT (email) DO NOTHING;
+
+INSERT INTO startups (name, slug, description, owner_uuid, status)
+SELECT v.name, v.slug, v.description, 'seed-founder-0001', v.status
+FROM (VALUES
+    ('Acme CRM', 'acme-crm', 'CRM for freelancers that never found its market', 'failed'),
+    ('PetPal', 'petpal', 'On-demand dog walking, shut down after seed round', 'failed')
+) AS v(name, slug, description, status)
+WHERE NOT EXISTS (SELECT 1 FROM startups s WHERE s.owner_uuid = 'seed-founder-0001' AND s.name = v.name)
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO assets (user_uuid, title, description, asset_type, price, is_negotiable)
+SELECT 'seed-founder-0001', v.title, v.description, v.asset_type, v.price, TRUE
+FROM (VALUES
+    ('acmecrm.io', 'Premium domain, renewed until 2028', 'domain', 1200.00),
+    ('Acme CRM codebase', 'Go + React monorepo with billing and auth', 'codebase', 8000.00),
+    ('Dog walker survey data', '2,400 survey responses from pet owners', 'data', 300.00)
+) AS v(title, description, asset_type, price)
+WHERE NOT EXISTS (SELECT 1 FROM assets a WHERE a.user_uuid = 'seed-founder-0001' AND a.title = v.title);
@@ -0,0 +1,73 @@
+package activity
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/etag"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/response"
+)
+
+type ActivityHandler struct {
+	service *Service
+}
+
+func NewActivityHandler(service *Service) *ActivityHandler {
+	return &ActivityHandler{service: service}
+}
+
+func (h *ActivityHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/activity", etag.Middleware(), h.list)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *ActivityHandler) Operations() []openapi.Operation {
+	types := make([]string, len(Types))
+	for i, t := range Types {
+		types[i] = string(t)
+	}
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/activity",
+			Tag:         "activity",
+			Summary:     "Marketplace activity feed",
+			Description: "Recent public events, newest first: new asset and startup listings and sales. Entries for deleted or unlisted items are left out.",
+			Params: []openapi.Param{
+				openapi.Query("type", "string", "Comma-separated types to include: "+strings.Join(types, ", ")+" (default all)", false),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 20)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[Activity]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+	}
+}
+
+const feedPageSize = 20
+
+func (h *ActivityHandler) list(c *gin.Context) {
+	types, err := ParseTypes(c.Query("type"))
+	if err != nil {
+		response.SendError(c, response.InvalidField(apperr.InvalidRequest, "type", "oneof", "invalid activity type"))
+		return
+	}
+	p, err := pagination.FromRequest(c, feedPageSize)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	list, total, err := h.service.List(c.Request.Context(), types, p.Limit, p.Offset())
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "activity listed", list, total, p)
+}
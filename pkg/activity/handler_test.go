@@ -0,0 +1,66 @@
+package activity
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockActivityRepository struct {
+	mock.Mock
+}
+
+func (m *mockActivityRepository) Record(ctx context.Context, t Type, entityID int64) error {
+	args := m.Called(ctx, t, entityID)
+	return args.Error(0)
+}
+
+func (m *mockActivityRepository) List(ctx context.Context, types []Type, limit, offset int) ([]Activity, int64, error) {
+	args := m.Called(ctx, types, limit, offset)
+	list, _ := args.Get(0).([]Activity)
+	return list, args.Get(1).(int64), args.Error(2)
+}
+
+func newRouter(repo ActivityRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewActivityHandler(NewService(repo)).RegisterRoutes(r)
+	return r
+}
+
+func TestActivityHandler_List_FiltersByType(t *testing.T) {
+	repo := new(mockActivityRepository)
+	repo.On("List", mock.Anything, []Type{AssetListed, StartupSold}, 5, 5).
+		Return([]Activity{{ID: 1, Type: AssetListed, EntityID: 3, Title: "acme.io"}}, int64(6), nil)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/activity?type=asset_listed,startup_sold&page=2&limit=5", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"title":"acme.io"`)
+	repo.AssertExpectations(t)
+}
+
+func TestActivityHandler_List_RejectsUnknownType(t *testing.T) {
+	repo := new(mockActivityRepository)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/activity?type=asset_deleted", nil))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	repo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_Record_SwallowsErrors(t *testing.T) {
+	repo := new(mockActivityRepository)
+	repo.On("Record", mock.Anything, AssetSold, int64(4)).Return(context.DeadlineExceeded)
+
+	NewService(repo).Record(context.Background(), AssetSold, 4)
+
+	repo.AssertExpectations(t)
+}
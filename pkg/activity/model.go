@@ -0,0 +1,37 @@
+package activity
+
+import "time"
+
+// Type is what happened. Each type refers to an asset or a startup through EntityID.
+type Type string
+
+const (
+	AssetListed   Type = "asset_listed"
+	AssetSold     Type = "asset_sold"
+	StartupListed Type = "startup_listed"
+	StartupSold   Type = "startup_sold"
+)
+
+// Types lists every activity type, in the order they are documented.
+var Types = []Type{AssetListed, AssetSold, StartupListed, StartupSold}
+
+func (t Type) valid() bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Activity is one entry in the public feed. Title and Price are read from the
+// asset or startup when the feed is listed, so edits show up and deleted entries
+// drop out.
+type Activity struct {
+	ID        int64     `json:"id"`
+	Type      Type      `json:"type"`
+	EntityID  int64     `json:"entity_id"`
+	Title     string    `json:"title"`
+	Price     float64   `json:"price,omitempty"` // assets only
+	CreatedAt time.Time `json:"created_at"`
+}
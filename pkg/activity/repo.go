@@ -0,0 +1,67 @@
+package activity
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ActivityRepository interface {
+	Record(ctx context.Context, t Type, entityID int64) error
+	List(ctx context.Context, types []Type, limit, offset int) ([]Activity, int64, error)
+}
+
+type postgresActivityRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresActivityRepository(pool *pgxpool.Pool) ActivityRepository {
+	return &postgresActivityRepository{pool: pool}
+}
+
+func (r *postgresActivityRepository) Record(ctx context.Context, t Type, entityID int64) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO activities (type, entity_id) VALUES ($1, $2)`, t, entityID)
+	return err
+}
+
+// visibleActivities joins each activity to its asset or startup, skipping ones
+// whose subject has since been deleted or unlisted.
+const visibleActivities = `
+	FROM activities a
+	LEFT JOIN assets s ON a.type IN ('asset_listed', 'asset_sold') AND s.id = a.entity_id
+	LEFT JOIN startups st ON a.type IN ('startup_listed', 'startup_sold') AND st.id = a.entity_id
+	WHERE (a.type = ANY($1) OR cardinality($1::text[]) = 0)
+	  AND ((s.id IS NOT NULL AND s.is_deleted = false AND s.is_active = true)
+	    OR (st.id IS NOT NULL AND st.is_deleted = false))`
+
+func (r *postgresActivityRepository) List(ctx context.Context, types []Type, limit, offset int) ([]Activity, int64, error) {
+	filter := make([]string, len(types))
+	for i, t := range types {
+		filter[i] = string(t)
+	}
+
+	var total int64
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*)`+visibleActivities, filter).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT a.id, a.type, a.entity_id, COALESCE(s.title, st.name), COALESCE(s.price, 0)::float8, a.created_at` +
+		visibleActivities + `
+	ORDER BY a.id DESC
+	LIMIT $2 OFFSET $3`
+	rows, err := r.pool.Query(ctx, query, filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var list []Activity
+	for rows.Next() {
+		var a Activity
+		if err := rows.Scan(&a.ID, &a.Type, &a.EntityID, &a.Title, &a.Price, &a.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		list = append(list, a)
+	}
+	return list, total, rows.Err()
+}
@@ -0,0 +1,56 @@
+package activity
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/requestid"
+)
+
+var ErrInvalidType = apperr.New(apperr.InvalidRequest, "invalid activity type")
+
+// Recorder is what other modules depend on to add to the feed.
+type Recorder interface {
+	Record(ctx context.Context, t Type, entityID int64)
+}
+
+type Service struct {
+	repo ActivityRepository
+}
+
+func NewService(repo ActivityRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Record adds an activity. The feed is best effort: failures are logged and never
+// reach the caller, whose own write has already succeeded.
+func (s *Service) Record(ctx context.Context, t Type, entityID int64) {
+	if err := s.repo.Record(ctx, t, entityID); err != nil {
+		log.Printf("[%s] record %s activity for %d failed: %v", requestid.FromContext(ctx), t, entityID, err)
+	}
+}
+
+// List returns the newest activities, optionally only those of the given types.
+func (s *Service) List(ctx context.Context, types []Type, limit, offset int) ([]Activity, int64, error) {
+	return s.repo.List(ctx, types, limit, offset)
+}
+
+// ParseTypes reads a comma-separated type filter such as "asset_listed,startup_sold".
+// An empty string means every type.
+func ParseTypes(raw string) ([]Type, error) {
+	var types []Type
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		t := Type(part)
+		if !t.valid() {
+			return nil, ErrInvalidType
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+// BulkFilter narrows an admin bulk operation. Zero fields match everything, which is
+// why ParseBulkFilter insists on at least one.
+type BulkFilter struct {
+	OwnerUUID     string
+	CreatedBefore *time.Time
+	Status        string
+}
+
+// BulkResult is how many rows an admin bulk operation touched or, on a dry run, would
+// touch.
+type BulkResult struct {
+	Matched int64 `json:"matched"`
+	DryRun  bool  `json:"dry_run"`
+}
+
+// BulkParams documents the query parameters read by ParseBulkFilter.
+func BulkParams(statuses ...string) []openapi.Param {
+	return []openapi.Param{
+		openapi.Query("owner_uuid", "string", "Only rows owned by this user", false),
+		openapi.Query("created_before", "string", "Only rows created before this instant, RFC 3339 or YYYY-MM-DD", false),
+		{Name: "status", In: "query", Type: "string", Description: "Only rows in this status", Enum: statuses},
+		openapi.Query("dry_run", "boolean", "Report the matching count without changing anything", false),
+	}
+}
+
+// ParseBulkFilter reads owner_uuid, created_before, status and dry_run from the query
+// string. status must be one of statuses, and at least one filter must be given so a
+// bulk operation can never silently cover the whole table.
+func ParseBulkFilter(c *gin.Context, statuses ...string) (BulkFilter, bool, error) {
+	var f BulkFilter
+	f.OwnerUUID = strings.TrimSpace(c.Query("owner_uuid"))
+
+	if v := c.Query("created_before"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, v)
+		}
+		if err != nil {
+			return BulkFilter{}, false, response.InvalidField(apperr.InvalidRequest, "created_before", "datetime", "invalid created_before, expected RFC 3339 or YYYY-MM-DD")
+		}
+		f.CreatedBefore = &t
+	}
+
+	if v := c.Query("status"); v != "" {
+		if !slices.Contains(statuses, v) {
+			return BulkFilter{}, false, response.InvalidField(apperr.InvalidRequest, "status", "oneof", "invalid status")
+		}
+		f.Status = v
+	}
+
+	dryRun := false
+	if v := c.Query("dry_run"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return BulkFilter{}, false, response.InvalidField(apperr.InvalidRequest, "dry_run", "boolean", "invalid dry_run, expected true or false")
+		}
+		dryRun = parsed
+	}
+
+	if f.OwnerUUID == "" && f.CreatedBefore == nil && f.Status == "" {
+		return BulkFilter{}, false, apperr.New(apperr.InvalidRequest, "at least one of owner_uuid, created_before or status is required")
+	}
+	return f, dryRun, nil
+}
@@ -0,0 +1,109 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+// DefaultRangeDays is the range returned when from/to are omitted.
+const DefaultRangeDays = 30
+
+type AdminHandler struct {
+	service StatsService
+	token   string
+}
+
+// NewAdminHandler serves the admin endpoints to callers presenting token as a bearer token.
+func NewAdminHandler(service StatsService, token string) *AdminHandler {
+	return &AdminHandler{service: service, token: token}
+}
+
+func (h *AdminHandler) RegisterRoutes(router *gin.Engine) {
+	group := router.Group("/admin", RequireToken(h.token))
+	group.GET("/stats", h.getStats)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *AdminHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/admin/stats",
+			Tag:         "admin",
+			Summary:     "Marketplace statistics",
+			Description: "Daily signups, new listings, sales, active chats and OTP sends for a UTC date range (max 366 days)",
+			Params: []openapi.Param{
+				openapi.Query("from", "string", "First day, YYYY-MM-DD (default: 29 days before to)", false),
+				openapi.Query("to", "string", "Last day, YYYY-MM-DD (default: today)", false),
+			},
+			Response: Stats{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+// RequireToken rejects requests that do not present token as a bearer token. Other
+// modules use it to put their moderation routes under /admin.
+func RequireToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			response.SendError(c, apperr.New(apperr.Unauthorized, "unauthorized"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireTokenOrPermission is RequireToken that also lets in signed-in users whose
+// role has perm, so staff can use their own access token instead of the shared one.
+func RequireTokenOrPermission(token string, perm auth.Permission) gin.HandlerFunc {
+	requireToken := RequireToken(token)
+	return func(c *gin.Context) {
+		if auth.UserID(c) != "" && auth.Can(auth.Role(c), perm) {
+			c.Next()
+			return
+		}
+		requireToken(c)
+	}
+}
+
+func (h *AdminHandler) getStats(c *gin.Context) {
+	to := time.Now().UTC()
+	if v := c.Query("to"); v != "" {
+		parsed, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			response.SendError(c, response.InvalidField(apperr.InvalidRequest, "to", "date", "invalid to date, expected YYYY-MM-DD"))
+			return
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -(DefaultRangeDays - 1))
+	if v := c.Query("from"); v != "" {
+		parsed, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			response.SendError(c, response.InvalidField(apperr.InvalidRequest, "from", "date", "invalid from date, expected YYYY-MM-DD"))
+			return
+		}
+		from = parsed
+	}
+
+	stats, err := h.service.Stats(c.Request.Context(), from, to)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+
+	response.SendAPIResponse(c, http.StatusOK, true, "stats fetched", stats)
+}
@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/auth"
+	"grveyard/pkg/response"
+	"grveyard/pkg/testhelpers"
+)
+
+type mockStatsService struct {
+	mock.Mock
+}
+
+func (m *mockStatsService) Stats(ctx context.Context, from, to time.Time) (Stats, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).(Stats), args.Error(1)
+}
+
+func (m *mockStatsService) RefreshRecent(ctx context.Context, days int) error {
+	args := m.Called(ctx, days)
+	return args.Error(0)
+}
+
+func setupRouter(svc StatsService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewAdminHandler(svc, "secret").RegisterRoutes(r)
+	return r
+}
+
+func getStats(r *gin.Engine, query, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/admin/stats"+query, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminHandler_RequiresToken(t *testing.T) {
+	svc := new(mockStatsService)
+	r := setupRouter(svc)
+
+	require.Equal(t, http.StatusUnauthorized, getStats(r, "", "").Code)
+	require.Equal(t, http.StatusUnauthorized, getStats(r, "", "wrong").Code)
+	svc.AssertNotCalled(t, "Stats", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRequireTokenOrPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, tc := range []struct {
+		name  string
+		role  string
+		token string
+		want  int
+	}{
+		{"moderator", auth.RoleModerator, "", http.StatusOK},
+		{"admin", auth.RoleAdmin, "", http.StatusOK},
+		{"founder", "founder", "", http.StatusUnauthorized},
+		{"founder with token", "founder", "secret", http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(testhelpers.AuthAs("user-1", tc.role))
+			r.GET("/admin/reports", RequireTokenOrPermission("secret", auth.PermReviewReports), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/admin/reports", nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			require.Equal(t, tc.want, w.Code)
+		})
+	}
+}
+
+func TestAdminHandler_GetStats(t *testing.T) {
+	svc := new(mockStatsService)
+	r := setupRouter(svc)
+
+	svc.On("Stats", mock.Anything, day("2025-03-01"), day("2025-03-07")).
+		Return(Stats{From: "2025-03-01", To: "2025-03-07", Totals: DailyStats{Signups: 4}}, nil)
+
+	w := getStats(r, "?from=2025-03-01&to=2025-03-07", "secret")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp response.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.True(t, resp.Success)
+	require.Contains(t, w.Body.String(), `"signups":4`)
+	svc.AssertExpectations(t)
+}
+
+func TestAdminHandler_InvalidDates(t *testing.T) {
+	svc := new(mockStatsService)
+	r := setupRouter(svc)
+
+	require.Equal(t, http.StatusBadRequest, getStats(r, "?from=03/01/2025", "secret").Code)
+
+	svc.On("Stats", mock.Anything, mock.Anything, mock.Anything).Return(Stats{}, ErrInvalidRange)
+	require.Equal(t, http.StatusBadRequest, getStats(r, "?from=2025-03-05&to=2025-03-01", "secret").Code)
+}
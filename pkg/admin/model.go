@@ -0,0 +1,35 @@
+package admin
+
+import "time"
+
+// DailyStats is one row of the daily_stats rollup. Day is midnight UTC.
+type DailyStats struct {
+	Day          time.Time `json:"day"`
+	Signups      int64     `json:"signups"`
+	NewAssets    int64     `json:"new_assets"`
+	NewStartups  int64     `json:"new_startups"`
+	AssetsSold   int64     `json:"assets_sold"`
+	StartupsSold int64     `json:"startups_sold"`
+	SalesVolume  float64   `json:"sales_volume"` // sum of asset prices sold that day
+	ActiveChats  int64     `json:"active_chats"` // distinct user pairs that exchanged messages
+	OTPsSent     int64     `json:"otps_sent"`
+}
+
+// Stats is the /admin/stats payload.
+type Stats struct {
+	From   string       `json:"from"`
+	To     string       `json:"to"`
+	Days   []DailyStats `json:"days"`
+	Totals DailyStats   `json:"totals"` // Day is left zero; ActiveChats sums per-day counts
+}
+
+func (d *DailyStats) add(o DailyStats) {
+	d.Signups += o.Signups
+	d.NewAssets += o.NewAssets
+	d.NewStartups += o.NewStartups
+	d.AssetsSold += o.AssetsSold
+	d.StartupsSold += o.StartupsSold
+	d.SalesVolume += o.SalesVolume
+	d.ActiveChats += o.ActiveChats
+	d.OTPsSent += o.OTPsSent
+}
@@ -0,0 +1,65 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PurgeCounts is how many soft-deleted rows were (or, on a dry run, would be) removed.
+type PurgeCounts struct {
+	Assets   int64
+	Startups int64
+	Users    int64
+}
+
+// Rows referenced by a transaction are kept so sales history stays intact, and
+// listings hidden by a ban are kept for an unban; deleting a user cascades to
+// whatever they still own.
+var purgeStatements = []struct {
+	query string
+	count func(*PurgeCounts) *int64
+}{
+	{
+		`DELETE FROM assets a
+		 WHERE a.is_deleted = true AND a.hidden_by_ban = false
+		   AND NOT EXISTS (SELECT 1 FROM transactions t WHERE t.asset_id = a.id)`,
+		func(c *PurgeCounts) *int64 { return &c.Assets },
+	},
+	{
+		`DELETE FROM startups WHERE is_deleted = true AND hidden_by_ban = false`,
+		func(c *PurgeCounts) *int64 { return &c.Startups },
+	},
+	{
+		`DELETE FROM users u
+		 WHERE u.is_deleted = true
+		   AND NOT EXISTS (SELECT 1 FROM transactions t WHERE t.buyer_id = u.id)
+		   AND NOT EXISTS (SELECT 1 FROM transactions t JOIN assets a ON a.id = t.asset_id WHERE a.user_uuid = u.uuid)`,
+		func(c *PurgeCounts) *int64 { return &c.Users },
+	},
+}
+
+// PurgeSoftDeleted permanently removes soft-deleted assets, startups and users in a
+// single transaction. A dry run performs the deletes to count them and rolls back.
+func PurgeSoftDeleted(ctx context.Context, pool *pgxpool.Pool, dryRun bool) (PurgeCounts, error) {
+	var counts PurgeCounts
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return counts, err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, stmt := range purgeStatements {
+		tag, err := tx.Exec(ctx, stmt.query)
+		if err != nil {
+			return PurgeCounts{}, err
+		}
+		*stmt.count(&counts) = tag.RowsAffected()
+	}
+
+	if dryRun {
+		return counts, nil
+	}
+	return counts, tx.Commit(ctx)
+}
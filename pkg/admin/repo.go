@@ -0,0 +1,88 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StatsRepository interface {
+	// Rollup recomputes the daily_stats rows for every day in [from, to].
+	Rollup(ctx context.Context, from, to time.Time) error
+	ListDailyStats(ctx context.Context, from, to time.Time) ([]DailyStats, error)
+}
+
+type postgresStatsRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresStatsRepository(pool *pgxpool.Pool) StatsRepository {
+	return &postgresStatsRepository{pool: pool}
+}
+
+// Rollup aggregates the source tables per UTC day. OTP rows are deleted once expired,
+// so otps_sent only ever grows; rolling up a day after its OTPs are purged keeps the
+// earlier count.
+func (r *postgresStatsRepository) Rollup(ctx context.Context, from, to time.Time) error {
+	query := `WITH days AS (
+	              SELECT d::date AS day FROM generate_series($1::date, $2::date, INTERVAL '1 day') d
+	          )
+	          INSERT INTO daily_stats (day, signups, new_assets, new_startups, assets_sold, startups_sold,
+	                                   sales_volume, active_chats, otps_sent, updated_at)
+	          SELECT days.day,
+	                 (SELECT COUNT(*) FROM users WHERE created_at::date = days.day),
+	                 (SELECT COUNT(*) FROM assets WHERE created_at::date = days.day),
+	                 (SELECT COUNT(*) FROM startups WHERE created_at::date = days.day),
+	                 (SELECT COUNT(*) FROM assets WHERE sold_at::date = days.day),
+	                 (SELECT COUNT(*) FROM startups WHERE sold_at::date = days.day),
+	                 (SELECT COALESCE(SUM(price), 0) FROM assets WHERE sold_at::date = days.day),
+	                 (SELECT COUNT(DISTINCT (LEAST(sender_id, receiver_id), GREATEST(sender_id, receiver_id)))
+	                    FROM messages
+	                   WHERE messaged_at >= EXTRACT(EPOCH FROM days.day::timestamp)::BIGINT
+	                     AND messaged_at < EXTRACT(EPOCH FROM (days.day + 1)::timestamp)::BIGINT),
+	                 (SELECT COUNT(*) FROM otps WHERE created_at::date = days.day),
+	                 NOW()
+	          FROM days
+	          ON CONFLICT (day) DO UPDATE SET
+	              signups = EXCLUDED.signups,
+	              new_assets = EXCLUDED.new_assets,
+	              new_startups = EXCLUDED.new_startups,
+	              assets_sold = EXCLUDED.assets_sold,
+	              startups_sold = EXCLUDED.startups_sold,
+	              sales_volume = EXCLUDED.sales_volume,
+	              active_chats = EXCLUDED.active_chats,
+	              otps_sent = GREATEST(daily_stats.otps_sent, EXCLUDED.otps_sent),
+	              updated_at = NOW()`
+	_, err := r.pool.Exec(ctx, query, from, to)
+	return err
+}
+
+func (r *postgresStatsRepository) ListDailyStats(ctx context.Context, from, to time.Time) ([]DailyStats, error) {
+	query := `SELECT day, signups, new_assets, new_startups, assets_sold, startups_sold,
+	                 sales_volume::float8, active_chats, otps_sent
+	          FROM daily_stats
+	          WHERE day BETWEEN $1::date AND $2::date
+	          ORDER BY day`
+
+	rows, err := r.pool.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]DailyStats, 0)
+	for rows.Next() {
+		var d DailyStats
+		if err := rows.Scan(&d.Day, &d.Signups, &d.NewAssets, &d.NewStartups, &d.AssetsSold, &d.StartupsSold,
+			&d.SalesVolume, &d.ActiveChats, &d.OTPsSent); err != nil {
+			return nil, err
+		}
+		list = append(list, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
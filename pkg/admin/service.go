@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"grveyard/pkg/apperr"
+)
+
+// MaxRangeDays bounds a single /admin/stats query.
+const MaxRangeDays = 366
+
+var ErrInvalidRange = apperr.New(apperr.InvalidDateRange, "from must not be after to and the range may span at most 366 days")
+
+type StatsService interface {
+	// Stats returns one row per day in [from, to] (UTC dates), zero-filled.
+	Stats(ctx context.Context, from, to time.Time) (Stats, error)
+	// RefreshRecent re-aggregates today and the previous days-1 days.
+	RefreshRecent(ctx context.Context, days int) error
+}
+
+type statsService struct {
+	repo StatsRepository
+	now  func() time.Time
+}
+
+func NewStatsService(repo StatsRepository) StatsService {
+	return &statsService{repo: repo, now: time.Now}
+}
+
+func (s *statsService) today() time.Time {
+	return truncateDay(s.now())
+}
+
+func (s *statsService) Stats(ctx context.Context, from, to time.Time) (Stats, error) {
+	from, to = truncateDay(from), truncateDay(to)
+	if to.Before(from) || to.Sub(from) >= MaxRangeDays*24*time.Hour {
+		return Stats{}, ErrInvalidRange
+	}
+
+	// Today's row changes all day; refresh it so the dashboard is not an hour behind
+	if today := s.today(); !today.Before(from) && !today.After(to) {
+		if err := s.repo.Rollup(ctx, today, today); err != nil {
+			return Stats{}, err
+		}
+	}
+
+	rows, err := s.repo.ListDailyStats(ctx, from, to)
+	if err != nil {
+		return Stats{}, err
+	}
+	byDay := make(map[time.Time]DailyStats, len(rows))
+	for _, r := range rows {
+		byDay[truncateDay(r.Day)] = r
+	}
+
+	out := Stats{From: from.Format(time.DateOnly), To: to.Format(time.DateOnly)}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		d, ok := byDay[day]
+		if !ok {
+			d = DailyStats{}
+		}
+		d.Day = day
+		out.Days = append(out.Days, d)
+		out.Totals.add(d)
+	}
+	return out, nil
+}
+
+func (s *statsService) RefreshRecent(ctx context.Context, days int) error {
+	if days < 1 {
+		days = 1
+	}
+	today := s.today()
+	return s.repo.Rollup(ctx, today.AddDate(0, 0, -(days-1)), today)
+}
+
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
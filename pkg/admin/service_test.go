@@ -0,0 +1,87 @@
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStatsRepository struct {
+	mock.Mock
+}
+
+func (m *mockStatsRepository) Rollup(ctx context.Context, from, to time.Time) error {
+	args := m.Called(ctx, from, to)
+	return args.Error(0)
+}
+
+func (m *mockStatsRepository) ListDailyStats(ctx context.Context, from, to time.Time) ([]DailyStats, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).([]DailyStats), args.Error(1)
+}
+
+func day(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+func newTestService(repo StatsRepository, now string) *statsService {
+	return &statsService{repo: repo, now: func() time.Time { return day(now).Add(15 * time.Hour) }}
+}
+
+func TestStats_ZeroFillsAndTotals(t *testing.T) {
+	repo := new(mockStatsRepository)
+	svc := newTestService(repo, "2025-03-10")
+
+	repo.On("ListDailyStats", mock.Anything, day("2025-03-01"), day("2025-03-03")).Return([]DailyStats{
+		{Day: day("2025-03-01"), Signups: 2, SalesVolume: 100},
+		{Day: day("2025-03-03"), Signups: 1, AssetsSold: 1, SalesVolume: 50.5},
+	}, nil)
+
+	stats, err := svc.Stats(context.Background(), day("2025-03-01"), day("2025-03-03"))
+
+	require.NoError(t, err)
+	require.Len(t, stats.Days, 3)
+	require.Equal(t, day("2025-03-02"), stats.Days[1].Day)
+	require.Zero(t, stats.Days[1].Signups)
+	require.Equal(t, int64(3), stats.Totals.Signups)
+	require.Equal(t, 150.5, stats.Totals.SalesVolume)
+	require.Equal(t, "2025-03-01", stats.From)
+	repo.AssertNotCalled(t, "Rollup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStats_RefreshesTodayWhenInRange(t *testing.T) {
+	repo := new(mockStatsRepository)
+	svc := newTestService(repo, "2025-03-10")
+
+	repo.On("Rollup", mock.Anything, day("2025-03-10"), day("2025-03-10")).Return(nil)
+	repo.On("ListDailyStats", mock.Anything, day("2025-03-09"), day("2025-03-10")).Return([]DailyStats{}, nil)
+
+	_, err := svc.Stats(context.Background(), day("2025-03-09"), day("2025-03-10"))
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestStats_InvalidRange(t *testing.T) {
+	svc := newTestService(new(mockStatsRepository), "2025-03-10")
+
+	_, err := svc.Stats(context.Background(), day("2025-03-05"), day("2025-03-01"))
+	require.ErrorIs(t, err, ErrInvalidRange)
+
+	_, err = svc.Stats(context.Background(), day("2024-01-01"), day("2025-03-01"))
+	require.ErrorIs(t, err, ErrInvalidRange)
+}
+
+func TestRefreshRecent(t *testing.T) {
+	repo := new(mockStatsRepository)
+	svc := newTestService(repo, "2025-03-10")
+
+	repo.On("Rollup", mock.Anything, day("2025-03-09"), day("2025-03-10")).Return(nil)
+
+	require.NoError(t, svc.RefreshRecent(context.Background(), 2))
+	repo.AssertExpectations(t)
+}
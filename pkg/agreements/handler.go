@@ -0,0 +1,222 @@
+package agreements
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+type AgreementHandler struct {
+	service *Service
+}
+
+func NewAgreementHandler(service *Service) *AgreementHandler {
+	return &AgreementHandler{service: service}
+}
+
+func (h *AgreementHandler) RegisterRoutes(router *gin.Engine) {
+	router.PUT("/assets/:id/agreement", auth.Required(), h.attach)
+	router.GET("/assets/:id/agreement", h.get)
+	router.DELETE("/assets/:id/agreement", auth.Required(), h.remove)
+	router.POST("/assets/:id/agreement/sign", auth.Required(), h.sign)
+	router.GET("/agreements/:id", auth.Required(), h.signed)
+	router.GET("/users/:uuid/agreements", auth.RequireSelf("uuid"), h.listSigned)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *AgreementHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPut,
+			Path:        "/assets/:id/agreement",
+			Tag:         "agreements",
+			Summary:     "Attach an agreement to an asset",
+			Description: "Sets the NDA or transfer agreement buyers must sign before viewing the asset's data preview or completing the purchase. Changing the text starts a new version, which buyers have to sign again.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Request:  AttachRequest{},
+			Response: Agreement{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/assets/:id/agreement",
+			Tag:     "agreements",
+			Summary: "Get an asset's agreement",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Response: Agreement{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/assets/:id/agreement",
+			Tag:         "agreements",
+			Summary:     "Remove an asset's agreement",
+			Description: "Buyers no longer need to sign. Copies signed so far are kept.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/assets/:id/agreement/sign",
+			Tag:         "agreements",
+			Summary:     "Sign an asset's agreement",
+			Description: "Records the typed-name signature with the time, IP address and user agent. version must be the current version from GET /assets/:id/agreement; 409 means the text changed since it was read. Signing again returns the existing copy.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Request:  SignRequest{},
+			Response: Signature{},
+			Status:   http.StatusCreated,
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/agreements/:id",
+			Tag:         "agreements",
+			Summary:     "Get a signed agreement",
+			Description: "The signed copy, available to the signer and the seller",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Signature ID"),
+			},
+			Response: Signature{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/agreements",
+			Tag:         "agreements",
+			Summary:     "List signed agreements",
+			Description: "Copies the user signed as a buyer or received as the seller, most recent first",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 20)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[Signature]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+const agreementsPageSize = 20
+
+func parseID(c *gin.Context, what string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid "+what+" id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *AgreementHandler) attach(c *gin.Context) {
+	id, ok := parseID(c, "asset")
+	if !ok {
+		return
+	}
+	var req AttachRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	agreement, err := h.service.Attach(c.Request.Context(), id, auth.UserID(c), req)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "agreement attached", agreement)
+}
+
+func (h *AgreementHandler) get(c *gin.Context) {
+	id, ok := parseID(c, "asset")
+	if !ok {
+		return
+	}
+
+	agreement, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "agreement fetched", agreement)
+}
+
+func (h *AgreementHandler) remove(c *gin.Context) {
+	id, ok := parseID(c, "asset")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Remove(c.Request.Context(), id, auth.UserID(c)); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "agreement removed", nil)
+}
+
+func (h *AgreementHandler) sign(c *gin.Context) {
+	id, ok := parseID(c, "asset")
+	if !ok {
+		return
+	}
+	var req SignRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	sig, err := h.service.Sign(c.Request.Context(), id, auth.UserID(c), req, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusCreated, true, "agreement signed", sig)
+}
+
+func (h *AgreementHandler) signed(c *gin.Context) {
+	id, ok := parseID(c, "agreement")
+	if !ok {
+		return
+	}
+
+	sig, err := h.service.Signed(c.Request.Context(), id, auth.UserID(c))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "signed agreement fetched", sig)
+}
+
+func (h *AgreementHandler) listSigned(c *gin.Context) {
+	p, err := pagination.FromRequest(c, agreementsPageSize)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	list, total, err := h.service.ListSigned(c.Request.Context(), c.Param("uuid"), p)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "signed agreements listed", list, total, p)
+}
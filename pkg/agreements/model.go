@@ -0,0 +1,53 @@
+package agreements
+
+import "time"
+
+// Kind is the type of agreement a seller attaches to a listing.
+type Kind string
+
+const (
+	KindNDA      Kind = "nda"
+	KindTransfer Kind = "transfer" // asset purchase / transfer agreement
+)
+
+// Agreement is the template attached to an asset. Buyers sign a specific Version;
+// editing the text starts a new version that has to be signed again.
+type Agreement struct {
+	AssetID   int64     `json:"asset_id"`
+	Kind      Kind      `json:"kind"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Signature is a signed copy. Kind, Title and Body are the text as signed, so the
+// copy stays valid after the seller edits or removes the template.
+type Signature struct {
+	ID         int64     `json:"id"`
+	AssetID    int64     `json:"asset_id"`
+	SellerUUID string    `json:"seller_uuid"`
+	SignerUUID string    `json:"signer_uuid"`
+	Kind       Kind      `json:"kind"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"`
+	Version    int       `json:"version"`
+	SignedName string    `json:"signed_name"` // typed by the signer
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	SignedAt   time.Time `json:"signed_at"`
+}
+
+// AttachRequest attaches or replaces the agreement on an asset.
+type AttachRequest struct {
+	Kind  Kind   `json:"kind" binding:"required,oneof=nda transfer"`
+	Title string `json:"title" binding:"required,max=200"`
+	Body  string `json:"body" binding:"required,max=50000"`
+}
+
+// SignRequest signs the given version of an asset's agreement; a stale version is
+// refused so buyers never sign text they have not seen.
+type SignRequest struct {
+	SignedName string `json:"signed_name" binding:"required,max=200"`
+	Version    int    `json:"version" binding:"required,gt=0"`
+}
@@ -0,0 +1,163 @@
+package agreements
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AgreementRepository interface {
+	// AssetOwner returns the owner of a live asset.
+	AssetOwner(ctx context.Context, assetID int64) (string, error)
+	// Save attaches the agreement, bumping its version if the text changed.
+	Save(ctx context.Context, a Agreement) (Agreement, error)
+	Get(ctx context.Context, assetID int64) (Agreement, error)
+	Delete(ctx context.Context, assetID int64) error
+	// Sign records sig against the current agreement if it is still at sig.Version.
+	// Signing the same version twice returns the first signature.
+	Sign(ctx context.Context, sig Signature) (Signature, error)
+	// Signed reports whether userUUID signed the current version of the asset's
+	// agreement. attached is false when the asset has no agreement.
+	Signed(ctx context.Context, assetID int64, userUUID string) (attached, signed bool, err error)
+	GetSignature(ctx context.Context, id int64) (Signature, error)
+	// ListSignatures returns the copies userUUID signed or countersigned as seller,
+	// newest first.
+	ListSignatures(ctx context.Context, userUUID string, limit, offset int) ([]Signature, int64, error)
+}
+
+type postgresAgreementRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresAgreementRepository(pool *pgxpool.Pool) AgreementRepository {
+	return &postgresAgreementRepository{pool: pool}
+}
+
+func (r *postgresAgreementRepository) AssetOwner(ctx context.Context, assetID int64) (string, error) {
+	var owner string
+	err := r.pool.QueryRow(ctx, `SELECT user_uuid FROM assets WHERE id = $1 AND is_deleted = false`, assetID).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrAssetNotFound
+	}
+	return owner, err
+}
+
+func (r *postgresAgreementRepository) Save(ctx context.Context, a Agreement) (Agreement, error) {
+	query := `INSERT INTO asset_agreements (asset_id, kind, title, body)
+			  VALUES ($1, $2, $3, $4)
+			  ON CONFLICT (asset_id) DO UPDATE SET
+				kind = EXCLUDED.kind,
+				title = EXCLUDED.title,
+				body = EXCLUDED.body,
+				version = CASE
+					WHEN (asset_agreements.kind, asset_agreements.title, asset_agreements.body) = (EXCLUDED.kind, EXCLUDED.title, EXCLUDED.body)
+					THEN asset_agreements.version
+					ELSE asset_agreements.version + 1
+				END,
+				updated_at = NOW()
+			  RETURNING version, updated_at`
+	err := r.pool.QueryRow(ctx, query, a.AssetID, a.Kind, a.Title, a.Body).Scan(&a.Version, &a.UpdatedAt)
+	return a, err
+}
+
+func (r *postgresAgreementRepository) Get(ctx context.Context, assetID int64) (Agreement, error) {
+	query := `SELECT asset_id, kind, title, body, version, updated_at FROM asset_agreements WHERE asset_id = $1`
+	var a Agreement
+	err := r.pool.QueryRow(ctx, query, assetID).Scan(&a.AssetID, &a.Kind, &a.Title, &a.Body, &a.Version, &a.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Agreement{}, ErrAgreementNotFound
+	}
+	return a, err
+}
+
+func (r *postgresAgreementRepository) Delete(ctx context.Context, assetID int64) error {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM asset_agreements WHERE asset_id = $1`, assetID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrAgreementNotFound
+	}
+	return nil
+}
+
+// Sign copies the text from the template in the same statement, so an edit racing
+// the signature cannot leave a copy that differs from what was signed.
+func (r *postgresAgreementRepository) Sign(ctx context.Context, sig Signature) (Signature, error) {
+	query := `INSERT INTO agreement_signatures
+				(asset_id, seller_uuid, signer_uuid, kind, title, body, version, signed_name, ip_address, user_agent)
+			  SELECT ag.asset_id, a.user_uuid, $2, ag.kind, ag.title, ag.body, ag.version, $4, $5, $6
+			  FROM asset_agreements ag
+			  JOIN assets a ON a.id = ag.asset_id
+			  WHERE ag.asset_id = $1 AND ag.version = $3
+			  ON CONFLICT (asset_id, signer_uuid, version) DO UPDATE SET signer_uuid = EXCLUDED.signer_uuid
+			  RETURNING ` + signatureColumns
+	out, err := scanSignature(r.pool.QueryRow(ctx, query, sig.AssetID, sig.SignerUUID, sig.Version, sig.SignedName, sig.IPAddress, sig.UserAgent))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Signature{}, ErrOutdated
+	}
+	return out, err
+}
+
+func (r *postgresAgreementRepository) Signed(ctx context.Context, assetID int64, userUUID string) (bool, bool, error) {
+	query := `SELECT EXISTS (
+				SELECT 1 FROM agreement_signatures s
+				WHERE s.asset_id = ag.asset_id AND s.signer_uuid = $2 AND s.version = ag.version
+			  )
+			  FROM asset_agreements ag WHERE ag.asset_id = $1`
+	var signed bool
+	err := r.pool.QueryRow(ctx, query, assetID, userUUID).Scan(&signed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+	return true, signed, nil
+}
+
+const signatureColumns = `id, asset_id, seller_uuid, signer_uuid, kind, title, body, version, signed_name, ip_address, user_agent, signed_at`
+
+func scanSignature(row pgx.Row) (Signature, error) {
+	var s Signature
+	err := row.Scan(&s.ID, &s.AssetID, &s.SellerUUID, &s.SignerUUID, &s.Kind, &s.Title, &s.Body, &s.Version, &s.SignedName, &s.IPAddress, &s.UserAgent, &s.SignedAt)
+	return s, err
+}
+
+func (r *postgresAgreementRepository) GetSignature(ctx context.Context, id int64) (Signature, error) {
+	s, err := scanSignature(r.pool.QueryRow(ctx, `SELECT `+signatureColumns+` FROM agreement_signatures WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Signature{}, ErrSignatureNotFound
+	}
+	return s, err
+}
+
+func (r *postgresAgreementRepository) ListSignatures(ctx context.Context, userUUID string, limit, offset int) ([]Signature, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM agreement_signatures WHERE signer_uuid = $1 OR seller_uuid = $1`
+	if err := r.pool.QueryRow(ctx, countQuery, userUUID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + signatureColumns + ` FROM agreement_signatures
+			  WHERE signer_uuid = $1 OR seller_uuid = $1
+			  ORDER BY signed_at DESC, id DESC
+			  LIMIT $2 OFFSET $3`
+	rows, err := r.pool.Query(ctx, query, userUUID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	list := []Signature{}
+	for rows.Next() {
+		s, err := scanSignature(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, s)
+	}
+	return list, total, rows.Err()
+}
@@ -0,0 +1,75 @@
+package agreements
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresAgreementRepository(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresAgreementRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	buyer := testhelpers.NewUser(t, pool)
+	asset := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID))
+
+	attached, _, err := repo.Signed(ctx, asset.ID, buyer.UUID)
+	require.NoError(t, err)
+	require.False(t, attached)
+
+	a, err := repo.Save(ctx, Agreement{AssetID: asset.ID, Kind: KindNDA, Title: "NDA", Body: "Keep it secret."})
+	require.NoError(t, err)
+	require.Equal(t, 1, a.Version)
+	// Saving the same text keeps the version
+	a, err = repo.Save(ctx, Agreement{AssetID: asset.ID, Kind: KindNDA, Title: "NDA", Body: "Keep it secret."})
+	require.NoError(t, err)
+	require.Equal(t, 1, a.Version)
+
+	sig, err := repo.Sign(ctx, Signature{AssetID: asset.ID, SignerUUID: buyer.UUID, Version: 1, SignedName: "Ana Buyer", IPAddress: "203.0.113.9"})
+	require.NoError(t, err)
+	require.Equal(t, seller.UUID, sig.SellerUUID)
+	require.Equal(t, "Keep it secret.", sig.Body)
+	again, err := repo.Sign(ctx, Signature{AssetID: asset.ID, SignerUUID: buyer.UUID, Version: 1, SignedName: "Someone Else", IPAddress: "198.51.100.1"})
+	require.NoError(t, err)
+	require.Equal(t, sig.ID, again.ID)
+	require.Equal(t, "Ana Buyer", again.SignedName)
+
+	attached, signed, err := repo.Signed(ctx, asset.ID, buyer.UUID)
+	require.NoError(t, err)
+	require.True(t, attached)
+	require.True(t, signed)
+
+	// Editing the text needs a new signature; the old copy keeps the old text
+	a, err = repo.Save(ctx, Agreement{AssetID: asset.ID, Kind: KindNDA, Title: "NDA", Body: "Keep it very secret."})
+	require.NoError(t, err)
+	require.Equal(t, 2, a.Version)
+	_, signed, err = repo.Signed(ctx, asset.ID, buyer.UUID)
+	require.NoError(t, err)
+	require.False(t, signed)
+	_, err = repo.Sign(ctx, Signature{AssetID: asset.ID, SignerUUID: buyer.UUID, Version: 1, SignedName: "Ana Buyer", IPAddress: "203.0.113.9"})
+	require.ErrorIs(t, err, ErrOutdated)
+
+	stored, err := repo.GetSignature(ctx, sig.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Keep it secret.", stored.Body)
+
+	for _, party := range []string{seller.UUID, buyer.UUID} {
+		list, total, err := repo.ListSignatures(ctx, party, 20, 0)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), total)
+		require.Equal(t, sig.ID, list[0].ID)
+	}
+
+	require.NoError(t, repo.Delete(ctx, asset.ID))
+	require.ErrorIs(t, repo.Delete(ctx, asset.ID), ErrAgreementNotFound)
+	_, err = repo.GetSignature(ctx, sig.ID)
+	require.NoError(t, err)
+}
@@ -0,0 +1,128 @@
+// Package agreements lets sellers attach an NDA or transfer agreement to a listing
+// that buyers sign, by typing their name, before they see the detailed deliverables
+// or complete the purchase. The signed text is copied into each signature so both
+// parties can retrieve exactly what was agreed.
+package agreements
+
+import (
+	"context"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/pagination"
+)
+
+var (
+	ErrAssetNotFound     = apperr.New(apperr.AssetNotFound, "asset not found")
+	ErrAgreementNotFound = apperr.New(apperr.AgreementNotFound, "no agreement attached to this asset")
+	ErrSignatureNotFound = apperr.New(apperr.SignatureNotFound, "signed agreement not found")
+	ErrNotOwner          = apperr.New(apperr.Forbidden, "only the asset owner can change its agreement")
+	ErrOwnAgreement      = apperr.New(apperr.Forbidden, "sellers do not sign their own agreement")
+	ErrOutdated          = apperr.New(apperr.AgreementOutdated, "the agreement has changed, review the current version before signing")
+	ErrSignatureRequired = apperr.New(apperr.AgreementRequired, "the agreement for this asset must be signed first")
+)
+
+const maxUserAgentLen = 512
+
+type Service struct {
+	repo AgreementRepository
+}
+
+func NewService(repo AgreementRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Attach sets the agreement buyers of assetID, which userUUID owns, have to sign.
+func (s *Service) Attach(ctx context.Context, assetID int64, userUUID string, req AttachRequest) (Agreement, error) {
+	if err := s.checkOwner(ctx, assetID, userUUID); err != nil {
+		return Agreement{}, err
+	}
+	return s.repo.Save(ctx, Agreement{AssetID: assetID, Kind: req.Kind, Title: req.Title, Body: req.Body})
+}
+
+func (s *Service) Get(ctx context.Context, assetID int64) (Agreement, error) {
+	return s.repo.Get(ctx, assetID)
+}
+
+// Remove detaches the agreement. Copies signed so far are kept.
+func (s *Service) Remove(ctx context.Context, assetID int64, userUUID string) error {
+	if err := s.checkOwner(ctx, assetID, userUUID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, assetID)
+}
+
+// Sign records the typed-name signature of the current agreement by the buyer,
+// signerUUID, with the IP address and user agent the request came from.
+func (s *Service) Sign(ctx context.Context, assetID int64, signerUUID string, req SignRequest, ipAddress, userAgent string) (Signature, error) {
+	owner, err := s.repo.AssetOwner(ctx, assetID)
+	if err != nil {
+		return Signature{}, err
+	}
+	if owner == signerUUID {
+		return Signature{}, ErrOwnAgreement
+	}
+	current, err := s.repo.Get(ctx, assetID)
+	if err != nil {
+		return Signature{}, err
+	}
+	if current.Version != req.Version {
+		return Signature{}, ErrOutdated
+	}
+	if len(userAgent) > maxUserAgentLen {
+		userAgent = userAgent[:maxUserAgentLen]
+	}
+	return s.repo.Sign(ctx, Signature{
+		AssetID:    assetID,
+		SignerUUID: signerUUID,
+		Version:    req.Version,
+		SignedName: req.SignedName,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+	})
+}
+
+// Require returns ErrSignatureRequired unless userUUID may proceed with assetID:
+// the asset has no agreement, userUUID owns it, or signed its current version.
+func (s *Service) Require(ctx context.Context, assetID int64, userUUID string) error {
+	attached, signed, err := s.repo.Signed(ctx, assetID, userUUID)
+	if err != nil || !attached || signed {
+		return err
+	}
+	owner, err := s.repo.AssetOwner(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if userUUID != "" && owner == userUUID {
+		return nil
+	}
+	return ErrSignatureRequired
+}
+
+// Signed returns a signed copy to either party; anyone else gets
+// ErrSignatureNotFound.
+func (s *Service) Signed(ctx context.Context, id int64, userUUID string) (Signature, error) {
+	sig, err := s.repo.GetSignature(ctx, id)
+	if err != nil {
+		return Signature{}, err
+	}
+	if userUUID != sig.SignerUUID && userUUID != sig.SellerUUID {
+		return Signature{}, ErrSignatureNotFound
+	}
+	return sig, nil
+}
+
+// ListSigned returns the copies userUUID signed or received as seller.
+func (s *Service) ListSigned(ctx context.Context, userUUID string, p pagination.Params) ([]Signature, int64, error) {
+	return s.repo.ListSignatures(ctx, userUUID, p.Limit, p.Offset())
+}
+
+func (s *Service) checkOwner(ctx context.Context, assetID int64, userUUID string) error {
+	owner, err := s.repo.AssetOwner(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if owner != userUUID {
+		return ErrNotOwner
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package agreements
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAgreementRepository struct {
+	mock.Mock
+}
+
+func (m *mockAgreementRepository) AssetOwner(ctx context.Context, assetID int64) (string, error) {
+	args := m.Called(ctx, assetID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockAgreementRepository) Save(ctx context.Context, a Agreement) (Agreement, error) {
+	args := m.Called(ctx, a)
+	out, _ := args.Get(0).(Agreement)
+	return out, args.Error(1)
+}
+
+func (m *mockAgreementRepository) Get(ctx context.Context, assetID int64) (Agreement, error) {
+	args := m.Called(ctx, assetID)
+	out, _ := args.Get(0).(Agreement)
+	return out, args.Error(1)
+}
+
+func (m *mockAgreementRepository) Delete(ctx context.Context, assetID int64) error {
+	return m.Called(ctx, assetID).Error(0)
+}
+
+func (m *mockAgreementRepository) Sign(ctx context.Context, sig Signature) (Signature, error) {
+	args := m.Called(ctx, sig)
+	out, _ := args.Get(0).(Signature)
+	return out, args.Error(1)
+}
+
+func (m *mockAgreementRepository) Signed(ctx context.Context, assetID int64, userUUID string) (bool, bool, error) {
+	args := m.Called(ctx, assetID, userUUID)
+	return args.Bool(0), args.Bool(1), args.Error(2)
+}
+
+func (m *mockAgreementRepository) GetSignature(ctx context.Context, id int64) (Signature, error) {
+	args := m.Called(ctx, id)
+	out, _ := args.Get(0).(Signature)
+	return out, args.Error(1)
+}
+
+func (m *mockAgreementRepository) ListSignatures(ctx context.Context, userUUID string, limit, offset int) ([]Signature, int64, error) {
+	args := m.Called(ctx, userUUID, limit, offset)
+	out, _ := args.Get(0).([]Signature)
+	return out, args.Get(1).(int64), args.Error(2)
+}
+
+func TestService_Attach_OwnerOnly(t *testing.T) {
+	repo := new(mockAgreementRepository)
+	service := NewService(repo)
+	repo.On("AssetOwner", mock.Anything, int64(4)).Return("seller", nil)
+
+	_, err := service.Attach(context.Background(), 4, "buyer", AttachRequest{Kind: KindNDA, Title: "NDA", Body: "..."})
+
+	require.ErrorIs(t, err, ErrNotOwner)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestService_Sign(t *testing.T) {
+	repo := new(mockAgreementRepository)
+	service := NewService(repo)
+	repo.On("AssetOwner", mock.Anything, int64(4)).Return("seller", nil)
+	repo.On("Get", mock.Anything, int64(4)).Return(Agreement{AssetID: 4, Kind: KindNDA, Version: 2}, nil)
+	want := Signature{AssetID: 4, SignerUUID: "buyer", Version: 2, SignedName: "Ana Buyer", IPAddress: "203.0.113.9", UserAgent: "curl/8"}
+	repo.On("Sign", mock.Anything, want).Return(want, nil)
+
+	sig, err := service.Sign(context.Background(), 4, "buyer", SignRequest{SignedName: "Ana Buyer", Version: 2}, "203.0.113.9", "curl/8")
+
+	require.NoError(t, err)
+	require.Equal(t, "Ana Buyer", sig.SignedName)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Sign_Rejected(t *testing.T) {
+	repo := new(mockAgreementRepository)
+	service := NewService(repo)
+	repo.On("AssetOwner", mock.Anything, int64(4)).Return("seller", nil)
+	repo.On("Get", mock.Anything, int64(4)).Return(Agreement{AssetID: 4, Version: 3}, nil)
+
+	_, err := service.Sign(context.Background(), 4, "buyer", SignRequest{SignedName: "Ana", Version: 2}, "", "")
+	require.ErrorIs(t, err, ErrOutdated)
+
+	_, err = service.Sign(context.Background(), 4, "seller", SignRequest{SignedName: "Sam", Version: 3}, "", "")
+	require.ErrorIs(t, err, ErrOwnAgreement)
+
+	repo.AssertNotCalled(t, "Sign", mock.Anything, mock.Anything)
+}
+
+func TestService_Require(t *testing.T) {
+	repo := new(mockAgreementRepository)
+	service := NewService(repo)
+	repo.On("AssetOwner", mock.Anything, int64(4)).Return("seller", nil)
+	repo.On("Signed", mock.Anything, int64(4), "signed").Return(true, true, nil)
+	repo.On("Signed", mock.Anything, int64(4), "unsigned").Return(true, false, nil)
+	repo.On("Signed", mock.Anything, int64(4), "").Return(true, false, nil)
+	repo.On("Signed", mock.Anything, int64(4), "seller").Return(true, false, nil)
+	repo.On("Signed", mock.Anything, int64(5), "unsigned").Return(false, false, nil)
+
+	ctx := context.Background()
+	require.NoError(t, service.Require(ctx, 4, "signed"))
+	require.NoError(t, service.Require(ctx, 4, "seller"))
+	require.NoError(t, service.Require(ctx, 5, "unsigned"))
+	require.ErrorIs(t, service.Require(ctx, 4, "unsigned"), ErrSignatureRequired)
+	require.ErrorIs(t, service.Require(ctx, 4, ""), ErrSignatureRequired)
+}
+
+func TestService_Signed_PartiesOnly(t *testing.T) {
+	repo := new(mockAgreementRepository)
+	service := NewService(repo)
+	repo.On("GetSignature", mock.Anything, int64(9)).Return(Signature{ID: 9, SellerUUID: "seller", SignerUUID: "buyer"}, nil)
+
+	for _, party := range []string{"seller", "buyer"} {
+		sig, err := service.Signed(context.Background(), 9, party)
+		require.NoError(t, err)
+		require.Equal(t, int64(9), sig.ID)
+	}
+	_, err := service.Signed(context.Background(), 9, "someone-else")
+	require.ErrorIs(t, err, ErrSignatureNotFound)
+}
@@ -0,0 +1,56 @@
+package analytics
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+type EventsHandler struct {
+	service *Service
+}
+
+func NewEventsHandler(service *Service) *EventsHandler {
+	return &EventsHandler{service: service}
+}
+
+func (h *EventsHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/events", h.ingest)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *EventsHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:  http.MethodPost,
+			Path:    "/events",
+			Tag:     "analytics",
+			Summary: "Record client analytics events",
+			Description: "Accepts up to 50 frontend events (" + strings.Join(Names(), ", ") + "). " +
+				"Each event's properties are checked against its schema; unknown properties are rejected, and one invalid event rejects the whole batch.",
+			Request:  Batch{},
+			Response: Accepted{},
+			Status:   http.StatusAccepted,
+			Errors:   []int{http.StatusBadRequest, http.StatusServiceUnavailable},
+		},
+	}
+}
+
+func (h *EventsHandler) ingest(c *gin.Context) {
+	var req Batch
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	accepted, err := h.service.Ingest(c.Request.Context(), response.Locale(c), req)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusAccepted, true, "events accepted", accepted)
+}
@@ -0,0 +1,156 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/response"
+)
+
+type fakeSink struct {
+	written []Event
+	err     error
+}
+
+func (s *fakeSink) Write(ctx context.Context, events []Event) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.written = append(s.written, events...)
+	return nil
+}
+
+var testNow = time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+
+func newRouter(sink Sink) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	service := NewService(sink)
+	service.now = func() time.Time { return testNow }
+	r := gin.New()
+	NewEventsHandler(service).RegisterRoutes(r)
+	return r
+}
+
+func post(r *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestEventsHandler_Ingest(t *testing.T) {
+	sink := &fakeSink{}
+
+	w := post(newRouter(sink), `{
+		"user_uuid": "11111111-1111-4111-8111-111111111111",
+		"session_id": "s-1",
+		"events": [
+			{"name": "listing_viewed", "properties": {"listing_type": "asset", "listing_id": 12}, "occurred_at": "2026-05-04T09:58:00Z"},
+			{"name": "search_performed", "properties": {"query": "crm", "results": 3}}
+		]
+	}`)
+
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), `"accepted":2`)
+	require.Len(t, sink.written, 2)
+	require.Equal(t, ListingViewed, sink.written[0].Name)
+	require.Equal(t, "s-1", sink.written[0].SessionID)
+	require.Equal(t, testNow.Add(-2*time.Minute), sink.written[0].OccurredAt)
+	require.Equal(t, testNow, sink.written[1].OccurredAt)
+	require.Equal(t, testNow, sink.written[1].ReceivedAt)
+}
+
+func TestEventsHandler_Ingest_RejectsWholeBatch(t *testing.T) {
+	sink := &fakeSink{}
+
+	w := post(newRouter(sink), `{"events": [
+		{"name": "chat_opened", "properties": {"peer_uuid": "11111111-1111-4111-8111-111111111111"}},
+		{"name": "listing_viewed", "properties": {"listing_type": "domain", "listing_id": 1.5, "referrer": "x"}},
+		{"name": "page_scrolled"}
+	]}`)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Empty(t, sink.written)
+
+	var res struct {
+		Data response.ValidationErrors `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	var fields []string
+	for _, fe := range res.Data.Errors {
+		fields = append(fields, fe.Field+":"+fe.Rule)
+	}
+	require.Equal(t, []string{
+		"events[1].properties.referrer:unknown",
+		"events[1].properties.listing_id:type",
+		"events[1].properties.listing_type:oneof",
+		"events[2].name:oneof",
+	}, fields)
+}
+
+func TestEventsHandler_Ingest_SinkFailure(t *testing.T) {
+	sink := &fakeSink{err: errors.New("connection refused")}
+
+	w := post(newRouter(sink), `{"events": [{"name": "search_performed", "properties": {"query": "crm"}}]}`)
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestValidate(t *testing.T) {
+	future := testNow.Add(2 * time.Hour)
+	tests := []struct {
+		name string
+		in   EventInput
+		want []string
+	}{
+		{"valid", EventInput{Name: ChatOpened, Properties: map[string]any{"peer_uuid": "11111111-1111-4111-8111-111111111111", "listing_id": float64(4)}}, nil},
+		{"missing required", EventInput{Name: SearchPerformed}, []string{"events[0].properties.query:required"}},
+		{"blank required", EventInput{Name: SearchPerformed, Properties: map[string]any{"query": "  "}}, []string{"events[0].properties.query:required"}},
+		{"bad uuid", EventInput{Name: ChatOpened, Properties: map[string]any{"peer_uuid": "bob"}}, []string{"events[0].properties.peer_uuid:uuid"}},
+		{"negative integer", EventInput{Name: SearchPerformed, Properties: map[string]any{"query": "a", "results": float64(-1)}}, []string{"events[0].properties.results:type"}},
+		{"too long", EventInput{Name: SearchPerformed, Properties: map[string]any{"query": strings.Repeat("a", 201)}}, []string{"events[0].properties.query:max"}},
+		{"future", EventInput{Name: SearchPerformed, Properties: map[string]any{"query": "a"}, OccurredAt: &future}, []string{"events[0].occurred_at:invalid"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, fe := range validate("en", 0, tt.in, testNow) {
+			got = append(got, fe.Field+":"+fe.Rule)
+		}
+		require.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var got map[string][]Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	events := []Event{{Name: SearchPerformed, Properties: map[string]any{"query": "crm"}, OccurredAt: testNow, ReceivedAt: testNow}}
+	require.NoError(t, NewWebhookSink(srv.URL, "secret").Write(context.Background(), events))
+	require.Equal(t, events, got["events"])
+}
+
+func TestWebhookSink_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	err := NewWebhookSink(srv.URL, "").Write(context.Background(), []Event{{Name: ChatOpened}})
+	require.ErrorContains(t, err, "429")
+	require.ErrorContains(t, err, "quota exceeded")
+}
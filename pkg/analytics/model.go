@@ -0,0 +1,46 @@
+package analytics
+
+import "time"
+
+// Name identifies a client event. Each name has a property schema in schema.go.
+type Name string
+
+const (
+	ListingViewed   Name = "listing_viewed"
+	SearchPerformed Name = "search_performed"
+	ChatOpened      Name = "chat_opened"
+)
+
+// MaxBatch is the most events accepted in one request.
+const MaxBatch = 50
+
+// Batch is the body of POST /events. Clients buffer events and send them together;
+// user and session apply to every event in the batch.
+type Batch struct {
+	UserUUID  string       `json:"user_uuid" binding:"omitempty,uuid"`
+	SessionID string       `json:"session_id" binding:"omitempty,max=64"`
+	Events    []EventInput `json:"events" binding:"required,min=1,max=50,dive"`
+}
+
+// EventInput is one event as sent by a client. OccurredAt defaults to the time the
+// batch is received.
+type EventInput struct {
+	Name       Name           `json:"name" binding:"required"`
+	Properties map[string]any `json:"properties"`
+	OccurredAt *time.Time     `json:"occurred_at"`
+}
+
+// Event is a validated event as written to the sink.
+type Event struct {
+	Name       Name           `json:"name"`
+	UserUUID   string         `json:"user_uuid,omitempty"`
+	SessionID  string         `json:"session_id,omitempty"`
+	Properties map[string]any `json:"properties"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	ReceivedAt time.Time      `json:"received_at"`
+}
+
+// Accepted is the response to POST /events.
+type Accepted struct {
+	Accepted int `json:"accepted"`
+}
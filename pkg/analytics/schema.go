@@ -0,0 +1,134 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"grveyard/pkg/i18n"
+	"grveyard/pkg/response"
+)
+
+type kind int
+
+const (
+	kindString kind = iota
+	kindInteger
+	kindUUID
+)
+
+// property describes one allowed property of an event.
+type property struct {
+	kind     kind
+	required bool
+	max      int      // longest string accepted; 0 for no limit
+	oneof    []string // allowed string values; empty for any
+}
+
+// schemas lists every accepted event and its properties. Properties not listed are
+// rejected, so adding one here is how the schema is extended.
+var schemas = map[Name]map[string]property{
+	ListingViewed: {
+		"listing_type": {kind: kindString, required: true, oneof: []string{"asset", "startup"}},
+		"listing_id":   {kind: kindInteger, required: true},
+		"source":       {kind: kindString, max: 32},
+	},
+	SearchPerformed: {
+		"query":   {kind: kindString, required: true, max: 200},
+		"scope":   {kind: kindString, oneof: []string{"assets", "startups"}},
+		"results": {kind: kindInteger},
+	},
+	ChatOpened: {
+		"peer_uuid":  {kind: kindUUID, required: true},
+		"listing_id": {kind: kindInteger},
+	},
+}
+
+// Names returns the accepted event names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(schemas))
+	for n := range schemas {
+		names = append(names, string(n))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// maxClockSkew is how far in the future a client's occurred_at may be.
+const maxClockSkew = time.Hour
+
+// validate checks the i-th event of a batch against its schema, returning field
+// errors in locale. JSON numbers arrive as float64, so integers are checked by value.
+func validate(locale string, i int, in EventInput, now time.Time) []response.FieldError {
+	prefix := "events[" + strconv.Itoa(i) + "]"
+	var errs []response.FieldError
+	fail := func(field, rule, key string, args ...any) {
+		errs = append(errs, response.FieldError{Field: field, Rule: rule, Message: i18n.T(locale, key, append([]any{field}, args...)...)})
+	}
+
+	schema, ok := schemas[in.Name]
+	if !ok {
+		fail(prefix+".name", "oneof", "validation.oneof", strings.Join(Names(), ", "))
+		return errs
+	}
+	if in.OccurredAt != nil && in.OccurredAt.After(now.Add(maxClockSkew)) {
+		fail(prefix+".occurred_at", "invalid", "validation.invalid")
+	}
+
+	keys := make([]string, 0, len(in.Properties))
+	for k := range in.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, ok := schema[k]; !ok {
+			fail(prefix+".properties."+k, "unknown", "validation.unknown")
+		}
+	}
+
+	names := make([]string, 0, len(schema))
+	for k := range schema {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		p := schema[k]
+		field := prefix + ".properties." + k
+		v, present := in.Properties[k]
+		if !present || v == nil {
+			if p.required {
+				fail(field, "required", "validation.required")
+			}
+			continue
+		}
+
+		switch p.kind {
+		case kindInteger:
+			n, ok := v.(float64)
+			if !ok || n != math.Trunc(n) || n < 0 || n > math.MaxInt64 {
+				fail(field, "type", "validation.type.number")
+			}
+		case kindString, kindUUID:
+			s, ok := v.(string)
+			switch {
+			case !ok:
+				fail(field, "type", "validation.type.string")
+			case p.kind == kindUUID && uuid.Validate(s) != nil:
+				fail(field, "uuid", "validation.uuid")
+			case len(p.oneof) > 0 && !slices.Contains(p.oneof, s):
+				fail(field, "oneof", "validation.oneof", strings.Join(p.oneof, ", "))
+			case p.max > 0 && len(s) > p.max:
+				fail(field, "max", "validation.max_len", fmt.Sprint(p.max))
+			case p.required && strings.TrimSpace(s) == "":
+				fail(field, "required", "validation.required")
+			}
+		}
+	}
+	return errs
+}
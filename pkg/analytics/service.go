@@ -0,0 +1,62 @@
+package analytics
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/i18n"
+	"grveyard/pkg/requestid"
+	"grveyard/pkg/response"
+)
+
+type Service struct {
+	sink Sink
+	now  func() time.Time
+}
+
+func NewService(sink Sink) *Service {
+	return &Service{sink: sink, now: time.Now}
+}
+
+// Ingest validates every event in the batch and writes them all to the sink, or
+// none: a single invalid event rejects the batch with an error per field.
+func (s *Service) Ingest(ctx context.Context, locale string, batch Batch) (Accepted, error) {
+	now := s.now().UTC()
+
+	var errs []response.FieldError
+	for i, in := range batch.Events {
+		errs = append(errs, validate(locale, i, in, now)...)
+	}
+	if len(errs) > 0 {
+		return Accepted{}, apperr.New(apperr.ValidationFailed, i18n.T(locale, "validation.failed")).
+			WithDetails(response.ValidationErrors{Errors: errs})
+	}
+
+	events := make([]Event, len(batch.Events))
+	for i, in := range batch.Events {
+		occurred := now
+		if in.OccurredAt != nil {
+			occurred = in.OccurredAt.UTC()
+		}
+		props := in.Properties
+		if props == nil {
+			props = map[string]any{}
+		}
+		events[i] = Event{
+			Name:       in.Name,
+			UserUUID:   batch.UserUUID,
+			SessionID:  batch.SessionID,
+			Properties: props,
+			OccurredAt: occurred,
+			ReceivedAt: now,
+		}
+	}
+
+	if err := s.sink.Write(ctx, events); err != nil {
+		log.Printf("[%s] write %d analytics events failed: %v", requestid.FromContext(ctx), len(events), err)
+		return Accepted{}, apperr.Wrap(apperr.ServiceUnavailable, "events could not be stored", err)
+	}
+	return Accepted{Accepted: len(events)}, nil
+}
@@ -0,0 +1,96 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/config"
+)
+
+// Sink stores or forwards a batch of validated events.
+type Sink interface {
+	Write(ctx context.Context, events []Event) error
+}
+
+// NewSink returns the sink cfg selects.
+func NewSink(cfg config.AnalyticsConfig, pool *pgxpool.Pool) Sink {
+	if cfg.Sink == "webhook" {
+		return NewWebhookSink(cfg.WebhookURL, cfg.WebhookToken)
+	}
+	return NewPostgresSink(pool)
+}
+
+type postgresSink struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresSink appends events to the events table.
+func NewPostgresSink(pool *pgxpool.Pool) Sink {
+	return &postgresSink{pool: pool}
+}
+
+func (s *postgresSink) Write(ctx context.Context, events []Event) error {
+	columns := []string{"name", "user_uuid", "session_id", "properties", "occurred_at", "received_at"}
+	_, err := s.pool.CopyFrom(ctx, pgx.Identifier{"events"}, columns, pgx.CopyFromSlice(len(events), func(i int) ([]any, error) {
+		e := events[i]
+		props, err := json.Marshal(e.Properties)
+		if err != nil {
+			return nil, err
+		}
+		return []any{string(e.Name), nullable(e.UserUUID), nullable(e.SessionID), props, e.OccurredAt, e.ReceivedAt}, nil
+	}))
+	return err
+}
+
+func nullable(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+type webhookSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewWebhookSink POSTs each batch as {"events": [...]} to url, with token as a
+// bearer token when set. Any non-2xx answer is an error.
+func NewWebhookSink(url, token string) Sink {
+	return &webhookSink{url: url, token: token, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (s *webhookSink) Write(ctx context.Context, events []Event) error {
+	body, err := json.Marshal(map[string]any{"events": events})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("analytics webhook: %s: %s", res.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Package apperr defines typed application errors with stable, machine-readable codes.
+// Handlers turn them into responses with response.SendError; clients should switch on
+// the code rather than the message, which may be reworded or translated.
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error is an error a client can act on. Message is shown to the client; Err, the
+// underlying cause, is not.
+type Error struct {
+	Code    Code
+	Message string
+	Details any // extra response data, e.g. per-field validation errors
+	Err     error
+}
+
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func Newf(code Code, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap returns an Error with the given code and message whose cause is err.
+func Wrap(code Code, message string, err error) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches any *Error with the same code, so a freshly built error satisfies
+// errors.Is against a package's sentinel.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetails returns a copy of e carrying details in the response data.
+func (e *Error) WithDetails(details any) *Error {
+	cp := *e
+	cp.Details = details
+	return &cp
+}
+
+// Status is the HTTP status registered for the error's code.
+func (e *Error) Status() int {
+	if def, ok := byCode[e.Code]; ok {
+		return def.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// Default returns err unchanged if its chain already holds an *Error, and otherwise
+// wraps it with code, keeping err's text as the message. Handlers use it for service
+// errors that are known to be the caller's fault but aren't typed yet.
+func Default(err error, code Code) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return Wrap(code, err.Error(), err)
+}
+
+// CodeOf returns the code of the first *Error in err's chain, or Internal.
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return Internal
+}
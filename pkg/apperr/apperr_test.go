@@ -0,0 +1,45 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefinitions_UniqueAndComplete(t *testing.T) {
+	seen := map[Code]bool{}
+	for _, d := range Definitions() {
+		require.False(t, seen[d.Code], "duplicate code %s", d.Code)
+		seen[d.Code] = true
+		require.NotZero(t, d.Status, d.Code)
+		require.NotEmpty(t, d.Description, d.Code)
+	}
+}
+
+func TestError_IsMatchesByCode(t *testing.T) {
+	sentinel := New(AssetNotFound, "asset not found")
+	err := fmt.Errorf("load: %w", New(AssetNotFound, "no such asset"))
+
+	require.ErrorIs(t, err, sentinel)
+	require.NotErrorIs(t, err, New(StartupNotFound, "startup not found"))
+	require.Equal(t, AssetNotFound, CodeOf(err))
+	require.Equal(t, Internal, CodeOf(errors.New("boom")))
+}
+
+func TestError_Status(t *testing.T) {
+	require.Equal(t, http.StatusNotFound, New(AssetNotFound, "").Status())
+	require.Equal(t, http.StatusConflict, New(AlreadySold, "").Status())
+	require.Equal(t, http.StatusInternalServerError, New(Code("UNREGISTERED"), "").Status())
+}
+
+func TestDefault(t *testing.T) {
+	typed := New(InvalidRole, "invalid role")
+	require.Same(t, typed, Default(typed, InvalidRequest))
+
+	err := Default(errors.New("name is required"), InvalidRequest)
+	require.Equal(t, InvalidRequest, CodeOf(err))
+	require.EqualError(t, err, "name is required")
+}
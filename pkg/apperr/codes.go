@@ -0,0 +1,223 @@
+package apperr
+
+import "net/http"
+
+// Code identifies an error condition. Codes are part of the API contract: add new ones
+// freely, but never rename or repurpose an existing code.
+type Code string
+
+// Definition documents a code; the registry below is published in the OpenAPI spec.
+type Definition struct {
+	Code        Code
+	Status      int
+	Description string
+}
+
+// General
+const (
+	InvalidRequest       Code = "INVALID_REQUEST"
+	ValidationFailed     Code = "VALIDATION_FAILED"
+	InvalidJSON          Code = "INVALID_JSON"
+	EmptyBody            Code = "EMPTY_BODY"
+	InvalidID            Code = "INVALID_ID"
+	InvalidCursor        Code = "INVALID_CURSOR"
+	PayloadTooLarge      Code = "PAYLOAD_TOO_LARGE"
+	UnsupportedMediaType Code = "UNSUPPORTED_MEDIA_TYPE"
+	Unauthorized         Code = "UNAUTHORIZED"
+	Forbidden            Code = "FORBIDDEN"
+	RateLimited          Code = "RATE_LIMITED"
+	ServiceUnavailable   Code = "SERVICE_UNAVAILABLE"
+	Internal             Code = "INTERNAL_ERROR"
+)
+
+// Domain
+const (
+	UserNotFound          Code = "USER_NOT_FOUND"
+	UserExists            Code = "USER_EXISTS"
+	InvalidRole           Code = "INVALID_ROLE"
+	UnsupportedLocale     Code = "UNSUPPORTED_LOCALE"
+	InvalidCredentials    Code = "INVALID_CREDENTIALS"
+	VerificationRequired  Code = "VERIFICATION_REQUIRED"
+	AssetNotFound         Code = "ASSET_NOT_FOUND"
+	InvalidAssetType      Code = "INVALID_ASSET_TYPE"
+	InvalidPrice          Code = "INVALID_PRICE"
+	StartupNotFound       Code = "STARTUP_NOT_FOUND"
+	InvalidStartupStatus  Code = "INVALID_STARTUP_STATUS"
+	AlreadySold           Code = "ALREADY_SOLD"
+	OTPRateLimited        Code = "OTP_RATE_LIMITED"
+	OTPNotFound           Code = "OTP_NOT_FOUND"
+	OTPExpired            Code = "OTP_EXPIRED"
+	OTPInvalid            Code = "OTP_INVALID"
+	InvalidUnsubscribe    Code = "INVALID_UNSUBSCRIBE_TOKEN"
+	InvalidSignature      Code = "INVALID_SIGNATURE"
+	InvalidDateRange      Code = "INVALID_DATE_RANGE"
+	IdempotencyInProgress Code = "IDEMPOTENCY_IN_PROGRESS"
+	IdempotencyKeyReused  Code = "IDEMPOTENCY_KEY_REUSED"
+	ImageNotFound         Code = "IMAGE_NOT_FOUND"
+	ImageAlreadySubmitted Code = "IMAGE_ALREADY_SUBMITTED"
+	FileNotFound          Code = "FILE_NOT_FOUND"
+	LinkExpired           Code = "LINK_EXPIRED"
+	NotificationNotFound  Code = "NOTIFICATION_NOT_FOUND"
+	ReportNotFound        Code = "REPORT_NOT_FOUND"
+	ReportTargetNotFound  Code = "REPORT_TARGET_NOT_FOUND"
+	ReportRateLimited     Code = "REPORT_RATE_LIMITED"
+	InvalidReportStatus   Code = "INVALID_REPORT_STATUS"
+	BookmarkNotFound      Code = "BOOKMARK_NOT_FOUND"
+	FavoriteNotFound      Code = "FAVORITE_NOT_FOUND"
+	InvoiceNotFound       Code = "INVOICE_NOT_FOUND"
+	InvalidCountry        Code = "INVALID_COUNTRY"
+	InvalidRegion         Code = "INVALID_REGION"
+	UnknownCategory       Code = "UNKNOWN_CATEGORY"
+	UnsupportedCurrency   Code = "UNSUPPORTED_CURRENCY"
+	ShareNotFound         Code = "SHARE_NOT_FOUND"
+	InvalidPreviewURL     Code = "INVALID_PREVIEW_URL"
+	PreviewUnavailable    Code = "PREVIEW_UNAVAILABLE"
+	RepoNotLinked         Code = "REPO_NOT_LINKED"
+	RepoInaccessible      Code = "REPO_INACCESSIBLE"
+	DataPreviewNotFound   Code = "DATA_PREVIEW_NOT_FOUND"
+	InvalidDataSample     Code = "INVALID_DATA_SAMPLE"
+	DocumentNotFound      Code = "DOCUMENT_NOT_FOUND"
+	InvalidDocument       Code = "INVALID_DOCUMENT"
+	AgreementNotFound     Code = "AGREEMENT_NOT_FOUND"
+	AgreementOutdated     Code = "AGREEMENT_OUTDATED"
+	AgreementRequired     Code = "AGREEMENT_REQUIRED"
+	SignatureNotFound     Code = "SIGNATURE_NOT_FOUND"
+	OfferNotFound         Code = "OFFER_NOT_FOUND"
+	OfferAlreadyDecided   Code = "OFFER_ALREADY_DECIDED"
+	InvalidAPIKey         Code = "INVALID_API_KEY"
+	QuotaExceeded         Code = "QUOTA_EXCEEDED"
+	InvalidToken          Code = "INVALID_TOKEN"
+	InvalidRefreshToken   Code = "INVALID_REFRESH_TOKEN"
+	InvalidResetToken     Code = "INVALID_RESET_TOKEN"
+	InvalidIDToken        Code = "INVALID_ID_TOKEN"
+	InvalidOAuthCode      Code = "INVALID_OAUTH_CODE"
+	IdentityTaken         Code = "IDENTITY_TAKEN"
+	IdentityNotFound      Code = "IDENTITY_NOT_FOUND"
+	TOTPRequired          Code = "TOTP_REQUIRED"
+	InvalidTOTPCode       Code = "INVALID_TOTP_CODE"
+	TOTPNotEnrolled       Code = "TOTP_NOT_ENROLLED"
+	TOTPAlreadyEnabled    Code = "TOTP_ALREADY_ENABLED"
+	TOTPLocked            Code = "TOTP_LOCKED"
+	SessionNotFound       Code = "SESSION_NOT_FOUND"
+	CannotBlockSelf       Code = "CANNOT_BLOCK_SELF"
+	UserBlocked           Code = "USER_BLOCKED"
+	InvalidHandle         Code = "INVALID_HANDLE"
+	HandleTaken           Code = "HANDLE_TAKEN"
+	AccountBanned         Code = "ACCOUNT_BANNED"
+	VerificationPending   Code = "VERIFICATION_PENDING"
+	VerificationNotFound  Code = "VERIFICATION_NOT_FOUND"
+	AlreadyVerified       Code = "ALREADY_VERIFIED"
+	MemberNotFound        Code = "MEMBER_NOT_FOUND"
+)
+
+var definitions = []Definition{
+	{InvalidRequest, http.StatusBadRequest, "A parameter is missing or malformed; see the message"},
+	{ValidationFailed, http.StatusBadRequest, "The body failed validation; data.errors lists each invalid field"},
+	{InvalidJSON, http.StatusBadRequest, "The body is not valid JSON"},
+	{EmptyBody, http.StatusBadRequest, "The request needs a JSON body"},
+	{InvalidID, http.StatusBadRequest, "A path ID is not a valid identifier"},
+	{InvalidCursor, http.StatusBadRequest, "The pagination cursor is malformed or expired"},
+	{PayloadTooLarge, http.StatusRequestEntityTooLarge, "The body exceeds the size limit"},
+	{UnsupportedMediaType, http.StatusUnsupportedMediaType, "The body must be application/json"},
+	{Unauthorized, http.StatusUnauthorized, "Missing or invalid credentials"},
+	{Forbidden, http.StatusForbidden, "The caller may not access this resource"},
+	{RateLimited, http.StatusTooManyRequests, "Too many requests; retry later"},
+	{ServiceUnavailable, http.StatusServiceUnavailable, "A dependency is unavailable or disabled"},
+	{Internal, http.StatusInternalServerError, "Unexpected server error; quote request_id when reporting it"},
+
+	{UserNotFound, http.StatusNotFound, "No user with that UUID or email"},
+	{UserExists, http.StatusConflict, "A user with that email already exists"},
+	{InvalidRole, http.StatusBadRequest, "role must be buyer or founder"},
+	{UnsupportedLocale, http.StatusBadRequest, "locale is not one of the supported languages"},
+	{InvalidCredentials, http.StatusUnauthorized, "Email or password is wrong"},
+	{VerificationRequired, http.StatusForbidden, "The user must verify their email with an OTP (POST /getOTP) before listing"},
+	{AssetNotFound, http.StatusNotFound, "No asset with that ID"},
+	{InvalidAssetType, http.StatusBadRequest, "asset_type is not a known asset type"},
+	{InvalidPrice, http.StatusBadRequest, "price must not be negative"},
+	{StartupNotFound, http.StatusNotFound, "No startup with that ID"},
+	{InvalidStartupStatus, http.StatusBadRequest, "status is not a known startup status"},
+	{AlreadySold, http.StatusConflict, "The asset or startup is already marked as sold"},
+	{OTPRateLimited, http.StatusTooManyRequests, "Too many OTPs requested for this email recently"},
+	{OTPNotFound, http.StatusUnauthorized, "No pending OTP for this email"},
+	{OTPExpired, http.StatusUnauthorized, "The OTP has expired; request a new one"},
+	{OTPInvalid, http.StatusUnauthorized, "The OTP code is wrong"},
+	{InvalidUnsubscribe, http.StatusBadRequest, "The unsubscribe token is malformed or its signature does not match"},
+	{InvalidSignature, http.StatusUnauthorized, "The webhook signature does not verify"},
+	{InvalidDateRange, http.StatusBadRequest, "Dates must be YYYY-MM-DD, from not after to, at most 366 days apart"},
+	{IdempotencyInProgress, http.StatusConflict, "A request with this Idempotency-Key is still being processed"},
+	{IdempotencyKeyReused, http.StatusUnprocessableEntity, "The Idempotency-Key was used with a different request body"},
+	{ImageNotFound, http.StatusNotFound, "No image with that ID"},
+	{ImageAlreadySubmitted, http.StatusConflict, "The image was already submitted for processing"},
+	{FileNotFound, http.StatusNotFound, "No file stored under that key"},
+	{LinkExpired, http.StatusForbidden, "The presigned link is invalid or has expired"},
+	{NotificationNotFound, http.StatusNotFound, "No notification with that ID"},
+	{ReportNotFound, http.StatusNotFound, "No report with that ID"},
+	{ReportTargetNotFound, http.StatusNotFound, "The reported user, startup, asset or message does not exist"},
+	{ReportRateLimited, http.StatusTooManyRequests, "Too many reports filed by this user recently"},
+	{InvalidReportStatus, http.StatusConflict, "The report cannot move from its current status to the requested one"},
+	{BookmarkNotFound, http.StatusNotFound, "The user has not bookmarked that startup"},
+	{FavoriteNotFound, http.StatusNotFound, "The user has not favorited that asset"},
+	{InvoiceNotFound, http.StatusNotFound, "No recorded sale with that transaction ID"},
+	{InvalidCountry, http.StatusBadRequest, "country is not an ISO 3166-1 alpha-2 code"},
+	{InvalidRegion, http.StatusBadRequest, "region is not an ISO 3166-2 code in the given country"},
+	{UnknownCategory, http.StatusBadRequest, "A category is not one of those listed by GET /categories"},
+	{UnsupportedCurrency, http.StatusBadRequest, "display_currency is not a currency the exchange rate provider quotes"},
+	{ShareNotFound, http.StatusNotFound, "No published list with that share token, or it was revoked"},
+	{InvalidPreviewURL, http.StatusBadRequest, "The URL is not http(s) on the default port, or resolves to a private or reserved address"},
+	{PreviewUnavailable, http.StatusBadGateway, "The page could not be fetched or is not HTML"},
+	{RepoNotLinked, http.StatusNotFound, "No GitHub repository is linked to that asset"},
+	{RepoInaccessible, http.StatusBadRequest, "The GitHub App installation cannot read that repository"},
+	{DataPreviewNotFound, http.StatusNotFound, "The seller has not published a data preview for that asset"},
+	{InvalidDataSample, http.StatusBadRequest, "The sample is missing, too large, or not a CSV file with a header row"},
+	{DocumentNotFound, http.StatusNotFound, "No document with that ID on the startup"},
+	{InvalidDocument, http.StatusBadRequest, "The document was not uploaded, or is larger than 50 MiB"},
+	{AgreementNotFound, http.StatusNotFound, "The asset has no agreement attached"},
+	{AgreementOutdated, http.StatusConflict, "The agreement was edited after the version being signed; fetch it again"},
+	{AgreementRequired, http.StatusForbidden, "The buyer must sign the asset's current agreement first"},
+	{SignatureNotFound, http.StatusNotFound, "No signed agreement with that ID that the user is a party to"},
+	{OfferNotFound, http.StatusNotFound, "No offer with that ID"},
+	{OfferAlreadyDecided, http.StatusConflict, "The offer was already accepted or rejected"},
+	{InvalidAPIKey, http.StatusUnauthorized, "The X-API-Key header does not match an active API key"},
+	{QuotaExceeded, http.StatusTooManyRequests, "The daily or monthly request quota for the caller's tier is used up; see X-RateLimit-Reset"},
+	{InvalidToken, http.StatusUnauthorized, "The bearer token is malformed, forged or expired; log in again"},
+	{InvalidRefreshToken, http.StatusUnauthorized, "The refresh token is unknown, expired, revoked or was already used; log in again"},
+	{InvalidResetToken, http.StatusBadRequest, "The password reset token is unknown, expired or was already used; request a new one"},
+	{InvalidIDToken, http.StatusUnauthorized, "The identity provider's ID token is invalid, expired, issued to another app, or for an unverified email"},
+	{InvalidOAuthCode, http.StatusBadRequest, "The OAuth authorization code is invalid, expired or was already redeemed; start the flow again"},
+	{IdentityTaken, http.StatusConflict, "The external account is already linked to another user"},
+	{IdentityNotFound, http.StatusNotFound, "The user has no linked account from that provider"},
+	{TOTPRequired, http.StatusUnauthorized, "The account has two-factor authentication on; repeat the login with totp_code"},
+	{InvalidTOTPCode, http.StatusUnauthorized, "The authenticator code is wrong, expired or was already used"},
+	{TOTPNotEnrolled, http.StatusNotFound, "Two-factor authentication has not been set up for this user"},
+	{TOTPAlreadyEnabled, http.StatusConflict, "Two-factor authentication is already on; disable it before enrolling again"},
+	{TOTPLocked, http.StatusTooManyRequests, "Too many wrong authenticator codes; wait 15 minutes"},
+	{SessionNotFound, http.StatusNotFound, "The user has no active session with that ID"},
+	{CannotBlockSelf, http.StatusBadRequest, "Users cannot block themselves"},
+	{UserBlocked, http.StatusForbidden, "One of the two users has blocked the other; chat messages are not delivered"},
+	{InvalidHandle, http.StatusBadRequest, "Handles are 3-30 lower-case letters, digits or underscores, starting with a letter"},
+	{HandleTaken, http.StatusConflict, "Another user already has that handle"},
+	{AccountBanned, http.StatusForbidden, "An admin suspended the account; it cannot log in"},
+	{VerificationPending, http.StatusConflict, "The startup already has a verification request waiting for review"},
+	{VerificationNotFound, http.StatusNotFound, "The startup has no verification request to show or review"},
+	{AlreadyVerified, http.StatusConflict, "The startup is already verified"},
+	{MemberNotFound, http.StatusNotFound, "The user is not a member of the startup's team"},
+}
+
+var byCode = func() map[Code]Definition {
+	m := make(map[Code]Definition, len(definitions))
+	for _, d := range definitions {
+		m[d.Code] = d
+	}
+	return m
+}()
+
+// Definitions returns every registered code, in documentation order.
+func Definitions() []Definition {
+	return append([]Definition(nil), definitions...)
+}
+
+// Lookup returns the registered definition for code.
+func Lookup(code Code) (Definition, bool) {
+	d, ok := byCode[code]
+	return d, ok
+}
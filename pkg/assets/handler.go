@@ -3,20 +3,36 @@ package assets
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"grveyard/pkg/admin"
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/currency"
+	"grveyard/pkg/etag"
+	"grveyard/pkg/geo"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
 	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
 )
 
 type AssetHandler struct {
 	service AssetService
+	rates   *currency.Service // optional
 }
 
 func NewAssetHandler(service AssetService) *AssetHandler {
 	return &AssetHandler{service: service}
 }
 
+// SetRates enables the display_currency parameter on the list endpoints.
+func (h *AssetHandler) SetRates(rates *currency.Service) {
+	h.rates = rates
+}
+
 func isValidAssetType(assetType string) bool {
 	switch assetType {
 	case "research", "codebase", "domain", "product", "data", "other":
@@ -27,66 +43,192 @@ func isValidAssetType(assetType string) bool {
 }
 
 func (h *AssetHandler) RegisterRoutes(router *gin.Engine) {
-	router.POST("/assets", h.createAsset)
-	router.PUT("/assets/:id", h.updateAsset)
-	router.DELETE("/assets/:id", h.deleteAsset)
-	router.DELETE("/assets", h.deleteAllAssets)
-	router.GET("/assets", h.listAssets)
-	router.GET("/assets/:id", h.getAssetByID)
-	router.GET("/users/:uuid/assets", h.listAssetsByUser)
-	router.DELETE("/users/:uuid/assets/delete-all", h.deleteAllAssetsByUserUUID)
+	router.POST("/assets", auth.Required(), h.createAsset)
+	router.PUT("/assets/:id", auth.Required(), h.updateAsset)
+	router.DELETE("/assets/:id", auth.Required(), h.deleteAsset)
+	router.GET("/assets", etag.Middleware(), h.listAssets)
+	router.GET("/assets/search", h.searchAssets)
+	router.GET("/assets/compare", h.compareAssets)
+	router.GET("/assets/:id", etag.Middleware(), h.getAssetByID)
+	router.GET("/users/:uuid/assets", etag.Middleware(), h.listAssetsByUser)
+	router.DELETE("/users/:uuid/assets/delete-all", auth.RequireSelf("uuid"), h.deleteAllAssetsByUserUUID)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *AssetHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/assets",
+			Tag:         "assets",
+			Summary:     "Create a new asset",
+			Description: "Creates a new asset for sale under a startup. The seller must have verified their email within the last 30 days (VERIFICATION_REQUIRED otherwise)",
+			Request:     createAssetRequest{},
+			Response:    Asset{},
+			Status:      http.StatusCreated,
+			Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/assets/:id",
+			Tag:         "assets",
+			Summary:     "Update an asset",
+			Description: "Updates an existing asset's details. Only its owner may update it.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Request:  updateAssetRequest{},
+			Response: Asset{},
+			Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/assets/:id",
+			Tag:         "assets",
+			Summary:     "Delete an asset",
+			Description: "Deletes an asset by ID. Owners may delete their own assets and admins any.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/assets/:id",
+			Tag:         "assets",
+			Summary:     "Get asset by ID",
+			Description: "Retrieves a single asset by its ID",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Response: Asset{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/assets",
+			Tag:         "assets",
+			Summary:     "List all assets",
+			Description: "Retrieves a paginated list of active assets with optional filters. Signed-in callers do not see assets of sellers who blocked them",
+			Params: []openapi.Param{
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 10)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+				openapi.Query("display_currency", "string", "ISO 4217 code; adds display_price converted at the day's exchange rate", false),
+				openapi.Query("user_uuid", "string", "Filter by user UUID", false),
+				{Name: "asset_type", In: "query", Type: "string", Description: "Filter by asset type", Enum: []string{"research", "codebase", "domain", "product", "data", "other"}},
+				openapi.Query("is_sold", "boolean", "Filter by sold status", false),
+				openapi.Query("country", "string", "Filter by ISO 3166-1 alpha-2 country, e.g. IN", false),
+				openapi.Query("region", "string", "Filter by ISO 3166-2 region, e.g. IN-MH", false),
+			},
+			Response: response.Paginated[Asset]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/assets/search",
+			Tag:         "assets",
+			Summary:     "Search assets",
+			Description: "Full-text search over active assets' titles and descriptions",
+			Params: []openapi.Param{
+				openapi.Query("q", "string", "Search terms", true),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 10)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+				openapi.Query("display_currency", "string", "ISO 4217 code; adds display_price converted at the day's exchange rate", false),
+			},
+			Response: response.Paginated[Asset]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/assets/compare",
+			Tag:         "assets",
+			Summary:     "Compare assets side by side",
+			Description: "Type, price, negotiability, views over the last 30 days and the seller's track record for 2 to 5 assets, in the order requested. Sold and unlisted assets are included; 404 if any id is unknown.",
+			Params: []openapi.Param{
+				openapi.Query("ids", "string", "Comma-separated asset IDs", true),
+			},
+			Response: []Comparison{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/assets",
+			Tag:         "assets",
+			Summary:     "List assets by user",
+			Description: "Retrieves a paginated list of active assets for a specific user",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 10)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+				openapi.Query("display_currency", "string", "ISO 4217 code; adds display_price converted at the day's exchange rate", false),
+			},
+			Response: response.Paginated[Asset]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/users/:uuid/assets/delete-all",
+			Tag:         "assets",
+			Summary:     "Delete all assets by user UUID",
+			Description: "Soft deletes all assets for a specific user by setting is_deleted to true",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:   true,
+		},
+	}
 }
 
 type createAssetRequest struct {
-	UserUUID     string  `json:"user_uuid" binding:"required"`
-	Title        string  `json:"title" binding:"required"`
-	Description  string  `json:"description"`
+	UserUUID     string  `json:"user_uuid" binding:"required,max=64"`
+	Title        string  `json:"title" binding:"required,max=200"`
+	Description  string  `json:"description" binding:"max=5000"`
 	AssetType    string  `json:"asset_type" binding:"required"`
-	ImageURL     string  `json:"image_url"`
+	ImageURL     string  `json:"image_url" binding:"max=2048"`
 	Price        float64 `json:"price"`
 	IsNegotiable bool    `json:"is_negotiable"`
 	IsSold       bool    `json:"is_sold"`
+	Country      string  `json:"country" binding:"max=2"`
+	Region       string  `json:"region" binding:"max=6"`
 }
 
 type updateAssetRequest struct {
-	Title        string  `json:"title" binding:"required"`
-	Description  string  `json:"description"`
+	Title        string  `json:"title" binding:"required,max=200"`
+	Description  string  `json:"description" binding:"max=5000"`
 	AssetType    string  `json:"asset_type" binding:"required"`
-	ImageURL     string  `json:"image_url"`
+	ImageURL     string  `json:"image_url" binding:"max=2048"`
 	Price        float64 `json:"price"`
 	IsNegotiable bool    `json:"is_negotiable"`
 	IsSold       bool    `json:"is_sold"`
+	Country      string  `json:"country" binding:"max=2"`
+	Region       string  `json:"region" binding:"max=6"`
 }
 
-// @Summary      Create a new asset
-// @Description  Creates a new asset for sale under a startup
-// @Tags         assets
-// @Accept       json
-// @Produce      json
-// @Param        request body createAssetRequest true "Asset creation request"
-// @Success      201  {object}  response.APIResponse{data=Asset} "Asset created successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid request payload"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /assets [post]
 func (h *AssetHandler) createAsset(c *gin.Context) {
 	var req createAssetRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid request payload", nil)
+	if !validation.BindJSON(c, &req) {
 		return
 	}
 
 	if req.UserUUID == "" {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "user_uuid must be provided", nil)
+		response.SendError(c, response.InvalidField(apperr.ValidationFailed, "user_uuid", "required", "user_uuid must be provided"))
 		return
 	}
 
 	if !isValidAssetType(req.AssetType) {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid asset_type", nil)
+		response.SendError(c, response.InvalidField(apperr.InvalidAssetType, "asset_type", "oneof", "invalid asset_type"))
 		return
 	}
 
 	if req.Price < 0 {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "price cannot be negative", nil)
+		response.SendError(c, response.InvalidField(apperr.InvalidPrice, "price", "gte", "price cannot be negative"))
 		return
 	}
 
@@ -100,47 +242,36 @@ func (h *AssetHandler) createAsset(c *gin.Context) {
 		IsNegotiable: req.IsNegotiable,
 		IsSold:       req.IsSold,
 		IsActive:     true,
+		Country:      req.Country,
+		Region:       req.Region,
 	})
 	if err != nil {
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
 
 	response.SendAPIResponse(c, http.StatusCreated, true, "asset created", asset)
 }
 
-// @Summary      Update an asset
-// @Description  Updates an existing asset's details
-// @Tags         assets
-// @Accept       json
-// @Produce      json
-// @Param        id   path      int  true  "Asset ID"
-// @Param        request body updateAssetRequest true "Asset update request"
-// @Success      200  {object}  response.APIResponse{data=Asset} "Asset updated successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid request"
-// @Failure      404  {object}  response.APIResponse "Asset not found"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /assets/{id} [put]
 func (h *AssetHandler) updateAsset(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || id <= 0 {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid asset id", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid asset id"))
 		return
 	}
 
 	var req updateAssetRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid request payload", nil)
+	if !validation.BindJSON(c, &req) {
 		return
 	}
 
 	if !isValidAssetType(req.AssetType) {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid asset_type", nil)
+		response.SendError(c, response.InvalidField(apperr.InvalidAssetType, "asset_type", "oneof", "invalid asset_type"))
 		return
 	}
 
 	if req.Price < 0 {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "price cannot be negative", nil)
+		response.SendError(c, response.InvalidField(apperr.InvalidPrice, "price", "gte", "price cannot be negative"))
 		return
 	}
 
@@ -153,105 +284,57 @@ func (h *AssetHandler) updateAsset(c *gin.Context) {
 		Price:        req.Price,
 		IsNegotiable: req.IsNegotiable,
 		IsSold:       req.IsSold,
-	})
+		Country:      req.Country,
+		Region:       req.Region,
+	}, auth.UserID(c))
 	if err != nil {
-		if err == ErrAssetNotFound {
-			response.SendAPIResponse(c, http.StatusNotFound, false, "asset not found", nil)
-			return
-		}
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
 
 	response.SendAPIResponse(c, http.StatusOK, true, "asset updated", asset)
 }
 
-// @Summary      Delete an asset
-// @Description  Deletes an asset by ID
-// @Tags         assets
-// @Produce      json
-// @Param        id   path      int  true  "Asset ID"
-// @Success      200  {object}  response.APIResponse "Asset deleted successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid asset ID"
-// @Failure      404  {object}  response.APIResponse "Asset not found"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /assets/{id} [delete]
 func (h *AssetHandler) deleteAsset(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || id <= 0 {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid asset id", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid asset id"))
 		return
 	}
 
-	if err := h.service.DeleteAsset(c.Request.Context(), id); err != nil {
-		if err == ErrAssetNotFound {
-			response.SendAPIResponse(c, http.StatusNotFound, false, "asset not found", nil)
-			return
-		}
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+	anyOwner := auth.Can(auth.Role(c), auth.PermDeleteListings)
+	if err := h.service.DeleteAsset(c.Request.Context(), id, auth.UserID(c), anyOwner); err != nil {
+		response.SendError(c, err)
 		return
 	}
 
 	response.SendAPIResponse(c, http.StatusOK, true, "asset deleted", nil)
 }
 
-// @Summary      Get asset by ID
-// @Description  Retrieves a single asset by its ID
-// @Tags         assets
-// @Produce      json
-// @Param        id   path      int  true  "Asset ID"
-// @Success      200  {object}  response.APIResponse{data=Asset} "Asset retrieved successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid asset ID"
-// @Failure      404  {object}  response.APIResponse "Asset not found"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /assets/{id} [get]
 func (h *AssetHandler) getAssetByID(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || id <= 0 {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid asset id", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid asset id"))
 		return
 	}
 
 	asset, err := h.service.GetAssetByID(c.Request.Context(), id)
 	if err != nil {
-		if err == ErrAssetNotFound {
-			response.SendAPIResponse(c, http.StatusNotFound, false, "asset not found", nil)
-			return
-		}
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
 
 	response.SendAPIResponse(c, http.StatusOK, true, "asset fetched", asset)
 }
 
-// @Summary      List all assets
-// @Description  Retrieves a paginated list of active assets with optional filters
-// @Tags         assets
-// @Produce      json
-// @Param        page        query     int     false  "Page number" default(1)
-// @Param        limit       query     int     false  "Items per page" default(10)
-// @Param        user_uuid   query     string  false  "Filter by user UUID"
-// @Param        asset_type  query     string  false  "Filter by asset type" Enums(research, codebase, domain, product, data, other)
-// @Param        is_sold     query     bool    false  "Filter by sold status"
-// @Success      200  {object}  response.APIResponse{data=AssetList} "Assets retrieved successfully"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /assets [get]
 func (h *AssetHandler) listAssets(c *gin.Context) {
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if err != nil || limit <= 0 {
-		limit = 10
-	}
-	if limit > 100 {
-		limit = 100
+	p, err := pagination.FromRequest(c, pagination.DefaultLimit)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
 	}
 
-	filters := AssetFilters{}
+	filters := AssetFilters{Viewer: auth.UserID(c)}
 
 	if userUUID := c.Query("user_uuid"); userUUID != "" {
 		filters.UserUUID = &userUUID
@@ -270,93 +353,208 @@ func (h *AssetHandler) listAssets(c *gin.Context) {
 		}
 	}
 
-	assetsList, total, err := h.service.ListAssets(c.Request.Context(), filters, page, limit)
+	country, region, err := geo.Normalize(c.Query("country"), c.Query("region"))
 	if err != nil {
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
+	if country != "" {
+		filters.Country = &country
+	}
+	if region != "" {
+		filters.Region = &region
+	}
 
-	data := AssetList{Items: assetsList, Total: total, Page: page, Limit: limit}
-	response.SendAPIResponse(c, http.StatusOK, true, "assets listed", data)
+	assetsList, total, err := h.service.ListAssets(c.Request.Context(), filters, p.Page, p.Limit)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+
+	if err := h.addDisplayPrices(c, assetsList); err != nil {
+		response.SendError(c, err)
+		return
+	}
+
+	response.SendPage(c, "assets listed", assetsList, total, p)
 }
 
-// @Summary      List assets by user
-// @Description  Retrieves a paginated list of active assets for a specific user
-// @Tags         assets
-// @Produce      json
-// @Param        uuid   path      string  true   "User UUID"
-// @Param        page   query     int  false  "Page number" default(1)
-// @Param        limit  query     int  false  "Items per page" default(10)
-// @Success      200  {object}  response.APIResponse{data=AssetList} "User assets retrieved successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid user UUID"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /users/{uuid}/assets [get]
-func (h *AssetHandler) listAssetsByUser(c *gin.Context) {
-	userUUID := c.Param("uuid")
-	if userUUID == "" {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid user uuid", nil)
+func (h *AssetHandler) searchAssets(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" || len(q) > 200 {
+		response.SendError(c, response.InvalidField(apperr.InvalidRequest, "q", "len", "q must be between 1 and 200 characters"))
 		return
 	}
 
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
+	p, err := pagination.FromRequest(c, pagination.DefaultLimit)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
 	}
 
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if err != nil || limit <= 0 {
-		limit = 10
+	assetsList, total, err := h.service.SearchAssets(c.Request.Context(), q, p.Page, p.Limit)
+	if err != nil {
+		response.SendError(c, err)
+		return
 	}
-	if limit > 100 {
-		limit = 100
+
+	if err := h.addDisplayPrices(c, assetsList); err != nil {
+		response.SendError(c, err)
+		return
 	}
 
-	assetsList, total, err := h.service.ListAssetsByUser(c.Request.Context(), userUUID, page, limit)
+	response.SendPage(c, "assets found", assetsList, total, p)
+}
+
+// parseCompareIDs reads ids=1,2,3, dropping repeats. Between 2 and MaxCompare
+// distinct ids are required.
+func parseCompareIDs(raw string) ([]int64, bool) {
+	var ids []int64
+	seen := map[int64]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || id <= 0 {
+			return nil, false
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, len(ids) >= 2 && len(ids) <= MaxCompare
+}
+
+func (h *AssetHandler) compareAssets(c *gin.Context) {
+	ids, ok := parseCompareIDs(c.Query("ids"))
+	if !ok {
+		response.SendError(c, response.InvalidField(apperr.InvalidRequest, "ids", "len", "ids must list 2 to 5 asset ids"))
+		return
+	}
+
+	list, err := h.service.CompareAssets(c.Request.Context(), ids)
 	if err != nil {
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
 
-	data := AssetList{Items: assetsList, Total: total, Page: page, Limit: limit}
-	response.SendAPIResponse(c, http.StatusOK, true, "startup assets listed", data)
+	response.SendAPIResponse(c, http.StatusOK, true, "assets compared", list)
 }
 
-// @Summary      Delete all assets
-// @Description  Soft deletes all assets by setting is_deleted to true
-// @Tags         assets
-// @Produce      json
-// @Success      200  {object}  response.APIResponse "All assets deleted successfully"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /assets [delete]
-func (h *AssetHandler) deleteAllAssets(c *gin.Context) {
-	if err := h.service.DeleteAllAssets(c.Request.Context()); err != nil {
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+func (h *AssetHandler) listAssetsByUser(c *gin.Context) {
+	userUUID := c.Param("uuid")
+	if userUUID == "" {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid user uuid"))
+		return
+	}
+
+	p, err := pagination.FromRequest(c, pagination.DefaultLimit)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
 		return
 	}
 
-	response.SendAPIResponse(c, http.StatusOK, true, "all assets deleted", nil)
+	assetsList, total, err := h.service.ListAssetsByUser(c.Request.Context(), userUUID, p.Page, p.Limit)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+
+	if err := h.addDisplayPrices(c, assetsList); err != nil {
+		response.SendError(c, err)
+		return
+	}
+
+	response.SendPage(c, "startup assets listed", assetsList, total, p)
 }
 
-// @Summary      Delete all assets by user UUID
-// @Description  Soft deletes all assets for a specific user by setting is_deleted to true
-// @Tags         assets
-// @Produce      json
-// @Param        uuid   path      string  true  "User UUID"
-// @Success      200  {object}  response.APIResponse "All user assets deleted successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid user UUID"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /users/{uuid}/assets/delete-all [delete]
 func (h *AssetHandler) deleteAllAssetsByUserUUID(c *gin.Context) {
 	userUUID := c.Param("uuid")
 	if userUUID == "" {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "user uuid required", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "user uuid required"))
 		return
 	}
 
 	if err := h.service.DeleteAllAssetsByUserUUID(c.Request.Context(), userUUID); err != nil {
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
 
 	response.SendAPIResponse(c, http.StatusOK, true, "all user assets deleted", nil)
 }
+
+// addDisplayPrices fills DisplayPrice when the request asks for a display_currency.
+func (h *AssetHandler) addDisplayPrices(c *gin.Context, list []Asset) error {
+	code := c.Query("display_currency")
+	if code == "" {
+		return nil
+	}
+	if h.rates == nil {
+		return currency.ErrUnavailable
+	}
+	quote, err := h.rates.Quote(c.Request.Context(), code)
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		price := quote.Convert(list[i].Price)
+		list[i].DisplayPrice = &price
+	}
+	return nil
+}
+
+// BulkHandler serves the filtered bulk operations on assets under /admin.
+type BulkHandler struct {
+	service AssetService
+	token   string
+}
+
+// NewBulkHandler serves the bulk routes to callers presenting token as a bearer token,
+// like the other admin endpoints.
+func NewBulkHandler(service AssetService, token string) *BulkHandler {
+	return &BulkHandler{service: service, token: token}
+}
+
+func (h *BulkHandler) RegisterRoutes(router *gin.Engine) {
+	group := router.Group("/admin/assets", admin.RequireToken(h.token))
+	group.DELETE("", h.bulkDelete)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *BulkHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodDelete,
+			Path:        "/admin/assets",
+			Tag:         "admin",
+			Summary:     "Bulk delete assets",
+			Description: "Soft deletes the assets matching the filters; at least one filter is required. With dry_run=true only the matching count is returned",
+			Params:      admin.BulkParams(bulkStatuses...),
+			Response:    admin.BulkResult{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError},
+			Auth:        true,
+		},
+	}
+}
+
+// bulkStatuses are the status filters BulkDeleteAssets understands.
+var bulkStatuses = []string{"active", "sold", "unlisted"}
+
+func (h *BulkHandler) bulkDelete(c *gin.Context) {
+	filter, dryRun, err := admin.ParseBulkFilter(c, bulkStatuses...)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+
+	result, err := h.service.BulkDeleteAssets(c.Request.Context(), filter, dryRun)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+
+	msg := "assets deleted"
+	if dryRun {
+		msg = "bulk delete previewed"
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, msg, result)
+}
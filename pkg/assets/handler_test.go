@@ -12,7 +12,12 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"grveyard/pkg/admin"
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/currency"
 	"grveyard/pkg/response"
+	"grveyard/pkg/testhelpers"
 )
 
 type mockAssetService struct {
@@ -25,14 +30,14 @@ func (m *mockAssetService) CreateAsset(ctx context.Context, input Asset) (Asset,
 	return asset, args.Error(1)
 }
 
-func (m *mockAssetService) UpdateAsset(ctx context.Context, input Asset) (Asset, error) {
-	args := m.Called(ctx, input)
+func (m *mockAssetService) UpdateAsset(ctx context.Context, input Asset, userUUID string) (Asset, error) {
+	args := m.Called(ctx, input, userUUID)
 	asset, _ := args.Get(0).(Asset)
 	return asset, args.Error(1)
 }
 
-func (m *mockAssetService) DeleteAsset(ctx context.Context, id int64) error {
-	args := m.Called(ctx, id)
+func (m *mockAssetService) DeleteAsset(ctx context.Context, id int64, userUUID string, anyOwner bool) error {
+	args := m.Called(ctx, id, userUUID, anyOwner)
 	return args.Error(0)
 }
 
@@ -54,9 +59,9 @@ func (m *mockAssetService) ListAssetsByUser(ctx context.Context, userUUID string
 	return assets, args.Get(1).(int64), args.Error(2)
 }
 
-func (m *mockAssetService) DeleteAllAssets(ctx context.Context) error {
-	args := m.Called(ctx)
-	return args.Error(0)
+func (m *mockAssetService) BulkDeleteAssets(ctx context.Context, filter admin.BulkFilter, dryRun bool) (admin.BulkResult, error) {
+	args := m.Called(ctx, filter, dryRun)
+	return args.Get(0).(admin.BulkResult), args.Error(1)
 }
 
 func (m *mockAssetService) DeleteAllAssetsByUserUUID(ctx context.Context, userUUID string) error {
@@ -64,14 +69,67 @@ func (m *mockAssetService) DeleteAllAssetsByUserUUID(ctx context.Context, userUU
 	return args.Error(0)
 }
 
+func (m *mockAssetService) CompareAssets(ctx context.Context, ids []int64) ([]Comparison, error) {
+	args := m.Called(ctx, ids)
+	list, _ := args.Get(0).([]Comparison)
+	return list, args.Error(1)
+}
+
+func (m *mockAssetService) SearchAssets(ctx context.Context, query string, page, limit int) ([]Asset, int64, error) {
+	args := m.Called(ctx, query, page, limit)
+	assets, _ := args.Get(0).([]Asset)
+	return assets, args.Get(1).(int64), args.Error(2)
+}
+
 func setupAssetRouter(service AssetService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
+	r.Use(testhelpers.AuthAs("uuid-1", "founder"))
 	h := NewAssetHandler(service)
 	h.RegisterRoutes(r)
 	return r
 }
 
+func TestAssetHandler_CreateAsset_Unauthenticated(t *testing.T) {
+	svc := new(mockAssetService)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewAssetHandler(svc).RegisterRoutes(r)
+
+	req := httptest.NewRequest(http.MethodPost, "/assets", strings.NewReader(`{"user_uuid":"uuid-1","title":"Asset","asset_type":"research"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	svc.AssertNotCalled(t, "CreateAsset", mock.Anything, mock.Anything)
+}
+
+func TestAssetHandler_DeleteAsset_NotOwner(t *testing.T) {
+	svc := new(mockAssetService)
+	r := setupAssetRouter(svc)
+
+	// Founders only delete their own assets
+	svc.On("DeleteAsset", mock.Anything, int64(7), "uuid-1", false).Return(ErrNotOwner)
+
+	req := httptest.NewRequest(http.MethodDelete, "/assets/7", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	gin.SetMode(gin.TestMode)
+	adminRouter := gin.New()
+	adminRouter.Use(testhelpers.AuthAs("admin-1", auth.RoleAdmin))
+	NewAssetHandler(svc).RegisterRoutes(adminRouter)
+	svc.On("DeleteAsset", mock.Anything, int64(7), "admin-1", true).Return(nil)
+
+	w = httptest.NewRecorder()
+	adminRouter.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/assets/7", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
 func TestAssetHandler_CreateAsset_Success(t *testing.T) {
 	svc := new(mockAssetService)
 	r := setupAssetRouter(svc)
@@ -113,10 +171,15 @@ func TestAssetHandler_CreateAsset_InvalidType(t *testing.T) {
 	r.ServeHTTP(w, req)
 
 	require.Equal(t, http.StatusBadRequest, w.Code)
-	var resp response.APIResponse
+	var resp struct {
+		response.APIResponse
+		Data response.ValidationErrors `json:"data"`
+	}
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
 	require.False(t, resp.Success)
 	require.Equal(t, "invalid asset_type", resp.Message)
+	require.Equal(t, apperr.InvalidAssetType, resp.ErrorCode)
+	require.Equal(t, []response.FieldError{{Field: "asset_type", Rule: "oneof", Message: "invalid asset_type"}}, resp.Data.Errors)
 
 	svc.AssertNotCalled(t, "CreateAsset", mock.Anything, mock.Anything)
 }
@@ -144,7 +207,7 @@ func TestAssetHandler_UpdateAsset_NotFound(t *testing.T) {
 	svc := new(mockAssetService)
 	r := setupAssetRouter(svc)
 
-	svc.On("UpdateAsset", mock.Anything, mock.Anything).Return(Asset{}, ErrAssetNotFound)
+	svc.On("UpdateAsset", mock.Anything, mock.Anything, "uuid-1").Return(Asset{}, ErrAssetNotFound)
 
 	req := httptest.NewRequest(http.MethodPut, "/assets/1", strings.NewReader(`{"title":"Asset","asset_type":"research"}`))
 	req.Header.Set("Content-Type", "application/json")
@@ -161,6 +224,29 @@ func TestAssetHandler_UpdateAsset_NotFound(t *testing.T) {
 	svc.AssertExpectations(t)
 }
 
+func TestAssetHandler_UpdateAsset_NotOwner(t *testing.T) {
+	svc := new(mockAssetService)
+	r := setupAssetRouter(svc)
+
+	svc.On("UpdateAsset", mock.Anything, mock.MatchedBy(func(input Asset) bool {
+		return input.ID == 1
+	}), "uuid-1").Return(Asset{}, ErrNotOwner)
+
+	req := httptest.NewRequest(http.MethodPut, "/assets/1", strings.NewReader(`{"title":"Asset","asset_type":"research"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	var resp response.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.False(t, resp.Success)
+	require.Equal(t, apperr.Forbidden, resp.ErrorCode)
+
+	svc.AssertExpectations(t)
+}
+
 // func TestAssetHandler_ListAssets_Success(t *testing.T) {
 // 	svc := new(mockAssetService)
 // 	r := setupAssetRouter(svc)
@@ -206,3 +292,165 @@ func TestAssetHandler_ListAssetsByUser_InvalidUUID(t *testing.T) {
 
 	svc.AssertNotCalled(t, "ListAssetsByUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
+
+func TestAssetHandler_SearchAssets(t *testing.T) {
+	svc := new(mockAssetService)
+	r := setupAssetRouter(svc)
+
+	svc.On("SearchAssets", mock.Anything, "crm codebase", 1, 10).Return([]Asset{{ID: 4, Title: "CRM"}}, int64(11), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/assets/search?q=crm+codebase", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		response.APIResponse
+		Data response.Paginated[Asset] `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "assets found", resp.Message)
+	require.Equal(t, int64(11), resp.Data.Total)
+	require.Len(t, resp.Data.Items, 1)
+	require.True(t, resp.Data.HasMore)
+	require.Equal(t, resp.Links.NextCursor, resp.Data.NextCursor)
+	svc.AssertExpectations(t)
+}
+
+func TestAssetHandler_SearchAssets_MissingQuery(t *testing.T) {
+	svc := new(mockAssetService)
+	r := setupAssetRouter(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/assets/search?q=+", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	svc.AssertNotCalled(t, "SearchAssets", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAssetHandler_CompareAssets(t *testing.T) {
+	svc := new(mockAssetService)
+	r := setupAssetRouter(svc)
+
+	svc.On("CompareAssets", mock.Anything, []int64{3, 1}).
+		Return([]Comparison{{ID: 3, Title: "acme.io"}, {ID: 1, Title: "Old App"}}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets/compare?ids=3,1,3", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"title":"acme.io"`)
+	svc.AssertExpectations(t)
+}
+
+func TestAssetHandler_CompareAssets_InvalidIDs(t *testing.T) {
+	svc := new(mockAssetService)
+	r := setupAssetRouter(svc)
+
+	for _, ids := range []string{"", "1", "1,x", "1,2,3,4,5,6"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets/compare?ids="+ids, nil))
+		require.Equal(t, http.StatusBadRequest, w.Code, ids)
+	}
+	svc.AssertNotCalled(t, "CompareAssets", mock.Anything, mock.Anything)
+}
+
+func setupBulkRouter(service AssetService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewBulkHandler(service, "secret").RegisterRoutes(r)
+	return r
+}
+
+func TestBulkHandler_DeleteAssets_DryRun(t *testing.T) {
+	svc := new(mockAssetService)
+	r := setupBulkRouter(svc)
+
+	svc.On("BulkDeleteAssets", mock.Anything, mock.MatchedBy(func(f admin.BulkFilter) bool {
+		return f.OwnerUUID == "user-1" && f.Status == "sold" && f.CreatedBefore != nil
+	}), true).Return(admin.BulkResult{Matched: 4, DryRun: true}, nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/assets?owner_uuid=user-1&status=sold&created_before=2025-01-01&dry_run=true", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"matched":4`)
+	svc.AssertExpectations(t)
+}
+
+func TestBulkHandler_DeleteAssets_Rejected(t *testing.T) {
+	svc := new(mockAssetService)
+	r := setupBulkRouter(svc)
+
+	cases := []struct {
+		query string
+		token string
+		want  int
+	}{
+		{"owner_uuid=user-1", "", http.StatusUnauthorized},
+		{"", "secret", http.StatusBadRequest},
+		{"status=gone", "secret", http.StatusBadRequest},
+		{"created_before=yesterday", "secret", http.StatusBadRequest},
+		{"owner_uuid=user-1&dry_run=maybe", "secret", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodDelete, "/admin/assets?"+tc.query, nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, tc.want, w.Code, tc.query)
+	}
+	svc.AssertNotCalled(t, "BulkDeleteAssets", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAssetHandler_ListAssets_CountryFilter(t *testing.T) {
+	svc := new(mockAssetService)
+	r := setupAssetRouter(svc)
+
+	svc.On("ListAssets", mock.Anything, mock.MatchedBy(func(f AssetFilters) bool {
+		return f.Country != nil && *f.Country == "IN" && f.Region == nil
+	}), 1, 10).Return([]Asset{{ID: 1, Country: "IN"}}, int64(1), nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets?country=in", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets?country=XX", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), string(apperr.InvalidCountry))
+	svc.AssertNumberOfCalls(t, "ListAssets", 1)
+}
+
+type fixedRates struct{}
+
+func (fixedRates) Latest(ctx context.Context) (currency.Rates, error) {
+	return currency.Rates{Base: "USD", Date: "2025-06-02", Rates: map[string]float64{"INR": 85.5}}, nil
+}
+
+func TestAssetHandler_ListAssets_DisplayCurrency(t *testing.T) {
+	svc := new(mockAssetService)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewAssetHandler(svc)
+	h.SetRates(currency.NewService(fixedRates{}))
+	h.RegisterRoutes(r)
+
+	svc.On("ListAssets", mock.Anything, AssetFilters{}, 1, 10).Return([]Asset{{ID: 1, Price: 100}}, int64(1), nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets?display_currency=inr", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"price":100`)
+	require.Contains(t, w.Body.String(), `"display_price":{"amount":8550,"currency":"INR"}`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets?display_currency=XYZ", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), string(apperr.UnsupportedCurrency))
+}
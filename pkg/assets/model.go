@@ -1,6 +1,10 @@
 package assets
 
-import "time"
+import (
+	"time"
+
+	"grveyard/pkg/currency"
+)
 
 type Asset struct {
 	ID           int64     `json:"id"`
@@ -13,12 +17,37 @@ type Asset struct {
 	IsNegotiable bool      `json:"is_negotiable"`
 	IsSold       bool      `json:"is_sold"`
 	IsActive     bool      `json:"is_active"`
+	Country      string    `json:"country,omitempty"` // ISO 3166-1 alpha-2 jurisdiction
+	Region       string    `json:"region,omitempty"`  // ISO 3166-2
 	CreatedAt    time.Time `json:"created_at"`
+	// DisplayPrice is Price converted on request (display_currency); never stored
+	DisplayPrice *currency.Amount `json:"display_price,omitempty"`
+}
+
+// MaxCompare is the most assets GET /assets/compare accepts.
+const MaxCompare = 5
+
+// Comparison is an asset flattened for side-by-side comparison, with the seller's
+// track record and recent interest. Views come from listing_viewed analytics events.
+type Comparison struct {
+	ID           int64            `json:"id"`
+	Title        string           `json:"title"`
+	AssetType    string           `json:"asset_type"`
+	ImageURL     string           `json:"image_url"`
+	Price        float64          `json:"price"`
+	IsNegotiable bool             `json:"is_negotiable"`
+	IsSold       bool             `json:"is_sold"`
+	Views30d     int64            `json:"views_30d"`
+	ListedAt     time.Time        `json:"listed_at"`
+	Seller       SellerReputation `json:"seller"`
 }
 
-type AssetList struct {
-	Items []Asset `json:"items"`
-	Total int64   `json:"total"`
-	Page  int     `json:"page"`
-	Limit int     `json:"limit"`
+// SellerReputation summarizes a seller for buyers weighing their listings.
+type SellerReputation struct {
+	UUID           string    `json:"uuid"`
+	Name           string    `json:"name"`
+	Verified       bool      `json:"verified"`
+	MemberSince    time.Time `json:"member_since"`
+	AssetsSold     int64     `json:"assets_sold"`
+	ActiveListings int64     `json:"active_listings"`
 }
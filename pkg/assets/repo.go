@@ -5,28 +5,41 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/db"
+	"grveyard/pkg/admin"
+	"grveyard/pkg/apperr"
 )
 
-var ErrAssetNotFound = errors.New("asset not found")
+var ErrAssetNotFound = apperr.New(apperr.AssetNotFound, "asset not found")
 
 type AssetRepository interface {
 	CreateAsset(ctx context.Context, input Asset) (Asset, error)
 	UpdateAsset(ctx context.Context, input Asset) (Asset, error)
 	DeleteAsset(ctx context.Context, id int64) error
-	DeleteAllAssets(ctx context.Context) error
+	BulkDeleteAssets(ctx context.Context, filter admin.BulkFilter, dryRun bool) ([]int64, error)
 	DeleteAllAssetsByUserUUID(ctx context.Context, userUUID string) error
 	GetAssetByID(ctx context.Context, id int64) (Asset, error)
 	ListAssets(ctx context.Context, filters AssetFilters, limit, offset int) ([]Asset, int64, error)
 	ListAssetsByUser(ctx context.Context, userUUID string, limit, offset int) ([]Asset, int64, error)
+	SearchAssets(ctx context.Context, query string, limit, offset int) ([]Asset, int64, error)
+	GetAssetsByIDs(ctx context.Context, ids []int64) ([]Asset, error)
+	CompareAssets(ctx context.Context, ids []int64, viewsSince time.Time) ([]Comparison, error)
 }
 
 type AssetFilters struct {
 	UserUUID  *string
 	AssetType *string
 	IsSold    *bool
+	Country   *string // ISO 3166-1 alpha-2
+	Region    *string // ISO 3166-2
+	// Viewer is the signed-in caller, if any; assets of sellers who blocked them are
+	// left out.
+	Viewer string
 }
 
 type postgresAssetRepository struct {
@@ -38,14 +51,14 @@ func NewPostgresAssetRepository(pool *pgxpool.Pool) AssetRepository {
 }
 
 func (r *postgresAssetRepository) CreateAsset(ctx context.Context, input Asset) (Asset, error) {
-	query := `INSERT INTO assets (user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, created_at)
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
-			  RETURNING id, user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, created_at`
+	query := `INSERT INTO assets (user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, sold_at, is_active, country, region, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CASE WHEN $8 THEN NOW() END, $9, $10, $11, NOW())
+			  RETURNING id, user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, country, region, created_at`
 
-	row := r.pool.QueryRow(ctx, query, input.UserUUID, input.Title, input.Description, input.AssetType, input.ImageURL, input.Price, input.IsNegotiable, input.IsSold, input.IsActive)
+	row := r.pool.QueryRow(ctx, query, input.UserUUID, input.Title, input.Description, input.AssetType, input.ImageURL, input.Price, input.IsNegotiable, input.IsSold, input.IsActive, input.Country, input.Region)
 
 	var created Asset
-	if err := row.Scan(&created.ID, &created.UserUUID, &created.Title, &created.Description, &created.AssetType, &created.ImageURL, &created.Price, &created.IsNegotiable, &created.IsSold, &created.IsActive, &created.CreatedAt); err != nil {
+	if err := row.Scan(&created.ID, &created.UserUUID, &created.Title, &created.Description, &created.AssetType, &created.ImageURL, &created.Price, &created.IsNegotiable, &created.IsSold, &created.IsActive, &created.Country, &created.Region, &created.CreatedAt); err != nil {
 		return Asset{}, err
 	}
 
@@ -54,14 +67,15 @@ func (r *postgresAssetRepository) CreateAsset(ctx context.Context, input Asset)
 
 func (r *postgresAssetRepository) UpdateAsset(ctx context.Context, input Asset) (Asset, error) {
 	query := `UPDATE assets
-              SET title = $1, description = $2, asset_type = $3, image_url = $4, price = $5, is_negotiable = $6, is_sold = $7
+              SET title = $1, description = $2, asset_type = $3, image_url = $4, price = $5, is_negotiable = $6, is_sold = $7,
+                  sold_at = CASE WHEN NOT $7 THEN NULL WHEN is_sold THEN sold_at ELSE NOW() END, country = $9, region = $10, updated_at = NOW()
               WHERE id = $8
-			  RETURNING id, user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, created_at`
+			  RETURNING id, user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, country, region, created_at`
 
-	row := r.pool.QueryRow(ctx, query, input.Title, input.Description, input.AssetType, input.ImageURL, input.Price, input.IsNegotiable, input.IsSold, input.ID)
+	row := r.pool.QueryRow(ctx, query, input.Title, input.Description, input.AssetType, input.ImageURL, input.Price, input.IsNegotiable, input.IsSold, input.ID, input.Country, input.Region)
 
 	var updated Asset
-	if err := row.Scan(&updated.ID, &updated.UserUUID, &updated.Title, &updated.Description, &updated.AssetType, &updated.ImageURL, &updated.Price, &updated.IsNegotiable, &updated.IsSold, &updated.IsActive, &updated.CreatedAt); err != nil {
+	if err := row.Scan(&updated.ID, &updated.UserUUID, &updated.Title, &updated.Description, &updated.AssetType, &updated.ImageURL, &updated.Price, &updated.IsNegotiable, &updated.IsSold, &updated.IsActive, &updated.Country, &updated.Region, &updated.CreatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return Asset{}, ErrAssetNotFound
 		}
@@ -83,14 +97,14 @@ func (r *postgresAssetRepository) DeleteAsset(ctx context.Context, id int64) err
 }
 
 func (r *postgresAssetRepository) GetAssetByID(ctx context.Context, id int64) (Asset, error) {
-	query := `SELECT id, user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, created_at
+	query := `SELECT id, user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, country, region, created_at
               FROM assets
               WHERE id = $1 AND is_deleted = false`
 
 	row := r.pool.QueryRow(ctx, query, id)
 
 	var a Asset
-	if err := row.Scan(&a.ID, &a.UserUUID, &a.Title, &a.Description, &a.AssetType, &a.ImageURL, &a.Price, &a.IsNegotiable, &a.IsSold, &a.IsActive, &a.CreatedAt); err != nil {
+	if err := row.Scan(&a.ID, &a.UserUUID, &a.Title, &a.Description, &a.AssetType, &a.ImageURL, &a.Price, &a.IsNegotiable, &a.IsSold, &a.IsActive, &a.Country, &a.Region, &a.CreatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return Asset{}, ErrAssetNotFound
 		}
@@ -123,26 +137,46 @@ func (r *postgresAssetRepository) ListAssets(ctx context.Context, filters AssetF
 		argPos++
 	}
 
+	if filters.Country != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("country = $%d", argPos))
+		args = append(args, *filters.Country)
+		argPos++
+	}
+
+	if filters.Region != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("region = $%d", argPos))
+		args = append(args, *filters.Region)
+		argPos++
+	}
+
+	if filters.Viewer != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("NOT EXISTS (SELECT 1 FROM blocked_users b WHERE b.blocker_uuid = assets.user_uuid AND b.blocked_uuid = $%d)", argPos))
+		args = append(args, filters.Viewer)
+		argPos++
+	}
+
 	whereSQL := "WHERE " + strings.Join(whereClauses, " AND ")
 
-	query := fmt.Sprintf(`SELECT id, user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, created_at
+	// The window count is computed before LIMIT, so the page and the total come back
+	// in one round trip
+	query := fmt.Sprintf(`SELECT id, user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, country, region, created_at,
+                     COUNT(*) OVER() AS total
               FROM assets
               %s
               ORDER BY id
               LIMIT $%d OFFSET $%d`, whereSQL, argPos, argPos+1)
 
-	args = append(args, limit, offset)
-
-	rows, err := r.pool.Query(ctx, query, args...)
+	rows, err := r.pool.Query(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer rows.Close()
 
+	var total int64
 	assetsList := make([]Asset, 0)
 	for rows.Next() {
 		var a Asset
-		if err := rows.Scan(&a.ID, &a.UserUUID, &a.Title, &a.Description, &a.AssetType, &a.ImageURL, &a.Price, &a.IsNegotiable, &a.IsSold, &a.IsActive, &a.CreatedAt); err != nil {
+		if err := rows.Scan(&a.ID, &a.UserUUID, &a.Title, &a.Description, &a.AssetType, &a.ImageURL, &a.Price, &a.IsNegotiable, &a.IsSold, &a.IsActive, &a.Country, &a.Region, &a.CreatedAt, &total); err != nil {
 			return nil, 0, err
 		}
 		assetsList = append(assetsList, a)
@@ -152,20 +186,19 @@ func (r *postgresAssetRepository) ListAssets(ctx context.Context, filters AssetF
 		return nil, 0, err
 	}
 
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM assets %s", whereSQL)
-	countArgs := args[:len(args)-2]
-
-	var total int64
-	countRow := r.pool.QueryRow(ctx, countQuery, countArgs...)
-	if err := countRow.Scan(&total); err != nil {
-		return nil, 0, err
+	// A page past the end has no rows to carry the total
+	if len(assetsList) == 0 && offset > 0 {
+		if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM assets "+whereSQL, args...).Scan(&total); err != nil {
+			return nil, 0, err
+		}
 	}
 
 	return assetsList, total, nil
 }
 
 func (r *postgresAssetRepository) ListAssetsByUser(ctx context.Context, userUUID string, limit, offset int) ([]Asset, int64, error) {
-	query := `SELECT id, user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, created_at
+	query := `SELECT id, user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, country, region, created_at,
+                     COUNT(*) OVER() AS total
               FROM assets
 			  WHERE user_uuid = $1 AND is_active = true AND is_deleted = false
               ORDER BY id
@@ -177,10 +210,11 @@ func (r *postgresAssetRepository) ListAssetsByUser(ctx context.Context, userUUID
 	}
 	defer rows.Close()
 
+	var total int64
 	assetsList := make([]Asset, 0)
 	for rows.Next() {
 		var a Asset
-		if err := rows.Scan(&a.ID, &a.UserUUID, &a.Title, &a.Description, &a.AssetType, &a.ImageURL, &a.Price, &a.IsNegotiable, &a.IsSold, &a.IsActive, &a.CreatedAt); err != nil {
+		if err := rows.Scan(&a.ID, &a.UserUUID, &a.Title, &a.Description, &a.AssetType, &a.ImageURL, &a.Price, &a.IsNegotiable, &a.IsSold, &a.IsActive, &a.Country, &a.Region, &a.CreatedAt, &total); err != nil {
 			return nil, 0, err
 		}
 		assetsList = append(assetsList, a)
@@ -190,21 +224,176 @@ func (r *postgresAssetRepository) ListAssetsByUser(ctx context.Context, userUUID
 		return nil, 0, err
 	}
 
-	var total int64
-	countRow := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM assets WHERE user_uuid = $1 AND is_active = true AND is_deleted = false", userUUID)
-	if err := countRow.Scan(&total); err != nil {
-		return nil, 0, err
+	if len(assetsList) == 0 && offset > 0 {
+		countRow := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM assets WHERE user_uuid = $1 AND is_active = true AND is_deleted = false", userUUID)
+		if err := countRow.Scan(&total); err != nil {
+			return nil, 0, err
+		}
 	}
 
 	return assetsList, total, nil
 }
 
-func (r *postgresAssetRepository) DeleteAllAssets(ctx context.Context) error {
-	_, err := r.pool.Exec(ctx, "UPDATE assets SET is_deleted = true WHERE is_deleted = false")
-	return err
+// BulkDeleteAssets soft deletes the assets matching filter and returns their ids. The
+// status filter is one of active, sold or unlisted. A dry run performs the update to
+// collect the ids and rolls back.
+func (r *postgresAssetRepository) BulkDeleteAssets(ctx context.Context, filter admin.BulkFilter, dryRun bool) ([]int64, error) {
+	whereClauses := []string{"is_deleted = false"}
+	args := []interface{}{}
+
+	if filter.OwnerUUID != "" {
+		args = append(args, filter.OwnerUUID)
+		whereClauses = append(whereClauses, fmt.Sprintf("user_uuid = $%d", len(args)))
+	}
+
+	if filter.CreatedBefore != nil {
+		args = append(args, *filter.CreatedBefore)
+		whereClauses = append(whereClauses, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	switch filter.Status {
+	case "active":
+		whereClauses = append(whereClauses, "is_active = true", "is_sold = false")
+	case "sold":
+		whereClauses = append(whereClauses, "is_sold = true")
+	case "unlisted":
+		whereClauses = append(whereClauses, "is_active = false", "is_sold = false")
+	}
+
+	query := `UPDATE assets SET is_deleted = true, updated_at = NOW()
+              WHERE ` + strings.Join(whereClauses, " AND ") + `
+              RETURNING id`
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return ids, nil
+	}
+	return ids, tx.Commit(ctx)
 }
 
 func (r *postgresAssetRepository) DeleteAllAssetsByUserUUID(ctx context.Context, userUUID string) error {
 	_, err := r.pool.Exec(ctx, "UPDATE assets SET is_deleted = true WHERE user_uuid = $1 AND is_deleted = false", userUUID)
 	return err
 }
+
+// SearchAssets is the SQL fallback for search: a case-insensitive substring match on
+// title and description, newest first.
+func (r *postgresAssetRepository) SearchAssets(ctx context.Context, query string, limit, offset int) ([]Asset, int64, error) {
+	pattern := "%" + db.EscapeLike(query) + "%"
+	where := `WHERE is_active = true AND is_deleted = false AND (title ILIKE $1 OR description ILIKE $1)`
+
+	rows, err := r.pool.Query(ctx, `SELECT id, user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, country, region, created_at
+              FROM assets
+              `+where+`
+              ORDER BY created_at DESC, id DESC
+              LIMIT $2 OFFSET $3`, pattern, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	assetsList := make([]Asset, 0)
+	for rows.Next() {
+		var a Asset
+		if err := rows.Scan(&a.ID, &a.UserUUID, &a.Title, &a.Description, &a.AssetType, &a.ImageURL, &a.Price, &a.IsNegotiable, &a.IsSold, &a.IsActive, &a.Country, &a.Region, &a.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		assetsList = append(assetsList, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM assets "+where, pattern).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	return assetsList, total, nil
+}
+
+// GetAssetsByIDs loads listed assets in the order of ids; unknown, unlisted or deleted
+// ids are skipped.
+func (r *postgresAssetRepository) GetAssetsByIDs(ctx context.Context, ids []int64) ([]Asset, error) {
+	query := `SELECT id, user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, country, region, created_at
+              FROM assets
+              WHERE id = ANY($1) AND is_active = true AND is_deleted = false
+              ORDER BY array_position($1, id::bigint)`
+
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assetsList := make([]Asset, 0, len(ids))
+	for rows.Next() {
+		var a Asset
+		if err := rows.Scan(&a.ID, &a.UserUUID, &a.Title, &a.Description, &a.AssetType, &a.ImageURL, &a.Price, &a.IsNegotiable, &a.IsSold, &a.IsActive, &a.Country, &a.Region, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		assetsList = append(assetsList, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return assetsList, nil
+}
+
+// CompareAssets loads non-deleted assets in the order of ids, including sold and
+// unlisted ones so a comparison doesn't silently lose a column; unknown ids are skipped.
+func (r *postgresAssetRepository) CompareAssets(ctx context.Context, ids []int64, viewsSince time.Time) ([]Comparison, error) {
+	query := `
+		SELECT a.id, a.title, a.asset_type, COALESCE(a.image_url, ''), COALESCE(a.price, 0)::float8, a.is_negotiable, a.is_sold, a.created_at,
+			(SELECT COUNT(*) FROM events e
+			 WHERE e.name = 'listing_viewed' AND e.occurred_at >= $2
+			   AND e.properties->>'listing_type' = 'asset' AND e.properties->>'listing_id' = a.id::text),
+			u.uuid, u.name, u.verified_at IS NOT NULL, u.created_at,
+			(SELECT COUNT(*) FROM assets s WHERE s.user_uuid = u.uuid AND s.is_sold = true AND s.is_deleted = false),
+			(SELECT COUNT(*) FROM assets s WHERE s.user_uuid = u.uuid AND s.is_active = true AND s.is_sold = false AND s.is_deleted = false)
+		FROM assets a
+		JOIN users u ON u.uuid = a.user_uuid
+		WHERE a.id = ANY($1) AND a.is_deleted = false
+		ORDER BY array_position($1, a.id::bigint)`
+
+	rows, err := r.pool.Query(ctx, query, ids, viewsSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]Comparison, 0, len(ids))
+	for rows.Next() {
+		var c Comparison
+		if err := rows.Scan(&c.ID, &c.Title, &c.AssetType, &c.ImageURL, &c.Price, &c.IsNegotiable, &c.IsSold, &c.ListedAt, &c.Views30d,
+			&c.Seller.UUID, &c.Seller.Name, &c.Seller.Verified, &c.Seller.MemberSince, &c.Seller.AssetsSold, &c.Seller.ActiveListings); err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
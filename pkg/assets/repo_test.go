@@ -2,45 +2,27 @@ package assets
 
 import (
 	"context"
-	"os"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/require"
 
+	"grveyard/pkg/admin"
 	"grveyard/pkg/testhelpers"
 )
 
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
 func setupAssetTestPool(t *testing.T) *pgxpool.Pool {
 	t.Helper()
 
-	dsn := os.Getenv("DATABASE_URL_FOR_TEST")
-	if dsn == "" {
-		t.Skip("DATABASE_URL_FOR_TEST not set; skipping asset repository tests")
-	}
-
-	ctx := context.Background()
-	cfg, err := pgxpool.ParseConfig(dsn)
-	require.NoError(t, err)
-
-	pool, err := pgxpool.NewWithConfig(ctx, cfg)
-	require.NoError(t, err)
-	require.NoError(t, pool.Ping(ctx))
-
-	t.Cleanup(pool.Close)
-	return pool
-}
-
-func cleanAssetTables(t *testing.T, pool *pgxpool.Pool) {
-	t.Helper()
-	ctx := context.Background()
-	_, err := pool.Exec(ctx, "TRUNCATE TABLE messages, chats, assets, startups, users RESTART IDENTITY CASCADE")
-	require.NoError(t, err)
+	return testhelpers.Pool(t)
 }
 
 func TestPostgresAssetRepository_CreateAsset(t *testing.T) {
+	t.Parallel()
 	pool := setupAssetTestPool(t)
-	// cleanAssetTables(t, pool)
 
 	repo := NewPostgresAssetRepository(pool)
 	ctx := context.Background()
@@ -66,8 +48,8 @@ func TestPostgresAssetRepository_CreateAsset(t *testing.T) {
 }
 
 func TestPostgresAssetRepository_UpdateAsset(t *testing.T) {
+	t.Parallel()
 	pool := setupAssetTestPool(t)
-	// cleanAssetTables(t, pool)
 
 	repo := NewPostgresAssetRepository(pool)
 	ctx := context.Background()
@@ -95,8 +77,8 @@ func TestPostgresAssetRepository_UpdateAsset(t *testing.T) {
 }
 
 func TestPostgresAssetRepository_DeleteAsset(t *testing.T) {
+	t.Parallel()
 	pool := setupAssetTestPool(t)
-	// cleanAssetTables(t, pool)
 
 	repo := NewPostgresAssetRepository(pool)
 	ctx := context.Background()
@@ -112,8 +94,8 @@ func TestPostgresAssetRepository_DeleteAsset(t *testing.T) {
 }
 
 func TestPostgresAssetRepository_ListAssets_WithFilters(t *testing.T) {
+	t.Parallel()
 	pool := setupAssetTestPool(t)
-	// cleanAssetTables(t, pool)
 
 	repo := NewPostgresAssetRepository(pool)
 	ctx := context.Background()
@@ -138,6 +120,41 @@ func TestPostgresAssetRepository_ListAssets_WithFilters(t *testing.T) {
 	require.Equal(t, "Three", items[0].Title)
 }
 
+// BenchmarkListAssets compares the single windowed query ListAssets runs with the
+// page query plus separate COUNT(*) it replaced.
+func BenchmarkListAssets(b *testing.B) {
+	pool := testhelpers.Pool(b)
+	repo := NewPostgresAssetRepository(pool)
+	ctx := context.Background()
+	owner := testhelpers.NewUser(b, pool).UUID
+	for i := 0; i < 500; i++ {
+		testhelpers.NewAsset(b, pool, testhelpers.WithAssetOwner(owner), testhelpers.WithAssetType("product"))
+	}
+	const where = "WHERE is_active = true AND is_deleted = false AND asset_type = $1"
+
+	b.Run("window_count", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := repo.ListAssets(ctx, AssetFilters{AssetType: ptrString("product")}, 20, 40); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("separate_count", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rows, err := pool.Query(ctx, `SELECT id, user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, is_active, country, region, created_at
+				FROM assets `+where+` ORDER BY id LIMIT 20 OFFSET 40`, "product")
+			if err != nil {
+				b.Fatal(err)
+			}
+			rows.Close()
+			var total int64
+			if err := pool.QueryRow(ctx, "SELECT COUNT(*) FROM assets "+where, "product").Scan(&total); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
 // func TestPostgresAssetRepository_ListAssets_Pagination(t *testing.T) {
 // 	pool := setupAssetTestPool(t)
 // 	// cleanAssetTables(t, pool)
@@ -178,3 +195,110 @@ func TestPostgresAssetRepository_ListAssets_WithFilters(t *testing.T) {
 
 func ptrString(v string) *string { return &v }
 func ptrBool(v bool) *bool       { return &v }
+
+func TestPostgresAssetRepository_CompareAssets(t *testing.T) {
+	t.Parallel()
+	pool := setupAssetTestPool(t)
+
+	repo := NewPostgresAssetRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool, testhelpers.WithVerified())
+	listed := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithPrice(50))
+	sold := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetSold())
+	deleted := testhelpers.NewAsset(t, pool, testhelpers.WithAssetDeleted())
+
+	_, err := pool.Exec(ctx, `INSERT INTO events (name, properties, occurred_at) VALUES ('listing_viewed', jsonb_build_object('listing_type', 'asset', 'listing_id', $1::bigint), NOW())`, listed.ID)
+	require.NoError(t, err)
+
+	list, err := repo.CompareAssets(ctx, []int64{sold.ID, deleted.ID, listed.ID}, time.Now().AddDate(0, 0, -30))
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, sold.ID, list[0].ID)
+	require.True(t, list[0].IsSold)
+	require.Equal(t, listed.ID, list[1].ID)
+	require.Equal(t, 50.0, list[1].Price)
+	require.Equal(t, int64(1), list[1].Views30d)
+	require.True(t, list[1].Seller.Verified)
+	require.Equal(t, int64(1), list[1].Seller.AssetsSold)
+	require.Equal(t, int64(1), list[1].Seller.ActiveListings)
+}
+
+func TestPostgresAssetRepository_BulkDeleteAssets(t *testing.T) {
+	t.Parallel()
+	pool := setupAssetTestPool(t)
+
+	repo := NewPostgresAssetRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool)
+	sold := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetSold())
+	listed := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID))
+	filter := admin.BulkFilter{OwnerUUID: seller.UUID, Status: "sold"}
+
+	// A dry run reports the match and rolls back
+	ids, err := repo.BulkDeleteAssets(ctx, filter, true)
+	require.NoError(t, err)
+	require.Equal(t, []int64{sold.ID}, ids)
+	_, err = repo.GetAssetByID(ctx, sold.ID)
+	require.NoError(t, err)
+
+	ids, err = repo.BulkDeleteAssets(ctx, filter, false)
+	require.NoError(t, err)
+	require.Equal(t, []int64{sold.ID}, ids)
+	_, err = repo.GetAssetByID(ctx, sold.ID)
+	require.ErrorIs(t, err, ErrAssetNotFound)
+	_, err = repo.GetAssetByID(ctx, listed.ID)
+	require.NoError(t, err)
+}
+
+func TestPostgresAssetRepository_ListAssets_ByCountry(t *testing.T) {
+	t.Parallel()
+	pool := setupAssetTestPool(t)
+
+	repo := NewPostgresAssetRepository(pool)
+	ctx := context.Background()
+	ownerUUID := testhelpers.CreateTestUser(t, pool)
+
+	for _, a := range []Asset{
+		{UserUUID: ownerUUID, Title: "Mumbai", AssetType: "domain", IsActive: true, Country: "IN", Region: "IN-MH"},
+		{UserUUID: ownerUUID, Title: "Delhi", AssetType: "domain", IsActive: true, Country: "IN", Region: "IN-DL"},
+		{UserUUID: ownerUUID, Title: "Austin", AssetType: "domain", IsActive: true, Country: "US", Region: "US-TX"},
+	} {
+		_, err := repo.CreateAsset(ctx, a)
+		require.NoError(t, err)
+	}
+
+	items, total, err := repo.ListAssets(ctx, AssetFilters{UserUUID: &ownerUUID, Country: ptrString("IN")}, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+	require.Equal(t, "IN", items[0].Country)
+
+	items, total, err = repo.ListAssets(ctx, AssetFilters{UserUUID: &ownerUUID, Region: ptrString("IN-MH")}, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Equal(t, "Mumbai", items[0].Title)
+}
+
+func TestPostgresAssetRepository_ListAssets_HidesBlockers(t *testing.T) {
+	t.Parallel()
+	pool := setupAssetTestPool(t)
+
+	repo := NewPostgresAssetRepository(pool)
+	ctx := context.Background()
+	blocker := testhelpers.CreateTestUser(t, pool)
+	seller := testhelpers.CreateTestUser(t, pool)
+	buyer := testhelpers.CreateTestUser(t, pool)
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(blocker), testhelpers.WithAssetTitle("Hidden"))
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller), testhelpers.WithAssetTitle("Visible"))
+
+	_, err := pool.Exec(ctx, `INSERT INTO blocked_users (blocker_uuid, blocked_uuid) VALUES ($1, $2)`, blocker, buyer)
+	require.NoError(t, err)
+
+	items, total, err := repo.ListAssets(ctx, AssetFilters{Viewer: buyer}, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Equal(t, "Visible", items[0].Title)
+
+	_, total, err = repo.ListAssets(ctx, AssetFilters{Viewer: seller}, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+}
@@ -1,36 +1,115 @@
 package assets
 
-import "context"
+import (
+	"context"
+	"log"
+	"time"
+
+	"grveyard/pkg/activity"
+	"grveyard/pkg/admin"
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/favorites"
+	"grveyard/pkg/geo"
+	"grveyard/pkg/requestid"
+	"grveyard/pkg/search"
+	"grveyard/pkg/users"
+)
 
 type AssetService interface {
 	CreateAsset(ctx context.Context, input Asset) (Asset, error)
-	UpdateAsset(ctx context.Context, input Asset) (Asset, error)
-	DeleteAsset(ctx context.Context, id int64) error
-	DeleteAllAssets(ctx context.Context) error
+	// UpdateAsset changes an asset owned by userUUID.
+	UpdateAsset(ctx context.Context, input Asset, userUUID string) (Asset, error)
+	// DeleteAsset soft deletes an asset. Only the owner may delete it unless anyOwner
+	// is set.
+	DeleteAsset(ctx context.Context, id int64, userUUID string, anyOwner bool) error
+	BulkDeleteAssets(ctx context.Context, filter admin.BulkFilter, dryRun bool) (admin.BulkResult, error)
 	DeleteAllAssetsByUserUUID(ctx context.Context, userUUID string) error
 	GetAssetByID(ctx context.Context, id int64) (Asset, error)
 	ListAssets(ctx context.Context, filters AssetFilters, page, limit int) ([]Asset, int64, error)
 	ListAssetsByUser(ctx context.Context, userUUID string, page, limit int) ([]Asset, int64, error)
+	SearchAssets(ctx context.Context, query string, page, limit int) ([]Asset, int64, error)
+	CompareAssets(ctx context.Context, ids []int64) ([]Comparison, error)
 }
 
+var ErrNotOwner = apperr.New(apperr.Forbidden, "only the asset owner can change it")
+
 type assetService struct {
-	repo AssetRepository
+	repo     AssetRepository
+	index    search.Index       // optional
+	feed     activity.Recorder  // optional
+	verifier users.Verifier     // optional
+	watchers favorites.Notifier // optional
 }
 
-func NewAssetService(repo AssetRepository) AssetService {
-	return &assetService{repo: repo}
+// NewAssetService creates the asset service. index may be nil, in which case search
+// runs against Postgres, feed may be nil to leave new listings out of the activity
+// feed, verifier may be nil to let unverified sellers list, and watchers may be nil
+// to skip telling users who favorited an asset that its price changed.
+func NewAssetService(repo AssetRepository, index search.Index, feed activity.Recorder, verifier users.Verifier, watchers favorites.Notifier) AssetService {
+	return &assetService{repo: repo, index: index, feed: feed, verifier: verifier, watchers: watchers}
 }
 
 func (s *assetService) CreateAsset(ctx context.Context, input Asset) (Asset, error) {
-	return s.repo.CreateAsset(ctx, input)
+	var err error
+	if input.Country, input.Region, err = geo.Normalize(input.Country, input.Region); err != nil {
+		return Asset{}, err
+	}
+	if s.verifier != nil {
+		if err := s.verifier.RequireVerified(ctx, input.UserUUID); err != nil {
+			return Asset{}, err
+		}
+	}
+	created, err := s.repo.CreateAsset(ctx, input)
+	if err != nil {
+		return Asset{}, err
+	}
+	s.indexAsset(ctx, created)
+	if s.feed != nil {
+		s.feed.Record(ctx, activity.AssetListed, created.ID)
+	}
+	return created, nil
 }
 
-func (s *assetService) UpdateAsset(ctx context.Context, input Asset) (Asset, error) {
-	return s.repo.UpdateAsset(ctx, input)
+func (s *assetService) UpdateAsset(ctx context.Context, input Asset, userUUID string) (Asset, error) {
+	var err error
+	if input.Country, input.Region, err = geo.Normalize(input.Country, input.Region); err != nil {
+		return Asset{}, err
+	}
+	existing, err := s.repo.GetAssetByID(ctx, input.ID)
+	if err != nil {
+		return Asset{}, err
+	}
+	if existing.UserUUID != userUUID {
+		return Asset{}, ErrNotOwner
+	}
+	updated, err := s.repo.UpdateAsset(ctx, input)
+	if err != nil {
+		return Asset{}, err
+	}
+	s.indexAsset(ctx, updated)
+	if s.watchers != nil && (existing.Price != updated.Price || existing.IsSold != updated.IsSold) {
+		s.watchers.AssetChanged(ctx, updated.ID)
+	}
+	return updated, nil
 }
 
-func (s *assetService) DeleteAsset(ctx context.Context, id int64) error {
-	return s.repo.DeleteAsset(ctx, id)
+func (s *assetService) DeleteAsset(ctx context.Context, id int64, userUUID string, anyOwner bool) error {
+	if !anyOwner {
+		existing, err := s.repo.GetAssetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if existing.UserUUID != userUUID {
+			return ErrNotOwner
+		}
+	}
+	if err := s.repo.DeleteAsset(ctx, id); err != nil {
+		return err
+	}
+	if s.index != nil {
+		logIndexErr(ctx, "delete", s.index.Delete(ctx, search.AssetsIndex, id))
+	}
+	return nil
 }
 
 func (s *assetService) GetAssetByID(ctx context.Context, id int64) (Asset, error) {
@@ -59,10 +138,91 @@ func (s *assetService) ListAssetsByUser(ctx context.Context, userUUID string, pa
 	return s.repo.ListAssetsByUser(ctx, userUUID, limit, offset)
 }
 
-func (s *assetService) DeleteAllAssets(ctx context.Context) error {
-	return s.repo.DeleteAllAssets(ctx)
+// SearchAssets ranks with the search engine when configured and loads the hits from
+// Postgres; if the engine fails it falls back to a SQL substring match.
+func (s *assetService) SearchAssets(ctx context.Context, query string, page, limit int) ([]Asset, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	if s.index != nil {
+		ids, total, err := s.index.Search(ctx, search.AssetsIndex, query, limit, offset)
+		if err == nil {
+			if len(ids) == 0 {
+				return []Asset{}, total, nil
+			}
+			list, err := s.repo.GetAssetsByIDs(ctx, ids)
+			return list, total, err
+		}
+		logIndexErr(ctx, "search", err)
+	}
+	return s.repo.SearchAssets(ctx, query, limit, offset)
+}
+
+// CompareAssets returns the assets in the order of ids, with views counted over the
+// last 30 days. Every id must name an existing asset.
+func (s *assetService) CompareAssets(ctx context.Context, ids []int64) ([]Comparison, error) {
+	list, err := s.repo.CompareAssets(ctx, ids, time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		return nil, err
+	}
+	if len(list) != len(ids) {
+		return nil, ErrAssetNotFound
+	}
+	return list, nil
+}
+
+// BulkDeleteAssets soft deletes the assets matching filter, or on a dry run only counts
+// them, and drops the deleted ones from the search index.
+func (s *assetService) BulkDeleteAssets(ctx context.Context, filter admin.BulkFilter, dryRun bool) (admin.BulkResult, error) {
+	ids, err := s.repo.BulkDeleteAssets(ctx, filter, dryRun)
+	if err != nil {
+		return admin.BulkResult{}, err
+	}
+	if !dryRun && s.index != nil && len(ids) > 0 {
+		logIndexErr(ctx, "bulk delete", s.index.Delete(ctx, search.AssetsIndex, ids...))
+	}
+	return admin.BulkResult{Matched: int64(len(ids)), DryRun: dryRun}, nil
 }
 
 func (s *assetService) DeleteAllAssetsByUserUUID(ctx context.Context, userUUID string) error {
-	return s.repo.DeleteAllAssetsByUserUUID(ctx, userUUID)
+	if err := s.repo.DeleteAllAssetsByUserUUID(ctx, userUUID); err != nil {
+		return err
+	}
+	if s.index != nil {
+		logIndexErr(ctx, "delete by user", s.index.DeleteWhere(ctx, search.AssetsIndex, "user_uuid", userUUID))
+	}
+	return nil
+}
+
+// indexAsset publishes listed assets and withdraws unlisted ones. Index failures are
+// logged and never fail the write; the SQL fallback keeps search usable meanwhile.
+func (s *assetService) indexAsset(ctx context.Context, a Asset) {
+	if s.index == nil {
+		return
+	}
+	if !a.IsActive {
+		logIndexErr(ctx, "delete", s.index.Delete(ctx, search.AssetsIndex, a.ID))
+		return
+	}
+	logIndexErr(ctx, "upsert", s.index.Upsert(ctx, search.AssetsIndex, search.Document{
+		"id":          a.ID,
+		"title":       a.Title,
+		"description": a.Description,
+		"asset_type":  a.AssetType,
+		"user_uuid":   a.UserUUID,
+		"price":       a.Price,
+		"is_sold":     a.IsSold,
+		"country":     a.Country,
+	}))
+}
+
+func logIndexErr(ctx context.Context, op string, err error) {
+	if err != nil {
+		log.Printf("[%s] search index %s assets: %v", requestid.FromContext(ctx), op, err)
+	}
 }
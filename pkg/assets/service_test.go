@@ -2,10 +2,16 @@ package assets
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/admin"
+	"grveyard/pkg/search"
+	"grveyard/pkg/users"
 )
 
 type mockAssetRepository struct {
@@ -47,9 +53,28 @@ func (m *mockAssetRepository) ListAssetsByUser(ctx context.Context, userUUID str
 	return assets, args.Get(1).(int64), args.Error(2)
 }
 
-func (m *mockAssetRepository) DeleteAllAssets(ctx context.Context) error {
-	args := m.Called(ctx)
-	return args.Error(0)
+func (m *mockAssetRepository) SearchAssets(ctx context.Context, query string, limit, offset int) ([]Asset, int64, error) {
+	args := m.Called(ctx, query, limit, offset)
+	assets, _ := args.Get(0).([]Asset)
+	return assets, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockAssetRepository) GetAssetsByIDs(ctx context.Context, ids []int64) ([]Asset, error) {
+	args := m.Called(ctx, ids)
+	assets, _ := args.Get(0).([]Asset)
+	return assets, args.Error(1)
+}
+
+func (m *mockAssetRepository) CompareAssets(ctx context.Context, ids []int64, viewsSince time.Time) ([]Comparison, error) {
+	args := m.Called(ctx, ids, viewsSince)
+	list, _ := args.Get(0).([]Comparison)
+	return list, args.Error(1)
+}
+
+func (m *mockAssetRepository) BulkDeleteAssets(ctx context.Context, filter admin.BulkFilter, dryRun bool) ([]int64, error) {
+	args := m.Called(ctx, filter, dryRun)
+	ids, _ := args.Get(0).([]int64)
+	return ids, args.Error(1)
 }
 
 func (m *mockAssetRepository) DeleteAllAssetsByUserUUID(ctx context.Context, userUUID string) error {
@@ -57,9 +82,39 @@ func (m *mockAssetRepository) DeleteAllAssetsByUserUUID(ctx context.Context, use
 	return args.Error(0)
 }
 
+type mockIndex struct {
+	mock.Mock
+}
+
+func (m *mockIndex) Upsert(ctx context.Context, index string, docs ...search.Document) error {
+	args := m.Called(ctx, index, docs)
+	return args.Error(0)
+}
+
+func (m *mockIndex) Delete(ctx context.Context, index string, ids ...int64) error {
+	args := m.Called(ctx, index, ids)
+	return args.Error(0)
+}
+
+func (m *mockIndex) DeleteWhere(ctx context.Context, index, field, value string) error {
+	args := m.Called(ctx, index, field, value)
+	return args.Error(0)
+}
+
+func (m *mockIndex) DeleteAll(ctx context.Context, index string) error {
+	args := m.Called(ctx, index)
+	return args.Error(0)
+}
+
+func (m *mockIndex) Search(ctx context.Context, index, query string, limit, offset int) ([]int64, int64, error) {
+	args := m.Called(ctx, index, query, limit, offset)
+	ids, _ := args.Get(0).([]int64)
+	return ids, args.Get(1).(int64), args.Error(2)
+}
+
 func TestAssetService_ListAssets_Defaults(t *testing.T) {
 	repo := new(mockAssetRepository)
-	service := NewAssetService(repo)
+	service := NewAssetService(repo, nil, nil, nil, nil)
 
 	repo.On("ListAssets", mock.Anything, AssetFilters{}, 10, 0).Return([]Asset{}, int64(0), nil)
 
@@ -71,7 +126,7 @@ func TestAssetService_ListAssets_Defaults(t *testing.T) {
 
 func TestAssetService_ListAssetsByUser_Defaults(t *testing.T) {
 	repo := new(mockAssetRepository)
-	service := NewAssetService(repo)
+	service := NewAssetService(repo, nil, nil, nil, nil)
 
 	repo.On("ListAssetsByUser", mock.Anything, "u-5", 10, 0).Return([]Asset{}, int64(0), nil)
 
@@ -83,7 +138,7 @@ func TestAssetService_ListAssetsByUser_Defaults(t *testing.T) {
 
 func TestAssetService_CreateAsset_Delegates(t *testing.T) {
 	repo := new(mockAssetRepository)
-	service := NewAssetService(repo)
+	service := NewAssetService(repo, nil, nil, nil, nil)
 
 	expected := Asset{ID: 1, Title: "A"}
 	repo.On("CreateAsset", mock.Anything, expected).Return(expected, nil)
@@ -94,3 +149,169 @@ func TestAssetService_CreateAsset_Delegates(t *testing.T) {
 	require.Equal(t, expected, got)
 	repo.AssertExpectations(t)
 }
+
+func TestAssetService_UpdateAsset_OwnerOnly(t *testing.T) {
+	repo := new(mockAssetRepository)
+	service := NewAssetService(repo, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	repo.On("GetAssetByID", mock.Anything, int64(1)).Return(Asset{ID: 1, UserUUID: "owner-1"}, nil)
+	_, err := service.UpdateAsset(ctx, Asset{ID: 1, Title: "B"}, "someone-else")
+	require.ErrorIs(t, err, ErrNotOwner)
+	repo.AssertNotCalled(t, "UpdateAsset", mock.Anything, mock.Anything)
+
+	repo.On("UpdateAsset", mock.Anything, Asset{ID: 1, Title: "B"}).Return(Asset{ID: 1, Title: "B", UserUUID: "owner-1"}, nil)
+	got, err := service.UpdateAsset(ctx, Asset{ID: 1, Title: "B"}, "owner-1")
+	require.NoError(t, err)
+	require.Equal(t, "B", got.Title)
+	repo.AssertExpectations(t)
+}
+
+type mockWatchers struct {
+	changed []int64
+}
+
+func (w *mockWatchers) AssetChanged(ctx context.Context, assetID int64) {
+	w.changed = append(w.changed, assetID)
+}
+
+func TestAssetService_UpdateAsset_NotifiesWatchersOfPriceChange(t *testing.T) {
+	repo := new(mockAssetRepository)
+	watchers := &mockWatchers{}
+	service := NewAssetService(repo, nil, nil, nil, watchers)
+	ctx := context.Background()
+
+	repo.On("GetAssetByID", mock.Anything, int64(1)).Return(Asset{ID: 1, UserUUID: "owner-1", Title: "A", Price: 100}, nil)
+	repo.On("UpdateAsset", mock.Anything, Asset{ID: 1, Title: "B", Price: 100}).Return(Asset{ID: 1, UserUUID: "owner-1", Title: "B", Price: 100}, nil).Once()
+	repo.On("UpdateAsset", mock.Anything, Asset{ID: 1, Title: "A", Price: 80}).Return(Asset{ID: 1, UserUUID: "owner-1", Title: "A", Price: 80}, nil).Once()
+
+	// A new title alone is not worth a notification
+	_, err := service.UpdateAsset(ctx, Asset{ID: 1, Title: "B", Price: 100}, "owner-1")
+	require.NoError(t, err)
+	require.Empty(t, watchers.changed)
+
+	_, err = service.UpdateAsset(ctx, Asset{ID: 1, Title: "A", Price: 80}, "owner-1")
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, watchers.changed)
+}
+
+func TestAssetService_CreateAsset_Indexes(t *testing.T) {
+	repo := new(mockAssetRepository)
+	index := new(mockIndex)
+	service := NewAssetService(repo, index, nil, nil, nil)
+
+	input := Asset{UserUUID: "u1", Title: "CRM", AssetType: "codebase", IsActive: true}
+	created := input
+	created.ID = 7
+	repo.On("CreateAsset", mock.Anything, input).Return(created, nil)
+	index.On("Upsert", mock.Anything, search.AssetsIndex, mock.MatchedBy(func(docs []search.Document) bool {
+		return len(docs) == 1 && docs[0]["id"] == int64(7) && docs[0]["title"] == "CRM"
+	})).Return(errors.New("meilisearch down"))
+
+	// Index failures never fail the write
+	got, err := service.CreateAsset(context.Background(), input)
+
+	require.NoError(t, err)
+	require.Equal(t, int64(7), got.ID)
+	index.AssertExpectations(t)
+}
+
+func TestAssetService_SearchAssets_UsesIndex(t *testing.T) {
+	repo := new(mockAssetRepository)
+	index := new(mockIndex)
+	service := NewAssetService(repo, index, nil, nil, nil)
+
+	index.On("Search", mock.Anything, search.AssetsIndex, "crm", 10, 10).Return([]int64{9, 3}, int64(12), nil)
+	repo.On("GetAssetsByIDs", mock.Anything, []int64{9, 3}).Return([]Asset{{ID: 9}, {ID: 3}}, nil)
+
+	list, total, err := service.SearchAssets(context.Background(), "crm", 2, 10)
+
+	require.NoError(t, err)
+	require.Equal(t, int64(12), total)
+	require.Equal(t, int64(9), list[0].ID)
+	repo.AssertNotCalled(t, "SearchAssets", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAssetService_SearchAssets_FallsBackToSQL(t *testing.T) {
+	repo := new(mockAssetRepository)
+	index := new(mockIndex)
+	service := NewAssetService(repo, index, nil, nil, nil)
+
+	index.On("Search", mock.Anything, search.AssetsIndex, "crm", 10, 0).Return(nil, int64(0), search.ErrUnavailable)
+	repo.On("SearchAssets", mock.Anything, "crm", 10, 0).Return([]Asset{{ID: 1}}, int64(1), nil)
+
+	list, total, err := service.SearchAssets(context.Background(), "crm", 1, 0)
+
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Len(t, list, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestAssetService_CompareAssets_MissingAsset(t *testing.T) {
+	repo := new(mockAssetRepository)
+	service := NewAssetService(repo, nil, nil, nil, nil)
+
+	repo.On("CompareAssets", mock.Anything, []int64{1, 2}, mock.Anything).Return([]Comparison{{ID: 1}}, nil)
+
+	_, err := service.CompareAssets(context.Background(), []int64{1, 2})
+	require.ErrorIs(t, err, ErrAssetNotFound)
+}
+
+func TestAssetService_BulkDeleteAssets(t *testing.T) {
+	repo := new(mockAssetRepository)
+	index := new(mockIndex)
+	service := NewAssetService(repo, index, nil, nil, nil)
+	filter := admin.BulkFilter{OwnerUUID: "user-1"}
+
+	repo.On("BulkDeleteAssets", mock.Anything, filter, true).Return([]int64{4, 5}, nil).Once()
+	repo.On("BulkDeleteAssets", mock.Anything, filter, false).Return([]int64{4, 5}, nil).Once()
+	index.On("Delete", mock.Anything, search.AssetsIndex, []int64{4, 5}).Return(nil).Once()
+
+	// A dry run leaves the search index alone
+	preview, err := service.BulkDeleteAssets(context.Background(), filter, true)
+	require.NoError(t, err)
+	require.Equal(t, admin.BulkResult{Matched: 2, DryRun: true}, preview)
+	index.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+
+	result, err := service.BulkDeleteAssets(context.Background(), filter, false)
+	require.NoError(t, err)
+	require.Equal(t, admin.BulkResult{Matched: 2}, result)
+	index.AssertExpectations(t)
+}
+
+type mockVerifier struct {
+	mock.Mock
+}
+
+func (m *mockVerifier) RequireVerified(ctx context.Context, uuid string) error {
+	args := m.Called(ctx, uuid)
+	return args.Error(0)
+}
+
+func TestAssetService_CreateAsset_RequiresVerification(t *testing.T) {
+	repo := new(mockAssetRepository)
+	verifier := new(mockVerifier)
+	service := NewAssetService(repo, nil, nil, verifier, nil)
+
+	verifier.On("RequireVerified", mock.Anything, "seller-1").Return(users.ErrNotVerified)
+
+	_, err := service.CreateAsset(context.Background(), Asset{UserUUID: "seller-1", Title: "CRM"})
+
+	require.ErrorIs(t, err, users.ErrNotVerified)
+	repo.AssertNotCalled(t, "CreateAsset", mock.Anything, mock.Anything)
+}
+
+func TestAssetService_DeleteAsset_NotOwner(t *testing.T) {
+	repo := new(mockAssetRepository)
+	service := NewAssetService(repo, nil, nil, nil, nil)
+
+	repo.On("GetAssetByID", mock.Anything, int64(10)).Return(Asset{ID: 10, UserUUID: "owner-1"}, nil)
+	repo.On("DeleteAsset", mock.Anything, int64(10)).Return(nil)
+
+	require.ErrorIs(t, service.DeleteAsset(context.Background(), 10, "someone-else", false), ErrNotOwner)
+	repo.AssertNotCalled(t, "DeleteAsset", mock.Anything, mock.Anything)
+	require.NoError(t, service.DeleteAsset(context.Background(), 10, "owner-1", false))
+	require.NoError(t, service.DeleteAsset(context.Background(), 10, "admin-1", true))
+	repo.AssertExpectations(t)
+}
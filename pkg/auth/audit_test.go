@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuditRepository struct{ entries []AuditEntry }
+
+func (f *fakeAuditRepository) Record(_ context.Context, e AuditEntry) error {
+	f.entries = append(f.entries, e)
+	return nil
+}
+
+func (f *fakeAuditRepository) ListAudit(context.Context, string, int, int) ([]AuditEntry, int64, error) {
+	return f.entries, int64(len(f.entries)), nil
+}
+
+func TestRecordImpersonation(t *testing.T) {
+	i := NewIssuer("test-secret", time.Minute)
+	repo := &fakeAuditRepository{}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Authenticate(i), RecordImpersonation(NewAuditLog(repo)))
+	r.DELETE("/assets/:id", Required(), func(c *gin.Context) { c.String(http.StatusOK, Impersonator(c)) })
+
+	own, err := i.Issue("user-1", "founder")
+	require.NoError(t, err)
+	w := serve(r, http.MethodDelete, "/assets/7", own.AccessToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Body.String())
+	require.Empty(t, repo.entries)
+
+	imp, err := i.IssueImpersonation("user-1", "founder", "admin-1")
+	require.NoError(t, err)
+	w = serve(r, http.MethodDelete, "/assets/7", imp.AccessToken)
+	require.Equal(t, "admin-1", w.Body.String())
+	require.Len(t, repo.entries, 1)
+	e := repo.entries[0]
+	require.Equal(t, "admin-1", e.ActorUUID)
+	require.Equal(t, "user-1", e.UserUUID)
+	require.Equal(t, http.MethodDelete, e.Method)
+	require.Equal(t, "/assets/:id", e.Path)
+	require.Equal(t, http.StatusOK, e.Status)
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+)
+
+var ErrSessionNotFound = apperr.New(apperr.SessionNotFound, "session not found")
+
+// Device is a login, listed so users can sign out devices they no longer use or do
+// not recognize. Last use is updated at each refresh, so it lags by up to the access
+// token TTL.
+type Device struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"device_name"` // chosen by the client at login
+	IP         string    `json:"ip"`          // of the last refresh
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// DeviceFromRequest describes the device making a login request.
+func DeviceFromRequest(c *gin.Context, name string) Device {
+	return Device{Name: name, IP: c.ClientIP(), UserAgent: truncate(c.Request.UserAgent(), 512)}
+}
+
+// Devices lists the user's signed-in sessions.
+func (s *Service) Devices(ctx context.Context, userUUID string) ([]Device, error) {
+	return s.repo.ListSessions(ctx, userUUID)
+}
+
+// RevokeDevice signs the user out of session id. Access tokens it already issued
+// stay valid until they expire.
+func (s *Service) RevokeDevice(ctx context.Context, userUUID string, id int64) error {
+	return s.repo.RevokeSession(ctx, userUUID, id)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
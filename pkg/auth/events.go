@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Event types in a user's security log.
+const (
+	EventLogin           = "login"
+	EventLoginFailed     = "login_failed" // wrong password for an existing account
+	EventLogout          = "logout"
+	EventOTPVerified     = "otp_verified"
+	EventPasswordChanged = "password_changed"
+)
+
+// Event is an entry in a user's security log, shown so they can spot access they do
+// not recognize.
+type Event struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type EventRepository interface {
+	Record(ctx context.Context, userUUID, typ, ip, userAgent string) error
+	// RecordByEmail records an event for the account with email; unknown emails are
+	// ignored.
+	RecordByEmail(ctx context.Context, email, typ, ip, userAgent string) error
+	// ListEvents returns the user's events, most recent first, and their total.
+	ListEvents(ctx context.Context, userUUID string, limit, offset int) ([]Event, int64, error)
+}
+
+// EventRecorder records auth events from handlers; *EventLog in production.
+type EventRecorder interface {
+	Record(c *gin.Context, userUUID, typ string)
+	RecordByEmail(c *gin.Context, email, typ string)
+}
+
+// EventLog is the security log. Recording never fails the request it describes:
+// errors are logged and dropped.
+type EventLog struct {
+	repo EventRepository
+}
+
+func NewEventLog(repo EventRepository) *EventLog {
+	return &EventLog{repo: repo}
+}
+
+// Record adds an event for userUUID from the client making request c.
+func (l *EventLog) Record(c *gin.Context, userUUID, typ string) {
+	if err := l.repo.Record(c.Request.Context(), userUUID, typ, c.ClientIP(), truncate(c.Request.UserAgent(), 512)); err != nil {
+		log.Printf("auth events: record %s for %s: %v", typ, userUUID, err)
+	}
+}
+
+// RecordByEmail is Record for requests that name the account by email.
+func (l *EventLog) RecordByEmail(c *gin.Context, email, typ string) {
+	if err := l.repo.RecordByEmail(c.Request.Context(), email, typ, c.ClientIP(), truncate(c.Request.UserAgent(), 512)); err != nil {
+		log.Printf("auth events: record %s by email: %v", typ, err)
+	}
+}
+
+func (l *EventLog) List(ctx context.Context, userUUID string, page, limit int) ([]Event, int64, error) {
+	return l.repo.ListEvents(ctx, userUUID, limit, (page-1)*limit)
+}
+
+type postgresEventRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresEventRepository(pool *pgxpool.Pool) EventRepository {
+	return &postgresEventRepository{pool: pool}
+}
+
+func (r *postgresEventRepository) Record(ctx context.Context, userUUID, typ, ip, userAgent string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO auth_events (user_uuid, event_type, ip, user_agent) VALUES ($1, $2, $3, $4)`,
+		userUUID, typ, ip, userAgent)
+	return err
+}
+
+func (r *postgresEventRepository) RecordByEmail(ctx context.Context, email, typ, ip, userAgent string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO auth_events (user_uuid, event_type, ip, user_agent)
+		SELECT uuid, $2, $3, $4 FROM users WHERE email = $1 AND is_deleted = false`,
+		email, typ, ip, userAgent)
+	return err
+}
+
+func (r *postgresEventRepository) ListEvents(ctx context.Context, userUUID string, limit, offset int) ([]Event, int64, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, event_type, ip, user_agent, created_at
+		FROM auth_events
+		WHERE user_uuid = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, userUUID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	list := make([]Event, 0)
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(&e.ID, &e.Type, &e.IP, &e.UserAgent, &e.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		list = append(list, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM auth_events WHERE user_uuid = $1`, userUUID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/response"
+)
+
+type EventsHandler struct {
+	events *EventLog
+}
+
+func NewEventsHandler(events *EventLog) *EventsHandler {
+	return &EventsHandler{events: events}
+}
+
+func (h *EventsHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/users/:uuid/security-log", RequireSelf("uuid"), h.securityLog)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *EventsHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/security-log",
+			Tag:         "auth",
+			Summary:     "Security log",
+			Description: "Logins, failed password attempts, logouts, OTP verifications and password changes on the account, most recent first, with the IP address and user agent of each.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 10)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[Event]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+func (h *EventsHandler) securityLog(c *gin.Context) {
+	p, err := pagination.FromRequest(c, pagination.DefaultLimit)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	events, total, err := h.events.List(c.Request.Context(), c.Param("uuid"), p.Page, p.Limit)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "security log fetched", events, total, p)
+}
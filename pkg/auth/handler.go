@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+type AuthHandler struct {
+	service *Service
+	events  EventRecorder // optional
+}
+
+func NewAuthHandler(service *Service) *AuthHandler {
+	return &AuthHandler{service: service}
+}
+
+// SetEvents records logouts in the security log.
+func (h *AuthHandler) SetEvents(events EventRecorder) {
+	h.events = events
+}
+
+func (h *AuthHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/auth/refresh", h.refresh)
+	router.POST("/auth/logout", h.logout)
+	router.GET("/users/:uuid/sessions", RequireSelf("uuid"), h.listSessions)
+	router.DELETE("/users/:uuid/sessions/:id", RequireSelf("uuid"), h.revokeSession)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *AuthHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/auth/refresh",
+			Tag:         "auth",
+			Summary:     "Refresh the access token",
+			Description: "Exchanges a refresh token from login or an earlier refresh for a new access token and refresh token. The old refresh token stops working; presenting it again signs out every device of that login.",
+			Request:     refreshRequest{},
+			Response:    Session{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/auth/logout",
+			Tag:         "auth",
+			Summary:     "Log out",
+			Description: "Revokes the refresh token's session, or with all=true every session of its user. Access tokens already issued stay valid until they expire.",
+			Request:     logoutRequest{},
+			Errors:      []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/sessions",
+			Tag:         "auth",
+			Summary:     "List signed-in devices",
+			Description: "Each login that can still refresh, most recently used first. last_used_at and ip are updated when the device refreshes its access token.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Response: []Device{},
+			Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/users/:uuid/sessions/:id",
+			Tag:         "auth",
+			Summary:     "Sign out a device",
+			Description: "Revokes the session's refresh token. Its current access token stays valid until it expires.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+				openapi.Path("id", "integer", "Session ID"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+	}
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required,max=128"`
+}
+
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required,max=128"`
+	All          bool   `json:"all"`
+}
+
+func (h *AuthHandler) refresh(c *gin.Context) {
+	var req refreshRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	session, err := h.service.Refresh(c.Request.Context(), req.RefreshToken, c.ClientIP())
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "token refreshed", session)
+}
+
+func (h *AuthHandler) logout(c *gin.Context) {
+	var req logoutRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	userUUID, err := h.service.Logout(c.Request.Context(), req.RefreshToken, req.All)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	if h.events != nil && userUUID != "" {
+		h.events.Record(c, userUUID, EventLogout)
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "logged out", nil)
+}
+
+func (h *AuthHandler) listSessions(c *gin.Context) {
+	devices, err := h.service.Devices(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "sessions listed", devices)
+}
+
+func (h *AuthHandler) revokeSession(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid session id"))
+		return
+	}
+	if err := h.service.RevokeDevice(c.Request.Context(), c.Param("uuid"), id); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "session revoked", nil)
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/response"
+)
+
+func newTestRouter(i *Issuer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Authenticate(i))
+	r.GET("/public", func(c *gin.Context) { c.String(http.StatusOK, UserID(c)) })
+	r.POST("/private", Required(), func(c *gin.Context) { c.String(http.StatusOK, UserID(c)+" "+Role(c)) })
+	r.PUT("/users/:uuid", RequireSelf("uuid"), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return r
+}
+
+func serve(r *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) apperr.Code {
+	t.Helper()
+	var resp response.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.ErrorCode
+}
+
+func TestAuthenticate_Required(t *testing.T) {
+	i := NewIssuer("test-secret", time.Minute)
+	r := newTestRouter(i)
+	tok, err := i.Issue("user-1", "founder")
+	require.NoError(t, err)
+
+	w := serve(r, http.MethodPost, "/private", tok.AccessToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "user-1 founder", w.Body.String())
+
+	w = serve(r, http.MethodPost, "/private", "")
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Equal(t, apperr.Unauthorized, errorCode(t, w))
+
+	w = serve(r, http.MethodPost, "/private", "not-a-token")
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Equal(t, apperr.InvalidToken, errorCode(t, w))
+}
+
+// TestAuthenticate_PublicRoutes leaves anonymous callers and non-JWT bearer tokens,
+// such as ADMIN_API_TOKEN, alone on routes that do not require a user.
+func TestAuthenticate_PublicRoutes(t *testing.T) {
+	i := NewIssuer("test-secret", time.Minute)
+	r := newTestRouter(i)
+	tok, err := i.Issue("user-1", "buyer")
+	require.NoError(t, err)
+
+	w := serve(r, http.MethodGet, "/public", "admin-api-token")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Body.String())
+
+	w = serve(r, http.MethodGet, "/public", tok.AccessToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "user-1", w.Body.String())
+}
+
+func TestRequireSelf(t *testing.T) {
+	i := NewIssuer("test-secret", time.Minute)
+	r := newTestRouter(i)
+	user, err := i.Issue("user-1", "buyer")
+	require.NoError(t, err)
+	admin, err := i.Issue("admin-1", RoleAdmin)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusNoContent, serve(r, http.MethodPut, "/users/user-1", user.AccessToken).Code)
+	require.Equal(t, http.StatusNoContent, serve(r, http.MethodPut, "/users/user-2", admin.AccessToken).Code)
+	require.Equal(t, http.StatusUnauthorized, serve(r, http.MethodPut, "/users/user-1", "").Code)
+
+	w := serve(r, http.MethodPut, "/users/user-2", user.AccessToken)
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Equal(t, apperr.Forbidden, errorCode(t, w))
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/response"
+)
+
+// RoleModerator reviews reports but cannot delete listings or change roles.
+const RoleModerator = "moderator"
+
+// Permission is an action only some roles may take, checked with RequirePermission.
+type Permission string
+
+const (
+	// PermDeleteListings allows DELETE /assets/:id and /startups/:id on any listing,
+	// and marking any listing sold or unlisting it.
+	PermDeleteListings Permission = "listings:delete"
+	// PermReviewReports allows the /admin/reports queue with an access token.
+	PermReviewReports Permission = "reports:review"
+	// PermManageRoles allows changing any user's role.
+	PermManageRoles Permission = "users:manage_roles"
+	// PermSearchEmails lets GET /users/search match and return email addresses.
+	PermSearchEmails Permission = "users:search_emails"
+	// PermBanUsers allows /admin/users/:uuid/ban and /unban with an access token.
+	PermBanUsers Permission = "users:ban"
+	// PermImpersonate allows /admin/impersonate/:uuid and /admin/audit-log with an
+	// access token.
+	PermImpersonate Permission = "users:impersonate"
+	// PermVerifyStartups allows the /admin/startups verification queue with an access
+	// token.
+	PermVerifyStartups Permission = "startups:verify"
+)
+
+// rolePermissions grants permissions beyond what every signed-in user can do.
+// Buyers and founders have none.
+var rolePermissions = map[string][]Permission{
+	RoleAdmin:     {PermDeleteListings, PermReviewReports, PermManageRoles, PermSearchEmails, PermBanUsers, PermImpersonate, PermVerifyStartups},
+	RoleModerator: {PermReviewReports},
+}
+
+var ErrForbidden = apperr.New(apperr.Forbidden, "your role does not allow this")
+
+// Can reports whether role has perm.
+func Can(role string, perm Permission) bool {
+	return slices.Contains(rolePermissions[role], perm)
+}
+
+// RequirePermission is Required for routes only roles with perm may call, answering
+// 403 to signed-in users without it.
+func RequirePermission(perm Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if UserID(c) == "" {
+			abort(c)
+			return
+		}
+		if !Can(Role(c), perm) {
+			response.SendError(c, ErrForbidden)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
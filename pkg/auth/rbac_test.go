@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/apperr"
+)
+
+func TestCan(t *testing.T) {
+	require.True(t, Can(RoleAdmin, PermDeleteListings))
+	require.True(t, Can(RoleModerator, PermReviewReports))
+	require.False(t, Can(RoleModerator, PermDeleteListings))
+	require.False(t, Can(RoleModerator, PermManageRoles))
+	require.False(t, Can(RoleModerator, PermSearchEmails))
+	require.True(t, Can(RoleAdmin, PermBanUsers))
+	require.False(t, Can(RoleModerator, PermBanUsers))
+	require.False(t, Can(RoleModerator, PermImpersonate))
+	require.False(t, Can("founder", PermReviewReports))
+	require.False(t, Can("", PermReviewReports))
+}
+
+func TestRequirePermission(t *testing.T) {
+	i := NewIssuer("test-secret", time.Minute)
+	r := newTestRouter(i)
+	r.DELETE("/assets/:id", RequirePermission(PermDeleteListings), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	admin, err := i.Issue("admin-1", RoleAdmin)
+	require.NoError(t, err)
+	moderator, err := i.Issue("mod-1", RoleModerator)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusNoContent, serve(r, http.MethodDelete, "/assets/1", admin.AccessToken).Code)
+	require.Equal(t, http.StatusUnauthorized, serve(r, http.MethodDelete, "/assets/1", "").Code)
+
+	w := serve(r, http.MethodDelete, "/assets/1", moderator.AccessToken)
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Equal(t, apperr.Forbidden, errorCode(t, w))
+}
@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RefreshRepository interface {
+	// Create starts a session for family on device and stores the hash of its first
+	// refresh token, valid for ttl.
+	Create(ctx context.Context, userUUID, family, hash string, ttl time.Duration, device Device) error
+	// Rotate revokes the token with oldHash and stores newHash in its family, returning
+	// the owner's UUID and current role, and marks the session used from ip. Presenting
+	// a revoked token revokes its whole family and returns ErrRefreshTokenReused.
+	Rotate(ctx context.Context, oldHash, newHash string, ttl time.Duration, ip string) (string, string, error)
+	// RevokeFamily revokes every token in the family of the token with hash and
+	// returns their user.
+	RevokeFamily(ctx context.Context, hash string) (string, error)
+	// RevokeUser revokes all of a user's active tokens.
+	RevokeUser(ctx context.Context, userUUID string) (int64, error)
+	// DeleteExpired deletes tokens that expired, or were revoked, more than grace ago,
+	// and sessions left without tokens.
+	DeleteExpired(ctx context.Context, grace time.Duration) (int64, error)
+	// ListSessions returns the user's sessions that still have a usable refresh
+	// token, most recently used first.
+	ListSessions(ctx context.Context, userUUID string) ([]Device, error)
+	// RevokeSession revokes the refresh tokens of the user's session id, returning
+	// ErrSessionNotFound if it does not exist or has already ended.
+	RevokeSession(ctx context.Context, userUUID string, id int64) error
+}
+
+type postgresRefreshRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresRefreshRepository(pool *pgxpool.Pool) RefreshRepository {
+	return &postgresRefreshRepository{pool: pool}
+}
+
+func (r *postgresRefreshRepository) Create(ctx context.Context, userUUID, family, hash string, ttl time.Duration, device Device) error {
+	_, err := r.pool.Exec(ctx, `
+		WITH session AS (
+			INSERT INTO sessions (user_uuid, family_id, device_name, ip, user_agent)
+			VALUES ($1, $2, $5, $6, $7)
+		)
+		INSERT INTO refresh_tokens (user_uuid, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))`,
+		userUUID, family, hash, ttl.Seconds(), device.Name, device.IP, device.UserAgent)
+	return err
+}
+
+func (r *postgresRefreshRepository) Rotate(ctx context.Context, oldHash, newHash string, ttl time.Duration, ip string) (string, string, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking the row makes a concurrent refresh with the same token wait, then find
+	// it revoked and treat it as reuse
+	var (
+		id               int64
+		userUUID, family string
+		role             string
+		revoked, expired bool
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT t.id, t.user_uuid, t.family_id, u.role, t.revoked_at IS NOT NULL, t.expires_at <= NOW()
+		FROM refresh_tokens t
+		JOIN users u ON u.uuid = t.user_uuid AND u.is_deleted = false AND u.banned_at IS NULL
+		WHERE t.token_hash = $1
+		FOR UPDATE OF t`, oldHash).
+		Scan(&id, &userUUID, &family, &role, &revoked, &expired)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", "", ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	switch {
+	case revoked:
+		if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`, family); err != nil {
+			return "", "", err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return "", "", err
+		}
+		return "", "", ErrRefreshTokenReused
+	case expired:
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE id = $1`, id); err != nil {
+		return "", "", err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO refresh_tokens (user_uuid, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))`,
+		userUUID, family, newHash, ttl.Seconds()); err != nil {
+		return "", "", err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE sessions SET last_used_at = NOW(), ip = COALESCE(NULLIF($2, ''), ip)
+		WHERE family_id = $1`, family, ip); err != nil {
+		return "", "", err
+	}
+	return userUUID, role, tx.Commit(ctx)
+}
+
+func (r *postgresRefreshRepository) RevokeFamily(ctx context.Context, hash string) (string, error) {
+	var userUUID string
+	err := r.pool.QueryRow(ctx, `
+		WITH target AS (SELECT user_uuid, family_id FROM refresh_tokens WHERE token_hash = $1),
+		revoked AS (
+			UPDATE refresh_tokens t SET revoked_at = NOW()
+			FROM target
+			WHERE t.family_id = target.family_id AND t.revoked_at IS NULL
+		)
+		SELECT user_uuid FROM target`, hash).Scan(&userUUID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrInvalidRefreshToken
+	}
+	return userUUID, err
+}
+
+func (r *postgresRefreshRepository) RevokeUser(ctx context.Context, userUUID string) (int64, error) {
+	cmd, err := r.pool.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_uuid = $1 AND revoked_at IS NULL`, userUUID)
+	if err != nil {
+		return 0, err
+	}
+	return cmd.RowsAffected(), nil
+}
+
+func (r *postgresRefreshRepository) DeleteExpired(ctx context.Context, grace time.Duration) (int64, error) {
+	cmd, err := r.pool.Exec(ctx, `
+		DELETE FROM refresh_tokens
+		WHERE expires_at < NOW() - make_interval(secs => $1)
+		   OR revoked_at < NOW() - make_interval(secs => $1)`, grace.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	if _, err := r.pool.Exec(ctx, `
+		DELETE FROM sessions s
+		WHERE NOT EXISTS (SELECT 1 FROM refresh_tokens t WHERE t.family_id = s.family_id)`); err != nil {
+		return 0, err
+	}
+	return cmd.RowsAffected(), nil
+}
+
+// activeSession matches sessions with a refresh token that can still be used.
+const activeSession = `EXISTS (
+	SELECT 1 FROM refresh_tokens t
+	WHERE t.family_id = s.family_id AND t.revoked_at IS NULL AND t.expires_at > NOW())`
+
+func (r *postgresRefreshRepository) ListSessions(ctx context.Context, userUUID string) ([]Device, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT s.id, s.device_name, s.ip, s.user_agent, s.created_at, s.last_used_at
+		FROM sessions s
+		WHERE s.user_uuid = $1 AND `+activeSession+`
+		ORDER BY s.last_used_at DESC, s.id DESC`, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := []Device{}
+	for rows.Next() {
+		var d Device
+		if err := rows.Scan(&d.ID, &d.Name, &d.IP, &d.UserAgent, &d.CreatedAt, &d.LastUsedAt); err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+	return devices, rows.Err()
+}
+
+func (r *postgresRefreshRepository) RevokeSession(ctx context.Context, userUUID string, id int64) error {
+	cmd, err := r.pool.Exec(ctx, `
+		UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE revoked_at IS NULL AND expires_at > NOW()
+		  AND family_id = (SELECT family_id FROM sessions WHERE id = $1 AND user_uuid = $2)`, id, userUUID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
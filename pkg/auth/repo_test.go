@@ -0,0 +1,149 @@
+// The test helpers import this package, so its database tests live outside it.
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/auth"
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresRefreshRepository(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+	repo := auth.NewPostgresRefreshRepository(pool)
+	ctx := context.Background()
+
+	user := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	other := testhelpers.NewUser(t, pool)
+
+	require.NoError(t, repo.Create(ctx, user.UUID, "fam-1", "hash-1", time.Hour, auth.Device{}))
+	require.NoError(t, repo.Create(ctx, user.UUID, "fam-2", "hash-2", time.Hour, auth.Device{}))
+	require.NoError(t, repo.Create(ctx, other.UUID, "fam-3", "hash-3", time.Hour, auth.Device{}))
+
+	uuid, role, err := repo.Rotate(ctx, "hash-1", "hash-1b", time.Hour, "")
+	require.NoError(t, err)
+	require.Equal(t, user.UUID, uuid)
+	require.Equal(t, "founder", role)
+
+	// Reusing hash-1 revokes hash-1b, but not the user's other family
+	_, _, err = repo.Rotate(ctx, "hash-1", "hash-1c", time.Hour, "")
+	require.ErrorIs(t, err, auth.ErrRefreshTokenReused)
+	_, _, err = repo.Rotate(ctx, "hash-1b", "hash-1d", time.Hour, "")
+	require.ErrorIs(t, err, auth.ErrRefreshTokenReused)
+	_, _, err = repo.Rotate(ctx, "hash-2", "hash-2b", time.Hour, "")
+	require.NoError(t, err)
+
+	_, _, err = repo.Rotate(ctx, "missing", "hash-x", time.Hour, "")
+	require.ErrorIs(t, err, auth.ErrInvalidRefreshToken)
+
+	require.NoError(t, repo.Create(ctx, user.UUID, "fam-4", "hash-expired", -time.Minute, auth.Device{}))
+	_, _, err = repo.Rotate(ctx, "hash-expired", "hash-y", time.Hour, "")
+	require.ErrorIs(t, err, auth.ErrInvalidRefreshToken)
+
+	uuid, err = repo.RevokeFamily(ctx, "hash-3")
+	require.NoError(t, err)
+	require.Equal(t, other.UUID, uuid)
+	_, err = repo.RevokeFamily(ctx, "missing")
+	require.ErrorIs(t, err, auth.ErrInvalidRefreshToken)
+
+	n, err := repo.RevokeUser(ctx, user.UUID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n) // hash-2b and hash-expired
+	_, _, err = repo.Rotate(ctx, "hash-2b", "hash-2c", time.Hour, "")
+	require.ErrorIs(t, err, auth.ErrRefreshTokenReused)
+
+	n, err = repo.DeleteExpired(ctx, 0)
+	require.NoError(t, err)
+	require.Positive(t, n)
+	n, err = repo.DeleteExpired(ctx, 0)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestPostgresRefreshRepository_DeletedUser(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+	repo := auth.NewPostgresRefreshRepository(pool)
+	ctx := context.Background()
+
+	user := testhelpers.NewUser(t, pool, testhelpers.WithUserDeleted())
+	require.NoError(t, repo.Create(ctx, user.UUID, "fam-1", "hash-1", time.Hour, auth.Device{}))
+
+	_, _, err := repo.Rotate(ctx, "hash-1", "hash-2", time.Hour, "")
+	require.ErrorIs(t, err, auth.ErrInvalidRefreshToken)
+}
+
+func TestPostgresRefreshRepository_Sessions(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+	repo := auth.NewPostgresRefreshRepository(pool)
+	ctx := context.Background()
+
+	user := testhelpers.NewUser(t, pool)
+	other := testhelpers.NewUser(t, pool)
+
+	phone := auth.Device{Name: "Phone", IP: "203.0.113.1", UserAgent: "app/1.0"}
+	require.NoError(t, repo.Create(ctx, user.UUID, "fam-1", "hash-1", time.Hour, phone))
+	require.NoError(t, repo.Create(ctx, user.UUID, "fam-2", "hash-2", time.Hour, auth.Device{Name: "Laptop"}))
+	require.NoError(t, repo.Create(ctx, user.UUID, "fam-3", "hash-3", -time.Minute, auth.Device{Name: "Expired"}))
+	require.NoError(t, repo.Create(ctx, other.UUID, "fam-4", "hash-4", time.Hour, auth.Device{Name: "Other"}))
+
+	_, _, err := repo.Rotate(ctx, "hash-1", "hash-1b", time.Hour, "203.0.113.9")
+	require.NoError(t, err)
+
+	devices, err := repo.ListSessions(ctx, user.UUID)
+	require.NoError(t, err)
+	require.Len(t, devices, 2)
+	require.Equal(t, "Phone", devices[0].Name, "most recently used first")
+	require.Equal(t, "203.0.113.9", devices[0].IP)
+	require.Equal(t, "app/1.0", devices[0].UserAgent)
+	require.Equal(t, "Laptop", devices[1].Name)
+
+	require.ErrorIs(t, repo.RevokeSession(ctx, other.UUID, devices[1].ID), auth.ErrSessionNotFound)
+	require.NoError(t, repo.RevokeSession(ctx, user.UUID, devices[1].ID))
+	require.ErrorIs(t, repo.RevokeSession(ctx, user.UUID, devices[1].ID), auth.ErrSessionNotFound)
+	_, _, err = repo.Rotate(ctx, "hash-2", "hash-2b", time.Hour, "")
+	require.ErrorIs(t, err, auth.ErrRefreshTokenReused)
+
+	devices, err = repo.ListSessions(ctx, user.UUID)
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+	require.Equal(t, "Phone", devices[0].Name)
+}
+
+func TestPostgresEventRepository(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+	repo := auth.NewPostgresEventRepository(pool)
+	ctx := context.Background()
+
+	user := testhelpers.NewUser(t, pool, testhelpers.WithEmail("events@example.com"))
+	other := testhelpers.NewUser(t, pool)
+
+	require.NoError(t, repo.Record(ctx, user.UUID, auth.EventLogin, "203.0.113.1", "app/1.0"))
+	require.NoError(t, repo.RecordByEmail(ctx, "events@example.com", auth.EventOTPVerified, "203.0.113.2", ""))
+	require.NoError(t, repo.RecordByEmail(ctx, "nobody@example.com", auth.EventLoginFailed, "203.0.113.3", ""))
+	require.NoError(t, repo.Record(ctx, other.UUID, auth.EventLogout, "", ""))
+
+	events, total, err := repo.ListEvents(ctx, user.UUID, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+	require.Len(t, events, 2)
+	require.Equal(t, auth.EventOTPVerified, events[0].Type, "most recent first")
+	require.Equal(t, auth.EventLogin, events[1].Type)
+	require.Equal(t, "203.0.113.1", events[1].IP)
+	require.Equal(t, "app/1.0", events[1].UserAgent)
+
+	events, total, err = repo.ListEvents(ctx, user.UUID, 1, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+	require.Len(t, events, 1)
+	require.Equal(t, auth.EventLogin, events[0].Type)
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"grveyard/pkg/apperr"
+)
+
+// DefaultRefreshTTL is how long a refresh token may be used, counted from the last
+// refresh.
+const DefaultRefreshTTL = 30 * 24 * time.Hour
+
+var (
+	ErrInvalidRefreshToken = apperr.New(apperr.InvalidRefreshToken, "invalid or expired refresh token")
+	// ErrRefreshTokenReused means a token that was already rotated came back, so it
+	// may have been stolen. Its family was revoked and the user must log in again.
+	ErrRefreshTokenReused = apperr.New(apperr.InvalidRefreshToken, "refresh token already used; log in again")
+)
+
+// Session is what a client holds after logging in: a short-lived access token for
+// requests and a refresh token for getting the next one.
+type Session struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"` // seconds
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in"` // seconds
+}
+
+// Service starts sessions at login and rotates their refresh tokens. Refresh tokens
+// are opaque random strings stored hashed; each login starts a family, and every
+// refresh revokes the presented token in favour of a new one in the same family.
+type Service struct {
+	repo       RefreshRepository
+	issuer     *Issuer
+	refreshTTL time.Duration
+}
+
+// NewService returns a Service; a refreshTTL of zero means DefaultRefreshTTL.
+func NewService(repo RefreshRepository, issuer *Issuer, refreshTTL time.Duration) *Service {
+	if refreshTTL <= 0 {
+		refreshTTL = DefaultRefreshTTL
+	}
+	return &Service{repo: repo, issuer: issuer, refreshTTL: refreshTTL}
+}
+
+// StartSession issues an access token and the first refresh token of a new family,
+// recorded as a session on device.
+func (s *Service) StartSession(ctx context.Context, userUUID, role string, device Device) (Session, error) {
+	family, err := randomToken(16)
+	if err != nil {
+		return Session{}, err
+	}
+	refresh, err := randomToken(32)
+	if err != nil {
+		return Session{}, err
+	}
+	if err := s.repo.Create(ctx, userUUID, family, hashToken(refresh), s.refreshTTL, device); err != nil {
+		return Session{}, err
+	}
+	return s.session(userUUID, role, refresh)
+}
+
+// Refresh exchanges a refresh token for a new access token and refresh token. The
+// role in the new access token is read from the user's current row, so role changes
+// take effect at the next refresh, and deleted or banned users cannot refresh. The
+// session's last use is recorded as coming from ip.
+func (s *Service) Refresh(ctx context.Context, refreshToken, ip string) (Session, error) {
+	next, err := randomToken(32)
+	if err != nil {
+		return Session{}, err
+	}
+	userUUID, role, err := s.repo.Rotate(ctx, hashToken(refreshToken), hashToken(next), s.refreshTTL, ip)
+	if err != nil {
+		return Session{}, err
+	}
+	return s.session(userUUID, role, next)
+}
+
+// Logout revokes the session refreshToken belongs to, or with all every session of
+// its user, and returns the user. Unknown and already revoked tokens are not an
+// error and return "".
+func (s *Service) Logout(ctx context.Context, refreshToken string, all bool) (string, error) {
+	userUUID, err := s.repo.RevokeFamily(ctx, hashToken(refreshToken))
+	if errors.Is(err, ErrInvalidRefreshToken) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if all {
+		if _, err := s.repo.RevokeUser(ctx, userUUID); err != nil {
+			return "", err
+		}
+	}
+	return userUUID, nil
+}
+
+// LogoutUser revokes every session of userUUID, e.g. after a password change.
+func (s *Service) LogoutUser(ctx context.Context, userUUID string) error {
+	_, err := s.repo.RevokeUser(ctx, userUUID)
+	return err
+}
+
+// Prune deletes refresh tokens that expired or were revoked over a day ago.
+func (s *Service) Prune(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, 24*time.Hour)
+}
+
+func (s *Service) session(userUUID, role, refresh string) (Session, error) {
+	tok, err := s.issuer.Issue(userUUID, role)
+	if err != nil {
+		return Session{}, err
+	}
+	return Session{
+		AccessToken:      tok.AccessToken,
+		TokenType:        TokenType,
+		ExpiresIn:        int64(tok.ExpiresAt.Sub(s.issuer.now()) / time.Second),
+		RefreshToken:     refresh,
+		RefreshExpiresIn: int64(s.refreshTTL / time.Second),
+	}, nil
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRefreshRepo struct {
+	tokens  map[string]fakeRefresh // by hash
+	revoked []string               // users passed to RevokeUser
+}
+
+type fakeRefresh struct {
+	userUUID, family string
+	revoked          bool
+}
+
+func newFakeRefreshRepo() *fakeRefreshRepo {
+	return &fakeRefreshRepo{tokens: map[string]fakeRefresh{}}
+}
+
+func (f *fakeRefreshRepo) Create(_ context.Context, userUUID, family, hash string, _ time.Duration, _ Device) error {
+	f.tokens[hash] = fakeRefresh{userUUID: userUUID, family: family}
+	return nil
+}
+
+func (f *fakeRefreshRepo) Rotate(_ context.Context, oldHash, newHash string, _ time.Duration, _ string) (string, string, error) {
+	old, ok := f.tokens[oldHash]
+	if !ok {
+		return "", "", ErrInvalidRefreshToken
+	}
+	if old.revoked {
+		f.revokeFamily(old.family)
+		return "", "", ErrRefreshTokenReused
+	}
+	old.revoked = true
+	f.tokens[oldHash] = old
+	f.tokens[newHash] = fakeRefresh{userUUID: old.userUUID, family: old.family}
+	return old.userUUID, "founder", nil
+}
+
+func (f *fakeRefreshRepo) RevokeFamily(_ context.Context, hash string) (string, error) {
+	tok, ok := f.tokens[hash]
+	if !ok {
+		return "", ErrInvalidRefreshToken
+	}
+	f.revokeFamily(tok.family)
+	return tok.userUUID, nil
+}
+
+func (f *fakeRefreshRepo) RevokeUser(_ context.Context, userUUID string) (int64, error) {
+	f.revoked = append(f.revoked, userUUID)
+	return 0, nil
+}
+
+func (f *fakeRefreshRepo) DeleteExpired(context.Context, time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeRefreshRepo) ListSessions(context.Context, string) ([]Device, error) {
+	return nil, nil
+}
+
+func (f *fakeRefreshRepo) RevokeSession(context.Context, string, int64) error {
+	return nil
+}
+
+func (f *fakeRefreshRepo) revokeFamily(family string) {
+	for h, t := range f.tokens {
+		if t.family == family {
+			t.revoked = true
+			f.tokens[h] = t
+		}
+	}
+}
+
+func TestService_SessionLifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRefreshRepo()
+	issuer := newTestIssuer(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+	svc := NewService(repo, issuer, time.Hour)
+
+	s, err := svc.StartSession(ctx, "user-1", "founder", Device{})
+	require.NoError(t, err)
+	require.Equal(t, TokenType, s.TokenType)
+	require.Equal(t, int64(60), s.ExpiresIn)
+	require.Equal(t, int64(3600), s.RefreshExpiresIn)
+	require.Contains(t, repo.tokens, hashToken(s.RefreshToken))
+	require.NotContains(t, repo.tokens, s.RefreshToken, "stored hashed")
+
+	next, err := svc.Refresh(ctx, s.RefreshToken, "")
+	require.NoError(t, err)
+	require.NotEqual(t, s.RefreshToken, next.RefreshToken)
+	claims, err := issuer.Parse(next.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, "user-1", claims.Subject)
+
+	// Replaying the first token revokes the rotated one too
+	_, err = svc.Refresh(ctx, s.RefreshToken, "")
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+	_, err = svc.Refresh(ctx, next.RefreshToken, "")
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	_, err = svc.Refresh(ctx, "unknown", "")
+	require.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
+
+func TestService_Logout(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRefreshRepo()
+	svc := NewService(repo, newTestIssuer(time.Now()), 0)
+
+	s, err := svc.StartSession(ctx, "user-1", "buyer", Device{})
+	require.NoError(t, err)
+	userUUID, err := svc.Logout(ctx, s.RefreshToken, false)
+	require.NoError(t, err)
+	require.Equal(t, "user-1", userUUID)
+	require.Empty(t, repo.revoked)
+	_, err = svc.Refresh(ctx, s.RefreshToken, "")
+	require.Error(t, err)
+
+	s, err = svc.StartSession(ctx, "user-1", "buyer", Device{})
+	require.NoError(t, err)
+	_, err = svc.Logout(ctx, s.RefreshToken, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user-1"}, repo.revoked)
+
+	userUUID, err = svc.Logout(ctx, "unknown", true)
+	require.NoError(t, err)
+	require.Empty(t, userUUID)
+}
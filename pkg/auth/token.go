@@ -0,0 +1,157 @@
+// Package auth issues and verifies the signed access tokens clients present as
+// "Authorization: Bearer <token>" after logging in. Tokens are HS256 JWTs carrying
+// the user's UUID and role, built with the standard library like the GitHub App
+// tokens in pkg/github.
+// https://datatracker.ietf.org/doc/html/rfc7519
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"grveyard/pkg/apperr"
+)
+
+const (
+	// DefaultAccessTTL is how long an access token is accepted.
+	DefaultAccessTTL = 15 * time.Minute
+	// ImpersonationTTL is how long a token from IssueImpersonation is accepted. It
+	// cannot be refreshed.
+	ImpersonationTTL = 10 * time.Minute
+	// TokenType is what clients put before the token in the Authorization header.
+	TokenType = "Bearer"
+
+	issuer = "grveyard"
+	header = `{"alg":"HS256","typ":"JWT"}`
+)
+
+var ErrInvalidToken = apperr.New(apperr.InvalidToken, "invalid or expired token")
+
+// Claims are the registered JWT claims we use plus the user's role.
+type Claims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"` // user UUID
+	Role      string `json:"role"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	ID        string `json:"jti"`
+	// Actor is set on impersonation tokens and names who is acting as Subject, as in
+	// RFC 8693's "act" claim.
+	Actor *Actor `json:"act,omitempty"`
+}
+
+// Actor is the party behind an impersonation token.
+type Actor struct {
+	Subject string `json:"sub"` // admin UUID, or ActorAdminToken
+}
+
+// Token is a signed access token and when it stops being accepted.
+type Token struct {
+	AccessToken string
+	ExpiresAt   time.Time
+}
+
+// Issuer signs and verifies access tokens with a shared secret.
+type Issuer struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewIssuer returns an Issuer signing with secret; a ttl of zero means DefaultAccessTTL.
+func NewIssuer(secret string, ttl time.Duration) *Issuer {
+	if ttl <= 0 {
+		ttl = DefaultAccessTTL
+	}
+	return &Issuer{secret: []byte(secret), ttl: ttl, now: time.Now}
+}
+
+// RandomSecret returns a secret for development setups without JWT_SECRET. Tokens
+// signed with it stop verifying when the process restarts.
+func RandomSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand never fails on supported platforms
+	}
+	return hex.EncodeToString(b)
+}
+
+// Issue returns an access token for the user.
+func (i *Issuer) Issue(userUUID, role string) (Token, error) {
+	return i.issue(userUUID, role, nil, i.ttl)
+}
+
+// IssueImpersonation returns an access token acting as the user on behalf of actor,
+// valid for ImpersonationTTL.
+func (i *Issuer) IssueImpersonation(userUUID, role, actor string) (Token, error) {
+	return i.issue(userUUID, role, &Actor{Subject: actor}, ImpersonationTTL)
+}
+
+func (i *Issuer) issue(userUUID, role string, actor *Actor, ttl time.Duration) (Token, error) {
+	now := i.now()
+	expires := now.Add(ttl)
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return Token{}, err
+	}
+	claims, err := json.Marshal(Claims{
+		Issuer:    issuer,
+		Subject:   userUUID,
+		Role:      role,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expires.Unix(),
+		ID:        hex.EncodeToString(jti),
+		Actor:     actor,
+	})
+	if err != nil {
+		return Token{}, err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return Token{
+		AccessToken: signingInput + "." + base64.RawURLEncoding.EncodeToString(i.sign(signingInput)),
+		ExpiresAt:   expires,
+	}, nil
+}
+
+// Parse verifies token's signature, issuer and expiry and returns its claims. Only
+// HS256 is accepted, whatever the header says, so a token cannot pick a weaker
+// algorithm such as "none".
+func (i *Issuer) Parse(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrInvalidToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, i.sign(parts[0]+"."+parts[1])) {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(rawHeader, &h) != nil || h.Alg != "HS256" {
+		return Claims{}, ErrInvalidToken
+	}
+	var c Claims
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || json.Unmarshal(rawClaims, &c) != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	if c.Issuer != issuer || c.Subject == "" || i.now().Unix() >= c.ExpiresAt {
+		return Claims{}, ErrInvalidToken
+	}
+	return c, nil
+}
+
+func (i *Issuer) sign(signingInput string) []byte {
+	mac := hmac.New(sha256.New, i.secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
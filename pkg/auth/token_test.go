@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIssuer(now time.Time) *Issuer {
+	i := NewIssuer("test-secret", time.Minute)
+	i.now = func() time.Time { return now }
+	return i
+}
+
+func TestIssuer_IssueAndParse(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	i := newTestIssuer(now)
+
+	tok, err := i.Issue("user-1", "founder")
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Minute), tok.ExpiresAt)
+
+	c, err := i.Parse(tok.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, "user-1", c.Subject)
+	require.Equal(t, "founder", c.Role)
+	require.Equal(t, now.Unix(), c.IssuedAt)
+	require.NotEmpty(t, c.ID)
+}
+
+func TestIssuer_IssueImpersonation(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	i := newTestIssuer(now)
+
+	tok, err := i.IssueImpersonation("user-1", "buyer", "admin-1")
+	require.NoError(t, err)
+	require.Equal(t, now.Add(ImpersonationTTL), tok.ExpiresAt)
+
+	c, err := i.Parse(tok.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, "user-1", c.Subject)
+	require.Equal(t, &Actor{Subject: "admin-1"}, c.Actor)
+
+	plain, err := i.Issue("user-1", "buyer")
+	require.NoError(t, err)
+	c, err = i.Parse(plain.AccessToken)
+	require.NoError(t, err)
+	require.Nil(t, c.Actor)
+}
+
+func TestIssuer_Parse_Expired(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	i := newTestIssuer(now)
+	tok, err := i.Issue("user-1", "buyer")
+	require.NoError(t, err)
+
+	i.now = func() time.Time { return now.Add(time.Minute) }
+
+	_, err = i.Parse(tok.AccessToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestIssuer_Parse_Rejects(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	i := newTestIssuer(now)
+	tok, err := i.Issue("user-1", "buyer")
+	require.NoError(t, err)
+	parts := strings.Split(tok.AccessToken, ".")
+
+	other, err := newTestIssuer(now).Issue("user-1", "buyer")
+	require.NoError(t, err)
+	otherSecret := NewIssuer("other-secret", time.Minute)
+	otherSecret.now = i.now
+	forged, err := otherSecret.Issue("user-1", "admin")
+	require.NoError(t, err)
+
+	admin := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"grveyard","sub":"user-1","role":"admin","exp":9999999999}`))
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	for name, token := range map[string]string{
+		"empty":          "",
+		"two parts":      parts[0] + "." + parts[1],
+		"other secret":   forged.AccessToken,
+		"swapped claims": parts[0] + "." + admin + "." + parts[2],
+		"alg none":       none + "." + parts[1] + ".",
+		"bad signature":  parts[0] + "." + parts[1] + ".!!",
+	} {
+		_, err := i.Parse(token)
+		require.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+
+	_, err = i.Parse(other.AccessToken)
+	require.NoError(t, err)
+}
@@ -0,0 +1,129 @@
+package bookmarks
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/response"
+)
+
+type BookmarkHandler struct {
+	service *Service
+}
+
+func NewBookmarkHandler(service *Service) *BookmarkHandler {
+	return &BookmarkHandler{service: service}
+}
+
+func (h *BookmarkHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/startups/:id/bookmark", auth.Required(), h.add)
+	router.DELETE("/startups/:id/bookmark", auth.Required(), h.remove)
+	router.GET("/users/:uuid/bookmarked-startups", auth.RequireSelf("uuid"), h.list)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *BookmarkHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/startups/:id/bookmark",
+			Tag:         "bookmarks",
+			Summary:     "Bookmark a startup",
+			Description: "Followers are notified in-app when the startup's status changes. Bookmarking a startup twice returns 200.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Startup ID"),
+			},
+			Status: http.StatusCreated,
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/startups/:id/bookmark",
+			Tag:     "bookmarks",
+			Summary: "Remove a startup bookmark",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Startup ID"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/bookmarked-startups",
+			Tag:         "bookmarks",
+			Summary:     "List bookmarked startups",
+			Description: "Startups the user bookmarked, most recent first, with their current status",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 20)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[Bookmark]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+const bookmarksPageSize = 20
+
+func startupID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *BookmarkHandler) add(c *gin.Context) {
+	id, ok := startupID(c)
+	if !ok {
+		return
+	}
+	added, err := h.service.Add(c.Request.Context(), auth.UserID(c), id)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	if !added {
+		response.SendAPIResponse(c, http.StatusOK, true, "startup already bookmarked", nil)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusCreated, true, "startup bookmarked", nil)
+}
+
+func (h *BookmarkHandler) remove(c *gin.Context) {
+	id, ok := startupID(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Remove(c.Request.Context(), auth.UserID(c), id); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "bookmark removed", nil)
+}
+
+func (h *BookmarkHandler) list(c *gin.Context) {
+	p, err := pagination.FromRequest(c, bookmarksPageSize)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	list, total, err := h.service.List(c.Request.Context(), c.Param("uuid"), p)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "bookmarked startups listed", list, total, p)
+}
@@ -0,0 +1,12 @@
+package bookmarks
+
+import "time"
+
+// Bookmark is a startup a user follows, with the startup's current details.
+type Bookmark struct {
+	StartupID    int64     `json:"startup_id"`
+	Name         string    `json:"name"`
+	LogoURL      string    `json:"logo_url"`
+	Status       string    `json:"status"`
+	BookmarkedAt time.Time `json:"bookmarked_at"`
+}
@@ -0,0 +1,111 @@
+package bookmarks
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BookmarkRepository stores startup bookmarks. They are kept apart from asset
+// favorites because buyers follow whole companies differently from single assets.
+type BookmarkRepository interface {
+	// Add bookmarks a startup that exists and is not deleted. added is false when the
+	// user had already bookmarked it.
+	Add(ctx context.Context, userUUID string, startupID int64) (added bool, err error)
+	Remove(ctx context.Context, userUUID string, startupID int64) error
+	StartupExists(ctx context.Context, startupID int64) (bool, error)
+	ListByUser(ctx context.Context, userUUID string, limit, offset int) ([]Bookmark, int64, error)
+	// Followers returns the users who bookmarked a startup, and its name.
+	Followers(ctx context.Context, startupID int64) (name string, userUUIDs []string, err error)
+}
+
+type postgresBookmarkRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresBookmarkRepository(pool *pgxpool.Pool) BookmarkRepository {
+	return &postgresBookmarkRepository{pool: pool}
+}
+
+func (r *postgresBookmarkRepository) Add(ctx context.Context, userUUID string, startupID int64) (bool, error) {
+	query := `INSERT INTO startup_bookmarks (user_uuid, startup_id) VALUES ($1, $2)
+			  ON CONFLICT (user_uuid, startup_id) DO NOTHING`
+	cmd, err := r.pool.Exec(ctx, query, userUUID, startupID)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+func (r *postgresBookmarkRepository) Remove(ctx context.Context, userUUID string, startupID int64) error {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM startup_bookmarks WHERE user_uuid = $1 AND startup_id = $2`, userUUID, startupID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+func (r *postgresBookmarkRepository) StartupExists(ctx context.Context, startupID int64) (bool, error) {
+	var ok bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM startups WHERE id = $1 AND is_deleted = false)`, startupID).Scan(&ok)
+	return ok, err
+}
+
+// ListByUser leaves out startups deleted since they were bookmarked.
+func (r *postgresBookmarkRepository) ListByUser(ctx context.Context, userUUID string, limit, offset int) ([]Bookmark, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM startup_bookmarks b
+				   JOIN startups s ON s.id = b.startup_id AND s.is_deleted = false
+				   WHERE b.user_uuid = $1`
+	if err := r.pool.QueryRow(ctx, countQuery, userUUID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT s.id, s.name, COALESCE(s.logo_url, ''), s.status, b.created_at
+			  FROM startup_bookmarks b
+			  JOIN startups s ON s.id = b.startup_id AND s.is_deleted = false
+			  WHERE b.user_uuid = $1
+			  ORDER BY b.created_at DESC, s.id DESC
+			  LIMIT $2 OFFSET $3`
+	rows, err := r.pool.Query(ctx, query, userUUID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	list := []Bookmark{}
+	for rows.Next() {
+		var b Bookmark
+		if err := rows.Scan(&b.StartupID, &b.Name, &b.LogoURL, &b.Status, &b.BookmarkedAt); err != nil {
+			return nil, 0, err
+		}
+		list = append(list, b)
+	}
+	return list, total, rows.Err()
+}
+
+func (r *postgresBookmarkRepository) Followers(ctx context.Context, startupID int64) (string, []string, error) {
+	query := `SELECT s.name, b.user_uuid
+			  FROM startup_bookmarks b
+			  JOIN startups s ON s.id = b.startup_id
+			  WHERE b.startup_id = $1 AND b.user_uuid <> s.owner_uuid`
+	rows, err := r.pool.Query(ctx, query, startupID)
+	if err != nil {
+		return "", nil, err
+	}
+	defer rows.Close()
+
+	var name string
+	var uuids []string
+	for rows.Next() {
+		var uuid string
+		if err := rows.Scan(&name, &uuid); err != nil {
+			return "", nil, err
+		}
+		uuids = append(uuids, uuid)
+	}
+	return name, uuids, rows.Err()
+}
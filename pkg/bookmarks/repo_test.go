@@ -0,0 +1,49 @@
+package bookmarks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresBookmarkRepository(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresBookmarkRepository(pool)
+	ctx := context.Background()
+	owner := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	buyer := testhelpers.NewUser(t, pool)
+	startup := testhelpers.NewStartup(t, pool, testhelpers.WithStartupOwner(owner.UUID))
+	deleted := testhelpers.NewStartup(t, pool, testhelpers.WithStartupDeleted())
+
+	added, err := repo.Add(ctx, buyer.UUID, startup.ID)
+	require.NoError(t, err)
+	require.True(t, added)
+	added, err = repo.Add(ctx, buyer.UUID, startup.ID)
+	require.NoError(t, err)
+	require.False(t, added)
+	_, err = repo.Add(ctx, owner.UUID, startup.ID)
+	require.NoError(t, err)
+	_, err = repo.Add(ctx, buyer.UUID, deleted.ID)
+	require.NoError(t, err)
+
+	list, total, err := repo.ListByUser(ctx, buyer.UUID, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, startup.ID, list[0].StartupID)
+
+	// The owner is never notified about their own startup
+	name, followers, err := repo.Followers(ctx, startup.ID)
+	require.NoError(t, err)
+	require.Equal(t, startup.Name, name)
+	require.Equal(t, []string{buyer.UUID}, followers)
+
+	require.NoError(t, repo.Remove(ctx, buyer.UUID, startup.ID))
+	require.ErrorIs(t, repo.Remove(ctx, buyer.UUID, startup.ID), ErrBookmarkNotFound)
+}
@@ -0,0 +1,74 @@
+package bookmarks
+
+import (
+	"context"
+	"log"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/notifications"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/requestid"
+)
+
+var (
+	ErrStartupNotFound  = apperr.New(apperr.StartupNotFound, "startup not found")
+	ErrBookmarkNotFound = apperr.New(apperr.BookmarkNotFound, "bookmark not found")
+)
+
+// Notifier is what the startup and buy services depend on to tell followers that a
+// startup changed status.
+type Notifier interface {
+	StatusChanged(ctx context.Context, startupID int64)
+}
+
+type Service struct {
+	repo      BookmarkRepository
+	publisher notifications.Publisher // optional; if nil, followers are not notified
+}
+
+func NewService(repo BookmarkRepository, publisher notifications.Publisher) *Service {
+	return &Service{repo: repo, publisher: publisher}
+}
+
+// Add bookmarks startupID for userUUID. Bookmarking the same startup twice is not an
+// error; added reports whether a new bookmark was stored.
+func (s *Service) Add(ctx context.Context, userUUID string, startupID int64) (bool, error) {
+	ok, err := s.repo.StartupExists(ctx, startupID)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, ErrStartupNotFound
+	}
+	return s.repo.Add(ctx, userUUID, startupID)
+}
+
+func (s *Service) Remove(ctx context.Context, userUUID string, startupID int64) error {
+	return s.repo.Remove(ctx, userUUID, startupID)
+}
+
+// List returns the startups userUUID bookmarked, most recent first.
+func (s *Service) List(ctx context.Context, userUUID string, p pagination.Params) ([]Bookmark, int64, error) {
+	return s.repo.ListByUser(ctx, userUUID, p.Limit, p.Offset())
+}
+
+// StatusChanged notifies everyone who bookmarked startupID, except its owner. It is
+// best effort: the new status is already saved, so failures are only logged.
+func (s *Service) StatusChanged(ctx context.Context, startupID int64) {
+	if s.publisher == nil {
+		return
+	}
+	name, followers, err := s.repo.Followers(ctx, startupID)
+	if err != nil {
+		log.Printf("[%s] load followers of startup %d failed: %v", requestid.FromContext(ctx), startupID, err)
+		return
+	}
+	for _, uuid := range followers {
+		s.publisher.Publish(ctx, notifications.Event{
+			Type:          notifications.EventBookmarkUpdated,
+			RecipientUUID: uuid,
+			EntityID:      startupID,
+			Title:         name,
+		})
+	}
+}
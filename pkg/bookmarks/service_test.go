@@ -0,0 +1,74 @@
+package bookmarks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/notifications"
+)
+
+type mockBookmarkRepository struct {
+	mock.Mock
+}
+
+func (m *mockBookmarkRepository) Add(ctx context.Context, userUUID string, startupID int64) (bool, error) {
+	args := m.Called(ctx, userUUID, startupID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockBookmarkRepository) Remove(ctx context.Context, userUUID string, startupID int64) error {
+	args := m.Called(ctx, userUUID, startupID)
+	return args.Error(0)
+}
+
+func (m *mockBookmarkRepository) StartupExists(ctx context.Context, startupID int64) (bool, error) {
+	args := m.Called(ctx, startupID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockBookmarkRepository) ListByUser(ctx context.Context, userUUID string, limit, offset int) ([]Bookmark, int64, error) {
+	args := m.Called(ctx, userUUID, limit, offset)
+	list, _ := args.Get(0).([]Bookmark)
+	return list, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockBookmarkRepository) Followers(ctx context.Context, startupID int64) (string, []string, error) {
+	args := m.Called(ctx, startupID)
+	uuids, _ := args.Get(1).([]string)
+	return args.String(0), uuids, args.Error(2)
+}
+
+type mockPublisher struct {
+	events []notifications.Event
+}
+
+func (p *mockPublisher) Publish(ctx context.Context, ev notifications.Event) {
+	p.events = append(p.events, ev)
+}
+
+func TestService_Add_UnknownStartup(t *testing.T) {
+	repo := new(mockBookmarkRepository)
+	repo.On("StartupExists", mock.Anything, int64(9)).Return(false, nil)
+
+	_, err := NewService(repo, nil).Add(context.Background(), "u1", 9)
+
+	require.ErrorIs(t, err, ErrStartupNotFound)
+	repo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_StatusChanged_NotifiesFollowers(t *testing.T) {
+	repo := new(mockBookmarkRepository)
+	repo.On("Followers", mock.Anything, int64(4)).Return("Old App", []string{"u1", "u2"}, nil)
+	pub := &mockPublisher{}
+
+	NewService(repo, pub).StatusChanged(context.Background(), 4)
+
+	require.Len(t, pub.events, 2)
+	require.Equal(t, notifications.EventBookmarkUpdated, pub.events[0].Type)
+	require.Equal(t, "u2", pub.events[1].RecipientUUID)
+	require.Equal(t, int64(4), pub.events[1].EntityID)
+	require.Equal(t, "Old App", pub.events[1].Title)
+}
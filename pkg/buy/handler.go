@@ -6,7 +6,11 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
 	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
 )
 
 type BuyHandler struct {
@@ -18,132 +22,163 @@ func NewBuyHandler(service BuyService) *BuyHandler {
 }
 
 func (h *BuyHandler) RegisterRoutes(router *gin.Engine) {
-	router.PATCH("/assets/:id/mark-sold", h.markAssetSold)
-	router.PATCH("/assets/:id/unlist", h.unlistAsset)
-	router.PATCH("/startups/:id/mark-sold", h.markStartupSold)
-	router.PATCH("/startups/:id/unlist", h.unlistStartup)
+	router.PATCH("/assets/:id/mark-sold", auth.Required(), h.markAssetSold)
+	router.PATCH("/assets/:id/unlist", auth.Required(), h.unlistAsset)
+	router.PATCH("/startups/:id/mark-sold", auth.Required(), h.markStartupSold)
+	router.PATCH("/startups/:id/unlist", auth.Required(), h.unlistStartup)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *BuyHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPatch,
+			Path:        "/assets/:id/mark-sold",
+			Tag:         "buy",
+			Summary:     "Mark asset as sold",
+			Description: "Marks an asset as sold (sets is_sold to true). Fails if asset is already sold or inactive. With an optional body naming the buyer and final price, the sale is also recorded as a transaction together with the GST/VAT owed under the TAX_RULES for the seller's and buyer's countries, and the buyer is emailed a receipt in their language with the invoice number. If the asset has an agreement attached, the buyer must have signed its current version (403 otherwise). Only the owner or an admin may mark it sold.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Request:      Sale{},
+			OptionalBody: true,
+			Errors:       []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+			Auth:         true,
+		},
+		{
+			Method:      http.MethodPatch,
+			Path:        "/assets/:id/unlist",
+			Tag:         "buy",
+			Summary:     "Unlist an asset",
+			Description: "Soft deletes an asset by setting is_active to false. Asset won't appear in marketplace listings. Only the owner or an admin may unlist it.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+		{
+			Method:      http.MethodPatch,
+			Path:        "/startups/:id/mark-sold",
+			Tag:         "buy",
+			Summary:     "Mark startup as sold",
+			Description: "Marks a startup as sold (sets status to 'sold'). Fails if startup is already sold. Only the owner or an admin may mark it sold; team editors may not.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Startup ID"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+			Auth:   true,
+		},
+		{
+			Method:      http.MethodPatch,
+			Path:        "/startups/:id/unlist",
+			Tag:         "buy",
+			Summary:     "Unlist a startup",
+			Description: "Unlists a startup by setting status to 'failed'. Startup won't be prominently displayed. Only the owner or an admin may unlist it.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Startup ID"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+	}
+}
+
+// anyOwner reports whether the caller may sell or unlist listings they do not own.
+func anyOwner(c *gin.Context) bool {
+	return auth.Can(auth.Role(c), auth.PermDeleteListings)
 }
 
-// @Summary      Mark asset as sold
-// @Description  Marks an asset as sold (sets is_sold to true). Fails if asset is already sold or inactive.
-// @Tags         buy
-// @Produce      json
-// @Param        id   path      int  true  "Asset ID"
-// @Success      200  {object}  response.APIResponse "Asset marked as sold successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid asset ID"
-// @Failure      404  {object}  response.APIResponse "Asset not found"
-// @Failure      409  {object}  response.APIResponse "Asset already marked as sold"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /assets/{id}/mark-sold [patch]
 func (h *BuyHandler) markAssetSold(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || id <= 0 {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid asset id", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid asset id"))
 		return
 	}
 
-	if err := h.service.MarkAssetSold(c.Request.Context(), id); err != nil {
+	var sale *Sale
+	if c.Request.ContentLength != 0 {
+		sale = &Sale{}
+		if !validation.BindJSON(c, sale) {
+			return
+		}
+	}
+
+	if err := h.service.MarkAssetSold(c.Request.Context(), id, auth.UserID(c), anyOwner(c), sale); err != nil {
 		if err == ErrNotFound {
-			response.SendAPIResponse(c, http.StatusNotFound, false, "asset not found", nil)
+			response.SendError(c, apperr.New(apperr.AssetNotFound, "asset not found"))
+			return
+		}
+		if err == ErrBuyerNotFound {
+			response.SendError(c, apperr.New(apperr.UserNotFound, "buyer not found"))
 			return
 		}
 		if err == ErrAlreadySold {
-			response.SendAPIResponse(c, http.StatusConflict, false, "asset already marked as sold", nil)
+			response.SendError(c, apperr.New(apperr.AlreadySold, "asset already marked as sold"))
 			return
 		}
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
 
 	response.SendAPIResponse(c, http.StatusOK, true, "asset marked as sold", nil)
 }
 
-// @Summary      Unlist an asset
-// @Description  Soft deletes an asset by setting is_active to false. Asset won't appear in marketplace listings.
-// @Tags         buy
-// @Produce      json
-// @Param        id   path      int  true  "Asset ID"
-// @Success      200  {object}  response.APIResponse "Asset unlisted successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid asset ID"
-// @Failure      404  {object}  response.APIResponse "Asset not found"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /assets/{id}/unlist [patch]
 func (h *BuyHandler) unlistAsset(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || id <= 0 {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid asset id", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid asset id"))
 		return
 	}
 
-	if err := h.service.UnlistAsset(c.Request.Context(), id); err != nil {
+	if err := h.service.UnlistAsset(c.Request.Context(), id, auth.UserID(c), anyOwner(c)); err != nil {
 		if err == ErrNotFound {
-			response.SendAPIResponse(c, http.StatusNotFound, false, "asset not found", nil)
+			response.SendError(c, apperr.New(apperr.AssetNotFound, "asset not found"))
 			return
 		}
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
 
 	response.SendAPIResponse(c, http.StatusOK, true, "asset unlisted", nil)
 }
 
-// @Summary      Mark startup as sold
-// @Description  Marks a startup as sold (sets status to 'sold'). Fails if startup is already sold.
-// @Tags         buy
-// @Produce      json
-// @Param        id   path      int  true  "Startup ID"
-// @Success      200  {object}  response.APIResponse "Startup marked as sold successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid startup ID"
-// @Failure      404  {object}  response.APIResponse "Startup not found"
-// @Failure      409  {object}  response.APIResponse "Startup already marked as sold"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /startups/{id}/mark-sold [patch]
 func (h *BuyHandler) markStartupSold(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || id <= 0 {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid startup id", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
 		return
 	}
 
-	if err := h.service.MarkStartupSold(c.Request.Context(), id); err != nil {
+	if err := h.service.MarkStartupSold(c.Request.Context(), id, auth.UserID(c), anyOwner(c)); err != nil {
 		if err == ErrNotFound {
-			response.SendAPIResponse(c, http.StatusNotFound, false, "startup not found", nil)
+			response.SendError(c, apperr.New(apperr.StartupNotFound, "startup not found"))
 			return
 		}
 		if err == ErrAlreadySold {
-			response.SendAPIResponse(c, http.StatusConflict, false, "startup already marked as sold", nil)
+			response.SendError(c, apperr.New(apperr.AlreadySold, "startup already marked as sold"))
 			return
 		}
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
 
 	response.SendAPIResponse(c, http.StatusOK, true, "startup marked as sold", nil)
 }
 
-// @Summary      Unlist a startup
-// @Description  Unlists a startup by setting status to 'failed'. Startup won't be prominently displayed.
-// @Tags         buy
-// @Produce      json
-// @Param        id   path      int  true  "Startup ID"
-// @Success      200  {object}  response.APIResponse "Startup unlisted successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid startup ID"
-// @Failure      404  {object}  response.APIResponse "Startup not found"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /startups/{id}/unlist [patch]
 func (h *BuyHandler) unlistStartup(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || id <= 0 {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid startup id", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
 		return
 	}
 
-	if err := h.service.UnlistStartup(c.Request.Context(), id); err != nil {
+	if err := h.service.UnlistStartup(c.Request.Context(), id, auth.UserID(c), anyOwner(c)); err != nil {
 		if err == ErrNotFound {
-			response.SendAPIResponse(c, http.StatusNotFound, false, "startup not found", nil)
+			response.SendError(c, apperr.New(apperr.StartupNotFound, "startup not found"))
 			return
 		}
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
 
@@ -5,42 +5,46 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"grveyard/pkg/apperr"
 	"grveyard/pkg/response"
+	"grveyard/pkg/testhelpers"
 )
 
 type mockBuyService struct {
 	mock.Mock
 }
 
-func (m *mockBuyService) MarkAssetSold(ctx context.Context, assetID int64) error {
-	args := m.Called(ctx, assetID)
+func (m *mockBuyService) MarkAssetSold(ctx context.Context, assetID int64, userUUID string, anyOwner bool, sale *Sale) error {
+	args := m.Called(ctx, assetID, userUUID, anyOwner, sale)
 	return args.Error(0)
 }
 
-func (m *mockBuyService) UnlistAsset(ctx context.Context, assetID int64) error {
-	args := m.Called(ctx, assetID)
+func (m *mockBuyService) UnlistAsset(ctx context.Context, assetID int64, userUUID string, anyOwner bool) error {
+	args := m.Called(ctx, assetID, userUUID, anyOwner)
 	return args.Error(0)
 }
 
-func (m *mockBuyService) MarkStartupSold(ctx context.Context, startupID int64) error {
-	args := m.Called(ctx, startupID)
+func (m *mockBuyService) MarkStartupSold(ctx context.Context, startupID int64, userUUID string, anyOwner bool) error {
+	args := m.Called(ctx, startupID, userUUID, anyOwner)
 	return args.Error(0)
 }
 
-func (m *mockBuyService) UnlistStartup(ctx context.Context, startupID int64) error {
-	args := m.Called(ctx, startupID)
+func (m *mockBuyService) UnlistStartup(ctx context.Context, startupID int64, userUUID string, anyOwner bool) error {
+	args := m.Called(ctx, startupID, userUUID, anyOwner)
 	return args.Error(0)
 }
 
 func setupBuyRouter(service BuyService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
+	r.Use(testhelpers.AuthAs("seller-uuid", "founder"))
 	h := NewBuyHandler(service)
 	h.RegisterRoutes(r)
 	return r
@@ -50,7 +54,7 @@ func TestBuyHandler_MarkAssetSold_Success(t *testing.T) {
 	svc := new(mockBuyService)
 	r := setupBuyRouter(svc)
 
-	svc.On("MarkAssetSold", mock.Anything, int64(1)).Return(nil)
+	svc.On("MarkAssetSold", mock.Anything, int64(1), "seller-uuid", false, (*Sale)(nil)).Return(nil)
 
 	req := httptest.NewRequest(http.MethodPatch, "/assets/1/mark-sold", nil)
 	w := httptest.NewRecorder()
@@ -70,7 +74,7 @@ func TestBuyHandler_MarkAssetSold_AlreadySold(t *testing.T) {
 	svc := new(mockBuyService)
 	r := setupBuyRouter(svc)
 
-	svc.On("MarkAssetSold", mock.Anything, int64(1)).Return(ErrAlreadySold)
+	svc.On("MarkAssetSold", mock.Anything, int64(1), "seller-uuid", false, (*Sale)(nil)).Return(ErrAlreadySold)
 
 	req := httptest.NewRequest(http.MethodPatch, "/assets/1/mark-sold", nil)
 	w := httptest.NewRecorder()
@@ -86,11 +90,60 @@ func TestBuyHandler_MarkAssetSold_AlreadySold(t *testing.T) {
 	svc.AssertExpectations(t)
 }
 
+func TestBuyHandler_MarkAssetSold_WithSale(t *testing.T) {
+	svc := new(mockBuyService)
+	r := setupBuyRouter(svc)
+
+	svc.On("MarkAssetSold", mock.Anything, int64(1), "seller-uuid", false, &Sale{BuyerUUID: "buyer-uuid", FinalPrice: 2500}).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/assets/1/mark-sold", strings.NewReader(`{"buyer_uuid":"buyer-uuid","final_price":2500}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestBuyHandler_MarkAssetSold_UnknownBuyer(t *testing.T) {
+	svc := new(mockBuyService)
+	r := setupBuyRouter(svc)
+
+	svc.On("MarkAssetSold", mock.Anything, int64(1), "seller-uuid", false, mock.Anything).Return(ErrBuyerNotFound)
+
+	req := httptest.NewRequest(http.MethodPatch, "/assets/1/mark-sold", strings.NewReader(`{"buyer_uuid":"ghost","final_price":10}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	var resp response.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, apperr.UserNotFound, resp.ErrorCode)
+	require.Equal(t, "buyer not found", resp.Message)
+}
+
+func TestBuyHandler_MarkAssetSold_InvalidSale(t *testing.T) {
+	svc := new(mockBuyService)
+	r := setupBuyRouter(svc)
+
+	req := httptest.NewRequest(http.MethodPatch, "/assets/1/mark-sold", strings.NewReader(`{"final_price":-5}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	svc.AssertNotCalled(t, "MarkAssetSold", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestBuyHandler_UnlistAsset_NotFound(t *testing.T) {
 	svc := new(mockBuyService)
 	r := setupBuyRouter(svc)
 
-	svc.On("UnlistAsset", mock.Anything, int64(2)).Return(ErrNotFound)
+	svc.On("UnlistAsset", mock.Anything, int64(2), "seller-uuid", false).Return(ErrNotFound)
 
 	req := httptest.NewRequest(http.MethodPatch, "/assets/2/unlist", nil)
 	w := httptest.NewRecorder()
@@ -110,7 +163,7 @@ func TestBuyHandler_MarkStartupSold_NotFound(t *testing.T) {
 	svc := new(mockBuyService)
 	r := setupBuyRouter(svc)
 
-	svc.On("MarkStartupSold", mock.Anything, int64(3)).Return(ErrNotFound)
+	svc.On("MarkStartupSold", mock.Anything, int64(3), "seller-uuid", false).Return(ErrNotFound)
 
 	req := httptest.NewRequest(http.MethodPatch, "/startups/3/mark-sold", nil)
 	w := httptest.NewRecorder()
@@ -141,5 +194,44 @@ func TestBuyHandler_UnlistStartup_InvalidID(t *testing.T) {
 	require.False(t, resp.Success)
 	require.Equal(t, "invalid startup id", resp.Message)
 
-	svc.AssertNotCalled(t, "UnlistStartup", mock.Anything, mock.Anything)
+	svc.AssertNotCalled(t, "UnlistStartup", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBuyHandler_NonOwnerForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := newOwnedRepository()
+	repos := &mockRepoAccess{}
+	r := gin.New()
+	r.Use(testhelpers.AuthAs("seller-uuid", "founder"))
+	NewBuyHandler(NewBuyService(repo, nil, nil, nil, nil, nil, repos, nil)).RegisterRoutes(r)
+
+	for _, path := range []string{"/assets/1/mark-sold", "/assets/1/unlist", "/startups/2/mark-sold", "/startups/2/unlist"} {
+		req := httptest.NewRequest(http.MethodPatch, path, strings.NewReader(`{"buyer_uuid":"seller-uuid","final_price":1,"github_username":"seller"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusForbidden, w.Code, path)
+		var resp response.APIResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Equal(t, apperr.Forbidden, resp.ErrorCode)
+	}
+	repo.AssertNotCalled(t, "SellAsset", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.Empty(t, repos.granted)
+}
+
+func TestBuyHandler_AdminMarksAnyAssetSold(t *testing.T) {
+	svc := new(mockBuyService)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(testhelpers.AuthAs("admin-uuid", "admin"))
+	NewBuyHandler(svc).RegisterRoutes(r)
+
+	svc.On("MarkAssetSold", mock.Anything, int64(1), "admin-uuid", true, (*Sale)(nil)).Return(nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/assets/1/mark-sold", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
 }
@@ -6,14 +6,26 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/tax"
 )
 
 var (
 	ErrNotFound      = errors.New("resource not found")
 	ErrAlreadySold   = errors.New("already marked as sold")
 	ErrInvalidEntity = errors.New("invalid entity type")
+	ErrBuyerNotFound = errors.New("buyer not found")
 )
 
+// Sale is the buyer and agreed price of an asset, recorded as a transaction when the
+// asset is marked sold.
+type Sale struct {
+	BuyerUUID  string  `json:"buyer_uuid" binding:"required"`
+	FinalPrice float64 `json:"final_price" binding:"gte=0"`
+	// GitHubUsername is invited to the asset's linked repository, if it has one
+	GitHubUsername string `json:"github_username,omitempty" binding:"omitempty,max=39"`
+}
+
 type BuyRepository interface {
 	MarkAssetSold(ctx context.Context, assetID int64) error
 	UnlistAsset(ctx context.Context, assetID int64) error
@@ -21,6 +33,13 @@ type BuyRepository interface {
 	UnlistStartup(ctx context.Context, startupID int64) error
 	GetAssetStatus(ctx context.Context, assetID int64) (bool, bool, error)
 	GetStartupStatus(ctx context.Context, startupID int64) (string, error)
+	GetAssetOwner(ctx context.Context, assetID int64) (string, string, error)
+	GetStartupOwner(ctx context.Context, startupID int64) (string, error)
+	// GetSaleCountries returns the seller's and buyer's countries for tax purposes.
+	GetSaleCountries(ctx context.Context, assetID int64, buyerUUID string) (string, string, error)
+	// SellAsset marks the asset sold and records the transaction with its tax
+	// breakdown, atomically, returning the transaction ID.
+	SellAsset(ctx context.Context, assetID int64, buyerUUID string, b tax.Breakdown) (int64, error)
 }
 
 type postgresBuyRepository struct {
@@ -32,7 +51,7 @@ func NewPostgresBuyRepository(pool *pgxpool.Pool) BuyRepository {
 }
 
 func (r *postgresBuyRepository) MarkAssetSold(ctx context.Context, assetID int64) error {
-	query := `UPDATE assets SET is_sold = true WHERE id = $1 AND is_active = true`
+	query := `UPDATE assets SET is_sold = true, sold_at = NOW(), updated_at = NOW() WHERE id = $1 AND is_active = true`
 	cmd, err := r.pool.Exec(ctx, query, assetID)
 	if err != nil {
 		return err
@@ -44,7 +63,7 @@ func (r *postgresBuyRepository) MarkAssetSold(ctx context.Context, assetID int64
 }
 
 func (r *postgresBuyRepository) UnlistAsset(ctx context.Context, assetID int64) error {
-	query := `UPDATE assets SET is_active = false WHERE id = $1`
+	query := `UPDATE assets SET is_active = false, updated_at = NOW() WHERE id = $1`
 	cmd, err := r.pool.Exec(ctx, query, assetID)
 	if err != nil {
 		return err
@@ -56,7 +75,7 @@ func (r *postgresBuyRepository) UnlistAsset(ctx context.Context, assetID int64)
 }
 
 func (r *postgresBuyRepository) MarkStartupSold(ctx context.Context, startupID int64) error {
-	query := `UPDATE startups SET status = 'sold' WHERE id = $1`
+	query := `UPDATE startups SET status = 'sold', sold_at = NOW(), updated_at = NOW() WHERE id = $1`
 	cmd, err := r.pool.Exec(ctx, query, startupID)
 	if err != nil {
 		return err
@@ -68,7 +87,7 @@ func (r *postgresBuyRepository) MarkStartupSold(ctx context.Context, startupID i
 }
 
 func (r *postgresBuyRepository) UnlistStartup(ctx context.Context, startupID int64) error {
-	query := `UPDATE startups SET status = 'failed' WHERE id = $1`
+	query := `UPDATE startups SET status = 'failed', updated_at = NOW() WHERE id = $1`
 	cmd, err := r.pool.Exec(ctx, query, startupID)
 	if err != nil {
 		return err
@@ -108,3 +127,89 @@ func (r *postgresBuyRepository) GetStartupStatus(ctx context.Context, startupID
 
 	return status, nil
 }
+
+func (r *postgresBuyRepository) GetAssetOwner(ctx context.Context, assetID int64) (string, string, error) {
+	query := `SELECT user_uuid, title FROM assets WHERE id = $1`
+	row := r.pool.QueryRow(ctx, query, assetID)
+
+	var ownerUUID, title string
+	if err := row.Scan(&ownerUUID, &title); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", "", ErrNotFound
+		}
+		return "", "", err
+	}
+
+	return ownerUUID, title, nil
+}
+
+func (r *postgresBuyRepository) GetStartupOwner(ctx context.Context, startupID int64) (string, error) {
+	query := `SELECT owner_uuid FROM startups WHERE id = $1`
+	row := r.pool.QueryRow(ctx, query, startupID)
+
+	var ownerUUID string
+	if err := row.Scan(&ownerUUID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+
+	return ownerUUID, nil
+}
+
+// GetSaleCountries uses the asset's own country as the seller's, falling back to the
+// owner's profile country when the listing has none.
+func (r *postgresBuyRepository) GetSaleCountries(ctx context.Context, assetID int64, buyerUUID string) (string, string, error) {
+	query := `
+		SELECT COALESCE(NULLIF(a.country, ''), o.country, ''),
+			(SELECT b.country FROM users b WHERE b.uuid = $2 AND b.is_deleted = false)
+		FROM assets a
+		LEFT JOIN users o ON o.uuid = a.user_uuid
+		WHERE a.id = $1`
+
+	var seller string
+	var buyer *string
+	if err := r.pool.QueryRow(ctx, query, assetID, buyerUUID).Scan(&seller, &buyer); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", "", ErrNotFound
+		}
+		return "", "", err
+	}
+	if buyer == nil {
+		return "", "", ErrBuyerNotFound
+	}
+	return seller, *buyer, nil
+}
+
+func (r *postgresBuyRepository) SellAsset(ctx context.Context, assetID int64, buyerUUID string, b tax.Breakdown) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	cmd, err := tx.Exec(ctx, `UPDATE assets SET is_sold = true, sold_at = NOW(), updated_at = NOW() WHERE id = $1 AND is_active = true`, assetID)
+	if err != nil {
+		return 0, err
+	}
+	if cmd.RowsAffected() == 0 {
+		return 0, ErrNotFound
+	}
+
+	var id int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO transactions (asset_id, buyer_id, final_price, seller_country, buyer_country, tax_name, tax_rate, tax_amount)
+		SELECT $1, u.id, $3, $4, $5, $6, $7, $8
+		FROM users u
+		WHERE u.uuid = $2 AND u.is_deleted = false
+		RETURNING id`,
+		assetID, buyerUUID, b.Net, b.SellerCountry, b.BuyerCountry, b.Name, b.Rate, b.Tax).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrBuyerNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return id, tx.Commit(ctx)
+}
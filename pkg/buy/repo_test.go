@@ -2,45 +2,26 @@ package buy
 
 import (
 	"context"
-	"os"
 	"testing"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/require"
 
+	"grveyard/pkg/tax"
 	"grveyard/pkg/testhelpers"
 )
 
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
 func setupBuyTestPool(t *testing.T) *pgxpool.Pool {
 	t.Helper()
 
-	dsn := os.Getenv("DATABASE_URL_FOR_TEST")
-	if dsn == "" {
-		t.Skip("DATABASE_URL_FOR_TEST not set; skipping buy repository tests")
-	}
-
-	ctx := context.Background()
-	cfg, err := pgxpool.ParseConfig(dsn)
-	require.NoError(t, err)
-
-	pool, err := pgxpool.NewWithConfig(ctx, cfg)
-	require.NoError(t, err)
-	require.NoError(t, pool.Ping(ctx))
-
-	t.Cleanup(pool.Close)
-	return pool
-}
-
-func cleanBuyTables(t *testing.T, pool *pgxpool.Pool) {
-	t.Helper()
-	ctx := context.Background()
-	_, err := pool.Exec(ctx, "TRUNCATE TABLE messages, chats, assets, startups, users RESTART IDENTITY CASCADE")
-	require.NoError(t, err)
+	return testhelpers.Pool(t)
 }
 
 func TestPostgresBuyRepository_MarkAssetSold(t *testing.T) {
+	t.Parallel()
 	pool := setupBuyTestPool(t)
-	// cleanBuyTables(t, pool)
 
 	repo := NewPostgresBuyRepository(pool)
 	ctx := context.Background()
@@ -56,8 +37,8 @@ func TestPostgresBuyRepository_MarkAssetSold(t *testing.T) {
 }
 
 func TestPostgresBuyRepository_UnlistAsset(t *testing.T) {
+	t.Parallel()
 	pool := setupBuyTestPool(t)
-	// cleanBuyTables(t, pool)
 
 	repo := NewPostgresBuyRepository(pool)
 	ctx := context.Background()
@@ -72,8 +53,8 @@ func TestPostgresBuyRepository_UnlistAsset(t *testing.T) {
 }
 
 func TestPostgresBuyRepository_MarkStartupSold(t *testing.T) {
+	t.Parallel()
 	pool := setupBuyTestPool(t)
-	// cleanBuyTables(t, pool)
 
 	repo := NewPostgresBuyRepository(pool)
 	ctx := context.Background()
@@ -88,8 +69,8 @@ func TestPostgresBuyRepository_MarkStartupSold(t *testing.T) {
 }
 
 func TestPostgresBuyRepository_UnlistStartup(t *testing.T) {
+	t.Parallel()
 	pool := setupBuyTestPool(t)
-	// cleanBuyTables(t, pool)
 
 	repo := NewPostgresBuyRepository(pool)
 	ctx := context.Background()
@@ -103,9 +84,26 @@ func TestPostgresBuyRepository_UnlistStartup(t *testing.T) {
 	require.Equal(t, "failed", status)
 }
 
+func TestPostgresBuyRepository_GetStartupOwner(t *testing.T) {
+	t.Parallel()
+	pool := setupBuyTestPool(t)
+
+	repo := NewPostgresBuyRepository(pool)
+	ctx := context.Background()
+	ownerUUID := testhelpers.CreateTestUser(t, pool)
+	sid := testhelpers.CreateTestStartup(t, pool, ownerUUID)
+
+	owner, err := repo.GetStartupOwner(ctx, int64(sid))
+	require.NoError(t, err)
+	require.Equal(t, ownerUUID, owner)
+
+	_, err = repo.GetStartupOwner(ctx, 999999)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
 func TestPostgresBuyRepository_GetAssetStatus_NotFound(t *testing.T) {
+	t.Parallel()
 	pool := setupBuyTestPool(t)
-	// cleanBuyTables(t, pool)
 
 	repo := NewPostgresBuyRepository(pool)
 	ctx := context.Background()
@@ -116,8 +114,8 @@ func TestPostgresBuyRepository_GetAssetStatus_NotFound(t *testing.T) {
 }
 
 func TestPostgresBuyRepository_GetStartupStatus_NotFound(t *testing.T) {
+	t.Parallel()
 	pool := setupBuyTestPool(t)
-	// cleanBuyTables(t, pool)
 
 	repo := NewPostgresBuyRepository(pool)
 	ctx := context.Background()
@@ -126,3 +124,56 @@ func TestPostgresBuyRepository_GetStartupStatus_NotFound(t *testing.T) {
 
 	require.ErrorIs(t, err, ErrNotFound)
 }
+
+func TestPostgresBuyRepository_SellAsset(t *testing.T) {
+	t.Parallel()
+	pool := setupBuyTestPool(t)
+
+	repo := NewPostgresBuyRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	buyer := testhelpers.NewUser(t, pool)
+	asset := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID))
+	_, err := pool.Exec(ctx, `UPDATE users SET country = 'IN' WHERE uuid = $1 OR uuid = $2`, seller.UUID, buyer.UUID)
+	require.NoError(t, err)
+
+	sellerCountry, buyerCountry, err := repo.GetSaleCountries(ctx, asset.ID, buyer.UUID)
+	require.NoError(t, err)
+	require.Equal(t, "IN", sellerCountry)
+	require.Equal(t, "IN", buyerCountry)
+
+	b := tax.Breakdown{SellerCountry: "IN", BuyerCountry: "IN", Name: "GST", Rate: 18, Net: 500, Tax: 90, Gross: 590}
+	id, err := repo.SellAsset(ctx, asset.ID, buyer.UUID, b)
+	require.NoError(t, err)
+	require.NotZero(t, id)
+
+	sold, _, err := repo.GetAssetStatus(ctx, asset.ID)
+	require.NoError(t, err)
+	require.True(t, sold)
+
+	var name string
+	var amount float64
+	err = pool.QueryRow(ctx, `SELECT tax_name, tax_amount::float8 FROM transactions WHERE id = $1 AND asset_id = $2`, id, asset.ID).Scan(&name, &amount)
+	require.NoError(t, err)
+	require.Equal(t, "GST", name)
+	require.Equal(t, 90.0, amount)
+}
+
+func TestPostgresBuyRepository_SellAsset_UnknownBuyer(t *testing.T) {
+	t.Parallel()
+	pool := setupBuyTestPool(t)
+
+	repo := NewPostgresBuyRepository(pool)
+	ctx := context.Background()
+	asset := testhelpers.NewAsset(t, pool)
+
+	_, _, err := repo.GetSaleCountries(ctx, asset.ID, "no-such-user")
+	require.ErrorIs(t, err, ErrBuyerNotFound)
+
+	_, err = repo.SellAsset(ctx, asset.ID, "no-such-user", tax.Breakdown{Net: 10, Gross: 10})
+	require.ErrorIs(t, err, ErrBuyerNotFound)
+
+	sold, _, err := repo.GetAssetStatus(ctx, asset.ID)
+	require.NoError(t, err)
+	require.False(t, sold)
+}
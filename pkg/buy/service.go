@@ -1,23 +1,73 @@
 package buy
 
-import "context"
+import (
+	"context"
+	"log"
 
+	"grveyard/pkg/activity"
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/bookmarks"
+	"grveyard/pkg/favorites"
+	"grveyard/pkg/notifications"
+	"grveyard/pkg/requestid"
+	"grveyard/pkg/tax"
+)
+
+var ErrNotOwner = apperr.New(apperr.Forbidden, "only the owner can sell or unlist this listing")
+
+// AgreementGate refuses checkout until the buyer has signed the asset's agreement.
+type AgreementGate interface {
+	Require(ctx context.Context, assetID int64, userUUID string) error
+}
+
+// RepoAccess grants a buyer access to the repository linked to a codebase asset.
+type RepoAccess interface {
+	GrantBuyer(ctx context.Context, assetID int64, githubUsername string) error
+}
+
+// BuyService acts for userUUID, who must own the listing unless anyOwner is set;
+// otherwise every method fails with ErrNotOwner before changing anything.
 type BuyService interface {
-	MarkAssetSold(ctx context.Context, assetID int64) error
-	UnlistAsset(ctx context.Context, assetID int64) error
-	MarkStartupSold(ctx context.Context, startupID int64) error
-	UnlistStartup(ctx context.Context, startupID int64) error
+	// MarkAssetSold marks the asset sold. With a sale it also records the
+	// transaction, taxed by the rules for the seller's and buyer's countries.
+	MarkAssetSold(ctx context.Context, assetID int64, userUUID string, anyOwner bool, sale *Sale) error
+	UnlistAsset(ctx context.Context, assetID int64, userUUID string, anyOwner bool) error
+	MarkStartupSold(ctx context.Context, startupID int64, userUUID string, anyOwner bool) error
+	UnlistStartup(ctx context.Context, startupID int64, userUUID string, anyOwner bool) error
 }
 
 type buyService struct {
-	repo BuyRepository
+	repo      BuyRepository
+	publisher notifications.Publisher // optional
+	feed      activity.Recorder       // optional
+	followers bookmarks.Notifier      // optional
+	watchers  favorites.Notifier      // optional
+	taxes     tax.Rules               // empty charges no tax
+	repos     RepoAccess              // optional
+	contracts AgreementGate           // optional
+}
+
+func NewBuyService(repo BuyRepository, publisher notifications.Publisher, feed activity.Recorder, followers bookmarks.Notifier, watchers favorites.Notifier, taxes tax.Rules, repos RepoAccess, contracts AgreementGate) BuyService {
+	return &buyService{repo: repo, publisher: publisher, feed: feed, followers: followers, watchers: watchers, taxes: taxes, repos: repos, contracts: contracts}
 }
 
-func NewBuyService(repo BuyRepository) BuyService {
-	return &buyService{repo: repo}
+// checkOwner fails with ErrNotOwner unless userUUID owns the listing or anyOwner is set.
+func checkOwner(ownerUUID, userUUID string, anyOwner bool) error {
+	if anyOwner || ownerUUID == userUUID {
+		return nil
+	}
+	return ErrNotOwner
 }
 
-func (s *buyService) MarkAssetSold(ctx context.Context, assetID int64) error {
+func (s *buyService) MarkAssetSold(ctx context.Context, assetID int64, userUUID string, anyOwner bool, sale *Sale) error {
+	ownerUUID, title, err := s.repo.GetAssetOwner(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if err := checkOwner(ownerUUID, userUUID, anyOwner); err != nil {
+		return err
+	}
+
 	isSold, isActive, err := s.repo.GetAssetStatus(ctx, assetID)
 	if err != nil {
 		return err
@@ -31,14 +81,112 @@ func (s *buyService) MarkAssetSold(ctx context.Context, assetID int64) error {
 		return ErrAlreadySold
 	}
 
-	return s.repo.MarkAssetSold(ctx, assetID)
+	var sold *receipt
+	if sale == nil {
+		err = s.repo.MarkAssetSold(ctx, assetID)
+	} else {
+		sold, err = s.sellAsset(ctx, assetID, *sale)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.notifyAssetSold(ctx, assetID, ownerUUID, title, sold)
+	if s.watchers != nil {
+		s.watchers.AssetChanged(ctx, assetID)
+	}
+	if s.feed != nil {
+		s.feed.Record(ctx, activity.AssetSold, assetID)
+	}
+	return nil
+}
+
+// receipt is what the buyer's receipt email needs about a recorded sale.
+type receipt struct {
+	buyerUUID     string
+	transactionID int64
+	total         float64
+}
+
+func (s *buyService) sellAsset(ctx context.Context, assetID int64, sale Sale) (*receipt, error) {
+	if s.contracts != nil {
+		if err := s.contracts.Require(ctx, assetID, sale.BuyerUUID); err != nil {
+			return nil, err
+		}
+	}
+	seller, buyer, err := s.repo.GetSaleCountries(ctx, assetID, sale.BuyerUUID)
+	if err != nil {
+		return nil, err
+	}
+	b := s.taxes.Calculate(seller, buyer, sale.FinalPrice)
+	id, err := s.repo.SellAsset(ctx, assetID, sale.BuyerUUID, b)
+	if err != nil {
+		return nil, err
+	}
+	s.grantRepoAccess(ctx, assetID, sale.GitHubUsername)
+	return &receipt{buyerUUID: sale.BuyerUUID, transactionID: id, total: b.Gross}, nil
+}
+
+// grantRepoAccess invites the buyer to the asset's linked repository. The sale is
+// already recorded, so failures are only logged for the seller to follow up.
+func (s *buyService) grantRepoAccess(ctx context.Context, assetID int64, githubUsername string) {
+	if s.repos == nil || githubUsername == "" {
+		return
+	}
+	if err := s.repos.GrantBuyer(ctx, assetID, githubUsername); err != nil {
+		log.Printf("[%s] grant %s access to the repository of asset %d failed: %v", requestid.FromContext(ctx), githubUsername, assetID, err)
+	}
+}
+
+// notifyAssetSold tells the owner their asset sold and, when the sale was recorded,
+// sends the buyer their receipt. Failures never affect the sale.
+func (s *buyService) notifyAssetSold(ctx context.Context, assetID int64, ownerUUID, title string, sold *receipt) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(ctx, notifications.Event{
+		Type:          notifications.EventAssetSold,
+		RecipientUUID: ownerUUID,
+		EntityID:      assetID,
+		Title:         title,
+	})
+	if sold != nil {
+		s.publisher.Publish(ctx, notifications.Event{
+			Type:          notifications.EventPurchaseReceipt,
+			RecipientUUID: sold.buyerUUID,
+			ActorUUID:     ownerUUID,
+			EntityID:      sold.transactionID,
+			Title:         title,
+			Amount:        sold.total,
+		})
+	}
 }
 
-func (s *buyService) UnlistAsset(ctx context.Context, assetID int64) error {
+func (s *buyService) UnlistAsset(ctx context.Context, assetID int64, userUUID string, anyOwner bool) error {
+	ownerUUID, _, err := s.repo.GetAssetOwner(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if err := checkOwner(ownerUUID, userUUID, anyOwner); err != nil {
+		return err
+	}
 	return s.repo.UnlistAsset(ctx, assetID)
 }
 
-func (s *buyService) MarkStartupSold(ctx context.Context, startupID int64) error {
+// checkStartupOwner is checkOwner for a startup. Team editors do not count: only
+// the owner may sell or unlist it.
+func (s *buyService) checkStartupOwner(ctx context.Context, startupID int64, userUUID string, anyOwner bool) error {
+	ownerUUID, err := s.repo.GetStartupOwner(ctx, startupID)
+	if err != nil {
+		return err
+	}
+	return checkOwner(ownerUUID, userUUID, anyOwner)
+}
+
+func (s *buyService) MarkStartupSold(ctx context.Context, startupID int64, userUUID string, anyOwner bool) error {
+	if err := s.checkStartupOwner(ctx, startupID, userUUID, anyOwner); err != nil {
+		return err
+	}
 	status, err := s.repo.GetStartupStatus(ctx, startupID)
 	if err != nil {
 		return err
@@ -48,9 +196,35 @@ func (s *buyService) MarkStartupSold(ctx context.Context, startupID int64) error
 		return ErrAlreadySold
 	}
 
-	return s.repo.MarkStartupSold(ctx, startupID)
+	if err := s.repo.MarkStartupSold(ctx, startupID); err != nil {
+		return err
+	}
+	if s.feed != nil {
+		s.feed.Record(ctx, activity.StartupSold, startupID)
+	}
+	if s.followers != nil {
+		s.followers.StatusChanged(ctx, startupID)
+	}
+	return nil
 }
 
-func (s *buyService) UnlistStartup(ctx context.Context, startupID int64) error {
-	return s.repo.UnlistStartup(ctx, startupID)
+func (s *buyService) UnlistStartup(ctx context.Context, startupID int64, userUUID string, anyOwner bool) error {
+	if err := s.checkStartupOwner(ctx, startupID, userUUID, anyOwner); err != nil {
+		return err
+	}
+	if s.followers == nil {
+		return s.repo.UnlistStartup(ctx, startupID)
+	}
+
+	status, err := s.repo.GetStartupStatus(ctx, startupID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UnlistStartup(ctx, startupID); err != nil {
+		return err
+	}
+	if status != "failed" {
+		s.followers.StatusChanged(ctx, startupID)
+	}
+	return nil
 }
@@ -2,10 +2,15 @@ package buy
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/activity"
+	"grveyard/pkg/notifications"
+	"grveyard/pkg/tax"
 )
 
 type mockBuyRepository struct {
@@ -42,88 +47,357 @@ func (m *mockBuyRepository) GetStartupStatus(ctx context.Context, startupID int6
 	return args.String(0), args.Error(1)
 }
 
-func TestBuyService_MarkAssetSold_AlreadySold(t *testing.T) {
+func (m *mockBuyRepository) GetSaleCountries(ctx context.Context, assetID int64, buyerUUID string) (string, string, error) {
+	args := m.Called(ctx, assetID, buyerUUID)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *mockBuyRepository) SellAsset(ctx context.Context, assetID int64, buyerUUID string, b tax.Breakdown) (int64, error) {
+	args := m.Called(ctx, assetID, buyerUUID, b)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockBuyRepository) GetAssetOwner(ctx context.Context, assetID int64) (string, string, error) {
+	args := m.Called(ctx, assetID)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *mockBuyRepository) GetStartupOwner(ctx context.Context, startupID int64) (string, error) {
+	args := m.Called(ctx, startupID)
+	return args.String(0), args.Error(1)
+}
+
+// newOwnedRepository returns a repository whose listings all belong to "owner-uuid".
+func newOwnedRepository() *mockBuyRepository {
 	repo := new(mockBuyRepository)
-	service := NewBuyService(repo)
+	repo.On("GetAssetOwner", mock.Anything, mock.Anything).Return("owner-uuid", "Old App", nil).Maybe()
+	repo.On("GetStartupOwner", mock.Anything, mock.Anything).Return("owner-uuid", nil).Maybe()
+	return repo
+}
+
+type mockPublisher struct {
+	events []notifications.Event
+}
+
+func (p *mockPublisher) Publish(ctx context.Context, ev notifications.Event) {
+	p.events = append(p.events, ev)
+}
+
+type recordedActivity struct {
+	typ      activity.Type
+	entityID int64
+}
+
+type mockRecorder struct {
+	recorded []recordedActivity
+}
+
+func (r *mockRecorder) Record(ctx context.Context, t activity.Type, entityID int64) {
+	r.recorded = append(r.recorded, recordedActivity{t, entityID})
+}
+
+func TestBuyService_MarkAssetSold_AlreadySold(t *testing.T) {
+	repo := newOwnedRepository()
+	service := NewBuyService(repo, nil, nil, nil, nil, nil, nil, nil)
 
 	repo.On("GetAssetStatus", mock.Anything, int64(1)).Return(true, true, nil)
 
-	err := service.MarkAssetSold(context.Background(), 1)
+	err := service.MarkAssetSold(context.Background(), 1, "owner-uuid", false, nil)
 
 	require.ErrorIs(t, err, ErrAlreadySold)
 	repo.AssertExpectations(t)
 }
 
 func TestBuyService_MarkAssetSold_Inactive(t *testing.T) {
-	repo := new(mockBuyRepository)
-	service := NewBuyService(repo)
+	repo := newOwnedRepository()
+	service := NewBuyService(repo, nil, nil, nil, nil, nil, nil, nil)
 
 	repo.On("GetAssetStatus", mock.Anything, int64(1)).Return(false, false, nil)
 
-	err := service.MarkAssetSold(context.Background(), 1)
+	err := service.MarkAssetSold(context.Background(), 1, "owner-uuid", false, nil)
 
 	require.ErrorIs(t, err, ErrNotFound)
 	repo.AssertExpectations(t)
 }
 
 func TestBuyService_MarkAssetSold_Success(t *testing.T) {
-	repo := new(mockBuyRepository)
-	service := NewBuyService(repo)
+	repo := newOwnedRepository()
+	service := NewBuyService(repo, nil, nil, nil, nil, nil, nil, nil)
+
+	repo.On("GetAssetStatus", mock.Anything, int64(1)).Return(false, true, nil)
+	repo.On("MarkAssetSold", mock.Anything, int64(1)).Return(nil)
+
+	err := service.MarkAssetSold(context.Background(), 1, "owner-uuid", false, nil)
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestBuyService_MarkAssetSold_RecordsTaxedSale(t *testing.T) {
+	repo := newOwnedRepository()
+	taxes, err := tax.ParseRules("IN:IN:GST:18")
+	require.NoError(t, err)
+	service := NewBuyService(repo, nil, nil, nil, nil, taxes, nil, nil)
+
+	repo.On("GetAssetStatus", mock.Anything, int64(1)).Return(false, true, nil)
+	repo.On("GetSaleCountries", mock.Anything, int64(1), "buyer-uuid").Return("IN", "IN", nil)
+	repo.On("SellAsset", mock.Anything, int64(1), "buyer-uuid", tax.Breakdown{
+		SellerCountry: "IN", BuyerCountry: "IN", Name: "GST", Rate: 18, Net: 500, Tax: 90, Gross: 590,
+	}).Return(int64(7), nil)
+
+	err = service.MarkAssetSold(context.Background(), 1, "owner-uuid", false, &Sale{BuyerUUID: "buyer-uuid", FinalPrice: 500})
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "MarkAssetSold", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestBuyService_MarkAssetSold_UnknownBuyer(t *testing.T) {
+	repo := newOwnedRepository()
+	service := NewBuyService(repo, nil, nil, nil, nil, nil, nil, nil)
+
+	repo.On("GetAssetStatus", mock.Anything, int64(1)).Return(false, true, nil)
+	repo.On("GetSaleCountries", mock.Anything, int64(1), "ghost").Return("", "", ErrBuyerNotFound)
+
+	err := service.MarkAssetSold(context.Background(), 1, "owner-uuid", false, &Sale{BuyerUUID: "ghost", FinalPrice: 10})
+
+	require.ErrorIs(t, err, ErrBuyerNotFound)
+	repo.AssertExpectations(t)
+}
+
+func TestBuyService_MarkAssetSold_NotifiesOwner(t *testing.T) {
+	repo := newOwnedRepository()
+	pub := &mockPublisher{}
+	service := NewBuyService(repo, pub, nil, nil, nil, nil, nil, nil)
 
 	repo.On("GetAssetStatus", mock.Anything, int64(1)).Return(false, true, nil)
 	repo.On("MarkAssetSold", mock.Anything, int64(1)).Return(nil)
 
-	err := service.MarkAssetSold(context.Background(), 1)
+	err := service.MarkAssetSold(context.Background(), 1, "owner-uuid", false, nil)
 
 	require.NoError(t, err)
+	require.Len(t, pub.events, 1)
+	require.Equal(t, notifications.EventAssetSold, pub.events[0].Type)
+	require.Equal(t, "owner-uuid", pub.events[0].RecipientUUID)
+	require.Equal(t, "Old App", pub.events[0].Title)
 	repo.AssertExpectations(t)
 }
 
+func TestBuyService_MarkAssetSold_SendsBuyerReceipt(t *testing.T) {
+	repo := newOwnedRepository()
+	pub := &mockPublisher{}
+	taxes, err := tax.ParseRules("IN:IN:GST:18")
+	require.NoError(t, err)
+	service := NewBuyService(repo, pub, nil, nil, nil, taxes, nil, nil)
+
+	repo.On("GetAssetStatus", mock.Anything, int64(1)).Return(false, true, nil)
+	repo.On("GetSaleCountries", mock.Anything, int64(1), "buyer-uuid").Return("IN", "IN", nil)
+	repo.On("SellAsset", mock.Anything, int64(1), "buyer-uuid", mock.Anything).Return(int64(7), nil)
+
+	require.NoError(t, service.MarkAssetSold(context.Background(), 1, "owner-uuid", false, &Sale{BuyerUUID: "buyer-uuid", FinalPrice: 500}))
+
+	require.Len(t, pub.events, 2)
+	require.Equal(t, notifications.Event{
+		Type:          notifications.EventPurchaseReceipt,
+		RecipientUUID: "buyer-uuid",
+		ActorUUID:     "owner-uuid",
+		EntityID:      7,
+		Title:         "Old App",
+		Amount:        590,
+	}, pub.events[1])
+}
+
 func TestBuyService_MarkStartupSold_AlreadySold(t *testing.T) {
-	repo := new(mockBuyRepository)
-	service := NewBuyService(repo)
+	repo := newOwnedRepository()
+	service := NewBuyService(repo, nil, nil, nil, nil, nil, nil, nil)
 
 	repo.On("GetStartupStatus", mock.Anything, int64(2)).Return("sold", nil)
 
-	err := service.MarkStartupSold(context.Background(), 2)
+	err := service.MarkStartupSold(context.Background(), 2, "owner-uuid", false)
 
 	require.ErrorIs(t, err, ErrAlreadySold)
 	repo.AssertExpectations(t)
 }
 
 func TestBuyService_MarkStartupSold_Success(t *testing.T) {
-	repo := new(mockBuyRepository)
-	service := NewBuyService(repo)
+	repo := newOwnedRepository()
+	service := NewBuyService(repo, nil, nil, nil, nil, nil, nil, nil)
 
 	repo.On("GetStartupStatus", mock.Anything, int64(2)).Return("active", nil)
 	repo.On("MarkStartupSold", mock.Anything, int64(2)).Return(nil)
 
-	err := service.MarkStartupSold(context.Background(), 2)
+	err := service.MarkStartupSold(context.Background(), 2, "owner-uuid", false)
 
 	require.NoError(t, err)
 	repo.AssertExpectations(t)
 }
 
 func TestBuyService_UnlistAsset(t *testing.T) {
-	repo := new(mockBuyRepository)
-	service := NewBuyService(repo)
+	repo := newOwnedRepository()
+	service := NewBuyService(repo, nil, nil, nil, nil, nil, nil, nil)
 
 	repo.On("UnlistAsset", mock.Anything, int64(3)).Return(nil)
 
-	err := service.UnlistAsset(context.Background(), 3)
+	err := service.UnlistAsset(context.Background(), 3, "owner-uuid", false)
 
 	require.NoError(t, err)
 	repo.AssertExpectations(t)
 }
 
 func TestBuyService_UnlistStartup(t *testing.T) {
-	repo := new(mockBuyRepository)
-	service := NewBuyService(repo)
+	repo := newOwnedRepository()
+	service := NewBuyService(repo, nil, nil, nil, nil, nil, nil, nil)
 
 	repo.On("UnlistStartup", mock.Anything, int64(4)).Return(nil)
 
-	err := service.UnlistStartup(context.Background(), 4)
+	err := service.UnlistStartup(context.Background(), 4, "owner-uuid", false)
 
 	require.NoError(t, err)
 	repo.AssertExpectations(t)
 }
+
+func TestBuyService_RecordsSalesInFeed(t *testing.T) {
+	repo := newOwnedRepository()
+	feed := &mockRecorder{}
+	service := NewBuyService(repo, nil, feed, nil, nil, nil, nil, nil)
+
+	repo.On("GetAssetStatus", mock.Anything, int64(1)).Return(false, true, nil)
+	repo.On("MarkAssetSold", mock.Anything, int64(1)).Return(nil)
+	repo.On("GetStartupStatus", mock.Anything, int64(2)).Return("failed", nil)
+	repo.On("MarkStartupSold", mock.Anything, int64(2)).Return(nil)
+
+	require.NoError(t, service.MarkAssetSold(context.Background(), 1, "owner-uuid", false, nil))
+	require.NoError(t, service.MarkStartupSold(context.Background(), 2, "owner-uuid", false))
+
+	require.Equal(t, []recordedActivity{{activity.AssetSold, 1}, {activity.StartupSold, 2}}, feed.recorded)
+}
+
+type mockFollowers struct {
+	changed []int64
+}
+
+func (f *mockFollowers) StatusChanged(ctx context.Context, startupID int64) {
+	f.changed = append(f.changed, startupID)
+}
+
+func TestBuyService_NotifiesStartupFollowers(t *testing.T) {
+	repo := newOwnedRepository()
+	followers := &mockFollowers{}
+	service := NewBuyService(repo, nil, nil, followers, nil, nil, nil, nil)
+
+	repo.On("GetStartupStatus", mock.Anything, int64(2)).Return("active", nil)
+	repo.On("MarkStartupSold", mock.Anything, int64(2)).Return(nil)
+	repo.On("GetStartupStatus", mock.Anything, int64(3)).Return("failed", nil)
+	repo.On("UnlistStartup", mock.Anything, int64(3)).Return(nil)
+
+	require.NoError(t, service.MarkStartupSold(context.Background(), 2, "owner-uuid", false))
+	// Unlisting a startup that is already failed is not a status change
+	require.NoError(t, service.UnlistStartup(context.Background(), 3, "owner-uuid", false))
+
+	require.Equal(t, []int64{2}, followers.changed)
+}
+
+type mockWatchers struct {
+	changed []int64
+}
+
+func (w *mockWatchers) AssetChanged(ctx context.Context, assetID int64) {
+	w.changed = append(w.changed, assetID)
+}
+
+func TestBuyService_NotifiesAssetWatchers(t *testing.T) {
+	repo := newOwnedRepository()
+	watchers := &mockWatchers{}
+	service := NewBuyService(repo, nil, nil, nil, watchers, nil, nil, nil)
+
+	repo.On("GetAssetStatus", mock.Anything, int64(1)).Return(false, true, nil)
+	repo.On("MarkAssetSold", mock.Anything, int64(1)).Return(nil)
+	repo.On("GetAssetStatus", mock.Anything, int64(2)).Return(true, true, nil)
+
+	require.NoError(t, service.MarkAssetSold(context.Background(), 1, "owner-uuid", false, nil))
+	require.ErrorIs(t, service.MarkAssetSold(context.Background(), 2, "owner-uuid", false, nil), ErrAlreadySold)
+
+	require.Equal(t, []int64{1}, watchers.changed)
+}
+
+type mockRepoAccess struct {
+	granted map[int64]string
+	err     error
+}
+
+func (m *mockRepoAccess) GrantBuyer(ctx context.Context, assetID int64, githubUsername string) error {
+	if m.granted == nil {
+		m.granted = map[int64]string{}
+	}
+	m.granted[assetID] = githubUsername
+	return m.err
+}
+
+func TestBuyService_MarkAssetSold_GrantsRepoAccess(t *testing.T) {
+	repo := newOwnedRepository()
+	repos := &mockRepoAccess{err: errors.New("github unavailable")}
+	service := NewBuyService(repo, nil, nil, nil, nil, nil, repos, nil)
+
+	repo.On("GetAssetStatus", mock.Anything, mock.Anything).Return(false, true, nil)
+	repo.On("GetSaleCountries", mock.Anything, mock.Anything, "buyer-uuid").Return("", "", nil)
+	repo.On("SellAsset", mock.Anything, mock.Anything, "buyer-uuid", mock.Anything).Return(int64(7), nil)
+
+	// A failed invitation never undoes the sale
+	require.NoError(t, service.MarkAssetSold(context.Background(), 1, "owner-uuid", false, &Sale{BuyerUUID: "buyer-uuid", FinalPrice: 10, GitHubUsername: "octocat"}))
+	require.NoError(t, service.MarkAssetSold(context.Background(), 2, "owner-uuid", false, &Sale{BuyerUUID: "buyer-uuid", FinalPrice: 10}))
+
+	require.Equal(t, map[int64]string{1: "octocat"}, repos.granted)
+}
+
+type unsignedGate struct{ err error }
+
+func (g unsignedGate) Require(ctx context.Context, assetID int64, userUUID string) error {
+	return g.err
+}
+
+func TestBuyService_MarkAssetSold_RequiresSignedAgreement(t *testing.T) {
+	repo := newOwnedRepository()
+	errUnsigned := errors.New("agreement must be signed")
+	service := NewBuyService(repo, nil, nil, nil, nil, nil, nil, unsignedGate{err: errUnsigned})
+
+	repo.On("GetAssetStatus", mock.Anything, int64(1)).Return(false, true, nil)
+
+	err := service.MarkAssetSold(context.Background(), 1, "owner-uuid", false, &Sale{BuyerUUID: "buyer-uuid", FinalPrice: 10})
+
+	require.ErrorIs(t, err, errUnsigned)
+	repo.AssertNotCalled(t, "SellAsset", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBuyService_RejectsNonOwner(t *testing.T) {
+	repo := newOwnedRepository()
+	repos := &mockRepoAccess{}
+	service := NewBuyService(repo, nil, nil, nil, nil, nil, repos, nil)
+	ctx := context.Background()
+
+	sale := &Sale{BuyerUUID: "someone-else", FinalPrice: 1, GitHubUsername: "someone-else"}
+	require.ErrorIs(t, service.MarkAssetSold(ctx, 1, "someone-else", false, sale), ErrNotOwner)
+	require.ErrorIs(t, service.UnlistAsset(ctx, 1, "someone-else", false), ErrNotOwner)
+	require.ErrorIs(t, service.MarkStartupSold(ctx, 2, "someone-else", false), ErrNotOwner)
+	require.ErrorIs(t, service.UnlistStartup(ctx, 2, "someone-else", false), ErrNotOwner)
+
+	// Nothing is checked or written for a caller who does not own the listing
+	repo.AssertNotCalled(t, "GetAssetStatus", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "GetSaleCountries", mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "SellAsset", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "UnlistAsset", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "GetStartupStatus", mock.Anything, mock.Anything)
+	require.Empty(t, repos.granted)
+}
+
+func TestBuyService_AdminSellsAnyListing(t *testing.T) {
+	repo := newOwnedRepository()
+	service := NewBuyService(repo, nil, nil, nil, nil, nil, nil, nil)
+
+	repo.On("GetAssetStatus", mock.Anything, int64(1)).Return(false, true, nil)
+	repo.On("MarkAssetSold", mock.Anything, int64(1)).Return(nil)
+	repo.On("UnlistStartup", mock.Anything, int64(2)).Return(nil)
+
+	require.NoError(t, service.MarkAssetSold(context.Background(), 1, "admin-uuid", true, nil))
+	require.NoError(t, service.UnlistStartup(context.Background(), 2, "admin-uuid", true))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,242 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var errBatcherClosed = errors.New("message store is closed")
+
+// BatchConfig tunes write-behind batching of SaveMessage.
+type BatchConfig struct {
+	// Window is how long the first queued message waits for others to join its batch.
+	Window time.Duration
+	// MaxSize flushes a batch early once it holds this many messages.
+	MaxSize int
+}
+
+// DefaultBatchConfig groups messages arriving within 5ms, up to 256 per insert.
+func DefaultBatchConfig() BatchConfig {
+	return BatchConfig{Window: 5 * time.Millisecond, MaxSize: 256}
+}
+
+type pendingMessage struct {
+	senderUUID, receiverUUID, content string
+	messageType                       int16
+	messagedAt                        int64
+	result                            chan saveResult // buffered, the batcher never blocks on it
+}
+
+type saveResult struct {
+	id  int64
+	err error
+}
+
+// batcher owns the goroutine that collects queued messages and writes each batch with
+// a single INSERT. COPY would be cheaper still but cannot return the new IDs, which
+// callers need for read receipts.
+type batcher struct {
+	pool  *pgxpool.Pool
+	cfg   BatchConfig
+	queue chan *pendingMessage
+	done  chan struct{}
+
+	mu     sync.RWMutex // held for reading while enqueueing, so Close never closes queue under a sender
+	closed bool
+}
+
+func newBatcher(pool *pgxpool.Pool, cfg BatchConfig) *batcher {
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultBatchConfig().Window
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = DefaultBatchConfig().MaxSize
+	}
+	b := &batcher{
+		pool:  pool,
+		cfg:   cfg,
+		queue: make(chan *pendingMessage, cfg.MaxSize),
+		done:  make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// save queues a message and waits for its batch to commit. If ctx ends first the
+// caller gets ctx.Err(), but a message already queued may still be written.
+func (b *batcher) save(ctx context.Context, p *pendingMessage) (int64, error) {
+	p.result = make(chan saveResult, 1)
+
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return 0, errBatcherClosed
+	}
+	select {
+	case b.queue <- p:
+		b.mu.RUnlock()
+	case <-ctx.Done():
+		b.mu.RUnlock()
+		return 0, ctx.Err()
+	}
+
+	select {
+	case res := <-p.result:
+		return res.id, res.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func (b *batcher) run() {
+	defer close(b.done)
+	for first := range b.queue {
+		batch := []*pendingMessage{first}
+		timer := time.NewTimer(b.cfg.Window)
+	collect:
+		for len(batch) < b.cfg.MaxSize {
+			select {
+			case p, ok := <-b.queue:
+				if !ok {
+					break collect
+				}
+				batch = append(batch, p)
+			case <-timer.C:
+				break collect
+			}
+		}
+		timer.Stop()
+		b.flush(batch)
+	}
+}
+
+func (b *batcher) flush(batch []*pendingMessage) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := insertMessages(ctx, b.pool, batch)
+	for i, p := range batch {
+		switch {
+		case err != nil:
+			p.result <- saveResult{err: err}
+		case ids[i] == 0:
+			p.result <- saveResult{err: fmt.Errorf("insert message: %w", pgx.ErrNoRows)}
+		default:
+			p.result <- saveResult{id: ids[i]}
+		}
+	}
+}
+
+// close stops accepting messages and waits until everything queued has been flushed.
+func (b *batcher) close(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// insertMessages writes batch in one statement and returns the new IDs in batch
+// order. A message whose sender or receiver does not exist gets ID 0 instead of
+// failing the batch, matching SaveMessage's no-rows error for that message alone.
+func insertMessages(ctx context.Context, pool *pgxpool.Pool, batch []*pendingMessage) ([]int64, error) {
+	uuids := make([]string, 0, 2*len(batch))
+	for _, p := range batch {
+		uuids = append(uuids, p.senderUUID, p.receiverUUID)
+	}
+	rows, err := pool.Query(ctx, `SELECT uuid, id FROM users WHERE uuid = ANY($1)`, uuids)
+	if err != nil {
+		return nil, fmt.Errorf("resolve message users: %w", err)
+	}
+	userIDs := map[string]int32{}
+	for rows.Next() {
+		var uuid string
+		var id int32
+		if err := rows.Scan(&uuid, &id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("resolve message users: %w", err)
+		}
+		userIDs[uuid] = id
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("resolve message users: %w", err)
+	}
+
+	var (
+		known              []int // positions in batch that will be inserted
+		senders, receivers []int32
+		contents           []string
+		types              []int16
+		messagedAts        []int64
+	)
+	for i, p := range batch {
+		s, okS := userIDs[p.senderUUID]
+		r, okR := userIDs[p.receiverUUID]
+		if !okS || !okR {
+			continue
+		}
+		known = append(known, i)
+		senders = append(senders, s)
+		receivers = append(receivers, r)
+		contents = append(contents, p.content)
+		types = append(types, p.messageType)
+		messagedAts = append(messagedAts, p.messagedAt)
+	}
+
+	ids := make([]int64, len(batch))
+	if len(known) == 0 {
+		return ids, nil
+	}
+
+	// Rows are inserted in ordinality order, so the BIGSERIAL IDs one statement gets
+	// ascend in batch order even if other inserts interleave with it; sorting the
+	// returned IDs maps them back without relying on RETURNING order.
+	const insertSQL = `
+		INSERT INTO messages (sender_id, receiver_id, content, message_type, is_read, messaged_at)
+		SELECT v.sender_id, v.receiver_id, v.content, v.message_type, FALSE, v.messaged_at
+		FROM unnest($1::int[], $2::int[], $3::text[], $4::smallint[], $5::bigint[]) WITH ORDINALITY
+			AS v(sender_id, receiver_id, content, message_type, messaged_at, ord)
+		ORDER BY v.ord
+		RETURNING id
+	`
+	rows, err = pool.Query(ctx, insertSQL, senders, receivers, contents, types, messagedAts)
+	if err != nil {
+		return nil, fmt.Errorf("insert messages: %w", err)
+	}
+	defer rows.Close()
+	inserted := make([]int64, 0, len(known))
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("insert messages: %w", err)
+		}
+		inserted = append(inserted, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("insert messages: %w", err)
+	}
+	if len(inserted) != len(known) {
+		return nil, fmt.Errorf("insert messages: %d rows inserted for %d messages", len(inserted), len(known))
+	}
+	slices.Sort(inserted)
+	for j, i := range known {
+		ids[i] = inserted[j]
+	}
+	return ids, nil
+}
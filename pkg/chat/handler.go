@@ -3,16 +3,29 @@ package chat
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/errorreport"
+	"grveyard/pkg/notifications"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
 	"grveyard/pkg/response"
+	"grveyard/pkg/telemetry"
+	"grveyard/pkg/validation"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Handler wraps the connection manager and provides HTTP handlers
@@ -22,15 +35,57 @@ type Handler struct {
 	logger interface {
 		Printf(string, ...interface{})
 	}
-	repo MessageStore // optional; if nil, persistence is skipped
+	repo     MessageStore            // optional; if nil, persistence is skipped
+	notifier notifications.Publisher // optional; if nil, offline receivers are not notified
+	blocks   BlockChecker            // optional; if nil, blocks are not enforced
+	bans     BanChecker              // optional; if nil, banned users may connect
+	ws       WSConfig
+	upgrader websocket.Upgrader
+
+	lifecycle sync.Mutex     // orders writers.Add against Shutdown's Wait
+	closing   bool           // set by Shutdown; rejects new upgrades
+	writers   sync.WaitGroup // running writeLoops, drained by Shutdown
 }
 
 // NewHandler creates a new chat handler
 func NewHandler(manager *ConnectionManager) *Handler {
-	return &Handler{
+	h := &Handler{
 		manager: manager,
 		logger:  log.New(log.Writer(), "[chat] ", log.LstdFlags),
 	}
+	h.SetWSConfig(DefaultWSConfig())
+	return h
+}
+
+// WSConfig tunes websocket connections.
+type WSConfig struct {
+	// MaxMessageBytes caps inbound messages; a larger one closes the connection with
+	// 1009 (message too big).
+	MaxMessageBytes int64
+	// Compression negotiates permessage-deflate with clients that offer it.
+	Compression bool
+	// CompressMinBytes is the smallest outbound message worth compressing; chat
+	// messages and acks are sent as is, history and offer payloads get deflated.
+	CompressMinBytes int
+}
+
+// DefaultWSConfig allows 64 KiB inbound messages and compresses outbound ones from 1 KiB.
+func DefaultWSConfig() WSConfig {
+	return WSConfig{MaxMessageBytes: 64 << 10, Compression: true, CompressMinBytes: 1024}
+}
+
+// SetWSConfig applies cfg to connections upgraded from now on.
+func (h *Handler) SetWSConfig(cfg WSConfig) {
+	h.ws = cfg
+	h.upgrader = websocket.Upgrader{
+		ReadBufferSize:    1024,
+		WriteBufferSize:   1024,
+		EnableCompression: cfg.Compression,
+		CheckOrigin: func(r *http.Request) bool {
+			// In production, validate origin properly
+			return true
+		},
+	}
 }
 
 // SetRepository injects the message store for persistence (kept name for compatibility)
@@ -38,13 +93,30 @@ func (h *Handler) SetRepository(r MessageStore) {
 	h.repo = r
 }
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		// In production, validate origin properly
-		return true
-	},
+// SetNotifier injects the notification publisher used for offline receivers
+func (h *Handler) SetNotifier(n notifications.Publisher) {
+	h.notifier = n
+}
+
+// BlockChecker reports whether either of two users blocked the other;
+// *users.BlockService in production.
+type BlockChecker interface {
+	IsBlocked(ctx context.Context, a, b string) (bool, error)
+}
+
+// SetBlocks makes the handler refuse messages between users when either blocked the other
+func (h *Handler) SetBlocks(b BlockChecker) {
+	h.blocks = b
+}
+
+// BanChecker reports whether an admin banned a user; *users.BanService in production.
+type BanChecker interface {
+	IsBanned(ctx context.Context, userID string) (bool, error)
+}
+
+// SetBans makes the handler refuse connections from banned users
+func (h *Handler) SetBans(b BanChecker) {
+	h.bans = b
 }
 
 // HandleWebSocket handles the WebSocket upgrade and connection
@@ -57,9 +129,32 @@ func (h *Handler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.bans != nil {
+		banned, err := h.bans.IsBanned(r.Context(), userID)
+		if err != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if banned {
+			http.Error(w, "forbidden: account suspended", http.StatusForbidden)
+			return
+		}
+	}
+
+	// Register the writeLoop up front so Shutdown can never miss a connection
+	h.lifecycle.Lock()
+	if h.closing {
+		h.lifecycle.Unlock()
+		http.Error(w, "server shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	h.writers.Add(1)
+	h.lifecycle.Unlock()
+
 	// Upgrade connection
-	conn, err := upgrader.Upgrade(w, r, nil)
+	conn, err := h.upgrader.Upgrade(w, r, nil)
 	if err != nil {
+		h.writers.Done()
 		h.logger.Printf("websocket upgrade error: %v", err)
 		return
 	}
@@ -80,17 +175,24 @@ func (h *Handler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	go h.writeLoop(client)
 }
 
-// HandleWebSocketGin validates user_id from query, injects into context, and upgrades to WebSocket.
+// HandleWebSocketGin takes the caller from their access token, injects it into the
+// context, and upgrades to WebSocket.
 func (h *Handler) HandleWebSocketGin(c *gin.Context) {
-	uid := c.Query("user_id")
-
-	ctx := context.WithValue(c.Request.Context(), "user_id", uid)
+	// The server's read/write timeouts are meant for ordinary requests; once upgraded
+	// the read and write loops manage their own deadlines. Errors only mean the
+	// writer cannot set deadlines (e.g. in tests), which is harmless.
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	ctx := context.WithValue(c.Request.Context(), "user_id", auth.UserID(c))
 	req := c.Request.WithContext(ctx)
 	h.HandleWebSocket(c.Writer, req)
 }
 
 // readLoop reads messages from the WebSocket connection
 func (h *Handler) readLoop(client *Client) {
+	defer errorreport.Recover(context.Background(), clientFields(client, "chat.readLoop"))
 	defer func() {
 		h.manager.RemoveClient(client.UserID)
 		client.Conn.Close()
@@ -104,6 +206,9 @@ func (h *Handler) readLoop(client *Client) {
 		}
 	}()
 
+	// Oversized messages make ReadJSON fail with ErrReadLimit after gorilla has sent
+	// the client a 1009 close frame
+	client.Conn.SetReadLimit(h.ws.MaxMessageBytes)
 	client.Conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 	client.Conn.SetPongHandler(func(string) error {
 		client.Conn.SetReadDeadline(time.Now().Add(60 * time.Second))
@@ -120,6 +225,10 @@ func (h *Handler) readLoop(client *Client) {
 		var rawMsg map[string]interface{}
 		err := client.Conn.ReadJSON(&rawMsg)
 		if err != nil {
+			if errors.Is(err, websocket.ErrReadLimit) {
+				h.logger.Printf("closing connection for user %s: message over %d bytes", client.UserID, h.ws.MaxMessageBytes)
+				return
+			}
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				h.logger.Printf("websocket error for user %s: %v", client.UserID, err)
 			}
@@ -144,6 +253,12 @@ func (h *Handler) readLoop(client *Client) {
 	}
 }
 
+// clientFields tags error reports from a connection's goroutines. A recovered panic
+// ends only the goroutine it happened in, never the whole server.
+func clientFields(client *Client, goroutine string) errorreport.Fields {
+	return errorreport.Fields{errorreport.UserField: client.UserID, "goroutine": goroutine}
+}
+
 // IsUserOnline reports if a given user has an active WS connection
 func (h *Handler) IsUserOnline(userID string) bool {
 	return h.manager.IsOnline(userID)
@@ -151,6 +266,8 @@ func (h *Handler) IsUserOnline(userID string) bool {
 
 // writeLoop writes messages to the WebSocket connection
 func (h *Handler) writeLoop(client *Client) {
+	defer h.writers.Done()
+	defer errorreport.Recover(context.Background(), clientFields(client, "chat.writeLoop"))
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
@@ -159,6 +276,10 @@ func (h *Handler) writeLoop(client *Client) {
 		case <-client.Done:
 			return
 
+		case <-client.closing:
+			h.flushAndClose(client)
+			return
+
 		case message, ok := <-client.Send:
 			client.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 
@@ -168,7 +289,7 @@ func (h *Handler) writeLoop(client *Client) {
 				return
 			}
 
-			err := client.Conn.WriteJSON(message)
+			err := h.writeJSON(client, message)
 			if err != nil {
 				h.logger.Printf("write error for user %s: %v", client.UserID, err)
 				return
@@ -184,8 +305,66 @@ func (h *Handler) writeLoop(client *Client) {
 	}
 }
 
+// flushAndClose writes whatever is still queued for client, then a going-away close frame.
+// The peer's close reply ends readLoop, which unregisters the client.
+// writeJSON sends v as a text message, deflated when compression was negotiated and
+// the payload is at least CompressMinBytes.
+func (h *Handler) writeJSON(client *Client, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	client.Conn.EnableWriteCompression(len(data) >= h.ws.CompressMinBytes)
+	return client.Conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (h *Handler) flushAndClose(client *Client) {
+	// writeLoop is the only reader of Send, so len is stable here
+	for len(client.Send) > 0 {
+		client.Conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+		if err := h.writeJSON(client, <-client.Send); err != nil {
+			return
+		}
+	}
+
+	client.Conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	if err := client.Conn.WriteMessage(websocket.CloseMessage, closeMsg); err != nil {
+		h.logger.Printf("close frame error for user %s: %v", client.UserID, err)
+	}
+}
+
+// Shutdown stops accepting websocket upgrades, asks every client to flush and close,
+// and waits for their write loops to finish. Connections still open when ctx expires
+// are closed forcibly.
+func (h *Handler) Shutdown(ctx context.Context) error {
+	h.lifecycle.Lock()
+	h.closing = true
+	h.lifecycle.Unlock()
+	h.manager.CloseAll()
+
+	done := make(chan struct{})
+	go func() {
+		h.writers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	h.manager.DisconnectAll()
+	return ctx.Err()
+}
+
 // processMessage validates and handles incoming messages
 func (h *Handler) processMessage(client *Client, msg Message) {
+	defer errorreport.Recover(context.Background(), clientFields(client, "chat.processMessage"))
+	// Each websocket frame starts its own trace; the upgrade request is long gone.
+	ctx, span := telemetry.Tracer().Start(context.Background(), "chat.processMessage",
+		trace.WithAttributes(attribute.String("chat.sender_id", client.UserID), attribute.String("chat.receiver_id", msg.ReceiverID)))
+	defer span.End()
+
 	// Validate message structure
 	if err := h.validateMessage(msg, client.UserID); err != nil {
 		h.sendError(client, msg, err.Error())
@@ -209,16 +388,33 @@ func (h *Handler) processMessage(client *Client, msg Message) {
 		return
 	}
 
+	if h.blocks != nil {
+		blocked, err := h.blocks.IsBlocked(ctx, msg.SenderID, msg.ReceiverID)
+		if err != nil {
+			span.RecordError(err)
+			h.logger.Printf("block check failed for user %s -> %s: %v", msg.SenderID, msg.ReceiverID, err)
+			h.sendError(client, msg, "failed to deliver message")
+			return
+		}
+		if blocked {
+			h.sendErrorCode(client, "you cannot message this user", apperr.UserBlocked)
+			return
+		}
+	}
+
 	// Default message type if not provided
 	if msg.MessageType == 0 {
 		msg.MessageType = 0 // text
 	}
 
-	// Persist synchronously after validation and before forwarding
+	// Persist after validation and before forwarding; with batching enabled this waits
+	// for the message's batch to commit
 	if h.repo != nil {
 		epoch := msg.Timestamp.Unix()
-		if _, err := h.repo.SaveMessage(context.Background(), msg.SenderID, msg.ReceiverID, msg.Content, msg.MessageType, epoch); err != nil {
+		if _, err := h.repo.SaveMessage(ctx, msg.SenderID, msg.ReceiverID, msg.Content, msg.MessageType, epoch); err != nil {
 			// Log and send error acknowledgement without crashing
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "persist message")
 			h.logger.Printf("db insert failed for user %s -> %s: %v", msg.SenderID, msg.ReceiverID, err)
 			ack := Acknowledgement{MessageID: msg.ID, Status: "error", Error: "failed to persist message"}
 			select {
@@ -237,6 +433,13 @@ func (h *Handler) processMessage(client *Client, msg Message) {
 			h.sendError(client, msg, fmt.Sprintf("failed to deliver message: %v", err))
 			return
 		}
+	} else if h.notifier != nil {
+		h.notifier.Publish(ctx, notifications.Event{
+			Type:          notifications.EventMessageReceived,
+			RecipientUUID: msg.ReceiverID,
+			ActorUUID:     msg.SenderID,
+			Title:         messagePreview(msg.Content),
+		})
 	}
 
 	// Acknowledge to sender immediately
@@ -255,6 +458,44 @@ func (h *Handler) processMessage(client *Client, msg Message) {
 	}
 }
 
+// PostOfferMessage records a system message about an offer in the conversation between
+// sender and receiver and pushes it to whichever of them is online. Both parties are
+// already notified about the offer itself, so no chat notification is published.
+func (h *Handler) PostOfferMessage(ctx context.Context, senderID, receiverID, content string, offerID int64) error {
+	msg := Message{
+		ID:          uuid.New().String(),
+		SenderID:    senderID,
+		ReceiverID:  receiverID,
+		Content:     content,
+		Timestamp:   time.Now().UTC(),
+		MessageType: MessageTypeSystem,
+		OfferID:     offerID,
+	}
+	if h.repo != nil {
+		if _, err := h.repo.SaveOfferMessage(ctx, senderID, receiverID, content, offerID, msg.Timestamp.Unix()); err != nil {
+			return err
+		}
+	}
+	for _, userID := range []string{receiverID, senderID} {
+		if h.manager.IsOnline(userID) {
+			if err := h.manager.BroadcastToUser(userID, msg); err != nil {
+				h.logger.Printf("offer message %d not delivered to %s: %v", offerID, userID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// messagePreview truncates content for notifications without splitting runes
+func messagePreview(content string) string {
+	const maxRunes = 140
+	runes := []rune(content)
+	if len(runes) <= maxRunes {
+		return content
+	}
+	return string(runes[:maxRunes]) + "…"
+}
+
 // validateMessage validates the message before processing
 func (h *Handler) validateMessage(msg Message, senderID string) error {
 	if msg.Content == "" {
@@ -269,6 +510,10 @@ func (h *Handler) validateMessage(msg Message, senderID string) error {
 		return fmt.Errorf("receiver_id is required")
 	}
 
+	if msg.MessageType == MessageTypeSystem || msg.OfferID != 0 {
+		return fmt.Errorf("system messages cannot be sent by clients")
+	}
+
 	// Reject self-messages
 	if msg.ReceiverID == senderID {
 		return fmt.Errorf("cannot send messages to yourself")
@@ -279,8 +524,14 @@ func (h *Handler) validateMessage(msg Message, senderID string) error {
 
 // sendError sends an error response to the client
 func (h *Handler) sendError(client *Client, originalMsg Message, errMsg string) {
+	h.sendErrorCode(client, errMsg, "")
+}
+
+// sendErrorCode sends an error response with a machine-readable code
+func (h *Handler) sendErrorCode(client *Client, errMsg string, code apperr.Code) {
 	errResp := ErrorResponse{
 		Error: errMsg,
+		Code:  string(code),
 	}
 
 	select {
@@ -292,6 +543,7 @@ func (h *Handler) sendError(client *Client, originalMsg Message, errMsg string)
 
 // processReadReceipt handles read receipt events from the receiver
 func (h *Handler) processReadReceipt(client *Client, rawMsg map[string]interface{}) {
+	defer errorreport.Recover(context.Background(), clientFields(client, "chat.processReadReceipt"))
 	if h.repo == nil {
 		return // No DB support, skip
 	}
@@ -314,21 +566,32 @@ func (h *Handler) processReadReceipt(client *Client, rawMsg map[string]interface
 		return
 	}
 
-	// Mark messages as read in DB (only where receiver_id = client.UserID)
-	senderUUIDs, err := h.repo.MarkMessagesAsRead(context.Background(), client.UserID, messageIDs)
-	if err != nil {
-		h.logger.Printf("failed to mark messages as read for %s: %v", client.UserID, err)
+	ctx, span := telemetry.Tracer().Start(context.Background(), "chat.processReadReceipt",
+		trace.WithAttributes(attribute.String("chat.reader_id", client.UserID), attribute.Int("chat.message_count", len(messageIDs))))
+	defer span.End()
+
+	if err := h.markRead(ctx, client.UserID, messageIDs); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "mark messages as read")
 		h.sendError(client, Message{}, "failed to mark messages as read")
-		return
+	}
+}
+
+// markRead marks messageIDs read where readerID is the receiver and tells their
+// senders, if online, which messages were read.
+func (h *Handler) markRead(ctx context.Context, readerID string, messageIDs []string) error {
+	// Only messages where receiver_id = readerID are updated
+	senderUUIDs, err := h.repo.MarkMessagesAsRead(ctx, readerID, messageIDs)
+	if err != nil {
+		h.logger.Printf("failed to mark messages as read for %s: %v", readerID, err)
+		return err
 	}
 
-	// Notify senders if they are online
 	notification := ReadReceiptNotification{
 		EventType:  "message_read",
 		MessageIDs: messageIDs,
-		ReadBy:     client.UserID,
+		ReadBy:     readerID,
 	}
-
 	for _, senderUUID := range senderUUIDs {
 		if h.manager.IsOnline(senderUUID) {
 			if err := h.manager.BroadcastToUser(senderUUID, notification); err != nil {
@@ -336,89 +599,183 @@ func (h *Handler) processReadReceipt(client *Client, rawMsg map[string]interface
 			}
 		}
 	}
+	return nil
 }
 
 // Gin-specific wrappers using SendAPIResponse
-// GetStatusGin godoc
-// @Summary Get online users
-// @Description Returns list of currently connected users
-// @Tags chat
-// @Produce json
-// @Success 200 {object} response.APIResponse
-// @Router /chat/status [get]
+
+// onlineStatus is the data of GET /chat/status.
+type onlineStatus struct {
+	OnlineUsers []string `json:"online_users"`
+	Count       int      `json:"count"`
+}
+
+// readResult is the data of POST /messages/read: the messages the receipt covered.
+type readResult struct {
+	MessageIDs []string `json:"message_ids"`
+	Count      int      `json:"count"`
+}
+
+// messageHistory is the data of GET /messages, oldest message first.
+type messageHistory struct {
+	Messages []MessageHistoryItem `json:"messages"`
+	Count    int                  `json:"count"`
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *Handler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/ws/chat",
+			Tag:         "chat",
+			Summary:     "Chat websocket",
+			Description: "Upgrades to a WebSocket carrying chat messages for the signed-in user. Messages to a user who blocked the sender, or whom the sender blocked, are refused with an error frame carrying code USER_BLOCKED. permessage-deflate is negotiated when the client offers it. Inbound messages over the configured limit (64 KiB by default) close the connection with code 1009.",
+			Status:      http.StatusSwitchingProtocols,
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/chat/status",
+			Tag:         "chat",
+			Summary:     "Get online users",
+			Description: "Returns list of currently connected users",
+			Response:    onlineStatus{},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/messages",
+			Tag:         "chat",
+			Summary:     "Get conversation history",
+			Description: "Fetch chat messages between the signed-in user and a peer",
+			Params: []openapi.Param{
+				openapi.Query("peer_id", "string", "Peer user UUID", true),
+				openapi.Query("limit", "integer", "Maximum messages to return (max 100)", false),
+				openapi.Query("before", "integer", "Epoch seconds cursor for pagination (deprecated, use cursor)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next", false),
+			},
+			Response: messageHistory{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError, http.StatusServiceUnavailable},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/messages/read",
+			Tag:         "chat",
+			Summary:     "Mark messages as read",
+			Description: "REST fallback for websocket read receipts, e.g. for a mobile client resuming from the background. Pass message_ids, or peer_id to mark every unread message from that peer up to up_to_id. Only messages sent to the signed-in user are marked; online senders get a message_read event.",
+			Request:     MarkReadRequest{},
+			Response:    readResult{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError, http.StatusServiceUnavailable},
+			Auth:        true,
+		},
+	}
+}
+
+// GetStatusGin lists the currently connected users.
 func (h *Handler) GetStatusGin(c *gin.Context) {
 	users := h.manager.GetOnlineUsers()
-	response.SendAPIResponse(c, http.StatusOK, true, "online status", map[string]interface{}{
-		"online_users": users,
-		"count":        len(users),
-	})
+	response.SendAPIResponse(c, http.StatusOK, true, "online status", onlineStatus{OnlineUsers: users, Count: len(users)})
 }
 
-// GetMessagesGin godoc
-// @Summary Get conversation history
-// @Description Fetch chat messages between the requesting user and a peer
-// @Tags chat
-// @Param user_id query string true "Requesting user UUID"
-// @Param peer_id query string true "Peer user UUID"
-// @Param limit query int false "Maximum messages to return (max 100)"
-// @Param before query int false "Epoch seconds cursor for pagination"
-// @Produce json
-// @Success 200 {object} response.APIResponse
-// @Failure 400 {object} response.APIResponse
-// @Failure 401 {object} response.APIResponse
-// @Failure 403 {object} response.APIResponse
-// @Failure 500 {object} response.APIResponse
-// @Router /messages [get]
+// GetMessagesGin returns a page of conversation history, newest page first.
 func (h *Handler) GetMessagesGin(c *gin.Context) {
 	if h.repo == nil {
-		response.SendAPIResponse(c, http.StatusServiceUnavailable, false, "message history not available", nil)
+		response.SendError(c, apperr.New(apperr.ServiceUnavailable, "message history not available"))
 		return
 	}
 
-	uid := c.Query("user_id")
-	ctx := context.WithValue(c.Request.Context(), "user_id", uid)
-	c.Request = c.Request.WithContext(ctx)
-
-	userID := uid
-	queryUserID := c.Query("user_id")
+	userID := auth.UserID(c)
 	peerID := c.Query("peer_id")
-
-	if queryUserID != userID {
-		response.SendAPIResponse(c, http.StatusForbidden, false, "forbidden: can only fetch your own messages", nil)
-		return
-	}
 	if peerID == "" {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "peer_id is required", nil)
+		response.SendError(c, response.InvalidField(apperr.InvalidRequest, "peer_id", "required", "peer_id is required"))
 		return
 	}
 
-	// Parse limit and before
+	// Parse limit and cursor; "before" (epoch seconds) is still accepted for older clients
 	limit := 50
 	if ls := c.Query("limit"); ls != "" {
 		if _, err := fmt.Sscanf(ls, "%d", &limit); err != nil {
-			response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid limit parameter", nil)
+			response.SendError(c, response.InvalidField(apperr.InvalidRequest, "limit", "type", "invalid limit parameter"))
 			return
 		}
 	}
-	beforeEpoch := time.Now().Unix()
-	if bs := c.Query("before"); bs != "" {
-		if _, err := fmt.Sscanf(bs, "%d", &beforeEpoch); err != nil {
-			response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid before parameter", nil)
+	cursor := historyCursor{Before: time.Now().Unix()}
+	if cs := c.Query("cursor"); cs != "" {
+		if err := pagination.DecodeCursor(cs, &cursor); err != nil {
+			response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+			return
+		}
+	} else if bs := c.Query("before"); bs != "" {
+		if _, err := fmt.Sscanf(bs, "%d", &cursor.Before); err != nil {
+			response.SendError(c, response.InvalidField(apperr.InvalidRequest, "before", "type", "invalid before parameter"))
 			return
 		}
 	}
 
-	messages, err := h.repo.GetConversationHistory(c.Request.Context(), userID, peerID, limit, beforeEpoch)
+	messages, err := h.repo.GetConversationHistory(c.Request.Context(), userID, peerID, limit, cursor.Before, cursor.ID)
 	if err != nil {
 		h.logger.Printf("failed to fetch messages for %s <-> %s: %v", userID, peerID, err)
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, "failed to fetch messages", nil)
+		response.SendError(c, apperr.New(apperr.Internal, "failed to fetch messages"))
 		return
 	}
 
-	response.SendAPIResponse(c, http.StatusOK, true, "messages", map[string]interface{}{
-		"messages": messages,
-		"count":    len(messages),
-	})
+	// A full page means there may be older messages; point next at the oldest one
+	var next any
+	if len(messages) > 0 && len(messages) >= limit {
+		oldest := messages[0]
+		next = historyCursor{Before: oldest.MessagedAt, ID: oldest.ID}
+	}
+	response.SendPaginatedResponse(c, http.StatusOK, "messages", messageHistory{Messages: messages, Count: len(messages)}, pagination.NextLinks(c, next))
+}
+
+// MarkReadGin marks messages as read without a websocket connection.
+func (h *Handler) MarkReadGin(c *gin.Context) {
+	if h.repo == nil {
+		response.SendError(c, apperr.New(apperr.ServiceUnavailable, "message history not available"))
+		return
+	}
+
+	var req MarkReadRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	if (len(req.MessageIDs) == 0) == (req.PeerID == "") {
+		response.SendError(c, response.InvalidField(apperr.InvalidRequest, "message_ids", "required_without", "provide either message_ids or peer_id"))
+		return
+	}
+	if req.UpToID != 0 && req.PeerID == "" {
+		response.SendError(c, response.InvalidField(apperr.InvalidRequest, "up_to_id", "required_with", "up_to_id requires peer_id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	userID := auth.UserID(c)
+	messageIDs := req.MessageIDs
+	if req.PeerID != "" {
+		ids, err := h.repo.UnreadMessageIDs(ctx, userID, req.PeerID, req.UpToID)
+		if err != nil {
+			h.logger.Printf("failed to list unread messages for %s <- %s: %v", userID, req.PeerID, err)
+			response.SendError(c, apperr.New(apperr.Internal, "failed to mark messages as read"))
+			return
+		}
+		messageIDs = ids
+	}
+
+	if len(messageIDs) > 0 {
+		if err := h.markRead(ctx, userID, messageIDs); err != nil {
+			response.SendError(c, apperr.New(apperr.Internal, "failed to mark messages as read"))
+			return
+		}
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "messages marked as read", readResult{MessageIDs: messageIDs, Count: len(messageIDs)})
+}
+
+// historyCursor is the keyset position for paging backwards through a conversation.
+type historyCursor struct {
+	Before int64 `json:"b"`
+	ID     int64 `json:"i,omitempty"`
 }
 
 // AuthMiddleware removed; Gin routes should handle auth and context injection
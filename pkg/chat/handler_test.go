@@ -3,10 +3,17 @@ package chat
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
 )
 
 // mockStore is a lightweight MessageStore double for unit testing handler logic.
@@ -22,6 +29,8 @@ type mockStore struct {
 	markErr       error
 	updateErr     error
 	historyResult []MessageHistoryItem
+	unreadResult  []string
+	markedIDs     []string
 }
 
 func (m *mockStore) SaveMessage(ctx context.Context, senderUUID, receiverUUID, content string, messageType int16, messagedAt int64) (int64, error) {
@@ -38,18 +47,27 @@ func (m *mockStore) SaveMessage(ctx context.Context, senderUUID, receiverUUID, c
 	return 1, nil
 }
 
+func (m *mockStore) SaveOfferMessage(ctx context.Context, senderUUID, receiverUUID, content string, offerID, messagedAt int64) (int64, error) {
+	return m.SaveMessage(ctx, senderUUID, receiverUUID, content, MessageTypeSystem, messagedAt)
+}
+
 func (m *mockStore) UpdateLastActive(ctx context.Context, userUUID string, lastActiveEpoch int64) error {
 	return m.updateErr
 }
 
 func (m *mockStore) MarkMessagesAsRead(ctx context.Context, receiverUUID string, messageIDs []string) ([]string, error) {
+	m.markedIDs = append(m.markedIDs, messageIDs...)
 	if m.markErr != nil {
 		return nil, m.markErr
 	}
 	return []string{"sender-online"}, nil
 }
 
-func (m *mockStore) GetConversationHistory(ctx context.Context, userUUID, peerUUID string, limit int, beforeEpoch int64) ([]MessageHistoryItem, error) {
+func (m *mockStore) UnreadMessageIDs(ctx context.Context, receiverUUID, senderUUID string, upToID int64) ([]string, error) {
+	return m.unreadResult, nil
+}
+
+func (m *mockStore) GetConversationHistory(ctx context.Context, userUUID, peerUUID string, limit int, beforeEpoch, beforeID int64) ([]MessageHistoryItem, error) {
 	return m.historyResult, nil
 }
 
@@ -66,6 +84,8 @@ func TestValidateMessage(t *testing.T) {
 		{"empty content", Message{ReceiverID: "user2", Content: ""}, "user1", true},
 		{"self message", Message{ReceiverID: "user1", Content: "hi"}, "user1", true},
 		{"missing receiver", Message{ReceiverID: "", Content: "hi"}, "user1", true},
+		{"system message", Message{ReceiverID: "user2", Content: "hi", MessageType: MessageTypeSystem}, "user1", true},
+		{"offer link", Message{ReceiverID: "user2", Content: "hi", OfferID: 7}, "user1", true},
 		{"valid message", Message{ReceiverID: "user2", Content: "hi"}, "user1", false},
 	}
 
@@ -136,6 +156,35 @@ func TestProcessMessage_OnlineDelivered(t *testing.T) {
 	require.Len(t, store.saveCalls, 1)
 }
 
+// TestPostOfferMessage persists a system message and pushes it to both online parties.
+func TestPostOfferMessage(t *testing.T) {
+	manager := NewConnectionManager()
+	seller := manager.AddClient("seller", nil)
+	seller.Send = make(chan interface{}, 1)
+	buyer := manager.AddClient("buyer", nil)
+	buyer.Send = make(chan interface{}, 1)
+	store := &mockStore{}
+	handler := NewHandler(manager)
+	handler.SetRepository(store)
+
+	err := handler.PostOfferMessage(context.Background(), "buyer", "seller", "Offered 90.00", 7)
+
+	require.NoError(t, err)
+	require.Len(t, store.saveCalls, 1)
+	require.Equal(t, MessageTypeSystem, store.saveCalls[0].typeID)
+	for _, c := range []*Client{seller, buyer} {
+		select {
+		case raw := <-c.Send:
+			msg := raw.(Message)
+			require.Equal(t, "Offered 90.00", msg.Content)
+			require.Equal(t, int64(7), msg.OfferID)
+			require.Equal(t, MessageTypeSystem, msg.MessageType)
+		case <-time.After(1 * time.Second):
+			t.Fatalf("no offer message for %s", c.UserID)
+		}
+	}
+}
+
 // TestProcessMessage_SaveError returns error ack and no forward.
 func TestProcessMessage_SaveError(t *testing.T) {
 	manager := NewConnectionManager()
@@ -186,3 +235,203 @@ func TestProcessMessage_SelfMessageRejected(t *testing.T) {
 	}
 	require.Empty(t, store.saveCalls)
 }
+
+// blockedPairs is a BlockChecker where either user of a pair blocked the other.
+type blockedPairs map[[2]string]bool
+
+func (b blockedPairs) IsBlocked(_ context.Context, a, c string) (bool, error) {
+	return b[[2]string{a, c}] || b[[2]string{c, a}], nil
+}
+
+// TestProcessMessage_Blocked rejects messages in either direction of a block.
+func TestProcessMessage_Blocked(t *testing.T) {
+	manager := NewConnectionManager()
+	receiver := manager.AddClient("user2", nil)
+	receiver.Send = make(chan interface{}, 1)
+	store := &mockStore{}
+	handler := NewHandler(manager)
+	handler.SetRepository(store)
+	handler.SetBlocks(blockedPairs{{"user2", "user1"}: true})
+
+	client := &Client{UserID: "user1", Send: make(chan interface{}, 1), Done: make(chan struct{})}
+	handler.processMessage(client, Message{ReceiverID: "user2", Content: "hi"})
+
+	select {
+	case raw := <-client.Send:
+		resp, ok := raw.(ErrorResponse)
+		require.True(t, ok)
+		require.Equal(t, "USER_BLOCKED", resp.Code)
+	case <-time.After(1 * time.Second):
+		t.Fatal("no error response")
+	}
+	require.Empty(t, store.saveCalls)
+	select {
+	case <-receiver.Send:
+		t.Fatal("should not forward a blocked message")
+	default:
+	}
+}
+
+// TestShutdown_FlushesAndSendsCloseFrame checks queued messages reach the client before
+// the going-away close frame, and that new upgrades are refused afterwards.
+func TestShutdown_FlushesAndSendsCloseFrame(t *testing.T) {
+	manager := NewConnectionManager()
+	handler := NewHandler(manager)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.HandleWebSocket(w, r.WithContext(context.WithValue(r.Context(), "user_id", r.URL.Query().Get("user_id"))))
+	}))
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "?user_id=user1"
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.Eventually(t, func() bool { return manager.IsOnline("user1") }, time.Second, 10*time.Millisecond)
+	require.NoError(t, manager.BroadcastToUser("user1", Message{ID: "m1", Content: "bye"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	go handler.Shutdown(ctx)
+
+	var got Message
+	require.NoError(t, conn.ReadJSON(&got))
+	require.Equal(t, "m1", got.ID)
+
+	_, _, err = conn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+
+	require.Eventually(t, func() bool {
+		handler.lifecycle.Lock()
+		defer handler.lifecycle.Unlock()
+		return handler.closing
+	}, time.Second, 10*time.Millisecond)
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	require.Error(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+// TestHandleWebSocket_MessageTooBig closes the connection with 1009 when an inbound
+// frame exceeds MaxMessageBytes, and negotiates compression for clients that offer it.
+func TestHandleWebSocket_MessageTooBig(t *testing.T) {
+	handler := NewHandler(NewConnectionManager())
+	handler.SetWSConfig(WSConfig{MaxMessageBytes: 512, Compression: true, CompressMinBytes: 0})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.HandleWebSocket(w, r.WithContext(context.WithValue(r.Context(), "user_id", r.URL.Query().Get("user_id"))))
+	}))
+	defer srv.Close()
+
+	dialer := websocket.Dialer{EnableCompression: true}
+	conn, resp, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"?user_id=user1", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Contains(t, resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"content":"`+strings.Repeat("x", 1024)+`"}`)))
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, _, err = conn.ReadMessage()
+		if err != nil {
+			break
+		}
+	}
+	require.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), "got %v", err)
+}
+
+// bannedUsers is a BanChecker banning the users it holds.
+type bannedUsers map[string]bool
+
+func (b bannedUsers) IsBanned(_ context.Context, userID string) (bool, error) {
+	return b[userID], nil
+}
+
+// TestHandleWebSocket_Banned refuses banned users and drops connections on Disconnect.
+func TestHandleWebSocket_Banned(t *testing.T) {
+	manager := NewConnectionManager()
+	handler := NewHandler(manager)
+	handler.SetBans(bannedUsers{"banned": true})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.HandleWebSocket(w, r.WithContext(context.WithValue(r.Context(), "user_id", r.URL.Query().Get("user_id"))))
+	}))
+	defer srv.Close()
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "?user_id="
+
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL+"banned", nil)
+	require.Error(t, err)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	require.False(t, manager.IsOnline("banned"))
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL+"user1", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Eventually(t, func() bool { return manager.IsOnline("user1") }, time.Second, 10*time.Millisecond)
+
+	manager.Disconnect("user1")
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err = conn.ReadMessage()
+	require.Error(t, err)
+	require.Eventually(t, func() bool { return !manager.IsOnline("user1") }, time.Second, 10*time.Millisecond)
+}
+
+func postMarkRead(t *testing.T, handler *Handler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/messages/read", testhelpers.AuthAs("reader", "buyer"), handler.MarkReadGin)
+	req := httptest.NewRequest(http.MethodPost, "/messages/read", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestMarkReadGin_MessageIDs marks the given messages and notifies the online sender.
+func TestMarkReadGin_MessageIDs(t *testing.T) {
+	manager := NewConnectionManager()
+	sender := manager.AddClient("sender-online", nil)
+	sender.Send = make(chan interface{}, 1)
+	store := &mockStore{}
+	handler := NewHandler(manager)
+	handler.SetRepository(store)
+
+	w := postMarkRead(t, handler, `{"message_ids":["4","5"]}`)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"count":2`)
+	require.Equal(t, []string{"4", "5"}, store.markedIDs)
+	select {
+	case raw := <-sender.Send:
+		n := raw.(ReadReceiptNotification)
+		require.Equal(t, "reader", n.ReadBy)
+		require.Equal(t, []string{"4", "5"}, n.MessageIDs)
+	case <-time.After(1 * time.Second):
+		t.Fatal("no read receipt for sender")
+	}
+}
+
+// TestMarkReadGin_Peer marks the conversation's unread messages.
+func TestMarkReadGin_Peer(t *testing.T) {
+	store := &mockStore{unreadResult: []string{"7", "9"}}
+	handler := NewHandler(NewConnectionManager())
+	handler.SetRepository(store)
+
+	w := postMarkRead(t, handler, `{"peer_id":"sender","up_to_id":9}`)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, []string{"7", "9"}, store.markedIDs)
+}
+
+func TestMarkReadGin_Invalid(t *testing.T) {
+	handler := NewHandler(NewConnectionManager())
+	handler.SetRepository(&mockStore{})
+
+	for _, body := range []string{
+		`{}`,
+		`{"message_ids":["1"],"peer_id":"sender"}`,
+		`{"message_ids":["1"],"up_to_id":3}`,
+	} {
+		w := postMarkRead(t, handler, body)
+		require.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
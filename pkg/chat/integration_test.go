@@ -3,10 +3,11 @@ package chat
 import (
 	"context"
 	"fmt"
-	"os"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/require"
@@ -14,37 +15,20 @@ import (
 	"grveyard/pkg/testhelpers"
 )
 
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
 // newTestPool connects to a real Postgres instance for integration tests.
-// Skips if DATABASE_URL_FOR_TEST is not set to keep CI deterministic.
+// Skips unless DATABASE_URL_FOR_TEST or TEST_DB_CONTAINER=1 is set to keep CI deterministic.
 func newTestPool(t *testing.T) *pgxpool.Pool {
 	t.Helper()
 	if err := godotenv.Load(); err != nil {
 		t.Log("No .env file found, using environment variables")
 	}
-	dsn := os.Getenv("DATABASE_URL_FOR_TEST")
-	if dsn == "" {
-		t.Skip("DATABASE_URL_FOR_TEST not set; skipping integration tests")
-	}
-
-	cfg, err := pgxpool.ParseConfig(dsn)
-	require.NoError(t, err)
-	cfg.MaxConns = 4
-
-	ctx := context.Background()
-	pool, err := pgxpool.NewWithConfig(ctx, cfg)
-	require.NoError(t, err)
-
-	// truncate := func() {
-	// 	_, err := pool.Exec(context.Background(), "TRUNCATE messages, users RESTART IDENTITY CASCADE")
-	// 	require.NoError(t, err)
-	// }
-	// truncate()
-	// t.Cleanup(truncate)
-	t.Cleanup(func() { pool.Close() })
-	return pool
+	return testhelpers.Pool(t)
 }
 
 func TestSaveMessage_PersistsFields(t *testing.T) {
+	t.Parallel()
 	pool := newTestPool(t)
 	store := NewPostgresMessageStore(pool)
 
@@ -74,7 +58,33 @@ func TestSaveMessage_PersistsFields(t *testing.T) {
 	require.Equal(t, messagedAt, storedAt)
 }
 
+func TestSaveOfferMessage_LinksOfferInHistory(t *testing.T) {
+	t.Parallel()
+	pool := newTestPool(t)
+	store := NewPostgresMessageStore(pool)
+	ctx := context.Background()
+
+	buyer := testhelpers.CreateTestUser(t, pool)
+	seller := testhelpers.CreateTestUser(t, pool)
+	now := time.Now().Unix()
+
+	_, err := store.SaveMessage(ctx, seller, buyer, "hi", 0, now-1)
+	require.NoError(t, err)
+	_, err = store.SaveOfferMessage(ctx, buyer, seller, "Offered 90.00", 42, now)
+	require.NoError(t, err)
+
+	history, err := store.GetConversationHistory(ctx, seller, buyer, 10, now+1, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, "Offered 90.00", history[0].Content)
+	require.Equal(t, MessageTypeSystem, history[0].MessageType)
+	require.NotNil(t, history[0].OfferID)
+	require.Equal(t, int64(42), *history[0].OfferID)
+	require.Nil(t, history[1].OfferID)
+}
+
 func TestProcessMessage_SelfMessageDoesNotPersist(t *testing.T) {
+	t.Parallel()
 	pool := newTestPool(t)
 	store := NewPostgresMessageStore(pool)
 	manager := NewConnectionManager()
@@ -103,6 +113,7 @@ func TestProcessMessage_SelfMessageDoesNotPersist(t *testing.T) {
 }
 
 func TestConversationHistory_BidirectionalAndOrdering(t *testing.T) {
+	t.Parallel()
 	pool := newTestPool(t)
 	store := NewPostgresMessageStore(pool)
 
@@ -117,7 +128,7 @@ func TestConversationHistory_BidirectionalAndOrdering(t *testing.T) {
 	_, err = store.SaveMessage(context.Background(), a, b, "m3", 0, 300)
 	require.NoError(t, err)
 
-	messages, err := store.GetConversationHistory(context.Background(), a, b, 10, time.Now().Unix())
+	messages, err := store.GetConversationHistory(context.Background(), a, b, 10, time.Now().Unix(), 0)
 	require.NoError(t, err)
 	require.Len(t, messages, 3)
 	require.Equal(t, []string{"m1", "m2", "m3"}, []string{messages[0].Content, messages[1].Content, messages[2].Content})
@@ -125,6 +136,7 @@ func TestConversationHistory_BidirectionalAndOrdering(t *testing.T) {
 }
 
 func TestConversationHistory_PaginationBefore(t *testing.T) {
+	t.Parallel()
 	pool := newTestPool(t)
 	store := NewPostgresMessageStore(pool)
 
@@ -135,13 +147,14 @@ func TestConversationHistory_PaginationBefore(t *testing.T) {
 	store.SaveMessage(context.Background(), a, b, "mid", 0, 200)
 	store.SaveMessage(context.Background(), a, b, "new", 0, 300)
 
-	messages, err := store.GetConversationHistory(context.Background(), a, b, 10, 250)
+	messages, err := store.GetConversationHistory(context.Background(), a, b, 10, 250, 0)
 	require.NoError(t, err)
 	require.Len(t, messages, 2)
 	require.Equal(t, []string{"old", "mid"}, []string{messages[0].Content, messages[1].Content})
 }
 
 func TestMarkMessagesAsRead_OnlyReceiverCanAcknowledge(t *testing.T) {
+	t.Parallel()
 	pool := newTestPool(t)
 	store := NewPostgresMessageStore(pool)
 
@@ -169,7 +182,37 @@ func TestMarkMessagesAsRead_OnlyReceiverCanAcknowledge(t *testing.T) {
 	// require.Zero(t, unread)
 }
 
+func TestUnreadMessageIDs_ConversationCursor(t *testing.T) {
+	t.Parallel()
+	pool := newTestPool(t)
+	store := NewPostgresMessageStore(pool)
+	ctx := context.Background()
+
+	sender := testhelpers.CreateTestUser(t, pool)
+	receiver := testhelpers.CreateTestUser(t, pool)
+
+	id1, err := store.SaveMessage(ctx, sender, receiver, "one", 0, 100)
+	require.NoError(t, err)
+	id2, err := store.SaveMessage(ctx, sender, receiver, "two", 0, 200)
+	require.NoError(t, err)
+	id3, err := store.SaveMessage(ctx, sender, receiver, "three", 0, 300)
+	require.NoError(t, err)
+	_, err = store.SaveMessage(ctx, receiver, sender, "reply", 0, 250)
+	require.NoError(t, err)
+	_, err = store.MarkMessagesAsRead(ctx, receiver, []string{fmt.Sprintf("%d", id1)})
+	require.NoError(t, err)
+
+	ids, err := store.UnreadMessageIDs(ctx, receiver, sender, id2)
+	require.NoError(t, err)
+	require.Equal(t, []string{fmt.Sprintf("%d", id2)}, ids)
+
+	ids, err = store.UnreadMessageIDs(ctx, receiver, sender, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{fmt.Sprintf("%d", id2), fmt.Sprintf("%d", id3)}, ids)
+}
+
 func TestUpdateLastActive_Monotonic(t *testing.T) {
+	t.Parallel()
 	pool := newTestPool(t)
 	store := NewPostgresMessageStore(pool)
 	user := testhelpers.CreateTestUser(t, pool)
@@ -181,3 +224,75 @@ func TestUpdateLastActive_Monotonic(t *testing.T) {
 	require.NoError(t, pool.QueryRow(context.Background(), "SELECT last_active_at FROM users WHERE uuid=$1", user).Scan(&lastActive))
 	require.Equal(t, int64(200), lastActive)
 }
+
+func TestSaveMessage_Batched(t *testing.T) {
+	t.Parallel()
+	pool := newTestPool(t)
+	store := NewPostgresMessageStore(pool)
+	store.EnableBatching(BatchConfig{Window: 20 * time.Millisecond, MaxSize: 8})
+	ctx := context.Background()
+
+	sender := testhelpers.CreateTestUser(t, pool)
+	receiver := testhelpers.CreateTestUser(t, pool)
+
+	const n = 20
+	ids := make([]int64, n)
+	errs := make([]error, n+1)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids[i], errs[i] = store.SaveMessage(ctx, sender, receiver, fmt.Sprintf("m%d", i), 0, int64(i))
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, errs[n] = store.SaveMessage(ctx, sender, "missing-user", "lost", 0, 0)
+	}()
+	wg.Wait()
+
+	require.ErrorIs(t, errs[n], pgx.ErrNoRows)
+	for i := 0; i < n; i++ {
+		require.NoError(t, errs[i])
+		var content string
+		require.NoError(t, pool.QueryRow(ctx, "SELECT content FROM messages WHERE id = $1", ids[i]).Scan(&content))
+		require.Equal(t, fmt.Sprintf("m%d", i), content)
+	}
+
+	require.NoError(t, store.Close(ctx))
+	_, err := store.SaveMessage(ctx, sender, receiver, "late", 0, 0)
+	require.ErrorIs(t, err, errBatcherClosed)
+}
+
+// BenchmarkSaveMessage compares one INSERT per message with write-behind batching
+// under concurrent senders, as processMessage sees them under load.
+func BenchmarkSaveMessage(b *testing.B) {
+	for _, mode := range []struct {
+		name  string
+		batch bool
+	}{{"direct", false}, {"batched", true}} {
+		b.Run(mode.name, func(b *testing.B) {
+			pool := testhelpers.Pool(b)
+			store := NewPostgresMessageStore(pool)
+			if mode.batch {
+				store.EnableBatching(DefaultBatchConfig())
+				defer store.Close(context.Background())
+			}
+			sender := testhelpers.NewUser(b, pool).UUID
+			receiver := testhelpers.NewUser(b, pool).UUID
+
+			b.SetParallelism(16)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := store.SaveMessage(context.Background(), sender, receiver, "hello", 0, time.Now().Unix()); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
@@ -13,6 +13,17 @@ type Client struct {
 	Conn   *websocket.Conn
 	Send   chan interface{} // Channel to send messages to this client
 	Done   chan struct{}    // Signal to stop reading/writing
+
+	closing   chan struct{} // Asks writeLoop to flush Send and send a close frame
+	closeOnce sync.Once
+}
+
+// requestClose asks the client's writeLoop to flush and say goodbye. Safe to call twice.
+func (c *Client) requestClose() {
+	if c.closing == nil {
+		return
+	}
+	c.closeOnce.Do(func() { close(c.closing) })
 }
 
 // ConnectionManager manages all active WebSocket connections
@@ -40,10 +51,11 @@ func (cm *ConnectionManager) AddClient(userID string, conn *websocket.Conn) *Cli
 	}
 
 	client := &Client{
-		UserID: userID,
-		Conn:   conn,
-		Send:   make(chan interface{}, 32), // Buffered channel to handle bursts
-		Done:   make(chan struct{}),
+		UserID:  userID,
+		Conn:    conn,
+		Send:    make(chan interface{}, 32), // Buffered channel to handle bursts
+		Done:    make(chan struct{}),
+		closing: make(chan struct{}),
 	}
 
 	cm.clients[userID] = client
@@ -112,3 +124,35 @@ func (cm *ConnectionManager) BroadcastToUser(userID string, message interface{})
 		return fmt.Errorf("user %s message queue full", userID)
 	}
 }
+
+// Disconnect force-closes userID's connection, if any; its readLoop then removes it
+func (cm *ConnectionManager) Disconnect(userID string) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if client, ok := cm.clients[userID]; ok && client.Conn != nil {
+		client.Conn.Close()
+	}
+}
+
+// CloseAll asks every connected client to flush pending messages and close
+func (cm *ConnectionManager) CloseAll() {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	for _, client := range cm.clients {
+		client.requestClose()
+	}
+}
+
+// DisconnectAll force-closes every remaining connection
+func (cm *ConnectionManager) DisconnectAll() {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	for _, client := range cm.clients {
+		if client.Conn != nil {
+			client.Conn.Close()
+		}
+	}
+}
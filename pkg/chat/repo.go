@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -11,25 +12,56 @@ import (
 
 type MessageStore interface {
 	SaveMessage(ctx context.Context, senderUUID, receiverUUID, content string, messageType int16, messagedAt int64) (int64, error)
+	// SaveOfferMessage stores a system message about offerID.
+	SaveOfferMessage(ctx context.Context, senderUUID, receiverUUID, content string, offerID, messagedAt int64) (int64, error)
 	UpdateLastActive(ctx context.Context, userUUID string, lastActiveEpoch int64) error
 	MarkMessagesAsRead(ctx context.Context, receiverUUID string, messageIDs []string) ([]string, error)
-	GetConversationHistory(ctx context.Context, userUUID, peerUUID string, limit int, beforeEpoch int64) ([]MessageHistoryItem, error)
+	// UnreadMessageIDs lists unread messages from sender to receiver, up to and
+	// including upToID when it is non-zero.
+	UnreadMessageIDs(ctx context.Context, receiverUUID, senderUUID string, upToID int64) ([]string, error)
+	GetConversationHistory(ctx context.Context, userUUID, peerUUID string, limit int, beforeEpoch, beforeID int64) ([]MessageHistoryItem, error)
 }
 
 type PostgresMessageStore struct {
-	pool *pgxpool.Pool
+	pool  *pgxpool.Pool
+	batch *batcher // nil unless EnableBatching was called
 }
 
 func NewPostgresMessageStore(pool *pgxpool.Pool) *PostgresMessageStore {
 	return &PostgresMessageStore{pool: pool}
 }
 
+// EnableBatching switches SaveMessage to write-behind mode: messages saved within
+// cfg.Window of each other are inserted together, and each call still returns only
+// once its message is committed. Call it once, before the store is used, and Close
+// the store on shutdown.
+func (r *PostgresMessageStore) EnableBatching(cfg BatchConfig) {
+	r.batch = newBatcher(r.pool, cfg)
+}
+
+// Close flushes messages queued for batching. It is a no-op without batching.
+func (r *PostgresMessageStore) Close(ctx context.Context) error {
+	if r.batch == nil {
+		return nil
+	}
+	return r.batch.close(ctx)
+}
+
 // SaveMessage inserts a message into the messages table using UUIDs to resolve user IDs.
 // Returns the inserted DB message ID (bigint) or an error.
 func (r *PostgresMessageStore) SaveMessage(ctx context.Context, senderUUID, receiverUUID, content string, messageType int16, messagedAt int64) (int64, error) {
 	if r.pool == nil {
 		return 0, errors.New("db pool is nil")
 	}
+	if r.batch != nil {
+		return r.batch.save(ctx, &pendingMessage{
+			senderUUID:   senderUUID,
+			receiverUUID: receiverUUID,
+			content:      content,
+			messageType:  messageType,
+			messagedAt:   messagedAt,
+		})
+	}
 
 	// Parameterized insert selecting ids from users by uuid
 	const insertSQL = `
@@ -52,6 +84,32 @@ func (r *PostgresMessageStore) SaveMessage(ctx context.Context, senderUUID, rece
 	return dbID, nil
 }
 
+// SaveOfferMessage inserts a system message linked to an offer, so clients can render
+// it as an offer card in the conversation.
+func (r *PostgresMessageStore) SaveOfferMessage(ctx context.Context, senderUUID, receiverUUID, content string, offerID, messagedAt int64) (int64, error) {
+	if r.pool == nil {
+		return 0, errors.New("db pool is nil")
+	}
+
+	const insertSQL = `
+		INSERT INTO messages (sender_id, receiver_id, content, message_type, is_read, messaged_at, offer_id)
+		SELECT s.id, r.id, $3, $4, FALSE, $5, $6
+		FROM users s, users r
+		WHERE s.uuid = $1 AND r.uuid = $2
+		RETURNING id
+	`
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var dbID int64
+	row := r.pool.QueryRow(ctxTimeout, insertSQL, senderUUID, receiverUUID, content, MessageTypeSystem, messagedAt, offerID)
+	if err := row.Scan(&dbID); err != nil {
+		return 0, fmt.Errorf("insert offer message: %w", err)
+	}
+	return dbID, nil
+}
+
 // UpdateLastActive updates users.last_active_at with epoch seconds for the given user UUID.
 func (r *PostgresMessageStore) UpdateLastActive(ctx context.Context, userUUID string, lastActiveEpoch int64) error {
 	if r.pool == nil {
@@ -77,6 +135,51 @@ func (r *PostgresMessageStore) UpdateLastActive(ctx context.Context, userUUID st
 	return nil
 }
 
+// maxUnreadIDs caps how many messages one conversation read receipt marks.
+const maxUnreadIDs = 1000
+
+// UnreadMessageIDs returns the IDs of unread messages in a conversation, oldest first,
+// so they can be passed to MarkMessagesAsRead.
+func (r *PostgresMessageStore) UnreadMessageIDs(ctx context.Context, receiverUUID, senderUUID string, upToID int64) ([]string, error) {
+	if r.pool == nil {
+		return nil, errors.New("db pool is nil")
+	}
+
+	const querySQL = `
+		SELECT m.id
+		FROM messages m
+		JOIN users s ON m.sender_id = s.id
+		JOIN users r ON m.receiver_id = r.id
+		WHERE r.uuid = $1 AND s.uuid = $2
+		  AND m.is_read = FALSE
+		  AND ($3::bigint = 0 OR m.id <= $3)
+		ORDER BY m.id
+		LIMIT $4
+	`
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctxTimeout, querySQL, receiverUUID, senderUUID, upToID, maxUnreadIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query unread messages: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan message id: %w", err)
+		}
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return ids, nil
+}
+
 // MarkMessagesAsRead marks messages as read where receiver matches the given UUID.
 // Returns the list of sender UUIDs who should be notified.
 func (r *PostgresMessageStore) MarkMessagesAsRead(ctx context.Context, receiverUUID string, messageIDs []string) ([]string, error) {
@@ -139,9 +242,10 @@ func (r *PostgresMessageStore) MarkMessagesAsRead(ctx context.Context, receiverU
 	return senderUUIDs, nil
 }
 
-// GetConversationHistory fetches message history between two users with pagination.
+// GetConversationHistory fetches the latest limit messages between two users older than
+// the (beforeEpoch, beforeID) keyset cursor; beforeID 0 means "strictly before beforeEpoch".
 // Returns messages ordered by messaged_at ASC (oldest first).
-func (r *PostgresMessageStore) GetConversationHistory(ctx context.Context, userUUID, peerUUID string, limit int, beforeEpoch int64) ([]MessageHistoryItem, error) {
+func (r *PostgresMessageStore) GetConversationHistory(ctx context.Context, userUUID, peerUUID string, limit int, beforeEpoch, beforeID int64) ([]MessageHistoryItem, error) {
 	if r.pool == nil {
 		return nil, errors.New("db pool is nil")
 	}
@@ -155,12 +259,14 @@ func (r *PostgresMessageStore) GetConversationHistory(ctx context.Context, userU
 
 	const querySQL = `
 		SELECT
+			m.id,
 			s.uuid as sender_uuid,
 			r.uuid as receiver_uuid,
 			m.content,
 			m.message_type,
 			m.is_read,
-			m.messaged_at
+			m.messaged_at,
+			m.offer_id
 		FROM messages m
 		JOIN users s ON m.sender_id = s.id
 		JOIN users r ON m.receiver_id = r.id
@@ -169,15 +275,15 @@ func (r *PostgresMessageStore) GetConversationHistory(ctx context.Context, userU
 			OR
 			(s.uuid = $2 AND r.uuid = $1)
 		)
-		AND m.messaged_at < $3
-		ORDER BY m.messaged_at ASC
+		AND (m.messaged_at < $3 OR (m.messaged_at = $3 AND m.id < $5))
+		ORDER BY m.messaged_at DESC, m.id DESC
 		LIMIT $4
 	`
 
 	ctxTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	rows, err := r.pool.Query(ctxTimeout, querySQL, userUUID, peerUUID, beforeEpoch, limit)
+	rows, err := r.pool.Query(ctxTimeout, querySQL, userUUID, peerUUID, beforeEpoch, limit, beforeID)
 	if err != nil {
 		return nil, fmt.Errorf("query conversation history: %w", err)
 	}
@@ -186,7 +292,7 @@ func (r *PostgresMessageStore) GetConversationHistory(ctx context.Context, userU
 	result := make([]MessageHistoryItem, 0, limit)
 	for rows.Next() {
 		var item MessageHistoryItem
-		if err := rows.Scan(&item.SenderID, &item.ReceiverID, &item.Content, &item.MessageType, &item.IsRead, &item.MessagedAt); err != nil {
+		if err := rows.Scan(&item.ID, &item.SenderID, &item.ReceiverID, &item.Content, &item.MessageType, &item.IsRead, &item.MessagedAt, &item.OfferID); err != nil {
 			return nil, fmt.Errorf("scan message: %w", err)
 		}
 		result = append(result, item)
@@ -196,5 +302,9 @@ func (r *PostgresMessageStore) GetConversationHistory(ctx context.Context, userU
 		return nil, fmt.Errorf("iterate rows: %w", err)
 	}
 
+	// Fetched newest first to apply the limit; callers expect oldest first
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
 	return result, nil
 }
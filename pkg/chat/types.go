@@ -4,6 +4,10 @@ import (
 	"time"
 )
 
+// MessageTypeSystem marks messages the platform posts on a user's behalf, such as
+// offer summaries.
+const MessageTypeSystem int16 = 3
+
 // Message represents a chat message
 type Message struct {
 	SenderID    string    `json:"sender_id"`
@@ -13,6 +17,7 @@ type Message struct {
 	ID          string    `json:"id"` // Unique message ID
 	MessageType int16     `json:"message_type,omitempty"`
 	IsRead      bool      `json:"is_read,omitempty"`
+	OfferID     int64     `json:"offer_id,omitempty"` // offer a system message is about
 }
 
 // Acknowledgement sent to sender when message is processed
@@ -34,6 +39,15 @@ type ReadReceipt struct {
 	MessageIDs []string `json:"message_ids"` // UUIDs of messages to mark as read
 }
 
+// MarkReadRequest is the body of POST /messages/read, for clients without an open
+// websocket. It takes message_ids like a websocket read receipt, or peer_id to mark
+// everything unread from that peer, up to and including up_to_id when set.
+type MarkReadRequest struct {
+	MessageIDs []string `json:"message_ids" binding:"omitempty,max=500"`
+	PeerID     string   `json:"peer_id"`
+	UpToID     int64    `json:"up_to_id" binding:"omitempty,min=1"`
+}
+
 // ReadReceiptNotification sent to sender when their messages are read
 type ReadReceiptNotification struct {
 	EventType  string   `json:"event_type"` // "message_read"
@@ -43,10 +57,12 @@ type ReadReceiptNotification struct {
 
 // MessageHistoryItem represents a message in conversation history (REST API)
 type MessageHistoryItem struct {
+	ID          int64  `json:"id"`
 	SenderID    string `json:"sender_id"`   // UUID
 	ReceiverID  string `json:"receiver_id"` // UUID
 	Content     string `json:"content"`
 	MessageType int16  `json:"message_type"`
 	IsRead      bool   `json:"is_read"`
 	MessagedAt  int64  `json:"messaged_at"` // epoch seconds
+	OfferID     *int64 `json:"offer_id,omitempty"`
 }
@@ -0,0 +1,193 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// Config controls which responses are compressed.
+type Config struct {
+	MinSize       int      // responses smaller than this are sent as-is
+	ExcludedPaths []string // path prefixes never compressed, e.g. websocket routes
+}
+
+// DefaultConfig skips tiny bodies, where the encoding overhead outweighs the savings.
+func DefaultConfig() Config {
+	return Config{MinSize: 1024}
+}
+
+// compressibleTypes are the media types worth compressing; images and archives
+// are already compressed.
+var compressibleTypes = map[string]bool{
+	"application/json":       true,
+	"application/javascript": true,
+	"application/xml":        true,
+	"image/svg+xml":          true,
+}
+
+// Middleware compresses responses with brotli or gzip, whichever the client prefers
+// (brotli on ties). WebSocket upgrades and event streams are always passed through.
+func Middleware(cfg Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if skip(c.Request, cfg) {
+			c.Next()
+			return
+		}
+		encoding := negotiate(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		cw := &compressWriter{ResponseWriter: original, encoding: encoding, minSize: cfg.MinSize, status: http.StatusOK}
+		original.Header().Add("Vary", "Accept-Encoding")
+		c.Writer = cw
+		defer func() {
+			c.Writer = original
+			// On panic leave the response to the recovery middleware untouched
+			if r := recover(); r != nil {
+				panic(r)
+			}
+			cw.finish()
+		}()
+		c.Next()
+	}
+}
+
+func skip(r *http.Request, cfg Config) bool {
+	if r.Method == http.MethodHead {
+		return true
+	}
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return true
+	}
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		return true
+	}
+	for _, prefix := range cfg.ExcludedPaths {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiate picks "br" or "gzip" from an Accept-Encoding header, honouring q=0.
+func negotiate(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "br" && name != "gzip" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > bestQ || (q == bestQ && name == "br") {
+			best, bestQ = name, q
+		}
+	}
+	if bestQ <= 0 {
+		return ""
+	}
+	return best
+}
+
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || compressibleTypes[mediaType]
+}
+
+// compressWriter buffers up to minSize bytes before deciding whether to compress, so
+// small responses keep their Content-Length and skip the encoder entirely.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+	status   int
+	buf      bytes.Buffer
+	encoder  io.WriteCloser
+	decided  bool
+}
+
+func (w *compressWriter) WriteHeader(code int) { w.status = code }
+func (w *compressWriter) WriteHeaderNow()      {}
+func (w *compressWriter) Status() int          { return w.status }
+
+func (w *compressWriter) WriteString(s string) (int, error) { return w.Write([]byte(s)) }
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.encoder != nil {
+			return w.encoder.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf.Write(b)
+	if w.buf.Len() >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// decide commits the headers and flushes the buffer, compressed if worthwhile.
+func (w *compressWriter) decide(bigEnough bool) error {
+	w.decided = true
+	h := w.Header()
+	if bigEnough && h.Get("Content-Encoding") == "" && w.status != http.StatusNoContent &&
+		w.status != http.StatusNotModified && compressible(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+		// The encoded representation differs byte-wise, so a strong ETag becomes weak
+		if tag := h.Get("ETag"); strings.HasPrefix(tag, `"`) {
+			h.Set("ETag", "W/"+tag)
+		}
+		if w.encoding == "br" {
+			w.encoder = brotli.NewWriterLevel(w.ResponseWriter, brotli.DefaultCompression)
+		} else {
+			w.encoder, _ = gzip.NewWriterLevel(w.ResponseWriter, gzip.DefaultCompression)
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(w.buf.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+	w.buf.Reset()
+	return err
+}
+
+func (w *compressWriter) finish() {
+	if !w.decided {
+		w.decide(false)
+	}
+	if w.encoder != nil {
+		w.encoder.Close()
+	}
+}
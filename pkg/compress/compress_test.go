@@ -0,0 +1,68 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+var large = strings.Repeat("graveyard ", 500)
+
+func setupRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware(Config{MinSize: 1024, ExcludedPaths: []string{"/ws/"}}))
+	r.GET("/large", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"data": large}) })
+	r.GET("/small", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"data": "tiny"}) })
+	r.GET("/ws/chat", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"data": large}) })
+	return r
+}
+
+func get(r *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_Gzip(t *testing.T) {
+	w := get(setupRouter(), "/large", "gzip")
+
+	require.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	zr, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	require.Contains(t, string(body), large)
+}
+
+func TestMiddleware_PrefersBrotli(t *testing.T) {
+	w := get(setupRouter(), "/large", "gzip, deflate, br")
+
+	require.Equal(t, "br", w.Header().Get("Content-Encoding"))
+	body, err := io.ReadAll(brotli.NewReader(w.Body))
+	require.NoError(t, err)
+	require.Contains(t, string(body), large)
+}
+
+func TestMiddleware_SkipsSmallAndExcluded(t *testing.T) {
+	r := setupRouter()
+
+	w := get(r, "/small", "gzip")
+	require.Empty(t, w.Header().Get("Content-Encoding"))
+	require.Contains(t, w.Body.String(), "tiny")
+
+	w = get(r, "/ws/chat", "gzip")
+	require.Empty(t, w.Header().Get("Content-Encoding"))
+
+	w = get(r, "/large", "gzip;q=0")
+	require.Empty(t, w.Header().Get("Content-Encoding"))
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// ACMEConfig enables automatic TLS certificates from Let's Encrypt.
+type ACMEConfig struct {
+	Enabled  bool
+	Domains  []string // certificates are only requested for these hosts
+	Email    string   // optional contact for expiry notices
+	CacheDir string   // certificates and the account key persist here across restarts
+	HTTPPort string   // serves HTTP-01 challenges and redirects everything else to HTTPS
+}
+
+// LoadACME reads ENABLE_ACME, ACME_DOMAINS (comma separated, required when enabled),
+// ACME_EMAIL, ACME_CACHE_DIR (default "certs") and ACME_HTTP_PORT (default 80).
+func LoadACME() (ACMEConfig, error) {
+	cfg := ACMEConfig{
+		Enabled:  strings.EqualFold(os.Getenv("ENABLE_ACME"), "true"),
+		Domains:  splitList(os.Getenv("ACME_DOMAINS")),
+		Email:    os.Getenv("ACME_EMAIL"),
+		CacheDir: os.Getenv("ACME_CACHE_DIR"),
+		HTTPPort: os.Getenv("ACME_HTTP_PORT"),
+	}
+	if cfg.CacheDir == "" {
+		cfg.CacheDir = "certs"
+	}
+	if cfg.HTTPPort == "" {
+		cfg.HTTPPort = "80"
+	}
+	if cfg.Enabled && len(cfg.Domains) == 0 {
+		return cfg, errors.New("ENABLE_ACME is set but ACME_DOMAINS is empty")
+	}
+	return cfg, nil
+}
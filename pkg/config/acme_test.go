@@ -0,0 +1,47 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadACME_DisabledByDefault(t *testing.T) {
+	t.Setenv("ENABLE_ACME", "")
+	t.Setenv("ACME_DOMAINS", "")
+	t.Setenv("ACME_CACHE_DIR", "")
+	t.Setenv("ACME_HTTP_PORT", "")
+
+	cfg, err := LoadACME()
+
+	require.NoError(t, err)
+	require.False(t, cfg.Enabled)
+	require.Equal(t, "certs", cfg.CacheDir)
+	require.Equal(t, "80", cfg.HTTPPort)
+}
+
+func TestLoadACME_Enabled(t *testing.T) {
+	t.Setenv("ENABLE_ACME", "true")
+	t.Setenv("ACME_DOMAINS", "api.example.com, www.api.example.com,")
+	t.Setenv("ACME_EMAIL", "ops@example.com")
+	t.Setenv("ACME_CACHE_DIR", "/var/lib/grveyard/certs")
+	t.Setenv("ACME_HTTP_PORT", "8081")
+
+	cfg, err := LoadACME()
+
+	require.NoError(t, err)
+	require.True(t, cfg.Enabled)
+	require.Equal(t, []string{"api.example.com", "www.api.example.com"}, cfg.Domains)
+	require.Equal(t, "ops@example.com", cfg.Email)
+	require.Equal(t, "/var/lib/grveyard/certs", cfg.CacheDir)
+	require.Equal(t, "8081", cfg.HTTPPort)
+}
+
+func TestLoadACME_RequiresDomains(t *testing.T) {
+	t.Setenv("ENABLE_ACME", "true")
+	t.Setenv("ACME_DOMAINS", "")
+
+	_, err := LoadACME()
+
+	require.Error(t, err)
+}
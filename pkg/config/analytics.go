@@ -0,0 +1,40 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// AnalyticsConfig selects where POST /events writes client events.
+type AnalyticsConfig struct {
+	Sink string // "postgres" (default) or "webhook"
+
+	// webhook
+	WebhookURL   string // batches are POSTed here as {"events": [...]}
+	WebhookToken string // sent as a bearer token when set
+}
+
+// LoadAnalytics reads ANALYTICS_SINK, ANALYTICS_WEBHOOK_URL and
+// ANALYTICS_WEBHOOK_TOKEN.
+func LoadAnalytics() (AnalyticsConfig, error) {
+	cfg := AnalyticsConfig{
+		Sink:         strings.ToLower(os.Getenv("ANALYTICS_SINK")),
+		WebhookURL:   os.Getenv("ANALYTICS_WEBHOOK_URL"),
+		WebhookToken: os.Getenv("ANALYTICS_WEBHOOK_TOKEN"),
+	}
+	if cfg.Sink == "" {
+		cfg.Sink = "postgres"
+	}
+
+	switch cfg.Sink {
+	case "postgres":
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			return cfg, errors.New("ANALYTICS_SINK=webhook requires ANALYTICS_WEBHOOK_URL")
+		}
+	default:
+		return cfg, errors.New("ANALYTICS_SINK must be postgres or webhook")
+	}
+	return cfg, nil
+}
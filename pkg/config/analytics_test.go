@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAnalytics(t *testing.T) {
+	t.Setenv("ANALYTICS_SINK", "")
+	t.Setenv("ANALYTICS_WEBHOOK_URL", "")
+
+	cfg, err := LoadAnalytics()
+	require.NoError(t, err)
+	require.Equal(t, "postgres", cfg.Sink)
+
+	t.Setenv("ANALYTICS_SINK", "Webhook")
+	_, err = LoadAnalytics()
+	require.EqualError(t, err, "ANALYTICS_SINK=webhook requires ANALYTICS_WEBHOOK_URL")
+
+	t.Setenv("ANALYTICS_WEBHOOK_URL", "https://collector.example.com/batch")
+	cfg, err = LoadAnalytics()
+	require.NoError(t, err)
+	require.Equal(t, "webhook", cfg.Sink)
+
+	t.Setenv("ANALYTICS_SINK", "kafka")
+	_, err = LoadAnalytics()
+	require.EqualError(t, err, "ANALYTICS_SINK must be postgres or webhook")
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"grveyard/pkg/awsauth"
+)
+
+// AWSSecretsManagerProvider reads secrets with the GetSecretValue API. The path is the
+// secret name or ARN.
+type AWSSecretsManagerProvider struct {
+	region   string
+	endpoint string
+	creds    awsauth.Credentials
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewAWSSecretsManagerProvider(region string, creds awsauth.Credentials) *AWSSecretsManagerProvider {
+	return &AWSSecretsManagerProvider{
+		region:   region,
+		endpoint: "https://secretsmanager." + region + ".amazonaws.com/",
+		creds:    creds,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		now:      time.Now,
+	}
+}
+
+func (p *AWSSecretsManagerProvider) Fetch(ctx context.Context, path string) (string, error) {
+	if !p.creds.Valid() {
+		return "", errors.New("aws-sm: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required")
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": path})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	awsauth.Sign(req, awsauth.PayloadHash(body), "secretsmanager", p.region, p.creds, p.now())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("aws-sm: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("aws-sm: %s reading %s: %s", resp.Status, path, strings.TrimSpace(string(msg)))
+	}
+
+	var out struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("aws-sm: decode %s: %w", path, err)
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("aws-sm: %s is a binary secret, only string secrets are supported", path)
+	}
+	return *out.SecretString, nil
+}
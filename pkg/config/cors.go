@@ -0,0 +1,114 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strings"
+)
+
+// CORSPolicy is the CORS configuration for one group of routes.
+type CORSPolicy struct {
+	Name             string
+	PathPrefixes     []string // empty matches every path; used for the fallback policy
+	AllowOrigins     []string // empty rejects every cross-origin request
+	AllowMethods     []string
+	AllowCredentials bool
+}
+
+// Matches reports whether path belongs to the policy's group. Prefixes match whole
+// path segments, so "/users" covers "/users/1" but not "/usersettings".
+func (p CORSPolicy) Matches(path string) bool {
+	if len(p.PathPrefixes) == 0 {
+		return true
+	}
+	for _, prefix := range p.PathPrefixes {
+		if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	defaultCredentialedPaths = []string{"/users", "/chat", "/messages", "/ws", "/getOTP", "/verifyOTP", "/admin"}
+	defaultServerPaths       = []string{"/webhooks", "/dev"}
+	allMethods               = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+)
+
+// LoadCORS builds the CORS policies from the environment, most specific first:
+//
+//   - "server": CORS_SERVER_PATHS (default /webhooks,/dev) are called server-to-server
+//     and never from a browser, so every cross-origin request is refused.
+//   - "credentialed": CORS_CREDENTIALED_PATHS (default /users,/chat,/messages,/ws,
+//     /getOTP,/verifyOTP,/admin) accept only CORS_CREDENTIALED_ORIGINS, with cookies. When
+//     that is unset the explicit (non-"*") entries of CORS_ALLOWED_ORIGINS are used.
+//   - "public": everything else accepts CORS_ALLOWED_ORIGINS (default "*"); credentials
+//     follow CORS_ALLOW_CREDENTIALS but are never combined with "*".
+func LoadCORS() []CORSPolicy {
+	public := splitList(os.Getenv("CORS_ALLOWED_ORIGINS"))
+	if len(public) == 0 {
+		public = []string{"*"}
+	}
+
+	credentialed := withoutWildcard(splitList(os.Getenv("CORS_CREDENTIALED_ORIGINS")))
+	if os.Getenv("CORS_CREDENTIALED_ORIGINS") == "" {
+		credentialed = withoutWildcard(public)
+	}
+	if len(credentialed) == 0 {
+		log.Println("CORS_CREDENTIALED_ORIGINS not set; cross-origin requests to credentialed routes will be refused")
+	}
+
+	publicCreds := strings.EqualFold(os.Getenv("CORS_ALLOW_CREDENTIALS"), "true")
+	if publicCreds && len(withoutWildcard(public)) != len(public) {
+		log.Println("CORS_ALLOW_CREDENTIALS ignored for public routes because CORS_ALLOWED_ORIGINS contains \"*\"")
+		publicCreds = false
+	}
+
+	return []CORSPolicy{
+		{
+			Name:         "server",
+			PathPrefixes: listOrDefault("CORS_SERVER_PATHS", defaultServerPaths),
+		},
+		{
+			Name:             "credentialed",
+			PathPrefixes:     listOrDefault("CORS_CREDENTIALED_PATHS", defaultCredentialedPaths),
+			AllowOrigins:     credentialed,
+			AllowMethods:     allMethods,
+			AllowCredentials: true,
+		},
+		{
+			Name:             "public",
+			AllowOrigins:     public,
+			AllowMethods:     allMethods,
+			AllowCredentials: publicCreds,
+		},
+	}
+}
+
+func listOrDefault(key string, def []string) []string {
+	if v := splitList(os.Getenv(key)); len(v) > 0 {
+		return v
+	}
+	return def
+}
+
+// splitList parses a comma separated env value, dropping blanks.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if v := strings.TrimSpace(part); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func withoutWildcard(origins []string) []string {
+	out := make([]string, 0, len(origins))
+	for _, o := range origins {
+		if o != "*" {
+			out = append(out, o)
+		}
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func policyByName(t *testing.T, policies []CORSPolicy, name string) CORSPolicy {
+	t.Helper()
+	for _, p := range policies {
+		if p.Name == name {
+			return p
+		}
+	}
+	t.Fatalf("policy %q not found", name)
+	return CORSPolicy{}
+}
+
+func TestLoadCORS_Defaults(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "")
+	t.Setenv("CORS_CREDENTIALED_ORIGINS", "")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+
+	policies := LoadCORS()
+
+	public := policyByName(t, policies, "public")
+	require.Equal(t, []string{"*"}, public.AllowOrigins)
+	require.False(t, public.AllowCredentials, "credentials must never be combined with *")
+
+	credentialed := policyByName(t, policies, "credentialed")
+	require.Empty(t, credentialed.AllowOrigins)
+	require.True(t, credentialed.AllowCredentials)
+
+	require.Empty(t, policyByName(t, policies, "server").AllowOrigins)
+}
+
+func TestLoadCORS_CredentialedFallsBackToExplicitOrigins(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "*, https://app.example.com")
+	t.Setenv("CORS_CREDENTIALED_ORIGINS", "")
+
+	credentialed := policyByName(t, LoadCORS(), "credentialed")
+	require.Equal(t, []string{"https://app.example.com"}, credentialed.AllowOrigins)
+}
+
+func TestLoadCORS_Overrides(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://a.example.com")
+	t.Setenv("CORS_CREDENTIALED_ORIGINS", "https://app.example.com")
+	t.Setenv("CORS_CREDENTIALED_PATHS", "/users, /admin")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+
+	policies := LoadCORS()
+	credentialed := policyByName(t, policies, "credentialed")
+	require.Equal(t, []string{"/users", "/admin"}, credentialed.PathPrefixes)
+	require.Equal(t, []string{"https://app.example.com"}, credentialed.AllowOrigins)
+	require.True(t, policyByName(t, policies, "public").AllowCredentials)
+}
+
+func TestCORSPolicy_Matches(t *testing.T) {
+	p := CORSPolicy{PathPrefixes: []string{"/users", "/ws/"}}
+
+	require.True(t, p.Matches("/users"))
+	require.True(t, p.Matches("/users/abc/assets"))
+	require.True(t, p.Matches("/ws/chat"))
+	require.False(t, p.Matches("/usersettings"))
+	require.False(t, p.Matches("/assets"))
+	require.True(t, CORSPolicy{}.Matches("/anything"))
+}
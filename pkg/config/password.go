@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordConfig selects how new passwords are hashed. Existing hashes keep working
+// and are rehashed with the current settings when their owner logs in.
+type PasswordConfig struct {
+	Algo       string // "bcrypt" (default) or "argon2id"
+	BcryptCost int    // 0 for bcrypt.DefaultCost
+}
+
+// LoadPassword reads PASSWORD_HASH_ALGO and BCRYPT_COST.
+func LoadPassword() (PasswordConfig, error) {
+	cfg := PasswordConfig{Algo: strings.ToLower(os.Getenv("PASSWORD_HASH_ALGO"))}
+	if cfg.Algo == "" {
+		cfg.Algo = "bcrypt"
+	}
+	if cfg.Algo != "bcrypt" && cfg.Algo != "argon2id" {
+		return cfg, fmt.Errorf("PASSWORD_HASH_ALGO must be bcrypt or argon2id")
+	}
+
+	if raw := os.Getenv("BCRYPT_COST"); raw != "" {
+		cost, err := strconv.Atoi(raw)
+		if err != nil || cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			return cfg, fmt.Errorf("BCRYPT_COST must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		cfg.BcryptCost = cost
+	}
+	return cfg, nil
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPassword(t *testing.T) {
+	t.Setenv("PASSWORD_HASH_ALGO", "")
+	t.Setenv("BCRYPT_COST", "")
+
+	cfg, err := LoadPassword()
+	require.NoError(t, err)
+	require.Equal(t, PasswordConfig{Algo: "bcrypt"}, cfg)
+
+	t.Setenv("PASSWORD_HASH_ALGO", "Argon2id")
+	t.Setenv("BCRYPT_COST", "12")
+	cfg, err = LoadPassword()
+	require.NoError(t, err)
+	require.Equal(t, PasswordConfig{Algo: "argon2id", BcryptCost: 12}, cfg)
+
+	t.Setenv("BCRYPT_COST", "3")
+	_, err = LoadPassword()
+	require.EqualError(t, err, "BCRYPT_COST must be between 4 and 31")
+
+	t.Setenv("PASSWORD_HASH_ALGO", "scrypt")
+	_, err = LoadPassword()
+	require.EqualError(t, err, "PASSWORD_HASH_ALGO must be bcrypt or argon2id")
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"grveyard/pkg/awsauth"
+)
+
+// SecretProvider fetches a secret from an external store. path is everything after
+// the scheme in a reference, minus the optional #key.
+type SecretProvider interface {
+	Fetch(ctx context.Context, path string) (string, error)
+}
+
+// Secrets replaces environment variables holding secret references with the secrets
+// they point to, so the rest of the code keeps reading plain env. A reference looks
+// like
+//
+//	DATABASE_URL=vault://secret/grveyard#DATABASE_URL
+//	SENDGRID_API_KEY=aws-sm://prod/grveyard#sendgrid_api_key
+//
+// The #key selects a field when the secret is a JSON object (Vault KV entries always
+// are); without it the whole secret is used.
+type Secrets struct {
+	mu        sync.Mutex
+	providers map[string]SecretProvider // by scheme
+	refs      map[string]string         // env var -> reference
+}
+
+// NewSecrets creates a resolver for the given scheme -> provider map.
+func NewSecrets(providers map[string]SecretProvider) *Secrets {
+	return &Secrets{providers: providers, refs: make(map[string]string)}
+}
+
+// LoadSecrets registers vault:// when VAULT_ADDR is set and aws-sm:// when AWS_REGION
+// is set, then resolves every reference in the environment.
+func LoadSecrets(ctx context.Context) (*Secrets, error) {
+	providers := make(map[string]SecretProvider)
+	if addr := os.Getenv("VAULT_ADDR"); addr != "" {
+		providers["vault"] = NewVaultProvider(addr, os.Getenv("VAULT_TOKEN"), os.Getenv("VAULT_NAMESPACE"))
+	}
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		providers["aws-sm"] = NewAWSSecretsManagerProvider(region, awsauth.CredentialsFromEnv())
+	}
+
+	s := NewSecrets(providers)
+	return s, s.Resolve(ctx)
+}
+
+// Resolve looks up every reference and writes the secrets into the environment. It is
+// safe to call again to pick up rotated secrets: references found on the first call
+// are remembered after their variables have been overwritten. On error nothing is
+// changed, so a store outage during a reload keeps the previous values.
+//
+// Values read once at startup (UNSUBSCRIBE_SECRET, ADMIN_API_TOKEN) still need a
+// restart to rotate; DATABASE_URL and SENDGRID_API_KEY are re-read on use.
+func (s *Secrets) Resolve(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if scheme, _, ok := strings.Cut(value, "://"); ok && isSecretScheme(scheme) {
+			s.refs[key] = value
+		}
+	}
+	if len(s.refs) == 0 {
+		return nil
+	}
+
+	fetched := make(map[string]string) // per scheme://path, so one secret serves many keys
+	resolved := make(map[string]string, len(s.refs))
+	for key, ref := range s.refs {
+		value, err := s.lookup(ctx, ref, fetched)
+		if err != nil {
+			return fmt.Errorf("resolve %s: %w", key, err)
+		}
+		resolved[key] = value
+	}
+
+	var changed []string
+	for key, value := range resolved {
+		if os.Getenv(key) != value {
+			if err := os.Setenv(key, value); err != nil {
+				return err
+			}
+			changed = append(changed, key)
+		}
+	}
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		log.Printf("Loaded secrets: %s", strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+// isSecretScheme keeps ordinary URLs such as postgres:// and https:// from being
+// mistaken for references.
+func isSecretScheme(scheme string) bool {
+	return scheme == "vault" || scheme == "aws-sm"
+}
+
+func (s *Secrets) lookup(ctx context.Context, ref string, fetched map[string]string) (string, error) {
+	scheme, rest, _ := strings.Cut(ref, "://")
+	path, field, _ := strings.Cut(rest, "#")
+	provider, ok := s.providers[scheme]
+	if !ok {
+		return "", fmt.Errorf("no provider configured for %s:// references", scheme)
+	}
+
+	cacheKey := scheme + "://" + path
+	raw, ok := fetched[cacheKey]
+	if !ok {
+		var err error
+		if raw, err = provider.Fetch(ctx, path); err != nil {
+			return "", err
+		}
+		fetched[cacheKey] = raw
+	}
+	if field == "" {
+		return raw, nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return "", fmt.Errorf("%s is not a JSON object, cannot select #%s", path, field)
+	}
+	v, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("%s has no field %q", path, field)
+	}
+	if str, ok := v.(string); ok {
+		return str, nil
+	}
+	return fmt.Sprint(v), nil
+}
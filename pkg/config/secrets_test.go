@@ -0,0 +1,102 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/awsauth"
+)
+
+type fakeProvider struct {
+	secrets map[string]string
+	calls   int
+	err     error
+}
+
+func (f *fakeProvider) Fetch(_ context.Context, path string) (string, error) {
+	f.calls++
+	if f.err != nil {
+		return "", f.err
+	}
+	v, ok := f.secrets[path]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return v, nil
+}
+
+func TestSecrets_Resolve(t *testing.T) {
+	provider := &fakeProvider{secrets: map[string]string{
+		"secret/grveyard": `{"DATABASE_URL":"postgres://app:pw1@db/grveyard","SENDGRID_API_KEY":"SG.one"}`,
+	}}
+	t.Setenv("DATABASE_URL", "vault://secret/grveyard#DATABASE_URL")
+	t.Setenv("SENDGRID_API_KEY", "vault://secret/grveyard#SENDGRID_API_KEY")
+	t.Setenv("PUBLIC_BASE_URL", "https://grveyard.example")
+
+	s := NewSecrets(map[string]SecretProvider{"vault": provider})
+	require.NoError(t, s.Resolve(context.Background()))
+
+	require.Equal(t, "postgres://app:pw1@db/grveyard", os.Getenv("DATABASE_URL"))
+	require.Equal(t, "SG.one", os.Getenv("SENDGRID_API_KEY"))
+	require.Equal(t, "https://grveyard.example", os.Getenv("PUBLIC_BASE_URL"))
+	require.Equal(t, 1, provider.calls, "one fetch per secret path")
+
+	// rotation: the remembered reference is looked up again
+	provider.secrets["secret/grveyard"] = `{"DATABASE_URL":"postgres://app:pw2@db/grveyard","SENDGRID_API_KEY":"SG.one"}`
+	require.NoError(t, s.Resolve(context.Background()))
+	require.Equal(t, "postgres://app:pw2@db/grveyard", os.Getenv("DATABASE_URL"))
+
+	// a failing reload keeps the previous values
+	provider.err = errors.New("vault sealed")
+	require.Error(t, s.Resolve(context.Background()))
+	require.Equal(t, "postgres://app:pw2@db/grveyard", os.Getenv("DATABASE_URL"))
+}
+
+func TestSecrets_Resolve_Errors(t *testing.T) {
+	t.Setenv("SENDGRID_API_KEY", "aws-sm://prod/grveyard")
+	err := NewSecrets(nil).Resolve(context.Background())
+	require.EqualError(t, err, "resolve SENDGRID_API_KEY: no provider configured for aws-sm:// references")
+
+	t.Setenv("SENDGRID_API_KEY", "aws-sm://prod/grveyard#missing")
+	provider := &fakeProvider{secrets: map[string]string{"prod/grveyard": `{"key":"v"}`}}
+	err = NewSecrets(map[string]SecretProvider{"aws-sm": provider}).Resolve(context.Background())
+	require.EqualError(t, err, `resolve SENDGRID_API_KEY: prod/grveyard has no field "missing"`)
+}
+
+func TestVaultProvider_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/secret/data/grveyard/prod", r.URL.Path)
+		require.Equal(t, "root-token", r.Header.Get("X-Vault-Token"))
+		_, _ = w.Write([]byte(`{"data":{"data":{"DATABASE_URL":"postgres://x"},"metadata":{"version":3}}}`))
+	}))
+	defer srv.Close()
+
+	got, err := NewVaultProvider(srv.URL, "root-token", "").Fetch(context.Background(), "secret/grveyard/prod")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"DATABASE_URL":"postgres://x"}`, got)
+
+	_, err = NewVaultProvider(srv.URL, "root-token", "").Fetch(context.Background(), "secret")
+	require.Error(t, err)
+}
+
+func TestAWSSecretsManagerProvider_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		require.Contains(t, r.Header.Get("Authorization"), "Credential=AKID/")
+		_, _ = w.Write([]byte(`{"Name":"prod/grveyard","SecretString":"{\"sendgrid_api_key\":\"SG.two\"}"}`))
+	}))
+	defer srv.Close()
+
+	p := NewAWSSecretsManagerProvider("eu-west-1", awsauth.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"})
+	p.endpoint = srv.URL
+
+	got, err := p.Fetch(context.Background(), "prod/grveyard")
+	require.NoError(t, err)
+	require.Equal(t, `{"sendgrid_api_key":"SG.two"}`, got)
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ServerConfig holds the http.Server limits. The defaults are short enough to shed
+// slowloris-style clients while leaving room for large JSON bodies on slow links.
+type ServerConfig struct {
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+}
+
+// LoadServer reads HTTP_READ_HEADER_TIMEOUT (5s), HTTP_READ_TIMEOUT (15s),
+// HTTP_WRITE_TIMEOUT (30s), HTTP_IDLE_TIMEOUT (2m) and HTTP_MAX_HEADER_BYTES (64 KiB).
+// A zero duration disables that timeout. WebSocket routes clear the read and write
+// deadlines when they upgrade, so the timeouts only apply to ordinary requests.
+func LoadServer() ServerConfig {
+	return ServerConfig{
+		ReadHeaderTimeout: durationEnv("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		ReadTimeout:       durationEnv("HTTP_READ_TIMEOUT", 15*time.Second),
+		WriteTimeout:      durationEnv("HTTP_WRITE_TIMEOUT", 30*time.Second),
+		IdleTimeout:       durationEnv("HTTP_IDLE_TIMEOUT", 2*time.Minute),
+		MaxHeaderBytes:    intEnv("HTTP_MAX_HEADER_BYTES", 64<<10),
+	}
+}
+
+func durationEnv(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Printf("Invalid duration for %s, using default: %s", key, def)
+		return def
+	}
+	return d
+}
+
+func intEnv(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		log.Printf("Invalid value for %s, using default: %d", key, def)
+		return def
+	}
+	return n
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadServer_Defaults(t *testing.T) {
+	for _, key := range []string{"HTTP_READ_HEADER_TIMEOUT", "HTTP_READ_TIMEOUT", "HTTP_WRITE_TIMEOUT", "HTTP_IDLE_TIMEOUT", "HTTP_MAX_HEADER_BYTES"} {
+		t.Setenv(key, "")
+	}
+
+	cfg := LoadServer()
+
+	require.Equal(t, 5*time.Second, cfg.ReadHeaderTimeout)
+	require.Equal(t, 15*time.Second, cfg.ReadTimeout)
+	require.Equal(t, 30*time.Second, cfg.WriteTimeout)
+	require.Equal(t, 2*time.Minute, cfg.IdleTimeout)
+	require.Equal(t, 64<<10, cfg.MaxHeaderBytes)
+}
+
+func TestLoadServer_Overrides(t *testing.T) {
+	t.Setenv("HTTP_READ_HEADER_TIMEOUT", "2s")
+	t.Setenv("HTTP_READ_TIMEOUT", "0")
+	t.Setenv("HTTP_WRITE_TIMEOUT", "1m")
+	t.Setenv("HTTP_IDLE_TIMEOUT", "bogus")
+	t.Setenv("HTTP_MAX_HEADER_BYTES", "-1")
+
+	cfg := LoadServer()
+
+	require.Equal(t, 2*time.Second, cfg.ReadHeaderTimeout)
+	require.Zero(t, cfg.ReadTimeout, "zero disables the timeout")
+	require.Equal(t, time.Minute, cfg.WriteTimeout)
+	require.Equal(t, 2*time.Minute, cfg.IdleTimeout, "invalid values fall back to the default")
+	require.Equal(t, 64<<10, cfg.MaxHeaderBytes)
+}
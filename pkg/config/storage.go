@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// StorageConfig selects and configures the blob store.
+type StorageConfig struct {
+	Driver string // "local" (default) or "s3"
+
+	// local
+	LocalDir       string // files are written below this directory
+	PublicURL      string // base URL presigned local links point at, normally this API
+	SigningSecret  string // signs local presigned links; a random one is used when empty
+	MaxUploadBytes int    // cap on a single presigned local upload
+
+	// s3 (also R2, MinIO and other S3-compatible stores via S3Endpoint)
+	S3Bucket    string
+	S3Region    string
+	S3Endpoint  string // e.g. https://<account>.r2.cloudflarestorage.com; empty for AWS
+	S3PathStyle bool   // address objects as <endpoint>/<bucket>/<key>, needed by MinIO
+}
+
+// LoadStorage reads STORAGE_DRIVER, STORAGE_LOCAL_DIR (default "uploads"),
+// STORAGE_PUBLIC_URL (default API_BASE_URL), STORAGE_SIGNING_SECRET,
+// STORAGE_MAX_UPLOAD_BYTES (25 MiB), S3_BUCKET, S3_REGION (default AWS_REGION),
+// S3_ENDPOINT and S3_FORCE_PATH_STYLE. S3 credentials come from the usual AWS_*
+// variables.
+func LoadStorage() (StorageConfig, error) {
+	cfg := StorageConfig{
+		Driver:         strings.ToLower(os.Getenv("STORAGE_DRIVER")),
+		LocalDir:       os.Getenv("STORAGE_LOCAL_DIR"),
+		PublicURL:      os.Getenv("STORAGE_PUBLIC_URL"),
+		SigningSecret:  os.Getenv("STORAGE_SIGNING_SECRET"),
+		MaxUploadBytes: intEnv("STORAGE_MAX_UPLOAD_BYTES", 25<<20),
+		S3Bucket:       os.Getenv("S3_BUCKET"),
+		S3Region:       os.Getenv("S3_REGION"),
+		S3Endpoint:     os.Getenv("S3_ENDPOINT"),
+		S3PathStyle:    strings.EqualFold(os.Getenv("S3_FORCE_PATH_STYLE"), "true"),
+	}
+	if cfg.Driver == "" {
+		cfg.Driver = "local"
+	}
+	if cfg.LocalDir == "" {
+		cfg.LocalDir = "uploads"
+	}
+	if cfg.PublicURL == "" {
+		cfg.PublicURL = os.Getenv("API_BASE_URL")
+	}
+	cfg.PublicURL = strings.TrimSuffix(cfg.PublicURL, "/")
+	if cfg.S3Region == "" {
+		cfg.S3Region = os.Getenv("AWS_REGION")
+	}
+
+	switch cfg.Driver {
+	case "local":
+	case "s3":
+		if cfg.S3Bucket == "" {
+			return cfg, errors.New("STORAGE_DRIVER=s3 requires S3_BUCKET")
+		}
+		if cfg.S3Region == "" {
+			return cfg, errors.New("STORAGE_DRIVER=s3 requires S3_REGION or AWS_REGION")
+		}
+	default:
+		return cfg, errors.New("STORAGE_DRIVER must be local or s3")
+	}
+	return cfg, nil
+}
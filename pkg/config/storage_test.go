@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStorage_Defaults(t *testing.T) {
+	t.Setenv("STORAGE_DRIVER", "")
+	t.Setenv("STORAGE_LOCAL_DIR", "")
+	t.Setenv("STORAGE_PUBLIC_URL", "")
+	t.Setenv("STORAGE_MAX_UPLOAD_BYTES", "")
+	t.Setenv("API_BASE_URL", "https://api.example.com/")
+
+	cfg, err := LoadStorage()
+
+	require.NoError(t, err)
+	require.Equal(t, "local", cfg.Driver)
+	require.Equal(t, "uploads", cfg.LocalDir)
+	require.Equal(t, "https://api.example.com", cfg.PublicURL)
+	require.Equal(t, 25<<20, cfg.MaxUploadBytes)
+}
+
+func TestLoadStorage_S3(t *testing.T) {
+	t.Setenv("STORAGE_DRIVER", "s3")
+	t.Setenv("S3_BUCKET", "")
+	t.Setenv("S3_REGION", "")
+	t.Setenv("AWS_REGION", "eu-west-1")
+
+	_, err := LoadStorage()
+	require.EqualError(t, err, "STORAGE_DRIVER=s3 requires S3_BUCKET")
+
+	t.Setenv("S3_BUCKET", "grveyard")
+	cfg, err := LoadStorage()
+	require.NoError(t, err)
+	require.Equal(t, "eu-west-1", cfg.S3Region)
+
+	t.Setenv("STORAGE_DRIVER", "gcs")
+	_, err = LoadStorage()
+	require.EqualError(t, err, "STORAGE_DRIVER must be local or s3")
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VaultProvider reads HashiCorp Vault KV version 2 secrets. A path of
+// "secret/grveyard" reads the "grveyard" entry from the "secret" mount and returns
+// its fields as a JSON object.
+type VaultProvider struct {
+	addr      string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+func NewVaultProvider(addr, token, namespace string) *VaultProvider {
+	return &VaultProvider{
+		addr:      strings.TrimSuffix(addr, "/"),
+		token:     token,
+		namespace: namespace,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *VaultProvider) Fetch(ctx context.Context, path string) (string, error) {
+	mount, entry, ok := strings.Cut(strings.Trim(path, "/"), "/")
+	if !ok || entry == "" {
+		return "", fmt.Errorf("vault path %q must be <mount>/<secret>", path)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.addr+"/v1/"+mount+"/data/"+entry, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("vault: %s reading %s: %s", resp.Status, path, strings.TrimSpace(string(body)))
+	}
+
+	var out struct {
+		Data struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("vault: decode %s: %w", path, err)
+	}
+	if len(out.Data.Data) == 0 || string(out.Data.Data) == "null" {
+		return "", fmt.Errorf("vault: %s has no data", path)
+	}
+	return string(out.Data.Data), nil
+}
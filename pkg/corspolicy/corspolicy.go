@@ -0,0 +1,92 @@
+package corspolicy
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/config"
+)
+
+// Headers are shared by every policy.
+type Headers struct {
+	Allow  []string // request headers browsers may send
+	Expose []string // response headers scripts may read
+}
+
+type group struct {
+	policy  config.CORSPolicy
+	methods map[string]bool
+	headers map[string]bool
+	handler gin.HandlerFunc // nil when the group refuses all cross-origin requests
+}
+
+// Middleware applies the first policy whose path prefixes match the request. On top
+// of gin-contrib/cors it refuses preflights asking for a method or header the group
+// does not allow, instead of answering 204 and leaving the browser to fail later.
+func Middleware(policies []config.CORSPolicy, h Headers) gin.HandlerFunc {
+	groups := make([]group, 0, len(policies))
+	for _, p := range policies {
+		g := group{policy: p, methods: make(map[string]bool), headers: make(map[string]bool)}
+		for _, m := range p.AllowMethods {
+			g.methods[strings.ToUpper(m)] = true
+		}
+		for _, name := range h.Allow {
+			g.headers[strings.ToLower(name)] = true
+		}
+		if len(p.AllowOrigins) > 0 {
+			g.handler = cors.New(cors.Config{
+				AllowOrigins:     p.AllowOrigins,
+				AllowMethods:     p.AllowMethods,
+				AllowHeaders:     h.Allow,
+				ExposeHeaders:    h.Expose,
+				AllowCredentials: p.AllowCredentials,
+				MaxAge:           12 * time.Hour,
+			})
+		}
+		groups = append(groups, g)
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" || origin == "http://"+c.Request.Host || origin == "https://"+c.Request.Host {
+			c.Next()
+			return
+		}
+
+		var g *group
+		for i := range groups {
+			if groups[i].policy.Matches(c.Request.URL.Path) {
+				g = &groups[i]
+				break
+			}
+		}
+		if g == nil || g.handler == nil {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		if c.Request.Method == http.MethodOptions && !g.allowsPreflight(c.Request) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		g.handler(c)
+	}
+}
+
+func (g *group) allowsPreflight(r *http.Request) bool {
+	method := r.Header.Get("Access-Control-Request-Method")
+	if method != "" && !g.methods[strings.ToUpper(method)] {
+		return false
+	}
+	for _, name := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && !g.headers[name] {
+			return false
+		}
+	}
+	return true
+}
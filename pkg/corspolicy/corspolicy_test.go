@@ -0,0 +1,101 @@
+package corspolicy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/config"
+)
+
+const appOrigin = "https://app.example.com"
+
+func setupRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	methods := []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	r := gin.New()
+	r.Use(Middleware([]config.CORSPolicy{
+		{Name: "server", PathPrefixes: []string{"/webhooks"}},
+		{Name: "credentialed", PathPrefixes: []string{"/users", "/chat"}, AllowOrigins: []string{appOrigin}, AllowMethods: methods, AllowCredentials: true},
+		{Name: "public", AllowOrigins: []string{"*"}, AllowMethods: []string{"GET", "OPTIONS"}},
+	}, Headers{Allow: []string{"Content-Type", "X-Request-ID"}, Expose: []string{"ETag"}}))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/assets", ok)
+	r.GET("/users", ok)
+	r.GET("/chat/status", ok)
+	r.POST("/webhooks/sendgrid", ok)
+	return r
+}
+
+func preflight(r *gin.Engine, path, origin, method, headers string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, path, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		req.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestPreflight_PublicGroup(t *testing.T) {
+	r := setupRouter()
+
+	w := preflight(r, "/assets", "https://anyone.example.org", "GET", "content-type")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	require.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+
+	// Methods outside the group's list are refused up front
+	w = preflight(r, "/assets", "https://anyone.example.org", "DELETE", "")
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestPreflight_CredentialedGroup(t *testing.T) {
+	r := setupRouter()
+
+	for _, path := range []string{"/users", "/chat/status"} {
+		w := preflight(r, path, appOrigin, "PUT", "Content-Type, X-Request-ID")
+		require.Equal(t, http.StatusNoContent, w.Code, path)
+		require.Equal(t, appOrigin, w.Header().Get("Access-Control-Allow-Origin"), path)
+		require.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"), path)
+
+		w = preflight(r, path, "https://evil.example.org", "GET", "")
+		require.Equal(t, http.StatusForbidden, w.Code, path)
+		require.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), path)
+	}
+
+	// Headers not on the allow list are refused
+	w := preflight(r, "/users", appOrigin, "GET", "X-Custom")
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestPreflight_ServerGroup(t *testing.T) {
+	r := setupRouter()
+
+	w := preflight(r, "/webhooks/sendgrid", appOrigin, "POST", "")
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// Server-to-server calls carry no Origin and are unaffected
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/sendgrid", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSimpleRequest_ExposesHeaders(t *testing.T) {
+	r := setupRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("Origin", appOrigin)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, appOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "Etag", w.Header().Get("Access-Control-Expose-Headers"))
+}
@@ -0,0 +1,124 @@
+// Package currency converts listing prices, stored in BaseCurrency, into a buyer's
+// display currency using daily exchange rates from a pluggable Provider.
+package currency
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/requestid"
+)
+
+// BaseCurrency is the currency every listing price is stored in.
+const BaseCurrency = "USD"
+
+// DefaultTTL is how long fetched rates are served before refetching. Providers
+// publish once per business day.
+const DefaultTTL = 24 * time.Hour
+
+var (
+	ErrUnavailable = apperr.New(apperr.ServiceUnavailable, "exchange rates unavailable")
+	ErrUnsupported = apperr.New(apperr.UnsupportedCurrency, "unsupported display_currency")
+)
+
+// Rates are units of each currency per one unit of Base, as published on Date.
+type Rates struct {
+	Base  string
+	Date  string // YYYY-MM-DD
+	Rates map[string]float64
+}
+
+// Provider fetches the latest published rates.
+type Provider interface {
+	Latest(ctx context.Context) (Rates, error)
+}
+
+// Amount is a price in a given currency.
+type Amount struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// Quote converts BaseCurrency amounts into Currency.
+type Quote struct {
+	Currency string
+	Rate     float64
+	AsOf     string
+}
+
+// Convert returns amount in the quote currency, rounded to cents.
+func (q Quote) Convert(amount float64) Amount {
+	return Amount{Amount: math.Round(amount*q.Rate*100) / 100, Currency: q.Currency}
+}
+
+// Service caches the provider's rates for ttl. When a refresh fails it keeps serving
+// the last rates it has, so a provider outage only matters on a cold start.
+type Service struct {
+	provider Provider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	rates   Rates
+	fetched time.Time
+}
+
+// NewService returns a service caching provider's rates for DefaultTTL.
+func NewService(provider Provider) *Service {
+	return &Service{provider: provider, ttl: DefaultTTL, now: time.Now}
+}
+
+// Quote returns the rate from BaseCurrency to code, a three-letter ISO 4217 code.
+func (s *Service) Quote(ctx context.Context, code string) (Quote, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return Quote{}, ErrUnsupported
+	}
+
+	rates, err := s.latest(ctx)
+	if err != nil {
+		return Quote{}, err
+	}
+	to, ok := rates.rate(code)
+	if !ok {
+		return Quote{}, ErrUnsupported
+	}
+	from, ok := rates.rate(BaseCurrency)
+	if !ok {
+		return Quote{}, fmt.Errorf("%w: no %s rate", ErrUnavailable, BaseCurrency)
+	}
+	return Quote{Currency: code, Rate: to / from, AsOf: rates.Date}, nil
+}
+
+func (s *Service) latest(ctx context.Context) (Rates, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fetched.IsZero() && s.now().Sub(s.fetched) < s.ttl {
+		return s.rates, nil
+	}
+	rates, err := s.provider.Latest(ctx)
+	if err != nil {
+		if s.fetched.IsZero() {
+			return Rates{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+		log.Printf("[%s] exchange rates refresh failed, serving rates from %s: %v", requestid.FromContext(ctx), s.rates.Date, err)
+		return s.rates, nil
+	}
+	s.rates, s.fetched = rates, s.now()
+	return rates, nil
+}
+
+func (r Rates) rate(code string) (float64, bool) {
+	if code == r.Base {
+		return 1, true
+	}
+	v, ok := r.Rates[code]
+	return v, ok && v > 0
+}
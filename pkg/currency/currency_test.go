@@ -0,0 +1,65 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	rates Rates
+	err   error
+	calls int
+}
+
+func (p *fakeProvider) Latest(ctx context.Context) (Rates, error) {
+	p.calls++
+	return p.rates, p.err
+}
+
+func TestService_Quote(t *testing.T) {
+	provider := &fakeProvider{rates: Rates{Base: "EUR", Date: "2025-06-02", Rates: map[string]float64{"USD": 1.25, "INR": 100}}}
+	svc := NewService(provider)
+
+	q, err := svc.Quote(context.Background(), "inr")
+	require.NoError(t, err)
+	require.Equal(t, "INR", q.Currency)
+	require.Equal(t, Amount{Amount: 8000, Currency: "INR"}, q.Convert(100))
+
+	q, err = svc.Quote(context.Background(), "EUR")
+	require.NoError(t, err)
+	require.Equal(t, 0.8, q.Rate)
+
+	_, err = svc.Quote(context.Background(), "XYZ")
+	require.ErrorIs(t, err, ErrUnsupported)
+	_, err = svc.Quote(context.Background(), "dollars")
+	require.ErrorIs(t, err, ErrUnsupported)
+	require.Equal(t, 1, provider.calls)
+}
+
+func TestService_RefreshesDailyAndKeepsStaleRates(t *testing.T) {
+	provider := &fakeProvider{rates: Rates{Base: "USD", Date: "2025-06-02", Rates: map[string]float64{"INR": 80}}}
+	svc := NewService(provider)
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	_, err := svc.Quote(context.Background(), "INR")
+	require.NoError(t, err)
+
+	now = now.Add(DefaultTTL)
+	provider.err = errors.New("provider down")
+	q, err := svc.Quote(context.Background(), "INR")
+	require.NoError(t, err)
+	require.Equal(t, 80.0, q.Rate)
+	require.Equal(t, 2, provider.calls)
+}
+
+func TestService_ColdStartFailure(t *testing.T) {
+	svc := NewService(&fakeProvider{err: errors.New("provider down")})
+
+	_, err := svc.Quote(context.Background(), "INR")
+	require.ErrorIs(t, err, ErrUnavailable)
+}
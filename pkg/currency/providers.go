@@ -0,0 +1,125 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ecbURL               = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+	openExchangeRatesURL = "https://openexchangerates.org/api/latest.json"
+)
+
+// NewProvider picks a provider by name: "ecb" (no key needed) or "openexchangerates"
+// (needs appID). It returns nil for an empty name so callers can keep conversion
+// optional.
+func NewProvider(name, appID string) (Provider, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	switch strings.ToLower(name) {
+	case "":
+		return nil, nil
+	case "ecb":
+		return &ECB{url: ecbURL, client: client}, nil
+	case "openexchangerates":
+		if appID == "" {
+			return nil, fmt.Errorf("openexchangerates needs an app id")
+		}
+		return &OpenExchangeRates{url: openExchangeRatesURL, appID: appID, client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown exchange rate provider %q", name)
+	}
+}
+
+// ECB reads the European Central Bank's daily reference rates, quoted per euro.
+type ECB struct {
+	url    string
+	client *http.Client
+}
+
+func (p *ECB) Latest(ctx context.Context) (Rates, error) {
+	var doc struct {
+		Cube struct {
+			Cube struct {
+				Time  string `xml:"time,attr"`
+				Rates []struct {
+					Currency string  `xml:"currency,attr"`
+					Rate     float64 `xml:"rate,attr"`
+				} `xml:"Cube"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	}
+	body, err := get(ctx, p.client, p.url)
+	if err != nil {
+		return Rates{}, err
+	}
+	defer body.Close()
+	if err := xml.NewDecoder(body).Decode(&doc); err != nil {
+		return Rates{}, fmt.Errorf("decode ecb rates: %w", err)
+	}
+
+	rates := Rates{Base: "EUR", Date: doc.Cube.Cube.Time, Rates: make(map[string]float64, len(doc.Cube.Cube.Rates))}
+	for _, r := range doc.Cube.Cube.Rates {
+		rates.Rates[r.Currency] = r.Rate
+	}
+	if len(rates.Rates) == 0 {
+		return Rates{}, fmt.Errorf("ecb returned no rates")
+	}
+	return rates, nil
+}
+
+// OpenExchangeRates reads openexchangerates.org's latest rates, quoted per US dollar
+// on the free plan.
+type OpenExchangeRates struct {
+	url    string
+	appID  string
+	client *http.Client
+}
+
+func (p *OpenExchangeRates) Latest(ctx context.Context) (Rates, error) {
+	var doc struct {
+		Base      string             `json:"base"`
+		Timestamp int64              `json:"timestamp"`
+		Rates     map[string]float64 `json:"rates"`
+	}
+	body, err := get(ctx, p.client, p.url+"?app_id="+url.QueryEscape(p.appID))
+	if err != nil {
+		return Rates{}, err
+	}
+	defer body.Close()
+	if err := json.NewDecoder(body).Decode(&doc); err != nil {
+		return Rates{}, fmt.Errorf("decode openexchangerates rates: %w", err)
+	}
+	if len(doc.Rates) == 0 {
+		return Rates{}, fmt.Errorf("openexchangerates returned no rates")
+	}
+	return Rates{Base: doc.Base, Date: time.Unix(doc.Timestamp, 0).UTC().Format(time.DateOnly), Rates: doc.Rates}, nil
+}
+
+func get(ctx context.Context, client *http.Client, u string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The app id travels in the query string, so errors name only the host
+	resp, err := client.Do(req)
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return nil, fmt.Errorf("exchange rates from %s: %w", req.URL.Host, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("exchange rates from %s: status %d", req.URL.Host, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,56 @@
+package currency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestECB_Latest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<Cube>
+		<Cube time="2025-06-02">
+			<Cube currency="USD" rate="1.1389"/>
+			<Cube currency="INR" rate="97.515"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`))
+	}))
+	defer srv.Close()
+
+	rates, err := (&ECB{url: srv.URL, client: srv.Client()}).Latest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "EUR", rates.Base)
+	require.Equal(t, "2025-06-02", rates.Date)
+	require.Equal(t, 97.515, rates.Rates["INR"])
+}
+
+func TestOpenExchangeRates_Latest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "secret", r.URL.Query().Get("app_id"))
+		w.Write([]byte(`{"base":"USD","timestamp":1748822400,"rates":{"INR":85.6,"EUR":0.878}}`))
+	}))
+	defer srv.Close()
+
+	rates, err := (&OpenExchangeRates{url: srv.URL, appID: "secret", client: srv.Client()}).Latest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "USD", rates.Base)
+	require.Equal(t, "2025-06-02", rates.Date)
+	require.Equal(t, 85.6, rates.Rates["INR"])
+}
+
+func TestOpenExchangeRates_ErrorHidesAppID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	_, err := (&OpenExchangeRates{url: srv.URL, appID: "secret", client: srv.Client()}).Latest(context.Background())
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "secret")
+}
@@ -0,0 +1,87 @@
+package dashboard
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+type DashboardHandler struct {
+	service *Service
+}
+
+func NewDashboardHandler(service *Service) *DashboardHandler {
+	return &DashboardHandler{service: service}
+}
+
+func (h *DashboardHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/users/:uuid/dashboard/seller", auth.RequireSelf("uuid"), h.seller)
+	router.GET("/users/:uuid/dashboard/buyer", auth.RequireSelf("uuid"), h.buyer)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *DashboardHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/dashboard/seller",
+			Tag:         "dashboard",
+			Summary:     "Seller dashboard summary",
+			Description: "Active listings, listing views over the last 7 and 30 days, pending offers on unsold assets, unread messages and earnings from recorded sales, with the tax collected on them, in one response. Views are only counted when analytics events are stored in Postgres.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Response: SellerSummary{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/dashboard/buyer",
+			Tag:         "dashboard",
+			Summary:     "Buyer dashboard summary",
+			Description: "The latest updates to assets on the buyer's favorites (price changes and sold items), pending offers made, conversations active in the last 30 days and the latest purchases in one response.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Response: BuyerSummary{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+func (h *DashboardHandler) seller(c *gin.Context) {
+	uid := c.Param("uuid")
+	if uid == "" {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid user uuid"))
+		return
+	}
+
+	summary, err := h.service.Seller(c.Request.Context(), uid)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "seller dashboard fetched", summary)
+}
+
+func (h *DashboardHandler) buyer(c *gin.Context) {
+	uid := c.Param("uuid")
+	if uid == "" {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid user uuid"))
+		return
+	}
+
+	summary, err := h.service.Buyer(c.Request.Context(), uid)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "buyer dashboard fetched", summary)
+}
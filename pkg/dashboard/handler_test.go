@@ -0,0 +1,114 @@
+package dashboard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/auth"
+	"grveyard/pkg/testhelpers"
+)
+
+type mockDashboardRepository struct {
+	mock.Mock
+}
+
+func (m *mockDashboardRepository) SellerListings(ctx context.Context, userUUID string) (SellerSummary, error) {
+	args := m.Called(ctx, userUUID)
+	return args.Get(0).(SellerSummary), args.Error(1)
+}
+
+func (m *mockDashboardRepository) ListingViews(ctx context.Context, userUUID string, week, month time.Time) (int64, int64, error) {
+	args := m.Called(ctx, userUUID, week, month)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockDashboardRepository) ActiveConversations(ctx context.Context, userUUID string, since time.Time) (int64, error) {
+	args := m.Called(ctx, userUUID, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockDashboardRepository) OpenOffersMade(ctx context.Context, userUUID string) (int64, error) {
+	args := m.Called(ctx, userUUID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockDashboardRepository) WatchlistUpdates(ctx context.Context, userUUID string, limit int) ([]WatchlistUpdate, error) {
+	args := m.Called(ctx, userUUID, limit)
+	list, _ := args.Get(0).([]WatchlistUpdate)
+	return list, args.Error(1)
+}
+
+func (m *mockDashboardRepository) Purchases(ctx context.Context, userUUID string, limit int) ([]Purchase, error) {
+	args := m.Called(ctx, userUUID, limit)
+	list, _ := args.Get(0).([]Purchase)
+	return list, args.Error(1)
+}
+
+func newRouter(repo DashboardRepository, now time.Time) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	service := NewService(repo)
+	service.now = func() time.Time { return now }
+	r := gin.New()
+	r.Use(testhelpers.AuthAs("admin-uuid", auth.RoleAdmin))
+	NewDashboardHandler(service).RegisterRoutes(r)
+	return r
+}
+
+func TestDashboardHandler_Seller(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	repo := new(mockDashboardRepository)
+	repo.On("SellerListings", mock.Anything, "u1").
+		Return(SellerSummary{ActiveListings: 3, ActiveAssets: 2, ActiveStartups: 1, UnreadBuyerMessages: 4, OpenOffers: 6}, nil)
+	repo.On("ListingViews", mock.Anything, "u1", now.AddDate(0, 0, -7), now.AddDate(0, 0, -30)).
+		Return(int64(5), int64(12), nil)
+
+	w := httptest.NewRecorder()
+	newRouter(repo, now).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/u1/dashboard/seller", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"active_listings":3`)
+	require.Contains(t, w.Body.String(), `"views_7d":5`)
+	require.Contains(t, w.Body.String(), `"views_30d":12`)
+	require.Contains(t, w.Body.String(), `"unread_buyer_messages":4`)
+	require.Contains(t, w.Body.String(), `"open_offers":6`)
+	repo.AssertExpectations(t)
+}
+
+func TestDashboardHandler_Seller_UnknownUser(t *testing.T) {
+	repo := new(mockDashboardRepository)
+	repo.On("SellerListings", mock.Anything, "missing").Return(SellerSummary{}, ErrUserNotFound)
+
+	w := httptest.NewRecorder()
+	newRouter(repo, time.Now()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/missing/dashboard/seller", nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	repo.AssertNotCalled(t, "ListingViews", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDashboardHandler_Buyer(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	repo := new(mockDashboardRepository)
+	repo.On("ActiveConversations", mock.Anything, "u1", now.Add(-conversationWindow)).Return(int64(2), nil)
+	repo.On("OpenOffersMade", mock.Anything, "u1").Return(int64(1), nil)
+	repo.On("WatchlistUpdates", mock.Anything, "u1", buyerListLimit).
+		Return([]WatchlistUpdate{{NotificationID: 9, AssetID: 3, Title: "acme.io", Price: 40, IsSold: true}}, nil)
+	repo.On("Purchases", mock.Anything, "u1", buyerListLimit).
+		Return([]Purchase{{TransactionID: 1, AssetID: 5, Title: "Old App", FinalPrice: 120, Status: PurchaseCompleted}}, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(repo, now).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/u1/dashboard/buyer", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"active_conversations":2`)
+	require.Contains(t, w.Body.String(), `"open_offers":1`)
+	require.Contains(t, w.Body.String(), `"title":"acme.io"`)
+	require.Contains(t, w.Body.String(), `"status":"completed"`)
+	repo.AssertExpectations(t)
+}
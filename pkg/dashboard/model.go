@@ -0,0 +1,67 @@
+package dashboard
+
+import "time"
+
+// SellerSummary is everything the seller home screen shows, gathered in one call.
+//
+// Views come from listing_viewed analytics events, so they stay zero when
+// ANALYTICS_SINK forwards events to a webhook instead of storing them. OpenOffers
+// counts pending offers on the seller's unsold assets.
+//
+// Earnings cover every recorded sale of the seller's assets: Revenue is the sum of
+// final prices and TaxCollected the GST/VAT charged on top of them.
+type SellerSummary struct {
+	ActiveListings      int64   `json:"active_listings"`
+	ActiveAssets        int64   `json:"active_assets"`
+	ActiveStartups      int64   `json:"active_startups"`
+	Views7d             int64   `json:"views_7d"`
+	Views30d            int64   `json:"views_30d"`
+	OpenOffers          int64   `json:"open_offers"`
+	UnreadBuyerMessages int64   `json:"unread_buyer_messages"`
+	Sales               int64   `json:"sales"`
+	Revenue             float64 `json:"revenue"`
+	TaxCollected        float64 `json:"tax_collected"`
+}
+
+// BuyerSummary is everything the buyer home screen shows, gathered in one call.
+//
+// Watchlist updates are the watched_asset_updated notifications in the buyer's
+// inbox, published when an asset they favorited changes price or sells, joined to
+// the asset's current price and availability. OpenOffers counts the buyer's offers
+// still waiting for the seller's answer.
+type BuyerSummary struct {
+	WatchlistUpdates    []WatchlistUpdate `json:"watchlist_updates"`
+	OpenOffers          int64             `json:"open_offers"`
+	ActiveConversations int64             `json:"active_conversations"`
+	Purchases           []Purchase        `json:"purchases"`
+}
+
+// WatchlistUpdate is a recent change to an asset the buyer follows.
+type WatchlistUpdate struct {
+	NotificationID int64     `json:"notification_id"`
+	AssetID        int64     `json:"asset_id"`
+	Title          string    `json:"title"`
+	Price          float64   `json:"price"`
+	IsSold         bool      `json:"is_sold"`
+	Read           bool      `json:"read"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// PurchaseCompleted is the status of every recorded transaction; sales are only
+// written once the seller marks the asset sold.
+const PurchaseCompleted = "completed"
+
+// Purchase is an asset the buyer bought. Total is FinalPrice plus the tax charged on
+// the sale; TaxName is empty when none was.
+type Purchase struct {
+	TransactionID int64     `json:"transaction_id"`
+	AssetID       int64     `json:"asset_id"`
+	Title         string    `json:"title"`
+	FinalPrice    float64   `json:"final_price"`
+	TaxName       string    `json:"tax_name,omitempty"`
+	TaxRate       float64   `json:"tax_rate"`
+	TaxAmount     float64   `json:"tax_amount"`
+	Total         float64   `json:"total"`
+	Status        string    `json:"status"`
+	PurchasedAt   time.Time `json:"purchased_at"`
+}
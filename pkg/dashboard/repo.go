@@ -0,0 +1,176 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/apperr"
+)
+
+var ErrUserNotFound = apperr.New(apperr.UserNotFound, "user not found")
+
+type DashboardRepository interface {
+	// SellerListings fills the listing and message counts of a seller's summary.
+	SellerListings(ctx context.Context, userUUID string) (SellerSummary, error)
+	// ListingViews counts views of the seller's listings since week and since month.
+	ListingViews(ctx context.Context, userUUID string, week, month time.Time) (int64, int64, error)
+	// ActiveConversations counts the people a buyer exchanged messages with since since.
+	ActiveConversations(ctx context.Context, userUUID string, since time.Time) (int64, error)
+	// OpenOffersMade counts a buyer's pending offers on assets still for sale.
+	OpenOffersMade(ctx context.Context, userUUID string) (int64, error)
+	WatchlistUpdates(ctx context.Context, userUUID string, limit int) ([]WatchlistUpdate, error)
+	Purchases(ctx context.Context, userUUID string, limit int) ([]Purchase, error)
+}
+
+type postgresDashboardRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresDashboardRepository(pool *pgxpool.Pool) DashboardRepository {
+	return &postgresDashboardRepository{pool: pool}
+}
+
+func (r *postgresDashboardRepository) SellerListings(ctx context.Context, userUUID string) (SellerSummary, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM assets a
+			 WHERE a.user_uuid = u.uuid AND a.is_active = true AND a.is_sold = false AND a.is_deleted = false),
+			(SELECT COUNT(*) FROM startups s
+			 WHERE s.owner_uuid = u.uuid AND s.status <> 'sold' AND s.is_deleted = false),
+			(SELECT COUNT(*) FROM messages m
+			 WHERE m.receiver_id = u.id AND m.is_read = false),
+			(SELECT COUNT(*) FROM offers o
+			 JOIN assets a ON a.id = o.asset_id
+			 WHERE a.user_uuid = u.uuid AND o.status = 'pending' AND a.is_sold = false AND a.is_deleted = false),
+			e.sales, e.revenue, e.tax
+		FROM users u
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*) AS sales,
+				COALESCE(SUM(t.final_price), 0)::float8 AS revenue,
+				COALESCE(SUM(t.tax_amount), 0)::float8 AS tax
+			FROM transactions t
+			JOIN assets a ON a.id = t.asset_id
+			WHERE a.user_uuid = u.uuid
+		) e
+		WHERE u.uuid = $1 AND u.is_deleted = false`
+
+	var s SellerSummary
+	err := r.pool.QueryRow(ctx, query, userUUID).Scan(&s.ActiveAssets, &s.ActiveStartups, &s.UnreadBuyerMessages, &s.OpenOffers, &s.Sales, &s.Revenue, &s.TaxCollected)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return SellerSummary{}, ErrUserNotFound
+	}
+	if err != nil {
+		return SellerSummary{}, err
+	}
+	s.ActiveListings = s.ActiveAssets + s.ActiveStartups
+	return s, nil
+}
+
+// ListingViews counts listing_viewed events for the seller's assets and startups.
+// Views of listings that have since been deleted still count.
+func (r *postgresDashboardRepository) ListingViews(ctx context.Context, userUUID string, week, month time.Time) (int64, int64, error) {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE e.occurred_at >= $2), COUNT(*)
+		FROM events e
+		WHERE e.name = 'listing_viewed' AND e.occurred_at >= $3
+		  AND (
+			(e.properties->>'listing_type' = 'asset' AND e.properties->>'listing_id' IN
+				(SELECT id::text FROM assets WHERE user_uuid = $1))
+			OR (e.properties->>'listing_type' = 'startup' AND e.properties->>'listing_id' IN
+				(SELECT id::text FROM startups WHERE owner_uuid = $1))
+		  )`
+
+	var last7, last30 int64
+	if err := r.pool.QueryRow(ctx, query, userUUID, week, month).Scan(&last7, &last30); err != nil {
+		return 0, 0, err
+	}
+	return last7, last30, nil
+}
+
+func (r *postgresDashboardRepository) ActiveConversations(ctx context.Context, userUUID string, since time.Time) (int64, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(DISTINCT CASE WHEN m.sender_id = u.id THEN m.receiver_id ELSE m.sender_id END)
+			 FROM messages m
+			 WHERE (m.sender_id = u.id OR m.receiver_id = u.id) AND m.messaged_at >= $2)
+		FROM users u
+		WHERE u.uuid = $1 AND u.is_deleted = false`
+
+	var n int64
+	err := r.pool.QueryRow(ctx, query, userUUID, since.Unix()).Scan(&n)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrUserNotFound
+	}
+	return n, err
+}
+
+func (r *postgresDashboardRepository) OpenOffersMade(ctx context.Context, userUUID string) (int64, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM offers o
+		JOIN assets a ON a.id = o.asset_id
+		WHERE o.buyer_uuid = $1 AND o.status = 'pending' AND a.is_sold = false AND a.is_deleted = false`
+
+	var n int64
+	err := r.pool.QueryRow(ctx, query, userUUID).Scan(&n)
+	return n, err
+}
+
+// WatchlistUpdates skips notifications whose asset has since been deleted.
+func (r *postgresDashboardRepository) WatchlistUpdates(ctx context.Context, userUUID string, limit int) ([]WatchlistUpdate, error) {
+	query := `
+		SELECT n.id, a.id, a.title, COALESCE(a.price, 0)::float8, a.is_sold, n.read_at IS NOT NULL, n.created_at
+		FROM notifications n
+		JOIN assets a ON a.id = n.entity_id AND a.is_deleted = false
+		WHERE n.user_uuid = $1 AND n.type = 'watched_asset_updated'
+		ORDER BY n.id DESC
+		LIMIT $2`
+	rows, err := r.pool.Query(ctx, query, userUUID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []WatchlistUpdate{}
+	for rows.Next() {
+		var w WatchlistUpdate
+		if err := rows.Scan(&w.NotificationID, &w.AssetID, &w.Title, &w.Price, &w.IsSold, &w.Read, &w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, w)
+	}
+	return list, rows.Err()
+}
+
+func (r *postgresDashboardRepository) Purchases(ctx context.Context, userUUID string, limit int) ([]Purchase, error) {
+	query := `
+		SELECT t.id, a.id, a.title, COALESCE(t.final_price, 0)::float8,
+			t.tax_name, t.tax_rate::float8, t.tax_amount::float8, t.created_at
+		FROM transactions t
+		JOIN users u ON u.id = t.buyer_id
+		JOIN assets a ON a.id = t.asset_id
+		WHERE u.uuid = $1
+		ORDER BY t.id DESC
+		LIMIT $2`
+	rows, err := r.pool.Query(ctx, query, userUUID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Purchase{}
+	for rows.Next() {
+		p := Purchase{Status: PurchaseCompleted}
+		if err := rows.Scan(&p.TransactionID, &p.AssetID, &p.Title, &p.FinalPrice, &p.TaxName, &p.TaxRate, &p.TaxAmount, &p.PurchasedAt); err != nil {
+			return nil, err
+		}
+		p.Total = math.Round((p.FinalPrice+p.TaxAmount)*100) / 100
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
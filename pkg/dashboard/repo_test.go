@@ -0,0 +1,136 @@
+package dashboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresDashboardRepository_Seller(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresDashboardRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	buyer := testhelpers.NewUser(t, pool)
+
+	listed := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID))
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetSold())
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetDeleted())
+	startup := testhelpers.NewStartup(t, pool, testhelpers.WithStartupOwner(seller.UUID))
+	testhelpers.NewStartup(t, pool, testhelpers.WithStartupOwner(seller.UUID), testhelpers.WithStartupSold())
+	other := testhelpers.NewAsset(t, pool)
+
+	testhelpers.NewMessage(t, pool, testhelpers.WithSender(buyer.ID), testhelpers.WithReceiver(seller.ID))
+	testhelpers.NewMessage(t, pool, testhelpers.WithSender(buyer.ID), testhelpers.WithReceiver(seller.ID), testhelpers.WithRead())
+	testhelpers.NewMessage(t, pool, testhelpers.WithSender(seller.ID), testhelpers.WithReceiver(buyer.ID))
+
+	offer := func(assetID int64, status string) {
+		_, err := pool.Exec(ctx, `INSERT INTO offers (asset_id, buyer_uuid, amount, status) VALUES ($1, $2, 10, $3)`, assetID, buyer.UUID, status)
+		require.NoError(t, err)
+	}
+	offer(listed.ID, "pending")
+	offer(listed.ID, "rejected")
+	offer(other.ID, "pending")
+
+	summary, err := repo.SellerListings(ctx, seller.UUID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), summary.ActiveAssets)
+	require.Equal(t, int64(1), summary.ActiveStartups)
+	require.Equal(t, int64(2), summary.ActiveListings)
+	require.Equal(t, int64(1), summary.UnreadBuyerMessages)
+	require.Equal(t, int64(1), summary.OpenOffers)
+	require.Zero(t, summary.Sales)
+
+	sold := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetSold())
+	testhelpers.NewTransaction(t, pool, testhelpers.WithTransactionAsset(sold.ID), testhelpers.WithBuyer(buyer.ID),
+		testhelpers.WithFinalPrice(200), testhelpers.WithTax("VAT", 20, 40))
+	testhelpers.NewTransaction(t, pool, testhelpers.WithFinalPrice(999))
+
+	summary, err = repo.SellerListings(ctx, seller.UUID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), summary.Sales)
+	require.Equal(t, 200.0, summary.Revenue)
+	require.Equal(t, 40.0, summary.TaxCollected)
+
+	now := time.Now().UTC()
+	view := func(listingType string, id int64, at time.Time) {
+		_, err := pool.Exec(ctx, `INSERT INTO events (name, properties, occurred_at) VALUES ('listing_viewed', jsonb_build_object('listing_type', $1::text, 'listing_id', $2::bigint), $3)`, listingType, id, at)
+		require.NoError(t, err)
+	}
+	view("asset", listed.ID, now.Add(-time.Hour))
+	view("startup", startup.ID, now.AddDate(0, 0, -10))
+	view("asset", listed.ID, now.AddDate(0, 0, -40))
+	view("asset", other.ID, now.Add(-time.Hour))
+
+	last7, last30, err := repo.ListingViews(ctx, seller.UUID, now.AddDate(0, 0, -7), now.AddDate(0, 0, -30))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), last7)
+	require.Equal(t, int64(2), last30)
+
+	_, err = repo.SellerListings(ctx, "no-such-user")
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestPostgresDashboardRepository_Buyer(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresDashboardRepository(pool)
+	ctx := context.Background()
+	buyer := testhelpers.NewUser(t, pool)
+	seller := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	stale := testhelpers.NewUser(t, pool)
+
+	testhelpers.NewMessage(t, pool, testhelpers.WithSender(buyer.ID), testhelpers.WithReceiver(seller.ID))
+	testhelpers.NewMessage(t, pool, testhelpers.WithSender(seller.ID), testhelpers.WithReceiver(buyer.ID))
+	testhelpers.NewMessage(t, pool, testhelpers.WithSender(stale.ID), testhelpers.WithReceiver(buyer.ID),
+		testhelpers.WithMessagedAt(time.Now().AddDate(0, 0, -60)))
+
+	n, err := repo.ActiveConversations(ctx, buyer.UUID, time.Now().AddDate(0, 0, -30))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	forSale := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID))
+	soldOut := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetSold())
+	for _, id := range []int64{forSale.ID, soldOut.ID} {
+		_, err := pool.Exec(ctx, `INSERT INTO offers (asset_id, buyer_uuid, amount) VALUES ($1, $2, 10)`, id, buyer.UUID)
+		require.NoError(t, err)
+	}
+	offers, err := repo.OpenOffersMade(ctx, buyer.UUID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), offers)
+
+	watched := testhelpers.NewAsset(t, pool, testhelpers.WithAssetSold(), testhelpers.WithPrice(40))
+	gone := testhelpers.NewAsset(t, pool, testhelpers.WithAssetDeleted())
+	for _, id := range []int64{watched.ID, gone.ID} {
+		_, err := pool.Exec(ctx, `INSERT INTO notifications (user_uuid, type, entity_id, title) VALUES ($1, 'watched_asset_updated', $2, 'x')`, buyer.UUID, id)
+		require.NoError(t, err)
+	}
+	updates, err := repo.WatchlistUpdates(ctx, buyer.UUID, 10)
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	require.Equal(t, watched.ID, updates[0].AssetID)
+	require.True(t, updates[0].IsSold)
+
+	tx := testhelpers.NewTransaction(t, pool, testhelpers.WithBuyer(buyer.ID), testhelpers.WithFinalPrice(75),
+		testhelpers.WithTax("GST", 18, 13.5))
+	purchases, err := repo.Purchases(ctx, buyer.UUID, 10)
+	require.NoError(t, err)
+	require.Len(t, purchases, 1)
+	require.Equal(t, tx.ID, purchases[0].TransactionID)
+	require.Equal(t, PurchaseCompleted, purchases[0].Status)
+	require.Equal(t, "GST", purchases[0].TaxName)
+	require.Equal(t, 13.5, purchases[0].TaxAmount)
+	require.Equal(t, 88.5, purchases[0].Total)
+
+	_, err = repo.ActiveConversations(ctx, "no-such-user", time.Now())
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
@@ -0,0 +1,60 @@
+package dashboard
+
+import (
+	"context"
+	"time"
+)
+
+// Buyer lists hold only the most recent entries; the notification inbox has the
+// full history of watchlist updates.
+const (
+	buyerListLimit     = 10
+	conversationWindow = 30 * 24 * time.Hour
+)
+
+type Service struct {
+	repo DashboardRepository
+	now  func() time.Time
+}
+
+func NewService(repo DashboardRepository) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+// Seller returns userUUID's seller summary, with views counted over the last 7 and
+// 30 days.
+func (s *Service) Seller(ctx context.Context, userUUID string) (SellerSummary, error) {
+	summary, err := s.repo.SellerListings(ctx, userUUID)
+	if err != nil {
+		return SellerSummary{}, err
+	}
+
+	now := s.now().UTC()
+	summary.Views7d, summary.Views30d, err = s.repo.ListingViews(ctx, userUUID, now.AddDate(0, 0, -7), now.AddDate(0, 0, -30))
+	if err != nil {
+		return SellerSummary{}, err
+	}
+	return summary, nil
+}
+
+// Buyer returns userUUID's buyer summary. A conversation is active if a message was
+// sent either way in the last 30 days.
+func (s *Service) Buyer(ctx context.Context, userUUID string) (BuyerSummary, error) {
+	conversations, err := s.repo.ActiveConversations(ctx, userUUID, s.now().Add(-conversationWindow))
+	if err != nil {
+		return BuyerSummary{}, err
+	}
+	offers, err := s.repo.OpenOffersMade(ctx, userUUID)
+	if err != nil {
+		return BuyerSummary{}, err
+	}
+	updates, err := s.repo.WatchlistUpdates(ctx, userUUID, buyerListLimit)
+	if err != nil {
+		return BuyerSummary{}, err
+	}
+	purchases, err := s.repo.Purchases(ctx, userUUID, buyerListLimit)
+	if err != nil {
+		return BuyerSummary{}, err
+	}
+	return BuyerSummary{WatchlistUpdates: updates, OpenOffers: offers, ActiveConversations: conversations, Purchases: purchases}, nil
+}
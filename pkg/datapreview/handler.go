@@ -0,0 +1,129 @@
+package datapreview
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/storage"
+	"grveyard/pkg/validation"
+)
+
+type DataPreviewHandler struct {
+	service *Service
+}
+
+func NewDataPreviewHandler(service *Service) *DataPreviewHandler {
+	return &DataPreviewHandler{service: service}
+}
+
+func (h *DataPreviewHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/assets/:id/data-preview/upload", auth.Required(), h.createUpload)
+	router.POST("/assets/:id/data-preview", auth.Required(), h.submit)
+	router.GET("/assets/:id/data-preview", h.get)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *DataPreviewHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/assets/:id/data-preview/upload",
+			Tag:         "data-preview",
+			Summary:     "Start a data sample upload",
+			Description: "Returns a presigned request for uploading a redacted CSV sample of a data asset, at most 5 MiB with a header row. Only the asset owner can upload.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Response: storage.PresignedRequest{},
+			Status:   http.StatusCreated,
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/assets/:id/data-preview",
+			Tag:         "data-preview",
+			Summary:     "Publish the uploaded data sample",
+			Description: "Infers the column names and types of the uploaded sample and publishes it, replacing any earlier preview. Email addresses in the sample are masked as a safeguard; sellers remain responsible for redacting it. total_rows is the size of the full dataset.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Request:  SubmitRequest{},
+			Response: Summary{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/assets/:id/data-preview",
+			Tag:         "data-preview",
+			Summary:     "Get a data asset's preview",
+			Description: "Column summary, row counts, the first rows of the sample and a download URL for the whole redacted sample. If the asset has an agreement attached, the caller must be signed in and have signed it (403 otherwise).",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Response: Summary{},
+			Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+		},
+	}
+}
+
+func assetID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid asset id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *DataPreviewHandler) createUpload(c *gin.Context) {
+	id, ok := assetID(c)
+	if !ok {
+		return
+	}
+	upload, err := h.service.CreateUpload(c.Request.Context(), id, auth.UserID(c))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusCreated, true, "upload created", upload)
+}
+
+func (h *DataPreviewHandler) submit(c *gin.Context) {
+	id, ok := assetID(c)
+	if !ok {
+		return
+	}
+	var req SubmitRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	summary, err := h.service.Submit(c.Request.Context(), id, auth.UserID(c), req)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "data preview published", summary)
+}
+
+func (h *DataPreviewHandler) get(c *gin.Context) {
+	id, ok := assetID(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.service.Get(c.Request.Context(), id, auth.UserID(c))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "data preview fetched", summary)
+}
@@ -0,0 +1,41 @@
+package datapreview
+
+import "time"
+
+// Column types inferred from a sample. A column is the narrowest type every
+// non-empty value fits.
+const (
+	TypeInteger   = "integer"
+	TypeNumber    = "number"
+	TypeBoolean   = "boolean"
+	TypeDate      = "date"      // YYYY-MM-DD
+	TypeTimestamp = "timestamp" // RFC 3339
+	TypeString    = "string"
+	TypeEmpty     = "empty" // no values in the sample
+)
+
+// Column describes one column of a sample.
+type Column struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Nulls int    `json:"nulls"` // empty values in the sample
+}
+
+// Summary is what buyers see of a data asset before purchase.
+type Summary struct {
+	AssetID    int64      `json:"asset_id"`
+	Columns    []Column   `json:"columns"`
+	SampleRows int        `json:"sample_rows"`
+	TotalRows  *int64     `json:"total_rows,omitempty"` // declared by the seller for the full dataset
+	Rows       [][]string `json:"rows"`                 // the first PreviewRows rows of the sample, redacted
+	// DownloadURL fetches the whole redacted sample as CSV; only filled in responses
+	DownloadURL string    `json:"download_url,omitempty"`
+	SampleKey   string    `json:"-"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SubmitRequest processes the uploaded sample. TotalRows is the size of the full
+// dataset, which the sample alone cannot tell.
+type SubmitRequest struct {
+	TotalRows *int64 `json:"total_rows,omitempty" binding:"omitempty,gte=0"`
+}
@@ -0,0 +1,168 @@
+package datapreview
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxColumns  = 200
+	maxCellLen  = 1000
+	PreviewRows = 20
+)
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// errSample marks problems with the uploaded file itself.
+var errSample = errors.New("invalid sample")
+
+// profile reads a CSV sample with a header row, infers each column's type and
+// returns the sample re-encoded with email addresses masked. Sellers are expected
+// to redact samples themselves; the masking only catches the most common leak.
+func profile(r io.Reader) (Summary, []byte, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 0 // every row has as many fields as the header
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return Summary{}, nil, fmt.Errorf("%w: the file is empty", errSample)
+	}
+	if err != nil {
+		return Summary{}, nil, fmt.Errorf("%w: %v", errSample, err)
+	}
+	if len(header) > maxColumns {
+		return Summary{}, nil, fmt.Errorf("%w: more than %d columns", errSample, maxColumns)
+	}
+
+	columns := make([]Column, len(header))
+	kinds := make([]kind, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")) // Excel's byte order mark
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		columns[i] = Column{Name: name}
+	}
+
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	if err := w.Write(namesOf(columns)); err != nil {
+		return Summary{}, nil, err
+	}
+
+	summary := Summary{Rows: [][]string{}}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Summary{}, nil, fmt.Errorf("%w: %v", errSample, err)
+		}
+		for i, v := range record {
+			v = redact(strings.TrimSpace(v))
+			if r := []rune(v); len(r) > maxCellLen {
+				v = string(r[:maxCellLen])
+			}
+			record[i] = v
+			if v == "" {
+				columns[i].Nulls++
+				continue
+			}
+			kinds[i] = kinds[i].widen(classify(v))
+		}
+		if err := w.Write(record); err != nil {
+			return Summary{}, nil, err
+		}
+		if len(summary.Rows) < PreviewRows {
+			summary.Rows = append(summary.Rows, record)
+		}
+		summary.SampleRows++
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return Summary{}, nil, err
+	}
+	if summary.SampleRows == 0 {
+		return Summary{}, nil, fmt.Errorf("%w: no rows after the header", errSample)
+	}
+
+	for i := range columns {
+		columns[i].Type = kinds[i].String()
+	}
+	summary.Columns = columns
+	return summary, out.Bytes(), nil
+}
+
+func namesOf(columns []Column) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func redact(v string) string {
+	return emailPattern.ReplaceAllStringFunc(v, func(email string) string {
+		return "***@" + email[strings.LastIndexByte(email, '@')+1:]
+	})
+}
+
+// kind orders the inferred types so two can be widened to the narrowest type that
+// holds both.
+type kind int
+
+const (
+	kindEmpty kind = iota
+	kindBoolean
+	kindInteger
+	kindNumber
+	kindDate
+	kindTimestamp
+	kindString
+)
+
+func (k kind) widen(other kind) kind {
+	switch {
+	case k == kindEmpty || k == other:
+		return other
+	case (k == kindInteger && other == kindNumber) || (k == kindNumber && other == kindInteger):
+		return kindNumber
+	case (k == kindDate && other == kindTimestamp) || (k == kindTimestamp && other == kindDate):
+		return kindTimestamp
+	default:
+		return kindString
+	}
+}
+
+func (k kind) String() string {
+	return [...]string{TypeEmpty, TypeBoolean, TypeInteger, TypeNumber, TypeDate, TypeTimestamp, TypeString}[k]
+}
+
+func classify(v string) kind {
+	switch strings.ToLower(v) {
+	case "true", "false":
+		return kindBoolean
+	}
+	if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return kindInteger
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return kindNumber
+	}
+	if _, err := time.Parse(time.DateOnly, v); err == nil {
+		return kindDate
+	}
+	if _, err := time.Parse(time.RFC3339, v); err == nil {
+		return kindTimestamp
+	}
+	return kindString
+}
@@ -0,0 +1,56 @@
+package datapreview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile(t *testing.T) {
+	sample := "\ufeffid,email,score,active,signed_up,last_seen,notes,\n" +
+		"1,ana@example.com,4.5,true,2024-01-02,2024-01-02T10:00:00Z,,\n" +
+		"2,,7,false,2024-03-04,2024-03-04,\"Contact bob@corp.io, please\",\n"
+
+	summary, redacted, err := profile(strings.NewReader(sample))
+
+	require.NoError(t, err)
+	require.Equal(t, []Column{
+		{Name: "id", Type: TypeInteger},
+		{Name: "email", Type: TypeString, Nulls: 1},
+		{Name: "score", Type: TypeNumber},
+		{Name: "active", Type: TypeBoolean},
+		{Name: "signed_up", Type: TypeDate},
+		{Name: "last_seen", Type: TypeTimestamp},
+		{Name: "notes", Type: TypeString, Nulls: 1},
+		{Name: "column_8", Type: TypeEmpty, Nulls: 2},
+	}, summary.Columns)
+	require.Equal(t, 2, summary.SampleRows)
+	require.Equal(t, "***@example.com", summary.Rows[0][1])
+	require.Equal(t, "Contact ***@corp.io, please", summary.Rows[1][6])
+	require.NotContains(t, string(redacted), "ana@")
+	require.NotContains(t, string(redacted), "bob@")
+	require.True(t, strings.HasPrefix(string(redacted), "id,email,score,"))
+}
+
+func TestProfile_PreviewRowsCapped(t *testing.T) {
+	sample := "n\n" + strings.Repeat("1\n", PreviewRows+5)
+
+	summary, _, err := profile(strings.NewReader(sample))
+
+	require.NoError(t, err)
+	require.Equal(t, PreviewRows+5, summary.SampleRows)
+	require.Len(t, summary.Rows, PreviewRows)
+}
+
+func TestProfile_Invalid(t *testing.T) {
+	for name, sample := range map[string]string{
+		"empty":         "",
+		"header only":   "a,b\n",
+		"ragged rows":   "a,b\n1,2,3\n",
+		"too many cols": strings.Repeat("c,", maxColumns) + "c\n" + strings.Repeat("1,", maxColumns) + "1\n",
+	} {
+		_, _, err := profile(strings.NewReader(sample))
+		require.ErrorIs(t, err, errSample, name)
+	}
+}
@@ -0,0 +1,80 @@
+package datapreview
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PreviewRepository interface {
+	// AssetOwner returns the owner and asset_type of a live asset.
+	AssetOwner(ctx context.Context, assetID int64) (ownerUUID, assetType string, err error)
+	// Save replaces the asset's preview.
+	Save(ctx context.Context, summary Summary) (Summary, error)
+	Get(ctx context.Context, assetID int64) (Summary, error)
+}
+
+type postgresPreviewRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresPreviewRepository(pool *pgxpool.Pool) PreviewRepository {
+	return &postgresPreviewRepository{pool: pool}
+}
+
+func (r *postgresPreviewRepository) AssetOwner(ctx context.Context, assetID int64) (string, string, error) {
+	var owner, assetType string
+	err := r.pool.QueryRow(ctx, `SELECT user_uuid, asset_type FROM assets WHERE id = $1 AND is_deleted = false`, assetID).Scan(&owner, &assetType)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", "", ErrAssetNotFound
+	}
+	return owner, assetType, err
+}
+
+func (r *postgresPreviewRepository) Save(ctx context.Context, summary Summary) (Summary, error) {
+	columns, err := json.Marshal(summary.Columns)
+	if err != nil {
+		return Summary{}, err
+	}
+	rows, err := json.Marshal(summary.Rows)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	query := `INSERT INTO asset_data_previews (asset_id, sample_key, columns, sample_rows, total_rows, preview_rows)
+			  VALUES ($1, $2, $3, $4, $5, $6)
+			  ON CONFLICT (asset_id) DO UPDATE SET
+				sample_key = EXCLUDED.sample_key,
+				columns = EXCLUDED.columns,
+				sample_rows = EXCLUDED.sample_rows,
+				total_rows = EXCLUDED.total_rows,
+				preview_rows = EXCLUDED.preview_rows,
+				updated_at = NOW()
+			  RETURNING updated_at`
+	err = r.pool.QueryRow(ctx, query, summary.AssetID, summary.SampleKey, columns, summary.SampleRows, summary.TotalRows, rows).Scan(&summary.UpdatedAt)
+	return summary, err
+}
+
+func (r *postgresPreviewRepository) Get(ctx context.Context, assetID int64) (Summary, error) {
+	query := `SELECT asset_id, sample_key, columns, sample_rows, total_rows, preview_rows, updated_at
+			  FROM asset_data_previews WHERE asset_id = $1`
+	var s Summary
+	var columns, rows []byte
+	err := r.pool.QueryRow(ctx, query, assetID).Scan(&s.AssetID, &s.SampleKey, &columns, &s.SampleRows, &s.TotalRows, &rows, &s.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Summary{}, ErrPreviewNotFound
+	}
+	if err != nil {
+		return Summary{}, err
+	}
+	if err := json.Unmarshal(columns, &s.Columns); err != nil {
+		return Summary{}, err
+	}
+	if err := json.Unmarshal(rows, &s.Rows); err != nil {
+		return Summary{}, err
+	}
+	return s, nil
+}
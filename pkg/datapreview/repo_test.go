@@ -0,0 +1,59 @@
+package datapreview
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresPreviewRepository(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresPreviewRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	asset := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetType("data"))
+
+	owner, assetType, err := repo.AssetOwner(ctx, asset.ID)
+	require.NoError(t, err)
+	require.Equal(t, seller.UUID, owner)
+	require.Equal(t, "data", assetType)
+
+	_, err = repo.Get(ctx, asset.ID)
+	require.ErrorIs(t, err, ErrPreviewNotFound)
+
+	total := int64(500)
+	_, err = repo.Save(ctx, Summary{
+		AssetID:    asset.ID,
+		SampleKey:  "data-samples/1/sample.csv",
+		Columns:    []Column{{Name: "id", Type: TypeInteger}},
+		SampleRows: 1,
+		TotalRows:  &total,
+		Rows:       [][]string{{"1"}},
+	})
+	require.NoError(t, err)
+
+	// Publishing again replaces the preview
+	_, err = repo.Save(ctx, Summary{
+		AssetID:    asset.ID,
+		SampleKey:  "data-samples/1/sample.csv",
+		Columns:    []Column{{Name: "id", Type: TypeInteger}, {Name: "name", Type: TypeString, Nulls: 1}},
+		SampleRows: 2,
+		Rows:       [][]string{{"1", "a"}, {"2", ""}},
+	})
+	require.NoError(t, err)
+
+	summary, err := repo.Get(ctx, asset.ID)
+	require.NoError(t, err)
+	require.Len(t, summary.Columns, 2)
+	require.Equal(t, 2, summary.SampleRows)
+	require.Nil(t, summary.TotalRows)
+	require.Equal(t, [][]string{{"1", "a"}, {"2", ""}}, summary.Rows)
+	require.False(t, summary.UpdatedAt.IsZero())
+}
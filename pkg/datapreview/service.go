@@ -0,0 +1,156 @@
+// Package datapreview lets sellers of data assets publish a redacted sample of the
+// dataset with a summary of its columns, so buyers can judge it before purchase.
+package datapreview
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/requestid"
+	"grveyard/pkg/storage"
+)
+
+const (
+	maxSampleBytes = 5 << 20
+	uploadExpiry   = 15 * time.Minute
+	urlExpiry      = time.Hour
+	sampleType     = "text/csv"
+)
+
+var (
+	ErrAssetNotFound   = apperr.New(apperr.AssetNotFound, "asset not found")
+	ErrPreviewNotFound = apperr.New(apperr.DataPreviewNotFound, "no data preview for this asset")
+	ErrNotDataAsset    = apperr.New(apperr.InvalidAssetType, "only data assets can publish a data preview")
+	ErrNotOwner        = apperr.New(apperr.Forbidden, "only the asset owner can publish its data preview")
+	ErrNoUpload        = apperr.New(apperr.InvalidDataSample, "no sample was uploaded")
+	ErrSampleTooLarge  = apperr.New(apperr.InvalidDataSample, "sample must be at most 5 MiB")
+	ErrInvalidSample   = apperr.New(apperr.InvalidDataSample, "sample must be a CSV file with a header row and at least one row")
+)
+
+// Gate decides whether a user may see an asset's preview; the agreements service,
+// which requires the asset's NDA to be signed first.
+type Gate interface {
+	Require(ctx context.Context, assetID int64, userUUID string) error
+}
+
+type Service struct {
+	repo  PreviewRepository
+	store storage.Storage
+	gate  Gate // optional; if nil, previews are public
+}
+
+func NewService(repo PreviewRepository, store storage.Storage, gate Gate) *Service {
+	return &Service{repo: repo, store: store, gate: gate}
+}
+
+// uploadKey is where the owner uploads a sample before it is processed. Each asset
+// has one, so a new upload replaces an unprocessed one.
+func uploadKey(assetID int64) string {
+	return "uploads/data-samples/" + strconv.FormatInt(assetID, 10)
+}
+
+func sampleKey(assetID int64) string {
+	return "data-samples/" + strconv.FormatInt(assetID, 10) + "/sample.csv"
+}
+
+// CreateUpload returns where the owner should PUT the CSV sample.
+func (s *Service) CreateUpload(ctx context.Context, assetID int64, userUUID string) (storage.PresignedRequest, error) {
+	if err := s.checkOwner(ctx, assetID, userUUID); err != nil {
+		return storage.PresignedRequest{}, err
+	}
+	return s.store.PresignUpload(ctx, uploadKey(assetID), sampleType, uploadExpiry)
+}
+
+// Submit profiles the sample userUUID uploaded and publishes it, replacing any
+// earlier preview. Only the redacted copy is kept.
+func (s *Service) Submit(ctx context.Context, assetID int64, userUUID string, req SubmitRequest) (Summary, error) {
+	if err := s.checkOwner(ctx, assetID, userUUID); err != nil {
+		return Summary{}, err
+	}
+
+	rc, err := s.store.Get(ctx, uploadKey(assetID))
+	if errors.Is(err, storage.ErrNotFound) {
+		return Summary{}, ErrNoUpload
+	}
+	if err != nil {
+		return Summary{}, err
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, maxSampleBytes+1))
+	rc.Close()
+	if err != nil {
+		return Summary{}, err
+	}
+	if len(data) > maxSampleBytes {
+		return Summary{}, ErrSampleTooLarge
+	}
+
+	summary, redacted, err := profile(bytes.NewReader(data))
+	if errors.Is(err, errSample) {
+		log.Printf("[%s] data sample of asset %d rejected: %v", requestid.FromContext(ctx), assetID, err)
+		return Summary{}, ErrInvalidSample
+	}
+	if err != nil {
+		return Summary{}, err
+	}
+
+	summary.AssetID = assetID
+	summary.TotalRows = req.TotalRows
+	summary.SampleKey = sampleKey(assetID)
+	if err := s.store.Put(ctx, summary.SampleKey, bytes.NewReader(redacted), int64(len(redacted)), sampleType); err != nil {
+		return Summary{}, err
+	}
+	summary, err = s.repo.Save(ctx, summary)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	// The upload may still hold what the redaction masked
+	if err := s.store.Delete(ctx, uploadKey(assetID)); err != nil {
+		log.Printf("[%s] delete data sample upload of asset %d: %v", requestid.FromContext(ctx), assetID, err)
+	}
+	return s.withDownloadURL(ctx, summary)
+}
+
+// Get returns the published preview of an asset with a download URL for the sample,
+// once viewerUUID has signed the asset's agreement if it has one.
+func (s *Service) Get(ctx context.Context, assetID int64, viewerUUID string) (Summary, error) {
+	if s.gate != nil {
+		if err := s.gate.Require(ctx, assetID, viewerUUID); err != nil {
+			return Summary{}, err
+		}
+	}
+	summary, err := s.repo.Get(ctx, assetID)
+	if err != nil {
+		return Summary{}, err
+	}
+	return s.withDownloadURL(ctx, summary)
+}
+
+func (s *Service) withDownloadURL(ctx context.Context, summary Summary) (Summary, error) {
+	u, err := s.store.PresignDownload(ctx, summary.SampleKey, urlExpiry)
+	if err != nil {
+		return Summary{}, err
+	}
+	summary.DownloadURL = u
+	return summary, nil
+}
+
+func (s *Service) checkOwner(ctx context.Context, assetID int64, userUUID string) error {
+	owner, assetType, err := s.repo.AssetOwner(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if owner != userUUID {
+		return ErrNotOwner
+	}
+	if assetType != "data" {
+		return ErrNotDataAsset
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package datapreview
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/storage"
+)
+
+type mockPreviewRepository struct {
+	mock.Mock
+}
+
+func (m *mockPreviewRepository) AssetOwner(ctx context.Context, assetID int64) (string, string, error) {
+	args := m.Called(ctx, assetID)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *mockPreviewRepository) Save(ctx context.Context, summary Summary) (Summary, error) {
+	args := m.Called(ctx, summary)
+	out, _ := args.Get(0).(Summary)
+	return out, args.Error(1)
+}
+
+func (m *mockPreviewRepository) Get(ctx context.Context, assetID int64) (Summary, error) {
+	args := m.Called(ctx, assetID)
+	out, _ := args.Get(0).(Summary)
+	return out, args.Error(1)
+}
+
+// memStorage is an in-memory storage.Storage.
+type memStorage struct {
+	objects map[string][]byte
+}
+
+func (s *memStorage) Put(_ context.Context, key string, body io.Reader, _ int64, _ string) error {
+	b, err := io.ReadAll(body)
+	s.objects[key] = b
+	return err
+}
+
+func (s *memStorage) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	b, ok := s.objects[key]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (s *memStorage) PresignUpload(_ context.Context, key, contentType string, expires time.Duration) (storage.PresignedRequest, error) {
+	return storage.PresignedRequest{Method: "PUT", URL: "https://files.example.com/" + key, Headers: map[string]string{"Content-Type": contentType}}, nil
+}
+
+func (s *memStorage) PresignDownload(_ context.Context, key string, _ time.Duration) (string, error) {
+	return "https://files.example.com/" + key, nil
+}
+
+func (s *memStorage) Delete(_ context.Context, key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+func TestService_CreateUpload(t *testing.T) {
+	repo := new(mockPreviewRepository)
+	service := NewService(repo, &memStorage{objects: map[string][]byte{}}, nil)
+	repo.On("AssetOwner", mock.Anything, int64(5)).Return("seller", "data", nil)
+
+	upload, err := service.CreateUpload(context.Background(), 5, "seller")
+	require.NoError(t, err)
+	require.Equal(t, "https://files.example.com/uploads/data-samples/5", upload.URL)
+
+	_, err = service.CreateUpload(context.Background(), 5, "someone-else")
+	require.ErrorIs(t, err, ErrNotOwner)
+}
+
+func TestService_CreateUpload_NotDataAsset(t *testing.T) {
+	repo := new(mockPreviewRepository)
+	service := NewService(repo, &memStorage{objects: map[string][]byte{}}, nil)
+	repo.On("AssetOwner", mock.Anything, int64(5)).Return("seller", "codebase", nil)
+
+	_, err := service.CreateUpload(context.Background(), 5, "seller")
+
+	require.ErrorIs(t, err, ErrNotDataAsset)
+}
+
+func TestService_Submit(t *testing.T) {
+	repo := new(mockPreviewRepository)
+	store := &memStorage{objects: map[string][]byte{
+		"uploads/data-samples/5": []byte("name,email\nAna,ana@example.com\n"),
+	}}
+	service := NewService(repo, store, nil)
+	total := int64(120000)
+
+	repo.On("AssetOwner", mock.Anything, int64(5)).Return("seller", "data", nil)
+	saved := Summary{AssetID: 5, SampleKey: "data-samples/5/sample.csv", SampleRows: 1, TotalRows: &total}
+	repo.On("Save", mock.Anything, mock.MatchedBy(func(s Summary) bool {
+		return s.AssetID == 5 && s.SampleKey == saved.SampleKey && s.SampleRows == 1 && *s.TotalRows == total
+	})).Return(saved, nil)
+
+	summary, err := service.Submit(context.Background(), 5, "seller", SubmitRequest{TotalRows: &total})
+
+	require.NoError(t, err)
+	require.Equal(t, "https://files.example.com/data-samples/5/sample.csv", summary.DownloadURL)
+	require.Equal(t, "name,email\nAna,***@example.com\n", string(store.objects["data-samples/5/sample.csv"]))
+	require.NotContains(t, store.objects, "uploads/data-samples/5")
+	repo.AssertExpectations(t)
+}
+
+func TestService_Submit_Rejected(t *testing.T) {
+	cases := map[string]struct {
+		upload []byte
+		want   error
+	}{
+		"no upload": {nil, ErrNoUpload},
+		"too large": {[]byte("a\n" + strings.Repeat("1\n", maxSampleBytes/2)), ErrSampleTooLarge},
+		"not csv":   {[]byte("a,b\n\"1,2\n"), ErrInvalidSample},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := new(mockPreviewRepository)
+			store := &memStorage{objects: map[string][]byte{}}
+			if tc.upload != nil {
+				store.objects["uploads/data-samples/5"] = tc.upload
+			}
+			repo.On("AssetOwner", mock.Anything, int64(5)).Return("seller", "data", nil)
+
+			_, err := NewService(repo, store, nil).Submit(context.Background(), 5, "seller", SubmitRequest{})
+
+			require.ErrorIs(t, err, tc.want)
+			repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_Get_NotFound(t *testing.T) {
+	repo := new(mockPreviewRepository)
+	repo.On("Get", mock.Anything, int64(5)).Return(nil, ErrPreviewNotFound)
+
+	_, err := NewService(repo, &memStorage{}, nil).Get(context.Background(), 5, "")
+
+	require.ErrorIs(t, err, ErrPreviewNotFound)
+}
+
+type gateFunc func(ctx context.Context, assetID int64, userUUID string) error
+
+func (f gateFunc) Require(ctx context.Context, assetID int64, userUUID string) error {
+	return f(ctx, assetID, userUUID)
+}
+
+func TestService_Get_Gated(t *testing.T) {
+	repo := new(mockPreviewRepository)
+	errUnsigned := errors.New("agreement must be signed")
+	gate := gateFunc(func(_ context.Context, _ int64, userUUID string) error {
+		if userUUID != "signed-buyer" {
+			return errUnsigned
+		}
+		return nil
+	})
+	service := NewService(repo, &memStorage{}, gate)
+	repo.On("Get", mock.Anything, int64(5)).Return(Summary{AssetID: 5, SampleKey: "data-samples/5/sample.csv"}, nil)
+
+	_, err := service.Get(context.Background(), 5, "")
+	require.ErrorIs(t, err, errUnsigned)
+
+	summary, err := service.Get(context.Background(), 5, "signed-buyer")
+	require.NoError(t, err)
+	require.Equal(t, "https://files.example.com/data-samples/5/sample.csv", summary.DownloadURL)
+	repo.AssertNumberOfCalls(t, "Get", 1)
+}
@@ -0,0 +1,30 @@
+package digest
+
+// PendingDigest summarises unread messages waiting for a single recipient.
+type PendingDigest struct {
+	UserUUID        string `json:"user_uuid"`
+	Email           string `json:"email"`
+	Name            string `json:"name"`
+	Role            string `json:"role"`
+	Locale          string `json:"locale"`
+	UnreadCount     int    `json:"unread_count"`
+	SenderCount     int    `json:"sender_count"`
+	QuietHoursStart *int16 `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd   *int16 `json:"quiet_hours_end,omitempty"`
+}
+
+// inQuietHours reports whether hour (0-23, UTC) falls inside the recipient's quiet window.
+// Windows may wrap midnight, e.g. 22 -> 7.
+func (p PendingDigest) inQuietHours(hour int) bool {
+	if p.QuietHoursStart == nil || p.QuietHoursEnd == nil {
+		return false
+	}
+	start, end := int(*p.QuietHoursStart), int(*p.QuietHoursEnd)
+	if start == end {
+		return false
+	}
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
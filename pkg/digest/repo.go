@@ -0,0 +1,69 @@
+package digest
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DigestRepository interface {
+	ListPendingDigests(ctx context.Context, olderThanEpoch int64) ([]PendingDigest, error)
+	MarkDigestSent(ctx context.Context, userUUID string, coveredUntilEpoch int64) error
+}
+
+type postgresDigestRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresDigestRepository(pool *pgxpool.Pool) DigestRepository {
+	return &postgresDigestRepository{pool: pool}
+}
+
+// ListPendingDigests returns recipients with unread messages sent before olderThanEpoch
+// that arrived after their last digest and who have not opted out of digest emails.
+func (r *postgresDigestRepository) ListPendingDigests(ctx context.Context, olderThanEpoch int64) ([]PendingDigest, error) {
+	query := `SELECT u.uuid, u.email, u.name, u.role, u.locale,
+	                 COUNT(m.id) AS unread_count,
+	                 COUNT(DISTINCT m.sender_id) AS sender_count,
+	                 p.quiet_hours_start, p.quiet_hours_end
+	          FROM messages m
+	          JOIN users u ON u.id = m.receiver_id
+	          LEFT JOIN notification_preferences p ON p.user_uuid = u.uuid
+	          WHERE m.is_read = false
+	            AND m.messaged_at <= $1
+	            AND m.messaged_at > COALESCE(p.last_digest_at, 0)
+	            AND u.is_deleted = false
+	            AND u.email IS NOT NULL
+	            AND COALESCE(p.email_digest, true)
+	          GROUP BY u.uuid, u.email, u.name, u.role, u.locale, p.quiet_hours_start, p.quiet_hours_end
+	          ORDER BY u.uuid`
+
+	rows, err := r.pool.Query(ctx, query, olderThanEpoch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]PendingDigest, 0)
+	for rows.Next() {
+		var d PendingDigest
+		if err := rows.Scan(&d.UserUUID, &d.Email, &d.Name, &d.Role, &d.Locale, &d.UnreadCount, &d.SenderCount, &d.QuietHoursStart, &d.QuietHoursEnd); err != nil {
+			return nil, err
+		}
+		list = append(list, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// MarkDigestSent records that unread messages up to coveredUntilEpoch were included in a digest.
+func (r *postgresDigestRepository) MarkDigestSent(ctx context.Context, userUUID string, coveredUntilEpoch int64) error {
+	query := `INSERT INTO notification_preferences (user_uuid, last_digest_at, updated_at)
+	          VALUES ($1, $2, NOW())
+	          ON CONFLICT (user_uuid) DO UPDATE SET last_digest_at = EXCLUDED.last_digest_at, updated_at = NOW()`
+	_, err := r.pool.Exec(ctx, query, userUUID, coveredUntilEpoch)
+	return err
+}
@@ -0,0 +1,59 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/chat"
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func setupDigestTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	return testhelpers.Pool(t)
+}
+
+func TestPostgresDigestRepository_ListPendingAndMarkSent(t *testing.T) {
+	t.Parallel()
+	pool := setupDigestTestPool(t)
+	ctx := context.Background()
+
+	repo := NewPostgresDigestRepository(pool)
+	store := chat.NewPostgresMessageStore(pool)
+
+	sender := testhelpers.CreateTestUser(t, pool)
+	receiver := testhelpers.CreateTestUser(t, pool)
+
+	old := time.Now().Add(-12 * time.Hour).Unix()
+	_, err := store.SaveMessage(ctx, sender, receiver, "are you there?", 0, old)
+	require.NoError(t, err)
+
+	cutoff := time.Now().Add(-6 * time.Hour).Unix()
+	pending, err := repo.ListPendingDigests(ctx, cutoff)
+	require.NoError(t, err)
+
+	var found *PendingDigest
+	for i := range pending {
+		if pending[i].UserUUID == receiver {
+			found = &pending[i]
+		}
+	}
+	require.NotNil(t, found)
+	require.Equal(t, 1, found.UnreadCount)
+	require.Equal(t, 1, found.SenderCount)
+
+	require.NoError(t, repo.MarkDigestSent(ctx, receiver, cutoff))
+
+	pending, err = repo.ListPendingDigests(ctx, cutoff)
+	require.NoError(t, err)
+	for _, d := range pending {
+		require.NotEqual(t, receiver, d.UserUUID)
+	}
+}
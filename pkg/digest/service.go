@@ -0,0 +1,128 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"grveyard/pkg/i18n"
+	"grveyard/pkg/notifications"
+	sendemail "grveyard/pkg/sendemail"
+)
+
+// PresenceChecker reports whether a user currently holds a live chat connection.
+type PresenceChecker interface {
+	IsOnline(userID string) bool
+}
+
+// UnsubscribeLinker builds the signed unsubscribe link included in each digest.
+type UnsubscribeLinker interface {
+	URL(userUUID string, c notifications.Category) string
+}
+
+type DigestService interface {
+	SendPendingDigests(ctx context.Context) (int, error)
+}
+
+type digestService struct {
+	repo        DigestRepository
+	presence    PresenceChecker
+	es          sendemail.EmailService
+	links       UnsubscribeLinker // optional
+	unreadAfter time.Duration
+	now         func() time.Time
+}
+
+// NewDigestService creates a digest sender. unreadAfter is how long a message must stay
+// unread before it is included in a digest. links may be nil to omit unsubscribe links.
+func NewDigestService(repo DigestRepository, presence PresenceChecker, es sendemail.EmailService, links UnsubscribeLinker, unreadAfter time.Duration) DigestService {
+	return &digestService{
+		repo:        repo,
+		presence:    presence,
+		es:          es,
+		links:       links,
+		unreadAfter: unreadAfter,
+		now:         time.Now,
+	}
+}
+
+// SendPendingDigests emails every offline recipient outside quiet hours who has unread
+// messages older than unreadAfter. Returns the number of digests sent.
+func (s *digestService) SendPendingDigests(ctx context.Context) (int, error) {
+	now := s.now().UTC()
+	cutoff := now.Add(-s.unreadAfter).Unix()
+
+	pending, err := s.repo.ListPendingDigests(ctx, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("list pending digests: %w", err)
+	}
+
+	sent := 0
+	for _, d := range pending {
+		if s.presence != nil && s.presence.IsOnline(d.UserUUID) {
+			continue
+		}
+		if d.inQuietHours(now.Hour()) {
+			continue
+		}
+
+		// Suppressed addresses are marked as sent so they are not retried every run.
+		if err := s.sendDigestEmail(ctx, d); err != nil && !errors.Is(err, sendemail.ErrSuppressed) {
+			log.Printf("digest email to %s failed: %v", d.UserUUID, err)
+			continue
+		}
+		if err := s.repo.MarkDigestSent(ctx, d.UserUUID, cutoff); err != nil {
+			return sent, fmt.Errorf("mark digest sent: %w", err)
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+func (s *digestService) sendDigestEmail(ctx context.Context, d PendingDigest) error {
+	summary := digestSummary(d)
+	subject := i18n.T(d.Locale, "email.digest.subject")
+	plainTextContent := i18n.T(d.Locale, "email.digest.text", d.Name, summary)
+	link := ""
+	if s.links != nil {
+		link = s.links.URL(d.UserUUID, notifications.CategoryDigest)
+	}
+	if link != "" {
+		plainTextContent += "\n\n" + i18n.T(d.Locale, "email.unsubscribe.text", link)
+	}
+	htmlContent, err := sendemail.RenderHTML("digest.html", struct {
+		Heading, Greeting, Waiting, CTA string
+		Unsubscribe                     *sendemail.UnsubscribeLink
+	}{
+		Heading:     i18n.T(d.Locale, "email.digest.heading"),
+		Greeting:    i18n.T(d.Locale, "email.digest.greeting", d.Name),
+		Waiting:     i18n.T(d.Locale, "email.digest.waiting", summary),
+		CTA:         i18n.T(d.Locale, "email.digest.cta"),
+		Unsubscribe: notifications.UnsubscribeFooter(d.Locale, link),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.es.SendEmail(ctx, subject, d.Email, plainTextContent, htmlContent)
+}
+
+// digestSummary renders e.g. "3 unread messages from 2 buyers" in the recipient's locale.
+func digestSummary(d PendingDigest) string {
+	messages := "email.digest.messages"
+	if d.UnreadCount == 1 {
+		messages = "email.digest.message"
+	}
+	// Founders mostly hear from buyers and vice versa.
+	counterpart := "email.digest.seller"
+	if d.Role == "founder" {
+		counterpart = "email.digest.buyer"
+	}
+	if d.SenderCount != 1 {
+		counterpart += "s"
+	}
+	return i18n.T(d.Locale, "email.digest.summary", d.UnreadCount, i18n.T(d.Locale, messages), d.SenderCount, i18n.T(d.Locale, counterpart))
+}
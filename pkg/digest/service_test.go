@@ -0,0 +1,135 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	sendemail "grveyard/pkg/sendemail"
+)
+
+type mockDigestRepository struct {
+	mock.Mock
+}
+
+func (m *mockDigestRepository) ListPendingDigests(ctx context.Context, olderThanEpoch int64) ([]PendingDigest, error) {
+	args := m.Called(ctx, olderThanEpoch)
+	list, _ := args.Get(0).([]PendingDigest)
+	return list, args.Error(1)
+}
+
+func (m *mockDigestRepository) MarkDigestSent(ctx context.Context, userUUID string, coveredUntilEpoch int64) error {
+	args := m.Called(ctx, userUUID, coveredUntilEpoch)
+	return args.Error(0)
+}
+
+type mockEmailService struct {
+	mock.Mock
+}
+
+func (m *mockEmailService) SendEmail(ctx context.Context, subject, toEmail, plainTextContent, htmlContent string) error {
+	args := m.Called(subject, toEmail, plainTextContent, htmlContent)
+	return args.Error(0)
+}
+
+type fakePresence map[string]bool
+
+func (f fakePresence) IsOnline(userID string) bool { return f[userID] }
+
+func newTestDigestService(repo DigestRepository, presence PresenceChecker, es *mockEmailService, now time.Time) *digestService {
+	s := NewDigestService(repo, presence, es, nil, 6*time.Hour).(*digestService)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func int16Ptr(v int16) *int16 { return &v }
+
+func TestDigestService_SendPendingDigests_SkipsOnlineUsers(t *testing.T) {
+	repo := new(mockDigestRepository)
+	es := new(mockEmailService)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	service := newTestDigestService(repo, fakePresence{"online": true}, es, now)
+
+	cutoff := now.Add(-6 * time.Hour).Unix()
+	repo.On("ListPendingDigests", mock.Anything, cutoff).Return([]PendingDigest{
+		{UserUUID: "online", Email: "on@example.com", Role: "founder", UnreadCount: 2, SenderCount: 1},
+		{UserUUID: "offline", Email: "off@example.com", Name: "Off", Role: "founder", UnreadCount: 3, SenderCount: 2},
+	}, nil)
+	es.On("SendEmail", mock.Anything, "off@example.com", mock.MatchedBy(func(body string) bool {
+		return body == "Hi Off, you have 3 unread messages from 2 buyers. Log in to reply."
+	}), mock.Anything).Return(nil)
+	repo.On("MarkDigestSent", mock.Anything, "offline", cutoff).Return(nil)
+
+	sent, err := service.SendPendingDigests(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, 1, sent)
+	es.AssertNotCalled(t, "SendEmail", mock.Anything, "on@example.com", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+	es.AssertExpectations(t)
+}
+
+func TestDigestService_SendPendingDigests_RespectsQuietHours(t *testing.T) {
+	repo := new(mockDigestRepository)
+	es := new(mockEmailService)
+	now := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+	service := newTestDigestService(repo, fakePresence{}, es, now)
+
+	repo.On("ListPendingDigests", mock.Anything, mock.Anything).Return([]PendingDigest{
+		{UserUUID: "sleepy", Email: "s@example.com", UnreadCount: 1, SenderCount: 1, QuietHoursStart: int16Ptr(22), QuietHoursEnd: int16Ptr(7)},
+	}, nil)
+
+	sent, err := service.SendPendingDigests(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, 0, sent)
+	es.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "MarkDigestSent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDigestService_SendPendingDigests_EmailFailureNotMarked(t *testing.T) {
+	repo := new(mockDigestRepository)
+	es := new(mockEmailService)
+	service := newTestDigestService(repo, nil, es, time.Now())
+
+	repo.On("ListPendingDigests", mock.Anything, mock.Anything).Return([]PendingDigest{
+		{UserUUID: "u1", Email: "u1@example.com", UnreadCount: 1, SenderCount: 1},
+	}, nil)
+	es.On("SendEmail", mock.Anything, "u1@example.com", mock.Anything, mock.Anything).Return(errors.New("sendgrid down"))
+
+	sent, err := service.SendPendingDigests(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, 0, sent)
+	repo.AssertNotCalled(t, "MarkDigestSent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDigestSummary(t *testing.T) {
+	require.Equal(t, "1 unread message from 1 seller", digestSummary(PendingDigest{Role: "buyer", UnreadCount: 1, SenderCount: 1}))
+	require.Equal(t, "3 unread messages from 2 buyers", digestSummary(PendingDigest{Role: "founder", UnreadCount: 3, SenderCount: 2}))
+}
+
+func TestDigestService_SendPendingDigests_SuppressedIsMarked(t *testing.T) {
+	repo := new(mockDigestRepository)
+	es := new(mockEmailService)
+	service := newTestDigestService(repo, nil, es, time.Now())
+
+	repo.On("ListPendingDigests", mock.Anything, mock.Anything).Return([]PendingDigest{
+		{UserUUID: "u1", Email: "u1@example.com", UnreadCount: 1, SenderCount: 1},
+	}, nil)
+	es.On("SendEmail", mock.Anything, "u1@example.com", mock.Anything, mock.Anything).Return(sendemail.ErrSuppressed)
+	repo.On("MarkDigestSent", mock.Anything, "u1", mock.Anything).Return(nil)
+
+	_, err := service.SendPendingDigests(context.Background())
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestDigestSummary_Localized(t *testing.T) {
+	require.Equal(t, "2 खरीदारों से 3 अपठित संदेश", digestSummary(PendingDigest{Role: "founder", Locale: "hi", UnreadCount: 3, SenderCount: 2}))
+}
@@ -0,0 +1,164 @@
+package documents
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/storage"
+	"grveyard/pkg/validation"
+)
+
+type DocumentHandler struct {
+	service *Service
+}
+
+func NewDocumentHandler(service *Service) *DocumentHandler {
+	return &DocumentHandler{service: service}
+}
+
+func (h *DocumentHandler) RegisterRoutes(router *gin.Engine) {
+	group := router.Group("/startups/:id/documents", auth.Required())
+	group.GET("", h.list)
+	group.POST("", h.createUpload)
+	group.POST("/:doc_id/complete", h.complete)
+	group.DELETE("/:doc_id", h.delete)
+}
+
+type uploadResult struct {
+	Document Document                 `json:"document"`
+	Upload   storage.PresignedRequest `json:"upload"`
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *DocumentHandler) Operations() []openapi.Operation {
+	startup := openapi.Path("id", "integer", "Startup ID")
+	document := []openapi.Param{startup, openapi.Path("doc_id", "integer", "Document ID")}
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/startups/:id/documents",
+			Tag:         "startups",
+			Summary:     "List a startup's documents",
+			Description: "Due-diligence files the seller attached, newest first, each with a download URL valid for an hour",
+			Params:      []openapi.Param{startup},
+			Response:    []Document{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/startups/:id/documents",
+			Tag:         "startups",
+			Summary:     "Start a document upload",
+			Description: "Registers a document and returns a presigned request for uploading the file, at most 50 MiB. Call complete once the upload finished. Only the startup owner can upload.",
+			Params:      []openapi.Param{startup},
+			Request:     UploadRequest{},
+			Response:    uploadResult{},
+			Status:      http.StatusCreated,
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/startups/:id/documents/:doc_id/complete",
+			Tag:         "startups",
+			Summary:     "Complete a document upload",
+			Description: "Checks the file was uploaded and lists the document. Files over 50 MiB are deleted and rejected with INVALID_DOCUMENT.",
+			Params:      document,
+			Response:    Document{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/startups/:id/documents/:doc_id",
+			Tag:     "startups",
+			Summary: "Delete a document",
+			Params:  document,
+			Errors:  []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:    true,
+		},
+	}
+}
+
+func parseID(c *gin.Context, param, what string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(param), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid "+what+" id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *DocumentHandler) list(c *gin.Context) {
+	startupID, ok := parseID(c, "id", "startup")
+	if !ok {
+		return
+	}
+
+	docs, err := h.service.List(c.Request.Context(), startupID)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "documents listed", docs)
+}
+
+func (h *DocumentHandler) createUpload(c *gin.Context) {
+	startupID, ok := parseID(c, "id", "startup")
+	if !ok {
+		return
+	}
+	var req UploadRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	doc, upload, err := h.service.CreateUpload(c.Request.Context(), startupID, auth.UserID(c), req)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusCreated, true, "upload created", uploadResult{Document: doc, Upload: upload})
+}
+
+func (h *DocumentHandler) complete(c *gin.Context) {
+	startupID, ok := parseID(c, "id", "startup")
+	if !ok {
+		return
+	}
+	id, ok := parseID(c, "doc_id", "document")
+	if !ok {
+		return
+	}
+
+	doc, err := h.service.CompleteUpload(c.Request.Context(), startupID, id, auth.UserID(c))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "document uploaded", doc)
+}
+
+func (h *DocumentHandler) delete(c *gin.Context) {
+	startupID, ok := parseID(c, "id", "startup")
+	if !ok {
+		return
+	}
+	id, ok := parseID(c, "doc_id", "document")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), startupID, id, auth.UserID(c)); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "document deleted", nil)
+}
@@ -0,0 +1,44 @@
+package documents
+
+import "time"
+
+// Kind says what a document is, so buyers can find the deck or the numbers.
+type Kind string
+
+const (
+	KindPitchDeck  Kind = "pitch_deck"
+	KindFinancials Kind = "financials"
+	KindLegal      Kind = "legal"
+	KindOther      Kind = "other"
+)
+
+// Status tracks an upload: pending until the seller has uploaded the file and
+// completed it, then ready. Only ready documents are listed.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusReady   Status = "ready"
+)
+
+// Document is a due-diligence file attached to a startup.
+type Document struct {
+	ID          int64  `json:"id"`
+	StartupID   int64  `json:"startup_id"`
+	Title       string `json:"title"`
+	Kind        Kind   `json:"kind"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size_bytes,omitempty"`
+	Status      Status `json:"status"`
+	// DownloadURL fetches the file; only filled in responses for ready documents
+	DownloadURL string    `json:"download_url,omitempty"`
+	Key         string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// UploadRequest starts a document upload.
+type UploadRequest struct {
+	Title       string `json:"title" binding:"required,max=200"`
+	Kind        Kind   `json:"kind" binding:"required,oneof=pitch_deck financials legal other"`
+	ContentType string `json:"content_type" binding:"required,oneof=application/pdf text/csv application/vnd.ms-powerpoint application/vnd.openxmlformats-officedocument.presentationml.presentation application/vnd.ms-excel application/vnd.openxmlformats-officedocument.spreadsheetml.sheet application/msword application/vnd.openxmlformats-officedocument.wordprocessingml.document"`
+}
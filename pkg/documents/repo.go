@@ -0,0 +1,95 @@
+package documents
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DocumentRepository interface {
+	// StartupOwner returns the owner of a live startup.
+	StartupOwner(ctx context.Context, startupID int64) (string, error)
+	Create(ctx context.Context, doc Document) (Document, error)
+	// Get returns a document of the startup in any status.
+	Get(ctx context.Context, startupID, id int64) (Document, error)
+	// MarkReady records the uploaded file's size and lists the document.
+	MarkReady(ctx context.Context, id, size int64) (Document, error)
+	// ListReady returns the startup's ready documents, newest first.
+	ListReady(ctx context.Context, startupID int64) ([]Document, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type postgresDocumentRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresDocumentRepository(pool *pgxpool.Pool) DocumentRepository {
+	return &postgresDocumentRepository{pool: pool}
+}
+
+const documentColumns = `id, startup_id, title, kind, content_type, size_bytes, status, storage_key, created_at`
+
+func scanDocument(row pgx.Row) (Document, error) {
+	var d Document
+	err := row.Scan(&d.ID, &d.StartupID, &d.Title, &d.Kind, &d.ContentType, &d.Size, &d.Status, &d.Key, &d.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Document{}, ErrDocumentNotFound
+	}
+	return d, err
+}
+
+func (r *postgresDocumentRepository) StartupOwner(ctx context.Context, startupID int64) (string, error) {
+	var owner string
+	err := r.pool.QueryRow(ctx, `SELECT owner_uuid FROM startups WHERE id = $1 AND is_deleted = false`, startupID).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrStartupNotFound
+	}
+	return owner, err
+}
+
+func (r *postgresDocumentRepository) Create(ctx context.Context, doc Document) (Document, error) {
+	return scanDocument(r.pool.QueryRow(ctx, `
+		INSERT INTO startup_documents (startup_id, title, kind, content_type, storage_key)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+documentColumns, doc.StartupID, doc.Title, doc.Kind, doc.ContentType, doc.Key))
+}
+
+func (r *postgresDocumentRepository) Get(ctx context.Context, startupID, id int64) (Document, error) {
+	return scanDocument(r.pool.QueryRow(ctx, `
+		SELECT `+documentColumns+` FROM startup_documents WHERE startup_id = $1 AND id = $2`, startupID, id))
+}
+
+func (r *postgresDocumentRepository) MarkReady(ctx context.Context, id, size int64) (Document, error) {
+	return scanDocument(r.pool.QueryRow(ctx, `
+		UPDATE startup_documents SET status = 'ready', size_bytes = $2 WHERE id = $1
+		RETURNING `+documentColumns, id, size))
+}
+
+func (r *postgresDocumentRepository) ListReady(ctx context.Context, startupID int64) ([]Document, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+documentColumns+`
+		FROM startup_documents
+		WHERE startup_id = $1 AND status = 'ready'
+		ORDER BY created_at DESC, id DESC`, startupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Document{}
+	for rows.Next() {
+		d, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, d)
+	}
+	return list, rows.Err()
+}
+
+func (r *postgresDocumentRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM startup_documents WHERE id = $1`, id)
+	return err
+}
@@ -0,0 +1,53 @@
+package documents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresDocumentRepository(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresDocumentRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	startup := testhelpers.NewStartup(t, pool, testhelpers.WithStartupOwner(seller.UUID))
+
+	owner, err := repo.StartupOwner(ctx, startup.ID)
+	require.NoError(t, err)
+	require.Equal(t, seller.UUID, owner)
+	_, err = repo.StartupOwner(ctx, 0)
+	require.ErrorIs(t, err, ErrStartupNotFound)
+
+	doc, err := repo.Create(ctx, Document{StartupID: startup.ID, Title: "Deck", Kind: KindPitchDeck, ContentType: "application/pdf", Key: "startup-documents/1/deck"})
+	require.NoError(t, err)
+	require.Equal(t, StatusPending, doc.Status)
+
+	// Pending documents are not listed
+	list, err := repo.ListReady(ctx, startup.ID)
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	ready, err := repo.MarkReady(ctx, doc.ID, 1234)
+	require.NoError(t, err)
+	require.Equal(t, StatusReady, ready.Status)
+	require.EqualValues(t, 1234, ready.Size)
+	list, err = repo.ListReady(ctx, startup.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "startup-documents/1/deck", list[0].Key)
+
+	_, err = repo.Get(ctx, startup.ID+1, doc.ID)
+	require.ErrorIs(t, err, ErrDocumentNotFound)
+
+	require.NoError(t, repo.Delete(ctx, doc.ID))
+	_, err = repo.Get(ctx, startup.ID, doc.ID)
+	require.ErrorIs(t, err, ErrDocumentNotFound)
+}
@@ -0,0 +1,160 @@
+// Package documents lets sellers attach due-diligence files, such as pitch decks and
+// financials, to their startups. Files go straight to storage with presigned URLs.
+package documents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/requestid"
+	"grveyard/pkg/storage"
+)
+
+const (
+	maxDocumentBytes = 50 << 20
+	uploadExpiry     = 15 * time.Minute
+	urlExpiry        = time.Hour
+)
+
+var (
+	ErrStartupNotFound  = apperr.New(apperr.StartupNotFound, "startup not found")
+	ErrDocumentNotFound = apperr.New(apperr.DocumentNotFound, "document not found")
+	ErrNotOwner         = apperr.New(apperr.Forbidden, "only the startup owner can manage its documents")
+	ErrNoUpload         = apperr.New(apperr.InvalidDocument, "no file was uploaded")
+	ErrTooLarge         = apperr.New(apperr.InvalidDocument, "documents must be at most 50 MiB")
+)
+
+type Service struct {
+	repo  DocumentRepository
+	store storage.Storage
+}
+
+func NewService(repo DocumentRepository, store storage.Storage) *Service {
+	return &Service{repo: repo, store: store}
+}
+
+// CreateUpload registers a pending document and returns where the owner should PUT
+// the file. The document is listed once CompleteUpload is called.
+func (s *Service) CreateUpload(ctx context.Context, startupID int64, userUUID string, req UploadRequest) (Document, storage.PresignedRequest, error) {
+	if err := s.checkOwner(ctx, startupID, userUUID); err != nil {
+		return Document{}, storage.PresignedRequest{}, err
+	}
+	doc, err := s.repo.Create(ctx, Document{
+		StartupID:   startupID,
+		Title:       req.Title,
+		Kind:        req.Kind,
+		ContentType: req.ContentType,
+		Key:         fmt.Sprintf("startup-documents/%d/%s", startupID, uuid.NewString()),
+	})
+	if err != nil {
+		return Document{}, storage.PresignedRequest{}, err
+	}
+	upload, err := s.store.PresignUpload(ctx, doc.Key, doc.ContentType, uploadExpiry)
+	if err != nil {
+		return Document{}, storage.PresignedRequest{}, err
+	}
+	return doc, upload, nil
+}
+
+// CompleteUpload checks the file arrived and is within the size limit, then lists the
+// document. An oversized file is deleted.
+func (s *Service) CompleteUpload(ctx context.Context, startupID, id int64, userUUID string) (Document, error) {
+	if err := s.checkOwner(ctx, startupID, userUUID); err != nil {
+		return Document{}, err
+	}
+	doc, err := s.repo.Get(ctx, startupID, id)
+	if err != nil {
+		return Document{}, err
+	}
+
+	rc, err := s.store.Get(ctx, doc.Key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return Document{}, ErrNoUpload
+	}
+	if err != nil {
+		return Document{}, err
+	}
+	size, err := io.Copy(io.Discard, io.LimitReader(rc, maxDocumentBytes+1))
+	rc.Close()
+	if err != nil {
+		return Document{}, err
+	}
+	if size > maxDocumentBytes {
+		s.deleteFile(ctx, doc)
+		return Document{}, ErrTooLarge
+	}
+
+	doc, err = s.repo.MarkReady(ctx, doc.ID, size)
+	if err != nil {
+		return Document{}, err
+	}
+	return s.withDownloadURL(ctx, doc)
+}
+
+// List returns the startup's ready documents with download URLs.
+func (s *Service) List(ctx context.Context, startupID int64) ([]Document, error) {
+	if _, err := s.repo.StartupOwner(ctx, startupID); err != nil {
+		return nil, err
+	}
+	docs, err := s.repo.ListReady(ctx, startupID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range docs {
+		if docs[i], err = s.withDownloadURL(ctx, docs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// Delete removes a document, pending or ready, and its file.
+func (s *Service) Delete(ctx context.Context, startupID, id int64, userUUID string) error {
+	if err := s.checkOwner(ctx, startupID, userUUID); err != nil {
+		return err
+	}
+	doc, err := s.repo.Get(ctx, startupID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, doc.ID); err != nil {
+		return err
+	}
+	s.deleteFile(ctx, doc)
+	return nil
+}
+
+func (s *Service) withDownloadURL(ctx context.Context, doc Document) (Document, error) {
+	u, err := s.store.PresignDownload(ctx, doc.Key, urlExpiry)
+	if err != nil {
+		return Document{}, err
+	}
+	doc.DownloadURL = u
+	return doc, nil
+}
+
+// deleteFile removes the stored file. Failures are logged; the row is what lists the
+// document, so a leftover file is never shown.
+func (s *Service) deleteFile(ctx context.Context, doc Document) {
+	if err := s.store.Delete(ctx, doc.Key); err != nil {
+		log.Printf("[%s] delete document %d of startup %d: %v", requestid.FromContext(ctx), doc.ID, doc.StartupID, err)
+	}
+}
+
+func (s *Service) checkOwner(ctx context.Context, startupID int64, userUUID string) error {
+	owner, err := s.repo.StartupOwner(ctx, startupID)
+	if err != nil {
+		return err
+	}
+	if owner != userUUID {
+		return ErrNotOwner
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package documents
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/storage"
+)
+
+// fakeDocumentRepository keeps documents of startup 5, owned by "seller", in memory.
+type fakeDocumentRepository struct {
+	docs   map[int64]Document
+	nextID int64
+}
+
+func (f *fakeDocumentRepository) StartupOwner(_ context.Context, startupID int64) (string, error) {
+	if startupID != 5 {
+		return "", ErrStartupNotFound
+	}
+	return "seller", nil
+}
+
+func (f *fakeDocumentRepository) Create(_ context.Context, doc Document) (Document, error) {
+	f.nextID++
+	doc.ID, doc.Status = f.nextID, StatusPending
+	f.docs[doc.ID] = doc
+	return doc, nil
+}
+
+func (f *fakeDocumentRepository) Get(_ context.Context, startupID, id int64) (Document, error) {
+	doc, ok := f.docs[id]
+	if !ok || doc.StartupID != startupID {
+		return Document{}, ErrDocumentNotFound
+	}
+	return doc, nil
+}
+
+func (f *fakeDocumentRepository) MarkReady(_ context.Context, id, size int64) (Document, error) {
+	doc := f.docs[id]
+	doc.Status, doc.Size = StatusReady, size
+	f.docs[id] = doc
+	return doc, nil
+}
+
+func (f *fakeDocumentRepository) ListReady(_ context.Context, startupID int64) ([]Document, error) {
+	list := []Document{}
+	for _, doc := range f.docs {
+		if doc.StartupID == startupID && doc.Status == StatusReady {
+			list = append(list, doc)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeDocumentRepository) Delete(_ context.Context, id int64) error {
+	delete(f.docs, id)
+	return nil
+}
+
+// memStorage is an in-memory storage.Storage.
+type memStorage struct {
+	objects map[string][]byte
+}
+
+func (s *memStorage) Put(_ context.Context, key string, body io.Reader, _ int64, _ string) error {
+	b, err := io.ReadAll(body)
+	s.objects[key] = b
+	return err
+}
+
+func (s *memStorage) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	b, ok := s.objects[key]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (s *memStorage) PresignUpload(_ context.Context, key, contentType string, _ time.Duration) (storage.PresignedRequest, error) {
+	return storage.PresignedRequest{Method: "PUT", URL: "https://files.example.com/" + key, Headers: map[string]string{"Content-Type": contentType}}, nil
+}
+
+func (s *memStorage) PresignDownload(_ context.Context, key string, _ time.Duration) (string, error) {
+	return "https://files.example.com/" + key, nil
+}
+
+func (s *memStorage) Delete(_ context.Context, key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+func newTestService() (*Service, *fakeDocumentRepository, *memStorage) {
+	repo := &fakeDocumentRepository{docs: map[int64]Document{}}
+	store := &memStorage{objects: map[string][]byte{}}
+	return NewService(repo, store), repo, store
+}
+
+func TestService_UploadFlow(t *testing.T) {
+	service, _, store := newTestService()
+	ctx := context.Background()
+	req := UploadRequest{Title: "Deck", Kind: KindPitchDeck, ContentType: "application/pdf"}
+
+	_, _, err := service.CreateUpload(ctx, 5, "someone-else", req)
+	require.ErrorIs(t, err, ErrNotOwner)
+	_, _, err = service.CreateUpload(ctx, 6, "seller", req)
+	require.ErrorIs(t, err, ErrStartupNotFound)
+
+	doc, upload, err := service.CreateUpload(ctx, 5, "seller", req)
+	require.NoError(t, err)
+	require.Equal(t, "https://files.example.com/"+doc.Key, upload.URL)
+	require.Equal(t, "application/pdf", upload.Headers["Content-Type"])
+
+	// Completing before the file arrived fails
+	_, err = service.CompleteUpload(ctx, 5, doc.ID, "seller")
+	require.ErrorIs(t, err, ErrNoUpload)
+	list, err := service.List(ctx, 5)
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	store.objects[doc.Key] = []byte("%PDF-1.7")
+	done, err := service.CompleteUpload(ctx, 5, doc.ID, "seller")
+	require.NoError(t, err)
+	require.Equal(t, StatusReady, done.Status)
+	require.EqualValues(t, 8, done.Size)
+	require.NotEmpty(t, done.DownloadURL)
+
+	list, err = service.List(ctx, 5)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "https://files.example.com/"+doc.Key, list[0].DownloadURL)
+
+	require.ErrorIs(t, service.Delete(ctx, 5, doc.ID, "someone-else"), ErrNotOwner)
+	require.NoError(t, service.Delete(ctx, 5, doc.ID, "seller"))
+	require.NotContains(t, store.objects, doc.Key)
+	require.ErrorIs(t, service.Delete(ctx, 5, doc.ID, "seller"), ErrDocumentNotFound)
+}
+
+func TestService_CompleteUpload_TooLarge(t *testing.T) {
+	service, repo, store := newTestService()
+	ctx := context.Background()
+
+	doc, _, err := service.CreateUpload(ctx, 5, "seller", UploadRequest{Title: "Numbers", Kind: KindFinancials, ContentType: "text/csv"})
+	require.NoError(t, err)
+	store.objects[doc.Key] = make([]byte, maxDocumentBytes+1)
+
+	_, err = service.CompleteUpload(ctx, 5, doc.ID, "seller")
+	require.ErrorIs(t, err, ErrTooLarge)
+	require.NotContains(t, store.objects, doc.Key)
+	require.Equal(t, StatusPending, repo.docs[doc.ID].Status)
+}
@@ -0,0 +1,108 @@
+// Package errorreport sends panics and unexpected errors to Sentry (or any service
+// that accepts the Sentry store API). Without SENTRY_DSN every function is a no-op, so
+// callers never need to check whether reporting is configured.
+package errorreport
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+
+	"grveyard/pkg/requestid"
+)
+
+// Fields add searchable tags to an event, e.g. {"job": "sitemap"}. The UserField
+// entry is reported as the event's user rather than as a tag.
+type Fields map[string]string
+
+// UserField is the Fields key holding the affected user's UUID.
+const UserField = "user_uuid"
+
+var current atomic.Pointer[client]
+
+// Setup enables reporting when SENTRY_DSN is set, tagging events with
+// SENTRY_ENVIRONMENT and SENTRY_RELEASE. The returned function waits for queued
+// events to be delivered and should be called before the process exits.
+func Setup() (func(context.Context) error, error) {
+	dsn := os.Getenv("SENTRY_DSN")
+	if dsn == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	c, err := newClient(dsn, os.Getenv("SENTRY_ENVIRONMENT"), os.Getenv("SENTRY_RELEASE"))
+	if err != nil {
+		return nil, err
+	}
+	current.Store(c)
+	log.Printf("Error reporting enabled for project %s", c.projectID)
+
+	return c.flush, nil
+}
+
+// CaptureError reports err. The request ID is taken from ctx when present.
+func CaptureError(ctx context.Context, err error, fields Fields) {
+	c := current.Load()
+	if c == nil || err == nil {
+		return
+	}
+	c.enqueue(newEvent(ctx, "error", fmt.Sprintf("%T", err), err.Error(), fields))
+}
+
+// CapturePanic reports a value returned by recover(). Call it from the deferred
+// function so the stack trace still includes the panicking frames.
+func CapturePanic(ctx context.Context, recovered any, fields Fields) {
+	c := current.Load()
+	if c == nil {
+		return
+	}
+	c.enqueue(newEvent(ctx, "fatal", "panic", fmt.Sprint(recovered), fields))
+}
+
+// Recover logs and reports a panic in the calling goroutine instead of letting it
+// crash the process. It only works when deferred directly:
+//
+//	defer errorreport.Recover(ctx, errorreport.Fields{"goroutine": "chat.readLoop"})
+func Recover(ctx context.Context, fields Fields) {
+	if r := recover(); r != nil {
+		log.Printf("recovered panic %v: %v", map[string]string(fields), r)
+		CapturePanic(ctx, r, fields)
+	}
+}
+
+func newEvent(ctx context.Context, level, typ, value string, fields Fields) *event {
+	ev := &event{
+		Level: level,
+		Exception: &exceptions{Values: []exception{{
+			Type:       typ,
+			Value:      value,
+			Stacktrace: &stacktrace{Frames: callers()},
+		}}},
+		Tags: map[string]string{},
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		ev.Tags["request_id"] = id
+	}
+	for k, v := range fields {
+		if k == UserField {
+			if v != "" {
+				ev.User = &user{ID: v}
+			}
+			continue
+		}
+		ev.Tags[k] = v
+	}
+	if r, ok := ctx.Value(requestKey{}).(*request); ok {
+		ev.Request = r
+	}
+	return ev
+}
+
+// requestKey carries the HTTP request summary attached by Recovery, so errors
+// captured further down the handler chain include it too.
+type requestKey struct{}
+
+func withRequest(ctx context.Context, r *request) context.Context {
+	return context.WithValue(ctx, requestKey{}, r)
+}
@@ -0,0 +1,136 @@
+package errorreport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/requestid"
+)
+
+// fakeSentry records events posted to the store endpoint.
+func fakeSentry(t *testing.T) <-chan event {
+	t.Helper()
+	events := make(chan event, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/42/store/", r.URL.Path)
+		require.Contains(t, r.Header.Get("X-Sentry-Auth"), "sentry_key=public")
+
+		var ev event
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&ev))
+		events <- ev
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Setenv("SENTRY_DSN", strings.Replace(srv.URL, "://", "://public@", 1)+"/42")
+	t.Setenv("SENTRY_ENVIRONMENT", "test")
+	flush, err := Setup()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = flush(context.Background())
+		current.Store(nil)
+	})
+	return events
+}
+
+func receive(t *testing.T, events <-chan event) event {
+	t.Helper()
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+		return event{}
+	}
+}
+
+func TestNewClient_DSN(t *testing.T) {
+	c, err := newClient("https://abc@o1.ingest.sentry.io/123", "", "")
+	require.NoError(t, err)
+	require.Equal(t, "https://o1.ingest.sentry.io/api/123/store/", c.endpoint)
+	require.Contains(t, c.auth, "sentry_key=abc")
+
+	c, err = newClient("https://abc@errors.example.com/glitchtip/7", "", "")
+	require.NoError(t, err)
+	require.Equal(t, "https://errors.example.com/glitchtip/api/7/store/", c.endpoint)
+
+	for _, dsn := range []string{"https://o1.ingest.sentry.io/123", "https://abc@o1.ingest.sentry.io/", "ftp://abc@host/1"} {
+		_, err := newClient(dsn, "", "")
+		require.Error(t, err, dsn)
+	}
+}
+
+func TestSetup_DisabledWithoutDSN(t *testing.T) {
+	t.Setenv("SENTRY_DSN", "")
+	flush, err := Setup()
+	require.NoError(t, err)
+	require.Nil(t, current.Load())
+
+	CaptureError(context.Background(), errors.New("ignored"), nil)
+	require.NoError(t, flush(context.Background()))
+}
+
+func TestCaptureError(t *testing.T) {
+	events := fakeSentry(t)
+
+	ctx := requestid.NewContext(context.Background(), "req-1")
+	CaptureError(ctx, errors.New("boom"), Fields{"job": "sitemap", UserField: "user-1"})
+
+	ev := receive(t, events)
+	require.Equal(t, "error", ev.Level)
+	require.Equal(t, "test", ev.Environment)
+	require.Len(t, ev.EventID, 32)
+	require.Equal(t, "boom", ev.Exception.Values[0].Value)
+	require.Equal(t, map[string]string{"job": "sitemap", "request_id": "req-1"}, ev.Tags)
+	require.Equal(t, "user-1", ev.User.ID)
+}
+
+func TestRecover(t *testing.T) {
+	events := fakeSentry(t)
+
+	func() {
+		defer Recover(context.Background(), Fields{"goroutine": "test"})
+		panic("kaboom")
+	}()
+
+	ev := receive(t, events)
+	require.Equal(t, "fatal", ev.Level)
+	require.Equal(t, "kaboom", ev.Exception.Values[0].Value)
+	require.NotEmpty(t, ev.Exception.Values[0].Stacktrace.Frames)
+}
+
+func TestRecovery_ReportsPanic(t *testing.T) {
+	events := fakeSentry(t)
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(requestid.Middleware(), Recovery())
+	router.GET("/boom/:id", func(c *gin.Context) { panic("handler exploded") })
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/boom/7?user_id=user-9", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set(requestid.Header, "req-42")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Contains(t, w.Body.String(), `"request_id":"req-42"`)
+
+	ev := receive(t, events)
+	require.Equal(t, "handler exploded", ev.Exception.Values[0].Value)
+	require.Equal(t, "user-9", ev.User.ID)
+	require.Equal(t, "req-42", ev.Tags["request_id"])
+	require.Equal(t, "/boom/:id", ev.Tags["route"])
+	require.Equal(t, http.MethodGet, ev.Request.Method)
+	require.Equal(t, "http://example.com/boom/7", ev.Request.URL)
+	require.NotContains(t, ev.Request.Headers, "Authorization")
+}
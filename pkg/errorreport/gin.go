@@ -0,0 +1,67 @@
+package errorreport
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/response"
+)
+
+// reportedHeaders are copied onto events. Credentials (Authorization, Cookie) are
+// deliberately left out.
+var reportedHeaders = []string{"User-Agent", "Referer", "Content-Type", "X-Forwarded-For", "X-Request-ID"}
+
+// Recovery replaces gin.Recovery: panics are still logged with a stack trace, then
+// reported with the request, request ID and user UUID, and answered with a 500 in the
+// usual response envelope. It also attaches the request summary to the request
+// context, so CaptureError calls made by handlers include it.
+func Recovery() gin.HandlerFunc {
+	recovery := gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		CapturePanic(c.Request.Context(), recovered, Fields{
+			UserField: userUUID(c),
+			"route":   c.FullPath(),
+		})
+		response.SendError(c, apperr.New(apperr.Internal, "internal server error"))
+		c.Abort()
+	})
+
+	return func(c *gin.Context) {
+		if current.Load() != nil {
+			c.Request = c.Request.WithContext(withRequest(c.Request.Context(), summarize(c.Request)))
+		}
+		recovery(c)
+	}
+}
+
+// userUUID finds the caller's UUID: set on the gin context by authenticated
+// middleware, or passed as ?user_id= by the chat and inbox endpoints.
+func userUUID(c *gin.Context) string {
+	if uid := c.GetString("user_id"); uid != "" {
+		return uid
+	}
+	return c.Query("user_id")
+}
+
+func summarize(r *http.Request) *request {
+	u := *r.URL
+	u.RawQuery = ""
+	if u.Host == "" {
+		u.Host = r.Host
+	}
+	if u.Scheme == "" {
+		u.Scheme = "http"
+		if r.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+
+	headers := make(map[string]string)
+	for _, h := range reportedHeaders {
+		if v := r.Header.Get(h); v != "" {
+			headers[h] = v
+		}
+	}
+	return &request{Method: r.Method, URL: u.String(), Query: r.URL.RawQuery, Headers: headers}
+}
@@ -0,0 +1,248 @@
+package errorreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	queueSize   = 100
+	sendTimeout = 10 * time.Second
+	maxFrames   = 50
+)
+
+// event is the subset of the Sentry event payload we fill in.
+// https://develop.sentry.dev/sdk/data-model/event-payloads/
+type event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Exception   *exceptions       `json:"exception,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	User        *user             `json:"user,omitempty"`
+	Request     *request          `json:"request,omitempty"`
+}
+
+type exceptions struct {
+	Values []exception `json:"values"`
+}
+
+type exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Stacktrace *stacktrace `json:"stacktrace,omitempty"`
+}
+
+type stacktrace struct {
+	Frames []frame `json:"frames"`
+}
+
+type frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+type user struct {
+	ID string `json:"id"`
+}
+
+type request struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Query   string            `json:"query_string,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// client delivers events on a single background goroutine so capturing never blocks
+// the caller on the network. Events are dropped when the queue is full.
+type client struct {
+	endpoint    string
+	auth        string
+	projectID   string
+	environment string
+	release     string
+	serverName  string
+	httpClient  *http.Client
+
+	queue   chan *event
+	pending sync.WaitGroup
+}
+
+// newClient parses a DSN of the form https://<public key>@<host>/<project id>.
+func newClient(dsn, environment, release string) (*client, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SENTRY_DSN: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid SENTRY_DSN: unsupported scheme %q", u.Scheme)
+	}
+	key := u.User.Username()
+	if key == "" {
+		return nil, fmt.Errorf("invalid SENTRY_DSN: missing public key")
+	}
+	path := strings.Trim(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	prefix, projectID := path[:max(i, 0)], path[i+1:]
+	if projectID == "" {
+		return nil, fmt.Errorf("invalid SENTRY_DSN: missing project id")
+	}
+
+	endpoint := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}
+	if prefix != "" {
+		endpoint.Path += prefix + "/"
+	}
+	endpoint.Path += "api/" + projectID + "/store/"
+
+	hostname, _ := os.Hostname()
+	c := &client{
+		endpoint:    endpoint.String(),
+		auth:        fmt.Sprintf("Sentry sentry_version=7, sentry_client=grveyard/1.0, sentry_key=%s", key),
+		projectID:   projectID,
+		environment: environment,
+		release:     release,
+		serverName:  hostname,
+		httpClient:  &http.Client{Timeout: sendTimeout},
+		queue:       make(chan *event, queueSize),
+	}
+	go c.worker()
+	return c, nil
+}
+
+func (c *client) enqueue(ev *event) {
+	ev.EventID = newEventID()
+	ev.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	ev.Platform = "go"
+	ev.ServerName = c.serverName
+	ev.Environment = c.environment
+	ev.Release = c.release
+
+	c.pending.Add(1)
+	select {
+	case c.queue <- ev:
+	default:
+		c.pending.Done()
+		log.Printf("error report queue full, dropping event %s", ev.EventID)
+	}
+}
+
+func (c *client) worker() {
+	for ev := range c.queue {
+		if err := c.send(ev); err != nil {
+			log.Printf("failed to send error report %s: %v", ev.EventID, err)
+		}
+		c.pending.Done()
+	}
+}
+
+func (c *client) send(ev *event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", c.auth)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// flush waits until every queued event has been sent or ctx expires.
+func (c *client) flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		c.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// callers returns the current stack, oldest frame first as Sentry expects, without
+// the runtime's panic machinery or this package's own frames.
+func callers() []frame {
+	pcs := make([]uintptr, maxFrames)
+	n := runtime.Callers(1, pcs)
+	iter := runtime.CallersFrames(pcs[:n])
+
+	var frames []frame
+	for {
+		f, more := iter.Next()
+		if !skipFrame(f.Function) {
+			module, function := splitFunction(f.Function)
+			frames = append(frames, frame{
+				Function: function,
+				Module:   module,
+				Filename: f.File,
+				Lineno:   f.Line,
+				InApp:    strings.HasPrefix(module, "grveyard/"),
+			})
+		}
+		if !more {
+			break
+		}
+	}
+
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return frames
+}
+
+func skipFrame(function string) bool {
+	return strings.HasPrefix(function, "runtime.") ||
+		strings.HasPrefix(function, "grveyard/pkg/errorreport.")
+}
+
+// splitFunction turns "grveyard/pkg/chat.(*Handler).readLoop" into
+// ("grveyard/pkg/chat", "(*Handler).readLoop").
+func splitFunction(name string) (string, string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+	dot += slash + 1
+	return name[:dot], name[dot+1:]
+}
@@ -0,0 +1,95 @@
+package etag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// volatileFields are top-level response fields that change on every request and
+// must not affect the ETag (response.APIResponse stamps created_at with time.Now).
+var volatileFields = []string{"created_at", "request_id"}
+
+// bufferedWriter holds the response back so the ETag can be computed from the body.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int)              { w.status = code }
+func (w *bufferedWriter) WriteHeaderNow()                   {}
+func (w *bufferedWriter) Status() int                       { return w.status }
+func (w *bufferedWriter) Write(b []byte) (int, error)       { return w.body.Write(b) }
+func (w *bufferedWriter) WriteString(s string) (int, error) { return w.body.WriteString(s) }
+
+// Middleware adds a strong ETag to successful GET responses and answers matching
+// If-None-Match requests with 304 Not Modified and no body.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		bw := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = bw
+		c.Next()
+		c.Writer = original
+
+		if bw.status != http.StatusOK {
+			original.WriteHeader(bw.status)
+			original.Write(bw.body.Bytes())
+			return
+		}
+
+		tag := compute(bw.body.Bytes())
+		original.Header().Set("ETag", tag)
+		if matches(c.GetHeader("If-None-Match"), tag) {
+			original.Header().Del("Content-Type")
+			original.Header().Del("Content-Length")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+		original.WriteHeader(http.StatusOK)
+		original.Write(bw.body.Bytes())
+	}
+}
+
+// compute hashes the JSON body without volatile fields. Non-JSON bodies hash as-is.
+func compute(body []byte) string {
+	content := body
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) == nil {
+		for _, f := range volatileFields {
+			delete(fields, f)
+		}
+		// map keys marshal sorted, so the encoding is stable
+		if b, err := json.Marshal(fields); err == nil {
+			content = b
+		}
+	}
+	sum := sha256.Sum256(content)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// matches implements If-None-Match's weak comparison over a comma separated list.
+func matches(header, tag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package etag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/response"
+)
+
+func setupRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/item", Middleware(), func(c *gin.Context) {
+		response.SendAPIResponse(c, http.StatusOK, true, "item fetched", map[string]any{"id": 1})
+	})
+	r.GET("/missing", Middleware(), func(c *gin.Context) {
+		response.SendAPIResponse(c, http.StatusNotFound, false, "not found", nil)
+	})
+	return r
+}
+
+func TestMiddleware_ETagStableAcrossRequests(t *testing.T) {
+	r := setupRouter()
+
+	w1 := httptest.NewRecorder()
+	r.ServeHTTP(w1, httptest.NewRequest(http.MethodGet, "/item", nil))
+	time.Sleep(2 * time.Millisecond)
+	w2 := httptest.NewRecorder()
+	r.ServeHTTP(w2, httptest.NewRequest(http.MethodGet, "/item", nil))
+
+	require.Equal(t, http.StatusOK, w1.Code)
+	require.NotEmpty(t, w1.Header().Get("ETag"))
+	require.Equal(t, w1.Header().Get("ETag"), w2.Header().Get("ETag"))
+	require.Contains(t, w1.Body.String(), "item fetched")
+}
+
+func TestMiddleware_NotModified(t *testing.T) {
+	r := setupRouter()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/item", nil))
+	tag := w.Header().Get("ETag")
+
+	req := httptest.NewRequest(http.MethodGet, "/item", nil)
+	req.Header.Set("If-None-Match", `"other", `+tag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotModified, w.Code)
+	require.Empty(t, w.Body.String())
+}
+
+func TestMiddleware_SkipsErrors(t *testing.T) {
+	r := setupRouter()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Empty(t, w.Header().Get("ETag"))
+	require.Contains(t, w.Body.String(), "not found")
+}
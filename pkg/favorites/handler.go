@@ -0,0 +1,204 @@
+package favorites
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/response"
+)
+
+type FavoriteHandler struct {
+	service *Service
+}
+
+func NewFavoriteHandler(service *Service) *FavoriteHandler {
+	return &FavoriteHandler{service: service}
+}
+
+func (h *FavoriteHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/assets/:id/favorite", auth.Required(), h.add)
+	router.DELETE("/assets/:id/favorite", auth.Required(), h.remove)
+	router.GET("/users/:uuid/favorites", auth.RequireSelf("uuid"), h.list)
+	router.POST("/users/:uuid/favorites/share", auth.RequireSelf("uuid"), h.share)
+	router.DELETE("/users/:uuid/favorites/share", auth.RequireSelf("uuid"), h.unshare)
+	router.GET("/lists/:share_token", h.listShared)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *FavoriteHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/assets/:id/favorite",
+			Tag:         "favorites",
+			Summary:     "Favorite an asset",
+			Description: "Adds the asset to the caller's watchlist. Watchers are notified in-app when its price changes or it sells, and see the change on their buyer dashboard. Favoriting an asset twice returns 200.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Status: http.StatusCreated,
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/assets/:id/favorite",
+			Tag:     "favorites",
+			Summary: "Remove an asset favorite",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/favorites",
+			Tag:         "favorites",
+			Summary:     "List favorite assets",
+			Description: "The user's watchlist, most recent first, with each asset's current price and availability",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 20)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[Favorite]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/users/:uuid/favorites/share",
+			Tag:         "favorites",
+			Summary:     "Publish favorites as a public list",
+			Description: "Opt in to a read-only link at /lists/:share_token that anyone can open without an account. Publishing an already published list returns the existing link.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Response: Share{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/users/:uuid/favorites/share",
+			Tag:         "favorites",
+			Summary:     "Revoke the public favorites list",
+			Description: "The share token stops working immediately. Publishing again issues a new token.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/lists/:share_token",
+			Tag:         "favorites",
+			Summary:     "View a shared favorites list",
+			Description: "The assets on a published list, most recent first, with their current price and availability. Returns 404 once the owner revokes the link.",
+			Params: []openapi.Param{
+				openapi.Path("share_token", "string", "Token from the publish response"),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 20)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[Favorite]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+	}
+}
+
+const favoritesPageSize = 20
+
+func assetID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid asset id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *FavoriteHandler) add(c *gin.Context) {
+	id, ok := assetID(c)
+	if !ok {
+		return
+	}
+	added, err := h.service.Add(c.Request.Context(), auth.UserID(c), id)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	if !added {
+		response.SendAPIResponse(c, http.StatusOK, true, "asset already favorited", nil)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusCreated, true, "asset favorited", nil)
+}
+
+func (h *FavoriteHandler) remove(c *gin.Context) {
+	id, ok := assetID(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Remove(c.Request.Context(), auth.UserID(c), id); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "favorite removed", nil)
+}
+
+func (h *FavoriteHandler) list(c *gin.Context) {
+	p, err := pagination.FromRequest(c, favoritesPageSize)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	list, total, err := h.service.List(c.Request.Context(), c.Param("uuid"), p)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "favorite assets listed", list, total, p)
+}
+
+func (h *FavoriteHandler) share(c *gin.Context) {
+	share, err := h.service.Share(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "favorites shared", share)
+}
+
+func (h *FavoriteHandler) unshare(c *gin.Context) {
+	if err := h.service.Unshare(c.Request.Context(), c.Param("uuid")); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "share link revoked", nil)
+}
+
+func (h *FavoriteHandler) listShared(c *gin.Context) {
+	p, err := pagination.FromRequest(c, favoritesPageSize)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	list, total, err := h.service.ListShared(c.Request.Context(), c.Param("share_token"), p)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "shared list fetched", list, total, p)
+}
@@ -0,0 +1,21 @@
+package favorites
+
+import "time"
+
+// Favorite is an asset on a user's watchlist, with the asset's current details.
+type Favorite struct {
+	AssetID     int64     `json:"asset_id"`
+	Title       string    `json:"title"`
+	ImageURL    string    `json:"image_url"`
+	Price       float64   `json:"price"`
+	IsSold      bool      `json:"is_sold"`
+	FavoritedAt time.Time `json:"favorited_at"`
+}
+
+// Share is the public read-only link to a user's favorites. Anyone with the token can
+// view the list at Path until the owner revokes it.
+type Share struct {
+	Token     string    `json:"share_token"`
+	Path      string    `json:"path"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,157 @@
+package favorites
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// FavoriteRepository stores asset favorites, the buyer's watchlist. Startups are
+// followed separately through bookmarks.
+type FavoriteRepository interface {
+	// Add favorites an asset that exists and is not deleted. added is false when the
+	// user had already favorited it.
+	Add(ctx context.Context, userUUID string, assetID int64) (added bool, err error)
+	Remove(ctx context.Context, userUUID string, assetID int64) error
+	AssetExists(ctx context.Context, assetID int64) (bool, error)
+	ListByUser(ctx context.Context, userUUID string, limit, offset int) ([]Favorite, int64, error)
+	// Watchers returns the users who favorited an asset, and its title.
+	Watchers(ctx context.Context, assetID int64) (title string, userUUIDs []string, err error)
+	// Share publishes the user's favorites under token, or returns the token and
+	// publish time of the link that is already live.
+	Share(ctx context.Context, userUUID, token string) (string, time.Time, error)
+	Unshare(ctx context.Context, userUUID string) error
+	// SharedBy returns the user who published token.
+	SharedBy(ctx context.Context, token string) (string, error)
+}
+
+type postgresFavoriteRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresFavoriteRepository(pool *pgxpool.Pool) FavoriteRepository {
+	return &postgresFavoriteRepository{pool: pool}
+}
+
+func (r *postgresFavoriteRepository) Add(ctx context.Context, userUUID string, assetID int64) (bool, error) {
+	query := `INSERT INTO asset_favorites (user_uuid, asset_id) VALUES ($1, $2)
+			  ON CONFLICT (user_uuid, asset_id) DO NOTHING`
+	cmd, err := r.pool.Exec(ctx, query, userUUID, assetID)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+func (r *postgresFavoriteRepository) Remove(ctx context.Context, userUUID string, assetID int64) error {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM asset_favorites WHERE user_uuid = $1 AND asset_id = $2`, userUUID, assetID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrFavoriteNotFound
+	}
+	return nil
+}
+
+func (r *postgresFavoriteRepository) AssetExists(ctx context.Context, assetID int64) (bool, error) {
+	var ok bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM assets WHERE id = $1 AND is_deleted = false)`, assetID).Scan(&ok)
+	return ok, err
+}
+
+// ListByUser leaves out assets deleted since they were favorited.
+func (r *postgresFavoriteRepository) ListByUser(ctx context.Context, userUUID string, limit, offset int) ([]Favorite, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM asset_favorites f
+				   JOIN assets a ON a.id = f.asset_id AND a.is_deleted = false
+				   WHERE f.user_uuid = $1`
+	if err := r.pool.QueryRow(ctx, countQuery, userUUID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT a.id, a.title, COALESCE(a.image_url, ''), COALESCE(a.price, 0)::float8, a.is_sold, f.created_at
+			  FROM asset_favorites f
+			  JOIN assets a ON a.id = f.asset_id AND a.is_deleted = false
+			  WHERE f.user_uuid = $1
+			  ORDER BY f.created_at DESC, a.id DESC
+			  LIMIT $2 OFFSET $3`
+	rows, err := r.pool.Query(ctx, query, userUUID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	list := []Favorite{}
+	for rows.Next() {
+		var f Favorite
+		if err := rows.Scan(&f.AssetID, &f.Title, &f.ImageURL, &f.Price, &f.IsSold, &f.FavoritedAt); err != nil {
+			return nil, 0, err
+		}
+		list = append(list, f)
+	}
+	return list, total, rows.Err()
+}
+
+func (r *postgresFavoriteRepository) Watchers(ctx context.Context, assetID int64) (string, []string, error) {
+	query := `SELECT a.title, f.user_uuid
+			  FROM asset_favorites f
+			  JOIN assets a ON a.id = f.asset_id
+			  WHERE f.asset_id = $1 AND f.user_uuid <> a.user_uuid`
+	rows, err := r.pool.Query(ctx, query, assetID)
+	if err != nil {
+		return "", nil, err
+	}
+	defer rows.Close()
+
+	var title string
+	var uuids []string
+	for rows.Next() {
+		var uuid string
+		if err := rows.Scan(&title, &uuid); err != nil {
+			return "", nil, err
+		}
+		uuids = append(uuids, uuid)
+	}
+	return title, uuids, rows.Err()
+}
+
+func (r *postgresFavoriteRepository) Share(ctx context.Context, userUUID, token string) (string, time.Time, error) {
+	query := `INSERT INTO favorite_shares (user_uuid, token)
+			  SELECT uuid, $2 FROM users WHERE uuid = $1 AND is_deleted = false
+			  ON CONFLICT (user_uuid) DO UPDATE SET user_uuid = EXCLUDED.user_uuid
+			  RETURNING token, created_at`
+	var createdAt time.Time
+	err := r.pool.QueryRow(ctx, query, userUUID, token).Scan(&token, &createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", time.Time{}, ErrUserNotFound
+	}
+	return token, createdAt, err
+}
+
+func (r *postgresFavoriteRepository) Unshare(ctx context.Context, userUUID string) error {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM favorite_shares WHERE user_uuid = $1`, userUUID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrShareNotFound
+	}
+	return nil
+}
+
+// SharedBy treats a link whose owner has since been deleted as revoked.
+func (r *postgresFavoriteRepository) SharedBy(ctx context.Context, token string) (string, error) {
+	query := `SELECT s.user_uuid FROM favorite_shares s
+			  JOIN users u ON u.uuid = s.user_uuid AND u.is_deleted = false
+			  WHERE s.token = $1`
+	var userUUID string
+	err := r.pool.QueryRow(ctx, query, token).Scan(&userUUID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrShareNotFound
+	}
+	return userUUID, err
+}
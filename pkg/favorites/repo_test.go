@@ -0,0 +1,80 @@
+package favorites
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresFavoriteRepository(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresFavoriteRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool)
+	buyer := testhelpers.NewUser(t, pool)
+	asset := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithPrice(250))
+	deleted := testhelpers.NewAsset(t, pool, testhelpers.WithAssetDeleted())
+
+	added, err := repo.Add(ctx, buyer.UUID, asset.ID)
+	require.NoError(t, err)
+	require.True(t, added)
+	added, err = repo.Add(ctx, buyer.UUID, asset.ID)
+	require.NoError(t, err)
+	require.False(t, added)
+	_, err = repo.Add(ctx, seller.UUID, asset.ID)
+	require.NoError(t, err)
+	_, err = repo.Add(ctx, buyer.UUID, deleted.ID)
+	require.NoError(t, err)
+
+	list, total, err := repo.ListByUser(ctx, buyer.UUID, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, asset.ID, list[0].AssetID)
+	require.Equal(t, 250.0, list[0].Price)
+
+	// The seller is never notified about their own asset
+	title, watchers, err := repo.Watchers(ctx, asset.ID)
+	require.NoError(t, err)
+	require.Equal(t, asset.Title, title)
+	require.Equal(t, []string{buyer.UUID}, watchers)
+
+	require.NoError(t, repo.Remove(ctx, buyer.UUID, asset.ID))
+	require.ErrorIs(t, repo.Remove(ctx, buyer.UUID, asset.ID), ErrFavoriteNotFound)
+}
+
+func TestPostgresFavoriteRepository_Share(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresFavoriteRepository(pool)
+	ctx := context.Background()
+	buyer := testhelpers.NewUser(t, pool)
+
+	token, _, err := repo.Share(ctx, buyer.UUID, "first-"+buyer.UUID)
+	require.NoError(t, err)
+	require.Equal(t, "first-"+buyer.UUID, token)
+
+	// Sharing again keeps the live link
+	token, _, err = repo.Share(ctx, buyer.UUID, "second-"+buyer.UUID)
+	require.NoError(t, err)
+	require.Equal(t, "first-"+buyer.UUID, token)
+
+	owner, err := repo.SharedBy(ctx, token)
+	require.NoError(t, err)
+	require.Equal(t, buyer.UUID, owner)
+
+	require.NoError(t, repo.Unshare(ctx, buyer.UUID))
+	require.ErrorIs(t, repo.Unshare(ctx, buyer.UUID), ErrShareNotFound)
+	_, err = repo.SharedBy(ctx, token)
+	require.ErrorIs(t, err, ErrShareNotFound)
+
+	_, _, err = repo.Share(ctx, "no-such-user", "third")
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
@@ -0,0 +1,116 @@
+package favorites
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"log"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/notifications"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/requestid"
+)
+
+var (
+	ErrAssetNotFound    = apperr.New(apperr.AssetNotFound, "asset not found")
+	ErrFavoriteNotFound = apperr.New(apperr.FavoriteNotFound, "favorite not found")
+	ErrUserNotFound     = apperr.New(apperr.UserNotFound, "user not found")
+	ErrShareNotFound    = apperr.New(apperr.ShareNotFound, "shared list not found")
+)
+
+// Notifier is what the asset and buy services depend on to tell watchers that an
+// asset changed price or sold.
+type Notifier interface {
+	AssetChanged(ctx context.Context, assetID int64)
+}
+
+type Service struct {
+	repo      FavoriteRepository
+	publisher notifications.Publisher // optional; if nil, watchers are not notified
+}
+
+func NewService(repo FavoriteRepository, publisher notifications.Publisher) *Service {
+	return &Service{repo: repo, publisher: publisher}
+}
+
+// Add puts assetID on userUUID's watchlist. Favoriting the same asset twice is not an
+// error; added reports whether a new favorite was stored.
+func (s *Service) Add(ctx context.Context, userUUID string, assetID int64) (bool, error) {
+	ok, err := s.repo.AssetExists(ctx, assetID)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, ErrAssetNotFound
+	}
+	return s.repo.Add(ctx, userUUID, assetID)
+}
+
+func (s *Service) Remove(ctx context.Context, userUUID string, assetID int64) error {
+	return s.repo.Remove(ctx, userUUID, assetID)
+}
+
+// List returns the assets userUUID favorited, most recent first.
+func (s *Service) List(ctx context.Context, userUUID string, p pagination.Params) ([]Favorite, int64, error) {
+	return s.repo.ListByUser(ctx, userUUID, p.Limit, p.Offset())
+}
+
+// Share publishes userUUID's favorites as a read-only list at /lists/:token. Sharing
+// an already published list returns the existing link.
+func (s *Service) Share(ctx context.Context, userUUID string) (Share, error) {
+	token, err := newShareToken()
+	if err != nil {
+		return Share{}, err
+	}
+	token, createdAt, err := s.repo.Share(ctx, userUUID, token)
+	if err != nil {
+		return Share{}, err
+	}
+	return Share{Token: token, Path: "/lists/" + token, CreatedAt: createdAt}, nil
+}
+
+// Unshare revokes userUUID's public link; the token stops working immediately.
+func (s *Service) Unshare(ctx context.Context, userUUID string) error {
+	return s.repo.Unshare(ctx, userUUID)
+}
+
+// ListShared returns the favorites published under token, most recent first.
+func (s *Service) ListShared(ctx context.Context, token string, p pagination.Params) ([]Favorite, int64, error) {
+	userUUID, err := s.repo.SharedBy(ctx, token)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListByUser(ctx, userUUID, p.Limit, p.Offset())
+}
+
+// newShareToken returns 128 random bits, URL-safe, so links cannot be guessed.
+func newShareToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AssetChanged notifies everyone watching assetID, except its seller, with a
+// watched_asset_updated event. It is best effort: the change is already saved, so
+// failures are only logged.
+func (s *Service) AssetChanged(ctx context.Context, assetID int64) {
+	if s.publisher == nil {
+		return
+	}
+	title, watchers, err := s.repo.Watchers(ctx, assetID)
+	if err != nil {
+		log.Printf("[%s] load watchers of asset %d failed: %v", requestid.FromContext(ctx), assetID, err)
+		return
+	}
+	for _, uuid := range watchers {
+		s.publisher.Publish(ctx, notifications.Event{
+			Type:          notifications.EventWatchedAssetUpdated,
+			RecipientUUID: uuid,
+			EntityID:      assetID,
+			Title:         title,
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package favorites
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/notifications"
+	"grveyard/pkg/pagination"
+)
+
+type mockFavoriteRepository struct {
+	mock.Mock
+}
+
+func (m *mockFavoriteRepository) Add(ctx context.Context, userUUID string, assetID int64) (bool, error) {
+	args := m.Called(ctx, userUUID, assetID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockFavoriteRepository) Remove(ctx context.Context, userUUID string, assetID int64) error {
+	args := m.Called(ctx, userUUID, assetID)
+	return args.Error(0)
+}
+
+func (m *mockFavoriteRepository) AssetExists(ctx context.Context, assetID int64) (bool, error) {
+	args := m.Called(ctx, assetID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockFavoriteRepository) ListByUser(ctx context.Context, userUUID string, limit, offset int) ([]Favorite, int64, error) {
+	args := m.Called(ctx, userUUID, limit, offset)
+	list, _ := args.Get(0).([]Favorite)
+	return list, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockFavoriteRepository) Watchers(ctx context.Context, assetID int64) (string, []string, error) {
+	args := m.Called(ctx, assetID)
+	uuids, _ := args.Get(1).([]string)
+	return args.String(0), uuids, args.Error(2)
+}
+
+func (m *mockFavoriteRepository) Share(ctx context.Context, userUUID, token string) (string, time.Time, error) {
+	args := m.Called(ctx, userUUID, token)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *mockFavoriteRepository) Unshare(ctx context.Context, userUUID string) error {
+	args := m.Called(ctx, userUUID)
+	return args.Error(0)
+}
+
+func (m *mockFavoriteRepository) SharedBy(ctx context.Context, token string) (string, error) {
+	args := m.Called(ctx, token)
+	return args.String(0), args.Error(1)
+}
+
+type mockPublisher struct {
+	events []notifications.Event
+}
+
+func (p *mockPublisher) Publish(ctx context.Context, ev notifications.Event) {
+	p.events = append(p.events, ev)
+}
+
+func TestService_Add_UnknownAsset(t *testing.T) {
+	repo := new(mockFavoriteRepository)
+	repo.On("AssetExists", mock.Anything, int64(9)).Return(false, nil)
+
+	_, err := NewService(repo, nil).Add(context.Background(), "u1", 9)
+
+	require.ErrorIs(t, err, ErrAssetNotFound)
+	repo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_AssetChanged_NotifiesWatchers(t *testing.T) {
+	repo := new(mockFavoriteRepository)
+	repo.On("Watchers", mock.Anything, int64(4)).Return("Old Domain", []string{"u1", "u2"}, nil)
+	pub := &mockPublisher{}
+
+	NewService(repo, pub).AssetChanged(context.Background(), 4)
+
+	require.Len(t, pub.events, 2)
+	require.Equal(t, notifications.EventWatchedAssetUpdated, pub.events[0].Type)
+	require.Equal(t, "u2", pub.events[1].RecipientUUID)
+	require.Equal(t, int64(4), pub.events[1].EntityID)
+	require.Equal(t, "Old Domain", pub.events[1].Title)
+}
+
+func TestService_Share(t *testing.T) {
+	repo := new(mockFavoriteRepository)
+	now := time.Now()
+	repo.On("Share", mock.Anything, "u1", mock.AnythingOfType("string")).Return("tok", now, nil)
+
+	share, err := NewService(repo, nil).Share(context.Background(), "u1")
+
+	require.NoError(t, err)
+	require.Equal(t, Share{Token: "tok", Path: "/lists/tok", CreatedAt: now}, share)
+	token := repo.Calls[0].Arguments.String(2)
+	require.Len(t, token, 22)
+}
+
+func TestService_ListShared(t *testing.T) {
+	repo := new(mockFavoriteRepository)
+	repo.On("SharedBy", mock.Anything, "tok").Return("u1", nil)
+	repo.On("ListByUser", mock.Anything, "u1", 20, 0).Return([]Favorite{{AssetID: 3}}, int64(1), nil)
+
+	list, total, err := NewService(repo, nil).ListShared(context.Background(), "tok", pagination.Params{Page: 1, Limit: 20})
+
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, int64(3), list[0].AssetID)
+}
+
+func TestService_ListShared_Revoked(t *testing.T) {
+	repo := new(mockFavoriteRepository)
+	repo.On("SharedBy", mock.Anything, "gone").Return("", ErrShareNotFound)
+
+	_, _, err := NewService(repo, nil).ListShared(context.Background(), "gone", pagination.Params{Page: 1, Limit: 20})
+
+	require.ErrorIs(t, err, ErrShareNotFound)
+	repo.AssertNotCalled(t, "ListByUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
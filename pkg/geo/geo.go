@@ -0,0 +1,42 @@
+// Package geo validates the ISO 3166 country and region codes users and listings
+// carry. Transferring some assets (domains, legal entities) depends on jurisdiction,
+// so buyers filter listings by them.
+package geo
+
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"grveyard/pkg/apperr"
+)
+
+var (
+	ErrInvalidCountry = apperr.New(apperr.InvalidCountry, "country must be an ISO 3166-1 alpha-2 code")
+	ErrInvalidRegion  = apperr.New(apperr.InvalidRegion, "region must be an ISO 3166-2 code in country")
+)
+
+var codes = validator.New()
+
+// Normalize upper-cases country (e.g. "IN") and region (e.g. "IN-MH") and checks them
+// against ISO 3166. Either may be empty; a region on its own implies its country.
+func Normalize(country, region string) (string, string, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	region = strings.ToUpper(strings.TrimSpace(region))
+
+	if region != "" {
+		if codes.Var(region, "iso3166_2") != nil {
+			return "", "", ErrInvalidRegion
+		}
+		prefix, _, _ := strings.Cut(region, "-")
+		if country == "" {
+			country = prefix
+		} else if country != prefix {
+			return "", "", ErrInvalidRegion
+		}
+	}
+	if country != "" && codes.Var(country, "iso3166_1_alpha2") != nil {
+		return "", "", ErrInvalidCountry
+	}
+	return country, region, nil
+}
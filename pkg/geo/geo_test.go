@@ -0,0 +1,30 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		country, region         string
+		wantCountry, wantRegion string
+		wantErr                 error
+	}{
+		{"", "", "", "", nil},
+		{"in", "", "IN", "", nil},
+		{" US ", "us-ca", "US", "US-CA", nil},
+		{"", "IN-MH", "IN", "IN-MH", nil},
+		{"XX", "", "", "", ErrInvalidCountry},
+		{"IND", "", "", "", ErrInvalidCountry},
+		{"", "IN-ZZ", "", "", ErrInvalidRegion},
+		{"US", "IN-MH", "", "", ErrInvalidRegion},
+	}
+	for _, tc := range cases {
+		country, region, err := Normalize(tc.country, tc.region)
+		require.ErrorIs(t, err, tc.wantErr, "%q %q", tc.country, tc.region)
+		require.Equal(t, tc.wantCountry, country)
+		require.Equal(t, tc.wantRegion, region)
+	}
+}
@@ -0,0 +1,272 @@
+// Package github links private GitHub repositories to codebase assets through a
+// GitHub App installed by the seller. The app reads repository stats for display and,
+// once the asset sells, invites the buyer as a collaborator. It talks to the REST API
+// directly rather than through an SDK.
+// https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apiURL     = "https://api.github.com"
+	apiVersion = "2022-11-28"
+	// CollaboratorPermission is what buyers are granted on a purchased repository.
+	CollaboratorPermission = "push"
+)
+
+var (
+	// errNotFound is GitHub's answer when the installation cannot see a repository.
+	errNotFound = errors.New("github: not found")
+	// errComputing means GitHub is still computing repository statistics.
+	errComputing = errors.New("github: statistics are being computed")
+	// errEmptyRepo is GitHub's 409 for repositories without commits.
+	errEmptyRepo = errors.New("github: repository is empty")
+)
+
+// App authenticates as a GitHub App and acts on its installations.
+type App struct {
+	id      string
+	key     *rsa.PrivateKey
+	baseURL string
+	client  *http.Client
+	now     func() time.Time
+
+	mu     sync.Mutex
+	tokens map[int64]installationToken
+}
+
+type installationToken struct {
+	token   string
+	expires time.Time
+}
+
+// NewApp returns an App for appID signing with privateKeyPEM, the PKCS#1 or PKCS#8
+// key downloaded from the app's settings. Escaped "\n" sequences are accepted so the
+// key fits in a single environment variable.
+func NewApp(appID, privateKeyPEM string) (*App, error) {
+	if appID == "" {
+		return nil, errors.New("github app id is required")
+	}
+	block, _ := pem.Decode([]byte(strings.ReplaceAll(privateKeyPEM, `\n`, "\n")))
+	if block == nil {
+		return nil, errors.New("github app private key is not PEM encoded")
+	}
+	key, err := parseKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("github app private key: %w", err)
+	}
+	return &App{
+		id:      appID,
+		key:     key,
+		baseURL: apiURL,
+		client:  &http.Client{Timeout: 15 * time.Second},
+		now:     time.Now,
+		tokens:  map[int64]installationToken{},
+	}, nil
+}
+
+func parseKey(der []byte) (*rsa.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA key")
+	}
+	return key, nil
+}
+
+// jwt is the short-lived RS256 token that authenticates as the app itself. iat is
+// backdated to allow for clock drift, as GitHub recommends.
+func (a *App) jwt() (string, error) {
+	now := a.now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": a.id,
+	})
+	if err != nil {
+		return "", err
+	}
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// token returns an installation access token, reusing it until a minute before it
+// expires.
+func (a *App) token(ctx context.Context, installationID int64) (string, error) {
+	a.mu.Lock()
+	cached, ok := a.tokens[installationID]
+	a.mu.Unlock()
+	if ok && a.now().Before(cached.expires.Add(-time.Minute)) {
+		return cached.token, nil
+	}
+
+	jwt, err := a.jwt()
+	if err != nil {
+		return "", err
+	}
+	var out struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	path := "/app/installations/" + strconv.FormatInt(installationID, 10) + "/access_tokens"
+	if err := a.do(ctx, http.MethodPost, path, "Bearer "+jwt, nil, &out); err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	a.tokens[installationID] = installationToken{token: out.Token, expires: out.ExpiresAt}
+	a.mu.Unlock()
+	return out.Token, nil
+}
+
+// Stats reads a repository's stats as installationID. Lines of code are the net
+// additions in GitHub's weekly code frequency, left nil while GitHub is still
+// computing them for a repository it has not seen recently.
+func (a *App) Stats(ctx context.Context, installationID int64, repo string) (Stats, error) {
+	token, err := a.token(ctx, installationID)
+	if err != nil {
+		return Stats{}, err
+	}
+	auth := "token " + token
+	base := "/repos/" + repo
+
+	var info struct {
+		FullName      string `json:"full_name"`
+		Private       bool   `json:"private"`
+		Stars         int    `json:"stargazers_count"`
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := a.do(ctx, http.MethodGet, base, auth, nil, &info); err != nil {
+		return Stats{}, err
+	}
+	s := Stats{Repo: info.FullName, Private: info.Private, Stars: info.Stars, Languages: map[string]int64{}}
+
+	if err := a.do(ctx, http.MethodGet, base+"/languages", auth, nil, &s.Languages); err != nil {
+		return Stats{}, err
+	}
+
+	var commits []struct {
+		Commit struct {
+			Committer struct {
+				Date time.Time `json:"date"`
+			} `json:"committer"`
+		} `json:"commit"`
+	}
+	query := "?per_page=1&sha=" + url.QueryEscape(info.DefaultBranch)
+	if err := a.do(ctx, http.MethodGet, base+"/commits"+query, auth, nil, &commits); err != nil && !errors.Is(err, errEmptyRepo) {
+		return Stats{}, err
+	}
+	if len(commits) > 0 {
+		at := commits[0].Commit.Committer.Date
+		s.LastCommitAt = &at
+	}
+
+	var weeks [][3]int64 // [timestamp, additions, deletions]
+	switch err := a.do(ctx, http.MethodGet, base+"/stats/code_frequency", auth, nil, &weeks); {
+	case err == nil:
+		var loc int64
+		for _, w := range weeks {
+			loc += w[1] + w[2] // deletions are negative
+		}
+		s.LinesOfCode = &loc
+	case errors.Is(err, errComputing), errors.Is(err, errEmptyRepo):
+	default:
+		return Stats{}, err
+	}
+	return s, nil
+}
+
+// AddCollaborator invites username to repo with CollaboratorPermission. GitHub
+// emails the invitation; re-inviting an existing collaborator is a no-op.
+func (a *App) AddCollaborator(ctx context.Context, installationID int64, repo, username string) error {
+	token, err := a.token(ctx, installationID)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]string{"permission": CollaboratorPermission})
+	if err != nil {
+		return err
+	}
+	path := "/repos/" + repo + "/collaborators/" + url.PathEscape(username)
+	return a.do(ctx, http.MethodPut, path, "token "+token, body, nil)
+}
+
+func (a *App) do(ctx context.Context, method, path, auth string, body []byte, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	req.Header.Set("Authorization", auth)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusAccepted && strings.Contains(path, "/stats/"):
+		return errComputing
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusForbidden && method == http.MethodGet:
+		return errNotFound
+	case resp.StatusCode == http.StatusConflict:
+		return errEmptyRepo
+	case resp.StatusCode >= 300:
+		var e struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &e)
+		return fmt.Errorf("github: %s %s: %d %s", method, path, resp.StatusCode, e.Message)
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("github: decode %s: %w", path, err)
+		}
+	}
+	return nil
+}
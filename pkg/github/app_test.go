@@ -0,0 +1,157 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testApp(t *testing.T, handler http.Handler) (*App, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	// The escaped form is how the key usually arrives through the environment
+	app, err := NewApp("12345", strings.ReplaceAll(string(keyPEM), "\n", `\n`))
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	app.baseURL = srv.URL
+	return app, key
+}
+
+func TestNewApp_InvalidKey(t *testing.T) {
+	_, err := NewApp("1", "not a key")
+	require.Error(t, err)
+	_, err = NewApp("", "")
+	require.Error(t, err)
+}
+
+func TestApp_JWT(t *testing.T) {
+	app, key := testApp(t, http.NotFoundHandler())
+	now := time.Unix(1_700_000_000, 0)
+	app.now = func() time.Time { return now }
+
+	token, err := app.jwt()
+	require.NoError(t, err)
+
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig))
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims struct {
+		Iat int64  `json:"iat"`
+		Exp int64  `json:"exp"`
+		Iss string `json:"iss"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &claims))
+	require.Equal(t, "12345", claims.Iss)
+	require.Equal(t, now.Unix()-60, claims.Iat)
+	require.Equal(t, now.Unix()+540, claims.Exp)
+}
+
+func TestApp_Stats(t *testing.T) {
+	tokenRequests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/7/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		require.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"token":"inst-token","expires_at":"` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}`))
+	})
+	mux.HandleFunc("GET /repos/acme/app", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "token inst-token", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"full_name":"acme/app","private":true,"stargazers_count":42,"default_branch":"main"}`))
+	})
+	mux.HandleFunc("GET /repos/acme/app/languages", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Go":12000,"HTML":300}`))
+	})
+	mux.HandleFunc("GET /repos/acme/app/commits", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "main", r.URL.Query().Get("sha"))
+		w.Write([]byte(`[{"commit":{"committer":{"date":"2026-09-01T10:00:00Z"}}}]`))
+	})
+	mux.HandleFunc("GET /repos/acme/app/stats/code_frequency", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[[1700000000,1500,-200],[1700604800,300,-100]]`))
+	})
+	app, _ := testApp(t, mux)
+
+	stats, err := app.Stats(context.Background(), 7, "acme/app")
+	require.NoError(t, err)
+	require.Equal(t, "acme/app", stats.Repo)
+	require.True(t, stats.Private)
+	require.Equal(t, 42, stats.Stars)
+	require.Equal(t, map[string]int64{"Go": 12000, "HTML": 300}, stats.Languages)
+	require.Equal(t, time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC), *stats.LastCommitAt)
+	require.Equal(t, int64(1500), *stats.LinesOfCode)
+
+	// The installation token is reused
+	_, err = app.Stats(context.Background(), 7, "acme/app")
+	require.NoError(t, err)
+	require.Equal(t, 1, tokenRequests)
+}
+
+func TestApp_Stats_ComputingAndInaccessible(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/7/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"token":"t","expires_at":"` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}`))
+	})
+	mux.HandleFunc("GET /repos/acme/app", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"full_name":"acme/app","default_branch":"main"}`))
+	})
+	mux.HandleFunc("GET /repos/acme/app/languages", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("GET /repos/acme/app/commits", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	mux.HandleFunc("GET /repos/acme/app/stats/code_frequency", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	app, _ := testApp(t, mux)
+
+	stats, err := app.Stats(context.Background(), 7, "acme/app")
+	require.NoError(t, err)
+	require.Nil(t, stats.LastCommitAt)
+	require.Nil(t, stats.LinesOfCode)
+
+	_, err = app.Stats(context.Background(), 7, "acme/secret")
+	require.ErrorIs(t, err, errNotFound)
+}
+
+func TestApp_AddCollaborator(t *testing.T) {
+	var body string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/7/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"token":"t","expires_at":"` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}`))
+	})
+	mux.HandleFunc("PUT /repos/acme/app/collaborators/new-owner", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusCreated)
+	})
+	app, _ := testApp(t, mux)
+
+	require.NoError(t, app.AddCollaborator(context.Background(), 7, "acme/app", "new-owner"))
+	require.JSONEq(t, `{"permission":"push"}`, body)
+}
@@ -0,0 +1,124 @@
+package github
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+type GitHubHandler struct {
+	service *Service
+}
+
+func NewGitHubHandler(service *Service) *GitHubHandler {
+	return &GitHubHandler{service: service}
+}
+
+func (h *GitHubHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/assets/:id/github", auth.Required(), h.link)
+	router.GET("/assets/:id/github", h.stats)
+	router.DELETE("/assets/:id/github", auth.Required(), h.unlink)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *GitHubHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/assets/:id/github",
+			Tag:         "github",
+			Summary:     "Link a GitHub repository",
+			Description: "Connects a repository to a codebase asset after the seller installs the marketplace's GitHub App on it. installation_id is the value GitHub passes to the app's setup URL. The repository is read immediately to check the installation can see it. When the asset is marked sold with a buyer github_username, the buyer is invited as a collaborator.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Request:  LinkRequest{},
+			Response: Link{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/assets/:id/github",
+			Tag:         "github",
+			Summary:     "Linked repository stats",
+			Description: "Stars, languages, last commit and lines of code of the linked repository, refreshed at most hourly. lines_of_code is omitted while GitHub is still computing it.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Response: Stats{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable},
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/assets/:id/github",
+			Tag:     "github",
+			Summary: "Unlink the GitHub repository",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+	}
+}
+
+func assetID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid asset id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *GitHubHandler) link(c *gin.Context) {
+	id, ok := assetID(c)
+	if !ok {
+		return
+	}
+	var req LinkRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	link, err := h.service.Link(c.Request.Context(), id, auth.UserID(c), req)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "repository linked", link)
+}
+
+func (h *GitHubHandler) stats(c *gin.Context) {
+	id, ok := assetID(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.service.Stats(c.Request.Context(), id)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "repository stats fetched", stats)
+}
+
+func (h *GitHubHandler) unlink(c *gin.Context) {
+	id, ok := assetID(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Unlink(c.Request.Context(), id, auth.UserID(c)); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "repository unlinked", nil)
+}
@@ -0,0 +1,30 @@
+package github
+
+import "time"
+
+// Stats are read-only details of a linked repository shown on the listing.
+type Stats struct {
+	Repo         string           `json:"repo"` // owner/name
+	Private      bool             `json:"private"`
+	Stars        int              `json:"stars"`
+	Languages    map[string]int64 `json:"languages"` // bytes of code per language
+	LastCommitAt *time.Time       `json:"last_commit_at,omitempty"`
+	LinesOfCode  *int64           `json:"lines_of_code,omitempty"` // absent while GitHub computes it
+	FetchedAt    time.Time        `json:"fetched_at"`
+}
+
+// Link is the repository connected to a codebase asset.
+type Link struct {
+	AssetID        int64     `json:"asset_id"`
+	InstallationID int64     `json:"installation_id"`
+	Repo           string    `json:"repo"`
+	Stats          *Stats    `json:"stats,omitempty"`
+	LinkedAt       time.Time `json:"linked_at"`
+}
+
+// LinkRequest connects a repository the seller's app installation can read. The
+// installation_id comes from GitHub's redirect to the app's setup URL.
+type LinkRequest struct {
+	InstallationID int64  `json:"installation_id" binding:"required,gt=0"`
+	Repo           string `json:"repo" binding:"required,max=200"`
+}
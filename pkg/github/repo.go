@@ -0,0 +1,103 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LinkRepository stores which repository each codebase asset is linked to.
+type LinkRepository interface {
+	// AssetOwner returns the owner and type of an asset that is not deleted.
+	AssetOwner(ctx context.Context, assetID int64) (ownerUUID, assetType string, err error)
+	// Save links the asset to a repository, replacing any earlier link.
+	Save(ctx context.Context, link Link) (Link, error)
+	Get(ctx context.Context, assetID int64) (Link, error)
+	SaveStats(ctx context.Context, assetID int64, stats Stats) error
+	Delete(ctx context.Context, assetID int64) error
+}
+
+type postgresLinkRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresLinkRepository(pool *pgxpool.Pool) LinkRepository {
+	return &postgresLinkRepository{pool: pool}
+}
+
+func (r *postgresLinkRepository) AssetOwner(ctx context.Context, assetID int64) (string, string, error) {
+	var owner, assetType string
+	err := r.pool.QueryRow(ctx, `SELECT user_uuid, asset_type FROM assets WHERE id = $1 AND is_deleted = false`, assetID).Scan(&owner, &assetType)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", "", ErrAssetNotFound
+	}
+	return owner, assetType, err
+}
+
+func (r *postgresLinkRepository) Save(ctx context.Context, link Link) (Link, error) {
+	stats, err := json.Marshal(link.Stats)
+	if err != nil {
+		return Link{}, err
+	}
+	var fetchedAt *time.Time
+	if link.Stats != nil {
+		fetchedAt = &link.Stats.FetchedAt
+	}
+
+	query := `INSERT INTO asset_repositories (asset_id, installation_id, repo_full_name, stats, stats_fetched_at)
+			  VALUES ($1, $2, $3, $4, $5)
+			  ON CONFLICT (asset_id) DO UPDATE SET
+				installation_id = EXCLUDED.installation_id,
+				repo_full_name = EXCLUDED.repo_full_name,
+				stats = EXCLUDED.stats,
+				stats_fetched_at = EXCLUDED.stats_fetched_at,
+				created_at = NOW()
+			  RETURNING created_at`
+	err = r.pool.QueryRow(ctx, query, link.AssetID, link.InstallationID, link.Repo, stats, fetchedAt).Scan(&link.LinkedAt)
+	return link, err
+}
+
+func (r *postgresLinkRepository) Get(ctx context.Context, assetID int64) (Link, error) {
+	query := `SELECT asset_id, installation_id, repo_full_name, stats, created_at
+			  FROM asset_repositories WHERE asset_id = $1`
+	var link Link
+	var stats []byte
+	err := r.pool.QueryRow(ctx, query, assetID).Scan(&link.AssetID, &link.InstallationID, &link.Repo, &stats, &link.LinkedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Link{}, ErrNotLinked
+	}
+	if err != nil {
+		return Link{}, err
+	}
+	if len(stats) > 0 && string(stats) != "null" {
+		link.Stats = &Stats{}
+		if err := json.Unmarshal(stats, link.Stats); err != nil {
+			return Link{}, err
+		}
+	}
+	return link, nil
+}
+
+func (r *postgresLinkRepository) SaveStats(ctx context.Context, assetID int64, stats Stats) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx, `UPDATE asset_repositories SET stats = $2, stats_fetched_at = $3 WHERE asset_id = $1`, assetID, data, stats.FetchedAt)
+	return err
+}
+
+func (r *postgresLinkRepository) Delete(ctx context.Context, assetID int64) error {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM asset_repositories WHERE asset_id = $1`, assetID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrNotLinked
+	}
+	return nil
+}
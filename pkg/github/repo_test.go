@@ -0,0 +1,52 @@
+package github
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresLinkRepository(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresLinkRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	asset := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID))
+
+	owner, _, err := repo.AssetOwner(ctx, asset.ID)
+	require.NoError(t, err)
+	require.Equal(t, seller.UUID, owner)
+	_, _, err = repo.AssetOwner(ctx, 999999)
+	require.ErrorIs(t, err, ErrAssetNotFound)
+
+	_, err = repo.Get(ctx, asset.ID)
+	require.ErrorIs(t, err, ErrNotLinked)
+
+	fetched := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	loc := int64(1200)
+	_, err = repo.Save(ctx, Link{AssetID: asset.ID, InstallationID: 7, Repo: "acme/app",
+		Stats: &Stats{Repo: "acme/app", Stars: 3, Languages: map[string]int64{"Go": 10}, LinesOfCode: &loc, FetchedAt: fetched}})
+	require.NoError(t, err)
+
+	link, err := repo.Get(ctx, asset.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), link.InstallationID)
+	require.Equal(t, 3, link.Stats.Stars)
+	require.Equal(t, int64(1200), *link.Stats.LinesOfCode)
+
+	require.NoError(t, repo.SaveStats(ctx, asset.ID, Stats{Repo: "acme/app", Stars: 4, FetchedAt: fetched.Add(time.Hour)}))
+	link, err = repo.Get(ctx, asset.ID)
+	require.NoError(t, err)
+	require.Equal(t, 4, link.Stats.Stars)
+
+	require.NoError(t, repo.Delete(ctx, asset.ID))
+	require.ErrorIs(t, repo.Delete(ctx, asset.ID), ErrNotLinked)
+}
@@ -0,0 +1,141 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"time"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/requestid"
+)
+
+// statsTTL is how long repository stats are shown before they are read again.
+const statsTTL = time.Hour
+
+var (
+	ErrAssetNotFound    = apperr.New(apperr.AssetNotFound, "asset not found")
+	ErrNotLinked        = apperr.New(apperr.RepoNotLinked, "no repository linked")
+	ErrNotCodebase      = apperr.New(apperr.InvalidAssetType, "only codebase assets can link a repository")
+	ErrNotOwner         = apperr.New(apperr.Forbidden, "only the asset owner can change its repository")
+	ErrInvalidRepo      = apperr.New(apperr.InvalidRequest, "repo must be owner/name")
+	ErrInvalidUsername  = apperr.New(apperr.InvalidRequest, "invalid github username")
+	ErrRepoInaccessible = apperr.New(apperr.RepoInaccessible, "the app installation cannot access that repository")
+	ErrUnavailable      = apperr.New(apperr.ServiceUnavailable, "github unavailable")
+)
+
+var (
+	repoPattern     = regexp.MustCompile(`^[A-Za-z0-9-]{1,39}/[A-Za-z0-9._-]{1,100}$`)
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]{0,38})$`)
+)
+
+// Client is the part of App the service uses.
+type Client interface {
+	Stats(ctx context.Context, installationID int64, repo string) (Stats, error)
+	AddCollaborator(ctx context.Context, installationID int64, repo, username string) error
+}
+
+type Service struct {
+	repo   LinkRepository
+	client Client
+	now    func() time.Time
+}
+
+func NewService(repo LinkRepository, client Client) *Service {
+	return &Service{repo: repo, client: client, now: time.Now}
+}
+
+// Link connects a codebase asset owned by userUUID to a repository. The stats are
+// read straight away, which also proves the installation can see the repository.
+func (s *Service) Link(ctx context.Context, assetID int64, userUUID string, req LinkRequest) (Link, error) {
+	if !repoPattern.MatchString(req.Repo) {
+		return Link{}, ErrInvalidRepo
+	}
+	if err := s.checkOwner(ctx, assetID, userUUID); err != nil {
+		return Link{}, err
+	}
+
+	stats, err := s.fetch(ctx, req.InstallationID, req.Repo)
+	if err != nil {
+		return Link{}, err
+	}
+	return s.repo.Save(ctx, Link{AssetID: assetID, InstallationID: req.InstallationID, Repo: stats.Repo, Stats: &stats})
+}
+
+// Unlink disconnects the repository; the app installation itself is left to the
+// seller to remove on GitHub.
+func (s *Service) Unlink(ctx context.Context, assetID int64, userUUID string) error {
+	if err := s.checkOwner(ctx, assetID, userUUID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, assetID)
+}
+
+// Stats returns the linked repository's stats, reading them again once they are
+// older than statsTTL. If GitHub cannot be reached the last stats are served.
+func (s *Service) Stats(ctx context.Context, assetID int64) (Stats, error) {
+	link, err := s.repo.Get(ctx, assetID)
+	if err != nil {
+		return Stats{}, err
+	}
+	if link.Stats != nil && s.now().Sub(link.Stats.FetchedAt) < statsTTL {
+		return *link.Stats, nil
+	}
+
+	stats, err := s.fetch(ctx, link.InstallationID, link.Repo)
+	if err != nil {
+		if link.Stats != nil && errors.Is(err, ErrUnavailable) {
+			log.Printf("[%s] refresh stats of %s failed, serving stats from %s", requestid.FromContext(ctx), link.Repo, link.Stats.FetchedAt.Format(time.RFC3339))
+			return *link.Stats, nil
+		}
+		return Stats{}, err
+	}
+	if err := s.repo.SaveStats(ctx, assetID, stats); err != nil {
+		log.Printf("[%s] save stats of %s failed: %v", requestid.FromContext(ctx), link.Repo, err)
+	}
+	return stats, nil
+}
+
+// GrantBuyer invites the buyer's GitHub account to the repository linked to assetID.
+// Assets without a linked repository are skipped.
+func (s *Service) GrantBuyer(ctx context.Context, assetID int64, githubUsername string) error {
+	if !usernamePattern.MatchString(githubUsername) {
+		return ErrInvalidUsername
+	}
+	link, err := s.repo.Get(ctx, assetID)
+	if errors.Is(err, ErrNotLinked) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.client.AddCollaborator(ctx, link.InstallationID, link.Repo, githubUsername)
+}
+
+func (s *Service) checkOwner(ctx context.Context, assetID int64, userUUID string) error {
+	owner, assetType, err := s.repo.AssetOwner(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if owner != userUUID {
+		return ErrNotOwner
+	}
+	if assetType != "codebase" {
+		return ErrNotCodebase
+	}
+	return nil
+}
+
+func (s *Service) fetch(ctx context.Context, installationID int64, repo string) (Stats, error) {
+	stats, err := s.client.Stats(ctx, installationID, repo)
+	if errors.Is(err, errNotFound) {
+		return Stats{}, ErrRepoInaccessible
+	}
+	if err != nil {
+		log.Printf("[%s] read github repo %s failed: %v", requestid.FromContext(ctx), repo, err)
+		return Stats{}, ErrUnavailable
+	}
+	stats.FetchedAt = s.now().UTC()
+	return stats, nil
+}
@@ -0,0 +1,157 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLinkRepository struct {
+	mock.Mock
+}
+
+func (m *mockLinkRepository) AssetOwner(ctx context.Context, assetID int64) (string, string, error) {
+	args := m.Called(ctx, assetID)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *mockLinkRepository) Save(ctx context.Context, link Link) (Link, error) {
+	args := m.Called(ctx, link)
+	return args.Get(0).(Link), args.Error(1)
+}
+
+func (m *mockLinkRepository) Get(ctx context.Context, assetID int64) (Link, error) {
+	args := m.Called(ctx, assetID)
+	return args.Get(0).(Link), args.Error(1)
+}
+
+func (m *mockLinkRepository) SaveStats(ctx context.Context, assetID int64, stats Stats) error {
+	args := m.Called(ctx, assetID, stats)
+	return args.Error(0)
+}
+
+func (m *mockLinkRepository) Delete(ctx context.Context, assetID int64) error {
+	args := m.Called(ctx, assetID)
+	return args.Error(0)
+}
+
+type mockClient struct {
+	mock.Mock
+}
+
+func (m *mockClient) Stats(ctx context.Context, installationID int64, repo string) (Stats, error) {
+	args := m.Called(ctx, installationID, repo)
+	return args.Get(0).(Stats), args.Error(1)
+}
+
+func (m *mockClient) AddCollaborator(ctx context.Context, installationID int64, repo, username string) error {
+	args := m.Called(ctx, installationID, repo, username)
+	return args.Error(0)
+}
+
+func newTestService(repo LinkRepository, client Client, now time.Time) *Service {
+	s := NewService(repo, client)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestService_Link(t *testing.T) {
+	repo, client := new(mockLinkRepository), new(mockClient)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestService(repo, client, now)
+
+	repo.On("AssetOwner", mock.Anything, int64(3)).Return("seller", "codebase", nil)
+	client.On("Stats", mock.Anything, int64(7), "acme/app").Return(Stats{Repo: "acme/app", Stars: 5}, nil)
+	want := Stats{Repo: "acme/app", Stars: 5, FetchedAt: now}
+	repo.On("Save", mock.Anything, Link{AssetID: 3, InstallationID: 7, Repo: "acme/app", Stats: &want}).
+		Return(Link{AssetID: 3, InstallationID: 7, Repo: "acme/app", Stats: &want, LinkedAt: now}, nil)
+
+	link, err := s.Link(context.Background(), 3, "seller", LinkRequest{InstallationID: 7, Repo: "acme/app"})
+
+	require.NoError(t, err)
+	require.Equal(t, 5, link.Stats.Stars)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Link_Rejected(t *testing.T) {
+	cases := []struct {
+		name      string
+		owner     string
+		assetType string
+		repo      string
+		statsErr  error
+		want      error
+	}{
+		{"not owner", "someone-else", "codebase", "acme/app", nil, ErrNotOwner},
+		{"not codebase", "seller", "domain", "acme/app", nil, ErrNotCodebase},
+		{"bad repo", "seller", "codebase", "https://github.com/acme/app", nil, ErrInvalidRepo},
+		{"inaccessible", "seller", "codebase", "acme/app", errNotFound, ErrRepoInaccessible},
+		{"github down", "seller", "codebase", "acme/app", errors.New("timeout"), ErrUnavailable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, client := new(mockLinkRepository), new(mockClient)
+			repo.On("AssetOwner", mock.Anything, int64(3)).Return(tc.owner, tc.assetType, nil)
+			client.On("Stats", mock.Anything, int64(7), "acme/app").Return(Stats{}, tc.statsErr)
+
+			_, err := NewService(repo, client).Link(context.Background(), 3, "seller", LinkRequest{InstallationID: 7, Repo: tc.repo})
+
+			require.ErrorIs(t, err, tc.want)
+			repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_Stats_CachedAndStale(t *testing.T) {
+	repo, client := new(mockLinkRepository), new(mockClient)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestService(repo, client, now)
+
+	fresh := &Stats{Repo: "acme/app", Stars: 1, FetchedAt: now.Add(-time.Minute)}
+	repo.On("Get", mock.Anything, int64(3)).Return(Link{AssetID: 3, InstallationID: 7, Repo: "acme/app", Stats: fresh}, nil).Once()
+	stats, err := s.Stats(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.Stars)
+	client.AssertNotCalled(t, "Stats", mock.Anything, mock.Anything, mock.Anything)
+
+	// Expired stats are served as they are while GitHub is unreachable
+	old := &Stats{Repo: "acme/app", Stars: 1, FetchedAt: now.Add(-2 * time.Hour)}
+	repo.On("Get", mock.Anything, int64(3)).Return(Link{AssetID: 3, InstallationID: 7, Repo: "acme/app", Stats: old}, nil).Once()
+	client.On("Stats", mock.Anything, int64(7), "acme/app").Return(Stats{}, errors.New("timeout")).Once()
+	stats, err = s.Stats(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, *old, stats)
+}
+
+func TestService_Stats_Refreshes(t *testing.T) {
+	repo, client := new(mockLinkRepository), new(mockClient)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestService(repo, client, now)
+
+	repo.On("Get", mock.Anything, int64(3)).Return(Link{AssetID: 3, InstallationID: 7, Repo: "acme/app"}, nil)
+	client.On("Stats", mock.Anything, int64(7), "acme/app").Return(Stats{Repo: "acme/app", Stars: 9}, nil)
+	repo.On("SaveStats", mock.Anything, int64(3), Stats{Repo: "acme/app", Stars: 9, FetchedAt: now}).Return(nil)
+
+	stats, err := s.Stats(context.Background(), 3)
+
+	require.NoError(t, err)
+	require.Equal(t, 9, stats.Stars)
+	repo.AssertExpectations(t)
+}
+
+func TestService_GrantBuyer(t *testing.T) {
+	repo, client := new(mockLinkRepository), new(mockClient)
+	repo.On("Get", mock.Anything, int64(3)).Return(Link{AssetID: 3, InstallationID: 7, Repo: "acme/app"}, nil)
+	repo.On("Get", mock.Anything, int64(4)).Return(Link{}, ErrNotLinked)
+	client.On("AddCollaborator", mock.Anything, int64(7), "acme/app", "buyer-gh").Return(nil)
+	s := NewService(repo, client)
+
+	require.NoError(t, s.GrantBuyer(context.Background(), 3, "buyer-gh"))
+	require.NoError(t, s.GrantBuyer(context.Background(), 4, "buyer-gh"))
+	require.ErrorIs(t, s.GrantBuyer(context.Background(), 3, "../evil"), ErrInvalidUsername)
+	client.AssertNumberOfCalls(t, "AddCollaborator", 1)
+}
@@ -0,0 +1,184 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultLocale is used whenever a user has no locale or asks for an unsupported one.
+const DefaultLocale = "en"
+
+// catalogs maps locale -> message key -> format string (fmt verbs allowed).
+var catalogs = map[string]map[string]string{
+	"en": {
+		"email.otp.subject":                "Your OTP Code",
+		"email.otp.heading":                "Your OTP Code",
+		"email.otp.intro":                  "Your one-time password is:",
+		"email.otp.expiry":                 "This code will expire in %d minutes.",
+		"email.otp.ignore":                 "If you didn't request this code, please ignore this email.",
+		"email.otp.text":                   "Your OTP code is: %s. This code will expire in %d minutes.",
+		"email.password_reset.subject":     "Reset your Graveyard password",
+		"email.password_reset.heading":     "Reset your password",
+		"email.password_reset.intro":       "We received a request to reset the password for your account.",
+		"email.password_reset.cta":         "Choose a new password",
+		"email.password_reset.token":       "Your reset code is:",
+		"email.password_reset.expiry":      "This link will expire in %d minutes.",
+		"email.password_reset.ignore":      "If you didn't request a password reset, you can ignore this email; your password will not change.",
+		"email.password_reset.text":        "To reset your password, use %s. It will expire in %d minutes. If you didn't request this, ignore this email.",
+		"email.digest.subject":             "You have unread messages on Graveyard",
+		"email.digest.heading":             "Unread messages",
+		"email.digest.greeting":            "Hi %s,",
+		"email.digest.waiting":             "You have %s waiting for you.",
+		"email.digest.cta":                 "Log in to reply.",
+		"email.digest.text":                "Hi %s, you have %s. Log in to reply.",
+		"email.digest.summary":             "%d unread %s from %d %s",
+		"email.digest.message":             "message",
+		"email.digest.messages":            "messages",
+		"email.digest.buyer":               "buyer",
+		"email.digest.buyers":              "buyers",
+		"email.digest.seller":              "seller",
+		"email.digest.sellers":             "sellers",
+		"email.offer_received.subject":     "You received a new offer",
+		"email.offer_received.body":        "You received an offer of %.2f on %s.",
+		"email.message_received.subject":   "You have a new message",
+		"email.message_received.body":      "New message: %s",
+		"email.asset_sold.subject":         "Your asset has been sold",
+		"email.asset_sold.body":            "Your asset %s has been marked as sold.",
+		"email.watched_asset_updated.body": "An asset you are watching was updated: %s",
+		"email.report_updated.body":        "Your report is now %s.",
+		"email.bookmark_updated.body":      "%s, a startup you bookmarked, changed status.",
+		"email.purchase_receipt.subject":   "Your Graveyard receipt",
+		"email.purchase_receipt.body":      "Thank you for buying %s. You paid %.2f including tax; your invoice number is INV-%06d.",
+		"email.unsubscribe.link":           "Unsubscribe from these emails",
+		"email.unsubscribe.text":           "To stop receiving these emails, visit %s",
+		"validation.failed":                "validation failed",
+		"validation.invalid_json":          "request body is not valid JSON",
+		"validation.empty_body":            "request body is required",
+		"validation.too_large":             "request body too large",
+		"validation.content_type":          "Content-Type must be application/json",
+		"validation.required":              "%s is required",
+		"validation.email":                 "%s must be a valid email address",
+		"validation.url":                   "%s must be a valid URL",
+		"validation.uuid":                  "%s must be a valid UUID",
+		"validation.min_len":               "%s must be at least %s characters",
+		"validation.max_len":               "%s must be at most %s characters",
+		"validation.min":                   "%s must be at least %s",
+		"validation.max":                   "%s must be at most %s",
+		"validation.gte":                   "%s must be at least %s",
+		"validation.lte":                   "%s must be at most %s",
+		"validation.gt":                    "%s must be greater than %s",
+		"validation.lt":                    "%s must be less than %s",
+		"validation.oneof":                 "%s must be one of: %s",
+		"validation.invalid":               "%s is invalid",
+		"validation.unknown":               "%s is not allowed",
+		"validation.type.string":           "%s must be a string",
+		"validation.type.number":           "%s must be a number",
+		"validation.type.boolean":          "%s must be true or false",
+		"validation.type.value":            "%s has the wrong type",
+	},
+	"hi": {
+		"email.otp.subject":                "आपका OTP कोड",
+		"email.otp.heading":                "आपका OTP कोड",
+		"email.otp.intro":                  "आपका वन-टाइम पासवर्ड है:",
+		"email.otp.expiry":                 "यह कोड %d मिनट में समाप्त हो जाएगा।",
+		"email.otp.ignore":                 "यदि आपने यह कोड नहीं माँगा है, तो कृपया इस ईमेल को अनदेखा करें।",
+		"email.otp.text":                   "आपका OTP कोड है: %s। यह कोड %d मिनट में समाप्त हो जाएगा।",
+		"email.password_reset.subject":     "अपना Graveyard पासवर्ड रीसेट करें",
+		"email.password_reset.heading":     "अपना पासवर्ड रीसेट करें",
+		"email.password_reset.intro":       "हमें आपके खाते का पासवर्ड रीसेट करने का अनुरोध मिला है।",
+		"email.password_reset.cta":         "नया पासवर्ड चुनें",
+		"email.password_reset.token":       "आपका रीसेट कोड है:",
+		"email.password_reset.expiry":      "यह लिंक %d मिनट में समाप्त हो जाएगा।",
+		"email.password_reset.ignore":      "यदि आपने पासवर्ड रीसेट का अनुरोध नहीं किया है, तो इस ईमेल को अनदेखा करें; आपका पासवर्ड नहीं बदलेगा।",
+		"email.password_reset.text":        "अपना पासवर्ड रीसेट करने के लिए %s का उपयोग करें। यह %d मिनट में समाप्त हो जाएगा। यदि आपने यह अनुरोध नहीं किया है, तो इस ईमेल को अनदेखा करें।",
+		"email.digest.subject":             "Graveyard पर आपके अपठित संदेश हैं",
+		"email.digest.heading":             "अपठित संदेश",
+		"email.digest.greeting":            "नमस्ते %s,",
+		"email.digest.waiting":             "आपके लिए %s प्रतीक्षा कर रहे हैं।",
+		"email.digest.cta":                 "जवाब देने के लिए लॉग इन करें।",
+		"email.digest.text":                "नमस्ते %s, आपके पास %s हैं। जवाब देने के लिए लॉग इन करें।",
+		"email.digest.summary":             "%[3]d %[4]s से %[1]d अपठित %[2]s",
+		"email.digest.message":             "संदेश",
+		"email.digest.messages":            "संदेश",
+		"email.digest.buyer":               "खरीदार",
+		"email.digest.buyers":              "खरीदारों",
+		"email.digest.seller":              "विक्रेता",
+		"email.digest.sellers":             "विक्रेताओं",
+		"email.offer_received.subject":     "आपको एक नया प्रस्ताव मिला है",
+		"email.offer_received.body":        "आपको %[2]s पर %.2[1]f का प्रस्ताव मिला है।",
+		"email.message_received.subject":   "आपके लिए एक नया संदेश है",
+		"email.message_received.body":      "नया संदेश: %s",
+		"email.asset_sold.subject":         "आपकी संपत्ति बिक गई है",
+		"email.asset_sold.body":            "आपकी संपत्ति %s को बिका हुआ चिह्नित किया गया है।",
+		"email.watched_asset_updated.body": "आपकी देखी जा रही संपत्ति अपडेट हुई है: %s",
+		"email.report_updated.body":        "आपकी रिपोर्ट की स्थिति अब %s है।",
+		"email.bookmark_updated.body":      "आपके बुकमार्क किए गए स्टार्टअप %s की स्थिति बदल गई है।",
+		"email.purchase_receipt.subject":   "आपकी Graveyard रसीद",
+		"email.purchase_receipt.body":      "%s खरीदने के लिए धन्यवाद। आपने कर सहित %.2f का भुगतान किया; आपका चालान नंबर INV-%06d है।",
+		"email.unsubscribe.link":           "इन ईमेल की सदस्यता समाप्त करें",
+		"email.unsubscribe.text":           "ये ईमेल बंद करने के लिए %s पर जाएँ",
+		"validation.failed":                "सत्यापन विफल रहा",
+		"validation.invalid_json":          "अनुरोध का मुख्य भाग मान्य JSON नहीं है",
+		"validation.empty_body":            "अनुरोध का मुख्य भाग आवश्यक है",
+		"validation.too_large":             "अनुरोध का मुख्य भाग बहुत बड़ा है",
+		"validation.content_type":          "Content-Type application/json होना चाहिए",
+		"validation.required":              "%s आवश्यक है",
+		"validation.email":                 "%s एक मान्य ईमेल पता होना चाहिए",
+		"validation.url":                   "%s एक मान्य URL होना चाहिए",
+		"validation.uuid":                  "%s एक मान्य UUID होना चाहिए",
+		"validation.min_len":               "%s कम से कम %s वर्णों का होना चाहिए",
+		"validation.max_len":               "%s अधिकतम %s वर्णों का होना चाहिए",
+		"validation.min":                   "%s कम से कम %s होना चाहिए",
+		"validation.max":                   "%s अधिकतम %s होना चाहिए",
+		"validation.gte":                   "%s कम से कम %s होना चाहिए",
+		"validation.lte":                   "%s अधिकतम %s होना चाहिए",
+		"validation.gt":                    "%s, %s से अधिक होना चाहिए",
+		"validation.lt":                    "%s, %s से कम होना चाहिए",
+		"validation.oneof":                 "%s इनमें से एक होना चाहिए: %s",
+		"validation.invalid":               "%s अमान्य है",
+		"validation.unknown":               "%s की अनुमति नहीं है",
+		"validation.type.string":           "%s एक स्ट्रिंग होना चाहिए",
+		"validation.type.number":           "%s एक संख्या होनी चाहिए",
+		"validation.type.boolean":          "%s true या false होना चाहिए",
+		"validation.type.value":            "%s का प्रकार गलत है",
+	},
+}
+
+// IsSupported reports whether a catalog exists for locale (after normalisation).
+func IsSupported(locale string) bool {
+	_, ok := catalogs[base(locale)]
+	return ok
+}
+
+// Normalize reduces tags like "hi-IN" to a supported base locale, falling back to DefaultLocale.
+func Normalize(locale string) string {
+	b := base(locale)
+	if _, ok := catalogs[b]; ok {
+		return b
+	}
+	return DefaultLocale
+}
+
+// T renders the message for key in locale. Missing translations fall back to English,
+// and unknown keys are returned as-is so they are easy to spot.
+func T(locale, key string, args ...any) string {
+	format, ok := catalogs[Normalize(locale)][key]
+	if !ok {
+		format, ok = catalogs[DefaultLocale][key]
+		if !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func base(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
@@ -0,0 +1,44 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	require.Equal(t, "hi", Normalize("hi-IN"))
+	require.Equal(t, "en", Normalize("EN_us"))
+	require.Equal(t, DefaultLocale, Normalize("fr"))
+	require.Equal(t, DefaultLocale, Normalize(""))
+}
+
+func TestT_FallsBackToEnglish(t *testing.T) {
+	require.Equal(t, "Your OTP Code", T("fr", "email.otp.subject"))
+	require.Equal(t, "आपका OTP कोड", T("hi", "email.otp.subject"))
+	require.Equal(t, "unknown.key", T("hi", "unknown.key"))
+}
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for key := range catalogs[DefaultLocale] {
+			_, ok := catalog[key]
+			require.Truef(t, ok, "locale %s is missing key %s", locale, key)
+		}
+	}
+}
+
+func TestFromAcceptLanguage(t *testing.T) {
+	require.Equal(t, "hi", FromAcceptLanguage("hi-IN,hi;q=0.9,en;q=0.8"))
+	require.Equal(t, "hi", FromAcceptLanguage("fr;q=1, en;q=0.5, hi;q=0.7"))
+	require.Equal(t, "en", FromAcceptLanguage("en-US,hi;q=0.9"))
+	require.Equal(t, DefaultLocale, FromAcceptLanguage("hi;q=0, fr"))
+	require.Equal(t, DefaultLocale, FromAcceptLanguage(""))
+}
+
+func TestMessage(t *testing.T) {
+	require.Equal(t, "संपत्ति नहीं मिली", Message("hi", "asset not found"))
+	require.Equal(t, "asset not found", Message("en", "asset not found"))
+	require.Equal(t, "चालान नहीं मिला", Message("hi", "invoice not found"))
+	require.Equal(t, "failed to connect: timeout", Message("hi", "failed to connect: timeout"))
+}
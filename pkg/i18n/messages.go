@@ -0,0 +1,390 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// messages translates API response messages, keyed by the English text handlers
+// already send, so the response package can localize them without each handler
+// looking up a key. Messages with no entry (e.g. ones that embed an error string)
+// are returned in English.
+var messages = map[string]map[string]string{
+	"hi": {
+		"ok":                            "ठीक है",
+		"unauthorized":                  "अनधिकृत",
+		"your role does not allow this": "आपकी भूमिका इसकी अनुमति नहीं देती",
+		"internal server error":         "आंतरिक सर्वर त्रुटि",
+		"invalid cursor":                "अमान्य कर्सर",
+		"invalid request payload":       "अमान्य अनुरोध",
+		"request body too large":        "अनुरोध का मुख्य भाग बहुत बड़ा है",
+
+		"user created":                                     "उपयोगकर्ता बनाया गया",
+		"user updated":                                     "उपयोगकर्ता अपडेट किया गया",
+		"user deleted":                                     "उपयोगकर्ता हटाया गया",
+		"two-factor code required":                         "दो-चरणीय कोड आवश्यक है",
+		"invalid two-factor code":                          "अमान्य दो-चरणीय कोड",
+		"two-factor authentication is not set up":          "दो-चरणीय प्रमाणीकरण सेट नहीं है",
+		"two-factor authentication is already enabled":     "दो-चरणीय प्रमाणीकरण पहले से चालू है",
+		"too many wrong two-factor codes; try again later": "बहुत अधिक गलत दो-चरणीय कोड; बाद में पुनः प्रयास करें",
+		"two-factor enrollment started":                    "दो-चरणीय नामांकन शुरू हुआ",
+		"two-factor authentication enabled":                "दो-चरणीय प्रमाणीकरण चालू किया गया",
+		"two-factor authentication disabled":               "दो-चरणीय प्रमाणीकरण बंद किया गया",
+		"session not found":                                "सत्र नहीं मिला",
+		"sessions listed":                                  "सत्र सूचीबद्ध",
+		"session revoked":                                  "सत्र रद्द किया गया",
+		"invalid session id":                               "अमान्य सत्र आईडी",
+		"you cannot block yourself":                        "आप स्वयं को ब्लॉक नहीं कर सकते",
+		"blocked users listed":                             "ब्लॉक किए गए उपयोगकर्ता सूचीबद्ध",
+		"user blocked":                                     "उपयोगकर्ता ब्लॉक किया गया",
+		"user unblocked":                                   "उपयोगकर्ता अनब्लॉक किया गया",
+		"profile fetched":                                  "प्रोफ़ाइल प्राप्त हुई",
+		"stats fetched":                                    "आँकड़े प्राप्त हुए",
+		"users found":                                      "उपयोगकर्ता मिले",
+		"invite code fetched":                              "आमंत्रण कोड प्राप्त हुआ",
+		"referrals fetched":                                "रेफ़रल प्राप्त हुए",
+		"impersonation started":                            "प्रतिरूपण शुरू हुआ",
+		"audit log fetched":                                "ऑडिट लॉग प्राप्त हुआ",
+		"admins cannot be impersonated":                    "एडमिन का प्रतिरूपण नहीं किया जा सकता",
+		"user banned":                                      "उपयोगकर्ता प्रतिबंधित किया गया",
+		"user unbanned":                                    "उपयोगकर्ता से प्रतिबंध हटाया गया",
+		"this account has been suspended":                  "यह खाता निलंबित कर दिया गया है",
+		"admins cannot be banned":                          "एडमिन को प्रतिबंधित नहीं किया जा सकता",
+		"security log fetched":                             "सुरक्षा लॉग प्राप्त हुआ",
+		"handle is taken":                                  "यह हैंडल पहले से लिया जा चुका है",
+		"handle must be 3-30 letters, digits or underscores, starting with a letter": "हैंडल 3-30 अक्षरों, अंकों या अंडरस्कोर का होना चाहिए और अक्षर से शुरू होना चाहिए",
+		"account scheduled for deletion":                                             "खाता हटाने के लिए निर्धारित किया गया",
+		"days must be between 1 and 90":                                              "days 1 और 90 के बीच होना चाहिए",
+		"role updated":                                                               "भूमिका अपडेट की गई",
+		"user fetched":                                                               "उपयोगकर्ता प्राप्त हुआ",
+		"users listed":                                                               "उपयोगकर्ताओं की सूची",
+		"user not found":                                                             "उपयोगकर्ता नहीं मिला",
+		"user exists with that email":                                                "इस ईमेल से एक उपयोगकर्ता पहले से मौजूद है",
+		"invalid user uuid":                                                          "अमान्य उपयोगकर्ता UUID",
+		"user uuid required":                                                         "उपयोगकर्ता UUID आवश्यक है",
+		"invalid role":                                                               "अमान्य भूमिका",
+		"unsupported locale":                                                         "असमर्थित भाषा",
+		"login successful":                                                           "लॉगिन सफल रहा",
+		"invalid credentials":                                                        "अमान्य क्रेडेंशियल",
+		"invalid or expired token":                                                   "अमान्य या समाप्त टोकन",
+		"invalid or expired refresh token":                                           "अमान्य या समाप्त रीफ़्रेश टोकन",
+		"refresh token already used; log in again":                                   "रीफ़्रेश टोकन पहले ही उपयोग हो चुका है; फिर से लॉगिन करें",
+		"invalid or expired password reset token":                                    "अमान्य या समाप्त पासवर्ड रीसेट टोकन",
+		"if that email has an account, a reset link has been sent":                   "यदि उस ईमेल का खाता है, तो रीसेट लिंक भेज दिया गया है",
+		"password reset":                                                             "पासवर्ड रीसेट हो गया",
+		"invalid or expired ID token":                                                "अमान्य या समाप्त ID टोकन",
+		"the provider has not verified this email":                                   "प्रदाता ने इस ईमेल को सत्यापित नहीं किया है",
+		"invalid or expired authorization code":                                      "अमान्य या समाप्त प्राधिकरण कोड",
+		"this account is already linked to another user":                             "यह खाता पहले से किसी अन्य उपयोगकर्ता से जुड़ा है",
+		"no linked account for that provider":                                        "उस प्रदाता के लिए कोई जुड़ा खाता नहीं है",
+		"only founders can link a GitHub account":                                    "केवल संस्थापक GitHub खाता जोड़ सकते हैं",
+		"identity linked":                                                            "खाता जोड़ा गया",
+		"identity unlinked":                                                          "खाता हटाया गया",
+		"token refreshed":                                                            "टोकन रीफ़्रेश किया गया",
+		"logged out":                                                                 "लॉग आउट किया गया",
+
+		"email verification required": "ईमेल सत्यापन आवश्यक है",
+
+		"asset created":                      "संपत्ति बनाई गई",
+		"asset updated":                      "संपत्ति अपडेट की गई",
+		"asset deleted":                      "संपत्ति हटाई गई",
+		"asset fetched":                      "संपत्ति प्राप्त हुई",
+		"assets listed":                      "संपत्तियों की सूची",
+		"assets found":                       "संपत्तियाँ मिलीं",
+		"startup assets listed":              "स्टार्टअप की संपत्तियों की सूची",
+		"assets deleted":                     "संपत्तियाँ हटाई गईं",
+		"all user assets deleted":            "उपयोगकर्ता की सभी संपत्तियाँ हटाई गईं",
+		"asset not found":                    "संपत्ति नहीं मिली",
+		"only the asset owner can change it": "केवल संपत्ति का स्वामी इसे बदल सकता है",
+		"invalid asset id":                   "अमान्य संपत्ति ID",
+		"invalid asset_type":                 "अमान्य asset_type",
+		"price cannot be negative":           "मूल्य ऋणात्मक नहीं हो सकता",
+		"user_uuid must be provided":         "user_uuid देना आवश्यक है",
+		"asset marked as sold":               "संपत्ति को बिका हुआ चिह्नित किया गया",
+		"asset unlisted":                     "संपत्ति सूची से हटाई गई",
+		"asset already marked as sold":       "संपत्ति पहले से ही बिकी हुई चिह्नित है",
+
+		"only the owner can sell or unlist this listing": "केवल स्वामी ही इस लिस्टिंग को बेच या हटा सकता है",
+
+		"assets compared":                "संपत्तियों की तुलना",
+		"ids must list 2 to 5 asset ids": "ids में 2 से 5 संपत्ति ID होनी चाहिए",
+
+		"country must be an ISO 3166-1 alpha-2 code":   "country एक ISO 3166-1 alpha-2 कोड होना चाहिए",
+		"region must be an ISO 3166-2 code in country": "region, country में एक ISO 3166-2 कोड होना चाहिए",
+
+		"unsupported display_currency": "असमर्थित display_currency",
+		"exchange rates unavailable":   "विनिमय दरें उपलब्ध नहीं हैं",
+
+		"buyer not found": "खरीदार नहीं मिला",
+
+		"startup created":                      "स्टार्टअप बनाया गया",
+		"startup updated":                      "स्टार्टअप अपडेट किया गया",
+		"startup deleted":                      "स्टार्टअप हटाया गया",
+		"startup restored":                     "स्टार्टअप बहाल किया गया",
+		"deleted startups listed":              "हटाए गए स्टार्टअप की सूची",
+		"startup fetched":                      "स्टार्टअप प्राप्त हुआ",
+		"startup fetched by uuid":              "स्टार्टअप प्राप्त हुए",
+		"startups listed":                      "स्टार्टअप की सूची",
+		"categories listed":                    "श्रेणियों की सूची",
+		"categories updated":                   "श्रेणियाँ अपडेट की गईं",
+		"startup transferred":                  "स्टार्टअप हस्तांतरित किया गया",
+		"ownership history listed":             "स्वामित्व इतिहास की सूची",
+		"trending startups listed":             "ट्रेंडिंग स्टार्टअप की सूची",
+		"verification requested":               "सत्यापन का अनुरोध किया गया",
+		"verification fetched":                 "सत्यापन की स्थिति प्राप्त हुई",
+		"verification requests listed":         "सत्यापन अनुरोधों की सूची",
+		"startup verified":                     "स्टार्टअप सत्यापित किया गया",
+		"verification rejected":                "सत्यापन अनुरोध अस्वीकार किया गया",
+		"members listed":                       "टीम सदस्यों की सूची",
+		"member added":                         "टीम सदस्य जोड़ा गया",
+		"member removed":                       "टीम सदस्य हटाया गया",
+		"startups found":                       "स्टार्टअप मिले",
+		"startups deleted":                     "स्टार्टअप हटाए गए",
+		"startup not found":                    "स्टार्टअप नहीं मिला",
+		"only the startup owner can change it": "केवल स्टार्टअप का स्वामी इसे बदल सकता है",
+		"invalid startup id":                   "अमान्य स्टार्टअप ID",
+		"invalid status":                       "अमान्य स्थिति",
+		"owner_uuid must be provided":          "owner_uuid देना आवश्यक है",
+		"startup marked as sold":               "स्टार्टअप को बिका हुआ चिह्नित किया गया",
+		"startup unlisted":                     "स्टार्टअप सूची से हटाया गया",
+		"startup already marked as sold":       "स्टार्टअप पहले से ही बिका हुआ चिह्नित है",
+
+		"bulk delete previewed":                                            "बल्क हटाने का पूर्वावलोकन",
+		"invalid dry_run, expected true or false":                          "अमान्य dry_run, true या false अपेक्षित है",
+		"invalid created_before, expected RFC 3339 or YYYY-MM-DD":          "अमान्य created_before, RFC 3339 या YYYY-MM-DD अपेक्षित है",
+		"at least one of owner_uuid, created_before or status is required": "owner_uuid, created_before या status में से कम से कम एक आवश्यक है",
+
+		"q must be between 1 and 200 characters": "q 1 से 200 वर्णों के बीच होना चाहिए",
+		"search engine unavailable":              "खोज सेवा उपलब्ध नहीं है",
+
+		"OTP sent successfully":                               "OTP सफलतापूर्वक भेजा गया",
+		"OTP verified successfully":                           "OTP सफलतापूर्वक सत्यापित हुआ",
+		"OTP has expired":                                     "OTP की समय सीमा समाप्त हो गई है",
+		"invalid OTP code":                                    "अमान्य OTP कोड",
+		"no OTP found for this email or OTP already verified": "इस ईमेल के लिए कोई OTP नहीं मिला या OTP पहले ही सत्यापित हो चुका है",
+		"too many OTP requests. Please try again later":       "बहुत अधिक OTP अनुरोध। कृपया बाद में पुनः प्रयास करें",
+
+		"messages":                                    "संदेश",
+		"online status":                               "ऑनलाइन स्थिति",
+		"peer_id is required":                         "peer_id आवश्यक है",
+		"invalid limit parameter":                     "अमान्य limit पैरामीटर",
+		"invalid before parameter":                    "अमान्य before पैरामीटर",
+		"failed to fetch messages":                    "संदेश प्राप्त करने में विफल",
+		"message history not available":               "संदेश इतिहास उपलब्ध नहीं है",
+		"forbidden: can only fetch your own messages": "निषिद्ध: आप केवल अपने संदेश देख सकते हैं",
+		"messages marked as read":                     "संदेश पढ़े गए के रूप में चिह्नित",
+		"failed to mark messages as read":             "संदेशों को पढ़ा गया चिह्नित करने में विफल",
+		"provide either message_ids or peer_id":       "message_ids या peer_id में से कोई एक दें",
+		"up_to_id requires peer_id":                   "up_to_id के लिए peer_id आवश्यक है",
+
+		"upload created":                   "अपलोड बनाया गया",
+		"file uploaded":                    "फ़ाइल अपलोड हुई",
+		"file not found":                   "फ़ाइल नहीं मिली",
+		"file too large":                   "फ़ाइल बहुत बड़ी है",
+		"invalid or expired link":          "अमान्य या समाप्त लिंक",
+		"image fetched":                    "छवि प्राप्त हुई",
+		"image not found":                  "छवि नहीं मिली",
+		"image queued for processing":      "छवि प्रोसेसिंग के लिए कतार में है",
+		"image has already been submitted": "छवि पहले ही सबमिट की जा चुकी है",
+
+		"activity listed":       "गतिविधि की सूची",
+		"invalid activity type": "अमान्य गतिविधि प्रकार",
+
+		"notifications listed":             "सूचनाओं की सूची",
+		"preferences fetched":              "प्राथमिकताएँ प्राप्त हुईं",
+		"preferences updated":              "प्राथमिकताएँ अपडेट की गईं",
+		"unread notifications":             "अपठित सूचनाएँ",
+		"notification marked as read":      "सूचना को पढ़ा हुआ चिह्नित किया गया",
+		"all notifications marked as read": "सभी सूचनाओं को पढ़ा हुआ चिह्नित किया गया",
+		"notification not found":           "सूचना नहीं मिली",
+		"invalid notification id":          "अमान्य सूचना ID",
+
+		"report filed":                             "रिपोर्ट दर्ज की गई",
+		"report already open":                      "यह रिपोर्ट पहले से खुली है",
+		"reports listed":                           "रिपोर्टों की सूची",
+		"report updated":                           "रिपोर्ट अपडेट की गई",
+		"report not found":                         "रिपोर्ट नहीं मिली",
+		"reported item not found":                  "रिपोर्ट की गई वस्तु नहीं मिली",
+		"too many reports. Please try again later": "बहुत अधिक रिपोर्टें। कृपया बाद में पुनः प्रयास करें",
+		"report cannot move to that status":        "रिपोर्ट को उस स्थिति में नहीं ले जाया जा सकता",
+		"you cannot report yourself":               "आप स्वयं की रिपोर्ट नहीं कर सकते",
+		"invalid report id":                        "अमान्य रिपोर्ट ID",
+		"invalid report status":                    "अमान्य रिपोर्ट स्थिति",
+
+		"startup bookmarked":         "स्टार्टअप बुकमार्क किया गया",
+		"startup already bookmarked": "स्टार्टअप पहले से बुकमार्क है",
+		"bookmark removed":           "बुकमार्क हटाया गया",
+		"bookmark not found":         "बुकमार्क नहीं मिला",
+		"bookmarked startups listed": "बुकमार्क किए गए स्टार्टअप की सूची",
+
+		"link preview fetched":                       "लिंक पूर्वावलोकन प्राप्त हुआ",
+		"link preview unavailable":                   "लिंक पूर्वावलोकन उपलब्ध नहीं है",
+		"url must be a public http or https address": "url एक सार्वजनिक http या https पता होना चाहिए",
+
+		"repository linked":                                  "रिपॉजिटरी लिंक की गई",
+		"repository unlinked":                                "रिपॉजिटरी अनलिंक की गई",
+		"repository stats fetched":                           "रिपॉजिटरी आँकड़े प्राप्त हुए",
+		"no repository linked":                               "कोई रिपॉजिटरी लिंक नहीं है",
+		"only codebase assets can link a repository":         "केवल codebase संपत्तियाँ रिपॉजिटरी लिंक कर सकती हैं",
+		"only the asset owner can change its repository":     "केवल संपत्ति का स्वामी इसकी रिपॉजिटरी बदल सकता है",
+		"repo must be owner/name":                            "repo owner/name के रूप में होना चाहिए",
+		"invalid github username":                            "अमान्य GitHub उपयोगकर्ता नाम",
+		"the app installation cannot access that repository": "ऐप इंस्टॉलेशन उस रिपॉजिटरी तक नहीं पहुँच सकता",
+		"github unavailable":                                 "GitHub उपलब्ध नहीं है",
+
+		"data preview published":                                           "डेटा पूर्वावलोकन प्रकाशित किया गया",
+		"data preview fetched":                                             "डेटा पूर्वावलोकन प्राप्त हुआ",
+		"documents listed":                                                 "दस्तावेज़ों की सूची",
+		"document uploaded":                                                "दस्तावेज़ अपलोड किया गया",
+		"document deleted":                                                 "दस्तावेज़ हटाया गया",
+		"no data preview for this asset":                                   "इस संपत्ति का कोई डेटा पूर्वावलोकन नहीं है",
+		"only data assets can publish a data preview":                      "केवल data संपत्तियाँ डेटा पूर्वावलोकन प्रकाशित कर सकती हैं",
+		"only the asset owner can publish its data preview":                "केवल संपत्ति का स्वामी इसका डेटा पूर्वावलोकन प्रकाशित कर सकता है",
+		"no sample was uploaded":                                           "कोई नमूना अपलोड नहीं किया गया",
+		"sample must be at most 5 MiB":                                     "नमूना अधिकतम 5 MiB का होना चाहिए",
+		"sample must be a CSV file with a header row and at least one row": "नमूना हेडर पंक्ति और कम से कम एक पंक्ति वाली CSV फ़ाइल होनी चाहिए",
+
+		"agreement attached":                            "अनुबंध संलग्न किया गया",
+		"agreement fetched":                             "अनुबंध प्राप्त हुआ",
+		"agreement removed":                             "अनुबंध हटाया गया",
+		"agreement signed":                              "अनुबंध पर हस्ताक्षर किए गए",
+		"signed agreement fetched":                      "हस्ताक्षरित अनुबंध प्राप्त हुआ",
+		"signed agreements listed":                      "हस्ताक्षरित अनुबंधों की सूची",
+		"invalid agreement id":                          "अमान्य अनुबंध ID",
+		"no agreement attached to this asset":           "इस संपत्ति से कोई अनुबंध संलग्न नहीं है",
+		"signed agreement not found":                    "हस्ताक्षरित अनुबंध नहीं मिला",
+		"only the asset owner can change its agreement": "केवल संपत्ति का स्वामी इसका अनुबंध बदल सकता है",
+		"sellers do not sign their own agreement":       "विक्रेता अपने स्वयं के अनुबंध पर हस्ताक्षर नहीं करते",
+		"the agreement has changed, review the current version before signing": "अनुबंध बदल गया है, हस्ताक्षर करने से पहले वर्तमान संस्करण देखें",
+		"the agreement for this asset must be signed first":                    "पहले इस संपत्ति के अनुबंध पर हस्ताक्षर करना आवश्यक है",
+
+		"offer sent to the seller": "प्रस्ताव विक्रेता को भेजा गया",
+		"offer accepted":           "प्रस्ताव स्वीकार किया गया",
+		"offer rejected":           "प्रस्ताव अस्वीकार किया गया",
+		"thank you for your offer, but the seller is unable to accept it": "आपके प्रस्ताव के लिए धन्यवाद, लेकिन विक्रेता इसे स्वीकार करने में असमर्थ है",
+		"offers listed":                                      "प्रस्तावों की सूची",
+		"offer rules fetched":                                "प्रस्ताव नियम प्राप्त हुए",
+		"offer rules saved":                                  "प्रस्ताव नियम सहेजे गए",
+		"invalid offer id":                                   "अमान्य प्रस्ताव ID",
+		"offer not found":                                    "प्रस्ताव नहीं मिला",
+		"only the asset owner can do that":                   "केवल संपत्ति का स्वामी ऐसा कर सकता है",
+		"you cannot make an offer on your own asset":         "आप अपनी ही संपत्ति पर प्रस्ताव नहीं दे सकते",
+		"you cannot make an offer to this user":              "आप इस उपयोगकर्ता को प्रस्ताव नहीं दे सकते",
+		"asset is no longer available":                       "संपत्ति अब उपलब्ध नहीं है",
+		"this asset's price is not negotiable":               "इस संपत्ति की कीमत पर मोलभाव नहीं हो सकता",
+		"offer has already been accepted or rejected":        "प्रस्ताव पहले ही स्वीकार या अस्वीकार किया जा चुका है",
+		"min_price cannot be above the asking price":         "min_price माँगी गई कीमत से अधिक नहीं हो सकता",
+		"negotiation bounds only apply to negotiable assets": "मोलभाव की सीमाएँ केवल मोलभाव योग्य संपत्तियों पर लागू होती हैं",
+
+		"suggestions fetched":                    "सुझाव प्राप्त हुए",
+		"q must be between 1 and 100 characters": "q 1 से 100 वर्णों के बीच होना चाहिए",
+		"limit must be between 1 and 10":         "limit 1 से 10 के बीच होना चाहिए",
+
+		"usage fetched":              "उपयोग प्राप्त हुआ",
+		"request quota exceeded":     "अनुरोध कोटा समाप्त हो गया",
+		"invalid or revoked API key": "अमान्य या रद्द की गई API कुंजी",
+		"unknown quota tier":         "अज्ञात कोटा स्तर",
+
+		"seller dashboard fetched": "विक्रेता डैशबोर्ड प्राप्त हुआ",
+		"buyer dashboard fetched":  "खरीदार डैशबोर्ड प्राप्त हुआ",
+
+		"events accepted":            "इवेंट स्वीकार किए गए",
+		"events could not be stored": "इवेंट सहेजे नहीं जा सके",
+
+		"unsubscribed":              "सदस्यता समाप्त की गई",
+		"token is required":         "token आवश्यक है",
+		"invalid unsubscribe token": "अमान्य सदस्यता-समाप्ति टोकन",
+
+		"Idempotency-Key is too long":                                       "Idempotency-Key बहुत लंबी है",
+		"Idempotency-Key was already used with a different request body":    "Idempotency-Key का उपयोग पहले ही किसी अन्य अनुरोध के साथ किया जा चुका है",
+		"a request with this Idempotency-Key is still in progress":          "इस Idempotency-Key वाला अनुरोध अभी भी प्रगति में है",
+		"from must not be after to and the range may span at most 366 days": "from, to के बाद नहीं होना चाहिए और अवधि अधिकतम 366 दिन हो सकती है",
+		"invalid from date, expected YYYY-MM-DD":                            "अमान्य from तिथि, YYYY-MM-DD अपेक्षित है",
+		"invalid to date, expected YYYY-MM-DD":                              "अमान्य to तिथि, YYYY-MM-DD अपेक्षित है",
+		"invalid date range":                                                "अमान्य तिथि सीमा",
+		"you can only change your own account":                              "आप केवल अपना खाता बदल सकते हैं",
+		"recipient not found":                                               "प्राप्तकर्ता नहीं मिला",
+		"metadata retrieved":                                                "मेटाडेटा प्राप्त हुआ",
+		"emails listed":                                                     "ईमेल की सूची",
+		"events processed":                                                  "इवेंट संसाधित किए गए",
+		"invalid webhook signature":                                         "अमान्य वेबहुक हस्ताक्षर",
+
+		"document not found":                              "दस्तावेज़ नहीं मिला",
+		"only the startup owner can manage its documents": "केवल स्टार्टअप का स्वामी इसके दस्तावेज़ प्रबंधित कर सकता है",
+		"no file was uploaded":                            "कोई फ़ाइल अपलोड नहीं की गई",
+		"documents must be at most 50 MiB":                "दस्तावेज़ अधिकतम 50 MiB के होने चाहिए",
+
+		"invalid startup slug":                                   "अमान्य स्टार्टअप स्लग",
+		"invalid verified, expected true or false":               "अमान्य verified, true या false अपेक्षित है",
+		"sort must be one of newest, name or most_assets":        "sort newest, name या most_assets में से एक होना चाहिए",
+		"limit must be between 1 and 50":                         "limit 1 से 50 के बीच होना चाहिए",
+		"only the startup owner and editors can change it":       "केवल स्टार्टअप का स्वामी और संपादक इसे बदल सकते हैं",
+		"only the startup's team can see its members":            "केवल स्टार्टअप की टीम इसके सदस्य देख सकती है",
+		"member not found":                                       "सदस्य नहीं मिला",
+		"the owner is already on the team":                       "स्वामी पहले से टीम में है",
+		"only sold startups can be transferred":                  "केवल बिके हुए स्टार्टअप स्थानांतरित किए जा सकते हैं",
+		"new owner not found":                                    "नया स्वामी नहीं मिला",
+		"the startup already belongs to this user":               "स्टार्टअप पहले से इस उपयोगकर्ता का है",
+		"the startup already has a pending verification request": "स्टार्टअप का एक सत्यापन अनुरोध पहले से लंबित है",
+		"verification request not found":                         "सत्यापन अनुरोध नहीं मिला",
+		"startup is already verified":                            "स्टार्टअप पहले से सत्यापित है",
+		"invalid verification status":                            "अमान्य सत्यापन स्थिति",
+
+		"asset favorited":         "संपत्ति पसंदीदा में जोड़ी गई",
+		"asset already favorited": "संपत्ति पहले से पसंदीदा में है",
+		"favorite removed":        "पसंदीदा हटाया गया",
+		"favorite assets listed":  "पसंदीदा संपत्तियों की सूची",
+		"favorite not found":      "पसंदीदा नहीं मिला",
+
+		"shared list not found": "साझा सूची नहीं मिली",
+		"favorites shared":      "पसंदीदा साझा किए गए",
+		"share link revoked":    "साझा लिंक रद्द किया गया",
+		"shared list fetched":   "साझा सूची प्राप्त हुई",
+
+		"invoice fetched":                              "चालान प्राप्त हुआ",
+		"earnings report fetched":                      "आय रिपोर्ट प्राप्त हुई",
+		"invalid transaction id":                       "अमान्य लेनदेन ID",
+		"invoice not found":                            "चालान नहीं मिला",
+		"only the buyer and seller can see an invoice": "केवल खरीदार और विक्रेता चालान देख सकते हैं",
+	},
+}
+
+// Message translates an English API message into locale, returning it unchanged
+// when locale is English or the catalog has no entry.
+func Message(locale, english string) string {
+	if translated, ok := messages[Normalize(locale)][english]; ok {
+		return translated
+	}
+	return english
+}
+
+// FromAcceptLanguage picks the supported locale the client ranks highest in an
+// Accept-Language header such as "hi-IN,hi;q=0.9,en;q=0.8", falling back to
+// DefaultLocale.
+func FromAcceptLanguage(header string) string {
+	type tag struct {
+		locale string
+		q      float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 && IsSupported(locale) {
+			tags = append(tags, tag{locale: base(locale), q: q})
+		}
+	}
+	if len(tags) == 0 {
+		return DefaultLocale
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	return tags[0].locale
+}
@@ -0,0 +1,169 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/requestid"
+	"grveyard/pkg/response"
+)
+
+const (
+	// Header carries the client-chosen key, typically a UUID per logical operation.
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set to "true" on responses served from a stored snapshot.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLen = 255
+)
+
+// Config controls the idempotency middleware.
+type Config struct {
+	TTL     time.Duration // how long snapshots are replayed
+	MaxBody int64         // larger request bodies are rejected rather than hashed; 0 disables
+	// Scope returns the identity keys are namespaced by. Defaults to the "user_id"
+	// context value, falling back to the client IP for anonymous requests.
+	Scope func(c *gin.Context) string
+}
+
+// DefaultConfig keeps snapshots for a day.
+func DefaultConfig() Config {
+	return Config{TTL: 24 * time.Hour, MaxBody: 1 << 20}
+}
+
+func defaultScope(c *gin.Context) string {
+	if uid := c.GetString("user_id"); uid != "" {
+		return uid
+	}
+	return c.ClientIP()
+}
+
+// captureWriter passes the response through while keeping a copy for the snapshot.
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Middleware makes POST and PATCH requests carrying an Idempotency-Key safe to retry.
+// The first request runs normally and its response is stored; retries with the same
+// key, route and body get the stored response back. Reusing a key with a different
+// body is rejected with 422, and a retry racing the original gets 409. 5xx responses
+// are not stored so the client can try again.
+func Middleware(store Store, cfg Config) gin.HandlerFunc {
+	if cfg.Scope == nil {
+		cfg.Scope = defaultScope
+	}
+	logger := log.New(log.Writer(), "[idempotency] ", log.LstdFlags)
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(Header)
+		if key == "" || (c.Request.Method != http.MethodPost && c.Request.Method != http.MethodPatch) {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLen {
+			response.SendError(c, apperr.New(apperr.InvalidRequest, "Idempotency-Key is too long"))
+			c.Abort()
+			return
+		}
+
+		var reader io.Reader = c.Request.Body
+		if cfg.MaxBody > 0 {
+			reader = io.LimitReader(reader, cfg.MaxBody+1)
+		}
+		body, err := io.ReadAll(reader)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) || (cfg.MaxBody > 0 && int64(len(body)) > cfg.MaxBody) {
+			response.SendError(c, apperr.New(apperr.PayloadTooLarge, "request body too large"))
+			c.Abort()
+			return
+		}
+		if err != nil {
+			response.SendError(c, apperr.Wrap(apperr.InvalidRequest, "failed to read request body", err))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(body)
+		hash := hex.EncodeToString(sum[:])
+		k := Key{Scope: cfg.Scope(c), Key: key, Route: c.Request.Method + " " + c.FullPath()}
+
+		ctx := c.Request.Context()
+		rec, created, err := store.Begin(ctx, k, hash, cfg.TTL)
+		if err != nil {
+			// Fail open: a broken store should not take writes down with it
+			logger.Printf("[%s] begin %s: %v", requestid.FromContext(ctx), k.Route, err)
+			c.Next()
+			return
+		}
+		if !created {
+			switch {
+			case rec.RequestHash != hash:
+				response.SendError(c, apperr.New(apperr.IdempotencyKeyReused, "Idempotency-Key was already used with a different request body"))
+			case rec.Status == 0:
+				response.SendError(c, apperr.New(apperr.IdempotencyInProgress, "a request with this Idempotency-Key is still in progress"))
+			default:
+				c.Header(ReplayedHeader, "true")
+				c.Data(rec.Status, rec.ContentType, rec.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		original := c.Writer
+		cw := &captureWriter{ResponseWriter: original}
+		c.Writer = cw
+		completed := false
+		defer func() {
+			c.Writer = original
+			if completed {
+				return
+			}
+			// The handler panicked; drop the reservation so a retry is not stuck on 409
+			if err := store.Release(context.WithoutCancel(ctx), k); err != nil {
+				logger.Printf("[%s] release %s: %v", requestid.FromContext(ctx), k.Route, err)
+			}
+		}()
+		c.Next()
+
+		// Record after the response went out; a cancelled request context must not
+		// lose the snapshot
+		storeCtx := context.WithoutCancel(ctx)
+		status := cw.Status()
+		if status >= http.StatusInternalServerError {
+			err = store.Release(storeCtx, k)
+		} else {
+			err = store.Complete(storeCtx, k, Record{
+				RequestHash: hash,
+				Status:      status,
+				ContentType: cw.Header().Get("Content-Type"),
+				Body:        cw.body.Bytes(),
+			})
+		}
+		completed = true
+		if err != nil {
+			logger.Printf("[%s] save %s: %v", requestid.FromContext(ctx), k.Route, err)
+		}
+	}
+}
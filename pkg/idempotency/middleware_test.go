@@ -0,0 +1,166 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/response"
+)
+
+type fakeStore struct {
+	mu      sync.Mutex
+	records map[Key]Record
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{records: make(map[Key]Record)}
+}
+
+func (s *fakeStore) Begin(ctx context.Context, k Key, requestHash string, ttl time.Duration) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rec, ok := s.records[k]; ok {
+		return rec, false, nil
+	}
+	s.records[k] = Record{RequestHash: requestHash}
+	return s.records[k], true, nil
+}
+
+func (s *fakeStore) Complete(ctx context.Context, k Key, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[k] = rec
+	return nil
+}
+
+func (s *fakeStore) Release(ctx context.Context, k Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, k)
+	return nil
+}
+
+func (s *fakeStore) DeleteExpired(ctx context.Context) (int64, error) { return 0, nil }
+
+func setupRouter(store Store, status *int) (*gin.Engine, *int) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	r := gin.New()
+	r.Use(gin.Recovery(), Middleware(store, DefaultConfig()))
+	r.POST("/startups", func(c *gin.Context) {
+		calls++
+		response.SendAPIResponse(c, *status, *status < 400, "startup created", map[string]any{"id": calls})
+	})
+	r.POST("/panics", func(c *gin.Context) {
+		calls++
+		panic("boom")
+	})
+	return r, &calls
+}
+
+func post(r *gin.Engine, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(Header, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_ReplaysStoredResponse(t *testing.T) {
+	status := http.StatusCreated
+	r, calls := setupRouter(newFakeStore(), &status)
+
+	w1 := post(r, "/startups", "k1", `{"name":"a"}`)
+	w2 := post(r, "/startups", "k1", `{"name":"a"}`)
+
+	require.Equal(t, 1, *calls)
+	require.Equal(t, http.StatusCreated, w2.Code)
+	require.Equal(t, w1.Body.String(), w2.Body.String())
+	require.Equal(t, "true", w2.Header().Get(ReplayedHeader))
+	require.Empty(t, w1.Header().Get(ReplayedHeader))
+}
+
+func TestMiddleware_WithoutKeyPassesThrough(t *testing.T) {
+	status := http.StatusCreated
+	r, calls := setupRouter(newFakeStore(), &status)
+
+	post(r, "/startups", "", `{}`)
+	post(r, "/startups", "", `{}`)
+
+	require.Equal(t, 2, *calls)
+}
+
+func TestMiddleware_DifferentBodyRejected(t *testing.T) {
+	status := http.StatusCreated
+	r, calls := setupRouter(newFakeStore(), &status)
+
+	post(r, "/startups", "k1", `{"name":"a"}`)
+	w := post(r, "/startups", "k1", `{"name":"b"}`)
+
+	require.Equal(t, 1, *calls)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestMiddleware_InFlightConflict(t *testing.T) {
+	store := newFakeStore()
+	status := http.StatusCreated
+	r, calls := setupRouter(store, &status)
+
+	// Reserve the key as if the original request were still running
+	store.records[Key{Scope: "192.0.2.1", Key: "k1", Route: "POST /startups"}] = Record{RequestHash: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}
+	w := post(r, "/startups", "k1", ``)
+
+	require.Equal(t, 0, *calls)
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestMiddleware_ServerErrorsNotStored(t *testing.T) {
+	status := http.StatusInternalServerError
+	r, calls := setupRouter(newFakeStore(), &status)
+
+	post(r, "/startups", "k1", `{}`)
+	status = http.StatusCreated
+	w := post(r, "/startups", "k1", `{}`)
+
+	require.Equal(t, 2, *calls)
+	require.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestMiddleware_PanicReleasesKey(t *testing.T) {
+	store := newFakeStore()
+	status := http.StatusCreated
+	r, calls := setupRouter(store, &status)
+
+	w := post(r, "/panics", "k1", `{}`)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Empty(t, store.records)
+
+	post(r, "/panics", "k1", `{}`)
+	require.Equal(t, 2, *calls)
+}
+
+func TestMiddleware_KeysScopedByRoute(t *testing.T) {
+	status := http.StatusCreated
+	r, calls := setupRouter(newFakeStore(), &status)
+	r.POST("/assets", func(c *gin.Context) {
+		*calls++
+		response.SendAPIResponse(c, http.StatusCreated, true, "asset created", nil)
+	})
+
+	post(r, "/startups", "k1", `{}`)
+	w := post(r, "/assets", "k1", `{}`)
+
+	require.Equal(t, 2, *calls)
+	require.Contains(t, w.Body.String(), "asset created")
+}
@@ -0,0 +1,95 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Key identifies one idempotent request: who sent it, the client-chosen key and the route.
+type Key struct {
+	Scope string
+	Key   string
+	Route string
+}
+
+// Record is a stored request. Status is 0 while the original request is still running.
+type Record struct {
+	RequestHash string
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// Store persists response snapshots keyed by Key.
+type Store interface {
+	// Begin reserves k for a request with the given body hash. When k is already
+	// reserved and not expired, the existing record is returned with created=false.
+	Begin(ctx context.Context, k Key, requestHash string, ttl time.Duration) (rec Record, created bool, err error)
+	// Complete stores the response snapshot for a reserved key.
+	Complete(ctx context.Context, k Key, rec Record) error
+	// Release drops a reservation so the client can retry, e.g. after a 5xx.
+	Release(ctx context.Context, k Key) error
+	// DeleteExpired removes records past their TTL.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type postgresStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) Store {
+	return &postgresStore{pool: pool}
+}
+
+func (s *postgresStore) Begin(ctx context.Context, k Key, requestHash string, ttl time.Duration) (Record, bool, error) {
+	// Insert, or take over a row whose TTL has lapsed but was not yet cleaned up
+	query := `INSERT INTO idempotency_keys (scope, idempotency_key, route, request_hash, expires_at)
+	          VALUES ($1, $2, $3, $4, NOW() + make_interval(secs => $5))
+	          ON CONFLICT (scope, idempotency_key, route) DO UPDATE
+	          SET request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = NULL,
+	              response_body = NULL, created_at = NOW(), expires_at = EXCLUDED.expires_at
+	          WHERE idempotency_keys.expires_at < NOW()
+	          RETURNING request_hash`
+	var hash string
+	err := s.pool.QueryRow(ctx, query, k.Scope, k.Key, k.Route, requestHash, ttl.Seconds()).Scan(&hash)
+	if err == nil {
+		return Record{RequestHash: hash}, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return Record{}, false, err
+	}
+
+	query = `SELECT request_hash, COALESCE(status_code, 0), COALESCE(content_type, ''), COALESCE(response_body, ''::bytea)
+	         FROM idempotency_keys
+	         WHERE scope = $1 AND idempotency_key = $2 AND route = $3`
+	var rec Record
+	if err := s.pool.QueryRow(ctx, query, k.Scope, k.Key, k.Route).Scan(&rec.RequestHash, &rec.Status, &rec.ContentType, &rec.Body); err != nil {
+		return Record{}, false, err
+	}
+	return rec, false, nil
+}
+
+func (s *postgresStore) Complete(ctx context.Context, k Key, rec Record) error {
+	query := `UPDATE idempotency_keys SET status_code = $4, content_type = $5, response_body = $6
+	          WHERE scope = $1 AND idempotency_key = $2 AND route = $3`
+	_, err := s.pool.Exec(ctx, query, k.Scope, k.Key, k.Route, rec.Status, rec.ContentType, rec.Body)
+	return err
+}
+
+func (s *postgresStore) Release(ctx context.Context, k Key) error {
+	query := `DELETE FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2 AND route = $3`
+	_, err := s.pool.Exec(ctx, query, k.Scope, k.Key, k.Route)
+	return err
+}
+
+func (s *postgresStore) DeleteExpired(ctx context.Context) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,113 @@
+package images
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/storage"
+	"grveyard/pkg/validation"
+)
+
+type ImageHandler struct {
+	service *Service
+}
+
+func NewImageHandler(service *Service) *ImageHandler {
+	return &ImageHandler{service: service}
+}
+
+func (h *ImageHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/images/uploads", auth.Required(), h.createUpload)
+	router.POST("/images/:id/submit", auth.Required(), h.submit)
+	router.GET("/images/:id", h.getImage)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *ImageHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/images/uploads",
+			Tag:         "images",
+			Summary:     "Start an image upload",
+			Description: "Registers an avatar, asset image or logo owned by the signed-in user and returns a presigned request for uploading the original",
+			Request:     createUploadRequest{},
+			Response:    uploadResult{},
+			Status:      http.StatusCreated,
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/images/:id/submit",
+			Tag:         "images",
+			Summary:     "Process an uploaded image",
+			Description: "Queues the uploaded original; a background worker strips metadata and renders the thumb, card and full variants. Only the uploader may submit it.",
+			Params: []openapi.Param{
+				openapi.Path("id", "string", "Image ID"),
+			},
+			Response: Image{},
+			Status:   http.StatusAccepted,
+			Errors:   []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/images/:id",
+			Tag:         "images",
+			Summary:     "Get an image",
+			Description: "Processing status, plus variant download URLs once ready",
+			Params: []openapi.Param{
+				openapi.Path("id", "string", "Image ID"),
+			},
+			Response: Image{},
+			Errors:   []int{http.StatusNotFound, http.StatusInternalServerError},
+		},
+	}
+}
+
+type createUploadRequest struct {
+	Kind        Kind   `json:"kind" binding:"required,oneof=avatar asset logo"`
+	ContentType string `json:"content_type" binding:"required,oneof=image/jpeg image/png image/gif"`
+}
+
+type uploadResult struct {
+	Image  Image                    `json:"image"`
+	Upload storage.PresignedRequest `json:"upload"`
+}
+
+func (h *ImageHandler) createUpload(c *gin.Context) {
+	var req createUploadRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	img, upload, err := h.service.CreateUpload(c.Request.Context(), auth.UserID(c), req.Kind, req.ContentType)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusCreated, true, "upload created", uploadResult{Image: img, Upload: upload})
+}
+
+func (h *ImageHandler) submit(c *gin.Context) {
+	img, err := h.service.Submit(c.Request.Context(), c.Param("id"), auth.UserID(c))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusAccepted, true, "image queued for processing", img)
+}
+
+func (h *ImageHandler) getImage(c *gin.Context) {
+	img, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "image fetched", img)
+}
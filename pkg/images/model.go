@@ -0,0 +1,56 @@
+package images
+
+import "time"
+
+// Kind says what an image is for. All kinds get the same variants today.
+type Kind string
+
+const (
+	KindAvatar Kind = "avatar"
+	KindAsset  Kind = "asset"
+	KindLogo   Kind = "logo"
+)
+
+// Status tracks an image through the pipeline:
+// pending (awaiting upload) -> queued -> processing -> ready | failed.
+type Status string
+
+const (
+	StatusPending    Status = "pending"
+	StatusQueued     Status = "queued"
+	StatusProcessing Status = "processing"
+	StatusReady      Status = "ready"
+	StatusFailed     Status = "failed"
+)
+
+// Variant is a standard rendition. Crop fills the box exactly (square thumbnails);
+// otherwise the image is scaled to fit inside it, never enlarged.
+type Variant struct {
+	Name      string
+	MaxWidth  int
+	MaxHeight int
+	Crop      bool
+}
+
+var Variants = []Variant{
+	{Name: "thumb", MaxWidth: 150, MaxHeight: 150, Crop: true},
+	{Name: "card", MaxWidth: 480, MaxHeight: 480},
+	{Name: "full", MaxWidth: 1600, MaxHeight: 1600},
+}
+
+type Image struct {
+	ID        string `json:"id"`
+	OwnerUUID string `json:"owner_uuid"`
+	Kind      Kind   `json:"kind"`
+	Status    Status `json:"status"`
+	Width     int    `json:"width,omitempty"`  // of the original, after EXIF rotation
+	Height    int    `json:"height,omitempty"` // of the original, after EXIF rotation
+	Error     string `json:"error,omitempty"`
+	// Variants maps variant name to a download URL; only filled in responses
+	Variants    map[string]string `json:"variants,omitempty"`
+	SourceKey   string            `json:"-"` // where the client uploads the original
+	VariantKeys map[string]string `json:"-"` // variant name -> storage key
+	Attempts    int               `json:"-"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
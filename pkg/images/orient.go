@@ -0,0 +1,103 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+// exifOrientation returns the EXIF Orientation tag (1-8) of a JPEG, or 1 when it has
+// none. Phones store photos in sensor orientation and rely on this tag, so it must be
+// applied before the metadata is dropped.
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xDA || size < 2 || i+2+size > len(data) { // start of scan: no more metadata
+			return 1
+		}
+		segment := data[i+4 : i+2+size]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for e := 0; e < count; e++ {
+		entry := ifd + 2 + e*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if o := int(order.Uint16(tiff[entry+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// orient rotates and/or mirrors src so it displays upright for EXIF orientation o.
+func orient(src *image.RGBA, o int) *image.RGBA {
+	if o <= 1 || o > 8 {
+		return src
+	}
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dw, dh := w, h
+	if o >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch o {
+			case 2: // mirrored
+				sx, sy = w-1-x, y
+			case 3: // upside down
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored upside down
+				sx, sy = x, h-1-y
+			case 5: // mirrored, rotated 90° counter-clockwise
+				sx, sy = y, x
+			case 6: // rotated 90° counter-clockwise, so turn it clockwise
+				sx, sy = y, h-1-x
+			case 7: // mirrored, rotated 90° clockwise
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotated 90° clockwise, so turn it counter-clockwise
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], src.Pix[sy*src.Stride+sx*4:sy*src.Stride+sx*4+4])
+		}
+	}
+	return dst
+}
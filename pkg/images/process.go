@@ -0,0 +1,158 @@
+package images
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder; the first frame is used
+	"image/jpeg"
+	"image/png"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format, use JPEG, PNG or GIF")
+	ErrTooManyPixels     = errors.New("image dimensions are too large")
+	ErrInvalidImage      = errors.New("file is not a valid image")
+)
+
+const jpegQuality = 85
+
+// Rendered is one encoded variant.
+type Rendered struct {
+	Variant     Variant
+	Data        []byte
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+}
+
+// Process validates data as an image of at most maxPixels, applies its EXIF
+// orientation and renders every variant. Output is re-encoded from pixels only, so
+// EXIF (GPS position, camera serials) and other metadata never reach storage. JPEGs
+// stay JPEG; PNG and GIF become PNG to keep transparency. width and height are the
+// oriented dimensions of the original.
+func Process(data []byte, variants []Variant, maxPixels int) (out []Rendered, width, height int, err error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return nil, 0, 0, ErrUnsupportedFormat
+		}
+		return nil, 0, 0, ErrInvalidImage
+	}
+	if format != "jpeg" && format != "png" && format != "gif" {
+		return nil, 0, 0, ErrUnsupportedFormat
+	}
+	// Checked before decoding so a tiny file claiming huge dimensions cannot exhaust memory
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, 0, 0, ErrTooManyPixels
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, ErrInvalidImage
+	}
+	rgba := toRGBA(src)
+	if format == "jpeg" {
+		rgba = orient(rgba, exifOrientation(data))
+	}
+	width, height = rgba.Bounds().Dx(), rgba.Bounds().Dy()
+
+	for _, v := range variants {
+		img := render(rgba, v)
+		var buf bytes.Buffer
+		r := Rendered{Variant: v, Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+		if format == "jpeg" {
+			err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+			r.ContentType, r.Ext = "image/jpeg", "jpg"
+		} else {
+			err = png.Encode(&buf, img)
+			r.ContentType, r.Ext = "image/png", "png"
+		}
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("encode %s: %w", v.Name, err)
+		}
+		r.Data = buf.Bytes()
+		out = append(out, r)
+	}
+	return out, width, height, nil
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	return dst
+}
+
+// render crops (for Crop variants) and scales src down to v's box.
+func render(src *image.RGBA, v Variant) *image.RGBA {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	if v.Crop {
+		// Centre crop to the box's aspect ratio, then scale
+		if w*v.MaxHeight > h*v.MaxWidth {
+			cw := h * v.MaxWidth / v.MaxHeight
+			src = crop(src, image.Rect((w-cw)/2, 0, (w-cw)/2+cw, h))
+		} else {
+			ch := w * v.MaxHeight / v.MaxWidth
+			src = crop(src, image.Rect(0, (h-ch)/2, w, (h-ch)/2+ch))
+		}
+		w, h = src.Rect.Dx(), src.Rect.Dy()
+	}
+
+	dw, dh := w, h
+	if dw > v.MaxWidth {
+		dw, dh = v.MaxWidth, max(1, h*v.MaxWidth/w)
+	}
+	if dh > v.MaxHeight {
+		dw, dh = max(1, w*v.MaxHeight/h), v.MaxHeight
+	}
+	if dw == w && dh == h {
+		return src
+	}
+	return resize(src, dw, dh)
+}
+
+func crop(src *image.RGBA, r image.Rectangle) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, r.Min, draw.Src)
+	return dst
+}
+
+// resize scales src down to dw x dh by averaging the source pixels each destination
+// pixel covers (a box filter). It is only used for shrinking, where it gives smooth
+// results without the aliasing of nearest-neighbour sampling.
+func resize(src *image.RGBA, dw, dh int) *image.RGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for dy := 0; dy < dh; dy++ {
+		y0, y1 := dy*sh/dh, max((dy+1)*sh/dh, dy*sh/dh+1)
+		for dx := 0; dx < dw; dx++ {
+			x0, x1 := dx*sw/dw, max((dx+1)*sw/dw, dx*sw/dw+1)
+
+			var r, g, b, a, n int
+			for y := y0; y < y1; y++ {
+				row := src.Pix[y*src.Stride+x0*4 : y*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += int(row[i])
+					g += int(row[i+1])
+					b += int(row[i+2])
+					a += int(row[i+3])
+					n++
+				}
+			}
+			o := dy*dst.Stride + dx*4
+			dst.Pix[o] = uint8(r / n)
+			dst.Pix[o+1] = uint8(g / n)
+			dst.Pix[o+2] = uint8(b / n)
+			dst.Pix[o+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,101 @@
+package images
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// withOrientation inserts a minimal EXIF segment carrying orientation o after the SOI marker.
+func withOrientation(jpg []byte, o byte) []byte {
+	payload := []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08" +
+		"\x00\x01" + // one IFD entry
+		"\x01\x12\x00\x03\x00\x00\x00\x01\x00" + string([]byte{o}) + "\x00\x00" +
+		"\x00\x00\x00\x00") // no next IFD
+	segment := append([]byte{0xFF, 0xE1, 0, byte(len(payload) + 2)}, payload...)
+	return append(append(append([]byte{}, jpg[:2]...), segment...), jpg[2:]...)
+}
+
+func encodeJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	return buf.Bytes()
+}
+
+func TestProcess_JPEG(t *testing.T) {
+	data := withOrientation(encodeJPEG(t, 2000, 1000), 6)
+	require.Equal(t, 6, exifOrientation(data))
+
+	out, width, height, err := Process(data, Variants, 25_000_000)
+
+	require.NoError(t, err)
+	require.Equal(t, 1000, width, "rotated upright")
+	require.Equal(t, 2000, height)
+	require.Len(t, out, 3)
+
+	sizes := map[string][2]int{}
+	for _, r := range out {
+		require.Equal(t, "image/jpeg", r.ContentType)
+		require.False(t, bytes.Contains(r.Data, []byte("Exif")), "metadata stripped from %s", r.Variant.Name)
+		require.Equal(t, 1, exifOrientation(r.Data))
+		sizes[r.Variant.Name] = [2]int{r.Width, r.Height}
+	}
+	require.Equal(t, [2]int{150, 150}, sizes["thumb"])
+	require.Equal(t, [2]int{240, 480}, sizes["card"])
+	require.Equal(t, [2]int{800, 1600}, sizes["full"])
+}
+
+func TestProcess_PNGKeepsFormatAndSmallSize(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 100, 50))
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	out, _, _, err := Process(buf.Bytes(), Variants, 25_000_000)
+
+	require.NoError(t, err)
+	for _, r := range out {
+		require.Equal(t, "image/png", r.ContentType)
+	}
+	require.Equal(t, 100, out[2].Width, "never enlarged")
+}
+
+func TestProcess_Rejects(t *testing.T) {
+	_, _, _, err := Process([]byte("%PDF-1.7 not an image"), Variants, 25_000_000)
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	_, _, _, err = Process(encodeJPEG(t, 200, 200), Variants, 100*100)
+	require.ErrorIs(t, err, ErrTooManyPixels)
+
+	_, _, _, err = Process(encodeJPEG(t, 200, 200)[:300], Variants, 25_000_000)
+	require.ErrorIs(t, err, ErrInvalidImage)
+}
+
+func TestOrient(t *testing.T) {
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, red)
+	src.Set(1, 0, blue)
+
+	cw := orient(src, 6)
+	require.Equal(t, image.Rect(0, 0, 1, 2), cw.Bounds())
+	require.Equal(t, red, cw.RGBAAt(0, 0))
+	require.Equal(t, blue, cw.RGBAAt(0, 1))
+
+	ccw := orient(src, 8)
+	require.Equal(t, blue, ccw.RGBAAt(0, 0))
+
+	flipped := orient(src, 2)
+	require.Equal(t, blue, flipped.RGBAAt(0, 0))
+}
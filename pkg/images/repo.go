@@ -0,0 +1,119 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/apperr"
+)
+
+var (
+	ErrImageNotFound = apperr.New(apperr.ImageNotFound, "image not found")
+	ErrNotPending    = apperr.New(apperr.ImageAlreadySubmitted, "image has already been submitted")
+)
+
+// maxAttempts bounds retries of transient (storage) failures.
+const maxAttempts = 3
+
+type ImageRepository interface {
+	Create(ctx context.Context, img Image) (Image, error)
+	Get(ctx context.Context, id string) (Image, error)
+	// Enqueue moves a pending image to queued.
+	Enqueue(ctx context.Context, id string) (Image, error)
+	// ClaimNext marks the oldest queued image as processing and returns it. Images
+	// stuck in processing for longer than staleAfter (a crashed worker) are claimed
+	// again. ok is false when there is nothing to do.
+	ClaimNext(ctx context.Context, staleAfter time.Duration) (img Image, ok bool, err error)
+	MarkReady(ctx context.Context, id string, width, height int, variantKeys map[string]string) error
+	// MarkFailed records reason. With retry the image is queued again unless it has
+	// used up its attempts.
+	MarkFailed(ctx context.Context, id, reason string, retry bool) error
+}
+
+type postgresImageRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresImageRepository(pool *pgxpool.Pool) ImageRepository {
+	return &postgresImageRepository{pool: pool}
+}
+
+const imageColumns = `id::text, owner_uuid, kind, status, source_key, variant_keys, COALESCE(width, 0), COALESCE(height, 0), COALESCE(error, ''), attempts, created_at, updated_at`
+
+func scanImage(row pgx.Row) (Image, error) {
+	var img Image
+	err := row.Scan(&img.ID, &img.OwnerUUID, &img.Kind, &img.Status, &img.SourceKey, &img.VariantKeys,
+		&img.Width, &img.Height, &img.Error, &img.Attempts, &img.CreatedAt, &img.UpdatedAt)
+	return img, err
+}
+
+func (r *postgresImageRepository) Create(ctx context.Context, img Image) (Image, error) {
+	query := `INSERT INTO images (id, owner_uuid, kind, source_key)
+	          VALUES ($1, $2, $3, $4)
+	          RETURNING ` + imageColumns
+	return scanImage(r.pool.QueryRow(ctx, query, img.ID, img.OwnerUUID, img.Kind, img.SourceKey))
+}
+
+func (r *postgresImageRepository) Get(ctx context.Context, id string) (Image, error) {
+	query := `SELECT ` + imageColumns + ` FROM images WHERE id = $1`
+	img, err := scanImage(r.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Image{}, ErrImageNotFound
+	}
+	return img, err
+}
+
+func (r *postgresImageRepository) Enqueue(ctx context.Context, id string) (Image, error) {
+	query := `UPDATE images SET status = 'queued', updated_at = NOW()
+	          WHERE id = $1 AND status = 'pending'
+	          RETURNING ` + imageColumns
+	img, err := scanImage(r.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := r.Get(ctx, id); err != nil {
+			return Image{}, err
+		}
+		return Image{}, ErrNotPending
+	}
+	return img, err
+}
+
+func (r *postgresImageRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (Image, bool, error) {
+	query := `UPDATE images SET status = 'processing', attempts = attempts + 1, updated_at = NOW()
+	          WHERE id = (
+	              SELECT id FROM images
+	              WHERE status = 'queued'
+	                 OR (status = 'processing' AND updated_at < NOW() - make_interval(secs => $1))
+	              ORDER BY updated_at
+	              LIMIT 1
+	              FOR UPDATE SKIP LOCKED
+	          )
+	          RETURNING ` + imageColumns
+	img, err := scanImage(r.pool.QueryRow(ctx, query, staleAfter.Seconds()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Image{}, false, nil
+	}
+	if err != nil {
+		return Image{}, false, err
+	}
+	return img, true, nil
+}
+
+func (r *postgresImageRepository) MarkReady(ctx context.Context, id string, width, height int, variantKeys map[string]string) error {
+	query := `UPDATE images SET status = 'ready', width = $2, height = $3, variant_keys = $4, error = NULL, updated_at = NOW()
+	          WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, id, width, height, variantKeys)
+	return err
+}
+
+func (r *postgresImageRepository) MarkFailed(ctx context.Context, id, reason string, retry bool) error {
+	query := `UPDATE images
+	          SET status = CASE WHEN $3 AND attempts < $4 THEN 'queued' ELSE 'failed' END,
+	              error = $2, updated_at = NOW()
+	          WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, id, reason, retry, maxAttempts)
+	return err
+}
@@ -0,0 +1,260 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"grveyard/pkg/errorreport"
+	"grveyard/pkg/storage"
+)
+
+// Config tunes the pipeline.
+type Config struct {
+	MaxBytes     int64         // originals larger than this are rejected
+	MaxPixels    int           // width*height limit, checked before decoding
+	PollInterval time.Duration // how often the worker looks for queued images it was not woken for
+	StaleAfter   time.Duration // processing claims older than this are retried
+	UploadExpiry time.Duration
+	URLExpiry    time.Duration // lifetime of variant download URLs
+}
+
+// DefaultConfig accepts originals up to 25 MiB and 25 megapixels.
+func DefaultConfig() Config {
+	return Config{
+		MaxBytes:     25 << 20,
+		MaxPixels:    25_000_000,
+		PollInterval: 10 * time.Second,
+		StaleAfter:   10 * time.Minute,
+		UploadExpiry: 15 * time.Minute,
+		URLExpiry:    time.Hour,
+	}
+}
+
+// Service issues upload URLs and runs the worker that turns originals into variants.
+// Upload requests only touch the database; decoding and resizing happen on the worker.
+type Service struct {
+	repo   ImageRepository
+	store  storage.Storage
+	cfg    Config
+	wake   chan struct{}
+	wg     sync.WaitGroup
+	logger interface {
+		Printf(string, ...interface{})
+	}
+}
+
+func NewService(repo ImageRepository, store storage.Storage, cfg Config) *Service {
+	return &Service{
+		repo:   repo,
+		store:  store,
+		cfg:    cfg,
+		wake:   make(chan struct{}, 1),
+		logger: log.New(log.Writer(), "[images] ", log.LstdFlags),
+	}
+}
+
+// CreateUpload registers a pending image and returns where the client should PUT the
+// original.
+func (s *Service) CreateUpload(ctx context.Context, ownerUUID string, kind Kind, contentType string) (Image, storage.PresignedRequest, error) {
+	id := uuid.NewString()
+	img, err := s.repo.Create(ctx, Image{
+		ID:        id,
+		OwnerUUID: ownerUUID,
+		Kind:      kind,
+		SourceKey: fmt.Sprintf("uploads/%s/%s", kind, id),
+	})
+	if err != nil {
+		return Image{}, storage.PresignedRequest{}, err
+	}
+
+	upload, err := s.store.PresignUpload(ctx, img.SourceKey, contentType, s.cfg.UploadExpiry)
+	if err != nil {
+		return Image{}, storage.PresignedRequest{}, err
+	}
+	return img, upload, nil
+}
+
+// Submit queues an image ownerUUID uploaded for processing and wakes the worker.
+// Other users' images are reported as not found.
+func (s *Service) Submit(ctx context.Context, id, ownerUUID string) (Image, error) {
+	if uuid.Validate(id) != nil {
+		return Image{}, ErrImageNotFound
+	}
+	img, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return Image{}, err
+	}
+	if img.OwnerUUID != ownerUUID {
+		return Image{}, ErrImageNotFound
+	}
+	img, err = s.repo.Enqueue(ctx, id)
+	if err != nil {
+		return Image{}, err
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default: // already awake
+	}
+	return img, nil
+}
+
+// Get returns the image with download URLs for its variants once it is ready.
+func (s *Service) Get(ctx context.Context, id string) (Image, error) {
+	if uuid.Validate(id) != nil {
+		return Image{}, ErrImageNotFound
+	}
+	img, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return Image{}, err
+	}
+	if img.Status != StatusReady {
+		return img, nil
+	}
+
+	img.Variants = make(map[string]string, len(img.VariantKeys))
+	for name, key := range img.VariantKeys {
+		u, err := s.store.PresignDownload(ctx, key, s.cfg.URLExpiry)
+		if err != nil {
+			return Image{}, err
+		}
+		img.Variants[name] = u
+	}
+	return img, nil
+}
+
+// Start runs the worker until ctx is cancelled.
+func (s *Service) Start(ctx context.Context) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			// Drain the queue, then sleep until woken or the next poll
+			for {
+				processed, err := s.ProcessNext(ctx)
+				if err != nil {
+					s.logger.Printf("processing failed: %v", err)
+					if ctx.Err() == nil {
+						errorreport.CaptureError(ctx, err, errorreport.Fields{"worker": "images"})
+					}
+				}
+				if !processed || ctx.Err() != nil {
+					break
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.wake:
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Wait blocks until the worker has stopped after its context was cancelled, or ctx expires.
+func (s *Service) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// rejectedError marks problems with the upload itself; retrying will not help.
+type rejectedError struct{ error }
+
+// ProcessNext claims and processes one queued image. processed is false when the
+// queue is empty. Rejected uploads are marked failed and are not returned as errors;
+// storage and database problems are, and the image is retried later.
+func (s *Service) ProcessNext(ctx context.Context) (processed bool, err error) {
+	img, ok, err := s.repo.ClaimNext(ctx, s.cfg.StaleAfter)
+	if err != nil || !ok {
+		return false, err
+	}
+	if img.Attempts > maxAttempts {
+		return true, s.repo.MarkFailed(ctx, img.ID, "processing kept failing", false)
+	}
+
+	err = s.process(ctx, img)
+	var rejected *rejectedError
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &rejected):
+		s.logger.Printf("image %s rejected: %v", img.ID, err)
+		return true, s.repo.MarkFailed(ctx, img.ID, err.Error(), false)
+	default:
+		if markErr := s.repo.MarkFailed(ctx, img.ID, "temporary failure, will retry", true); markErr != nil {
+			s.logger.Printf("image %s: %v", img.ID, markErr)
+		}
+		return true, fmt.Errorf("image %s: %w", img.ID, err)
+	}
+}
+
+func (s *Service) process(ctx context.Context, img Image) (err error) {
+	// Decoders run on untrusted input; a panic fails this image, not the worker
+	defer func() {
+		if r := recover(); r != nil {
+			errorreport.CapturePanic(ctx, r, errorreport.Fields{"worker": "images", "image_id": img.ID})
+			err = &rejectedError{errors.New("image could not be processed")}
+		}
+	}()
+
+	rc, err := s.store.Get(ctx, img.SourceKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return &rejectedError{errors.New("no file was uploaded")}
+	}
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, s.cfg.MaxBytes+1))
+	rc.Close()
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > s.cfg.MaxBytes {
+		return &rejectedError{errors.New("file is too large")}
+	}
+
+	rendered, width, height, err := Process(data, Variants, s.cfg.MaxPixels)
+	if err != nil {
+		return &rejectedError{err}
+	}
+
+	keys := make(map[string]string, len(rendered))
+	for _, r := range rendered {
+		key := fmt.Sprintf("images/%s/%s/%s.%s", img.Kind, img.ID, r.Variant.Name, r.Ext)
+		if err := s.store.Put(ctx, key, bytes.NewReader(r.Data), int64(len(r.Data)), r.ContentType); err != nil {
+			return err
+		}
+		keys[r.Variant.Name] = key
+	}
+	if err := s.repo.MarkReady(ctx, img.ID, width, height, keys); err != nil {
+		return err
+	}
+
+	// The original still carries its EXIF data, so it is not kept
+	if err := s.store.Delete(ctx, img.SourceKey); err != nil {
+		s.logger.Printf("image %s: delete original: %v", img.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/storage"
+)
+
+type mockImageRepository struct {
+	mock.Mock
+}
+
+func (m *mockImageRepository) Create(ctx context.Context, img Image) (Image, error) {
+	args := m.Called(ctx, img)
+	out, _ := args.Get(0).(Image)
+	return out, args.Error(1)
+}
+
+func (m *mockImageRepository) Get(ctx context.Context, id string) (Image, error) {
+	args := m.Called(ctx, id)
+	out, _ := args.Get(0).(Image)
+	return out, args.Error(1)
+}
+
+func (m *mockImageRepository) Enqueue(ctx context.Context, id string) (Image, error) {
+	args := m.Called(ctx, id)
+	out, _ := args.Get(0).(Image)
+	return out, args.Error(1)
+}
+
+func (m *mockImageRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (Image, bool, error) {
+	args := m.Called(ctx, staleAfter)
+	out, _ := args.Get(0).(Image)
+	return out, args.Bool(1), args.Error(2)
+}
+
+func (m *mockImageRepository) MarkReady(ctx context.Context, id string, width, height int, variantKeys map[string]string) error {
+	return m.Called(ctx, id, width, height, variantKeys).Error(0)
+}
+
+func (m *mockImageRepository) MarkFailed(ctx context.Context, id, reason string, retry bool) error {
+	return m.Called(ctx, id, reason, retry).Error(0)
+}
+
+// memStorage is an in-memory storage.Storage.
+type memStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemStorage() *memStorage { return &memStorage{objects: map[string][]byte{}} }
+
+func (s *memStorage) Put(_ context.Context, key string, body io.Reader, _ int64, _ string) error {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = b
+	return nil
+}
+
+func (s *memStorage) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.objects[key]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (s *memStorage) PresignUpload(_ context.Context, key, contentType string, expires time.Duration) (storage.PresignedRequest, error) {
+	return storage.PresignedRequest{Method: "PUT", URL: "https://files.example.com/" + key, ExpiresAt: time.Now().Add(expires)}, nil
+}
+
+func (s *memStorage) PresignDownload(_ context.Context, key string, _ time.Duration) (string, error) {
+	return "https://files.example.com/" + key, nil
+}
+
+func (s *memStorage) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+const imageID = "7d9f2c1e-4b1a-4c55-9e0b-2a6f1c3d4e5f"
+
+func TestService_ProcessNext(t *testing.T) {
+	repo := new(mockImageRepository)
+	store := newMemStorage()
+	service := NewService(repo, store, DefaultConfig())
+
+	store.objects["uploads/avatar/"+imageID] = encodeJPEG(t, 640, 480)
+	repo.On("ClaimNext", mock.Anything, mock.Anything).
+		Return(Image{ID: imageID, Kind: KindAvatar, SourceKey: "uploads/avatar/" + imageID, Attempts: 1}, true, nil).Once()
+	repo.On("MarkReady", mock.Anything, imageID, 640, 480, map[string]string{
+		"thumb": "images/avatar/" + imageID + "/thumb.jpg",
+		"card":  "images/avatar/" + imageID + "/card.jpg",
+		"full":  "images/avatar/" + imageID + "/full.jpg",
+	}).Return(nil)
+
+	processed, err := service.ProcessNext(context.Background())
+
+	require.NoError(t, err)
+	require.True(t, processed)
+	require.Contains(t, store.objects, "images/avatar/"+imageID+"/thumb.jpg")
+	require.NotContains(t, store.objects, "uploads/avatar/"+imageID, "original removed")
+	repo.AssertExpectations(t)
+}
+
+func TestService_ProcessNext_RejectsBadUpload(t *testing.T) {
+	repo := new(mockImageRepository)
+	store := newMemStorage()
+	service := NewService(repo, store, DefaultConfig())
+
+	store.objects["uploads/logo/"+imageID] = []byte("<svg></svg>")
+	repo.On("ClaimNext", mock.Anything, mock.Anything).
+		Return(Image{ID: imageID, Kind: KindLogo, SourceKey: "uploads/logo/" + imageID, Attempts: 1}, true, nil).Once()
+	repo.On("MarkFailed", mock.Anything, imageID, ErrUnsupportedFormat.Error(), false).Return(nil)
+
+	processed, err := service.ProcessNext(context.Background())
+
+	require.NoError(t, err)
+	require.True(t, processed)
+	repo.AssertExpectations(t)
+}
+
+func TestService_ProcessNext_Empty(t *testing.T) {
+	repo := new(mockImageRepository)
+	service := NewService(repo, newMemStorage(), DefaultConfig())
+	repo.On("ClaimNext", mock.Anything, mock.Anything).Return(Image{}, false, nil)
+
+	processed, err := service.ProcessNext(context.Background())
+
+	require.NoError(t, err)
+	require.False(t, processed)
+}
+
+func TestService_Get(t *testing.T) {
+	repo := new(mockImageRepository)
+	service := NewService(repo, newMemStorage(), DefaultConfig())
+
+	_, err := service.Get(context.Background(), "not-a-uuid")
+	require.ErrorIs(t, err, ErrImageNotFound)
+
+	repo.On("Get", mock.Anything, imageID).Return(Image{
+		ID: imageID, Status: StatusReady, VariantKeys: map[string]string{"thumb": "images/asset/x/thumb.jpg"},
+	}, nil)
+	img, err := service.Get(context.Background(), imageID)
+	require.NoError(t, err)
+	require.Equal(t, "https://files.example.com/images/asset/x/thumb.jpg", img.Variants["thumb"])
+}
@@ -0,0 +1,107 @@
+package invoices
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+// DefaultRangeDays is the range of an earnings report when from/to are omitted.
+const DefaultRangeDays = 30
+
+type InvoiceHandler struct {
+	service *Service
+}
+
+func NewInvoiceHandler(service *Service) *InvoiceHandler {
+	return &InvoiceHandler{service: service}
+}
+
+func (h *InvoiceHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/transactions/:id/invoice", auth.Required(), h.invoice)
+	router.GET("/users/:uuid/earnings", auth.RequireSelf("uuid"), h.earnings)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *InvoiceHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/transactions/:id/invoice",
+			Tag:         "invoices",
+			Summary:     "Get a sale's invoice",
+			Description: "The invoice of a recorded sale with its tax breakdown: the agreed price, the GST/VAT charged under the rule for the seller's and buyer's countries, and the total. Only the buyer and seller may see it; the buyer dashboard lists the transaction IDs of purchases.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Transaction ID"),
+			},
+			Response: Invoice{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/earnings",
+			Tag:         "invoices",
+			Summary:     "Seller earnings report",
+			Description: "The seller's recorded sales over a date range, with revenue and the tax collected on top of it, totalled and broken down by month and by tax.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+				openapi.Query("from", "string", "First day, YYYY-MM-DD (default 29 days before to)", false),
+				openapi.Query("to", "string", "Last day, YYYY-MM-DD (default today)", false),
+			},
+			Response: EarningsReport{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+func (h *InvoiceHandler) invoice(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid transaction id"))
+		return
+	}
+
+	inv, err := h.service.Invoice(c.Request.Context(), id, auth.UserID(c), auth.Role(c) == auth.RoleAdmin)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "invoice fetched", inv)
+}
+
+func (h *InvoiceHandler) earnings(c *gin.Context) {
+	to := time.Now().UTC()
+	if v := c.Query("to"); v != "" {
+		parsed, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			response.SendError(c, response.InvalidField(apperr.InvalidRequest, "to", "date", "invalid to date, expected YYYY-MM-DD"))
+			return
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -(DefaultRangeDays - 1))
+	if v := c.Query("from"); v != "" {
+		parsed, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			response.SendError(c, response.InvalidField(apperr.InvalidRequest, "from", "date", "invalid from date, expected YYYY-MM-DD"))
+			return
+		}
+		from = parsed
+	}
+
+	report, err := h.service.Earnings(c.Request.Context(), c.Param("uuid"), from, to)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "earnings report fetched", report)
+}
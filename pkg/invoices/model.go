@@ -0,0 +1,60 @@
+package invoices
+
+import "time"
+
+// Invoice is the bill for one recorded sale. Subtotal is the agreed price and Total
+// adds the GST/VAT charged on top of it; TaxName is empty when none was.
+type Invoice struct {
+	Number        string    `json:"number"`
+	TransactionID int64     `json:"transaction_id"`
+	IssuedAt      time.Time `json:"issued_at"`
+	AssetID       int64     `json:"asset_id"`
+	AssetTitle    string    `json:"asset_title"`
+	Seller        Party     `json:"seller"`
+	Buyer         Party     `json:"buyer"`
+	Subtotal      float64   `json:"subtotal"`
+	TaxName       string    `json:"tax_name,omitempty"`
+	TaxRate       float64   `json:"tax_rate"`
+	TaxAmount     float64   `json:"tax_amount"`
+	Total         float64   `json:"total"`
+}
+
+// Party is the seller or buyer named on an invoice, with the country the tax rule
+// was chosen by at the time of sale.
+type Party struct {
+	UUID    string `json:"uuid"`
+	Name    string `json:"name"`
+	Country string `json:"country,omitempty"`
+}
+
+// EarningsReport sums a seller's recorded sales between From and To, inclusive.
+// Revenue is the sum of agreed prices and TaxCollected the GST/VAT charged on top,
+// which the seller owes to the tax authority; Gross is both together.
+type EarningsReport struct {
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	Sales        int64            `json:"sales"`
+	Revenue      float64          `json:"revenue"`
+	TaxCollected float64          `json:"tax_collected"`
+	Gross        float64          `json:"gross"`
+	Months       []MonthlyEarning `json:"months"`
+	Taxes        []TaxLine        `json:"taxes"`
+}
+
+// MonthlyEarning is one calendar month of a report; months without sales are left out.
+type MonthlyEarning struct {
+	Month        string  `json:"month"` // YYYY-MM
+	Sales        int64   `json:"sales"`
+	Revenue      float64 `json:"revenue"`
+	TaxCollected float64 `json:"tax_collected"`
+}
+
+// TaxLine totals the sales of a report charged under one tax and rate. Untaxed sales
+// have an empty TaxName.
+type TaxLine struct {
+	TaxName      string  `json:"tax_name"`
+	TaxRate      float64 `json:"tax_rate"`
+	Sales        int64   `json:"sales"`
+	Revenue      float64 `json:"revenue"`
+	TaxCollected float64 `json:"tax_collected"`
+}
@@ -0,0 +1,101 @@
+package invoices
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type InvoiceRepository interface {
+	// Get loads the invoice of a transaction, without its number.
+	Get(ctx context.Context, transactionID int64) (Invoice, error)
+	// Earnings groups the sales of sellerUUID's assets in [from, to) by month and
+	// by tax.
+	Earnings(ctx context.Context, sellerUUID string, from, to time.Time) ([]MonthlyEarning, []TaxLine, error)
+}
+
+type postgresInvoiceRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresInvoiceRepository(pool *pgxpool.Pool) InvoiceRepository {
+	return &postgresInvoiceRepository{pool: pool}
+}
+
+// Get names the parties as they are today; the countries are those recorded with
+// the sale.
+func (r *postgresInvoiceRepository) Get(ctx context.Context, transactionID int64) (Invoice, error) {
+	query := `
+		SELECT t.id, t.created_at, a.id, a.title,
+			s.uuid, s.name, t.seller_country, b.uuid, b.name, t.buyer_country,
+			COALESCE(t.final_price, 0)::float8, t.tax_name, t.tax_rate::float8, t.tax_amount::float8
+		FROM transactions t
+		JOIN assets a ON a.id = t.asset_id
+		JOIN users s ON s.uuid = a.user_uuid
+		JOIN users b ON b.id = t.buyer_id
+		WHERE t.id = $1`
+
+	var inv Invoice
+	err := r.pool.QueryRow(ctx, query, transactionID).Scan(&inv.TransactionID, &inv.IssuedAt, &inv.AssetID, &inv.AssetTitle,
+		&inv.Seller.UUID, &inv.Seller.Name, &inv.Seller.Country, &inv.Buyer.UUID, &inv.Buyer.Name, &inv.Buyer.Country,
+		&inv.Subtotal, &inv.TaxName, &inv.TaxRate, &inv.TaxAmount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Invoice{}, ErrInvoiceNotFound
+	}
+	return inv, err
+}
+
+func (r *postgresInvoiceRepository) Earnings(ctx context.Context, sellerUUID string, from, to time.Time) ([]MonthlyEarning, []TaxLine, error) {
+	monthsQuery := `
+		SELECT to_char(date_trunc('month', t.created_at), 'YYYY-MM'), COUNT(*),
+			COALESCE(SUM(t.final_price), 0)::float8, COALESCE(SUM(t.tax_amount), 0)::float8
+		FROM transactions t
+		JOIN assets a ON a.id = t.asset_id
+		WHERE a.user_uuid = $1 AND t.created_at >= $2 AND t.created_at < $3
+		GROUP BY 1
+		ORDER BY 1`
+	rows, err := r.pool.Query(ctx, monthsQuery, sellerUUID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	months := []MonthlyEarning{}
+	for rows.Next() {
+		var m MonthlyEarning
+		if err := rows.Scan(&m.Month, &m.Sales, &m.Revenue, &m.TaxCollected); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		months = append(months, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	taxesQuery := `
+		SELECT t.tax_name, t.tax_rate::float8, COUNT(*),
+			COALESCE(SUM(t.final_price), 0)::float8, COALESCE(SUM(t.tax_amount), 0)::float8
+		FROM transactions t
+		JOIN assets a ON a.id = t.asset_id
+		WHERE a.user_uuid = $1 AND t.created_at >= $2 AND t.created_at < $3
+		GROUP BY t.tax_name, t.tax_rate
+		ORDER BY t.tax_name, t.tax_rate`
+	rows, err = r.pool.Query(ctx, taxesQuery, sellerUUID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	taxes := []TaxLine{}
+	for rows.Next() {
+		var l TaxLine
+		if err := rows.Scan(&l.TaxName, &l.TaxRate, &l.Sales, &l.Revenue, &l.TaxCollected); err != nil {
+			return nil, nil, err
+		}
+		taxes = append(taxes, l)
+	}
+	return months, taxes, rows.Err()
+}
@@ -0,0 +1,74 @@
+package invoices
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresInvoiceRepository_Get(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresInvoiceRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	buyer := testhelpers.NewUser(t, pool)
+	asset := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetSold())
+	tx := testhelpers.NewTransaction(t, pool, testhelpers.WithTransactionAsset(asset.ID), testhelpers.WithBuyer(buyer.ID),
+		testhelpers.WithFinalPrice(200), testhelpers.WithTax("VAT", 20, 40))
+
+	inv, err := repo.Get(ctx, tx.ID)
+	require.NoError(t, err)
+	require.Equal(t, asset.Title, inv.AssetTitle)
+	require.Equal(t, seller.UUID, inv.Seller.UUID)
+	require.Equal(t, buyer.UUID, inv.Buyer.UUID)
+	require.Equal(t, 200.0, inv.Subtotal)
+	require.Equal(t, "VAT", inv.TaxName)
+	require.Equal(t, 40.0, inv.TaxAmount)
+
+	_, err = repo.Get(ctx, tx.ID+1000000)
+	require.ErrorIs(t, err, ErrInvoiceNotFound)
+}
+
+func TestPostgresInvoiceRepository_Earnings(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresInvoiceRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	sale := func(price, tax float64, at time.Time) {
+		asset := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetSold())
+		name := ""
+		if tax > 0 {
+			name = "GST"
+		}
+		tx := testhelpers.NewTransaction(t, pool, testhelpers.WithTransactionAsset(asset.ID), testhelpers.WithFinalPrice(price),
+			testhelpers.WithTax(name, tax/price*100, tax))
+		_, err := pool.Exec(ctx, `UPDATE transactions SET created_at = $2 WHERE id = $1`, tx.ID, at)
+		require.NoError(t, err)
+	}
+	sale(100, 18, time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC))
+	sale(50, 9, time.Date(2026, 2, 3, 12, 0, 0, 0, time.UTC))
+	sale(30, 0, time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC))
+	sale(70, 0, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)) // after the range
+	testhelpers.NewTransaction(t, pool, testhelpers.WithFinalPrice(999))
+
+	months, taxes, err := repo.Earnings(ctx, seller.UUID, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, []MonthlyEarning{
+		{Month: "2026-01", Sales: 1, Revenue: 100, TaxCollected: 18},
+		{Month: "2026-02", Sales: 2, Revenue: 80, TaxCollected: 9},
+	}, months)
+	require.Equal(t, []TaxLine{
+		{TaxName: "", TaxRate: 0, Sales: 1, Revenue: 30, TaxCollected: 0},
+		{TaxName: "GST", TaxRate: 18, Sales: 2, Revenue: 150, TaxCollected: 27},
+	}, taxes)
+}
@@ -0,0 +1,73 @@
+package invoices
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"grveyard/pkg/apperr"
+)
+
+// MaxRangeDays bounds a single earnings report, like /admin/stats.
+const MaxRangeDays = 366
+
+var (
+	ErrInvoiceNotFound = apperr.New(apperr.InvoiceNotFound, "invoice not found")
+	ErrNotParty        = apperr.New(apperr.Forbidden, "only the buyer and seller can see an invoice")
+	ErrInvalidRange    = apperr.New(apperr.InvalidDateRange, "invalid date range")
+)
+
+type Service struct {
+	repo InvoiceRepository
+}
+
+func NewService(repo InvoiceRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Invoice returns the invoice of a transaction to its buyer or seller, or to anyone
+// when anyUser is set.
+func (s *Service) Invoice(ctx context.Context, transactionID int64, userUUID string, anyUser bool) (Invoice, error) {
+	inv, err := s.repo.Get(ctx, transactionID)
+	if err != nil {
+		return Invoice{}, err
+	}
+	if !anyUser && userUUID != inv.Seller.UUID && userUUID != inv.Buyer.UUID {
+		return Invoice{}, ErrNotParty
+	}
+	inv.Number = InvoiceNumber(inv.TransactionID)
+	inv.Total = round(inv.Subtotal + inv.TaxAmount)
+	return inv, nil
+}
+
+// InvoiceNumber is the number printed on the invoice of a transaction.
+func InvoiceNumber(transactionID int64) string {
+	return fmt.Sprintf("INV-%06d", transactionID)
+}
+
+// Earnings reports sellerUUID's sales from the start of from to the end of to.
+func (s *Service) Earnings(ctx context.Context, sellerUUID string, from, to time.Time) (EarningsReport, error) {
+	from, to = from.UTC().Truncate(24*time.Hour), to.UTC().Truncate(24*time.Hour)
+	if to.Before(from) || to.Sub(from) >= MaxRangeDays*24*time.Hour {
+		return EarningsReport{}, ErrInvalidRange
+	}
+
+	months, taxes, err := s.repo.Earnings(ctx, sellerUUID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return EarningsReport{}, err
+	}
+	report := EarningsReport{From: from.Format(time.DateOnly), To: to.Format(time.DateOnly), Months: months, Taxes: taxes}
+	for _, m := range months {
+		report.Sales += m.Sales
+		report.Revenue += m.Revenue
+		report.TaxCollected += m.TaxCollected
+	}
+	report.Revenue, report.TaxCollected = round(report.Revenue), round(report.TaxCollected)
+	report.Gross = round(report.Revenue + report.TaxCollected)
+	return report, nil
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,108 @@
+package invoices
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+type mockInvoiceRepository struct {
+	mock.Mock
+}
+
+func (m *mockInvoiceRepository) Get(ctx context.Context, transactionID int64) (Invoice, error) {
+	args := m.Called(ctx, transactionID)
+	return args.Get(0).(Invoice), args.Error(1)
+}
+
+func (m *mockInvoiceRepository) Earnings(ctx context.Context, sellerUUID string, from, to time.Time) ([]MonthlyEarning, []TaxLine, error) {
+	args := m.Called(ctx, sellerUUID, from, to)
+	months, _ := args.Get(0).([]MonthlyEarning)
+	taxes, _ := args.Get(1).([]TaxLine)
+	return months, taxes, args.Error(2)
+}
+
+func TestService_Invoice(t *testing.T) {
+	repo := new(mockInvoiceRepository)
+	repo.On("Get", mock.Anything, int64(42)).Return(Invoice{
+		TransactionID: 42,
+		Seller:        Party{UUID: "seller"},
+		Buyer:         Party{UUID: "buyer"},
+		Subtotal:      75,
+		TaxName:       "GST",
+		TaxRate:       18,
+		TaxAmount:     13.5,
+	}, nil)
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	inv, err := svc.Invoice(ctx, 42, "buyer", false)
+	require.NoError(t, err)
+	require.Equal(t, "INV-000042", inv.Number)
+	require.Equal(t, 88.5, inv.Total)
+
+	_, err = svc.Invoice(ctx, 42, "seller", false)
+	require.NoError(t, err)
+	_, err = svc.Invoice(ctx, 42, "someone-else", false)
+	require.ErrorIs(t, err, ErrNotParty)
+	_, err = svc.Invoice(ctx, 42, "admin-uuid", true)
+	require.NoError(t, err)
+}
+
+func TestService_Earnings(t *testing.T) {
+	repo := new(mockInvoiceRepository)
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+	repo.On("Earnings", mock.Anything, "seller", from, to.AddDate(0, 0, 1)).Return(
+		[]MonthlyEarning{{Month: "2026-01", Sales: 1, Revenue: 100.1, TaxCollected: 18.02}, {Month: "2026-02", Sales: 2, Revenue: 80.2, TaxCollected: 9}},
+		[]TaxLine{{TaxName: "GST", TaxRate: 18, Sales: 3, Revenue: 180.3, TaxCollected: 27.02}}, nil)
+	svc := NewService(repo)
+
+	report, err := svc.Earnings(context.Background(), "seller", from.Add(5*time.Hour), to)
+	require.NoError(t, err)
+	require.Equal(t, "2026-01-01", report.From)
+	require.Equal(t, "2026-02-28", report.To)
+	require.Equal(t, int64(3), report.Sales)
+	require.Equal(t, 180.3, report.Revenue)
+	require.Equal(t, 27.02, report.TaxCollected)
+	require.Equal(t, 207.32, report.Gross)
+	require.Len(t, report.Taxes, 1)
+
+	_, err = svc.Earnings(context.Background(), "seller", to, from)
+	require.ErrorIs(t, err, ErrInvalidRange)
+	_, err = svc.Earnings(context.Background(), "seller", from, from.AddDate(0, 0, MaxRangeDays))
+	require.ErrorIs(t, err, ErrInvalidRange)
+}
+
+func TestInvoiceHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(mockInvoiceRepository)
+	repo.On("Get", mock.Anything, int64(7)).Return(Invoice{TransactionID: 7, Seller: Party{UUID: "seller"}, Buyer: Party{UUID: "buyer"}, Subtotal: 10}, nil)
+	repo.On("Get", mock.Anything, int64(8)).Return(Invoice{}, ErrInvoiceNotFound)
+	r := gin.New()
+	r.Use(testhelpers.AuthAs("buyer", "buyer"))
+	NewInvoiceHandler(NewService(repo)).RegisterRoutes(r)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	w := get("/transactions/7/invoice")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"number":"INV-000007"`)
+	require.Equal(t, http.StatusNotFound, get("/transactions/8/invoice").Code)
+	require.Equal(t, http.StatusBadRequest, get("/transactions/abc/invoice").Code)
+
+	// Earnings are the seller's own
+	require.Equal(t, http.StatusForbidden, get("/users/seller/earnings").Code)
+	require.Equal(t, http.StatusBadRequest, get("/users/buyer/earnings?from=yesterday").Code)
+}
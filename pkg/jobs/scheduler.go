@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"grveyard/pkg/errorreport"
+)
+
+// Func is a unit of background work. It receives a context that is cancelled on shutdown.
+type Func func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       Func
+}
+
+// Scheduler runs registered jobs on fixed intervals until its context is cancelled.
+type Scheduler struct {
+	mu     sync.Mutex
+	jobs   []job
+	wg     sync.WaitGroup
+	logger interface {
+		Printf(string, ...interface{})
+	}
+}
+
+// NewScheduler creates an empty scheduler
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		logger: log.New(log.Writer(), "[jobs] ", log.LstdFlags),
+	}
+}
+
+// Every registers fn to run once per interval. Must be called before Start.
+func (s *Scheduler) Every(name string, interval time.Duration, fn Func) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job{name: name, interval: interval, fn: fn})
+}
+
+// Start launches one goroutine per registered job. Jobs stop when ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs {
+		if j.interval <= 0 {
+			s.logger.Printf("job %s has non-positive interval, skipping", j.name)
+			continue
+		}
+		s.wg.Add(1)
+		go s.run(ctx, j)
+	}
+}
+
+// Wait blocks until every job goroutine has returned (i.e. after the Start context is
+// cancelled and any in-flight run finishes) or ctx expires.
+func (s *Scheduler) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, j)
+		}
+	}
+}
+
+// runOnce runs j a single time. Failures and panics are logged and reported; a panic
+// does not stop the job from running again on the next tick.
+func (s *Scheduler) runOnce(ctx context.Context, j job) {
+	fields := errorreport.Fields{"job": j.name}
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Printf("job %s panicked: %v", j.name, r)
+			errorreport.CapturePanic(ctx, r, fields)
+		}
+	}()
+
+	if err := j.fn(ctx); err != nil {
+		s.logger.Printf("job %s failed: %v", j.name, err)
+		if ctx.Err() == nil {
+			errorreport.CaptureError(ctx, err, fields)
+		}
+	}
+}
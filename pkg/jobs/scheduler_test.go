@@ -0,0 +1,26 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_RecoversPanickingJob(t *testing.T) {
+	var runs atomic.Int32
+	s := NewScheduler()
+	s.Every("flaky", 5*time.Millisecond, func(context.Context) error {
+		runs.Add(1)
+		panic("boom")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	require.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	require.NoError(t, s.Wait(context.Background()))
+}
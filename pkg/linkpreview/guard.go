@@ -0,0 +1,84 @@
+package linkpreview
+
+import (
+	"errors"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+)
+
+var (
+	errInvalid = errors.New("invalid URL")
+	errScheme  = errors.New("only http and https URLs can be previewed")
+	errPort    = errors.New("only ports 80 and 443 can be previewed")
+	errAddress = errors.New("URL resolves to a private or reserved address")
+)
+
+// blocked lists ranges that are not publicly routable but that netip's Is* helpers
+// do not cover.
+var blocked = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // TEST-NET-1
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // TEST-NET-2
+	netip.MustParsePrefix("203.0.113.0/24"),  // TEST-NET-3
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, can reach IPv4 internals
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+}
+
+// checkURL rejects URLs the scraper must never request, before any DNS lookup.
+func checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errScheme
+	}
+	if u.Hostname() == "" || u.User != nil {
+		return errInvalid
+	}
+	if p := u.Port(); p != "" && p != "80" && p != "443" {
+		return errPort
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return errAddress
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !isPublic(addr) {
+		return errAddress
+	}
+	return nil
+}
+
+// isPublic reports whether addr is a globally routable unicast address.
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() {
+		return false
+	}
+	for _, p := range blocked {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowDial is the default connect-time check: public addresses on ports 80 and 443.
+func allowDial(addr netip.AddrPort) bool {
+	return (addr.Port() == 80 || addr.Port() == 443) && isPublic(addr.Addr())
+}
+
+// dialControl runs after DNS resolution for every connection, including those made
+// while following redirects, so a hostname that resolves (or re-resolves) to an
+// internal address is refused at connect time.
+func dialControl(allow func(netip.AddrPort) bool) func(network, address string, _ syscall.RawConn) error {
+	return func(network, address string, _ syscall.RawConn) error {
+		addr, err := netip.ParseAddrPort(address)
+		if err != nil || !allow(addr) {
+			return errAddress
+		}
+		return nil
+	}
+}
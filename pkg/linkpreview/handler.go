@@ -0,0 +1,58 @@
+package linkpreview
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+// PreviewRequest is the URL to preview.
+type PreviewRequest struct {
+	URL string `json:"url" binding:"required,url,max=2048"`
+}
+
+type LinkPreviewHandler struct {
+	service *Service
+}
+
+func NewLinkPreviewHandler(service *Service) *LinkPreviewHandler {
+	return &LinkPreviewHandler{service: service}
+}
+
+func (h *LinkPreviewHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/utils/link-preview", h.preview)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *LinkPreviewHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/utils/link-preview",
+			Tag:         "utils",
+			Summary:     "Link preview metadata",
+			Description: "Fetches the page server-side and returns its OpenGraph or Twitter card title, description and image, falling back to <title>. Only public http(s) URLs on the default ports are fetched; results are cached for an hour.",
+			Request:     PreviewRequest{},
+			Response:    Preview{},
+			Errors:      []int{http.StatusBadRequest, http.StatusBadGateway, http.StatusInternalServerError},
+		},
+	}
+}
+
+func (h *LinkPreviewHandler) preview(c *gin.Context) {
+	var req PreviewRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	p, err := h.service.Preview(c.Request.Context(), req.URL)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "link preview fetched", p)
+}
@@ -0,0 +1,208 @@
+// Package linkpreview fetches OpenGraph and Twitter card metadata for URLs that
+// sellers paste into listings and chat, so clients can render rich previews without
+// requesting third-party pages themselves.
+package linkpreview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	fetchTimeout = 5 * time.Second
+	maxRedirects = 5
+	maxBodyBytes = 512 << 10 // metadata lives in <head>; the rest is never read
+	maxTextLen   = 500
+	userAgent    = "GraveyardLinkPreview/1.0"
+)
+
+// Preview is the metadata of a page. Fields the page does not declare are empty.
+type Preview struct {
+	URL         string `json:"url"` // after redirects
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+// Fetcher requests pages for previews. Only http and https on the default ports are
+// allowed, and every connection, including redirects, is refused if the host
+// resolves to a private, loopback, link-local or otherwise reserved address.
+type Fetcher struct {
+	client *http.Client
+	check  func(*url.URL) error
+}
+
+func NewFetcher() *Fetcher {
+	return newFetcher(checkURL, allowDial)
+}
+
+func newFetcher(check func(*url.URL) error, allow func(netip.AddrPort) bool) *Fetcher {
+	dialer := &net.Dialer{Timeout: fetchTimeout, Control: dialControl(allow)}
+	transport := &http.Transport{
+		Proxy:                 nil, // a proxy would connect on our behalf, bypassing the dial check
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   fetchTimeout,
+		ResponseHeaderTimeout: fetchTimeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+	return &Fetcher{
+		check: check,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   fetchTimeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return errors.New("too many redirects")
+				}
+				return check(req.URL)
+			},
+		},
+	}
+}
+
+// Fetch downloads rawURL and extracts its preview metadata.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (Preview, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Preview{}, fmt.Errorf("%w: %v", errInvalid, err)
+	}
+	if err := f.check(u); err != nil {
+		return Preview{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return Preview{}, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return Preview{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Preview{}, fmt.Errorf("fetch %s: status %d", u.Redacted(), resp.StatusCode)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return Preview{}, fmt.Errorf("fetch %s: not an HTML page (%s)", u.Redacted(), mediaType)
+	}
+
+	return parse(io.LimitReader(resp.Body, maxBodyBytes), resp.Request.URL), nil
+}
+
+// parse reads meta tags from the document head. og: properties win over twitter:
+// names, which win over <title> and the plain description meta tag.
+func parse(r io.Reader, base *url.URL) Preview {
+	meta := map[string]string{}
+	var title string
+
+	z := html.NewTokenizer(r)
+	inTitle := false
+loop:
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			break loop
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "body":
+				break loop
+			case "title":
+				inTitle = true
+			case "meta":
+				if !hasAttr {
+					continue
+				}
+				var key, content string
+				for {
+					k, v, more := z.TagAttr()
+					switch string(k) {
+					case "property", "name":
+						if key == "" {
+							key = strings.ToLower(strings.TrimSpace(string(v)))
+						}
+					case "content":
+						content = strings.TrimSpace(string(v))
+					}
+					if !more {
+						break
+					}
+				}
+				if _, seen := meta[key]; key != "" && content != "" && !seen {
+					meta[key] = content
+				}
+			}
+		case html.TextToken:
+			if inTitle && title == "" {
+				title = strings.TrimSpace(string(z.Text()))
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				break loop
+			}
+		}
+	}
+
+	first := func(keys ...string) string {
+		for _, k := range keys {
+			if v := meta[k]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	return Preview{
+		URL:         base.String(),
+		Title:       truncate(first("og:title", "twitter:title"), title),
+		Description: truncate(first("og:description", "twitter:description", "description"), ""),
+		Image:       resolve(base, first("og:image", "og:image:url", "twitter:image", "twitter:image:src")),
+		SiteName:    truncate(first("og:site_name"), ""),
+		Type:        truncate(first("og:type"), ""),
+	}
+}
+
+func truncate(s, fallback string) string {
+	if s == "" {
+		s = fallback
+	}
+	if r := []rune(s); len(r) > maxTextLen {
+		return string(r[:maxTextLen])
+	}
+	return s
+}
+
+// resolve makes a relative image URL absolute and drops anything but http(s), so a
+// page cannot make clients load javascript: or data: URLs.
+func resolve(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	u, err := base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
@@ -0,0 +1,112 @@
+package linkpreview
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testFetcher skips the address checks so it can reach httptest servers on loopback.
+func testFetcher() *Fetcher {
+	return newFetcher(func(*url.URL) error { return nil }, func(netip.AddrPort) bool { return true })
+}
+
+func TestParse(t *testing.T) {
+	base, _ := url.Parse("https://shop.example.com/p/1")
+	doc := `<!doctype html><html><head>
+		<title>Fallback title</title>
+		<meta name="description" content="Plain description">
+		<meta content="OG Title" property="og:title">
+		<meta name="twitter:title" content="Twitter Title">
+		<meta property="og:image" content="/img/cover.png">
+		<meta property="og:site_name" content="Shop">
+		</head><body><meta property="og:description" content="ignored, in body"></body></html>`
+
+	p := parse(strings.NewReader(doc), base)
+
+	require.Equal(t, Preview{
+		URL:         "https://shop.example.com/p/1",
+		Title:       "OG Title",
+		Description: "Plain description",
+		Image:       "https://shop.example.com/img/cover.png",
+		SiteName:    "Shop",
+	}, p)
+}
+
+func TestParse_FallbacksAndUnsafeImage(t *testing.T) {
+	base, _ := url.Parse("https://example.com/")
+	doc := `<html><head><title> Just a title </title>
+		<meta name="twitter:image" content="javascript:alert(1)"></head></html>`
+
+	p := parse(strings.NewReader(doc), base)
+
+	require.Equal(t, "Just a title", p.Title)
+	require.Empty(t, p.Image)
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/new":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(`<head><meta property="og:title" content="Moved"></head>`))
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	f := testFetcher()
+
+	p, err := f.Fetch(context.Background(), srv.URL+"/old")
+	require.NoError(t, err)
+	require.Equal(t, "Moved", p.Title)
+	require.Equal(t, srv.URL+"/new", p.URL)
+
+	_, err = f.Fetch(context.Background(), srv.URL+"/json")
+	require.ErrorContains(t, err, "not an HTML page")
+
+	_, err = f.Fetch(context.Background(), srv.URL+"/missing")
+	require.ErrorContains(t, err, "status 404")
+}
+
+func TestFetcher_RefusesPrivateAddressAtConnect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("private address was reached")
+	}))
+	defer srv.Close()
+	// Only the dial check is active, as if a public hostname resolved to loopback
+	f := newFetcher(func(*url.URL) error { return nil }, allowDial)
+
+	_, err := f.Fetch(context.Background(), srv.URL)
+
+	require.ErrorIs(t, err, errAddress)
+}
+
+func TestFetcher_RefusesRedirectToOtherScheme(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+	}))
+	defer srv.Close()
+	allowOrigin := func(u *url.URL) error {
+		if u.Scheme != "http" {
+			return errScheme
+		}
+		return nil
+	}
+	f := newFetcher(allowOrigin, func(netip.AddrPort) bool { return true })
+
+	_, err := f.Fetch(context.Background(), srv.URL)
+
+	require.ErrorIs(t, err, errScheme)
+}
@@ -0,0 +1,95 @@
+package linkpreview
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/requestid"
+)
+
+const (
+	cacheTTL     = time.Hour
+	cacheEntries = 1000
+)
+
+var (
+	ErrInvalidURL  = apperr.New(apperr.InvalidPreviewURL, "url must be a public http or https address")
+	ErrUnavailable = apperr.New(apperr.PreviewUnavailable, "link preview unavailable")
+)
+
+// PreviewFetcher is what Service fetches pages with; Fetcher in production.
+type PreviewFetcher interface {
+	Fetch(ctx context.Context, rawURL string) (Preview, error)
+}
+
+type cached struct {
+	preview Preview
+	expires time.Time
+}
+
+// Service caches previews in memory for an hour, so a link shared in a busy chat or
+// shown on a popular listing is only fetched once. Failures are not cached.
+type Service struct {
+	fetcher PreviewFetcher
+	now     func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cached
+}
+
+func NewService(fetcher PreviewFetcher) *Service {
+	return &Service{fetcher: fetcher, now: time.Now, cache: map[string]cached{}}
+}
+
+// Preview returns the metadata of rawURL, fetching it unless a fresh copy is cached.
+func (s *Service) Preview(ctx context.Context, rawURL string) (Preview, error) {
+	key := strings.TrimSpace(rawURL)
+	if p, ok := s.lookup(key); ok {
+		return p, nil
+	}
+
+	p, err := s.fetcher.Fetch(ctx, key)
+	if err != nil {
+		if errors.Is(err, errScheme) || errors.Is(err, errPort) || errors.Is(err, errAddress) || errors.Is(err, errInvalid) {
+			return Preview{}, ErrInvalidURL
+		}
+		log.Printf("[%s] link preview of %q failed: %v", requestid.FromContext(ctx), key, err)
+		return Preview{}, ErrUnavailable
+	}
+	s.store(key, p)
+	return p, nil
+}
+
+func (s *Service) lookup(key string) (Preview, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.cache[key]
+	if !ok || !s.now().Before(c.expires) {
+		return Preview{}, false
+	}
+	return c.preview, true
+}
+
+// store evicts expired entries once the cache is full, and everything if that is not
+// enough; previews are cheap to refetch.
+func (s *Service) store(key string, p Preview) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if len(s.cache) >= cacheEntries {
+		for k, c := range s.cache {
+			if !now.Before(c.expires) {
+				delete(s.cache, k)
+			}
+		}
+		if len(s.cache) >= cacheEntries {
+			clear(s.cache)
+		}
+	}
+	s.cache[key] = cached{preview: p, expires: now.Add(cacheTTL)}
+}
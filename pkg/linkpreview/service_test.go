@@ -0,0 +1,57 @@
+package linkpreview
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockFetcher struct {
+	mock.Mock
+}
+
+func (m *mockFetcher) Fetch(ctx context.Context, rawURL string) (Preview, error) {
+	args := m.Called(ctx, rawURL)
+	return args.Get(0).(Preview), args.Error(1)
+}
+
+func TestService_Preview_Caches(t *testing.T) {
+	f := new(mockFetcher)
+	f.On("Fetch", mock.Anything, "https://example.com").Return(Preview{URL: "https://example.com", Title: "Example"}, nil).Once()
+	s := NewService(f)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	for range 2 {
+		p, err := s.Preview(context.Background(), " https://example.com ")
+		require.NoError(t, err)
+		require.Equal(t, "Example", p.Title)
+	}
+	f.AssertNumberOfCalls(t, "Fetch", 1)
+
+	now = now.Add(cacheTTL)
+	f.On("Fetch", mock.Anything, "https://example.com").Return(Preview{Title: "Changed"}, nil).Once()
+	p, err := s.Preview(context.Background(), "https://example.com")
+	require.NoError(t, err)
+	require.Equal(t, "Changed", p.Title)
+}
+
+func TestService_Preview_Errors(t *testing.T) {
+	f := new(mockFetcher)
+	f.On("Fetch", mock.Anything, "http://10.0.0.1").Return(Preview{}, errAddress)
+	f.On("Fetch", mock.Anything, "https://down.example.com").Return(Preview{}, errors.New("connection refused"))
+	s := NewService(f)
+
+	_, err := s.Preview(context.Background(), "http://10.0.0.1")
+	require.ErrorIs(t, err, ErrInvalidURL)
+
+	_, err = s.Preview(context.Background(), "https://down.example.com")
+	require.ErrorIs(t, err, ErrUnavailable)
+	_, err = s.Preview(context.Background(), "https://down.example.com")
+	require.ErrorIs(t, err, ErrUnavailable)
+	f.AssertNumberOfCalls(t, "Fetch", 3)
+}
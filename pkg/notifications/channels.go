@@ -0,0 +1,151 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+
+	"grveyard/pkg/i18n"
+	sendemail "grveyard/pkg/sendemail"
+)
+
+// Channel delivers an event to a recipient over one medium.
+type Channel interface {
+	Name() string
+	Enabled(p Preferences, t EventType) bool
+	Deliver(ctx context.Context, r Recipient, ev Event) error
+}
+
+type emailChannel struct {
+	es     sendemail.EmailService
+	signer *UnsubscribeSigner // optional; nil omits unsubscribe links
+}
+
+func NewEmailChannel(es sendemail.EmailService, signer *UnsubscribeSigner) Channel {
+	return &emailChannel{es: es, signer: signer}
+}
+
+func (c *emailChannel) Name() string { return "email" }
+
+func (c *emailChannel) Enabled(p Preferences, t EventType) bool {
+	switch t {
+	case EventMessageReceived:
+		return p.EmailOnMessage
+	case EventOfferReceived:
+		return p.EmailOnOffer
+	case EventAssetSold:
+		return p.EmailOnSale
+	case EventPurchaseReceipt:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *emailChannel) Deliver(ctx context.Context, r Recipient, ev Event) error {
+	if r.Email == "" {
+		return nil
+	}
+	key := "email." + string(ev.Type)
+	body := eventBody(r.Locale, ev)
+	subject := i18n.T(r.Locale, key+".subject")
+	plainTextContent := body
+	var link string
+	if ev.Type != EventPurchaseReceipt {
+		link = c.signer.URL(r.UUID, categoryFor(ev.Type))
+	}
+	if link != "" {
+		plainTextContent += "\n\n" + i18n.T(r.Locale, "email.unsubscribe.text", link)
+	}
+	htmlContent, err := sendemail.RenderHTML("notification.html", struct {
+		Subject, Body string
+		Unsubscribe   *sendemail.UnsubscribeLink
+	}{subject, body, UnsubscribeFooter(r.Locale, link)})
+	if err != nil {
+		return err
+	}
+
+	err = c.es.SendEmail(ctx, subject, r.Email, plainTextContent, htmlContent)
+	if errors.Is(err, sendemail.ErrSuppressed) {
+		return nil
+	}
+	return err
+}
+
+// UnsubscribeFooter is the unsubscribe link appended to outgoing emails, or nil when
+// there is no link.
+func UnsubscribeFooter(locale, link string) *sendemail.UnsubscribeLink {
+	if link == "" {
+		return nil
+	}
+	return &sendemail.UnsubscribeLink{URL: link, Label: i18n.T(locale, "email.unsubscribe.link")}
+}
+
+func eventBody(locale string, ev Event) string {
+	key := "email." + string(ev.Type) + ".body"
+	switch ev.Type {
+	case EventOfferReceived:
+		return i18n.T(locale, key, ev.Amount, ev.Title)
+	case EventPurchaseReceipt:
+		return i18n.T(locale, key, ev.Title, ev.Amount, ev.EntityID)
+	default:
+		return i18n.T(locale, key, ev.Title)
+	}
+}
+
+// Broadcaster is satisfied by chat.ConnectionManager.
+type Broadcaster interface {
+	IsOnline(userID string) bool
+	BroadcastToUser(userID string, message interface{}) error
+}
+
+// PushNotification is the WebSocket frame sent by the push channel.
+type PushNotification struct {
+	EventType string `json:"event_type"` // always "notification"
+	Event     Event  `json:"event"`
+	Text      string `json:"text"`
+}
+
+type pushChannel struct {
+	broadcaster Broadcaster
+}
+
+// NewPushChannel pushes events to users with a live chat connection. Offline users are skipped.
+func NewPushChannel(b Broadcaster) Channel {
+	return &pushChannel{broadcaster: b}
+}
+
+func (c *pushChannel) Name() string { return "push" }
+
+func (c *pushChannel) Enabled(p Preferences, t EventType) bool {
+	return p.PushEnabled
+}
+
+func (c *pushChannel) Deliver(ctx context.Context, r Recipient, ev Event) error {
+	if !c.broadcaster.IsOnline(r.UUID) {
+		return nil
+	}
+	return c.broadcaster.BroadcastToUser(r.UUID, PushNotification{
+		EventType: "notification",
+		Event:     ev,
+		Text:      eventBody(r.Locale, ev),
+	})
+}
+
+type inAppChannel struct {
+	repo InboxRepository
+}
+
+// NewInAppChannel records every event in the recipient's in-app inbox, regardless of
+// their email and push preferences.
+func NewInAppChannel(repo InboxRepository) Channel {
+	return &inAppChannel{repo: repo}
+}
+
+func (c *inAppChannel) Name() string { return "in_app" }
+
+func (c *inAppChannel) Enabled(p Preferences, t EventType) bool { return true }
+
+func (c *inAppChannel) Deliver(ctx context.Context, r Recipient, ev Event) error {
+	ev.RecipientUUID = r.UUID
+	return c.repo.Add(ctx, ev)
+}
@@ -0,0 +1,82 @@
+package notifications
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+// UnsubscribeHandler serves the unsubscribe links embedded in outgoing emails. The
+// signed token is the only credential, so no login is required.
+type UnsubscribeHandler struct {
+	signer *UnsubscribeSigner
+	repo   PreferencesRepository
+}
+
+func NewUnsubscribeHandler(signer *UnsubscribeSigner, repo PreferencesRepository) *UnsubscribeHandler {
+	return &UnsubscribeHandler{signer: signer, repo: repo}
+}
+
+func (h *UnsubscribeHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/email/unsubscribe", h.unsubscribe)
+	// RFC 8058 one-click unsubscribe posts to the same URL
+	router.POST("/email/unsubscribe", h.unsubscribe)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *UnsubscribeHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/email/unsubscribe",
+			Tag:         "email",
+			Summary:     "Unsubscribe from emails",
+			Description: "Turns off the email category encoded in a signed unsubscribe token",
+			Params: []openapi.Param{
+				openapi.Query("token", "string", "Signed unsubscribe token", true),
+			},
+			Response: unsubscribeResult{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/email/unsubscribe",
+			Tag:         "email",
+			Summary:     "One-click unsubscribe",
+			Description: "RFC 8058 one-click unsubscribe; mail clients post the List-Unsubscribe URL with a form body",
+			Params: []openapi.Param{
+				openapi.Query("token", "string", "Signed unsubscribe token", true),
+			},
+			Response: unsubscribeResult{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+	}
+}
+
+type unsubscribeResult struct {
+	Category Category `json:"category"`
+}
+
+func (h *UnsubscribeHandler) unsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		response.SendError(c, response.InvalidField(apperr.InvalidRequest, "token", "required", "token is required"))
+		return
+	}
+
+	userUUID, category, err := h.signer.Parse(token)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	if err := h.repo.Unsubscribe(c.Request.Context(), userUUID, category); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "unsubscribed", unsubscribeResult{Category: category})
+}
@@ -0,0 +1,48 @@
+package notifications
+
+import (
+	"context"
+
+	"grveyard/pkg/pagination"
+)
+
+// Inbox serves a user's in-app notifications.
+type Inbox struct {
+	repo InboxRepository
+}
+
+func NewInbox(repo InboxRepository) *Inbox {
+	return &Inbox{repo: repo}
+}
+
+// List returns a page of userUUID's notifications, newest first, with Text rendered
+// in locale.
+func (s *Inbox) List(ctx context.Context, userUUID, locale string, p pagination.Params) ([]Notification, int64, error) {
+	list, total, err := s.repo.List(ctx, userUUID, p.Limit, p.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range list {
+		list[i].Text = eventBody(locale, Event{Type: list[i].Type, Title: list[i].Title, Amount: list[i].Amount})
+	}
+	return list, total, nil
+}
+
+func (s *Inbox) UnreadCount(ctx context.Context, userUUID string) (UnreadCount, error) {
+	n, err := s.repo.UnreadCount(ctx, userUUID)
+	return UnreadCount{Unread: n}, err
+}
+
+// MarkRead marks one of userUUID's notifications as read.
+func (s *Inbox) MarkRead(ctx context.Context, id int64, userUUID string) error {
+	return s.repo.MarkRead(ctx, id, userUUID)
+}
+
+// MarkAllRead marks every unread notification of userUUID as read and returns the
+// remaining (zero) unread count, so clients can reset the badge from the response.
+func (s *Inbox) MarkAllRead(ctx context.Context, userUUID string) (UnreadCount, error) {
+	if _, err := s.repo.MarkAllRead(ctx, userUUID); err != nil {
+		return UnreadCount{}, err
+	}
+	return UnreadCount{}, nil
+}
@@ -0,0 +1,138 @@
+package notifications
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/response"
+)
+
+// InboxHandler serves the in-app notification inbox.
+type InboxHandler struct {
+	inbox *Inbox
+}
+
+func NewInboxHandler(inbox *Inbox) *InboxHandler {
+	return &InboxHandler{inbox: inbox}
+}
+
+func (h *InboxHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/users/:uuid/notifications", auth.RequireSelf("uuid"), h.list)
+	router.GET("/users/:uuid/notifications/unread-count", auth.RequireSelf("uuid"), h.unreadCount)
+	router.POST("/users/:uuid/notifications/read-all", auth.RequireSelf("uuid"), h.markAllRead)
+	router.POST("/notifications/:id/read", auth.Required(), h.markRead)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *InboxHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/notifications",
+			Tag:         "notifications",
+			Summary:     "List notifications",
+			Description: "The user's in-app notifications, newest first, with text in the request's language",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 20)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[Notification]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/notifications/unread-count",
+			Tag:         "notifications",
+			Summary:     "Count unread notifications",
+			Description: "Number for the notification badge",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Response: UnreadCount{},
+			Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/users/:uuid/notifications/read-all",
+			Tag:     "notifications",
+			Summary: "Mark all notifications as read",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Response: UnreadCount{},
+			Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/notifications/:id/read",
+			Tag:         "notifications",
+			Summary:     "Mark a notification as read",
+			Description: "Only the recipient may mark it; anyone else gets NOTIFICATION_NOT_FOUND.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Notification ID"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+	}
+}
+
+const inboxPageSize = 20
+
+func (h *InboxHandler) list(c *gin.Context) {
+	p, err := pagination.FromRequest(c, inboxPageSize)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	list, total, err := h.inbox.List(c.Request.Context(), c.Param("uuid"), response.Locale(c), p)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "notifications listed", list, total, p)
+}
+
+func (h *InboxHandler) unreadCount(c *gin.Context) {
+	count, err := h.inbox.UnreadCount(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "unread notifications", count)
+}
+
+func (h *InboxHandler) markAllRead(c *gin.Context) {
+	count, err := h.inbox.MarkAllRead(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "all notifications marked as read", count)
+}
+
+func (h *InboxHandler) markRead(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid notification id"))
+		return
+	}
+
+	if err := h.inbox.MarkRead(c.Request.Context(), id, auth.UserID(c)); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "notification marked as read", nil)
+}
@@ -0,0 +1,138 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/testhelpers"
+)
+
+type mockInboxRepository struct {
+	mock.Mock
+}
+
+func (m *mockInboxRepository) Add(ctx context.Context, ev Event) error {
+	args := m.Called(ctx, ev)
+	return args.Error(0)
+}
+
+func (m *mockInboxRepository) List(ctx context.Context, userUUID string, limit, offset int) ([]Notification, int64, error) {
+	args := m.Called(ctx, userUUID, limit, offset)
+	list, _ := args.Get(0).([]Notification)
+	return list, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockInboxRepository) UnreadCount(ctx context.Context, userUUID string) (int64, error) {
+	args := m.Called(ctx, userUUID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockInboxRepository) MarkRead(ctx context.Context, id int64, userUUID string) error {
+	args := m.Called(ctx, id, userUUID)
+	return args.Error(0)
+}
+
+func (m *mockInboxRepository) MarkAllRead(ctx context.Context, userUUID string) (int64, error) {
+	args := m.Called(ctx, userUUID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestOrchestrator_Dispatch_RecordsInAppRegardlessOfPreferences(t *testing.T) {
+	repo := new(mockRecipientRepository)
+	inbox := new(mockInboxRepository)
+	o := NewOrchestrator(repo, NewInAppChannel(inbox))
+
+	// Every other channel is switched off; the inbox still records the event
+	repo.On("GetRecipient", mock.Anything, "u1").Return(Recipient{UUID: "u1"}, Preferences{}, nil)
+	ev := Event{Type: EventOfferReceived, RecipientUUID: "u1", ActorUUID: "u2", EntityID: 7, Title: "Old App", Amount: 50}
+	inbox.On("Add", mock.Anything, ev).Return(nil)
+
+	o.Dispatch(context.Background(), ev)
+
+	inbox.AssertExpectations(t)
+}
+
+func newInboxRouter(repo InboxRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(testhelpers.AuthAs("u1", "buyer"))
+	NewInboxHandler(NewInbox(repo)).RegisterRoutes(r)
+	return r
+}
+
+func TestInboxHandler_List_RendersTextInLocale(t *testing.T) {
+	repo := new(mockInboxRepository)
+	repo.On("List", mock.Anything, "u1", 20, 0).
+		Return([]Notification{{ID: 3, Type: EventAssetSold, Title: "Old App"}}, int64(1), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/u1/notifications", nil)
+	req.Header.Set("Accept-Language", "hi")
+	w := httptest.NewRecorder()
+	newInboxRouter(repo).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data struct {
+			Items []Notification `json:"items"`
+			Total int64          `json:"total"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.EqualValues(t, 1, body.Data.Total)
+	require.Equal(t, "आपकी संपत्ति Old App को बिका हुआ चिह्नित किया गया है।", body.Data.Items[0].Text)
+}
+
+func TestInboxHandler_UnreadCount(t *testing.T) {
+	repo := new(mockInboxRepository)
+	repo.On("UnreadCount", mock.Anything, "u1").Return(int64(4), nil)
+
+	w := httptest.NewRecorder()
+	newInboxRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/u1/notifications/unread-count", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"unread":4}`, string(dataOf(t, w)))
+}
+
+func TestInboxHandler_MarkRead(t *testing.T) {
+	repo := new(mockInboxRepository)
+	repo.On("MarkRead", mock.Anything, int64(9), "u1").Return(ErrNotificationNotFound)
+	r := newInboxRouter(repo)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/notifications/9/read", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Contains(t, w.Body.String(), string(apperr.NotificationNotFound))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/notifications/abc/read", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestInboxHandler_MarkAllRead(t *testing.T) {
+	repo := new(mockInboxRepository)
+	repo.On("MarkAllRead", mock.Anything, "u1").Return(int64(3), nil)
+
+	w := httptest.NewRecorder()
+	newInboxRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/u1/notifications/read-all", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"unread":0}`, string(dataOf(t, w)))
+	repo.AssertExpectations(t)
+}
+
+func dataOf(t *testing.T, w *httptest.ResponseRecorder) json.RawMessage {
+	t.Helper()
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body.Data
+}
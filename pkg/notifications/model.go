@@ -0,0 +1,85 @@
+package notifications
+
+import "time"
+
+type EventType string
+
+const (
+	EventOfferReceived   EventType = "offer_received"
+	EventMessageReceived EventType = "message_received"
+	EventAssetSold       EventType = "asset_sold"
+	// EventWatchedAssetUpdated tells a buyer that an asset they follow changed price
+	// or availability; Title is the asset title. It is delivered in-app only.
+	EventWatchedAssetUpdated EventType = "watched_asset_updated"
+	// EventReportUpdated tells a reporter that a moderator changed the status of
+	// their report; EntityID is the report ID and Title the new status.
+	EventReportUpdated EventType = "report_updated"
+	// EventBookmarkUpdated tells a user that a startup they bookmarked changed
+	// status; EntityID is the startup ID and Title its name. It is delivered in-app
+	// and by push only.
+	EventBookmarkUpdated EventType = "bookmark_updated"
+	// EventPurchaseReceipt gives a buyer the receipt for a recorded sale; EntityID is
+	// the transaction ID, Title the asset title and Amount the total paid including
+	// tax. Its email is transactional, so it is always sent and has no unsubscribe link.
+	EventPurchaseReceipt EventType = "purchase_receipt"
+)
+
+// Event is published by other modules; the orchestrator decides who hears about it and how.
+type Event struct {
+	Type          EventType `json:"type"`
+	RecipientUUID string    `json:"recipient_uuid"`
+	ActorUUID     string    `json:"actor_uuid,omitempty"`
+	EntityID      int64     `json:"entity_id,omitempty"` // asset/offer id, depending on Type
+	Title         string    `json:"title,omitempty"`     // asset title or message preview
+	Amount        float64   `json:"amount,omitempty"`    // offer amount
+}
+
+// Recipient is the user an event is delivered to.
+type Recipient struct {
+	UUID   string
+	Email  string
+	Name   string
+	Locale string
+}
+
+// Preferences controls which channels a recipient wants per event type.
+type Preferences struct {
+	EmailOnMessage bool `json:"email_on_message"`
+	EmailOnOffer   bool `json:"email_on_offer"`
+	EmailOnSale    bool `json:"email_on_sale"`
+	EmailDigest    bool `json:"email_digest"` // the unread-messages digest email
+	PushEnabled    bool `json:"push_enabled"`
+}
+
+// DefaultPreferences applies to users who never saved preferences. New messages are
+// covered by the unread digest, so per-message email is opt-in.
+func DefaultPreferences() Preferences {
+	return Preferences{
+		EmailOnMessage: false,
+		EmailOnOffer:   true,
+		EmailOnSale:    true,
+		EmailDigest:    true,
+		PushEnabled:    true,
+	}
+}
+
+// Notification is an entry in a user's in-app inbox. Text is rendered in the
+// reader's locale when the inbox is listed.
+type Notification struct {
+	ID        int64      `json:"id"`
+	Type      EventType  `json:"type"`
+	ActorUUID string     `json:"actor_uuid,omitempty"`
+	EntityID  int64      `json:"entity_id,omitempty"`
+	Title     string     `json:"title,omitempty"`
+	Amount    float64    `json:"amount,omitempty"`
+	Text      string     `json:"text"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UserUUID  string     `json:"-"`
+}
+
+// UnreadCount is the badge number for a user's inbox.
+type UnreadCount struct {
+	Unread int64 `json:"unread"`
+}
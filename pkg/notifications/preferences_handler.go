@@ -0,0 +1,115 @@
+package notifications
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+// preferencesUpdate is the PUT /users/:uuid/preferences body; fields left out keep
+// their current value.
+type preferencesUpdate struct {
+	EmailOnMessage *bool `json:"email_on_message"`
+	EmailOnOffer   *bool `json:"email_on_offer"`
+	EmailOnSale    *bool `json:"email_on_sale"`
+	EmailDigest    *bool `json:"email_digest"`
+	PushEnabled    *bool `json:"push_enabled"`
+}
+
+func (u preferencesUpdate) apply(p Preferences) Preferences {
+	for _, f := range []struct {
+		from *bool
+		to   *bool
+	}{
+		{u.EmailOnMessage, &p.EmailOnMessage},
+		{u.EmailOnOffer, &p.EmailOnOffer},
+		{u.EmailOnSale, &p.EmailOnSale},
+		{u.EmailDigest, &p.EmailDigest},
+		{u.PushEnabled, &p.PushEnabled},
+	} {
+		if f.from != nil {
+			*f.to = *f.from
+		}
+	}
+	return p
+}
+
+// PreferencesHandler lets users choose which emails and pushes they get. The email
+// and push channels and the digest read the same settings before sending.
+type PreferencesHandler struct {
+	repo PreferencesRepository
+}
+
+func NewPreferencesHandler(repo PreferencesRepository) *PreferencesHandler {
+	return &PreferencesHandler{repo: repo}
+}
+
+func (h *PreferencesHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/users/:uuid/preferences", auth.RequireSelf("uuid"), h.get)
+	router.PUT("/users/:uuid/preferences", auth.RequireSelf("uuid"), h.update)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *PreferencesHandler) Operations() []openapi.Operation {
+	params := []openapi.Param{openapi.Path("uuid", "string", "User UUID")}
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/preferences",
+			Tag:         "notifications",
+			Summary:     "Get notification preferences",
+			Description: "Users who never saved preferences get the defaults: email for offers and sales, the unread-messages digest and push on, email for each new message off.",
+			Params:      params,
+			Response:    Preferences{},
+			Errors:      []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/users/:uuid/preferences",
+			Tag:         "notifications",
+			Summary:     "Update notification preferences",
+			Description: "Fields left out keep their current value. In-app notifications are always recorded.",
+			Params:      params,
+			Request:     preferencesUpdate{},
+			Response:    Preferences{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+	}
+}
+
+func (h *PreferencesHandler) get(c *gin.Context) {
+	p, err := h.repo.GetPreferences(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "preferences fetched", p)
+}
+
+func (h *PreferencesHandler) update(c *gin.Context) {
+	var req preferencesUpdate
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	uuid := c.Param("uuid")
+	current, err := h.repo.GetPreferences(ctx, uuid)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	p := req.apply(current)
+	if err := h.repo.SavePreferences(ctx, uuid, p); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "preferences updated", p)
+}
@@ -0,0 +1,60 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/auth"
+)
+
+func newPreferencesRouter(repo PreferencesRepository, userUUID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set(auth.UserIDKey, userUUID) })
+	NewPreferencesHandler(repo).RegisterRoutes(r)
+	return r
+}
+
+func TestPreferencesHandler_Get(t *testing.T) {
+	repo := new(mockPreferencesRepository)
+	repo.On("GetPreferences", mock.Anything, "u1").Return(DefaultPreferences(), nil)
+	r := newPreferencesRouter(repo, "u1")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/u1/preferences", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data Preferences `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, DefaultPreferences(), body.Data)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/u2/preferences", nil))
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestPreferencesHandler_UpdateKeepsOmittedFields(t *testing.T) {
+	repo := new(mockPreferencesRepository)
+	want := DefaultPreferences()
+	want.EmailDigest = false
+	want.EmailOnMessage = true
+	repo.On("GetPreferences", mock.Anything, "u1").Return(DefaultPreferences(), nil)
+	repo.On("SavePreferences", mock.Anything, "u1", want).Return(nil)
+	r := newPreferencesRouter(repo, "u1")
+
+	req := httptest.NewRequest(http.MethodPut, "/users/u1/preferences", strings.NewReader(`{"email_digest":false,"email_on_message":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,205 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/apperr"
+)
+
+var ErrRecipientNotFound = apperr.New(apperr.UserNotFound, "recipient not found")
+
+type RecipientRepository interface {
+	GetRecipient(ctx context.Context, userUUID string) (Recipient, Preferences, error)
+}
+
+type postgresRecipientRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresRecipientRepository(pool *pgxpool.Pool) RecipientRepository {
+	return &postgresRecipientRepository{pool: pool}
+}
+
+func (r *postgresRecipientRepository) GetRecipient(ctx context.Context, userUUID string) (Recipient, Preferences, error) {
+	d := DefaultPreferences()
+	query := `SELECT u.uuid, COALESCE(u.email, ''), u.name, u.locale,
+	                 COALESCE(p.email_on_message, $2), COALESCE(p.email_on_offer, $3),
+	                 COALESCE(p.email_on_sale, $4), COALESCE(p.email_digest, $5), COALESCE(p.push_enabled, $6)
+	          FROM users u
+	          LEFT JOIN notification_preferences p ON p.user_uuid = u.uuid
+	          WHERE u.uuid = $1 AND u.is_deleted = false`
+	row := r.pool.QueryRow(ctx, query, userUUID, d.EmailOnMessage, d.EmailOnOffer, d.EmailOnSale, d.EmailDigest, d.PushEnabled)
+
+	var rc Recipient
+	var p Preferences
+	if err := row.Scan(&rc.UUID, &rc.Email, &rc.Name, &rc.Locale, &p.EmailOnMessage, &p.EmailOnOffer, &p.EmailOnSale, &p.EmailDigest, &p.PushEnabled); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Recipient{}, Preferences{}, ErrRecipientNotFound
+		}
+		return Recipient{}, Preferences{}, err
+	}
+	return rc, p, nil
+}
+
+type PreferencesRepository interface {
+	Unsubscribe(ctx context.Context, userUUID string, c Category) error
+	// GetPreferences returns the user's preferences, DefaultPreferences for users who
+	// never saved any, and ErrRecipientNotFound for unknown users.
+	GetPreferences(ctx context.Context, userUUID string) (Preferences, error)
+	SavePreferences(ctx context.Context, userUUID string, p Preferences) error
+}
+
+type postgresPreferencesRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresPreferencesRepository(pool *pgxpool.Pool) PreferencesRepository {
+	return &postgresPreferencesRepository{pool: pool}
+}
+
+func (r *postgresPreferencesRepository) GetPreferences(ctx context.Context, userUUID string) (Preferences, error) {
+	_, p, err := NewPostgresRecipientRepository(r.pool).GetRecipient(ctx, userUUID)
+	return p, err
+}
+
+func (r *postgresPreferencesRepository) SavePreferences(ctx context.Context, userUUID string, p Preferences) error {
+	query := `INSERT INTO notification_preferences (user_uuid, email_on_message, email_on_offer, email_on_sale, email_digest, push_enabled)
+	          VALUES ($1, $2, $3, $4, $5, $6)
+	          ON CONFLICT (user_uuid) DO UPDATE SET
+	              email_on_message = EXCLUDED.email_on_message, email_on_offer = EXCLUDED.email_on_offer,
+	              email_on_sale = EXCLUDED.email_on_sale, email_digest = EXCLUDED.email_digest,
+	              push_enabled = EXCLUDED.push_enabled, updated_at = NOW()`
+	_, err := r.pool.Exec(ctx, query, userUUID, p.EmailOnMessage, p.EmailOnOffer, p.EmailOnSale, p.EmailDigest, p.PushEnabled)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return ErrRecipientNotFound
+	}
+	return err
+}
+
+// unsubscribeColumns lists the email columns switched off for each category.
+var unsubscribeColumns = map[Category][]string{
+	CategoryDigest:  {"email_digest"},
+	CategoryMessage: {"email_on_message"},
+	CategoryOffer:   {"email_on_offer"},
+	CategorySale:    {"email_on_sale"},
+	CategoryAll:     {"email_digest", "email_on_message", "email_on_offer", "email_on_sale"},
+}
+
+func (r *postgresPreferencesRepository) Unsubscribe(ctx context.Context, userUUID string, c Category) error {
+	columns, ok := unsubscribeColumns[c]
+	if !ok {
+		return ErrInvalidToken
+	}
+
+	sets := make([]string, 0, len(columns)+1)
+	for _, col := range columns {
+		sets = append(sets, col+" = FALSE")
+	}
+	sets = append(sets, "updated_at = NOW()")
+
+	query := `INSERT INTO notification_preferences (user_uuid, ` + strings.Join(columns, ", ") + `)
+	          VALUES ($1` + strings.Repeat(", FALSE", len(columns)) + `)
+	          ON CONFLICT (user_uuid) DO UPDATE SET ` + strings.Join(sets, ", ")
+	if _, err := r.pool.Exec(ctx, query, userUUID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrRecipientNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+var ErrNotificationNotFound = apperr.New(apperr.NotificationNotFound, "notification not found")
+
+// InboxRepository stores in-app notifications.
+type InboxRepository interface {
+	Add(ctx context.Context, ev Event) error
+	List(ctx context.Context, userUUID string, limit, offset int) ([]Notification, int64, error)
+	UnreadCount(ctx context.Context, userUUID string) (int64, error)
+	// MarkRead fails with ErrNotificationNotFound unless userUUID received id.
+	MarkRead(ctx context.Context, id int64, userUUID string) error
+	MarkAllRead(ctx context.Context, userUUID string) (int64, error)
+}
+
+type postgresInboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresInboxRepository(pool *pgxpool.Pool) InboxRepository {
+	return &postgresInboxRepository{pool: pool}
+}
+
+func (r *postgresInboxRepository) Add(ctx context.Context, ev Event) error {
+	query := `INSERT INTO notifications (user_uuid, type, actor_uuid, entity_id, title, amount)
+	          VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, 0), $5, NULLIF($6, 0))`
+	_, err := r.pool.Exec(ctx, query, ev.RecipientUUID, ev.Type, ev.ActorUUID, ev.EntityID, ev.Title, ev.Amount)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return ErrRecipientNotFound
+	}
+	return err
+}
+
+func (r *postgresInboxRepository) List(ctx context.Context, userUUID string, limit, offset int) ([]Notification, int64, error) {
+	var total int64
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_uuid = $1`, userUUID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT id, user_uuid, type, COALESCE(actor_uuid, ''), COALESCE(entity_id, 0), title,
+	                 COALESCE(amount, 0)::float8, read_at, created_at
+	          FROM notifications
+	          WHERE user_uuid = $1
+	          ORDER BY id DESC
+	          LIMIT $2 OFFSET $3`
+	rows, err := r.pool.Query(ctx, query, userUUID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var list []Notification
+	for rows.Next() {
+		var n Notification
+		if err := rows.Scan(&n.ID, &n.UserUUID, &n.Type, &n.ActorUUID, &n.EntityID, &n.Title, &n.Amount, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		n.Read = n.ReadAt != nil
+		list = append(list, n)
+	}
+	return list, total, rows.Err()
+}
+
+func (r *postgresInboxRepository) UnreadCount(ctx context.Context, userUUID string) (int64, error) {
+	var n int64
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_uuid = $1 AND read_at IS NULL`, userUUID).Scan(&n)
+	return n, err
+}
+
+// MarkRead is idempotent: reading an already read notification keeps its read_at.
+func (r *postgresInboxRepository) MarkRead(ctx context.Context, id int64, userUUID string) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_uuid = $2`, id, userUUID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *postgresInboxRepository) MarkAllRead(ctx context.Context, userUUID string) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `UPDATE notifications SET read_at = NOW() WHERE user_uuid = $1 AND read_at IS NULL`, userUUID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,107 @@
+package notifications
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"grveyard/pkg/requestid"
+)
+
+const queueSize = 256
+
+// Publisher is what other modules depend on to emit events.
+type Publisher interface {
+	Publish(ctx context.Context, ev Event)
+}
+
+// Orchestrator resolves recipients and preferences and fans events out to channels.
+// Publishing never blocks the caller; events are processed by a background worker.
+type Orchestrator struct {
+	repo     RecipientRepository
+	channels []Channel
+	queue    chan queuedEvent
+	wg       sync.WaitGroup
+	logger   interface {
+		Printf(string, ...interface{})
+	}
+}
+
+func NewOrchestrator(repo RecipientRepository, channels ...Channel) *Orchestrator {
+	return &Orchestrator{
+		repo:     repo,
+		channels: channels,
+		queue:    make(chan queuedEvent, queueSize),
+		logger:   log.New(log.Writer(), "[notifications] ", log.LstdFlags),
+	}
+}
+
+// queuedEvent keeps the publisher's request ID so async deliveries stay traceable.
+type queuedEvent struct {
+	requestID string
+	ev        Event
+}
+
+// Publish enqueues ev. If the queue is full the event is dropped and logged.
+func (o *Orchestrator) Publish(ctx context.Context, ev Event) {
+	select {
+	case o.queue <- queuedEvent{requestID: requestid.FromContext(ctx), ev: ev}:
+	default:
+		o.logger.Printf("queue full, dropping %s event for %s", ev.Type, ev.RecipientUUID)
+	}
+}
+
+// Start runs the dispatch worker until ctx is cancelled.
+func (o *Orchestrator) Start(ctx context.Context) {
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case q := <-o.queue:
+				o.Dispatch(requestid.NewContext(ctx, q.requestID), q.ev)
+			}
+		}
+	}()
+}
+
+// Wait blocks until the worker has stopped after its context was cancelled, or ctx expires.
+func (o *Orchestrator) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		o.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Dispatch delivers ev synchronously to every channel the recipient has enabled.
+// Channel failures are logged and do not stop other channels.
+func (o *Orchestrator) Dispatch(ctx context.Context, ev Event) {
+	if ev.RecipientUUID == "" || ev.RecipientUUID == ev.ActorUUID {
+		return
+	}
+
+	recipient, prefs, err := o.repo.GetRecipient(ctx, ev.RecipientUUID)
+	if err != nil {
+		o.logger.Printf("[%s] resolve recipient %s failed: %v", requestid.FromContext(ctx), ev.RecipientUUID, err)
+		return
+	}
+
+	for _, ch := range o.channels {
+		if !ch.Enabled(prefs, ev.Type) {
+			continue
+		}
+		if err := ch.Deliver(ctx, recipient, ev); err != nil {
+			o.logger.Printf("[%s] %s delivery of %s to %s failed: %v", requestid.FromContext(ctx), ch.Name(), ev.Type, ev.RecipientUUID, err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRecipientRepository struct {
+	mock.Mock
+}
+
+func (m *mockRecipientRepository) GetRecipient(ctx context.Context, userUUID string) (Recipient, Preferences, error) {
+	args := m.Called(ctx, userUUID)
+	r, _ := args.Get(0).(Recipient)
+	p, _ := args.Get(1).(Preferences)
+	return r, p, args.Error(2)
+}
+
+type mockEmailService struct {
+	mock.Mock
+}
+
+func (m *mockEmailService) SendEmail(ctx context.Context, subject, toEmail, plainTextContent, htmlContent string) error {
+	args := m.Called(subject, toEmail, plainTextContent, htmlContent)
+	return args.Error(0)
+}
+
+type fakeBroadcaster struct {
+	online map[string]bool
+	sent   []interface{}
+}
+
+func (b *fakeBroadcaster) IsOnline(userID string) bool { return b.online[userID] }
+
+func (b *fakeBroadcaster) BroadcastToUser(userID string, message interface{}) error {
+	b.sent = append(b.sent, message)
+	return nil
+}
+
+func TestOrchestrator_Dispatch_RespectsPreferences(t *testing.T) {
+	repo := new(mockRecipientRepository)
+	es := new(mockEmailService)
+	b := &fakeBroadcaster{online: map[string]bool{"u1": true}}
+	o := NewOrchestrator(repo, NewEmailChannel(es, nil), NewPushChannel(b))
+
+	prefs := DefaultPreferences()
+	repo.On("GetRecipient", mock.Anything, "u1").
+		Return(Recipient{UUID: "u1", Email: "a@example.com", Locale: "en"}, prefs, nil)
+
+	o.Dispatch(context.Background(), Event{Type: EventMessageReceived, RecipientUUID: "u1", ActorUUID: "u2", Title: "hi"})
+
+	es.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.Len(t, b.sent, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestOrchestrator_Dispatch_SendsEmailForSale(t *testing.T) {
+	repo := new(mockRecipientRepository)
+	es := new(mockEmailService)
+	b := &fakeBroadcaster{online: map[string]bool{}}
+	o := NewOrchestrator(repo, NewEmailChannel(es, nil), NewPushChannel(b))
+
+	repo.On("GetRecipient", mock.Anything, "u1").
+		Return(Recipient{UUID: "u1", Email: "a@example.com", Locale: "en"}, DefaultPreferences(), nil)
+	es.On("SendEmail", mock.Anything, "a@example.com", mock.MatchedBy(func(body string) bool {
+		return body != ""
+	}), mock.Anything).Return(nil)
+
+	o.Dispatch(context.Background(), Event{Type: EventAssetSold, RecipientUUID: "u1", Title: "Old App"})
+
+	require.Empty(t, b.sent)
+	es.AssertExpectations(t)
+}
+
+func TestOrchestrator_Dispatch_SendsLocalizedReceipt(t *testing.T) {
+	repo := new(mockRecipientRepository)
+	es := new(mockEmailService)
+	o := NewOrchestrator(repo, NewEmailChannel(es, NewUnsubscribeSigner("secret", "https://api.example.com")))
+
+	// Receipts are transactional: sent even with sale emails off, never with an unsubscribe link
+	prefs := DefaultPreferences()
+	prefs.EmailOnSale = false
+	repo.On("GetRecipient", mock.Anything, "u1").
+		Return(Recipient{UUID: "u1", Email: "a@example.com", Locale: "hi"}, prefs, nil)
+	es.On("SendEmail", "आपकी Graveyard रसीद", "a@example.com",
+		"Old App खरीदने के लिए धन्यवाद। आपने कर सहित 590.00 का भुगतान किया; आपका चालान नंबर INV-000007 है।", mock.Anything).Return(nil)
+
+	o.Dispatch(context.Background(), Event{Type: EventPurchaseReceipt, RecipientUUID: "u1", ActorUUID: "u2", EntityID: 7, Title: "Old App", Amount: 590})
+
+	es.AssertExpectations(t)
+}
+
+func TestOrchestrator_Dispatch_SkipsSelfEvents(t *testing.T) {
+	repo := new(mockRecipientRepository)
+	o := NewOrchestrator(repo)
+
+	o.Dispatch(context.Background(), Event{Type: EventOfferReceived, RecipientUUID: "u1", ActorUUID: "u1"})
+
+	repo.AssertNotCalled(t, "GetRecipient", mock.Anything, mock.Anything)
+}
+
+func TestOrchestrator_Dispatch_UnknownRecipient(t *testing.T) {
+	repo := new(mockRecipientRepository)
+	es := new(mockEmailService)
+	o := NewOrchestrator(repo, NewEmailChannel(es, nil))
+
+	repo.On("GetRecipient", mock.Anything, "ghost").Return(nil, nil, errors.New("recipient not found"))
+
+	o.Dispatch(context.Background(), Event{Type: EventAssetSold, RecipientUUID: "ghost"})
+
+	es.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,104 @@
+package notifications
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"grveyard/pkg/apperr"
+)
+
+var ErrInvalidToken = apperr.New(apperr.InvalidUnsubscribe, "invalid unsubscribe token")
+
+// Category is the group of emails an unsubscribe link opts out of.
+type Category string
+
+const (
+	CategoryDigest  Category = "digest"
+	CategoryMessage Category = "message"
+	CategoryOffer   Category = "offer"
+	CategorySale    Category = "sale"
+	CategoryAll     Category = "all"
+)
+
+func (c Category) valid() bool {
+	switch c {
+	case CategoryDigest, CategoryMessage, CategoryOffer, CategorySale, CategoryAll:
+		return true
+	}
+	return false
+}
+
+// categoryFor maps an event to the category its emails are unsubscribed under.
+func categoryFor(t EventType) Category {
+	switch t {
+	case EventMessageReceived:
+		return CategoryMessage
+	case EventOfferReceived:
+		return CategoryOffer
+	case EventAssetSold:
+		return CategorySale
+	default:
+		return CategoryAll
+	}
+}
+
+// UnsubscribeSigner issues and verifies HMAC-signed unsubscribe tokens. Tokens do not
+// expire: an unsubscribe link in an old email must keep working.
+type UnsubscribeSigner struct {
+	secret  []byte
+	baseURL string
+}
+
+// NewUnsubscribeSigner returns nil when secret is empty, which disables unsubscribe links.
+// baseURL is the public origin of this API, e.g. https://api.example.com.
+func NewUnsubscribeSigner(secret, baseURL string) *UnsubscribeSigner {
+	if secret == "" {
+		return nil
+	}
+	return &UnsubscribeSigner{secret: []byte(secret), baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Token encodes userUUID and category as payload.signature, both base64url.
+func (s *UnsubscribeSigner) Token(userUUID string, c Category) string {
+	payload := []byte(userUUID + ":" + string(c))
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload))
+}
+
+// Parse verifies token and returns the user and category it was issued for.
+func (s *UnsubscribeSigner) Parse(token string) (string, Category, error) {
+	encPayload, encSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", "", ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encPayload)
+	if err != nil {
+		return "", "", ErrInvalidToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encSig)
+	if err != nil || !hmac.Equal(sig, s.sign(payload)) {
+		return "", "", ErrInvalidToken
+	}
+
+	userUUID, category, ok := strings.Cut(string(payload), ":")
+	if !ok || userUUID == "" || !Category(category).valid() {
+		return "", "", ErrInvalidToken
+	}
+	return userUUID, Category(category), nil
+}
+
+// URL returns the one-click unsubscribe link, or "" if the signer is disabled.
+func (s *UnsubscribeSigner) URL(userUUID string, c Category) string {
+	if s == nil {
+		return ""
+	}
+	return s.baseURL + "/email/unsubscribe?token=" + url.QueryEscape(s.Token(userUUID, c))
+}
+
+func (s *UnsubscribeSigner) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,94 @@
+package notifications
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPreferencesRepository struct {
+	mock.Mock
+}
+
+func (m *mockPreferencesRepository) Unsubscribe(ctx context.Context, userUUID string, c Category) error {
+	args := m.Called(ctx, userUUID, c)
+	return args.Error(0)
+}
+
+func (m *mockPreferencesRepository) GetPreferences(ctx context.Context, userUUID string) (Preferences, error) {
+	args := m.Called(ctx, userUUID)
+	p, _ := args.Get(0).(Preferences)
+	return p, args.Error(1)
+}
+
+func (m *mockPreferencesRepository) SavePreferences(ctx context.Context, userUUID string, p Preferences) error {
+	args := m.Called(ctx, userUUID, p)
+	return args.Error(0)
+}
+
+func TestUnsubscribeSigner_RoundTrip(t *testing.T) {
+	s := NewUnsubscribeSigner("secret", "https://api.example.com/")
+
+	userUUID, category, err := s.Parse(s.Token("user-1", CategoryDigest))
+
+	require.NoError(t, err)
+	require.Equal(t, "user-1", userUUID)
+	require.Equal(t, CategoryDigest, category)
+	require.True(t, strings.HasPrefix(s.URL("user-1", CategoryDigest), "https://api.example.com/email/unsubscribe?token="))
+}
+
+func TestUnsubscribeSigner_RejectsTamperedToken(t *testing.T) {
+	s := NewUnsubscribeSigner("secret", "")
+	other := NewUnsubscribeSigner("other-secret", "")
+
+	_, _, err := s.Parse(other.Token("user-1", CategoryAll))
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	_, _, err = s.Parse("not-a-token")
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestUnsubscribeSigner_DisabledWithoutSecret(t *testing.T) {
+	s := NewUnsubscribeSigner("", "https://api.example.com")
+
+	require.Nil(t, s)
+	require.Empty(t, s.URL("user-1", CategoryDigest))
+}
+
+func TestUnsubscribeHandler_Unsubscribe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	signer := NewUnsubscribeSigner("secret", "")
+	repo := new(mockPreferencesRepository)
+	r := gin.New()
+	NewUnsubscribeHandler(signer, repo).RegisterRoutes(r)
+
+	repo.On("Unsubscribe", mock.Anything, "user-1", CategoryOffer).Return(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/email/unsubscribe?token="+url.QueryEscape(signer.Token("user-1", CategoryOffer)), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestUnsubscribeHandler_InvalidToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(mockPreferencesRepository)
+	r := gin.New()
+	NewUnsubscribeHandler(NewUnsubscribeSigner("secret", ""), repo).RegisterRoutes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/email/unsubscribe?token=bogus", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	repo.AssertNotCalled(t, "Unsubscribe", mock.Anything, mock.Anything, mock.Anything)
+}
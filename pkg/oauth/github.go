@@ -0,0 +1,126 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"grveyard/pkg/apperr"
+)
+
+// ProviderGitHub identities come from a GitHub OAuth app's web flow.
+// https://docs.github.com/en/apps/oauth-apps/building-oauth-apps/authorizing-oauth-apps#web-application-flow
+const ProviderGitHub = "github"
+
+const (
+	githubTokenURL = "https://github.com/login/oauth/access_token"
+	githubAPIURL   = "https://api.github.com"
+)
+
+var ErrInvalidOAuthCode = apperr.New(apperr.InvalidOAuthCode, "invalid or expired authorization code")
+
+// GitHub exchanges authorization codes from GitHub's OAuth web flow for the identity
+// of the account that approved it. The access token is used once to read the
+// profile and not kept; we only need to know who the account is.
+type GitHub struct {
+	clientID     string
+	clientSecret string
+	tokenURL     string
+	apiURL       string
+	client       *http.Client
+}
+
+func NewGitHub(clientID, clientSecret string) *GitHub {
+	return &GitHub{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		tokenURL:     githubTokenURL,
+		apiURL:       githubAPIURL,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Exchange redeems code, which GitHub sent to redirectURI, and returns the account.
+// redirectURI must match the one the client used to start the flow, or be empty if
+// it used the app's default.
+func (g *GitHub) Exchange(ctx context.Context, code, redirectURI string) (Identity, error) {
+	form := url.Values{"client_id": {g.clientID}, "client_secret": {g.clientSecret}, "code": {code}}
+	if redirectURI != "" {
+		form.Set("redirect_uri", redirectURI)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Identity{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+	if err := g.do(req, &tok); err != nil {
+		return Identity{}, err
+	}
+	// GitHub reports bad and expired codes as 200 with error=bad_verification_code
+	if tok.Error != "" || tok.AccessToken == "" {
+		return Identity{}, ErrInvalidOAuthCode
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, g.apiURL+"/user", nil)
+	if err != nil {
+		return Identity{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+
+	var user struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+		HTMLURL   string `json:"html_url"`
+	}
+	if err := g.do(req, &user); err != nil {
+		return Identity{}, err
+	}
+	if user.ID == 0 || user.Login == "" {
+		return Identity{}, fmt.Errorf("github: user response without id or login")
+	}
+	return Identity{
+		Provider:   ProviderGitHub,
+		Subject:    strconv.FormatInt(user.ID, 10), // logins can be renamed; the ID is stable
+		Name:       user.Name,
+		Picture:    user.AvatarURL,
+		Username:   user.Login,
+		ProfileURL: user.HTMLURL,
+	}, nil
+}
+
+func (g *GitHub) do(req *http.Request, out any) error {
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("github oauth: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		return ErrInvalidOAuthCode
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("github oauth: %s %s: %d", req.Method, req.URL.Path, resp.StatusCode)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("github oauth: decode %s: %w", req.URL.Path, err)
+	}
+	return nil
+}
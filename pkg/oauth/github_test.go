@@ -0,0 +1,63 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGitHub(t *testing.T) *GitHub {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client-id", r.PostForm.Get("client_id"))
+		require.Equal(t, "client-secret", r.PostForm.Get("client_secret"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("code") != "good-code" {
+			_, _ = w.Write([]byte(`{"error":"bad_verification_code","error_description":"The code passed is incorrect or expired."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"gho_abc","token_type":"bearer","scope":""}`))
+	})
+	mux.HandleFunc("GET /user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gho_abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":583231,"login":"octocat","name":"The Octocat","avatar_url":"https://avatars.example/u/583231","html_url":"https://github.com/octocat"}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	g := NewGitHub("client-id", "client-secret")
+	g.tokenURL = srv.URL + "/login/oauth/access_token"
+	g.apiURL = srv.URL
+	g.client = srv.Client()
+	return g
+}
+
+func TestGitHub_Exchange(t *testing.T) {
+	g := newTestGitHub(t)
+
+	id, err := g.Exchange(context.Background(), "good-code", "")
+	require.NoError(t, err)
+	require.Equal(t, Identity{
+		Provider:   ProviderGitHub,
+		Subject:    "583231",
+		Name:       "The Octocat",
+		Picture:    "https://avatars.example/u/583231",
+		Username:   "octocat",
+		ProfileURL: "https://github.com/octocat",
+	}, id)
+}
+
+func TestGitHub_Exchange_BadCode(t *testing.T) {
+	g := newTestGitHub(t)
+
+	_, err := g.Exchange(context.Background(), "expired", "https://app.example/callback")
+	require.ErrorIs(t, err, ErrInvalidOAuthCode)
+}
@@ -0,0 +1,225 @@
+// Package oauth signs users in with third-party identity providers and links their
+// accounts to profiles. Clients run the provider's own flow and send us the result:
+// a Google ID token, which we verify against Google's published keys with the
+// standard library the same way pkg/auth and pkg/github handle JWTs, or a GitHub
+// authorization code, which we redeem.
+// https://developers.google.com/identity/gsi/web/guides/verify-google-id-token
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"grveyard/pkg/apperr"
+)
+
+const (
+	ProviderGoogle = "google"
+
+	googleCertsURL = "https://www.googleapis.com/oauth2/v3/certs"
+	// Keys are refetched at most this often when a token names an unknown kid, so
+	// garbage tokens cannot make us hammer Google.
+	minKeyRefresh = time.Minute
+	// clockSkew is how far past exp a token is still accepted.
+	clockSkew = time.Minute
+)
+
+var (
+	ErrInvalidIDToken = apperr.New(apperr.InvalidIDToken, "invalid or expired ID token")
+	// ErrEmailNotVerified means the provider has not confirmed the account's email, so
+	// it cannot be matched to or create a user.
+	ErrEmailNotVerified = apperr.New(apperr.InvalidIDToken, "the provider has not verified this email")
+)
+
+var googleIssuers = []string{"accounts.google.com", "https://accounts.google.com"}
+
+var maxAgeRe = regexp.MustCompile(`max-age=(\d+)`)
+
+// Identity is who the provider says signed in.
+type Identity struct {
+	Provider   string
+	Subject    string // the provider's stable user ID
+	Email      string
+	Name       string
+	Picture    string
+	Username   string // the handle shown on profiles, e.g. a GitHub login
+	ProfileURL string
+}
+
+// Google verifies ID tokens from Sign in with Google.
+type Google struct {
+	clientIDs []string
+	certsURL  string
+	client    *http.Client
+	now       func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey // by kid
+	expires   time.Time
+	fetchedAt time.Time
+}
+
+// NewGoogle returns a verifier accepting tokens issued to any of clientIDs, the
+// OAuth client IDs of our web and mobile apps.
+func NewGoogle(clientIDs ...string) *Google {
+	return &Google{
+		clientIDs: clientIDs,
+		certsURL:  googleCertsURL,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		now:       time.Now,
+	}
+}
+
+// Verify checks idToken's signature, issuer, audience and expiry and returns the
+// identity it carries. Tokens for unverified emails return ErrEmailNotVerified.
+func (g *Google) Verify(ctx context.Context, idToken string) (Identity, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return Identity{}, ErrInvalidIDToken
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "RS256" {
+		return Identity{}, ErrInvalidIDToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, ErrInvalidIDToken
+	}
+	key, err := g.key(ctx, header.Kid)
+	if err != nil {
+		return Identity{}, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) != nil {
+		return Identity{}, ErrInvalidIDToken
+	}
+
+	var claims struct {
+		Issuer        string          `json:"iss"`
+		Audience      string          `json:"aud"`
+		Subject       string          `json:"sub"`
+		ExpiresAt     int64           `json:"exp"`
+		Email         string          `json:"email"`
+		EmailVerified json.RawMessage `json:"email_verified"` // bool, or "true" in older tokens
+		Name          string          `json:"name"`
+		Picture       string          `json:"picture"`
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Identity{}, ErrInvalidIDToken
+	}
+	if !slices.Contains(googleIssuers, claims.Issuer) || !slices.Contains(g.clientIDs, claims.Audience) ||
+		claims.Subject == "" || g.now().After(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)) {
+		return Identity{}, ErrInvalidIDToken
+	}
+	if v := strings.Trim(string(claims.EmailVerified), `"`); claims.Email == "" || v != "true" {
+		return Identity{}, ErrEmailNotVerified
+	}
+	return Identity{
+		Provider: ProviderGoogle,
+		Subject:  claims.Subject,
+		Email:    strings.ToLower(claims.Email),
+		Name:     claims.Name,
+		Picture:  claims.Picture,
+	}, nil
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// key returns the signing key for kid, refetching Google's keys when the cached set
+// has expired or does not contain it.
+func (g *Google) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	if key, ok := g.keys[kid]; ok && now.Before(g.expires) {
+		return key, nil
+	}
+	if g.keys != nil && now.Before(g.expires) && now.Sub(g.fetchedAt) < minKeyRefresh {
+		return nil, ErrInvalidIDToken
+	}
+	if err := g.fetchKeys(ctx); err != nil {
+		return nil, err
+	}
+	key, ok := g.keys[kid]
+	if !ok {
+		return nil, ErrInvalidIDToken
+	}
+	return key, nil
+}
+
+func (g *Google) fetchKeys(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.certsURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch google keys: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch google keys: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("decode google keys: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	if len(keys) == 0 {
+		return errors.New("fetch google keys: no RSA keys")
+	}
+
+	// Google rotates keys and says how long to cache them in Cache-Control
+	ttl := time.Hour
+	if m := maxAgeRe.FindStringSubmatch(resp.Header.Get("Cache-Control")); m != nil {
+		if secs, err := strconv.Atoi(m[1]); err == nil {
+			ttl = time.Duration(secs) * time.Second
+		}
+	}
+	now := g.now()
+	g.keys, g.fetchedAt, g.expires = keys, now, now.Add(ttl)
+	return nil
+}
@@ -0,0 +1,136 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testKeys struct {
+	key     *rsa.PrivateKey
+	fetches atomic.Int32
+	server  *httptest.Server
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	k := &testKeys{key: key}
+	k.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		k.fetches.Add(1)
+		w.Header().Set("Cache-Control", "public, max-age=600, must-revalidate")
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kid": "kid-1",
+			"kty": "RSA",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	t.Cleanup(k.server.Close)
+	return k
+}
+
+func (k *testKeys) sign(t *testing.T, kid string, claims map[string]any) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+	body, err := json.Marshal(claims)
+	require.NoError(t, err)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	digest := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, k.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func newTestGoogle(k *testKeys, now time.Time) *Google {
+	g := NewGoogle("web-client", "ios-client")
+	g.certsURL = k.server.URL
+	g.client = k.server.Client()
+	g.now = func() time.Time { return now }
+	return g
+}
+
+func googleClaims(now time.Time) map[string]any {
+	return map[string]any{
+		"iss":            "https://accounts.google.com",
+		"aud":            "ios-client",
+		"sub":            "10769150350006150715113082367",
+		"exp":            now.Add(time.Hour).Unix(),
+		"email":          "Jane@Example.com",
+		"email_verified": true,
+		"name":           "Jane",
+		"picture":        "https://lh3.example/jane.png",
+	}
+}
+
+func TestGoogle_Verify(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	k := newTestKeys(t)
+	g := newTestGoogle(k, now)
+
+	id, err := g.Verify(context.Background(), k.sign(t, "kid-1", googleClaims(now)))
+	require.NoError(t, err)
+	require.Equal(t, Identity{
+		Provider: ProviderGoogle,
+		Subject:  "10769150350006150715113082367",
+		Email:    "jane@example.com",
+		Name:     "Jane",
+		Picture:  "https://lh3.example/jane.png",
+	}, id)
+
+	// Keys are cached for max-age
+	_, err = g.Verify(context.Background(), k.sign(t, "kid-1", googleClaims(now)))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), k.fetches.Load())
+}
+
+func TestGoogle_Verify_Rejects(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	k := newTestKeys(t)
+	g := newTestGoogle(k, now)
+
+	with := func(key string, v any) map[string]any {
+		c := googleClaims(now)
+		c[key] = v
+		return c
+	}
+	valid := k.sign(t, "kid-1", googleClaims(now))
+	other := newTestKeys(t)
+
+	for name, token := range map[string]string{
+		"garbage":      "not-a-token",
+		"other app":    k.sign(t, "kid-1", with("aud", "someone-else")),
+		"other issuer": k.sign(t, "kid-1", with("iss", "https://evil.example")),
+		"expired":      k.sign(t, "kid-1", with("exp", now.Add(-2*time.Minute).Unix())),
+		"no subject":   k.sign(t, "kid-1", with("sub", "")),
+		"unknown kid":  k.sign(t, "kid-2", googleClaims(now)),
+		"other key":    other.sign(t, "kid-1", googleClaims(now)),
+		"tampered":     valid[:len(valid)-4] + "AAAA",
+	} {
+		_, err := g.Verify(context.Background(), token)
+		require.ErrorIs(t, err, ErrInvalidIDToken, name)
+	}
+
+	_, err := g.Verify(context.Background(), k.sign(t, "kid-1", with("email_verified", false)))
+	require.ErrorIs(t, err, ErrEmailNotVerified)
+	_, err = g.Verify(context.Background(), k.sign(t, "kid-1", with("email_verified", "true")))
+	require.NoError(t, err)
+
+	// An unknown kid does not trigger a refetch within minKeyRefresh
+	require.Equal(t, int32(1), k.fetches.Load())
+}
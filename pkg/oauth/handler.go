@@ -0,0 +1,74 @@
+package oauth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/users"
+	"grveyard/pkg/validation"
+)
+
+type OAuthHandler struct {
+	service *Service
+	events  auth.EventRecorder // optional
+}
+
+func NewOAuthHandler(service *Service) *OAuthHandler {
+	return &OAuthHandler{service: service}
+}
+
+// SetEvents records sign-ins in the security log.
+func (h *OAuthHandler) SetEvents(events auth.EventRecorder) {
+	h.events = events
+}
+
+func (h *OAuthHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/auth/google", h.google)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *OAuthHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/auth/google",
+			Tag:         "auth",
+			Summary:     "Sign in with Google",
+			Description: "Exchanges the ID token from Sign in with Google for the same user and tokens as POST /users/login. The Google account's verified email is matched to a user, and a buyer account is created if there is none.",
+			Request:     idTokenRequest{},
+			Response:    signInResponse{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+		},
+	}
+}
+
+type idTokenRequest struct {
+	IDToken    string `json:"id_token" binding:"required,max=4096"`
+	DeviceName string `json:"device_name" binding:"max=100"`
+}
+
+// signInResponse has the same shape as the password login response.
+type signInResponse struct {
+	users.User
+	*auth.Session
+}
+
+func (h *OAuthHandler) google(c *gin.Context) {
+	var req idTokenRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	u, session, err := h.service.SignInWithGoogle(c.Request.Context(), req.IDToken, auth.DeviceFromRequest(c, req.DeviceName))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	if h.events != nil {
+		h.events.Record(c, u.UUID, auth.EventLogin)
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "login successful", signInResponse{User: u, Session: &session})
+}
@@ -0,0 +1,54 @@
+package oauth
+
+import (
+	"context"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/users"
+)
+
+var ErrFounderOnly = apperr.New(apperr.Forbidden, "only founders can link a GitHub account")
+
+// CodeExchanger redeems an OAuth authorization code; *GitHub in production.
+type CodeExchanger interface {
+	Exchange(ctx context.Context, code, redirectURI string) (Identity, error)
+}
+
+// UserLookup finds the user an identity is linked to; users.UserService in
+// production.
+type UserLookup interface {
+	GetUserByUUID(ctx context.Context, uuid string) (users.User, error)
+}
+
+// IdentityService links external accounts to profiles, where buyers can see them.
+// Linking GitHub lets a founder show that the codebases they list are theirs.
+type IdentityService struct {
+	github CodeExchanger
+	repo   IdentityRepository
+	users  UserLookup
+}
+
+func NewIdentityService(github CodeExchanger, repo IdentityRepository, users UserLookup) *IdentityService {
+	return &IdentityService{github: github, repo: repo, users: users}
+}
+
+// LinkGitHub redeems code for the GitHub account that approved it and links it to
+// the founder userUUID.
+func (s *IdentityService) LinkGitHub(ctx context.Context, userUUID, code, redirectURI string) (users.LinkedIdentity, error) {
+	u, err := s.users.GetUserByUUID(ctx, userUUID)
+	if err != nil {
+		return users.LinkedIdentity{}, err
+	}
+	if u.Role != "founder" {
+		return users.LinkedIdentity{}, ErrFounderOnly
+	}
+	id, err := s.github.Exchange(ctx, code, redirectURI)
+	if err != nil {
+		return users.LinkedIdentity{}, err
+	}
+	return s.repo.LinkIdentity(ctx, userUUID, id)
+}
+
+func (s *IdentityService) Unlink(ctx context.Context, userUUID, provider string) error {
+	return s.repo.UnlinkIdentity(ctx, userUUID, provider)
+}
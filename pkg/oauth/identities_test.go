@@ -0,0 +1,74 @@
+package oauth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/users"
+)
+
+type fakeExchanger struct {
+	id    Identity
+	calls int
+}
+
+func (f *fakeExchanger) Exchange(context.Context, string, string) (Identity, error) {
+	f.calls++
+	return f.id, nil
+}
+
+type fakeIdentityRepo struct {
+	linked map[string]Identity // by user UUID
+}
+
+func (f *fakeIdentityRepo) LinkIdentity(_ context.Context, userUUID string, id Identity) (users.LinkedIdentity, error) {
+	f.linked[userUUID] = id
+	return users.LinkedIdentity{Provider: id.Provider, Username: id.Username, ProfileURL: id.ProfileURL}, nil
+}
+
+func (f *fakeIdentityRepo) UnlinkIdentity(_ context.Context, userUUID, provider string) error {
+	if _, ok := f.linked[userUUID]; !ok {
+		return ErrIdentityNotFound
+	}
+	delete(f.linked, userUUID)
+	return nil
+}
+
+type fakeUserLookup map[string]users.User
+
+func (f fakeUserLookup) GetUserByUUID(_ context.Context, uuid string) (users.User, error) {
+	u, ok := f[uuid]
+	if !ok {
+		return users.User{}, users.ErrUserNotFound
+	}
+	return u, nil
+}
+
+func TestIdentityService_LinkGitHub(t *testing.T) {
+	ctx := context.Background()
+	github := &fakeExchanger{id: Identity{Provider: ProviderGitHub, Subject: "1", Username: "octocat", ProfileURL: "https://github.com/octocat"}}
+	repo := &fakeIdentityRepo{linked: map[string]Identity{}}
+	lookup := fakeUserLookup{
+		"founder-1": {UUID: "founder-1", Role: "founder"},
+		"buyer-1":   {UUID: "buyer-1", Role: "buyer"},
+	}
+	svc := NewIdentityService(github, repo, lookup)
+
+	linked, err := svc.LinkGitHub(ctx, "founder-1", "code", "")
+	require.NoError(t, err)
+	require.Equal(t, users.LinkedIdentity{Provider: "github", Username: "octocat", ProfileURL: "https://github.com/octocat"}, linked)
+	require.Contains(t, repo.linked, "founder-1")
+
+	// Buyers are refused before the code is redeemed
+	_, err = svc.LinkGitHub(ctx, "buyer-1", "code", "")
+	require.ErrorIs(t, err, ErrFounderOnly)
+	require.Equal(t, 1, github.calls)
+
+	_, err = svc.LinkGitHub(ctx, "missing", "code", "")
+	require.ErrorIs(t, err, users.ErrUserNotFound)
+
+	require.NoError(t, svc.Unlink(ctx, "founder-1", ProviderGitHub))
+	require.ErrorIs(t, svc.Unlink(ctx, "founder-1", ProviderGitHub), ErrIdentityNotFound)
+}
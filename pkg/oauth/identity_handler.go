@@ -0,0 +1,84 @@
+package oauth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/users"
+	"grveyard/pkg/validation"
+)
+
+type IdentityHandler struct {
+	service *IdentityService
+}
+
+func NewIdentityHandler(service *IdentityService) *IdentityHandler {
+	return &IdentityHandler{service: service}
+}
+
+func (h *IdentityHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/users/:uuid/identities/github", auth.RequireSelf("uuid"), h.linkGitHub)
+	router.DELETE("/users/:uuid/identities/:provider", auth.RequireSelf("uuid"), h.unlink)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *IdentityHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/users/:uuid/identities/github",
+			Tag:         "users",
+			Summary:     "Link a GitHub account",
+			Description: "Redeems the code GitHub's OAuth web flow sent to the client's redirect URI and shows the approving account on the founder's profile (GET /users/:uuid identities). The client creates and checks the flow's state parameter. Linking again replaces the previous account.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "Founder UUID"),
+			},
+			Request:  linkGitHubRequest{},
+			Response: users.LinkedIdentity{},
+			Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/users/:uuid/identities/:provider",
+			Tag:     "users",
+			Summary: "Unlink an external account",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+				openapi.Path("provider", "string", "Provider, e.g. github"),
+			},
+			Errors: []int{http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+	}
+}
+
+type linkGitHubRequest struct {
+	Code        string `json:"code" binding:"required,max=256"`
+	RedirectURI string `json:"redirect_uri" binding:"omitempty,url,max=2048"`
+}
+
+func (h *IdentityHandler) linkGitHub(c *gin.Context) {
+	var req linkGitHubRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	linked, err := h.service.LinkGitHub(c.Request.Context(), c.Param("uuid"), req.Code, req.RedirectURI)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "identity linked", linked)
+}
+
+func (h *IdentityHandler) unlink(c *gin.Context) {
+	if err := h.service.Unlink(c.Request.Context(), c.Param("uuid"), c.Param("provider")); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "identity unlinked", nil)
+}
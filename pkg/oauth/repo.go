@@ -0,0 +1,69 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/users"
+)
+
+var (
+	ErrIdentityTaken    = apperr.New(apperr.IdentityTaken, "this account is already linked to another user")
+	ErrIdentityNotFound = apperr.New(apperr.IdentityNotFound, "no linked account for that provider")
+)
+
+type IdentityRepository interface {
+	// LinkIdentity attaches id to the user, replacing any account they had linked
+	// from the same provider.
+	LinkIdentity(ctx context.Context, userUUID string, id Identity) (users.LinkedIdentity, error)
+	UnlinkIdentity(ctx context.Context, userUUID, provider string) error
+}
+
+type postgresIdentityRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresIdentityRepository(pool *pgxpool.Pool) IdentityRepository {
+	return &postgresIdentityRepository{pool: pool}
+}
+
+func (r *postgresIdentityRepository) LinkIdentity(ctx context.Context, userUUID string, id Identity) (users.LinkedIdentity, error) {
+	var linked users.LinkedIdentity
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO user_identities (user_uuid, provider, provider_user_id, username, profile_url)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_uuid, provider) DO UPDATE
+		SET provider_user_id = EXCLUDED.provider_user_id, username = EXCLUDED.username,
+		    profile_url = EXCLUDED.profile_url, updated_at = NOW()
+		RETURNING provider, username, profile_url`,
+		userUUID, id.Provider, id.Subject, id.Username, id.ProfileURL).
+		Scan(&linked.Provider, &linked.Username, &linked.ProfileURL)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505": // (provider, provider_user_id) belongs to someone else
+				return users.LinkedIdentity{}, ErrIdentityTaken
+			case "23503":
+				return users.LinkedIdentity{}, users.ErrUserNotFound
+			}
+		}
+		return users.LinkedIdentity{}, err
+	}
+	return linked, nil
+}
+
+func (r *postgresIdentityRepository) UnlinkIdentity(ctx context.Context, userUUID, provider string) error {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM user_identities WHERE user_uuid = $1 AND provider = $2`, userUUID, provider)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrIdentityNotFound
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package oauth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+	"grveyard/pkg/users"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresIdentityRepository(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+	repo := NewPostgresIdentityRepository(pool)
+	userRepo := users.NewPostgresUserRepository(pool)
+	ctx := context.Background()
+
+	founder := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	other := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	octocat := Identity{Provider: ProviderGitHub, Subject: "583231", Username: "octocat", ProfileURL: "https://github.com/octocat"}
+
+	linked, err := repo.LinkIdentity(ctx, founder.UUID, octocat)
+	require.NoError(t, err)
+	require.Equal(t, "octocat", linked.Username)
+
+	_, err = repo.LinkIdentity(ctx, other.UUID, octocat)
+	require.ErrorIs(t, err, ErrIdentityTaken)
+
+	// Relinking replaces the founder's GitHub account
+	_, err = repo.LinkIdentity(ctx, founder.UUID, Identity{Provider: ProviderGitHub, Subject: "1", Username: "hubot", ProfileURL: "https://github.com/hubot"})
+	require.NoError(t, err)
+	u, err := userRepo.GetUserByUUID(ctx, founder.UUID)
+	require.NoError(t, err)
+	require.Equal(t, []users.LinkedIdentity{{Provider: "github", Username: "hubot", ProfileURL: "https://github.com/hubot"}}, u.Identities)
+
+	// octocat is free again
+	_, err = repo.LinkIdentity(ctx, other.UUID, octocat)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.UnlinkIdentity(ctx, founder.UUID, ProviderGitHub))
+	require.ErrorIs(t, repo.UnlinkIdentity(ctx, founder.UUID, ProviderGitHub), ErrIdentityNotFound)
+	u, err = userRepo.GetUserByUUID(ctx, founder.UUID)
+	require.NoError(t, err)
+	require.Empty(t, u.Identities)
+
+	_, err = repo.LinkIdentity(ctx, "missing", Identity{Provider: ProviderGitHub, Subject: "2", Username: "monalisa"})
+	require.ErrorIs(t, err, users.ErrUserNotFound)
+}
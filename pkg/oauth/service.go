@@ -0,0 +1,99 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"grveyard/pkg/auth"
+	"grveyard/pkg/users"
+)
+
+// DefaultRole is what users created by signing in with a provider start as; they
+// can switch to founder with PUT /users/:uuid.
+const DefaultRole = "buyer"
+
+// Verifier checks a provider's ID token; *Google in production.
+type Verifier interface {
+	Verify(ctx context.Context, idToken string) (Identity, error)
+}
+
+// UserStore finds and creates users; users.UserService in production.
+type UserStore interface {
+	GetUserByEmail(ctx context.Context, email string) (users.User, error)
+	CreateUser(ctx context.Context, name, email, role, password, profilePicURL, uuid string) (users.User, error)
+}
+
+// SessionStarter issues the same tokens as password login; *auth.Service in
+// production.
+type SessionStarter interface {
+	StartSession(ctx context.Context, userUUID, role string, device auth.Device) (auth.Session, error)
+}
+
+type Service struct {
+	google   Verifier
+	users    UserStore
+	sessions SessionStarter
+}
+
+func NewService(google Verifier, users UserStore, sessions SessionStarter) *Service {
+	return &Service{google: google, users: users, sessions: sessions}
+}
+
+// SignInWithGoogle verifies a Google ID token and logs in the user with its email on
+// device, creating the user first if there is none. Banned users get
+// users.ErrAccountBanned.
+func (s *Service) SignInWithGoogle(ctx context.Context, idToken string, device auth.Device) (users.User, auth.Session, error) {
+	id, err := s.google.Verify(ctx, idToken)
+	if err != nil {
+		return users.User{}, auth.Session{}, err
+	}
+	u, err := s.findOrCreate(ctx, id)
+	if err != nil {
+		return users.User{}, auth.Session{}, err
+	}
+	if u.BannedAt != nil {
+		return users.User{}, auth.Session{}, users.ErrAccountBanned
+	}
+	session, err := s.sessions.StartSession(ctx, u.UUID, u.Role, device)
+	if err != nil {
+		return users.User{}, auth.Session{}, err
+	}
+	return u, session, nil
+}
+
+// findOrCreate matches the provider's verified email to a user, provisioning one
+// with an unguessable password when there is none; they can set a real one through
+// the password reset flow.
+func (s *Service) findOrCreate(ctx context.Context, id Identity) (users.User, error) {
+	u, err := s.users.GetUserByEmail(ctx, id.Email)
+	if err == nil || !errors.Is(err, users.ErrUserNotFound) {
+		return u, err
+	}
+
+	password, err := randomPassword()
+	if err != nil {
+		return users.User{}, err
+	}
+	name := id.Name
+	if name == "" {
+		name = id.Email
+	}
+	u, err = s.users.CreateUser(ctx, name, id.Email, DefaultRole, password, id.Picture, uuid.NewString())
+	if errors.Is(err, users.ErrUserExists) {
+		// A concurrent sign-in created them first
+		return s.users.GetUserByEmail(ctx, id.Email)
+	}
+	return u, err
+}
+
+func randomPassword() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
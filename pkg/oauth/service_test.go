@@ -0,0 +1,100 @@
+package oauth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/auth"
+	"grveyard/pkg/users"
+)
+
+type fakeVerifier struct {
+	id  Identity
+	err error
+}
+
+func (f fakeVerifier) Verify(context.Context, string) (Identity, error) { return f.id, f.err }
+
+type fakeUserStore struct {
+	byEmail map[string]users.User
+	created []users.User
+}
+
+func (f *fakeUserStore) GetUserByEmail(_ context.Context, email string) (users.User, error) {
+	u, ok := f.byEmail[email]
+	if !ok {
+		return users.User{}, users.ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (f *fakeUserStore) CreateUser(_ context.Context, name, email, role, password, profilePicURL, uuid string) (users.User, error) {
+	if _, ok := f.byEmail[email]; ok {
+		return users.User{}, users.ErrUserExists
+	}
+	u := users.User{Name: name, Email: email, Role: role, ProfilePicURL: profilePicURL, UUID: uuid}
+	f.byEmail[email] = u
+	f.created = append(f.created, u)
+	return u, nil
+}
+
+type fakeSessions struct{}
+
+func (fakeSessions) StartSession(_ context.Context, userUUID, role string, _ auth.Device) (auth.Session, error) {
+	return auth.Session{AccessToken: userUUID + ":" + role, TokenType: auth.TokenType}, nil
+}
+
+func TestService_SignInWithGoogle_ProvisionsBuyer(t *testing.T) {
+	store := &fakeUserStore{byEmail: map[string]users.User{}}
+	id := Identity{Provider: ProviderGoogle, Subject: "g-1", Email: "jane@example.com", Name: "Jane", Picture: "pic.png"}
+	svc := NewService(fakeVerifier{id: id}, store, fakeSessions{})
+
+	u, session, err := svc.SignInWithGoogle(context.Background(), "token", auth.Device{})
+	require.NoError(t, err)
+	require.Len(t, store.created, 1)
+	require.Equal(t, "Jane", u.Name)
+	require.Equal(t, DefaultRole, u.Role)
+	require.Equal(t, "pic.png", u.ProfilePicURL)
+	require.NotEmpty(t, u.UUID)
+	require.Equal(t, u.UUID+":buyer", session.AccessToken)
+
+	// Signing in again finds the same user
+	again, _, err := svc.SignInWithGoogle(context.Background(), "token", auth.Device{})
+	require.NoError(t, err)
+	require.Equal(t, u.UUID, again.UUID)
+	require.Len(t, store.created, 1)
+}
+
+func TestService_SignInWithGoogle_ExistingUser(t *testing.T) {
+	existing := users.User{UUID: "uuid-1", Email: "jane@example.com", Role: "founder"}
+	store := &fakeUserStore{byEmail: map[string]users.User{existing.Email: existing}}
+	svc := NewService(fakeVerifier{id: Identity{Email: existing.Email}}, store, fakeSessions{})
+
+	u, session, err := svc.SignInWithGoogle(context.Background(), "token", auth.Device{})
+	require.NoError(t, err)
+	require.Equal(t, existing, u)
+	require.Equal(t, "uuid-1:founder", session.AccessToken)
+	require.Empty(t, store.created)
+}
+
+func TestService_SignInWithGoogle_InvalidToken(t *testing.T) {
+	store := &fakeUserStore{byEmail: map[string]users.User{}}
+	svc := NewService(fakeVerifier{err: ErrInvalidIDToken}, store, fakeSessions{})
+
+	_, _, err := svc.SignInWithGoogle(context.Background(), "token", auth.Device{})
+	require.ErrorIs(t, err, ErrInvalidIDToken)
+	require.Empty(t, store.created)
+}
+
+func TestService_SignInWithGoogle_Banned(t *testing.T) {
+	bannedAt := time.Now()
+	existing := users.User{UUID: "uuid-1", Email: "jane@example.com", Role: "founder", BannedAt: &bannedAt}
+	store := &fakeUserStore{byEmail: map[string]users.User{existing.Email: existing}}
+	svc := NewService(fakeVerifier{id: Identity{Email: existing.Email}}, store, fakeSessions{})
+
+	_, _, err := svc.SignInWithGoogle(context.Background(), "token", auth.Device{})
+	require.ErrorIs(t, err, users.ErrAccountBanned)
+}
@@ -0,0 +1,246 @@
+package offers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+type OfferHandler struct {
+	service *Service
+}
+
+func NewOfferHandler(service *Service) *OfferHandler {
+	return &OfferHandler{service: service}
+}
+
+func (h *OfferHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/assets/:id/offers", auth.Required(), h.make)
+	router.GET("/assets/:id/offers", auth.Required(), h.listForAsset)
+	router.PATCH("/offers/:id", auth.Required(), h.respond)
+	router.GET("/users/:uuid/offers", auth.RequireSelf("uuid"), h.listByBuyer)
+	router.GET("/assets/:id/offer-rules", auth.Required(), h.rules)
+	router.PUT("/assets/:id/offer-rules", auth.Required(), h.setRules)
+}
+
+var pageParams = []openapi.Param{
+	openapi.Query("page", "integer", "Page number (default 1)", false),
+	openapi.Query("limit", "integer", "Items per page (default 20)", false),
+	openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *OfferHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/assets/:id/offers",
+			Tag:         "offers",
+			Summary:     "Make an offer",
+			Description: "Offers on non-negotiable assets must meet the asking price. The seller's offer rules may decide the offer immediately: status is rejected when it is below their private floor, or accepted when they auto-accept offers at the asking price. Otherwise it stays pending for the seller to review.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Request:  CreateOfferRequest{},
+			Response: Offer{},
+			Status:   http.StatusCreated,
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/assets/:id/offers",
+			Tag:         "offers",
+			Summary:     "List offers on an asset",
+			Description: "All offers on the asset, most recent first. Only the asset owner can list them.",
+			Params: append([]openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			}, pageParams...),
+			Response: response.Paginated[Offer]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:  http.MethodPatch,
+			Path:    "/offers/:id",
+			Tag:     "offers",
+			Summary: "Accept or reject an offer",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Offer ID"),
+			},
+			Request:  RespondRequest{},
+			Response: Offer{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/offers",
+			Tag:         "offers",
+			Summary:     "List a buyer's offers",
+			Description: "Offers the user made, most recent first, with their status",
+			Params: append([]openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			}, pageParams...),
+			Response: response.Paginated[Offer]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/assets/:id/offer-rules",
+			Tag:         "offers",
+			Summary:     "Get an asset's offer rules",
+			Description: "The seller's private negotiation bounds. Only the asset owner can read them.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Response: Rules{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/assets/:id/offer-rules",
+			Tag:         "offers",
+			Summary:     "Set an asset's offer rules",
+			Description: "min_price is a hidden floor: offers below it are rejected with a generic reply that does not reveal it. auto_accept_asking accepts offers at or above the asking price without review. Only negotiable assets take rules, and the floor cannot exceed the asking price.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Request:  RulesRequest{},
+			Response: Rules{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+const offersPageSize = 20
+
+func parseID(c *gin.Context, what string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid "+what+" id"))
+		return 0, false
+	}
+	return id, true
+}
+
+// offerReplies are what the buyer is told about a new offer. A rejection reads the
+// same whether the floor or the seller made it.
+var offerReplies = map[Status]string{
+	StatusPending:  "offer sent to the seller",
+	StatusAccepted: "offer accepted",
+	StatusRejected: "thank you for your offer, but the seller is unable to accept it",
+}
+
+func (h *OfferHandler) make(c *gin.Context) {
+	id, ok := parseID(c, "asset")
+	if !ok {
+		return
+	}
+	var req CreateOfferRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	offer, err := h.service.Make(c.Request.Context(), id, auth.UserID(c), req)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusCreated, true, offerReplies[offer.Status], offer)
+}
+
+func (h *OfferHandler) listForAsset(c *gin.Context) {
+	id, ok := parseID(c, "asset")
+	if !ok {
+		return
+	}
+	p, err := pagination.FromRequest(c, offersPageSize)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	list, total, err := h.service.ListForAsset(c.Request.Context(), id, auth.UserID(c), p)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "offers listed", list, total, p)
+}
+
+func (h *OfferHandler) respond(c *gin.Context) {
+	id, ok := parseID(c, "offer")
+	if !ok {
+		return
+	}
+	var req RespondRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	offer, err := h.service.Respond(c.Request.Context(), id, auth.UserID(c), req)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "offer "+string(offer.Status), offer)
+}
+
+func (h *OfferHandler) listByBuyer(c *gin.Context) {
+	p, err := pagination.FromRequest(c, offersPageSize)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	list, total, err := h.service.ListByBuyer(c.Request.Context(), c.Param("uuid"), p)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "offers listed", list, total, p)
+}
+
+func (h *OfferHandler) rules(c *gin.Context) {
+	id, ok := parseID(c, "asset")
+	if !ok {
+		return
+	}
+
+	rules, err := h.service.Rules(c.Request.Context(), id, auth.UserID(c))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "offer rules fetched", rules)
+}
+
+func (h *OfferHandler) setRules(c *gin.Context) {
+	id, ok := parseID(c, "asset")
+	if !ok {
+		return
+	}
+	var req RulesRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	rules, err := h.service.SetRules(c.Request.Context(), id, auth.UserID(c), req)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "offer rules saved", rules)
+}
@@ -0,0 +1,61 @@
+package offers
+
+import "time"
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusAccepted Status = "accepted"
+	StatusRejected Status = "rejected"
+)
+
+// Offer is a buyer's proposed price for an asset.
+type Offer struct {
+	ID         int64     `json:"id"`
+	AssetID    int64     `json:"asset_id"`
+	AssetTitle string    `json:"asset_title"`
+	BuyerUUID  string    `json:"buyer_uuid"`
+	SellerUUID string    `json:"seller_uuid"`
+	Amount     float64   `json:"amount"`
+	Message    string    `json:"message,omitempty"`
+	Status     Status    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Rules are a seller's private negotiation bounds for one listing. They are never
+// shown to buyers.
+type Rules struct {
+	AssetID int64 `json:"asset_id"`
+	// MinPrice rejects offers below it automatically; nil accepts any offer for review
+	MinPrice *float64 `json:"min_price"`
+	// AutoAcceptAsking accepts offers at or above the asking price without review
+	AutoAcceptAsking bool      `json:"auto_accept_asking"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Listing is what the service needs to know about the asset an offer is for.
+type Listing struct {
+	OwnerUUID  string
+	Title      string
+	Price      *float64
+	Negotiable bool
+	Sold       bool
+	Active     bool
+}
+
+type CreateOfferRequest struct {
+	Amount  float64 `json:"amount" binding:"required,gt=0"`
+	Message string  `json:"message" binding:"max=1000"`
+}
+
+// RespondRequest is the seller accepting or rejecting a pending offer.
+type RespondRequest struct {
+	Status Status `json:"status" binding:"required,oneof=accepted rejected"`
+}
+
+type RulesRequest struct {
+	MinPrice         *float64 `json:"min_price" binding:"omitempty,gte=0"`
+	AutoAcceptAsking bool     `json:"auto_accept_asking"`
+}
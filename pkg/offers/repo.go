@@ -0,0 +1,144 @@
+package offers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OfferRepository interface {
+	Listing(ctx context.Context, assetID int64) (Listing, error)
+	// Rules returns the asset's negotiation bounds; assets without any get the zero
+	// Rules.
+	Rules(ctx context.Context, assetID int64) (Rules, error)
+	SaveRules(ctx context.Context, rules Rules) (Rules, error)
+	Create(ctx context.Context, offer Offer) (Offer, error)
+	Get(ctx context.Context, id int64) (Offer, error)
+	// Respond moves a pending offer to status; offers already decided return
+	// ErrNotPending.
+	Respond(ctx context.Context, id int64, status Status) (Offer, error)
+	ListByAsset(ctx context.Context, assetID int64, limit, offset int) ([]Offer, int64, error)
+	ListByBuyer(ctx context.Context, buyerUUID string, limit, offset int) ([]Offer, int64, error)
+}
+
+type postgresOfferRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresOfferRepository(pool *pgxpool.Pool) OfferRepository {
+	return &postgresOfferRepository{pool: pool}
+}
+
+func (r *postgresOfferRepository) Listing(ctx context.Context, assetID int64) (Listing, error) {
+	query := `SELECT user_uuid, title, price, is_negotiable, is_sold, is_active
+			  FROM assets WHERE id = $1 AND is_deleted = false`
+	var l Listing
+	err := r.pool.QueryRow(ctx, query, assetID).Scan(&l.OwnerUUID, &l.Title, &l.Price, &l.Negotiable, &l.Sold, &l.Active)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Listing{}, ErrAssetNotFound
+	}
+	return l, err
+}
+
+func (r *postgresOfferRepository) Rules(ctx context.Context, assetID int64) (Rules, error) {
+	query := `SELECT min_price, auto_accept_asking, updated_at FROM asset_offer_rules WHERE asset_id = $1`
+	rules := Rules{AssetID: assetID}
+	err := r.pool.QueryRow(ctx, query, assetID).Scan(&rules.MinPrice, &rules.AutoAcceptAsking, &rules.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return rules, nil
+	}
+	return rules, err
+}
+
+func (r *postgresOfferRepository) SaveRules(ctx context.Context, rules Rules) (Rules, error) {
+	query := `INSERT INTO asset_offer_rules (asset_id, min_price, auto_accept_asking)
+			  VALUES ($1, $2, $3)
+			  ON CONFLICT (asset_id) DO UPDATE SET
+				min_price = EXCLUDED.min_price,
+				auto_accept_asking = EXCLUDED.auto_accept_asking,
+				updated_at = NOW()
+			  RETURNING updated_at`
+	err := r.pool.QueryRow(ctx, query, rules.AssetID, rules.MinPrice, rules.AutoAcceptAsking).Scan(&rules.UpdatedAt)
+	return rules, err
+}
+
+const offerColumns = `o.id, o.asset_id, a.title, o.buyer_uuid, a.user_uuid, o.amount, o.message, o.status, o.created_at, o.updated_at`
+
+func scanOffer(row pgx.Row) (Offer, error) {
+	var o Offer
+	err := row.Scan(&o.ID, &o.AssetID, &o.AssetTitle, &o.BuyerUUID, &o.SellerUUID, &o.Amount, &o.Message, &o.Status, &o.CreatedAt, &o.UpdatedAt)
+	return o, err
+}
+
+func (r *postgresOfferRepository) Create(ctx context.Context, offer Offer) (Offer, error) {
+	query := `WITH o AS (
+				INSERT INTO offers (asset_id, buyer_uuid, amount, message, status)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING *
+			  )
+			  SELECT ` + offerColumns + ` FROM o JOIN assets a ON a.id = o.asset_id`
+	return scanOffer(r.pool.QueryRow(ctx, query, offer.AssetID, offer.BuyerUUID, offer.Amount, offer.Message, offer.Status))
+}
+
+func (r *postgresOfferRepository) Get(ctx context.Context, id int64) (Offer, error) {
+	query := `SELECT ` + offerColumns + ` FROM offers o JOIN assets a ON a.id = o.asset_id WHERE o.id = $1`
+	o, err := scanOffer(r.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Offer{}, ErrOfferNotFound
+	}
+	return o, err
+}
+
+func (r *postgresOfferRepository) Respond(ctx context.Context, id int64, status Status) (Offer, error) {
+	query := `WITH o AS (
+				UPDATE offers SET status = $2, updated_at = NOW()
+				WHERE id = $1 AND status = 'pending'
+				RETURNING *
+			  )
+			  SELECT ` + offerColumns + ` FROM o JOIN assets a ON a.id = o.asset_id`
+	o, err := scanOffer(r.pool.QueryRow(ctx, query, id, status))
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := r.Get(ctx, id); err != nil {
+			return Offer{}, err
+		}
+		return Offer{}, ErrNotPending
+	}
+	return o, err
+}
+
+func (r *postgresOfferRepository) ListByAsset(ctx context.Context, assetID int64, limit, offset int) ([]Offer, int64, error) {
+	return r.list(ctx, `o.asset_id = $1`, assetID, limit, offset)
+}
+
+func (r *postgresOfferRepository) ListByBuyer(ctx context.Context, buyerUUID string, limit, offset int) ([]Offer, int64, error) {
+	return r.list(ctx, `o.buyer_uuid = $1`, buyerUUID, limit, offset)
+}
+
+func (r *postgresOfferRepository) list(ctx context.Context, where string, arg any, limit, offset int) ([]Offer, int64, error) {
+	var total int64
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM offers o WHERE `+where, arg).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + offerColumns + ` FROM offers o JOIN assets a ON a.id = o.asset_id
+			  WHERE ` + where + `
+			  ORDER BY o.created_at DESC, o.id DESC
+			  LIMIT $2 OFFSET $3`
+	rows, err := r.pool.Query(ctx, query, arg, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	list := []Offer{}
+	for rows.Next() {
+		o, err := scanOffer(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, o)
+	}
+	return list, total, rows.Err()
+}
@@ -0,0 +1,61 @@
+package offers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresOfferRepository(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresOfferRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	buyer := testhelpers.NewUser(t, pool)
+	asset := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetTitle("Old App"), testhelpers.WithPrice(100))
+
+	listing, err := repo.Listing(ctx, asset.ID)
+	require.NoError(t, err)
+	require.Equal(t, seller.UUID, listing.OwnerUUID)
+	require.Equal(t, 100.0, *listing.Price)
+
+	rules, err := repo.Rules(ctx, asset.ID)
+	require.NoError(t, err)
+	require.Nil(t, rules.MinPrice)
+	floor := 80.0
+	_, err = repo.SaveRules(ctx, Rules{AssetID: asset.ID, MinPrice: &floor, AutoAcceptAsking: true})
+	require.NoError(t, err)
+	rules, err = repo.Rules(ctx, asset.ID)
+	require.NoError(t, err)
+	require.Equal(t, 80.0, *rules.MinPrice)
+	require.True(t, rules.AutoAcceptAsking)
+
+	offer, err := repo.Create(ctx, Offer{AssetID: asset.ID, BuyerUUID: buyer.UUID, Amount: 90, Message: "Would you take 90?", Status: StatusPending})
+	require.NoError(t, err)
+	require.Equal(t, "Old App", offer.AssetTitle)
+	require.Equal(t, seller.UUID, offer.SellerUUID)
+
+	offer, err = repo.Respond(ctx, offer.ID, StatusAccepted)
+	require.NoError(t, err)
+	require.Equal(t, StatusAccepted, offer.Status)
+	_, err = repo.Respond(ctx, offer.ID, StatusRejected)
+	require.ErrorIs(t, err, ErrNotPending)
+	_, err = repo.Respond(ctx, 999999, StatusRejected)
+	require.ErrorIs(t, err, ErrOfferNotFound)
+
+	list, total, err := repo.ListByAsset(ctx, asset.ID, 20, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, offer.ID, list[0].ID)
+	list, total, err = repo.ListByBuyer(ctx, buyer.UUID, 20, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, StatusAccepted, list[0].Status)
+}
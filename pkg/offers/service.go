@@ -0,0 +1,204 @@
+// Package offers records buyers' price offers on assets. Sellers can set private
+// bounds per listing: offers below a hidden floor are rejected straight away and,
+// optionally, offers at or above the asking price are accepted without review.
+// Offers are also summarized in the buyer and seller's chat, so the negotiation and
+// the conversation around it stay in one thread.
+package offers
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/notifications"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/requestid"
+)
+
+var (
+	ErrAssetNotFound  = apperr.New(apperr.AssetNotFound, "asset not found")
+	ErrOfferNotFound  = apperr.New(apperr.OfferNotFound, "offer not found")
+	ErrNotOwner       = apperr.New(apperr.Forbidden, "only the asset owner can do that")
+	ErrOwnAsset       = apperr.New(apperr.Forbidden, "you cannot make an offer on your own asset")
+	ErrNotAvailable   = apperr.New(apperr.AlreadySold, "asset is no longer available")
+	ErrNotNegotiable  = apperr.New(apperr.InvalidPrice, "this asset's price is not negotiable")
+	ErrNotPending     = apperr.New(apperr.OfferAlreadyDecided, "offer has already been accepted or rejected")
+	ErrInvalidBounds  = apperr.New(apperr.InvalidPrice, "min_price cannot be above the asking price")
+	ErrBoundsRequired = apperr.New(apperr.InvalidPrice, "negotiation bounds only apply to negotiable assets")
+	ErrBlocked        = apperr.New(apperr.UserBlocked, "you cannot make an offer to this user")
+)
+
+// Conversations posts system messages into the chat between two users; the chat
+// handler in production.
+type Conversations interface {
+	PostOfferMessage(ctx context.Context, senderUUID, receiverUUID, content string, offerID int64) error
+}
+
+// BlockChecker reports whether either of two users blocked the other;
+// *users.BlockService in production.
+type BlockChecker interface {
+	IsBlocked(ctx context.Context, a, b string) (bool, error)
+}
+
+type Service struct {
+	repo      OfferRepository
+	publisher notifications.Publisher // optional; if nil, sellers are not notified
+	chat      Conversations           // optional; if nil, offers are not posted to chat
+	blocks    BlockChecker            // optional; if nil, blocked users can still make offers
+}
+
+func NewService(repo OfferRepository, publisher notifications.Publisher, chat Conversations, blocks BlockChecker) *Service {
+	return &Service{repo: repo, publisher: publisher, chat: chat, blocks: blocks}
+}
+
+// Make records buyerUUID's offer, applying the listing's bounds. Buyers and sellers
+// who blocked each other cannot negotiate, just as they cannot chat. An offer below the
+// floor is stored as rejected and the buyer gets the same reply as for any rejection,
+// so the floor is never revealed. Offers left pending, and those accepted automatically,
+// are announced to the seller and summarized in the buyer and seller's chat.
+func (s *Service) Make(ctx context.Context, assetID int64, buyerUUID string, req CreateOfferRequest) (Offer, error) {
+	listing, err := s.repo.Listing(ctx, assetID)
+	if err != nil {
+		return Offer{}, err
+	}
+	if listing.OwnerUUID == buyerUUID {
+		return Offer{}, ErrOwnAsset
+	}
+	if s.blocks != nil {
+		blocked, err := s.blocks.IsBlocked(ctx, buyerUUID, listing.OwnerUUID)
+		if err != nil {
+			return Offer{}, err
+		}
+		if blocked {
+			return Offer{}, ErrBlocked
+		}
+	}
+	if listing.Sold || !listing.Active {
+		return Offer{}, ErrNotAvailable
+	}
+	atAsking := listing.Price != nil && req.Amount >= *listing.Price
+	if !listing.Negotiable && listing.Price != nil && !atAsking {
+		return Offer{}, ErrNotNegotiable
+	}
+
+	rules, err := s.repo.Rules(ctx, assetID)
+	if err != nil {
+		return Offer{}, err
+	}
+	status := StatusPending
+	switch {
+	case rules.MinPrice != nil && req.Amount < *rules.MinPrice:
+		status = StatusRejected
+	case rules.AutoAcceptAsking && atAsking:
+		status = StatusAccepted
+	}
+
+	offer, err := s.repo.Create(ctx, Offer{
+		AssetID:   assetID,
+		BuyerUUID: buyerUUID,
+		Amount:    req.Amount,
+		Message:   req.Message,
+		Status:    status,
+	})
+	if err != nil {
+		return Offer{}, err
+	}
+	if status != StatusRejected && s.publisher != nil {
+		s.publisher.Publish(ctx, notifications.Event{
+			Type:          notifications.EventOfferReceived,
+			RecipientUUID: listing.OwnerUUID,
+			ActorUUID:     buyerUUID,
+			EntityID:      assetID,
+			Title:         listing.Title,
+			Amount:        req.Amount,
+		})
+	}
+	if status != StatusRejected {
+		summary := fmt.Sprintf("Made an offer of %.2f for %q (%s).", offer.Amount, listing.Title, offer.Status)
+		if offer.Message != "" {
+			summary += "\n\n" + offer.Message
+		}
+		s.post(ctx, offer.BuyerUUID, listing.OwnerUUID, summary, offer.ID)
+	}
+	return offer, nil
+}
+
+// Respond lets the seller, userUUID, accept or reject a pending offer.
+func (s *Service) Respond(ctx context.Context, offerID int64, userUUID string, req RespondRequest) (Offer, error) {
+	offer, err := s.repo.Get(ctx, offerID)
+	if err != nil {
+		return Offer{}, err
+	}
+	if offer.SellerUUID != userUUID {
+		return Offer{}, ErrNotOwner
+	}
+	offer, err = s.repo.Respond(ctx, offerID, req.Status)
+	if err != nil {
+		return Offer{}, err
+	}
+	verb := "Accepted"
+	if offer.Status == StatusRejected {
+		verb = "Rejected"
+	}
+	s.post(ctx, offer.SellerUUID, offer.BuyerUUID, fmt.Sprintf("%s the offer of %.2f for %q.", verb, offer.Amount, offer.AssetTitle), offer.ID)
+	return offer, nil
+}
+
+// post adds an offer summary to the chat. The offer is already recorded, so a chat
+// failure is logged rather than failing the request.
+func (s *Service) post(ctx context.Context, senderUUID, receiverUUID, content string, offerID int64) {
+	if s.chat == nil {
+		return
+	}
+	if err := s.chat.PostOfferMessage(ctx, senderUUID, receiverUUID, content, offerID); err != nil {
+		log.Printf("[%s] offer %d not posted to chat: %v", requestid.FromContext(ctx), offerID, err)
+	}
+}
+
+// ListForAsset returns the offers on an asset to its owner, newest first.
+func (s *Service) ListForAsset(ctx context.Context, assetID int64, userUUID string, p pagination.Params) ([]Offer, int64, error) {
+	if _, err := s.owned(ctx, assetID, userUUID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListByAsset(ctx, assetID, p.Limit, p.Offset())
+}
+
+// ListByBuyer returns the offers buyerUUID made, newest first.
+func (s *Service) ListByBuyer(ctx context.Context, buyerUUID string, p pagination.Params) ([]Offer, int64, error) {
+	return s.repo.ListByBuyer(ctx, buyerUUID, p.Limit, p.Offset())
+}
+
+// Rules returns an asset's negotiation bounds to its owner.
+func (s *Service) Rules(ctx context.Context, assetID int64, userUUID string) (Rules, error) {
+	if _, err := s.owned(ctx, assetID, userUUID); err != nil {
+		return Rules{}, err
+	}
+	return s.repo.Rules(ctx, assetID)
+}
+
+// SetRules replaces the negotiation bounds of an asset owned by userUUID.
+func (s *Service) SetRules(ctx context.Context, assetID int64, userUUID string, req RulesRequest) (Rules, error) {
+	listing, err := s.owned(ctx, assetID, userUUID)
+	if err != nil {
+		return Rules{}, err
+	}
+	if !listing.Negotiable && (req.MinPrice != nil || req.AutoAcceptAsking) {
+		return Rules{}, ErrBoundsRequired
+	}
+	if req.MinPrice != nil && listing.Price != nil && *req.MinPrice > *listing.Price {
+		return Rules{}, ErrInvalidBounds
+	}
+	return s.repo.SaveRules(ctx, Rules{AssetID: assetID, MinPrice: req.MinPrice, AutoAcceptAsking: req.AutoAcceptAsking})
+}
+
+func (s *Service) owned(ctx context.Context, assetID int64, userUUID string) (Listing, error) {
+	listing, err := s.repo.Listing(ctx, assetID)
+	if err != nil {
+		return Listing{}, err
+	}
+	if listing.OwnerUUID != userUUID {
+		return Listing{}, ErrNotOwner
+	}
+	return listing, nil
+}
@@ -0,0 +1,219 @@
+package offers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/notifications"
+)
+
+type mockOfferRepository struct {
+	mock.Mock
+}
+
+func (m *mockOfferRepository) Listing(ctx context.Context, assetID int64) (Listing, error) {
+	args := m.Called(ctx, assetID)
+	out, _ := args.Get(0).(Listing)
+	return out, args.Error(1)
+}
+
+func (m *mockOfferRepository) Rules(ctx context.Context, assetID int64) (Rules, error) {
+	args := m.Called(ctx, assetID)
+	out, _ := args.Get(0).(Rules)
+	return out, args.Error(1)
+}
+
+func (m *mockOfferRepository) SaveRules(ctx context.Context, rules Rules) (Rules, error) {
+	args := m.Called(ctx, rules)
+	out, _ := args.Get(0).(Rules)
+	return out, args.Error(1)
+}
+
+func (m *mockOfferRepository) Create(ctx context.Context, offer Offer) (Offer, error) {
+	args := m.Called(ctx, offer)
+	out, _ := args.Get(0).(Offer)
+	return out, args.Error(1)
+}
+
+func (m *mockOfferRepository) Get(ctx context.Context, id int64) (Offer, error) {
+	args := m.Called(ctx, id)
+	out, _ := args.Get(0).(Offer)
+	return out, args.Error(1)
+}
+
+func (m *mockOfferRepository) Respond(ctx context.Context, id int64, status Status) (Offer, error) {
+	args := m.Called(ctx, id, status)
+	out, _ := args.Get(0).(Offer)
+	return out, args.Error(1)
+}
+
+func (m *mockOfferRepository) ListByAsset(ctx context.Context, assetID int64, limit, offset int) ([]Offer, int64, error) {
+	args := m.Called(ctx, assetID, limit, offset)
+	out, _ := args.Get(0).([]Offer)
+	return out, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockOfferRepository) ListByBuyer(ctx context.Context, buyerUUID string, limit, offset int) ([]Offer, int64, error) {
+	args := m.Called(ctx, buyerUUID, limit, offset)
+	out, _ := args.Get(0).([]Offer)
+	return out, args.Get(1).(int64), args.Error(2)
+}
+
+type mockPublisher struct {
+	events []notifications.Event
+}
+
+func (p *mockPublisher) Publish(ctx context.Context, ev notifications.Event) {
+	p.events = append(p.events, ev)
+}
+
+type chatMessage struct {
+	sender, receiver, content string
+	offerID                   int64
+}
+
+type mockConversations struct {
+	messages []chatMessage
+}
+
+func (c *mockConversations) PostOfferMessage(ctx context.Context, senderUUID, receiverUUID, content string, offerID int64) error {
+	c.messages = append(c.messages, chatMessage{senderUUID, receiverUUID, content, offerID})
+	return nil
+}
+
+// blockedPairs treats every listed pair as blocked, in either direction.
+type blockedPairs [][2]string
+
+func (b blockedPairs) IsBlocked(_ context.Context, x, y string) (bool, error) {
+	for _, pair := range b {
+		if pair == [2]string{x, y} || pair == [2]string{y, x} {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func price(v float64) *float64 { return &v }
+
+func TestService_Make_AppliesRules(t *testing.T) {
+	cases := []struct {
+		name   string
+		rules  Rules
+		amount float64
+		want   Status
+		notify bool
+	}{
+		{"no rules", Rules{}, 50, StatusPending, true},
+		{"below floor", Rules{MinPrice: price(80)}, 79.99, StatusRejected, false},
+		{"at floor", Rules{MinPrice: price(80)}, 80, StatusPending, true},
+		{"at asking, auto accept", Rules{MinPrice: price(80), AutoAcceptAsking: true}, 100, StatusAccepted, true},
+		{"above asking, auto accept", Rules{AutoAcceptAsking: true}, 120, StatusAccepted, true},
+		{"at asking, no auto accept", Rules{MinPrice: price(80)}, 100, StatusPending, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(mockOfferRepository)
+			pub := &mockPublisher{}
+			chat := &mockConversations{}
+			service := NewService(repo, pub, chat, nil)
+
+			repo.On("Listing", mock.Anything, int64(3)).Return(Listing{OwnerUUID: "seller", Title: "Old App", Price: price(100), Negotiable: true, Active: true}, nil)
+			repo.On("Rules", mock.Anything, int64(3)).Return(tc.rules, nil)
+			repo.On("Create", mock.Anything, Offer{AssetID: 3, BuyerUUID: "buyer", Amount: tc.amount, Status: tc.want}).
+				Return(Offer{ID: 1, AssetID: 3, BuyerUUID: "buyer", SellerUUID: "seller", Amount: tc.amount, Status: tc.want}, nil)
+
+			offer, err := service.Make(context.Background(), 3, "buyer", CreateOfferRequest{Amount: tc.amount})
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, offer.Status)
+			if tc.notify {
+				require.Equal(t, []notifications.Event{{
+					Type: notifications.EventOfferReceived, RecipientUUID: "seller", ActorUUID: "buyer", EntityID: 3, Title: "Old App", Amount: tc.amount,
+				}}, pub.events)
+				require.Len(t, chat.messages, 1)
+				require.Equal(t, "buyer", chat.messages[0].sender)
+				require.Equal(t, "seller", chat.messages[0].receiver)
+				require.Equal(t, int64(1), chat.messages[0].offerID)
+			} else {
+				require.Empty(t, pub.events)
+				require.Empty(t, chat.messages, "floor rejections must not reach the seller's chat")
+			}
+		})
+	}
+}
+
+func TestService_Make_Refused(t *testing.T) {
+	cases := []struct {
+		name    string
+		listing Listing
+		buyer   string
+		amount  float64
+		want    error
+	}{
+		{"own asset", Listing{OwnerUUID: "seller", Negotiable: true, Active: true}, "seller", 10, ErrOwnAsset},
+		{"sold", Listing{OwnerUUID: "seller", Negotiable: true, Active: true, Sold: true}, "buyer", 10, ErrNotAvailable},
+		{"unlisted", Listing{OwnerUUID: "seller", Negotiable: true}, "buyer", 10, ErrNotAvailable},
+		{"fixed price", Listing{OwnerUUID: "seller", Price: price(100), Active: true}, "buyer", 99, ErrNotNegotiable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(mockOfferRepository)
+			repo.On("Listing", mock.Anything, int64(3)).Return(tc.listing, nil)
+
+			_, err := NewService(repo, nil, nil, nil).Make(context.Background(), 3, tc.buyer, CreateOfferRequest{Amount: tc.amount})
+
+			require.ErrorIs(t, err, tc.want)
+			repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_Make_Blocked(t *testing.T) {
+	repo := new(mockOfferRepository)
+	chat := &mockConversations{}
+	repo.On("Listing", mock.Anything, int64(3)).Return(Listing{OwnerUUID: "seller", Title: "Old App", Negotiable: true, Active: true}, nil)
+
+	_, err := NewService(repo, nil, chat, blockedPairs{{"seller", "buyer"}}).Make(context.Background(), 3, "buyer", CreateOfferRequest{Amount: 10})
+
+	require.ErrorIs(t, err, ErrBlocked)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	require.Empty(t, chat.messages)
+}
+
+func TestService_SetRules(t *testing.T) {
+	repo := new(mockOfferRepository)
+	service := NewService(repo, nil, nil, nil)
+	repo.On("Listing", mock.Anything, int64(3)).Return(Listing{OwnerUUID: "seller", Price: price(100), Negotiable: true, Active: true}, nil)
+	repo.On("SaveRules", mock.Anything, Rules{AssetID: 3, MinPrice: price(75), AutoAcceptAsking: true}).
+		Return(Rules{AssetID: 3, MinPrice: price(75), AutoAcceptAsking: true}, nil)
+
+	rules, err := service.SetRules(context.Background(), 3, "seller", RulesRequest{MinPrice: price(75), AutoAcceptAsking: true})
+	require.NoError(t, err)
+	require.Equal(t, 75.0, *rules.MinPrice)
+
+	_, err = service.SetRules(context.Background(), 3, "seller", RulesRequest{MinPrice: price(150)})
+	require.ErrorIs(t, err, ErrInvalidBounds)
+
+	_, err = service.SetRules(context.Background(), 3, "buyer", RulesRequest{MinPrice: price(10)})
+	require.ErrorIs(t, err, ErrNotOwner)
+}
+
+func TestService_Respond_SellerOnly(t *testing.T) {
+	repo := new(mockOfferRepository)
+	chat := &mockConversations{}
+	service := NewService(repo, nil, chat, nil)
+	repo.On("Get", mock.Anything, int64(8)).Return(Offer{ID: 8, SellerUUID: "seller", BuyerUUID: "buyer", Status: StatusPending}, nil)
+	repo.On("Respond", mock.Anything, int64(8), StatusAccepted).
+		Return(Offer{ID: 8, AssetTitle: "Old App", SellerUUID: "seller", BuyerUUID: "buyer", Amount: 90, Status: StatusAccepted}, nil)
+
+	_, err := service.Respond(context.Background(), 8, "buyer", RespondRequest{Status: StatusAccepted})
+	require.ErrorIs(t, err, ErrNotOwner)
+
+	offer, err := service.Respond(context.Background(), 8, "seller", RespondRequest{Status: StatusAccepted})
+	require.NoError(t, err)
+	require.Equal(t, StatusAccepted, offer.Status)
+	require.Equal(t, []chatMessage{{"seller", "buyer", `Accepted the offer of 90.00 for "Old App".`, 8}}, chat.messages)
+}
@@ -0,0 +1,292 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/response"
+)
+
+// BearerAuth is the security scheme name used by operations with Auth set.
+const BearerAuth = "BearerAuth"
+
+// Operation describes one route. Handlers return them from Operations, next to
+// RegisterRoutes, using the same request and response structs the handler binds.
+type Operation struct {
+	Method       string // http.MethodGet, ...
+	Path         string // as registered with gin, e.g. "/assets/:id"
+	Tag          string
+	Summary      string
+	Description  string
+	Params       []Param // query/header parameters, and descriptions for path parameters
+	Request      any     // zero value of the JSON request body, if any
+	OptionalBody bool    // the request body may be omitted
+	Response     any     // zero value of the envelope's data field, if any
+	Status       int     // success status; 200 when zero
+	Errors       []int   // error statuses, all using the error envelope
+	Auth         bool    // requires a bearer token: an access token, or ADMIN_API_TOKEN under /admin
+	Raw          bool    // Response is the whole JSON body rather than the envelope's data
+	ContentType  string  // success content type for non-JSON bodies, e.g. "application/xml"
+}
+
+type Param struct {
+	Name        string
+	In          string // "query", "path" or "header"
+	Type        string // JSON schema type; "string" when empty
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+// Query is a query parameter.
+func Query(name, typ, description string, required bool) Param {
+	return Param{Name: name, In: "query", Type: typ, Description: description, Required: required}
+}
+
+// Path describes a path parameter; path parameters without one default to strings
+// ("id" defaults to an int64).
+func Path(name, typ, description string) Param {
+	return Param{Name: name, In: "path", Type: typ, Description: description, Required: true}
+}
+
+// Describer is implemented by handlers that document their routes.
+type Describer interface {
+	Operations() []Operation
+}
+
+// Build generates the spec for the routes registered on the engine. Every registered
+// route is listed, so nothing can be missing from the docs; described routes carry
+// their schemas, and descriptions of routes that were not registered (disabled by
+// configuration) are dropped.
+func Build(info Info, servers []Server, routes gin.RoutesInfo, describers ...Describer) *Document {
+	described := map[string]Operation{}
+	for _, d := range describers {
+		for _, op := range d.Operations() {
+			described[op.Method+" "+op.Path] = op
+		}
+	}
+
+	s := newSchemas()
+	envelope := s.of(response.APIResponse{})
+	s.components["ErrorResponse"] = &Schema{AllOf: []*Schema{envelope, {
+		Type:        "object",
+		Description: "success is false; request_id identifies the request in server logs",
+		Properties:  map[string]*Schema{"error_code": errorCodes()},
+		Required:    []string{"success", "message", "error_code"},
+	}}}
+	s.of(response.Problem{})
+	s.components["ValidationErrorResponse"] = &Schema{AllOf: []*Schema{Ref("ErrorResponse"), {
+		Type:       "object",
+		Properties: map[string]*Schema{"data": s.of(response.ValidationErrors{})},
+	}}}
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Servers: servers,
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas: s.components,
+			SecuritySchemes: map[string]SecurityScheme{
+				BearerAuth: {Type: "http", Scheme: "bearer", Description: "Access token from POST /users/login; ADMIN_API_TOKEN for /admin routes"},
+			},
+		},
+	}
+
+	sorted := append(gin.RoutesInfo(nil), routes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].Method < sorted[j].Method
+	})
+	for _, r := range sorted {
+		op, ok := described[r.Method+" "+r.Path]
+		if !ok {
+			op = Operation{Method: r.Method, Path: r.Path, Tag: tagFor(r.Path)}
+		}
+		p := specPath(r.Path)
+		if doc.Paths[p] == nil {
+			doc.Paths[p] = &PathItem{}
+		}
+		(*doc.Paths[p])[strings.ToLower(r.Method)] = s.operation(op, envelope)
+	}
+
+	return doc
+}
+
+func (s *schemas) operation(op Operation, envelope *Schema) *OperationObject {
+	out := &OperationObject{
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: operationID(op.Method, op.Path),
+		Responses:   map[string]ResponseObj{},
+	}
+	if op.Tag != "" {
+		out.Tags = []string{op.Tag}
+	}
+	if op.Auth {
+		out.Security = []map[string][]string{{BearerAuth: {}}}
+	}
+
+	documented := map[string]Param{}
+	for _, p := range op.Params {
+		if p.In == "path" {
+			documented[p.Name] = p
+		}
+	}
+	for _, name := range pathParams(op.Path) {
+		p, ok := documented[name]
+		if !ok {
+			p = Path(name, "string", "")
+			if name == "id" {
+				p.Type = "integer"
+			}
+		}
+		out.Parameters = append(out.Parameters, parameter(p))
+	}
+	for _, p := range op.Params {
+		if p.In != "path" {
+			out.Parameters = append(out.Parameters, parameter(p))
+		}
+	}
+
+	if op.Request != nil {
+		out.RequestBody = &RequestBody{
+			Required: !op.OptionalBody,
+			Content:  map[string]MediaType{"application/json": {Schema: s.of(op.Request)}},
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := ResponseObj{Description: http.StatusText(status)}
+	switch {
+	case op.ContentType != "":
+		success.Content = map[string]MediaType{op.ContentType: {Schema: &Schema{Type: "string"}}}
+	case op.Raw:
+		success.Content = jsonContent(s.of(op.Response))
+	case op.Response != nil:
+		success.Content = jsonContent(&Schema{AllOf: []*Schema{envelope, {
+			Type:       "object",
+			Properties: map[string]*Schema{"data": s.of(op.Response)},
+		}}})
+	default:
+		success.Content = jsonContent(envelope)
+	}
+	out.Responses[strconv.Itoa(status)] = success
+
+	for _, code := range op.Errors {
+		schema := Ref("ErrorResponse")
+		if code == http.StatusBadRequest && op.Request != nil {
+			schema = Ref("ValidationErrorResponse")
+		}
+		out.Responses[strconv.Itoa(code)] = ResponseObj{Description: http.StatusText(code), Content: errorContent(schema)}
+	}
+	if op.Auth {
+		if _, ok := out.Responses["401"]; !ok {
+			out.Responses["401"] = ResponseObj{Description: http.StatusText(http.StatusUnauthorized), Content: errorContent(Ref("ErrorResponse"))}
+		}
+	}
+
+	return out
+}
+
+func parameter(p Param) ParameterObject {
+	typ := p.Type
+	if typ == "" {
+		typ = "string"
+	}
+	schema := &Schema{Type: typ, Enum: p.Enum}
+	if typ == "integer" {
+		schema.Format = "int64"
+	}
+	return ParameterObject{Name: p.Name, In: p.In, Description: p.Description, Required: p.Required || p.In == "path", Schema: schema}
+}
+
+// errorContent offers the envelope and, for clients that ask for it, RFC 7807.
+func errorContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{
+		"application/json":          {Schema: schema},
+		response.ProblemContentType: {Schema: Ref("response.Problem")},
+	}
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// errorCodes documents the apperr registry: clients switch on error_code, so every
+// code is listed with its status and meaning.
+func errorCodes() *Schema {
+	defs := apperr.Definitions()
+	enum := make([]string, len(defs))
+	var desc strings.Builder
+	desc.WriteString("Stable, machine-readable error code:\n")
+	for i, d := range defs {
+		enum[i] = string(d.Code)
+		fmt.Fprintf(&desc, "\n- `%s` (%d): %s", d.Code, d.Status, d.Description)
+	}
+	return &Schema{Type: "string", Enum: enum, Description: desc.String()}
+}
+
+// specPath converts gin's "/assets/:id" and "/files/*path" to "/assets/{id}".
+func specPath(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			segments[i] = "{" + seg[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func pathParams(ginPath string) []string {
+	var names []string
+	for _, seg := range strings.Split(ginPath, "/") {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			names = append(names, seg[1:])
+		}
+	}
+	return names
+}
+
+// operationID is e.g. "get_assets_id" for GET /assets/:id.
+func operationID(method, ginPath string) string {
+	parts := []string{strings.ToLower(method)}
+	for _, seg := range strings.Split(ginPath, "/") {
+		seg = strings.TrimLeft(seg, ":*")
+		seg = strings.NewReplacer(".", "_", "-", "_").Replace(seg)
+		if seg != "" {
+			parts = append(parts, seg)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// tagFor groups undescribed routes by their first path segment.
+func tagFor(ginPath string) string {
+	first, _, _ := strings.Cut(strings.TrimPrefix(ginPath, "/"), "/")
+	return first
+}
+
+// Handler serves the document as JSON. The spec is encoded once.
+func Handler(doc *Document) gin.HandlerFunc {
+	body, err := json.Marshal(doc)
+	return func(c *gin.Context) {
+		if err != nil {
+			response.SendError(c, apperr.Wrap(apperr.Internal, "failed to encode OpenAPI spec", err))
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+}
@@ -0,0 +1,167 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type widget struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Tags      []string  `json:"tags"`
+	Parent    *widget   `json:"parent,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	internal  string
+}
+
+type createWidgetRequest struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Email string `json:"email" binding:"omitempty,email"`
+	Kind  string `json:"kind" binding:"oneof=a b"`
+	Count int    `json:"count" binding:"min=1"`
+	Skip  string `json:"-"`
+}
+
+type widgetHandler struct{}
+
+func (widgetHandler) Operations() []Operation {
+	return []Operation{
+		{
+			Method:   http.MethodPost,
+			Path:     "/widgets",
+			Tag:      "widgets",
+			Summary:  "Create a widget",
+			Request:  createWidgetRequest{},
+			Response: widget{},
+			Status:   http.StatusCreated,
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/widgets/:id",
+			Tag:      "widgets",
+			Response: widget{},
+			Auth:     true,
+		},
+		{
+			// Not registered, so it must not appear
+			Method: http.MethodDelete,
+			Path:   "/widgets/:id",
+		},
+	}
+}
+
+func buildTestSpec(t *testing.T) map[string]any {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	noop := func(c *gin.Context) {}
+	r.POST("/widgets", noop)
+	r.GET("/widgets/:id", noop)
+	r.GET("/health/ready", noop)
+
+	doc := Build(Info{Title: "Test", Version: "1"}, nil, r.Routes(), widgetHandler{})
+	r.GET("/openapi.json", Handler(doc))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	return spec
+}
+
+func at(t *testing.T, v any, keys ...string) any {
+	t.Helper()
+	for _, k := range keys {
+		m, ok := v.(map[string]any)
+		require.True(t, ok, "expected object at %q", k)
+		v, ok = m[k]
+		require.True(t, ok, "missing key %q", k)
+	}
+	return v
+}
+
+func TestBuild_ListsEveryRegisteredRoute(t *testing.T) {
+	spec := buildTestSpec(t)
+	require.Equal(t, "3.0.3", spec["openapi"])
+
+	paths := at(t, spec, "paths").(map[string]any)
+	require.Len(t, paths, 3)
+	require.Contains(t, paths, "/widgets")
+	require.Contains(t, paths, "/health/ready")
+
+	item := paths["/widgets/{id}"].(map[string]any)
+	require.Contains(t, item, "get")
+	require.NotContains(t, item, "delete")
+
+	// Undescribed routes are still documented, grouped by their first segment
+	require.Equal(t, []any{"health"}, at(t, paths, "/health/ready", "get", "tags"))
+}
+
+func TestBuild_DerivesSchemasFromStructs(t *testing.T) {
+	spec := buildTestSpec(t)
+
+	req := at(t, spec, "components", "schemas", "openapi.createWidgetRequest")
+	require.Equal(t, []any{"name"}, at(t, req, "required"))
+	require.EqualValues(t, 50, at(t, req, "properties", "name", "maxLength"))
+	require.Equal(t, "email", at(t, req, "properties", "email", "format"))
+	require.Equal(t, []any{"a", "b"}, at(t, req, "properties", "kind", "enum"))
+	require.EqualValues(t, 1, at(t, req, "properties", "count", "minimum"))
+	require.NotContains(t, at(t, req, "properties"), "Skip")
+
+	w := at(t, spec, "components", "schemas", "openapi.widget")
+	props := at(t, w, "properties").(map[string]any)
+	require.Equal(t, "date-time", at(t, props, "created_at", "format"))
+	require.Equal(t, "int64", at(t, props, "id", "format"))
+	require.Equal(t, "#/components/schemas/openapi.widget", at(t, props, "parent", "$ref"))
+	require.NotContains(t, props, "internal")
+
+	body := at(t, spec, "paths", "/widgets", "post", "requestBody", "content", "application/json", "schema", "$ref")
+	require.Equal(t, "#/components/schemas/openapi.createWidgetRequest", body)
+}
+
+func TestBuild_EnvelopesAndAuth(t *testing.T) {
+	spec := buildTestSpec(t)
+	post := at(t, spec, "paths", "/widgets", "post")
+
+	created := at(t, post, "responses", "201", "content", "application/json", "schema", "allOf").([]any)
+	require.Equal(t, "#/components/schemas/response.APIResponse", at(t, created[0], "$ref"))
+	require.Equal(t, "#/components/schemas/openapi.widget", at(t, created[1], "properties", "data", "$ref"))
+
+	require.Equal(t, "#/components/schemas/ValidationErrorResponse", at(t, post, "responses", "400", "content", "application/json", "schema", "$ref"))
+	require.Equal(t, "#/components/schemas/ErrorResponse", at(t, post, "responses", "500", "content", "application/json", "schema", "$ref"))
+	require.Equal(t, "#/components/schemas/response.Problem", at(t, post, "responses", "500", "content", "application/problem+json", "schema", "$ref"))
+	at(t, spec, "components", "schemas", "response.ValidationErrors")
+	errorCode := at(t, spec, "components", "schemas", "ErrorResponse", "allOf").([]any)[1]
+	require.Contains(t, at(t, errorCode, "properties", "error_code", "enum"), "ASSET_NOT_FOUND")
+
+	get := at(t, spec, "paths", "/widgets/{id}", "get")
+	require.Equal(t, []any{map[string]any{BearerAuth: []any{}}}, at(t, get, "security"))
+	require.Contains(t, at(t, get, "responses"), "401")
+	require.Equal(t, "bearer", at(t, spec, "components", "securitySchemes", BearerAuth, "scheme"))
+
+	params := at(t, get, "parameters").([]any)
+	require.Len(t, params, 1)
+	require.Equal(t, "id", at(t, params[0], "name"))
+	require.Equal(t, "path", at(t, params[0], "in"))
+	require.Equal(t, "integer", at(t, params[0], "schema", "type"))
+}
+
+type page[T any] struct {
+	Items []T `json:"items"`
+}
+
+func TestComponentName_Generics(t *testing.T) {
+	require.Equal(t, "openapi.widget", componentName(reflect.TypeOf(widget{})))
+	require.Equal(t, "openapi.page_openapi.widget", componentName(reflect.TypeOf(page[widget]{})))
+	require.Equal(t, "openapi.page_time.Time", componentName(reflect.TypeOf(page[time.Time]{})))
+}
@@ -0,0 +1,190 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rawJSONType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemas derives JSON schemas from Go types the way encoding/json serialises them,
+// registering named structs as components. Gin binding tags contribute required
+// fields and length, range and format constraints.
+type schemas struct {
+	components map[string]*Schema
+}
+
+func newSchemas() *schemas {
+	return &schemas{components: map[string]*Schema{}}
+}
+
+// of returns the schema for v's type; nil v means "no body".
+func (s *schemas) of(v any) *Schema {
+	if v == nil {
+		return nil
+	}
+	return s.forType(reflect.TypeOf(v))
+}
+
+func (s *schemas) forType(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawJSONType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		inner := s.forType(t.Elem())
+		if inner.Ref != "" {
+			return inner
+		}
+		inner.Nullable = true
+		return inner
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.forType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.forType(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		name := componentName(t)
+		if _, ok := s.components[name]; !ok {
+			// Placeholder first so self-referencing types terminate
+			s.components[name] = &Schema{}
+			*s.components[name] = *s.structSchema(t)
+		}
+		return Ref(name)
+	}
+	return &Schema{}
+}
+
+func (s *schemas) structSchema(t reflect.Type) *Schema {
+	out := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	s.addFields(out, t)
+	return out
+}
+
+func (s *schemas) addFields(out *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				s.addFields(out, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := s.forType(f.Type)
+		if applyBinding(prop, f.Tag.Get("binding")) {
+			out.Required = append(out.Required, name)
+		}
+		out.Properties[name] = prop
+	}
+}
+
+// applyBinding adds the constraints of a gin binding tag to prop and reports whether
+// the field is required. Constraints are not applied to component references.
+func applyBinding(prop *Schema, tag string) bool {
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		key, arg, _ := strings.Cut(rule, "=")
+		if key == "required" {
+			required = true
+			continue
+		}
+		if prop.Ref != "" {
+			continue
+		}
+		switch key {
+		case "email":
+			prop.Format = "email"
+		case "uuid":
+			prop.Format = "uuid"
+		case "url":
+			prop.Format = "uri"
+		case "oneof":
+			prop.Enum = strings.Fields(arg)
+		case "min", "max", "gte", "lte":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				continue
+			}
+			lower := key == "min" || key == "gte"
+			switch prop.Type {
+			case "string":
+				length := int(n)
+				if lower {
+					prop.MinLength = &length
+				} else {
+					prop.MaxLength = &length
+				}
+			case "integer", "number":
+				if lower {
+					prop.Minimum = &n
+				} else {
+					prop.Maximum = &n
+				}
+			}
+		}
+	}
+	return required
+}
+
+// componentName is "<package>.<Type>", e.g. "assets.Asset". Instantiated generics
+// get their type arguments appended the same way, e.g. "response.Paginated_assets.Asset",
+// since reflect's "Paginated[grveyard/pkg/assets.Asset]" is not a valid component key.
+func componentName(t reflect.Type) string {
+	name, args, generic := strings.Cut(t.Name(), "[")
+	if generic {
+		for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+			name += "_" + path.Base(arg)
+		}
+	}
+	return path.Base(t.PkgPath()) + "." + name
+}
+
+// Ref points at a schema in components.
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
@@ -1,92 +1,107 @@
 package otp
 
 import (
+	"errors"
 	"net/http"
 
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
 	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
 
 	"github.com/gin-gonic/gin"
 )
 
 type OTPHandler struct {
 	service OTPService
+	events  auth.EventRecorder // optional
 }
 
 func NewOTPHandler(service OTPService) *OTPHandler {
 	return &OTPHandler{service: service}
 }
 
+// SetEvents records successful verifications in the security log.
+func (h *OTPHandler) SetEvents(events auth.EventRecorder) {
+	h.events = events
+}
+
 func (h *OTPHandler) RegisterRoutes(router *gin.Engine) {
 	router.POST("/getOTP", h.getOTP)
 	router.POST("/verifyOTP", h.verifyOTP)
 }
 
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *OTPHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/getOTP",
+			Tag:         "OTP",
+			Summary:     "Generate and send OTP",
+			Description: "Generate a one-time password and send it to the provided email",
+			Request:     getOTPRequest{},
+			Errors:      []int{http.StatusBadRequest, http.StatusTooManyRequests, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/verifyOTP",
+			Tag:         "OTP",
+			Summary:     "Verify OTP",
+			Description: "Verify the one-time password for the provided email",
+			Request:     verifyOTPRequest{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized},
+		},
+	}
+}
+
 type getOTPRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
 
 type verifyOTPRequest struct {
 	Email string `json:"email" binding:"required,email"`
-	Code  string `json:"code" binding:"required"`
+	Code  string `json:"code" binding:"required,max=16"`
 }
 
-// @Summary      Generate and send OTP
-// @Description  Generate a one-time password and send it to the provided email
-// @Tags         OTP
-// @Accept       json
-// @Produce      json
-// @Param        request body getOTPRequest true "Email to send OTP to"
-// @Success      200 {object} response.APIResponse
-// @Failure      400 {object} response.APIResponse
-// @Failure      429 {object} response.APIResponse
-// @Failure      500 {object} response.APIResponse
-// @Router       /getOTP [post]
 func (h *OTPHandler) getOTP(c *gin.Context) {
 	var req getOTPRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "Invalid request: "+err.Error(), nil)
+	if !validation.BindJSON(c, &req) {
 		return
 	}
 
 	if err := h.service.GenerateAndSendOTP(c.Request.Context(), req.Email); err != nil {
-		if err.Error() == "too many OTP requests. Please try again later" {
-			response.SendAPIResponse(c, http.StatusTooManyRequests, false, err.Error(), nil)
+		if errors.Is(err, ErrTooManyRequests) {
+			response.SendError(c, err)
 			return
 		}
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, "Failed to generate and send OTP: "+err.Error(), nil)
+		response.SendError(c, apperr.Wrap(apperr.Internal, "Failed to generate and send OTP: "+err.Error(), err))
 		return
 	}
 
-	response.SendAPIResponse(c, http.StatusOK, true, "OTP sent successfully to "+req.Email, nil)
+	response.SendAPIResponse(c, http.StatusOK, true, "OTP sent successfully", gin.H{"email": req.Email})
 }
 
-// @Summary      Verify OTP
-// @Description  Verify the one-time password for the provided email
-// @Tags         OTP
-// @Accept       json
-// @Produce      json
-// @Param        request body verifyOTPRequest true "Email and OTP code to verify"
-// @Success      200 {object} response.APIResponse
-// @Failure      400 {object} response.APIResponse
-// @Failure      401 {object} response.APIResponse
-// @Router       /verifyOTP [post]
 func (h *OTPHandler) verifyOTP(c *gin.Context) {
 	var req verifyOTPRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "Invalid request: "+err.Error(), nil)
+	if !validation.BindJSON(c, &req) {
 		return
 	}
 
 	valid, err := h.service.VerifyOTP(c.Request.Context(), req.Email, req.Code)
 	if err != nil {
-		response.SendAPIResponse(c, http.StatusUnauthorized, false, "OTP verification failed: "+err.Error(), nil)
+		response.SendError(c, apperr.Default(err, apperr.Internal))
 		return
 	}
 
 	if !valid {
-		response.SendAPIResponse(c, http.StatusUnauthorized, false, "Invalid OTP", nil)
+		response.SendError(c, ErrInvalidCode)
 		return
 	}
+	if h.events != nil {
+		h.events.RecordByEmail(c, req.Email, auth.EventOTPVerified)
+	}
 
 	response.SendAPIResponse(c, http.StatusOK, true, "OTP verified successfully", gin.H{"verified": true})
 }
@@ -2,8 +2,9 @@ package otp
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/i18n"
 	sendemail "grveyard/pkg/sendemail"
 	"grveyard/pkg/users"
 	"math/rand"
@@ -21,6 +22,13 @@ type otpService struct {
 	es       sendemail.EmailService
 }
 
+var (
+	ErrTooManyRequests = apperr.New(apperr.OTPRateLimited, "too many OTP requests. Please try again later")
+	ErrOTPNotFound     = apperr.New(apperr.OTPNotFound, "no OTP found for this email or OTP already verified")
+	ErrOTPExpired      = apperr.New(apperr.OTPExpired, "OTP has expired")
+	ErrInvalidCode     = apperr.New(apperr.OTPInvalid, "invalid OTP code")
+)
+
 func NewOTPService(repo OTPRepository, userRepo users.UserRepository, es sendemail.EmailService) OTPService {
 	return &otpService{repo: repo, userRepo: userRepo, es: es}
 }
@@ -32,7 +40,7 @@ func (s *otpService) GenerateAndSendOTP(ctx context.Context, email string) error
 	}
 
 	if count >= 3 {
-		return errors.New("too many OTP requests. Please try again later")
+		return ErrTooManyRequests
 	}
 
 	code := generateOTP(6)
@@ -44,7 +52,13 @@ func (s *otpService) GenerateAndSendOTP(ctx context.Context, email string) error
 		return fmt.Errorf("failed to create OTP: %w", err)
 	}
 
-	if err := s.sendOTPEmail(email, code); err != nil {
+	// Unknown emails (e.g. during sign-up) get the default locale
+	locale := i18n.DefaultLocale
+	if u, err := s.userRepo.GetUserByEmail(ctx, email); err == nil {
+		locale = u.Locale
+	}
+
+	if err := s.sendOTPEmail(ctx, email, code, locale); err != nil {
 		return fmt.Errorf("failed to send OTP email: %w", err)
 	}
 
@@ -56,15 +70,15 @@ func (s *otpService) GenerateAndSendOTP(ctx context.Context, email string) error
 func (s *otpService) VerifyOTP(ctx context.Context, email, code string) (bool, error) {
 	otp, err := s.repo.GetOTPByEmail(ctx, email)
 	if err != nil {
-		return false, errors.New("no OTP found for this email or OTP already verified")
+		return false, ErrOTPNotFound
 	}
 
 	if time.Now().After(otp.ExpiresAt) {
-		return false, errors.New("OTP has expired")
+		return false, ErrOTPExpired
 	}
 
 	if otp.Code != code {
-		return false, errors.New("invalid OTP code")
+		return false, ErrInvalidCode
 	}
 
 	if err := s.repo.MarkOTPAsVerified(ctx, otp.ID); err != nil {
@@ -88,21 +102,22 @@ func generateOTP(length int) string {
 	return string(otp)
 }
 
-func (s *otpService) sendOTPEmail(toEmail, code string) error {
-	subject := "Your OTP Code"
-	plainTextContent := fmt.Sprintf("Your OTP code is: %s. This code will expire in 10 minutes.", code)
-	htmlContent := fmt.Sprintf(`
-		<div style="font-family: Arial, sans-serif; padding: 20px;">
-			<h2>Your OTP Code</h2>
-			<p>Your one-time password is:</p>
-			<div style="font-size: 24px; font-weight: bold; color: #333; padding: 10px; background-color: #f5f5f5; border-radius: 5px; display: inline-block;">
-				%s
-			</div>
-			<p>This code will expire in 10 minutes.</p>
-			<p>If you didn't request this code, please ignore this email.</p>
-		</div>
-	`, code)
-
-	err := s.es.SendEmail(subject, toEmail, plainTextContent, htmlContent)
-	return err
+func (s *otpService) sendOTPEmail(ctx context.Context, toEmail, code, locale string) error {
+	const expiryMinutes = 10
+	subject := i18n.T(locale, "email.otp.subject")
+	plainTextContent := i18n.T(locale, "email.otp.text", code, expiryMinutes)
+	htmlContent, err := sendemail.RenderHTML("otp.html", struct {
+		Heading, Intro, Code, Expiry, Ignore string
+	}{
+		Heading: i18n.T(locale, "email.otp.heading"),
+		Intro:   i18n.T(locale, "email.otp.intro"),
+		Code:    code,
+		Expiry:  i18n.T(locale, "email.otp.expiry", expiryMinutes),
+		Ignore:  i18n.T(locale, "email.otp.ignore"),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.es.SendEmail(ctx, subject, toEmail, plainTextContent, htmlContent)
 }
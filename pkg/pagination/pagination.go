@@ -0,0 +1,131 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+)
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+var ErrInvalidCursor = apperr.New(apperr.InvalidCursor, "invalid cursor")
+
+// Params is a parsed page request. Clients may send page/limit or the opaque cursor
+// returned in a previous response's links; a cursor wins when both are present.
+type Params struct {
+	Page  int
+	Limit int
+}
+
+// Offset is the number of rows to skip for this page.
+func (p Params) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// Links is attached to list responses so clients can follow pages without doing math.
+type Links struct {
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+type pageCursor struct {
+	Page  int `json:"p"`
+	Limit int `json:"l"`
+}
+
+// FromRequest reads cursor, page and limit query parameters, clamping limit to
+// [1, MaxLimit] and defaulting to defaultLimit. Bad page/limit values fall back to
+// defaults; only a malformed cursor is an error.
+func FromRequest(c *gin.Context, defaultLimit int) (Params, error) {
+	if raw := c.Query("cursor"); raw != "" {
+		var pc pageCursor
+		if err := DecodeCursor(raw, &pc); err != nil || pc.Page < 1 || pc.Limit < 1 {
+			return Params{}, ErrInvalidCursor
+		}
+		return Params{Page: pc.Page, Limit: clampLimit(pc.Limit, defaultLimit)}, nil
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		limit = defaultLimit
+	}
+	return Params{Page: page, Limit: clampLimit(limit, defaultLimit)}, nil
+}
+
+func clampLimit(limit, defaultLimit int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
+
+// PageLinks builds next/prev links for an offset-paginated list of total rows.
+func PageLinks(c *gin.Context, p Params, total int64) *Links {
+	links := &Links{}
+	if int64(p.Page*p.Limit) < total {
+		links.NextCursor = EncodeCursor(pageCursor{Page: p.Page + 1, Limit: p.Limit})
+		links.Next = withCursor(c, links.NextCursor)
+	}
+	if p.Page > 1 {
+		links.PrevCursor = EncodeCursor(pageCursor{Page: p.Page - 1, Limit: p.Limit})
+		links.Prev = withCursor(c, links.PrevCursor)
+	}
+	return links
+}
+
+// NextLinks builds a next-only link for keyset-paginated lists (e.g. chat history),
+// where next is any cursor value understood by the endpoint. nil means last page.
+func NextLinks(c *gin.Context, next any) *Links {
+	if next == nil {
+		return &Links{}
+	}
+	cursor := EncodeCursor(next)
+	return &Links{NextCursor: cursor, Next: withCursor(c, cursor)}
+}
+
+// EncodeCursor serialises v as an opaque URL-safe token.
+func EncodeCursor(v any) string {
+	b, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor reverses EncodeCursor.
+func DecodeCursor(cursor string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+// withCursor returns the request's path and query with page/cursor replaced.
+func withCursor(c *gin.Context, cursor string) string {
+	q := url.Values{}
+	for k, v := range c.Request.URL.Query() {
+		q[k] = v
+	}
+	q.Del("page")
+	q.Del("limit")
+	q.Set("cursor", cursor)
+	return c.Request.URL.Path + "?" + q.Encode()
+}
@@ -0,0 +1,69 @@
+package pagination
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newContext(target string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c
+}
+
+func TestFromRequest_PageAndLimit(t *testing.T) {
+	tests := []struct {
+		query string
+		want  Params
+	}{
+		{"", Params{Page: 1, Limit: 10}},
+		{"?page=3&limit=20", Params{Page: 3, Limit: 20}},
+		{"?page=-1&limit=abc", Params{Page: 1, Limit: 10}},
+		{"?limit=1000", Params{Page: 1, Limit: MaxLimit}},
+	}
+	for _, tt := range tests {
+		p, err := FromRequest(newContext("/items"+tt.query), DefaultLimit)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, p, tt.query)
+	}
+}
+
+func TestPageLinks_RoundTrip(t *testing.T) {
+	c := newContext("/assets?asset_type=domain&page=2&limit=5")
+	p, err := FromRequest(c, DefaultLimit)
+	require.NoError(t, err)
+
+	links := PageLinks(c, p, 30)
+	require.NotEmpty(t, links.Next)
+	require.NotEmpty(t, links.Prev)
+
+	next, err := url.Parse(links.Next)
+	require.NoError(t, err)
+	require.Equal(t, "/assets", next.Path)
+	require.Equal(t, "domain", next.Query().Get("asset_type"))
+
+	p, err = FromRequest(newContext(links.Next), DefaultLimit)
+	require.NoError(t, err)
+	require.Equal(t, Params{Page: 3, Limit: 5}, p)
+	require.Equal(t, 10, p.Offset())
+}
+
+func TestPageLinks_LastPage(t *testing.T) {
+	c := newContext("/users?page=1&limit=10")
+	p, _ := FromRequest(c, DefaultLimit)
+
+	links := PageLinks(c, p, 10)
+	require.Empty(t, links.Next)
+	require.Empty(t, links.Prev)
+}
+
+func TestFromRequest_InvalidCursor(t *testing.T) {
+	_, err := FromRequest(newContext("/users?cursor=!!!"), DefaultLimit)
+	require.ErrorIs(t, err, ErrInvalidCursor)
+}
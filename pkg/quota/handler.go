@@ -0,0 +1,51 @@
+package quota
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+type QuotaHandler struct {
+	service *Service
+}
+
+func NewQuotaHandler(service *Service) *QuotaHandler {
+	return &QuotaHandler{service: service}
+}
+
+func (h *QuotaHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/users/:uuid/usage", auth.RequireSelf("uuid"), h.usage)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *QuotaHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/usage",
+			Tag:         "users",
+			Summary:     "API quota usage",
+			Description: "Requests counted this UTC day and month against the user's own quota, which follows from their role and verification, and against each of their API keys. limit and remaining are null for unlimited tiers. Every response also carries X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset for the caller's tightest window; requests over quota get 429.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Response: Report{},
+			Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+func (h *QuotaHandler) usage(c *gin.Context) {
+	report, err := h.service.Usage(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "usage fetched", report)
+}
@@ -0,0 +1,80 @@
+package quota
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/requestid"
+	"grveyard/pkg/response"
+)
+
+const (
+	// KeyHeader carries an integrator's API key.
+	KeyHeader = "X-API-Key"
+
+	LimitHeader     = "X-RateLimit-Limit"
+	RemainingHeader = "X-RateLimit-Remaining"
+	ResetHeader     = "X-RateLimit-Reset" // Unix seconds when the window resets
+)
+
+// Config controls the quota middleware.
+type Config struct {
+	// ExcludedPaths are path prefixes that are not counted, e.g. health checks and
+	// websocket upgrades.
+	ExcludedPaths []string
+}
+
+// Middleware counts every request against its subject's quota, sets the
+// X-RateLimit-* headers for the window closest to its limit and refuses requests
+// over either limit with 429. The authenticated user is read from the "user_id"
+// context value. If the counters cannot be updated the request is let through.
+func Middleware(service *Service, cfg Config) gin.HandlerFunc {
+	logger := log.New(log.Writer(), "[quota] ", log.LstdFlags)
+
+	return func(c *gin.Context) {
+		for _, prefix := range cfg.ExcludedPaths {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
+		ctx := c.Request.Context()
+		subject, err := service.Resolve(ctx, c.GetHeader(KeyHeader), c.GetString("user_id"), c.ClientIP())
+		if err != nil {
+			if apperr.CodeOf(err) == apperr.InvalidAPIKey {
+				response.SendError(c, err)
+				c.Abort()
+				return
+			}
+			logger.Printf("[%s] resolve quota subject: %v", requestid.FromContext(ctx), err)
+			c.Next()
+			return
+		}
+
+		w, allowed, err := service.Consume(ctx, subject)
+		if err != nil {
+			// Fail open: a broken counter should not take the API down with it
+			logger.Printf("[%s] count %s: %v", requestid.FromContext(ctx), subject.ID, err)
+			c.Next()
+			return
+		}
+		if w.Limit != nil {
+			c.Header(LimitHeader, strconv.FormatInt(*w.Limit, 10))
+			c.Header(RemainingHeader, strconv.FormatInt(*w.Remaining, 10))
+			c.Header(ResetHeader, strconv.FormatInt(w.ResetsAt.Unix(), 10))
+		}
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(w.ResetsAt).Seconds())+1))
+			response.SendError(c, apperr.New(apperr.QuotaExceeded, "request quota exceeded").WithDetails(gin.H{"tier": subject.Tier}))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
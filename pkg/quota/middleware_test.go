@@ -0,0 +1,90 @@
+package quota
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRouter(repo *fakeRepo, plans Plans) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if uid := c.GetHeader("X-Test-User"); uid != "" {
+			c.Set("user_id", uid)
+		}
+	}, Middleware(NewService(repo, plans), Config{ExcludedPaths: []string{"/ws/"}}))
+	r.GET("/assets", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/ws/chat", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func get(r *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_EnforcesQuota(t *testing.T) {
+	repo := newFakeRepo()
+	r := setupRouter(repo, Plans{TierAnonymous: {Daily: 2, Monthly: 100}})
+
+	w := get(r, "/assets", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "2", w.Header().Get(LimitHeader))
+	require.Equal(t, "1", w.Header().Get(RemainingHeader))
+	require.NotEmpty(t, w.Header().Get(ResetHeader))
+
+	get(r, "/assets", nil)
+	w = get(r, "/assets", nil)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "0", w.Header().Get(RemainingHeader))
+	require.NotEmpty(t, w.Header().Get("Retry-After"))
+	require.Contains(t, w.Body.String(), "QUOTA_EXCEEDED")
+
+	// Excluded paths are neither counted nor refused
+	w = get(r, "/ws/chat", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Header().Get(LimitHeader))
+}
+
+func TestMiddleware_TiersByUserAndKey(t *testing.T) {
+	repo := newFakeRepo()
+	repo.tiers["admin-1"] = TierAdmin
+	repo.tiers["buyer-1"] = TierBuyer
+	repo.keys[hashKey("gv_partner")] = APIKey{ID: 3, UserUUID: "buyer-1", Tier: TierPartner}
+	r := setupRouter(repo, Plans{TierBuyer: {Daily: 10}, TierPartner: {Daily: 1000}})
+
+	w := get(r, "/assets", map[string]string{"X-Test-User": "buyer-1"})
+	require.Equal(t, "10", w.Header().Get(LimitHeader))
+
+	w = get(r, "/assets", map[string]string{"X-Test-User": "buyer-1", KeyHeader: "gv_partner"})
+	require.Equal(t, "1000", w.Header().Get(LimitHeader))
+
+	w = get(r, "/assets", map[string]string{"X-Test-User": "admin-1"})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Header().Get(LimitHeader))
+
+	w = get(r, "/assets", map[string]string{KeyHeader: "gv_revoked"})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), "INVALID_API_KEY")
+}
+
+func TestMiddleware_FailsOpen(t *testing.T) {
+	repo := newFakeRepo()
+	repo.incrementErr = errors.New("connection refused")
+	r := setupRouter(repo, DefaultPlans())
+
+	w := get(r, "/assets", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Header().Get(LimitHeader))
+}
@@ -0,0 +1,47 @@
+package quota
+
+import "time"
+
+// Subject is who a request counts against: "ip:<addr>", "user:<uuid>" or
+// "key:<id>".
+type Subject struct {
+	ID   string
+	Tier Tier
+}
+
+// Window is the usage of one quota period. Limit and Remaining are nil when the tier
+// is unlimited.
+type Window struct {
+	Limit     *int64    `json:"limit"`
+	Used      int64     `json:"used"`
+	Remaining *int64    `json:"remaining"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
+// APIKey identifies an integrator. Only a hash of the key is stored; Prefix is its
+// first characters so owners can tell keys apart.
+type APIKey struct {
+	ID        int64      `json:"id"`
+	UserUUID  string     `json:"user_uuid"`
+	Prefix    string     `json:"prefix"`
+	Tier      Tier       `json:"tier"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// KeyUsage is an API key with its usage this day and month.
+type KeyUsage struct {
+	APIKey
+	Daily   Window `json:"daily"`
+	Monthly Window `json:"monthly"`
+}
+
+// Report is a user's quota usage: requests made as themselves, and through each of
+// their API keys, which have quotas of their own.
+type Report struct {
+	UserUUID string     `json:"user_uuid"`
+	Tier     Tier       `json:"tier"`
+	Daily    Window     `json:"daily"`
+	Monthly  Window     `json:"monthly"`
+	Keys     []KeyUsage `json:"keys"`
+}
@@ -0,0 +1,93 @@
+// Package quota enforces daily and monthly request quotas per tier. Requests are
+// attributed to an API key when one is sent, else to the authenticated user, else to
+// the client IP; the tier follows from the key, or the user's role and verification.
+// Counts are kept in Postgres per UTC day and month so every instance shares them.
+package quota
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tier is a quota plan.
+type Tier string
+
+const (
+	TierAnonymous       Tier = "anonymous"
+	TierBuyer           Tier = "buyer"
+	TierFounder         Tier = "founder"
+	TierVerifiedFounder Tier = "verified_founder"
+	TierPartner         Tier = "partner"
+	TierAdmin           Tier = "admin"
+)
+
+// Limits are the requests allowed per UTC day and month; zero means unlimited.
+type Limits struct {
+	Daily   int64
+	Monthly int64
+}
+
+// Plans maps tiers to their limits. Tiers missing from the map are unlimited.
+type Plans map[Tier]Limits
+
+// DefaultPlans are used for tiers API_QUOTAS does not mention. Admins are unlimited.
+func DefaultPlans() Plans {
+	return Plans{
+		TierAnonymous:       {Daily: 1_000, Monthly: 20_000},
+		TierBuyer:           {Daily: 5_000, Monthly: 100_000},
+		TierFounder:         {Daily: 10_000, Monthly: 200_000},
+		TierVerifiedFounder: {Daily: 50_000, Monthly: 1_000_000},
+		TierPartner:         {Daily: 200_000, Monthly: 5_000_000},
+	}
+}
+
+// ParsePlans reads a comma separated list of TIER:DAILY/MONTHLY entries, e.g.
+// "buyer:2000/40000,partner:0/0", over DefaultPlans. A limit of 0 is unlimited.
+func ParsePlans(spec string) (Plans, error) {
+	plans := DefaultPlans()
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		tier, limits, ok := strings.Cut(entry, ":")
+		daily, monthly, ok2 := strings.Cut(limits, "/")
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("quota %q: expected TIER:DAILY/MONTHLY", entry)
+		}
+		t := Tier(strings.ToLower(strings.TrimSpace(tier)))
+		if !t.Valid() {
+			return nil, fmt.Errorf("quota %q: unknown tier", entry)
+		}
+		d, err := strconv.ParseInt(strings.TrimSpace(daily), 10, 64)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("quota %q: daily limit must be a non-negative integer", entry)
+		}
+		m, err := strconv.ParseInt(strings.TrimSpace(monthly), 10, 64)
+		if err != nil || m < 0 {
+			return nil, fmt.Errorf("quota %q: monthly limit must be a non-negative integer", entry)
+		}
+		plans[t] = Limits{Daily: d, Monthly: m}
+	}
+	return plans, nil
+}
+
+// Valid reports whether t is a known tier.
+func (t Tier) Valid() bool {
+	switch t {
+	case TierAnonymous, TierBuyer, TierFounder, TierVerifiedFounder, TierPartner, TierAdmin:
+		return true
+	}
+	return false
+}
+
+// windows returns the start of the UTC day and month containing now, and when each
+// resets.
+func windows(now time.Time) (day, month, dayReset, monthReset time.Time) {
+	now = now.UTC()
+	day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return day, month, day.AddDate(0, 0, 1), month.AddDate(0, 1, 0)
+}
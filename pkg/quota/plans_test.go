@@ -0,0 +1,42 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlans(t *testing.T) {
+	plans, err := ParsePlans(" Buyer:2000/40000, partner:0/0 ,")
+
+	require.NoError(t, err)
+	require.Equal(t, Limits{Daily: 2000, Monthly: 40000}, plans[TierBuyer])
+	require.Equal(t, Limits{}, plans[TierPartner])
+	require.Equal(t, DefaultPlans()[TierFounder], plans[TierFounder])
+	require.NotContains(t, plans, TierAdmin)
+}
+
+func TestParsePlans_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"buyer:2000",
+		"buyer=2000/40000",
+		"gold:1/1",
+		"buyer:-1/10",
+		"buyer:10/abc",
+	} {
+		_, err := ParsePlans(spec)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestWindows(t *testing.T) {
+	now := time.Date(2026, time.December, 31, 22, 30, 0, 0, time.FixedZone("IST", 5*3600+1800))
+
+	day, month, dayReset, monthReset := windows(now)
+
+	require.Equal(t, time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC), day)
+	require.Equal(t, time.Date(2026, time.December, 1, 0, 0, 0, 0, time.UTC), month)
+	require.Equal(t, time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), dayReset)
+	require.Equal(t, time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), monthReset)
+}
@@ -0,0 +1,133 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UsageRepository interface {
+	// KeyByHash returns the unrevoked key with that hash.
+	KeyByHash(ctx context.Context, hash string) (APIKey, error)
+	// UserTier derives a user's tier from their role and verification.
+	UserTier(ctx context.Context, userUUID string) (Tier, error)
+	// Increment counts a request against subject in the day and month windows and
+	// returns the new totals.
+	Increment(ctx context.Context, subject string, day, month time.Time) (int64, int64, error)
+	// Usage returns the totals without counting a request.
+	Usage(ctx context.Context, subject string, day, month time.Time) (int64, int64, error)
+	CreateKey(ctx context.Context, key APIKey, hash string) (APIKey, error)
+	ListKeys(ctx context.Context, userUUID string) ([]APIKey, error)
+	// DeleteBefore drops counters of windows that started before cutoff.
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type postgresUsageRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresUsageRepository(pool *pgxpool.Pool) UsageRepository {
+	return &postgresUsageRepository{pool: pool}
+}
+
+func (r *postgresUsageRepository) KeyByHash(ctx context.Context, hash string) (APIKey, error) {
+	var k APIKey
+	err := r.pool.QueryRow(ctx, `
+		SELECT k.id, k.user_uuid, k.prefix, k.tier, k.created_at
+		FROM api_keys k
+		JOIN users u ON u.uuid = k.user_uuid
+		WHERE k.key_hash = $1 AND k.revoked_at IS NULL AND u.is_deleted = false`, hash).
+		Scan(&k.ID, &k.UserUUID, &k.Prefix, &k.Tier, &k.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return APIKey{}, ErrInvalidKey
+	}
+	return k, err
+}
+
+func (r *postgresUsageRepository) UserTier(ctx context.Context, userUUID string) (Tier, error) {
+	var role string
+	var verified bool
+	err := r.pool.QueryRow(ctx, `SELECT role, verified_at IS NOT NULL FROM users WHERE uuid = $1 AND is_deleted = false`, userUUID).
+		Scan(&role, &verified)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrUserNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case role == "admin":
+		return TierAdmin, nil
+	case role == "founder" && verified:
+		return TierVerifiedFounder, nil
+	case role == "founder":
+		return TierFounder, nil
+	}
+	return TierBuyer, nil
+}
+
+func (r *postgresUsageRepository) Increment(ctx context.Context, subject string, day, month time.Time) (int64, int64, error) {
+	var daily, monthly int64
+	err := r.pool.QueryRow(ctx, `
+		WITH counted AS (
+			INSERT INTO api_usage (subject, period, period_start, requests)
+			VALUES ($1, 'day', $2, 1), ($1, 'month', $3, 1)
+			ON CONFLICT (subject, period, period_start) DO UPDATE SET requests = api_usage.requests + 1
+			RETURNING period, requests
+		)
+		SELECT
+			COALESCE(MAX(requests) FILTER (WHERE period = 'day'), 0),
+			COALESCE(MAX(requests) FILTER (WHERE period = 'month'), 0)
+		FROM counted`, subject, day, month).Scan(&daily, &monthly)
+	return daily, monthly, err
+}
+
+func (r *postgresUsageRepository) Usage(ctx context.Context, subject string, day, month time.Time) (int64, int64, error) {
+	var daily, monthly int64
+	err := r.pool.QueryRow(ctx, `
+		SELECT
+			COALESCE(MAX(requests) FILTER (WHERE period = 'day' AND period_start = $2), 0),
+			COALESCE(MAX(requests) FILTER (WHERE period = 'month' AND period_start = $3), 0)
+		FROM api_usage
+		WHERE subject = $1`, subject, day, month).Scan(&daily, &monthly)
+	return daily, monthly, err
+}
+
+func (r *postgresUsageRepository) CreateKey(ctx context.Context, key APIKey, hash string) (APIKey, error) {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO api_keys (user_uuid, key_hash, prefix, tier)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`, key.UserUUID, hash, key.Prefix, key.Tier).
+		Scan(&key.ID, &key.CreatedAt)
+	return key, err
+}
+
+func (r *postgresUsageRepository) ListKeys(ctx context.Context, userUUID string) ([]APIKey, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, user_uuid, prefix, tier, created_at, revoked_at
+		FROM api_keys
+		WHERE user_uuid = $1
+		ORDER BY id`, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []APIKey{}
+	for rows.Next() {
+		var k APIKey
+		if err := rows.Scan(&k.ID, &k.UserUUID, &k.Prefix, &k.Tier, &k.CreatedAt, &k.RevokedAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (r *postgresUsageRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM api_usage WHERE period_start < $1`, cutoff)
+	return tag.RowsAffected(), err
+}
@@ -0,0 +1,66 @@
+package quota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresUsageRepository(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+	repo := NewPostgresUsageRepository(pool)
+	ctx := context.Background()
+
+	founder := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"), testhelpers.WithVerified())
+	buyer := testhelpers.NewUser(t, pool)
+
+	tier, err := repo.UserTier(ctx, founder.UUID)
+	require.NoError(t, err)
+	require.Equal(t, TierVerifiedFounder, tier)
+	tier, err = repo.UserTier(ctx, buyer.UUID)
+	require.NoError(t, err)
+	require.Equal(t, TierBuyer, tier)
+	_, err = repo.UserTier(ctx, "missing")
+	require.ErrorIs(t, err, ErrUserNotFound)
+
+	key, err := repo.CreateKey(ctx, APIKey{UserUUID: founder.UUID, Prefix: "gv_abcdef", Tier: TierPartner}, "hash-1")
+	require.NoError(t, err)
+	found, err := repo.KeyByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	require.Equal(t, key.ID, found.ID)
+	require.Equal(t, TierPartner, found.Tier)
+	_, err = pool.Exec(ctx, `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1`, key.ID)
+	require.NoError(t, err)
+	_, err = repo.KeyByHash(ctx, "hash-1")
+	require.ErrorIs(t, err, ErrInvalidKey)
+	keys, err := repo.ListKeys(ctx, founder.UUID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.NotNil(t, keys[0].RevokedAt)
+
+	day, month, _, _ := windows(time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC))
+	for range 2 {
+		_, _, err = repo.Increment(ctx, "user:"+buyer.UUID, day, month)
+		require.NoError(t, err)
+	}
+	daily, monthly, err := repo.Increment(ctx, "user:"+buyer.UUID, day.AddDate(0, 0, -1), month)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), daily)
+	require.Equal(t, int64(3), monthly)
+
+	daily, monthly, err = repo.Usage(ctx, "user:"+buyer.UUID, day, month)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), daily)
+	require.Equal(t, int64(3), monthly)
+
+	deleted, err := repo.DeleteBefore(ctx, day)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted, "the 15th's and the month's counters")
+}
@@ -0,0 +1,199 @@
+package quota
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	"grveyard/pkg/apperr"
+)
+
+const (
+	// keyPrefix marks the API keys issued here, so leaked keys are easy to grep for.
+	keyPrefix = "gv_"
+	// resolveTTL is how long a key's or user's tier is cached; a role change or
+	// revoked key takes effect within it.
+	resolveTTL     = time.Minute
+	resolveEntries = 10_000
+)
+
+var (
+	ErrInvalidKey   = apperr.New(apperr.InvalidAPIKey, "invalid or revoked API key")
+	ErrUserNotFound = apperr.New(apperr.UserNotFound, "user not found")
+	ErrInvalidTier  = apperr.New(apperr.InvalidRequest, "unknown quota tier")
+)
+
+type resolved struct {
+	subject Subject
+	expires time.Time
+}
+
+type Service struct {
+	repo  UsageRepository
+	plans Plans
+	now   func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]resolved // by key hash or user UUID
+}
+
+func NewService(repo UsageRepository, plans Plans) *Service {
+	return &Service{repo: repo, plans: plans, now: time.Now, cache: map[string]resolved{}}
+}
+
+// Resolve returns who a request counts against: the API key when one is sent, else
+// the authenticated user, else the client IP. An unknown or revoked key is an error
+// rather than a fallback, so integrators notice.
+func (s *Service) Resolve(ctx context.Context, apiKey, userUUID, clientIP string) (Subject, error) {
+	switch {
+	case apiKey != "":
+		hash := hashKey(apiKey)
+		return s.cached(hash, func() (Subject, error) {
+			k, err := s.repo.KeyByHash(ctx, hash)
+			if err != nil {
+				return Subject{}, err
+			}
+			return Subject{ID: keySubject(k.ID), Tier: k.Tier}, nil
+		})
+	case userUUID != "":
+		return s.cached(userUUID, func() (Subject, error) {
+			tier, err := s.repo.UserTier(ctx, userUUID)
+			if err != nil {
+				return Subject{}, err
+			}
+			return Subject{ID: userSubject(userUUID), Tier: tier}, nil
+		})
+	}
+	return Subject{ID: "ip:" + clientIP, Tier: TierAnonymous}, nil
+}
+
+// Consume counts one request against subject and reports whether it is within both
+// windows' limits. The returned window is the one with the least headroom, for the
+// X-RateLimit headers, and is zero when the tier is unlimited.
+func (s *Service) Consume(ctx context.Context, subject Subject) (Window, bool, error) {
+	limits, ok := s.plans[subject.Tier]
+	if !ok || (limits.Daily == 0 && limits.Monthly == 0) {
+		return Window{}, true, nil
+	}
+	day, month, dayReset, monthReset := windows(s.now())
+	daily, monthly, err := s.repo.Increment(ctx, subject.ID, day, month)
+	if err != nil {
+		return Window{}, true, err
+	}
+
+	w := window(limits.Daily, daily, dayReset)
+	if m := window(limits.Monthly, monthly, monthReset); w.Remaining == nil || (m.Remaining != nil && *m.Remaining < *w.Remaining) {
+		w = m
+	}
+	return w, (limits.Daily == 0 || daily <= limits.Daily) && (limits.Monthly == 0 || monthly <= limits.Monthly), nil
+}
+
+// Usage reports a user's quota usage, as themselves and per API key.
+func (s *Service) Usage(ctx context.Context, userUUID string) (Report, error) {
+	tier, err := s.repo.UserTier(ctx, userUUID)
+	if err != nil {
+		return Report{}, err
+	}
+	report := Report{UserUUID: userUUID, Tier: tier}
+	if report.Daily, report.Monthly, err = s.usage(ctx, userSubject(userUUID), tier); err != nil {
+		return Report{}, err
+	}
+
+	keys, err := s.repo.ListKeys(ctx, userUUID)
+	if err != nil {
+		return Report{}, err
+	}
+	report.Keys = make([]KeyUsage, 0, len(keys))
+	for _, k := range keys {
+		ku := KeyUsage{APIKey: k}
+		if ku.Daily, ku.Monthly, err = s.usage(ctx, keySubject(k.ID), k.Tier); err != nil {
+			return Report{}, err
+		}
+		report.Keys = append(report.Keys, ku)
+	}
+	return report, nil
+}
+
+// CreateKey issues an API key for userUUID on tier. The key itself is returned only
+// here; it cannot be recovered later.
+func (s *Service) CreateKey(ctx context.Context, userUUID string, tier Tier) (string, APIKey, error) {
+	if !tier.Valid() || tier == TierAnonymous {
+		return "", APIKey{}, ErrInvalidTier
+	}
+	if _, err := s.repo.UserTier(ctx, userUUID); err != nil {
+		return "", APIKey{}, err
+	}
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", APIKey{}, err
+	}
+	key := keyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	created, err := s.repo.CreateKey(ctx, APIKey{UserUUID: userUUID, Prefix: key[:len(keyPrefix)+6], Tier: tier}, hashKey(key))
+	if err != nil {
+		return "", APIKey{}, err
+	}
+	return key, created, nil
+}
+
+// Prune drops counters from before the previous month.
+func (s *Service) Prune(ctx context.Context) (int64, error) {
+	_, month, _, _ := windows(s.now())
+	return s.repo.DeleteBefore(ctx, month.AddDate(0, -1, 0))
+}
+
+func (s *Service) usage(ctx context.Context, subject string, tier Tier) (Window, Window, error) {
+	day, month, dayReset, monthReset := windows(s.now())
+	daily, monthly, err := s.repo.Usage(ctx, subject, day, month)
+	if err != nil {
+		return Window{}, Window{}, err
+	}
+	limits := s.plans[tier]
+	return window(limits.Daily, daily, dayReset), window(limits.Monthly, monthly, monthReset), nil
+}
+
+// cached resolves a subject through the in-memory cache, so most requests cost a
+// single counter update. Failures are not cached.
+func (s *Service) cached(key string, load func() (Subject, error)) (Subject, error) {
+	now := s.now()
+	s.mu.Lock()
+	r, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && now.Before(r.expires) {
+		return r.subject, nil
+	}
+
+	subject, err := load()
+	if err != nil {
+		return Subject{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cache) >= resolveEntries {
+		clear(s.cache)
+	}
+	s.cache[key] = resolved{subject: subject, expires: now.Add(resolveTTL)}
+	return subject, nil
+}
+
+func window(limit, used int64, resetsAt time.Time) Window {
+	w := Window{Used: used, ResetsAt: resetsAt}
+	if limit > 0 {
+		remaining := max(limit-used, 0)
+		w.Limit, w.Remaining = &limit, &remaining
+	}
+	return w
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func userSubject(uuid string) string { return "user:" + uuid }
+
+func keySubject(id int64) string { return "key:" + strconv.FormatInt(id, 10) }
@@ -0,0 +1,206 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo keeps keys, tiers and counters in memory.
+type fakeRepo struct {
+	mu           sync.Mutex
+	keys         map[string]APIKey // by hash
+	tiers        map[string]Tier   // by user UUID
+	counts       map[string]int64  // by subject, period and window start
+	lookups      int
+	incrementErr error
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{keys: map[string]APIKey{}, tiers: map[string]Tier{}, counts: map[string]int64{}}
+}
+
+func (r *fakeRepo) KeyByHash(ctx context.Context, hash string) (APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	k, ok := r.keys[hash]
+	if !ok {
+		return APIKey{}, ErrInvalidKey
+	}
+	return k, nil
+}
+
+func (r *fakeRepo) UserTier(ctx context.Context, userUUID string) (Tier, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	tier, ok := r.tiers[userUUID]
+	if !ok {
+		return "", ErrUserNotFound
+	}
+	return tier, nil
+}
+
+func (r *fakeRepo) Increment(ctx context.Context, subject string, day, month time.Time) (int64, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.incrementErr != nil {
+		return 0, 0, r.incrementErr
+	}
+	d, m := subject+"/day/"+day.Format(time.DateOnly), subject+"/month/"+month.Format(time.DateOnly)
+	r.counts[d]++
+	r.counts[m]++
+	return r.counts[d], r.counts[m], nil
+}
+
+func (r *fakeRepo) Usage(ctx context.Context, subject string, day, month time.Time) (int64, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counts[subject+"/day/"+day.Format(time.DateOnly)], r.counts[subject+"/month/"+month.Format(time.DateOnly)], nil
+}
+
+func (r *fakeRepo) CreateKey(ctx context.Context, key APIKey, hash string) (APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key.ID = int64(len(r.keys) + 1)
+	r.keys[hash] = key
+	return key, nil
+}
+
+func (r *fakeRepo) ListKeys(ctx context.Context, userUUID string) ([]APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var keys []APIKey
+	for _, k := range r.keys {
+		if k.UserUUID == userUUID {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (r *fakeRepo) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) { return 0, nil }
+
+func ptr(v int64) *int64 { return &v }
+
+func TestService_Resolve(t *testing.T) {
+	repo := newFakeRepo()
+	repo.tiers["founder-1"] = TierVerifiedFounder
+	repo.keys[hashKey("gv_secret")] = APIKey{ID: 7, UserUUID: "founder-1", Tier: TierPartner}
+	s := NewService(repo, DefaultPlans())
+	ctx := context.Background()
+
+	subject, err := s.Resolve(ctx, "gv_secret", "founder-1", "10.0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, Subject{ID: "key:7", Tier: TierPartner}, subject)
+
+	subject, err = s.Resolve(ctx, "", "founder-1", "10.0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, Subject{ID: "user:founder-1", Tier: TierVerifiedFounder}, subject)
+
+	subject, err = s.Resolve(ctx, "", "", "10.0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, Subject{ID: "ip:10.0.0.1", Tier: TierAnonymous}, subject)
+
+	_, err = s.Resolve(ctx, "gv_wrong", "", "10.0.0.1")
+	require.ErrorIs(t, err, ErrInvalidKey)
+
+	// Resolved keys and users are cached; failures are not
+	_, _ = s.Resolve(ctx, "gv_secret", "", "")
+	_, _ = s.Resolve(ctx, "", "founder-1", "")
+	_, _ = s.Resolve(ctx, "gv_wrong", "", "")
+	require.Equal(t, 4, repo.lookups)
+}
+
+func TestService_Consume(t *testing.T) {
+	repo := newFakeRepo()
+	s := NewService(repo, Plans{TierBuyer: {Daily: 2, Monthly: 10}, TierFounder: {Monthly: 3}})
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+	buyer := Subject{ID: "user:b", Tier: TierBuyer}
+
+	w, allowed, err := s.Consume(ctx, buyer)
+	require.NoError(t, err)
+	require.True(t, allowed)
+	require.Equal(t, Window{Limit: ptr(2), Used: 1, Remaining: ptr(1), ResetsAt: time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)}, w)
+
+	_, allowed, _ = s.Consume(ctx, buyer)
+	require.True(t, allowed)
+	w, allowed, _ = s.Consume(ctx, buyer)
+	require.False(t, allowed)
+	require.Equal(t, int64(0), *w.Remaining)
+
+	// A new day resets the daily window but not the monthly one
+	now = now.Add(24 * time.Hour)
+	w, allowed, _ = s.Consume(ctx, buyer)
+	require.True(t, allowed)
+	require.Equal(t, int64(4), repo.counts["user:b/month/2026-10-01"])
+	require.Equal(t, ptr(1), w.Remaining)
+
+	// Only a monthly limit: that window is reported
+	w, _, _ = s.Consume(ctx, Subject{ID: "user:f", Tier: TierFounder})
+	require.Equal(t, Window{Limit: ptr(3), Used: 1, Remaining: ptr(2), ResetsAt: time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)}, w)
+
+	// Unlimited tiers are not counted
+	w, allowed, err = s.Consume(ctx, Subject{ID: "user:a", Tier: TierAdmin})
+	require.NoError(t, err)
+	require.True(t, allowed)
+	require.Equal(t, Window{}, w)
+	require.NotContains(t, repo.counts, "user:a/day/2026-10-17")
+}
+
+func TestService_Consume_StoreError(t *testing.T) {
+	repo := newFakeRepo()
+	repo.incrementErr = errors.New("connection refused")
+	s := NewService(repo, DefaultPlans())
+
+	_, allowed, err := s.Consume(context.Background(), Subject{ID: "ip:1.2.3.4", Tier: TierAnonymous})
+
+	require.Error(t, err)
+	require.True(t, allowed)
+}
+
+func TestService_CreateKeyAndUsage(t *testing.T) {
+	repo := newFakeRepo()
+	repo.tiers["founder-1"] = TierFounder
+	s := NewService(repo, Plans{TierFounder: {Daily: 100, Monthly: 1000}, TierPartner: {Daily: 5000}})
+	ctx := context.Background()
+
+	_, _, err := s.CreateKey(ctx, "founder-1", TierAnonymous)
+	require.ErrorIs(t, err, ErrInvalidTier)
+	_, _, err = s.CreateKey(ctx, "missing", TierPartner)
+	require.ErrorIs(t, err, ErrUserNotFound)
+
+	key, created, err := s.CreateKey(ctx, "founder-1", TierPartner)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(key, keyPrefix))
+	require.True(t, strings.HasPrefix(key, created.Prefix))
+	require.Len(t, created.Prefix, len(keyPrefix)+6)
+
+	subject, err := s.Resolve(ctx, key, "", "")
+	require.NoError(t, err)
+	_, _, err = s.Consume(ctx, subject)
+	require.NoError(t, err)
+	_, _, err = s.Consume(ctx, Subject{ID: "user:founder-1", Tier: TierFounder})
+	require.NoError(t, err)
+
+	report, err := s.Usage(ctx, "founder-1")
+	require.NoError(t, err)
+	require.Equal(t, TierFounder, report.Tier)
+	require.Equal(t, int64(1), report.Daily.Used)
+	require.Equal(t, ptr(999), report.Monthly.Remaining)
+	require.Len(t, report.Keys, 1)
+	require.Equal(t, ptr(4999), report.Keys[0].Daily.Remaining)
+	require.Nil(t, report.Keys[0].Monthly.Limit)
+	require.Equal(t, int64(1), report.Keys[0].Monthly.Used)
+
+	_, err = s.Usage(ctx, "missing")
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
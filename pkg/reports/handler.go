@@ -0,0 +1,197 @@
+package reports
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/admin"
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+// ReportHandler lets users file reports and follow their status.
+type ReportHandler struct {
+	service *Service
+}
+
+func NewReportHandler(service *Service) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+func (h *ReportHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/reports", auth.Required(), h.file)
+	router.GET("/users/:uuid/reports", auth.RequireSelf("uuid"), h.listByReporter)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *ReportHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/reports",
+			Tag:         "reports",
+			Summary:     "Report a user, startup, asset or message",
+			Description: "target_id is a user UUID for user reports and a numeric ID otherwise; messages can only be reported by their receiver. Repeating a report that is still open returns it with 200 instead of filing another. A user may file 10 reports per hour.",
+			Request:     NewReport{},
+			Response:    Report{},
+			Status:      http.StatusCreated,
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/reports",
+			Tag:         "reports",
+			Summary:     "List a user's reports",
+			Description: "Reports the user filed, newest first, with their moderation status",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "Reporter UUID"),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 20)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[Report]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+const reportsPageSize = 20
+
+func (h *ReportHandler) file(c *gin.Context) {
+	var req NewReport
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	report, created, err := h.service.File(c.Request.Context(), auth.UserID(c), req)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	if !created {
+		response.SendAPIResponse(c, http.StatusOK, true, "report already open", report)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusCreated, true, "report filed", report)
+}
+
+func (h *ReportHandler) listByReporter(c *gin.Context) {
+	p, err := pagination.FromRequest(c, reportsPageSize)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	list, total, err := h.service.ListByReporter(c.Request.Context(), c.Param("uuid"), p)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "reports listed", list, total, p)
+}
+
+// ReviewHandler serves the moderation queue under /admin.
+type ReviewHandler struct {
+	service *Service
+	token   string
+}
+
+// NewReviewHandler serves the moderation routes to callers presenting token as a
+// bearer token, like the other admin endpoints, and to admins and moderators signed
+// in with their own access token.
+func NewReviewHandler(service *Service, token string) *ReviewHandler {
+	return &ReviewHandler{service: service, token: token}
+}
+
+func (h *ReviewHandler) RegisterRoutes(router *gin.Engine) {
+	group := router.Group("/admin/reports", admin.RequireTokenOrPermission(h.token, auth.PermReviewReports))
+	group.GET("", h.list)
+	group.PATCH("/:id", h.updateStatus)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *ReviewHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/admin/reports",
+			Tag:         "admin",
+			Summary:     "Moderation queue",
+			Description: "Reports oldest first, optionally filtered by status",
+			Params: []openapi.Param{
+				openapi.Query("status", "string", "open, reviewing, resolved or dismissed (default all)", false),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 20)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[Report]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodPatch,
+			Path:        "/admin/reports/:id",
+			Tag:         "admin",
+			Summary:     "Update a report's status",
+			Description: "Open reports may move to reviewing, resolved or dismissed; reviewing ones to resolved or dismissed. The reporter is notified in-app.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Report ID"),
+			},
+			Request:  StatusUpdate{},
+			Response: Report{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+func (h *ReviewHandler) list(c *gin.Context) {
+	status := Status(c.Query("status"))
+	switch status {
+	case "", StatusOpen, StatusReviewing, StatusResolved, StatusDismissed:
+	default:
+		response.SendError(c, response.InvalidField(apperr.InvalidRequest, "status", "oneof", "invalid report status"))
+		return
+	}
+
+	p, err := pagination.FromRequest(c, reportsPageSize)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	list, total, err := h.service.List(c.Request.Context(), status, p)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "reports listed", list, total, p)
+}
+
+func (h *ReviewHandler) updateStatus(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid report id"))
+		return
+	}
+
+	var req StatusUpdate
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	report, err := h.service.UpdateStatus(c.Request.Context(), id, req)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "report updated", report)
+}
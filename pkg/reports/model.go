@@ -0,0 +1,55 @@
+package reports
+
+import "time"
+
+// TargetType is the kind of entity a report is about.
+type TargetType string
+
+const (
+	TargetUser    TargetType = "user"
+	TargetStartup TargetType = "startup"
+	TargetAsset   TargetType = "asset"
+	TargetMessage TargetType = "message"
+)
+
+// Status is where a report is in moderation. Open and reviewing reports are
+// pending; resolved and dismissed are final.
+type Status string
+
+const (
+	StatusOpen      Status = "open"
+	StatusReviewing Status = "reviewing"
+	StatusResolved  Status = "resolved"
+	StatusDismissed Status = "dismissed"
+)
+
+// transitions lists the statuses a moderator may move a report to from each status.
+var transitions = map[Status][]Status{
+	StatusOpen:      {StatusReviewing, StatusResolved, StatusDismissed},
+	StatusReviewing: {StatusResolved, StatusDismissed},
+}
+
+// CanMoveTo reports whether a report in status s may be moved to next.
+func (s Status) CanMoveTo(next Status) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Report is an abuse report filed by a user. TargetID is a user UUID for user
+// reports and a numeric ID for the other target types.
+type Report struct {
+	ID             int64      `json:"id"`
+	ReporterUUID   string     `json:"reporter_uuid"`
+	TargetType     TargetType `json:"target_type"`
+	TargetID       string     `json:"target_id"`
+	Reason         string     `json:"reason"`
+	Details        string     `json:"details,omitempty"`
+	Status         Status     `json:"status"`
+	ResolutionNote string     `json:"resolution_note,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,158 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReportRepository interface {
+	// Create inserts r unless the reporter already has a pending report on the same
+	// target, in which case it returns that report and created is false.
+	Create(ctx context.Context, r Report) (report Report, created bool, err error)
+	Get(ctx context.Context, id int64) (Report, error)
+	FindPending(ctx context.Context, reporterUUID string, t TargetType, targetID string) (Report, error)
+	ListByReporter(ctx context.Context, reporterUUID string, limit, offset int) ([]Report, int64, error)
+	List(ctx context.Context, status Status, limit, offset int) ([]Report, int64, error)
+	CountSince(ctx context.Context, reporterUUID string, since time.Time) (int, error)
+	UpdateStatus(ctx context.Context, id int64, from, to Status, note string) (Report, error)
+	// TargetExists reports whether the target can be reported by reporterUUID:
+	// users, startups and assets must not be deleted, and messages must have been
+	// sent to the reporter.
+	TargetExists(ctx context.Context, reporterUUID string, t TargetType, targetID string) (bool, error)
+}
+
+type postgresReportRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresReportRepository(pool *pgxpool.Pool) ReportRepository {
+	return &postgresReportRepository{pool: pool}
+}
+
+const reportColumns = `id, reporter_uuid, target_type, target_id, reason, details, status, resolution_note, created_at, updated_at`
+
+func scanReport(row pgx.Row) (Report, error) {
+	var r Report
+	err := row.Scan(&r.ID, &r.ReporterUUID, &r.TargetType, &r.TargetID, &r.Reason, &r.Details, &r.Status, &r.ResolutionNote, &r.CreatedAt, &r.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Report{}, ErrReportNotFound
+	}
+	return r, err
+}
+
+func (r *postgresReportRepository) Create(ctx context.Context, rep Report) (Report, bool, error) {
+	query := `
+		INSERT INTO reports (reporter_uuid, target_type, target_id, reason, details)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (reporter_uuid, target_type, target_id) WHERE status IN ('open', 'reviewing') DO NOTHING
+		RETURNING ` + reportColumns
+	created, err := scanReport(r.pool.QueryRow(ctx, query, rep.ReporterUUID, rep.TargetType, rep.TargetID, rep.Reason, rep.Details))
+	if errors.Is(err, ErrReportNotFound) {
+		existing, err := r.FindPending(ctx, rep.ReporterUUID, rep.TargetType, rep.TargetID)
+		return existing, false, err
+	}
+	return created, err == nil, err
+}
+
+func (r *postgresReportRepository) Get(ctx context.Context, id int64) (Report, error) {
+	return scanReport(r.pool.QueryRow(ctx, `SELECT `+reportColumns+` FROM reports WHERE id = $1`, id))
+}
+
+func (r *postgresReportRepository) FindPending(ctx context.Context, reporterUUID string, t TargetType, targetID string) (Report, error) {
+	query := `SELECT ` + reportColumns + ` FROM reports
+		WHERE reporter_uuid = $1 AND target_type = $2 AND target_id = $3 AND status IN ('open', 'reviewing')`
+	return scanReport(r.pool.QueryRow(ctx, query, reporterUUID, t, targetID))
+}
+
+func (r *postgresReportRepository) ListByReporter(ctx context.Context, reporterUUID string, limit, offset int) ([]Report, int64, error) {
+	return r.list(ctx, `reporter_uuid = $1`, `id DESC`, reporterUUID, limit, offset)
+}
+
+// List returns reports in status, oldest first so moderators work through the
+// queue in order. An empty status lists every report.
+func (r *postgresReportRepository) List(ctx context.Context, status Status, limit, offset int) ([]Report, int64, error) {
+	return r.list(ctx, `($1::text = '' OR status = $1)`, `id`, string(status), limit, offset)
+}
+
+func (r *postgresReportRepository) list(ctx context.Context, where, order string, arg any, limit, offset int) ([]Report, int64, error) {
+	var total int64
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM reports WHERE `+where, arg).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.pool.Query(ctx, `SELECT `+reportColumns+` FROM reports WHERE `+where+` ORDER BY `+order+` LIMIT $2 OFFSET $3`, arg, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var list []Report
+	for rows.Next() {
+		rep, err := scanReport(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, rep)
+	}
+	return list, total, rows.Err()
+}
+
+func (r *postgresReportRepository) CountSince(ctx context.Context, reporterUUID string, since time.Time) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM reports WHERE reporter_uuid = $1 AND created_at > $2`, reporterUUID, since).Scan(&count)
+	return count, err
+}
+
+// UpdateStatus moves report id from status from to status to. It returns
+// ErrInvalidTransition when the report is no longer in from, so concurrent
+// moderators cannot both act on the same report.
+func (r *postgresReportRepository) UpdateStatus(ctx context.Context, id int64, from, to Status, note string) (Report, error) {
+	query := `
+		UPDATE reports SET status = $3, resolution_note = $4, updated_at = NOW()
+		WHERE id = $1 AND status = $2
+		RETURNING ` + reportColumns
+	rep, err := scanReport(r.pool.QueryRow(ctx, query, id, from, to, note))
+	if errors.Is(err, ErrReportNotFound) {
+		return Report{}, ErrInvalidTransition
+	}
+	return rep, err
+}
+
+func (r *postgresReportRepository) TargetExists(ctx context.Context, reporterUUID string, t TargetType, targetID string) (bool, error) {
+	var query string
+	switch t {
+	case TargetUser:
+		query = `SELECT EXISTS (SELECT 1 FROM users WHERE uuid = $1 AND is_deleted = false)`
+	case TargetStartup:
+		query = `SELECT EXISTS (SELECT 1 FROM startups WHERE id = $1 AND is_deleted = false)`
+	case TargetAsset:
+		query = `SELECT EXISTS (SELECT 1 FROM assets WHERE id = $1 AND is_deleted = false)`
+	case TargetMessage:
+		query = `SELECT EXISTS (
+			SELECT 1 FROM messages m JOIN users u ON u.id = m.receiver_id
+			WHERE m.id = $1 AND u.uuid = $2)`
+	default:
+		return false, nil
+	}
+
+	args := []any{targetID}
+	if t != TargetUser {
+		id, err := strconv.ParseInt(targetID, 10, 64)
+		if err != nil {
+			return false, nil
+		}
+		args[0] = id
+	}
+	if t == TargetMessage {
+		args = append(args, reporterUUID)
+	}
+
+	var exists bool
+	err := r.pool.QueryRow(ctx, query, args...).Scan(&exists)
+	return exists, err
+}
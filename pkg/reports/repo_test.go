@@ -0,0 +1,112 @@
+package reports
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresReportRepository_CreateDeduplicatesPending(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresReportRepository(pool)
+	ctx := context.Background()
+	reporter := testhelpers.NewUser(t, pool)
+	target := testhelpers.NewUser(t, pool)
+
+	in := Report{ReporterUUID: reporter.UUID, TargetType: TargetUser, TargetID: target.UUID, Reason: "spam"}
+	first, created, err := repo.Create(ctx, in)
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Equal(t, StatusOpen, first.Status)
+
+	again, created, err := repo.Create(ctx, in)
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Equal(t, first.ID, again.ID)
+
+	// Once the first report is closed the same target can be reported again
+	_, err = repo.UpdateStatus(ctx, first.ID, StatusOpen, StatusDismissed, "")
+	require.NoError(t, err)
+	second, created, err := repo.Create(ctx, in)
+	require.NoError(t, err)
+	require.True(t, created)
+	require.NotEqual(t, first.ID, second.ID)
+
+	count, err := repo.CountSince(ctx, reporter.UUID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	list, total, err := repo.ListByReporter(ctx, reporter.UUID, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), total)
+	require.Equal(t, second.ID, list[0].ID)
+}
+
+func TestPostgresReportRepository_UpdateStatusRequiresCurrentStatus(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresReportRepository(pool)
+	ctx := context.Background()
+	reporter := testhelpers.NewUser(t, pool)
+	asset := testhelpers.NewAsset(t, pool)
+
+	rep, _, err := repo.Create(ctx, Report{ReporterUUID: reporter.UUID, TargetType: TargetAsset, TargetID: strconv.FormatInt(asset.ID, 10), Reason: "scam"})
+	require.NoError(t, err)
+
+	updated, err := repo.UpdateStatus(ctx, rep.ID, StatusOpen, StatusReviewing, "looking into it")
+	require.NoError(t, err)
+	require.Equal(t, StatusReviewing, updated.Status)
+	require.Equal(t, "looking into it", updated.ResolutionNote)
+
+	_, err = repo.UpdateStatus(ctx, rep.ID, StatusOpen, StatusResolved, "")
+	require.ErrorIs(t, err, ErrInvalidTransition)
+
+	queue, total, err := repo.List(ctx, StatusReviewing, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, rep.ID, queue[0].ID)
+}
+
+func TestPostgresReportRepository_TargetExists(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+
+	repo := NewPostgresReportRepository(pool)
+	ctx := context.Background()
+	reporter := testhelpers.NewUser(t, pool)
+	other := testhelpers.NewUser(t, pool)
+	deleted := testhelpers.NewUser(t, pool, testhelpers.WithUserDeleted())
+	startup := testhelpers.NewStartup(t, pool)
+	received := testhelpers.NewMessage(t, pool, testhelpers.WithSender(other.ID), testhelpers.WithReceiver(reporter.ID))
+	sent := testhelpers.NewMessage(t, pool, testhelpers.WithSender(reporter.ID), testhelpers.WithReceiver(other.ID))
+
+	tests := []struct {
+		name     string
+		typ      TargetType
+		targetID string
+		want     bool
+	}{
+		{"user", TargetUser, other.UUID, true},
+		{"deleted user", TargetUser, deleted.UUID, false},
+		{"startup", TargetStartup, strconv.FormatInt(startup.ID, 10), true},
+		{"missing asset", TargetAsset, "999999", false},
+		{"non-numeric id", TargetAsset, "abc", false},
+		{"received message", TargetMessage, strconv.FormatInt(received.ID, 10), true},
+		{"sent message", TargetMessage, strconv.FormatInt(sent.ID, 10), false},
+	}
+	for _, tt := range tests {
+		ok, err := repo.TargetExists(ctx, reporter.UUID, tt.typ, tt.targetID)
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.want, ok, tt.name)
+	}
+}
@@ -0,0 +1,126 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/notifications"
+	"grveyard/pkg/pagination"
+)
+
+// MaxPerHour caps how many reports one user may file in an hour. Repeats of a
+// pending report do not count.
+const MaxPerHour = 10
+
+var (
+	ErrReportNotFound    = apperr.New(apperr.ReportNotFound, "report not found")
+	ErrTargetNotFound    = apperr.New(apperr.ReportTargetNotFound, "reported item not found")
+	ErrTooManyReports    = apperr.New(apperr.ReportRateLimited, "too many reports. Please try again later")
+	ErrInvalidTransition = apperr.New(apperr.InvalidReportStatus, "report cannot move to that status")
+	ErrSelfReport        = apperr.New(apperr.InvalidRequest, "you cannot report yourself")
+)
+
+// NewReport is what a user submits to file a report.
+type NewReport struct {
+	TargetType TargetType `json:"target_type" binding:"required,oneof=user startup asset message"`
+	TargetID   string     `json:"target_id" binding:"required,max=64"`
+	Reason     string     `json:"reason" binding:"required,oneof=spam scam abuse inappropriate other"`
+	Details    string     `json:"details" binding:"max=2000"`
+}
+
+// StatusUpdate is a moderator's decision on a report.
+type StatusUpdate struct {
+	Status Status `json:"status" binding:"required,oneof=reviewing resolved dismissed"`
+	Note   string `json:"note" binding:"max=2000"`
+}
+
+type Service struct {
+	repo      ReportRepository
+	publisher notifications.Publisher // optional; if nil, reporters are not told about status changes
+	now       func() time.Time
+}
+
+func NewService(repo ReportRepository, publisher notifications.Publisher) *Service {
+	return &Service{repo: repo, publisher: publisher, now: time.Now}
+}
+
+// File records reporterUUID's report. A repeat of a report that is still pending
+// returns the existing report with created false instead of adding another.
+func (s *Service) File(ctx context.Context, reporterUUID string, in NewReport) (Report, bool, error) {
+	in.TargetID = strings.TrimSpace(in.TargetID)
+	if in.TargetType == TargetUser && in.TargetID == reporterUUID {
+		return Report{}, false, ErrSelfReport
+	}
+
+	existing, err := s.repo.FindPending(ctx, reporterUUID, in.TargetType, in.TargetID)
+	if err == nil {
+		return existing, false, nil
+	}
+	if !errors.Is(err, ErrReportNotFound) {
+		return Report{}, false, err
+	}
+
+	count, err := s.repo.CountSince(ctx, reporterUUID, s.now().Add(-time.Hour))
+	if err != nil {
+		return Report{}, false, err
+	}
+	if count >= MaxPerHour {
+		return Report{}, false, ErrTooManyReports
+	}
+
+	ok, err := s.repo.TargetExists(ctx, reporterUUID, in.TargetType, in.TargetID)
+	if err != nil {
+		return Report{}, false, err
+	}
+	if !ok {
+		return Report{}, false, ErrTargetNotFound
+	}
+
+	return s.repo.Create(ctx, Report{
+		ReporterUUID: reporterUUID,
+		TargetType:   in.TargetType,
+		TargetID:     in.TargetID,
+		Reason:       in.Reason,
+		Details:      strings.TrimSpace(in.Details),
+	})
+}
+
+// ListByReporter returns the reports reporterUUID filed, newest first, with their
+// current status.
+func (s *Service) ListByReporter(ctx context.Context, reporterUUID string, p pagination.Params) ([]Report, int64, error) {
+	return s.repo.ListByReporter(ctx, reporterUUID, p.Limit, p.Offset())
+}
+
+// List returns the moderation queue, optionally only reports in status.
+func (s *Service) List(ctx context.Context, status Status, p pagination.Params) ([]Report, int64, error) {
+	return s.repo.List(ctx, status, p.Limit, p.Offset())
+}
+
+// UpdateStatus applies a moderator's decision and notifies the reporter.
+func (s *Service) UpdateStatus(ctx context.Context, id int64, in StatusUpdate) (Report, error) {
+	current, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return Report{}, err
+	}
+	if !current.Status.CanMoveTo(in.Status) {
+		return Report{}, ErrInvalidTransition
+	}
+
+	updated, err := s.repo.UpdateStatus(ctx, id, current.Status, in.Status, strings.TrimSpace(in.Note))
+	if err != nil {
+		return Report{}, err
+	}
+
+	if s.publisher != nil {
+		s.publisher.Publish(ctx, notifications.Event{
+			Type:          notifications.EventReportUpdated,
+			RecipientUUID: updated.ReporterUUID,
+			EntityID:      updated.ID,
+			Title:         string(updated.Status),
+		})
+	}
+	return updated, nil
+}
@@ -0,0 +1,192 @@
+package reports
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/notifications"
+)
+
+type mockReportRepository struct {
+	mock.Mock
+}
+
+func (m *mockReportRepository) Create(ctx context.Context, r Report) (Report, bool, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(Report), args.Bool(1), args.Error(2)
+}
+
+func (m *mockReportRepository) Get(ctx context.Context, id int64) (Report, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Report), args.Error(1)
+}
+
+func (m *mockReportRepository) FindPending(ctx context.Context, reporterUUID string, t TargetType, targetID string) (Report, error) {
+	args := m.Called(ctx, reporterUUID, t, targetID)
+	return args.Get(0).(Report), args.Error(1)
+}
+
+func (m *mockReportRepository) ListByReporter(ctx context.Context, reporterUUID string, limit, offset int) ([]Report, int64, error) {
+	args := m.Called(ctx, reporterUUID, limit, offset)
+	list, _ := args.Get(0).([]Report)
+	return list, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockReportRepository) List(ctx context.Context, status Status, limit, offset int) ([]Report, int64, error) {
+	args := m.Called(ctx, status, limit, offset)
+	list, _ := args.Get(0).([]Report)
+	return list, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockReportRepository) CountSince(ctx context.Context, reporterUUID string, since time.Time) (int, error) {
+	args := m.Called(ctx, reporterUUID, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockReportRepository) UpdateStatus(ctx context.Context, id int64, from, to Status, note string) (Report, error) {
+	args := m.Called(ctx, id, from, to, note)
+	return args.Get(0).(Report), args.Error(1)
+}
+
+func (m *mockReportRepository) TargetExists(ctx context.Context, reporterUUID string, t TargetType, targetID string) (bool, error) {
+	args := m.Called(ctx, reporterUUID, t, targetID)
+	return args.Bool(0), args.Error(1)
+}
+
+type mockPublisher struct {
+	events []notifications.Event
+}
+
+func (p *mockPublisher) Publish(ctx context.Context, ev notifications.Event) {
+	p.events = append(p.events, ev)
+}
+
+const reporter = "11111111-1111-4111-8111-111111111111"
+
+func TestService_File(t *testing.T) {
+	repo := new(mockReportRepository)
+	service := NewService(repo, nil)
+
+	in := NewReport{TargetType: TargetAsset, TargetID: " 42 ", Reason: "scam", Details: "asks for payment off-site "}
+	want := Report{ReporterUUID: reporter, TargetType: TargetAsset, TargetID: "42", Reason: "scam", Details: "asks for payment off-site"}
+	repo.On("FindPending", mock.Anything, reporter, TargetAsset, "42").Return(Report{}, ErrReportNotFound)
+	repo.On("CountSince", mock.Anything, reporter, mock.Anything).Return(0, nil)
+	repo.On("TargetExists", mock.Anything, reporter, TargetAsset, "42").Return(true, nil)
+	repo.On("Create", mock.Anything, want).Return(Report{ID: 1, Status: StatusOpen}, true, nil)
+
+	report, created, err := service.File(context.Background(), reporter, in)
+
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Equal(t, int64(1), report.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestService_File_ReturnsPendingReport(t *testing.T) {
+	repo := new(mockReportRepository)
+	service := NewService(repo, nil)
+
+	existing := Report{ID: 7, Status: StatusReviewing}
+	repo.On("FindPending", mock.Anything, reporter, TargetUser, "u-2").Return(existing, nil)
+
+	report, created, err := service.File(context.Background(), reporter, NewReport{TargetType: TargetUser, TargetID: "u-2", Reason: "spam"})
+
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Equal(t, existing, report)
+	repo.AssertNotCalled(t, "CountSince", mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestService_File_RateLimited(t *testing.T) {
+	repo := new(mockReportRepository)
+	service := NewService(repo, nil)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	repo.On("FindPending", mock.Anything, reporter, TargetStartup, "3").Return(Report{}, ErrReportNotFound)
+	repo.On("CountSince", mock.Anything, reporter, now.Add(-time.Hour)).Return(MaxPerHour, nil)
+
+	_, _, err := service.File(context.Background(), reporter, NewReport{TargetType: TargetStartup, TargetID: "3", Reason: "spam"})
+
+	require.ErrorIs(t, err, ErrTooManyReports)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestService_File_UnknownTarget(t *testing.T) {
+	repo := new(mockReportRepository)
+	service := NewService(repo, nil)
+
+	repo.On("FindPending", mock.Anything, reporter, TargetMessage, "9").Return(Report{}, ErrReportNotFound)
+	repo.On("CountSince", mock.Anything, reporter, mock.Anything).Return(0, nil)
+	repo.On("TargetExists", mock.Anything, reporter, TargetMessage, "9").Return(false, nil)
+
+	_, _, err := service.File(context.Background(), reporter, NewReport{TargetType: TargetMessage, TargetID: "9", Reason: "abuse"})
+
+	require.ErrorIs(t, err, ErrTargetNotFound)
+}
+
+func TestService_File_Self(t *testing.T) {
+	service := NewService(new(mockReportRepository), nil)
+
+	_, _, err := service.File(context.Background(), reporter, NewReport{TargetType: TargetUser, TargetID: reporter, Reason: "spam"})
+
+	require.ErrorIs(t, err, ErrSelfReport)
+}
+
+func TestService_UpdateStatus_NotifiesReporter(t *testing.T) {
+	repo := new(mockReportRepository)
+	pub := &mockPublisher{}
+	service := NewService(repo, pub)
+
+	repo.On("Get", mock.Anything, int64(5)).Return(Report{ID: 5, ReporterUUID: reporter, Status: StatusOpen}, nil)
+	repo.On("UpdateStatus", mock.Anything, int64(5), StatusOpen, StatusResolved, "listing removed").
+		Return(Report{ID: 5, ReporterUUID: reporter, Status: StatusResolved, ResolutionNote: "listing removed"}, nil)
+
+	report, err := service.UpdateStatus(context.Background(), 5, StatusUpdate{Status: StatusResolved, Note: " listing removed"})
+
+	require.NoError(t, err)
+	require.Equal(t, StatusResolved, report.Status)
+	require.Equal(t, []notifications.Event{{
+		Type:          notifications.EventReportUpdated,
+		RecipientUUID: reporter,
+		EntityID:      5,
+		Title:         "resolved",
+	}}, pub.events)
+}
+
+func TestService_UpdateStatus_FinalStatus(t *testing.T) {
+	repo := new(mockReportRepository)
+	pub := &mockPublisher{}
+	service := NewService(repo, pub)
+
+	repo.On("Get", mock.Anything, int64(5)).Return(Report{ID: 5, Status: StatusDismissed}, nil)
+
+	_, err := service.UpdateStatus(context.Background(), 5, StatusUpdate{Status: StatusReviewing})
+
+	require.ErrorIs(t, err, ErrInvalidTransition)
+	require.Empty(t, pub.events)
+	repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStatus_CanMoveTo(t *testing.T) {
+	tests := []struct {
+		from, to Status
+		want     bool
+	}{
+		{StatusOpen, StatusReviewing, true},
+		{StatusOpen, StatusDismissed, true},
+		{StatusReviewing, StatusResolved, true},
+		{StatusReviewing, StatusOpen, false},
+		{StatusReviewing, StatusReviewing, false},
+		{StatusResolved, StatusDismissed, false},
+		{StatusDismissed, StatusOpen, false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, tt.from.CanMoveTo(tt.to), "%s -> %s", tt.from, tt.to)
+	}
+}
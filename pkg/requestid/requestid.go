@@ -0,0 +1,66 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Header carries the request ID in both directions and to downstream services.
+const Header = "X-Request-ID"
+
+// ContextKey is the gin context key the ID is stored under (also used by the access log).
+const ContextKey = "request_id"
+
+const maxLength = 128
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or "".
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// Middleware reuses a well-formed incoming X-Request-ID or generates a new one, stores
+// it on the gin and request contexts, echoes it in the response and tags the trace span.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if !valid(id) {
+			id = uuid.New().String()
+		}
+
+		c.Set(ContextKey, id)
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), id))
+		c.Header(Header, id)
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("request.id", id))
+
+		c.Next()
+	}
+}
+
+// valid accepts IDs from upstream proxies as long as they are short printable ASCII,
+// so they cannot be used to inject into logs or headers.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package requestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRouter(seen *string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/", func(c *gin.Context) {
+		*seen = FromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestMiddleware_ReusesIncomingID(t *testing.T) {
+	var seen string
+	r := setupRouter(&seen)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(Header, "abc-123")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, "abc-123", seen)
+	require.Equal(t, "abc-123", w.Header().Get(Header))
+}
+
+func TestMiddleware_GeneratesWhenMissingOrInvalid(t *testing.T) {
+	var seen string
+	r := setupRouter(&seen)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(Header, "bad id\twith spaces")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.NotEmpty(t, seen)
+	require.NotEqual(t, "bad id\twith spaces", seen)
+	require.Equal(t, seen, w.Header().Get(Header))
+}
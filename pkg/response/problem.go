@@ -0,0 +1,62 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/requestid"
+)
+
+// ProblemContentType is the RFC 7807 media type. Clients that list it in Accept
+// ahead of application/json get error bodies as Problem instead of APIResponse.
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix prefixes the error code to form a Problem's type URI.
+const ProblemTypePrefix = "urn:grveyard:error:"
+
+// Problem is an RFC 7807 error body. Code, RequestID and Errors are extension
+// members carrying the same information as the default envelope.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      apperr.Code  `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// wantsProblem reports whether the Accept header prefers problem+json; the envelope
+// stays the default for */* and for clients that don't send Accept.
+func wantsProblem(c *gin.Context) bool {
+	return c.NegotiateFormat(gin.MIMEJSON, ProblemContentType) == ProblemContentType
+}
+
+func sendProblem(c *gin.Context, status int, code apperr.Code, detail string, details any) {
+	title := http.StatusText(status)
+	if def, ok := apperr.Lookup(code); ok {
+		title = def.Description
+	}
+	p := Problem{
+		Type:      ProblemTypePrefix + string(code),
+		Title:     title,
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		RequestID: c.GetString(requestid.ContextKey),
+	}
+	if v, ok := details.(ValidationErrors); ok {
+		p.Errors = v.Errors
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		c.Status(status)
+		return
+	}
+	c.Data(status, ProblemContentType+"; charset=utf-8", body)
+}
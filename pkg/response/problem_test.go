@@ -0,0 +1,61 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/apperr"
+)
+
+func sendErrorWithAccept(t *testing.T, accept string, err error) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/assets/:id", func(c *gin.Context) { SendError(c, err) })
+
+	req := httptest.NewRequest(http.MethodGet, "/assets/7", nil)
+	req.Header.Set("Accept", accept)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestSendError_ProblemJSON(t *testing.T) {
+	w := sendErrorWithAccept(t, "application/problem+json", apperr.New(apperr.AssetNotFound, "asset not found"))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, "application/problem+json; charset=utf-8", w.Header().Get("Content-Type"))
+	var p Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	require.Equal(t, "urn:grveyard:error:ASSET_NOT_FOUND", p.Type)
+	require.Equal(t, http.StatusNotFound, p.Status)
+	require.Equal(t, "asset not found", p.Detail)
+	require.Equal(t, "/assets/7", p.Instance)
+	require.Equal(t, apperr.AssetNotFound, p.Code)
+	require.NotEmpty(t, p.Title)
+}
+
+func TestSendError_ProblemJSONCarriesFieldErrors(t *testing.T) {
+	w := sendErrorWithAccept(t, "application/problem+json, application/json;q=0.5",
+		InvalidField(apperr.InvalidPrice, "price", "gte", "price cannot be negative"))
+
+	var p Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	require.Equal(t, []FieldError{{Field: "price", Rule: "gte", Message: "price cannot be negative"}}, p.Errors)
+}
+
+func TestSendError_EnvelopeByDefault(t *testing.T) {
+	for _, accept := range []string{"", "*/*", "application/json", "application/json, application/problem+json"} {
+		w := sendErrorWithAccept(t, accept, apperr.New(apperr.AssetNotFound, "asset not found"))
+		require.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"), accept)
+
+		var resp APIResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Equal(t, apperr.AssetNotFound, resp.ErrorCode)
+	}
+}
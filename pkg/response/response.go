@@ -1,25 +1,176 @@
 package response
 
 import (
+	"errors"
+	"log"
+	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/i18n"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/requestid"
 )
 
 type APIResponse struct {
-	Success   bool      `json:"success"`
-	Message   string    `json:"message"`
-	Data      any       `json:"data,omitempty"`
-	CreatedAt time.Time `json:"created_at,omitempty"`
+	Success   bool              `json:"success"`
+	Message   string            `json:"message"`
+	ErrorCode apperr.Code       `json:"error_code,omitempty"` // set on failures; see apperr for the registry
+	Data      any               `json:"data,omitempty"`
+	Links     *pagination.Links `json:"links,omitempty"`
+	RequestID string            `json:"request_id,omitempty"` // set on failures so users can quote it to support
+	CreatedAt time.Time         `json:"created_at,omitempty"`
 }
 
+// SendAPIResponse writes the standard envelope. message is the English text; it is
+// translated into the caller's Accept-Language when the i18n catalog has it.
 func SendAPIResponse(c *gin.Context, code int, success bool, message string, data any) {
 	resp := APIResponse{
 		Success:   success,
-		Message:   message,
+		Message:   localize(c, message),
 		Data:      data,
 		CreatedAt: time.Now(),
 	}
+	if !success {
+		resp.RequestID = c.GetString(requestid.ContextKey)
+	}
 
 	c.JSON(code, resp)
 }
+
+// FieldError describes one invalid field, named as it appears in the JSON body. Rule
+// is the check that failed (required, max, oneof, type, ...) so forms can pick their
+// own wording; Message is a ready-to-show, localized fallback.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is the data payload of a 400 validation response.
+type ValidationErrors struct {
+	Errors []FieldError `json:"errors"`
+}
+
+// InvalidField is a failure with code for a single field that passed binding but
+// failed a handler-level check, reported in the same shape as binding errors.
+func InvalidField(code apperr.Code, field, rule, message string) *apperr.Error {
+	return apperr.New(code, message).WithDetails(ValidationErrors{
+		Errors: []FieldError{{Field: field, Rule: rule, Message: message}},
+	})
+}
+
+var hideInternal atomic.Bool
+
+// HideInternalErrors makes SendError answer INTERNAL_ERROR failures with a generic
+// message instead of the error text, which for database errors can include SQL and
+// schema details. serve enables it when APP_ENV=production; the full error is logged
+// with the request ID either way.
+func HideInternalErrors(hide bool) {
+	hideInternal.Store(hide)
+}
+
+// describe is err's text plus the wrapped cause when the apperr message hides it.
+func describe(err error, appErr *apperr.Error) string {
+	msg := err.Error()
+	if appErr.Err != nil && appErr.Err.Error() != msg {
+		msg += ": " + appErr.Err.Error()
+	}
+	return msg
+}
+
+// SendError writes a failure response for err. An *apperr.Error in err's chain supplies
+// the status, code, message and details; any other error becomes a 500 INTERNAL_ERROR.
+// Clients that prefer application/problem+json get a Problem body instead.
+func SendError(c *gin.Context, err error) {
+	var appErr *apperr.Error
+	if !errors.As(err, &appErr) {
+		appErr = apperr.Wrap(apperr.Internal, err.Error(), err)
+	}
+	if appErr.Code == apperr.Internal {
+		log.Printf("[%s] %s %s: %s", c.GetString(requestid.ContextKey), c.Request.Method, c.Request.URL.Path, describe(err, appErr))
+		if hideInternal.Load() {
+			appErr = apperr.New(apperr.Internal, "internal server error")
+		}
+	}
+
+	details := appErr.Details
+	if v, ok := details.(ValidationErrors); ok {
+		// Handler-level field errors are English; binding errors are already localized
+		// and pass through unchanged.
+		locale := Locale(c)
+		fields := make([]FieldError, len(v.Errors))
+		for i, fe := range v.Errors {
+			fe.Message = i18n.Message(locale, fe.Message)
+			fields[i] = fe
+		}
+		details = ValidationErrors{Errors: fields}
+	}
+
+	if wantsProblem(c) {
+		sendProblem(c, appErr.Status(), appErr.Code, localize(c, appErr.Message), details)
+		return
+	}
+	c.JSON(appErr.Status(), APIResponse{
+		Success:   false,
+		Message:   localize(c, appErr.Message),
+		ErrorCode: appErr.Code,
+		Data:      details,
+		RequestID: c.GetString(requestid.ContextKey),
+		CreatedAt: time.Now(),
+	})
+}
+
+// Paginated is the data payload of offset-paginated list endpoints. NextCursor and
+// HasMore mirror links so clients that only read data can still page.
+type Paginated[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int64  `json:"total"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// SendPage sends one page of items out of total as a 200 Paginated response with
+// next/prev links.
+func SendPage[T any](c *gin.Context, message string, items []T, total int64, p pagination.Params) {
+	if items == nil {
+		items = []T{}
+	}
+	links := pagination.PageLinks(c, p, total)
+	SendPaginatedResponse(c, http.StatusOK, message, Paginated[T]{
+		Items:      items,
+		Total:      total,
+		Page:       p.Page,
+		Limit:      p.Limit,
+		NextCursor: links.NextCursor,
+		HasMore:    links.NextCursor != "",
+	}, links)
+}
+
+// SendPaginatedResponse is SendAPIResponse for list endpoints, adding next/prev links.
+func SendPaginatedResponse(c *gin.Context, code int, message string, data any, links *pagination.Links) {
+	c.JSON(code, APIResponse{
+		Success:   true,
+		Message:   localize(c, message),
+		Data:      data,
+		Links:     links,
+		CreatedAt: time.Now(),
+	})
+}
+
+// Locale is the supported language the caller prefers, from Accept-Language.
+func Locale(c *gin.Context) string {
+	return i18n.FromAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
+func localize(c *gin.Context, message string) string {
+	locale := Locale(c)
+	c.Header("Content-Language", locale)
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	return i18n.Message(locale, message)
+}
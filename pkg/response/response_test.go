@@ -0,0 +1,80 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/apperr"
+)
+
+func serve(t *testing.T, acceptLanguage string, handler gin.HandlerFunc) (*httptest.ResponseRecorder, APIResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w, resp
+}
+
+func TestSendAPIResponse_TranslatesMessage(t *testing.T) {
+	w, resp := serve(t, "hi-IN,hi;q=0.9,en;q=0.8", func(c *gin.Context) {
+		SendAPIResponse(c, http.StatusOK, true, "asset created", nil)
+	})
+	require.Equal(t, "संपत्ति बनाई गई", resp.Message)
+	require.Equal(t, "hi", w.Header().Get("Content-Language"))
+	require.Contains(t, w.Header().Values("Vary"), "Accept-Language")
+
+	_, resp = serve(t, "", func(c *gin.Context) {
+		SendAPIResponse(c, http.StatusOK, true, "asset created", nil)
+	})
+	require.Equal(t, "asset created", resp.Message)
+}
+
+func TestSendError_TypedAndUntyped(t *testing.T) {
+	w, resp := serve(t, "hi", func(c *gin.Context) {
+		SendError(c, InvalidField(apperr.InvalidPrice, "price", "gte", "price cannot be negative"))
+	})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, apperr.InvalidPrice, resp.ErrorCode)
+	require.Equal(t, "मूल्य ऋणात्मक नहीं हो सकता", resp.Message)
+	data := resp.Data.(map[string]any)["errors"].([]any)[0].(map[string]any)
+	require.Equal(t, "price", data["field"])
+	require.Equal(t, "मूल्य ऋणात्मक नहीं हो सकता", data["message"])
+
+	w, resp = serve(t, "", func(c *gin.Context) {
+		SendError(c, errors.New("connection refused"))
+	})
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Equal(t, apperr.Internal, resp.ErrorCode)
+	require.False(t, resp.Success)
+}
+
+func TestSendError_HidesInternalDetailsWhenEnabled(t *testing.T) {
+	HideInternalErrors(true)
+	t.Cleanup(func() { HideInternalErrors(false) })
+
+	dbErr := errors.New(`ERROR: column "pricee" does not exist (SQLSTATE 42703)`)
+	w, resp := serve(t, "", func(c *gin.Context) { SendError(c, dbErr) })
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Equal(t, apperr.Internal, resp.ErrorCode)
+	require.Equal(t, "internal server error", resp.Message)
+
+	// Client errors keep their message
+	_, resp = serve(t, "", func(c *gin.Context) { SendError(c, apperr.New(apperr.AssetNotFound, "asset not found")) })
+	require.Equal(t, "asset not found", resp.Message)
+}
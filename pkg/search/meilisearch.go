@@ -0,0 +1,135 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// filterable lists, per index, the attributes DeleteWhere may filter on.
+var filterable = map[string][]string{
+	AssetsIndex:   {"user_uuid"},
+	StartupsIndex: {"owner_uuid"},
+}
+
+// Meilisearch talks to a Meilisearch server over its REST API.
+type Meilisearch struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewMeilisearch returns nil when baseURL is empty so callers can keep search optional.
+func NewMeilisearch(baseURL, apiKey string) *Meilisearch {
+	if baseURL == "" {
+		return nil
+	}
+	return &Meilisearch{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Configure creates the indexes and declares their filterable attributes. Meilisearch
+// applies settings asynchronously; this only waits for the task to be accepted.
+func (m *Meilisearch) Configure(ctx context.Context) error {
+	for index, attrs := range filterable {
+		settings := map[string]any{"filterableAttributes": attrs}
+		if err := m.do(ctx, http.MethodPatch, "/indexes/"+url.PathEscape(index)+"/settings", settings, nil); err != nil {
+			return fmt.Errorf("configure %s: %w", index, err)
+		}
+	}
+	return nil
+}
+
+func (m *Meilisearch) Upsert(ctx context.Context, index string, docs ...Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	return m.do(ctx, http.MethodPost, "/indexes/"+url.PathEscape(index)+"/documents?primaryKey=id", docs, nil)
+}
+
+func (m *Meilisearch) Delete(ctx context.Context, index string, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return m.do(ctx, http.MethodPost, "/indexes/"+url.PathEscape(index)+"/documents/delete-batch", ids, nil)
+}
+
+func (m *Meilisearch) DeleteWhere(ctx context.Context, index, field, value string) error {
+	filter := fmt.Sprintf("%s = %s", field, strconv.Quote(value))
+	return m.do(ctx, http.MethodPost, "/indexes/"+url.PathEscape(index)+"/documents/delete", map[string]any{"filter": filter}, nil)
+}
+
+func (m *Meilisearch) DeleteAll(ctx context.Context, index string) error {
+	return m.do(ctx, http.MethodDelete, "/indexes/"+url.PathEscape(index)+"/documents", nil, nil)
+}
+
+func (m *Meilisearch) Search(ctx context.Context, index, query string, limit, offset int) ([]int64, int64, error) {
+	req := map[string]any{
+		"q":                    query,
+		"limit":                limit,
+		"offset":               offset,
+		"attributesToRetrieve": []string{"id"},
+	}
+	var resp struct {
+		Hits []struct {
+			ID int64 `json:"id"`
+		} `json:"hits"`
+		EstimatedTotalHits int64 `json:"estimatedTotalHits"`
+	}
+	if err := m.do(ctx, http.MethodPost, "/indexes/"+url.PathEscape(index)+"/search", req, &resp); err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]int64, 0, len(resp.Hits))
+	for _, h := range resp.Hits {
+		ids = append(ids, h.ID)
+	}
+	return ids, resp.EstimatedTotalHits, nil
+}
+
+func (m *Meilisearch) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, m.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: %s %s: %d %s", ErrUnavailable, method, path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
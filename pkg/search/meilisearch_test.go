@@ -0,0 +1,58 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMeilisearch_DisabledWithoutURL(t *testing.T) {
+	require.Nil(t, NewMeilisearch("", "key"))
+}
+
+func TestMeilisearch_Search(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/indexes/assets/search", r.URL.Path)
+		require.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "crm", body["q"])
+		require.Equal(t, float64(5), body["limit"])
+		w.Write([]byte(`{"hits":[{"id":3},{"id":1}],"estimatedTotalHits":8}`))
+	}))
+	defer srv.Close()
+
+	ids, total, err := NewMeilisearch(srv.URL+"/", "key").Search(context.Background(), AssetsIndex, "crm", 5, 0)
+
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 1}, ids)
+	require.Equal(t, int64(8), total)
+}
+
+func TestMeilisearch_DeleteWhereQuotesValue(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/indexes/assets/documents/delete", r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, `user_uuid = "a\"b"`, body["filter"])
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	require.NoError(t, NewMeilisearch(srv.URL, "").DeleteWhere(context.Background(), AssetsIndex, "user_uuid", `a"b`))
+}
+
+func TestMeilisearch_ErrorsWrapUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"index not found"}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	_, _, err := NewMeilisearch(srv.URL, "").Search(context.Background(), StartupsIndex, "x", 10, 0)
+	require.ErrorIs(t, err, ErrUnavailable)
+	require.Contains(t, err.Error(), "index not found")
+}
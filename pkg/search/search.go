@@ -0,0 +1,31 @@
+package search
+
+import (
+	"context"
+
+	"grveyard/pkg/apperr"
+)
+
+// Index names shared by the services that publish to and query the search engine.
+const (
+	AssetsIndex   = "assets"
+	StartupsIndex = "startups"
+)
+
+var ErrUnavailable = apperr.New(apperr.ServiceUnavailable, "search engine unavailable")
+
+// Document is one indexed record. It must carry an integer "id" matching the
+// Postgres primary key so hits can be loaded from the database.
+type Document map[string]any
+
+// Index is an external full-text search engine. Services treat it as optional: writes
+// are best effort and reads fall back to SQL when it fails.
+type Index interface {
+	Upsert(ctx context.Context, index string, docs ...Document) error
+	Delete(ctx context.Context, index string, ids ...int64) error
+	// DeleteWhere removes documents whose field equals value; field must be filterable.
+	DeleteWhere(ctx context.Context, index, field, value string) error
+	DeleteAll(ctx context.Context, index string) error
+	// Search returns matching ids, best match first, and the estimated total.
+	Search(ctx context.Context, index, query string, limit, offset int) ([]int64, int64, error)
+}
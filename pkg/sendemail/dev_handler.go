@@ -0,0 +1,54 @@
+package sendemail
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+// DevHandler exposes the sandbox outbox. Only register it when EMAIL_MODE=sandbox.
+type DevHandler struct {
+	sandbox *SandboxEmailService
+}
+
+func NewDevHandler(sandbox *SandboxEmailService) *DevHandler {
+	return &DevHandler{sandbox: sandbox}
+}
+
+func (h *DevHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/dev/emails", h.listEmails)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *DevHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/dev/emails",
+			Tag:         "email",
+			Summary:     "List sandbox emails",
+			Description: "Lists recently \"sent\" emails captured by the sandbox email service (development only)",
+			Params: []openapi.Param{
+				openapi.Query("to", "string", "Only emails sent to this address", false),
+			},
+			Response: []SentEmail{},
+		},
+	}
+}
+
+func (h *DevHandler) listEmails(c *gin.Context) {
+	emails := h.sandbox.Recent()
+	if to := c.Query("to"); to != "" {
+		filtered := make([]SentEmail, 0, len(emails))
+		for _, e := range emails {
+			if normalizeEmail(e.To) == normalizeEmail(to) {
+				filtered = append(filtered, e)
+			}
+		}
+		emails = filtered
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "emails listed", emails)
+}
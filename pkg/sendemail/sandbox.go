@@ -0,0 +1,93 @@
+package sendemail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const sandboxOutboxSize = 100
+
+// SentEmail is a message captured by the sandbox instead of being delivered.
+type SentEmail struct {
+	Subject          string    `json:"subject"`
+	To               string    `json:"to"`
+	PlainTextContent string    `json:"plain_text_content"`
+	HTMLContent      string    `json:"html_content"`
+	SentAt           time.Time `json:"sent_at"`
+}
+
+// SandboxEmailService is a log-only EmailService for local development and tests.
+// Messages are printed, optionally written to dir, and kept in a bounded in-memory outbox.
+type SandboxEmailService struct {
+	mu     sync.RWMutex
+	outbox []SentEmail
+	dir    string
+	logger interface {
+		Printf(string, ...interface{})
+	}
+}
+
+// NewSandboxEmailService creates a sandbox sender. If dir is non-empty each email is also
+// written there as a text file.
+func NewSandboxEmailService(dir string) *SandboxEmailService {
+	return &SandboxEmailService{
+		dir:    dir,
+		logger: log.New(log.Writer(), "[email-sandbox] ", log.LstdFlags),
+	}
+}
+
+func (s *SandboxEmailService) SendEmail(ctx context.Context, subject, toEmail, plainTextContent, htmlContent string) error {
+	email := SentEmail{
+		Subject:          subject,
+		To:               toEmail,
+		PlainTextContent: plainTextContent,
+		HTMLContent:      htmlContent,
+		SentAt:           time.Now().UTC(),
+	}
+
+	s.mu.Lock()
+	s.outbox = append(s.outbox, email)
+	if len(s.outbox) > sandboxOutboxSize {
+		s.outbox = s.outbox[len(s.outbox)-sandboxOutboxSize:]
+	}
+	s.mu.Unlock()
+
+	s.logger.Printf("to=%s subject=%q\n%s", toEmail, subject, plainTextContent)
+
+	if s.dir != "" {
+		if err := s.writeToDisk(email); err != nil {
+			return fmt.Errorf("write sandbox email: %w", err)
+		}
+	}
+	return nil
+}
+
+// Recent returns captured emails, newest first.
+func (s *SandboxEmailService) Recent() []SentEmail {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]SentEmail, 0, len(s.outbox))
+	for i := len(s.outbox) - 1; i >= 0; i-- {
+		list = append(list, s.outbox[i])
+	}
+	return list
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (s *SandboxEmailService) writeToDisk(email SentEmail) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%s.txt", email.SentAt.UnixNano(), unsafeFileChars.ReplaceAllString(email.To, "_"))
+	content := fmt.Sprintf("To: %s\nSubject: %s\nDate: %s\n\n%s\n\n--- HTML ---\n%s\n",
+		email.To, email.Subject, email.SentAt.Format(time.RFC1123Z), email.PlainTextContent, email.HTMLContent)
+	return os.WriteFile(filepath.Join(s.dir, name), []byte(content), 0o644)
+}
@@ -0,0 +1,62 @@
+package sendemail
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/response"
+)
+
+func TestSandboxEmailService_CapturesAndWrites(t *testing.T) {
+	dir := t.TempDir()
+	sandbox := NewSandboxEmailService(dir)
+
+	require.NoError(t, sandbox.SendEmail(context.Background(), "First", "a@example.com", "one", "<p>one</p>"))
+	require.NoError(t, sandbox.SendEmail(context.Background(), "Second", "b@example.com", "two", "<p>two</p>"))
+
+	recent := sandbox.Recent()
+	require.Len(t, recent, 2)
+	require.Equal(t, "Second", recent[0].Subject)
+	require.Equal(t, "a@example.com", recent[1].To)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+}
+
+func TestSandboxEmailService_OutboxIsBounded(t *testing.T) {
+	sandbox := NewSandboxEmailService("")
+	for i := 0; i < sandboxOutboxSize+5; i++ {
+		require.NoError(t, sandbox.SendEmail(context.Background(), "s", "a@example.com", "p", "h"))
+	}
+	require.Len(t, sandbox.Recent(), sandboxOutboxSize)
+}
+
+func TestDevHandler_ListEmails_FilterByRecipient(t *testing.T) {
+	sandbox := NewSandboxEmailService("")
+	require.NoError(t, sandbox.SendEmail(context.Background(), "OTP", "a@example.com", "123456", ""))
+	require.NoError(t, sandbox.SendEmail(context.Background(), "OTP", "b@example.com", "654321", ""))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewDevHandler(sandbox).RegisterRoutes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/dev/emails?to=B@example.com", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp response.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	items, ok := resp.Data.([]any)
+	require.True(t, ok)
+	require.Len(t, items, 1)
+	require.Equal(t, "b@example.com", items[0].(map[string]any)["to"])
+}
@@ -1,18 +1,28 @@
 package sendemail
 
 import (
+	"context"
 	"errors"
 	"os"
+	"sync"
 
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"grveyard/pkg/requestid"
+	"grveyard/pkg/telemetry"
 )
 
 type EmailService interface {
-	SendEmail(subject, toEmail, plainTextContent, htmlContent string) error
+	SendEmail(ctx context.Context, subject, toEmail, plainTextContent, htmlContent string) error
 }
 
 type emailService struct {
+	mu          sync.Mutex
+	apiKey      string
 	clinet      *sendgrid.Client
 	senderEmail string
 	senderName  string
@@ -23,20 +33,49 @@ func NewEmailService() EmailService {
 	senderEmail := os.Getenv("SENDGRID_SENDER_EMAIL")
 	senderName := os.Getenv("SENDGRID_SENDER_NAME")
 	return &emailService{
+		apiKey:      apiKey,
 		clinet:      sendgrid.NewSendClient(apiKey),
 		senderEmail: senderEmail,
 		senderName:  senderName,
 	}
 }
 
-func (e *emailService) SendEmail(subject, toEmail, plainTextContent, htmlContent string) error {
+// client returns a SendGrid client for the current SENDGRID_API_KEY, which changes
+// when a rotated key is loaded from the secrets store.
+func (e *emailService) client() *sendgrid.Client {
+	apiKey := os.Getenv("SENDGRID_API_KEY")
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if apiKey != e.apiKey {
+		e.apiKey = apiKey
+		e.clinet = sendgrid.NewSendClient(apiKey)
+	}
+	return e.clinet
+}
+
+func (e *emailService) SendEmail(ctx context.Context, subject, toEmail, plainTextContent, htmlContent string) (err error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "sendgrid.send", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
 	from := mail.NewEmail(e.senderName, e.senderEmail)
 	to := mail.NewEmail("", toEmail)
 	message := mail.NewSingleEmail(from, subject, to, plainTextContent, htmlContent)
-	response, err := e.clinet.Send(message)
+	if id := requestid.FromContext(ctx); id != "" {
+		// custom_args are echoed back in SendGrid event webhooks
+		message.SetCustomArg(requestid.ContextKey, id)
+		message.SetHeader(requestid.Header, id)
+	}
+	response, err := e.client().SendWithContext(ctx, message)
 	if err != nil {
 		return err
 	}
+	span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
 	if response.StatusCode >= 400 {
 		return errors.New("failed to send email")
 	}
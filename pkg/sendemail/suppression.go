@@ -0,0 +1,77 @@
+package sendemail
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrSuppressed = errors.New("recipient is on the suppression list")
+
+// Suppression records why an address must no longer receive email.
+type Suppression struct {
+	Email     string `json:"email"`
+	EventType string `json:"event_type"` // "bounce" or "spamreport"
+	Reason    string `json:"reason"`
+	EventAt   int64  `json:"event_at"` // epoch seconds reported by SendGrid
+}
+
+type SuppressionRepository interface {
+	AddSuppression(ctx context.Context, s Suppression) error
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+}
+
+type postgresSuppressionRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresSuppressionRepository(pool *pgxpool.Pool) SuppressionRepository {
+	return &postgresSuppressionRepository{pool: pool}
+}
+
+// AddSuppression upserts an address; the latest event wins.
+func (r *postgresSuppressionRepository) AddSuppression(ctx context.Context, s Suppression) error {
+	query := `INSERT INTO email_suppressions (email, event_type, reason, event_at, created_at)
+	          VALUES ($1, $2, $3, $4, NOW())
+	          ON CONFLICT (email) DO UPDATE
+	          SET event_type = EXCLUDED.event_type, reason = EXCLUDED.reason, event_at = EXCLUDED.event_at`
+	_, err := r.pool.Exec(ctx, query, normalizeEmail(s.Email), s.EventType, s.Reason, s.EventAt)
+	return err
+}
+
+func (r *postgresSuppressionRepository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	var exists bool
+	row := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM email_suppressions WHERE email = $1)`, normalizeEmail(email))
+	if err := row.Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+type suppressingEmailService struct {
+	next EmailService
+	repo SuppressionRepository
+}
+
+// WithSuppression wraps an EmailService so that suppressed recipients are skipped
+// with ErrSuppressed instead of being sent to SendGrid.
+func WithSuppression(next EmailService, repo SuppressionRepository) EmailService {
+	return &suppressingEmailService{next: next, repo: repo}
+}
+
+func (s *suppressingEmailService) SendEmail(ctx context.Context, subject, toEmail, plainTextContent, htmlContent string) error {
+	suppressed, err := s.repo.IsSuppressed(ctx, toEmail)
+	if err != nil {
+		return err
+	}
+	if suppressed {
+		return ErrSuppressed
+	}
+	return s.next.SendEmail(ctx, subject, toEmail, plainTextContent, htmlContent)
+}
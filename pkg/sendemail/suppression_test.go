@@ -0,0 +1,36 @@
+package sendemail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingEmailService struct {
+	sentTo []string
+}
+
+func (r *recordingEmailService) SendEmail(ctx context.Context, subject, toEmail, plainTextContent, htmlContent string) error {
+	r.sentTo = append(r.sentTo, toEmail)
+	return nil
+}
+
+func TestWithSuppression_SkipsSuppressedRecipients(t *testing.T) {
+	repo := new(mockSuppressionRepository)
+	inner := &recordingEmailService{}
+	es := WithSuppression(inner, repo)
+
+	repo.On("IsSuppressed", mock.Anything, "bounced@example.com").Return(true, nil)
+	repo.On("IsSuppressed", mock.Anything, "ok@example.com").Return(false, nil)
+
+	err := es.SendEmail(context.Background(), "s", "bounced@example.com", "p", "h")
+	require.ErrorIs(t, err, ErrSuppressed)
+
+	err = es.SendEmail(context.Background(), "s", "ok@example.com", "p", "h")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"ok@example.com"}, inner.sentTo)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,29 @@
+package sendemail
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// UnsubscribeLink is the footer link rendered by the "unsubscribe" template; a nil
+// link renders nothing.
+type UnsubscribeLink struct {
+	URL   string
+	Label string
+}
+
+// RenderHTML executes an embedded email template, e.g. "otp.html". Values are
+// HTML-escaped, so user-provided titles and names are safe to pass in.
+func RenderHTML(name string, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,36 @@
+package sendemail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHTML_EscapesValues(t *testing.T) {
+	out, err := RenderHTML("notification.html", struct {
+		Subject, Body string
+		Unsubscribe   *UnsubscribeLink
+	}{"New offer", `Offer on <script>alert(1)</script>`, &UnsubscribeLink{URL: "https://x.example/u?token=a&b", Label: "Unsubscribe"}})
+
+	require.NoError(t, err)
+	require.Contains(t, out, "<h2>New offer</h2>")
+	require.Contains(t, out, "&lt;script&gt;")
+	require.NotContains(t, out, "<script>")
+	require.Contains(t, out, `<a href="https://x.example/u?token=a&amp;b">Unsubscribe</a>`)
+}
+
+func TestRenderHTML_OmitsMissingUnsubscribeLink(t *testing.T) {
+	out, err := RenderHTML("digest.html", struct {
+		Heading, Greeting, Waiting, CTA string
+		Unsubscribe                     *UnsubscribeLink
+	}{Heading: "Unread messages"})
+
+	require.NoError(t, err)
+	require.Contains(t, out, "Unread messages")
+	require.NotContains(t, out, "<a ")
+}
+
+func TestRenderHTML_UnknownTemplate(t *testing.T) {
+	_, err := RenderHTML("missing.html", nil)
+	require.Error(t, err)
+}
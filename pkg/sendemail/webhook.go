@@ -0,0 +1,154 @@
+package sendemail
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+const (
+	signatureHeader = "X-Twilio-Email-Event-Webhook-Signature"
+	timestampHeader = "X-Twilio-Email-Event-Webhook-Timestamp"
+)
+
+// webhookEvent is the subset of a SendGrid Event Webhook payload we act on.
+type webhookEvent struct {
+	Email     string `json:"email"`
+	Event     string `json:"event"`
+	Type      string `json:"type"` // "bounce" or "blocked" for bounce events
+	Reason    string `json:"reason"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type WebhookHandler struct {
+	repo      SuppressionRepository
+	publicKey *ecdsa.PublicKey // optional; when nil signatures are not checked
+}
+
+// NewWebhookHandler creates the SendGrid event webhook handler. verificationKey is the
+// base64 encoded public key from SendGrid's signed event webhook settings; pass "" to
+// disable signature verification.
+func NewWebhookHandler(repo SuppressionRepository, verificationKey string) (*WebhookHandler, error) {
+	h := &WebhookHandler{repo: repo}
+	if verificationKey == "" {
+		return h, nil
+	}
+
+	der, err := base64.StdEncoding.DecodeString(verificationKey)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	ecKey, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("sendgrid verification key is not an ECDSA key")
+	}
+	h.publicKey = ecKey
+	return h, nil
+}
+
+func (h *WebhookHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/webhooks/sendgrid", h.handleEvents)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *WebhookHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/webhooks/sendgrid",
+			Tag:         "email",
+			Summary:     "SendGrid event webhook",
+			Description: "Records hard bounces and spam complaints into the email suppression list",
+			Params: []openapi.Param{
+				{Name: signatureHeader, In: "header", Description: "ECDSA signature, required when a verification key is configured"},
+				{Name: timestampHeader, In: "header", Description: "Signature timestamp"},
+			},
+			Request: []webhookEvent{},
+			Errors:  []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError},
+		},
+	}
+}
+
+func (h *WebhookHandler) handleEvents(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		response.SendError(c, apperr.New(apperr.InvalidJSON, "invalid request payload"))
+		return
+	}
+
+	if h.publicKey != nil && !h.verifySignature(c.GetHeader(signatureHeader), c.GetHeader(timestampHeader), body) {
+		response.SendError(c, apperr.New(apperr.InvalidSignature, "invalid webhook signature"))
+		return
+	}
+
+	var events []webhookEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		response.SendError(c, apperr.New(apperr.InvalidJSON, "invalid request payload"))
+		return
+	}
+
+	recorded := 0
+	for _, ev := range events {
+		if !isSuppressionEvent(ev) {
+			continue
+		}
+		err := h.repo.AddSuppression(c.Request.Context(), Suppression{
+			Email:     ev.Email,
+			EventType: ev.Event,
+			Reason:    ev.Reason,
+			EventAt:   ev.Timestamp,
+		})
+		if err != nil {
+			log.Printf("failed to record %s for %s: %v", ev.Event, ev.Email, err)
+			response.SendError(c, apperr.Wrap(apperr.Internal, "failed to record events", err))
+			return
+		}
+		recorded++
+	}
+
+	response.SendAPIResponse(c, http.StatusOK, true, "events processed", gin.H{"recorded": recorded})
+}
+
+// isSuppressionEvent keeps hard bounces and spam complaints. Soft bounces ("blocked")
+// are transient and must not suppress the address.
+func isSuppressionEvent(ev webhookEvent) bool {
+	if ev.Email == "" {
+		return false
+	}
+	switch ev.Event {
+	case "spamreport":
+		return true
+	case "bounce":
+		return ev.Type != "blocked"
+	default:
+		return false
+	}
+}
+
+func (h *WebhookHandler) verifySignature(signature, timestamp string, body []byte) bool {
+	if signature == "" || timestamp == "" {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	digest := sha256.Sum256(append([]byte(timestamp), body...))
+	return ecdsa.VerifyASN1(h.publicKey, digest[:], sig)
+}
@@ -0,0 +1,124 @@
+package sendemail
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/response"
+)
+
+type mockSuppressionRepository struct {
+	mock.Mock
+}
+
+func (m *mockSuppressionRepository) AddSuppression(ctx context.Context, s Suppression) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *mockSuppressionRepository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	args := m.Called(ctx, email)
+	return args.Bool(0), args.Error(1)
+}
+
+func setupWebhookRouter(h *WebhookHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h.RegisterRoutes(r)
+	return r
+}
+
+func TestWebhookHandler_RecordsBouncesAndComplaints(t *testing.T) {
+	repo := new(mockSuppressionRepository)
+	h, err := NewWebhookHandler(repo, "")
+	require.NoError(t, err)
+	r := setupWebhookRouter(h)
+
+	repo.On("AddSuppression", mock.Anything, Suppression{Email: "hard@example.com", EventType: "bounce", Reason: "550 no such user", EventAt: 100}).Return(nil)
+	repo.On("AddSuppression", mock.Anything, Suppression{Email: "angry@example.com", EventType: "spamreport", EventAt: 200}).Return(nil)
+
+	body := `[
+		{"email":"hard@example.com","event":"bounce","type":"bounce","reason":"550 no such user","timestamp":100},
+		{"email":"soft@example.com","event":"bounce","type":"blocked","reason":"mailbox full","timestamp":150},
+		{"email":"angry@example.com","event":"spamreport","timestamp":200},
+		{"email":"happy@example.com","event":"delivered","timestamp":300}
+	]`
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/sendgrid", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp response.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.True(t, resp.Success)
+	data, ok := resp.Data.(map[string]any)
+	require.True(t, ok)
+	require.EqualValues(t, 2, data["recorded"])
+
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookHandler_InvalidPayload(t *testing.T) {
+	repo := new(mockSuppressionRepository)
+	h, err := NewWebhookHandler(repo, "")
+	require.NoError(t, err)
+	r := setupWebhookRouter(h)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/sendgrid", strings.NewReader(`{"not":"an array"}`))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	repo.AssertNotCalled(t, "AddSuppression", mock.Anything, mock.Anything)
+}
+
+func TestWebhookHandler_SignatureVerification(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	repo := new(mockSuppressionRepository)
+	h, err := NewWebhookHandler(repo, base64.StdEncoding.EncodeToString(der))
+	require.NoError(t, err)
+	r := setupWebhookRouter(h)
+
+	body := `[{"email":"angry@example.com","event":"spamreport","timestamp":200}]`
+	timestamp := "1700000000"
+	digest := sha256.Sum256([]byte(timestamp + body))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	// Unsigned request is rejected
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/sendgrid", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Signed request is accepted
+	repo.On("AddSuppression", mock.Anything, mock.Anything).Return(nil).Once()
+	req = httptest.NewRequest(http.MethodPost, "/webhooks/sendgrid", strings.NewReader(body))
+	req.Header.Set(signatureHeader, base64.StdEncoding.EncodeToString(sig))
+	req.Header.Set(timestampHeader, timestamp)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	repo.AssertExpectations(t)
+}
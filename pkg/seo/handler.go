@@ -0,0 +1,80 @@
+package seo
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+type SEOHandler struct {
+	service *Service
+}
+
+func NewSEOHandler(service *Service) *SEOHandler {
+	return &SEOHandler{service: service}
+}
+
+func (h *SEOHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/sitemap.xml", h.sitemap)
+	router.GET("/assets/:id/meta", h.assetMeta)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *SEOHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/sitemap.xml",
+			Tag:         "seo",
+			Summary:     "Sitemap",
+			Description: "XML sitemap of listed assets and startups, regenerated periodically",
+			Errors:      []int{http.StatusInternalServerError},
+			ContentType: "application/xml",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/assets/:id/meta",
+			Tag:         "seo",
+			Summary:     "Asset OpenGraph metadata",
+			Description: "OpenGraph tags for a listed asset, for server-side rendering of link previews",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Asset ID"),
+			},
+			Response: OpenGraph{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+	}
+}
+
+func (h *SEOHandler) sitemap(c *gin.Context) {
+	data, generatedAt, err := h.service.Sitemap(c.Request.Context())
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Header("Last-Modified", generatedAt.UTC().Format(http.TimeFormat))
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", data)
+}
+
+func (h *SEOHandler) assetMeta(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid asset id"))
+		return
+	}
+
+	og, err := h.service.AssetOpenGraph(c.Request.Context(), id)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	response.SendAPIResponse(c, http.StatusOK, true, "metadata retrieved", og)
+}
@@ -0,0 +1,59 @@
+package seo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRouter(repo SEORepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewSEOHandler(NewService(repo, "https://grveyard.example")).RegisterRoutes(r)
+	return r
+}
+
+func TestSEOHandler_Sitemap(t *testing.T) {
+	repo := new(mockSEORepository)
+	repo.On("ListSitemapEntries", mock.Anything, MaxSitemapURLs).Return([]Entry{{Kind: "assets", ID: 3, Title: "Thing", LastMod: time.Now()}}, nil)
+	r := setupRouter(repo)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	require.NotEmpty(t, w.Header().Get("Last-Modified"))
+	require.Contains(t, w.Body.String(), "https://grveyard.example/assets/3-thing")
+}
+
+func TestSEOHandler_AssetMeta(t *testing.T) {
+	repo := new(mockSEORepository)
+	repo.On("GetAssetMeta", mock.Anything, int64(5)).Return(AssetMeta{ID: 5, Title: "Thing", AssetType: "saas"}, nil)
+	repo.On("GetAssetMeta", mock.Anything, int64(6)).Return(AssetMeta{}, ErrAssetNotFound)
+	r := setupRouter(repo)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets/5/meta", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data map[string]string `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, "Thing", body.Data["og:title"])
+	require.Equal(t, "https://grveyard.example/assets/5-thing", body.Data["og:url"])
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets/6/meta", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets/abc/meta", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
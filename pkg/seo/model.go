@@ -0,0 +1,36 @@
+package seo
+
+import "time"
+
+// Entry is one public page listed in the sitemap.
+type Entry struct {
+	Kind    string // "assets" or "startups"; also the URL path prefix
+	ID      int64
+	Title   string
+	Slug    string // startups only; assets get Slug(ID, Title)
+	LastMod time.Time
+}
+
+// AssetMeta is what the OpenGraph endpoint needs to describe an asset.
+type AssetMeta struct {
+	ID          int64
+	Title       string
+	Description string
+	AssetType   string
+	ImageURL    string
+	IsSold      bool
+	UpdatedAt   time.Time
+}
+
+// OpenGraph holds the og:* (and product:*) tags for a page, keyed by property name
+// so a server-side renderer can emit <meta property=... content=...> directly.
+type OpenGraph struct {
+	Title        string `json:"og:title"`
+	Description  string `json:"og:description"`
+	Type         string `json:"og:type"`
+	URL          string `json:"og:url"`
+	Image        string `json:"og:image,omitempty"`
+	SiteName     string `json:"og:site_name"`
+	UpdatedTime  string `json:"og:updated_time"`
+	Availability string `json:"product:availability"`
+}
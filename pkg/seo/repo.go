@@ -0,0 +1,72 @@
+package seo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/apperr"
+)
+
+var ErrAssetNotFound = apperr.New(apperr.AssetNotFound, "asset not found")
+
+type SEORepository interface {
+	// ListSitemapEntries returns listed assets and non-deleted startups, most recently
+	// changed first, capped at limit.
+	ListSitemapEntries(ctx context.Context, limit int) ([]Entry, error)
+	GetAssetMeta(ctx context.Context, id int64) (AssetMeta, error)
+}
+
+type postgresSEORepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresSEORepository(pool *pgxpool.Pool) SEORepository {
+	return &postgresSEORepository{pool: pool}
+}
+
+func (r *postgresSEORepository) ListSitemapEntries(ctx context.Context, limit int) ([]Entry, error) {
+	query := `SELECT 'startups', id, name, slug, updated_at FROM startups WHERE is_deleted = false
+	          UNION ALL
+	          SELECT 'assets', id, title, '', updated_at FROM assets WHERE is_active = true AND is_deleted = false
+	          ORDER BY 5 DESC
+	          LIMIT $1`
+
+	rows, err := r.pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]Entry, 0)
+	for rows.Next() {
+		var e Entry
+		if err := rows.Scan(&e.Kind, &e.ID, &e.Title, &e.Slug, &e.LastMod); err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (r *postgresSEORepository) GetAssetMeta(ctx context.Context, id int64) (AssetMeta, error) {
+	query := `SELECT id, title, COALESCE(description, ''), asset_type, COALESCE(image_url, ''), is_sold, updated_at
+	          FROM assets
+	          WHERE id = $1 AND is_active = true AND is_deleted = false`
+
+	var m AssetMeta
+	err := r.pool.QueryRow(ctx, query, id).Scan(&m.ID, &m.Title, &m.Description, &m.AssetType, &m.ImageURL, &m.IsSold, &m.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return AssetMeta{}, ErrAssetNotFound
+		}
+		return AssetMeta{}, err
+	}
+	return m, nil
+}
@@ -0,0 +1,144 @@
+package seo
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxSitemapURLs is the sitemap protocol's per-file limit.
+const MaxSitemapURLs = 50000
+
+const (
+	siteName          = "Graveyard"
+	maxDescriptionLen = 200
+)
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// Service renders the sitemap and OpenGraph metadata for the public site at siteURL.
+// The sitemap is cached in memory; Refresh rebuilds it and is run periodically.
+type Service struct {
+	repo    SEORepository
+	siteURL string
+
+	mu          sync.RWMutex
+	sitemap     []byte
+	generatedAt time.Time
+}
+
+func NewService(repo SEORepository, siteURL string) *Service {
+	return &Service{repo: repo, siteURL: strings.TrimRight(siteURL, "/")}
+}
+
+// PageURL is the public URL of a page whose slug is derived from its id and title.
+// Startups have a stored slug instead, used when set.
+func (s *Service) PageURL(kind string, id int64, title string) string {
+	return s.siteURL + "/" + kind + "/" + Slug(id, title)
+}
+
+// Refresh rebuilds the cached sitemap.
+func (s *Service) Refresh(ctx context.Context) error {
+	entries, err := s.repo.ListSitemapEntries(ctx, MaxSitemapURLs)
+	if err != nil {
+		return err
+	}
+
+	set := urlSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9", URLs: make([]sitemapURL, 0, len(entries))}
+	for _, e := range entries {
+		loc := s.PageURL(e.Kind, e.ID, e.Title)
+		if e.Slug != "" {
+			loc = s.siteURL + "/" + e.Kind + "/" + e.Slug
+		}
+		set.URLs = append(set.URLs, sitemapURL{
+			Loc:     loc,
+			LastMod: e.LastMod.UTC().Format(time.RFC3339),
+		})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(set); err != nil {
+		return fmt.Errorf("encode sitemap: %w", err)
+	}
+
+	s.mu.Lock()
+	s.sitemap = buf.Bytes()
+	s.generatedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// Sitemap returns the cached sitemap, building it on first use.
+func (s *Service) Sitemap(ctx context.Context) ([]byte, time.Time, error) {
+	s.mu.RLock()
+	data, at := s.sitemap, s.generatedAt
+	s.mu.RUnlock()
+	if data != nil {
+		return data, at, nil
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		return nil, time.Time{}, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sitemap, s.generatedAt, nil
+}
+
+// AssetOpenGraph describes a listed asset for link previews.
+func (s *Service) AssetOpenGraph(ctx context.Context, id int64) (OpenGraph, error) {
+	m, err := s.repo.GetAssetMeta(ctx, id)
+	if err != nil {
+		return OpenGraph{}, err
+	}
+
+	description := truncate(strings.Join(strings.Fields(m.Description), " "), maxDescriptionLen)
+	if description == "" {
+		description = fmt.Sprintf("A %s asset for sale on %s.", m.AssetType, siteName)
+	}
+	availability := "in stock"
+	if m.IsSold {
+		availability = "out of stock"
+	}
+
+	return OpenGraph{
+		Title:        m.Title,
+		Description:  description,
+		Type:         "product",
+		URL:          s.PageURL("assets", m.ID, m.Title),
+		Image:        m.ImageURL,
+		SiteName:     siteName,
+		UpdatedTime:  m.UpdatedAt.UTC().Format(time.RFC3339),
+		Availability: availability,
+	}, nil
+}
+
+// truncate shortens s to at most n runes, cutting at a word boundary with an ellipsis.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)[:n-1]
+	cut := string(runes)
+	if i := strings.LastIndexByte(cut, ' '); i > n/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
@@ -0,0 +1,110 @@
+package seo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSEORepository struct {
+	mock.Mock
+}
+
+func (m *mockSEORepository) ListSitemapEntries(ctx context.Context, limit int) ([]Entry, error) {
+	args := m.Called(ctx, limit)
+	if v := args.Get(0); v != nil {
+		return v.([]Entry), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockSEORepository) GetAssetMeta(ctx context.Context, id int64) (AssetMeta, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(AssetMeta), args.Error(1)
+}
+
+func TestService_SitemapIsCachedUntilRefresh(t *testing.T) {
+	repo := new(mockSEORepository)
+	mod := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	repo.On("ListSitemapEntries", mock.Anything, MaxSitemapURLs).Return([]Entry{
+		{Kind: "startups", ID: 7, Title: "Acme CRM", Slug: "acme-crm", LastMod: mod},
+		{Kind: "assets", ID: 42, Title: "Domain & <logo>", LastMod: mod},
+	}, nil).Once()
+	svc := NewService(repo, "https://grveyard.example/")
+
+	data, _, err := svc.Sitemap(context.Background())
+	require.NoError(t, err)
+	body := string(data)
+	require.True(t, strings.HasPrefix(body, "<?xml"))
+	require.Contains(t, body, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+	require.Contains(t, body, "<loc>https://grveyard.example/startups/acme-crm</loc>")
+	require.Contains(t, body, "<loc>https://grveyard.example/assets/42-domain-logo</loc>")
+	require.Contains(t, body, "<lastmod>2026-03-04T05:06:07Z</lastmod>")
+
+	again, _, err := svc.Sitemap(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, data, again)
+	repo.AssertNumberOfCalls(t, "ListSitemapEntries", 1)
+
+	repo.On("ListSitemapEntries", mock.Anything, MaxSitemapURLs).Return([]Entry{}, nil).Once()
+	require.NoError(t, svc.Refresh(context.Background()))
+	data, _, err = svc.Sitemap(context.Background())
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "<url>")
+}
+
+func TestService_RefreshErrorKeepsPreviousSitemap(t *testing.T) {
+	repo := new(mockSEORepository)
+	repo.On("ListSitemapEntries", mock.Anything, MaxSitemapURLs).Return([]Entry{{Kind: "assets", ID: 1, Title: "x", LastMod: time.Now()}}, nil).Once()
+	repo.On("ListSitemapEntries", mock.Anything, MaxSitemapURLs).Return(nil, errors.New("db down")).Once()
+	svc := NewService(repo, "https://grveyard.example")
+
+	first, _, err := svc.Sitemap(context.Background())
+	require.NoError(t, err)
+	require.Error(t, svc.Refresh(context.Background()))
+
+	data, _, err := svc.Sitemap(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, first, data)
+}
+
+func TestService_AssetOpenGraph(t *testing.T) {
+	repo := new(mockSEORepository)
+	repo.On("GetAssetMeta", mock.Anything, int64(42)).Return(AssetMeta{
+		ID:          42,
+		Title:       "Acme CRM",
+		Description: strings.Repeat("word ", 100),
+		AssetType:   "saas",
+		ImageURL:    "https://cdn.example/acme.png",
+		IsSold:      true,
+		UpdatedAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, nil)
+	svc := NewService(repo, "https://grveyard.example")
+
+	og, err := svc.AssetOpenGraph(context.Background(), 42)
+	require.NoError(t, err)
+	require.Equal(t, "Acme CRM", og.Title)
+	require.Equal(t, "product", og.Type)
+	require.Equal(t, "https://grveyard.example/assets/42-acme-crm", og.URL)
+	require.Equal(t, "https://cdn.example/acme.png", og.Image)
+	require.Equal(t, "out of stock", og.Availability)
+	require.Equal(t, "2026-01-02T03:04:05Z", og.UpdatedTime)
+	require.LessOrEqual(t, len([]rune(og.Description)), maxDescriptionLen)
+	require.True(t, strings.HasSuffix(og.Description, "word…"))
+}
+
+func TestService_AssetOpenGraphDefaultsDescription(t *testing.T) {
+	repo := new(mockSEORepository)
+	repo.On("GetAssetMeta", mock.Anything, int64(1)).Return(AssetMeta{ID: 1, Title: "Logo", AssetType: "design"}, nil)
+	svc := NewService(repo, "https://grveyard.example")
+
+	og, err := svc.AssetOpenGraph(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "A design asset for sale on Graveyard.", og.Description)
+	require.Equal(t, "in stock", og.Availability)
+}
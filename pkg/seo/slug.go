@@ -0,0 +1,49 @@
+package seo
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const maxSlugLen = 60
+
+// Slug builds the public path segment for a record, e.g. 42 "Acme CRM!" -> "42-acme-crm".
+// The id prefix keeps slugs unique and lets the frontend resolve them without a lookup
+// table; renaming a record changes only the cosmetic suffix.
+func Slug(id int64, title string) string {
+	s := Slugify(title)
+	if s == "" {
+		return strconv.FormatInt(id, 10)
+	}
+	return strconv.FormatInt(id, 10) + "-" + s
+}
+
+// Slugify lower-cases title, strips accents and joins ASCII alphanumeric runs with
+// hyphens. Scripts without an ASCII decomposition are dropped, so the result may be
+// empty.
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFKD.String(title) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// combining mark left over from decomposing an accented letter
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			sep := dash && b.Len() > 0
+			if sep && b.Len()+2 > maxSlugLen || b.Len()+1 > maxSlugLen {
+				return b.String()
+			}
+			if sep {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
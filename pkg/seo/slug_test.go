@@ -0,0 +1,31 @@
+package seo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		id    int64
+		title string
+		want  string
+	}{
+		{42, "Acme CRM!", "42-acme-crm"},
+		{7, "  Café -- Déjà Vu  ", "7-cafe-deja-vu"},
+		{3, "100% AI_powered", "3-100-ai-powered"},
+		{9, "स्टार्टअप", "9"},
+		{5, "", "5"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, Slug(tt.id, tt.title), tt.title)
+	}
+}
+
+func TestSlug_Truncates(t *testing.T) {
+	s := Slug(1, strings.Repeat("word ", 40))
+	require.LessOrEqual(t, len(s), len("1-")+maxSlugLen)
+	require.False(t, strings.HasSuffix(s, "-"))
+}
@@ -0,0 +1,137 @@
+package startups
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/response"
+)
+
+// Category is an industry in the fixed taxonomy startups are tagged with, e.g.
+// fintech. New categories are added in db/schema.sql.
+type Category struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+type CategoryRepository interface {
+	ListCategories(ctx context.Context) ([]Category, error)
+	// ListStartupCategories returns the categories the startup is tagged with, by name.
+	ListStartupCategories(ctx context.Context, startupID int64) ([]Category, error)
+	// SetStartupCategories replaces the startup's categories with slugs.
+	SetStartupCategories(ctx context.Context, startupID int64, slugs []string) error
+}
+
+// CategoryService tags startups with industries so GET /startups can filter on them.
+type CategoryService struct {
+	repo     CategoryRepository
+	startups StartupRepository
+}
+
+func NewCategoryService(repo CategoryRepository, startups StartupRepository) *CategoryService {
+	return &CategoryService{repo: repo, startups: startups}
+}
+
+func (s *CategoryService) ListCategories(ctx context.Context) ([]Category, error) {
+	return s.repo.ListCategories(ctx)
+}
+
+func (s *CategoryService) StartupCategories(ctx context.Context, startupID int64) ([]Category, error) {
+	if _, err := s.startups.GetStartupByID(ctx, startupID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStartupCategories(ctx, startupID)
+}
+
+// SetStartupCategories replaces the categories of a startup owned by userUUID. Slugs
+// are case-insensitive and duplicates are ignored.
+func (s *CategoryService) SetStartupCategories(ctx context.Context, startupID int64, userUUID string, slugs []string) ([]Category, error) {
+	st, err := s.startups.GetStartupByID(ctx, startupID)
+	if err != nil {
+		return nil, err
+	}
+	if st.OwnerUUID != userUUID {
+		return nil, ErrNotOwner
+	}
+
+	known, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	normalized := make([]string, 0, len(slugs))
+	for _, slug := range slugs {
+		slug = strings.ToLower(strings.TrimSpace(slug))
+		if !slices.ContainsFunc(known, func(c Category) bool { return c.Slug == slug }) {
+			return nil, response.InvalidField(apperr.UnknownCategory, "categories", "oneof", "unknown category "+slug)
+		}
+		if !slices.Contains(normalized, slug) {
+			normalized = append(normalized, slug)
+		}
+	}
+
+	if err := s.repo.SetStartupCategories(ctx, startupID, normalized); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStartupCategories(ctx, startupID)
+}
+
+type postgresCategoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresCategoryRepository(pool *pgxpool.Pool) CategoryRepository {
+	return &postgresCategoryRepository{pool: pool}
+}
+
+func (r *postgresCategoryRepository) ListCategories(ctx context.Context) ([]Category, error) {
+	return r.query(ctx, `SELECT slug, name FROM categories ORDER BY name`)
+}
+
+func (r *postgresCategoryRepository) ListStartupCategories(ctx context.Context, startupID int64) ([]Category, error) {
+	return r.query(ctx, `
+		SELECT c.slug, c.name
+		FROM startup_categories sc
+		JOIN categories c ON c.slug = sc.category_slug
+		WHERE sc.startup_id = $1
+		ORDER BY c.name`, startupID)
+}
+
+func (r *postgresCategoryRepository) SetStartupCategories(ctx context.Context, startupID int64, slugs []string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM startup_categories WHERE startup_id = $1`, startupID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO startup_categories (startup_id, category_slug)
+		SELECT $1, slug FROM unnest($2::text[]) AS slug`, startupID, slugs); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *postgresCategoryRepository) query(ctx context.Context, sql string, args ...any) ([]Category, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Category{}
+	for rows.Next() {
+		var c Category
+		if err := rows.Scan(&c.Slug, &c.Name); err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,113 @@
+package startups
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+type setCategoriesRequest struct {
+	Categories []string `json:"categories" binding:"required,max=5,dive,max=50"` // slugs; [] clears them
+}
+
+type CategoryHandler struct {
+	service *CategoryService
+}
+
+func NewCategoryHandler(service *CategoryService) *CategoryHandler {
+	return &CategoryHandler{service: service}
+}
+
+func (h *CategoryHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/categories", h.listCategories)
+	router.GET("/startups/:id/categories", h.getStartupCategories)
+	router.PUT("/startups/:id/categories", auth.Required(), h.setStartupCategories)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *CategoryHandler) Operations() []openapi.Operation {
+	params := []openapi.Param{openapi.Path("id", "integer", "Startup ID")}
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/categories",
+			Tag:         "startups",
+			Summary:     "List categories",
+			Description: "The industries startups can be tagged with. Filter GET /startups on one with category=<slug>.",
+			Response:    []Category{},
+			Errors:      []int{http.StatusInternalServerError},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/startups/:id/categories",
+			Tag:      "startups",
+			Summary:  "Get a startup's categories",
+			Params:   params,
+			Response: []Category{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/startups/:id/categories",
+			Tag:         "startups",
+			Summary:     "Set a startup's categories",
+			Description: "Replaces the startup's categories with up to five slugs from GET /categories. Only its owner may change them.",
+			Params:      params,
+			Request:     setCategoriesRequest{},
+			Response:    []Category{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+	}
+}
+
+func (h *CategoryHandler) listCategories(c *gin.Context) {
+	list, err := h.service.ListCategories(c.Request.Context())
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "categories listed", list)
+}
+
+func (h *CategoryHandler) getStartupCategories(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
+		return
+	}
+
+	list, err := h.service.StartupCategories(c.Request.Context(), id)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "categories listed", list)
+}
+
+func (h *CategoryHandler) setStartupCategories(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
+		return
+	}
+
+	var req setCategoriesRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	list, err := h.service.SetStartupCategories(c.Request.Context(), id, auth.UserID(c), req.Categories)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "categories updated", list)
+}
@@ -0,0 +1,93 @@
+package startups
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/response"
+	"grveyard/pkg/testhelpers"
+)
+
+type fakeCategoryRepository struct {
+	tags map[int64][]string
+}
+
+func (f *fakeCategoryRepository) ListCategories(context.Context) ([]Category, error) {
+	return []Category{{Slug: "fintech", Name: "Fintech"}, {Slug: "saas", Name: "SaaS"}}, nil
+}
+
+func (f *fakeCategoryRepository) ListStartupCategories(_ context.Context, startupID int64) ([]Category, error) {
+	list := []Category{}
+	for _, slug := range f.tags[startupID] {
+		list = append(list, Category{Slug: slug})
+	}
+	return list, nil
+}
+
+func (f *fakeCategoryRepository) SetStartupCategories(_ context.Context, startupID int64, slugs []string) error {
+	f.tags[startupID] = slugs
+	return nil
+}
+
+func TestCategoryService_SetStartupCategories(t *testing.T) {
+	repo := &fakeCategoryRepository{tags: map[int64][]string{}}
+	startups := new(mockStartupRepository)
+	startups.On("GetStartupByID", mock.Anything, int64(1)).Return(Startup{ID: 1, OwnerUUID: "owner"}, nil)
+	startups.On("GetStartupByID", mock.Anything, int64(2)).Return(Startup{}, ErrStartupNotFound)
+	svc := NewCategoryService(repo, startups)
+	ctx := context.Background()
+
+	list, err := svc.SetStartupCategories(ctx, 1, "owner", []string{"SaaS", "fintech", "saas"})
+	require.NoError(t, err)
+	require.Equal(t, []Category{{Slug: "saas"}, {Slug: "fintech"}}, list)
+
+	_, err = svc.SetStartupCategories(ctx, 1, "owner", []string{"fintech", "pets"})
+	var appErr *apperr.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperr.UnknownCategory, appErr.Code)
+	require.Equal(t, []string{"saas", "fintech"}, repo.tags[1], "a rejected update changes nothing")
+
+	_, err = svc.SetStartupCategories(ctx, 1, "someone-else", nil)
+	require.ErrorIs(t, err, ErrNotOwner)
+	_, err = svc.SetStartupCategories(ctx, 2, "owner", nil)
+	require.ErrorIs(t, err, ErrStartupNotFound)
+}
+
+func TestCategoryHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &fakeCategoryRepository{tags: map[int64][]string{}}
+	startups := new(mockStartupRepository)
+	startups.On("GetStartupByID", mock.Anything, int64(1)).Return(Startup{ID: 1, OwnerUUID: "user-uuid-1"}, nil)
+	r := gin.New()
+	r.Use(testhelpers.AuthAs("user-uuid-1", "founder"))
+	NewCategoryHandler(NewCategoryService(repo, startups)).RegisterRoutes(r)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPut, "/startups/1/categories", `{"categories":["fintech"]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp response.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "categories updated", resp.Message)
+	require.Equal(t, []string{"fintech"}, repo.tags[1])
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/startups/1/categories", `{"categories":["a","b","c","d","e","f"]}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/startups/1/categories", `{}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/startups/abc/categories", "").Code)
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/categories", "").Code)
+}
@@ -2,21 +2,38 @@ package startups
 
 import (
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"grveyard/pkg/admin"
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/etag"
+	"grveyard/pkg/geo"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
 	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
 )
 
 type StartupHandler struct {
 	service StartupService
+	views   ViewRecorder // optional
 }
 
 func NewStartupHandler(service StartupService) *StartupHandler {
 	return &StartupHandler{service: service}
 }
 
+// SetViews counts every fetch of a single startup as a view.
+func (h *StartupHandler) SetViews(views ViewRecorder) {
+	h.views = views
+}
+
 func isValidStatus(status string) bool {
 	if status == "" {
 		return true
@@ -30,246 +47,549 @@ func isValidStatus(status string) bool {
 }
 
 func (h *StartupHandler) RegisterRoutes(router *gin.Engine) {
-	router.POST("/startups", h.createStartup)
-	router.PUT("/startups/:id", h.updateStartup)
-	router.DELETE("/startups/:id", h.deleteStartup)
-	router.DELETE("/startups", h.deleteAllStartups)
-	router.GET("/startups", h.listStartups)
-	router.GET("/startups/user/:uuid", h.ListStartupsByUser)
-	router.GET("/startups/:id", h.getStartupByID)
+	router.POST("/startups", auth.Required(), h.createStartup)
+	router.PUT("/startups/:id", auth.Required(), h.updateStartup)
+	router.DELETE("/startups/:id", auth.Required(), h.deleteStartup)
+	router.POST("/startups/:id/restore", auth.Required(), h.restoreStartup)
+	router.GET("/startups", etag.Middleware(), h.listStartups)
+	router.GET("/startups/search", h.searchStartups)
+	router.GET("/startups/user/:uuid", etag.Middleware(), h.ListStartupsByUser)
+	router.GET("/startups/slug/:slug", etag.Middleware(), h.getStartupBySlug)
+	router.GET("/startups/:id", etag.Middleware(), h.getStartupByID)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *StartupHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/startups",
+			Tag:         "startups",
+			Summary:     "Create a new startup",
+			Description: "Creates a new startup with the provided details. The owner must have verified their email within the last 30 days (VERIFICATION_REQUIRED otherwise)",
+			Request:     createStartupRequest{},
+			Response:    Startup{},
+			Status:      http.StatusCreated,
+			Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/startups/:id",
+			Tag:         "startups",
+			Summary:     "Update a startup",
+			Description: "Replaces an existing startup's details, post-mortem included, so fields left out are cleared. Its owner and editors from its team may update it.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Startup ID"),
+			},
+			Request:  updateStartupRequest{},
+			Response: Startup{},
+			Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/startups/:id",
+			Tag:         "startups",
+			Summary:     "Delete a startup",
+			Description: "Deletes a startup by ID. Owners may delete their own startups and admins any; editors cannot. POST /startups/:id/restore undoes it.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Startup ID"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:   true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/startups/:id/restore",
+			Tag:         "startups",
+			Summary:     "Restore a deleted startup",
+			Description: "Undoes a delete. Owners may restore their own startups, admins any. Startups hidden because their owner was banned come back on unban instead and answer 404 here, as do ones already purged.",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Startup ID"),
+			},
+			Response: Startup{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/startups/:id",
+			Tag:         "startups",
+			Summary:     "Get startup by ID",
+			Description: "Retrieves a single startup by its ID",
+			Params: []openapi.Param{
+				openapi.Path("id", "integer", "Startup ID"),
+			},
+			Response: Startup{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/startups/slug/:slug",
+			Tag:         "startups",
+			Summary:     "Get startup by slug",
+			Description: "Retrieves a single startup by the URL slug generated from its name at creation, for detail pages that should not expose the numeric ID",
+			Params: []openapi.Param{
+				openapi.Path("slug", "string", "Startup slug, e.g. acme-crm"),
+			},
+			Response: Startup{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/startups",
+			Tag:         "startups",
+			Summary:     "List all startups",
+			Description: "Retrieves a paginated list of all startups with optional filters, in id order unless sort is given. Signed-in callers do not see startups of founders who blocked them",
+			Params: []openapi.Param{
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 10)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+				{Name: "status", In: "query", Type: "string", Description: "Filter by status", Enum: []string{"active", "failed", "sold"}},
+				openapi.Query("owner_uuid", "string", "Filter by founder UUID", false),
+				openapi.Query("category", "string", "Filter by category slug from GET /categories, e.g. fintech", false),
+				openapi.Query("verified", "boolean", "Only startups with (true) or without (false) the verified badge", false),
+				openapi.Query("created_after", "string", "Only startups created at or after this instant, RFC 3339 or YYYY-MM-DD", false),
+				openapi.Query("created_before", "string", "Only startups created before this instant, RFC 3339 or YYYY-MM-DD", false),
+				openapi.Query("country", "string", "Filter by ISO 3166-1 alpha-2 country, e.g. IN", false),
+				openapi.Query("region", "string", "Filter by ISO 3166-2 region, e.g. IN-MH", false),
+				{Name: "sort", In: "query", Type: "string", Description: "newest, name (A-Z) or most_assets (founders with the most listed assets first)", Enum: StartupSorts},
+			},
+			Response: response.Paginated[Startup]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/startups/search",
+			Tag:         "startups",
+			Summary:     "Search startups",
+			Description: "Full-text search over startup names and descriptions. Every word matches as a prefix; matches in the name rank first. Without a search engine configured, each result carries a highlight excerpt with matches in <mark> tags.",
+			Params: []openapi.Param{
+				openapi.Query("q", "string", "Search terms", true),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 10)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[Startup]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/startups/user/:uuid",
+			Tag:         "startups",
+			Summary:     "Get startups by UUID",
+			Description: "Retrieves startups by user's UUID",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "user UUID"),
+			},
+			Response: response.Paginated[Startup]{},
+			Errors:   []int{http.StatusInternalServerError},
+		},
+	}
 }
 
 type createStartupRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
-	LogoURL     string `json:"logo_url"`
-	OwnerUUID   string `json:"owner_uuid" binding:"required"`
+	Name        string `json:"name" binding:"required,max=200"`
+	Description string `json:"description" binding:"max=5000"`
+	LogoURL     string `json:"logo_url" binding:"max=2048"`
+	OwnerUUID   string `json:"owner_uuid" binding:"required,max=64"`
 	Status      string `json:"status"`
+	Country     string `json:"country" binding:"max=2"`
+	Region      string `json:"region" binding:"max=6"`
+	// Post-mortem
+	FailureReason   string   `json:"failure_reason" binding:"max=5000"`
+	LifespanMonths  *int32   `json:"lifespan_months" binding:"omitempty,min=0,max=1200"`
+	PeakMRR         *float64 `json:"peak_mrr" binding:"omitempty,min=0,max=9999999999"` // USD
+	UsersAtShutdown *int64   `json:"users_at_shutdown" binding:"omitempty,min=0"`
 }
 
 type updateStartupRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
-	LogoURL     string `json:"logo_url"`
+	Name        string `json:"name" binding:"required,max=200"`
+	Description string `json:"description" binding:"max=5000"`
+	LogoURL     string `json:"logo_url" binding:"max=2048"`
 	Status      string `json:"status"`
+	Country     string `json:"country" binding:"max=2"`
+	Region      string `json:"region" binding:"max=6"`
+	// Post-mortem
+	FailureReason   string   `json:"failure_reason" binding:"max=5000"`
+	LifespanMonths  *int32   `json:"lifespan_months" binding:"omitempty,min=0,max=1200"`
+	PeakMRR         *float64 `json:"peak_mrr" binding:"omitempty,min=0,max=9999999999"` // USD
+	UsersAtShutdown *int64   `json:"users_at_shutdown" binding:"omitempty,min=0"`
 }
 
-// @Summary      Create a new startup
-// @Description  Creates a new startup with the provided details
-// @Tags         startups
-// @Accept       json
-// @Produce      json
-// @Param        request body createStartupRequest true "Startup creation request"
-// @Success      201  {object}  response.APIResponse{data=Startup} "Startup created successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid request payload"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /startups [post]
 func (h *StartupHandler) createStartup(c *gin.Context) {
 	var req createStartupRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid request payload", nil)
+	if !validation.BindJSON(c, &req) {
 		return
 	}
 
 	if req.OwnerUUID == "" {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "owner_uuid must be provided", nil)
+		response.SendError(c, response.InvalidField(apperr.ValidationFailed, "owner_uuid", "required", "owner_uuid must be provided"))
 		return
 	}
 
 	if !isValidStatus(req.Status) {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid status", nil)
+		response.SendError(c, response.InvalidField(apperr.InvalidStartupStatus, "status", "oneof", "invalid status"))
 		return
 	}
 
 	startup, err := h.service.CreateStartup(c.Request.Context(), Startup{
-		Name:        req.Name,
-		Description: req.Description,
-		LogoURL:     req.LogoURL,
-		OwnerUUID:   req.OwnerUUID,
-		Status:      req.Status,
+		Name:            req.Name,
+		Description:     req.Description,
+		LogoURL:         req.LogoURL,
+		OwnerUUID:       req.OwnerUUID,
+		Status:          req.Status,
+		Country:         req.Country,
+		Region:          req.Region,
+		FailureReason:   req.FailureReason,
+		LifespanMonths:  req.LifespanMonths,
+		PeakMRR:         req.PeakMRR,
+		UsersAtShutdown: req.UsersAtShutdown,
 	})
 	if err != nil {
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
 
 	response.SendAPIResponse(c, http.StatusCreated, true, "startup created", startup)
 }
 
-// @Summary      Update a startup
-// @Description  Updates an existing startup's details
-// @Tags         startups
-// @Accept       json
-// @Produce      json
-// @Param        id   path      int  true  "Startup ID"
-// @Param        request body updateStartupRequest true "Startup update request"
-// @Success      200  {object}  response.APIResponse{data=Startup} "Startup updated successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid request"
-// @Failure      404  {object}  response.APIResponse "Startup not found"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /startups/{id} [put]
 func (h *StartupHandler) updateStartup(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || id <= 0 {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid startup id", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
 		return
 	}
 
 	var req updateStartupRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid request payload", nil)
+	if !validation.BindJSON(c, &req) {
 		return
 	}
 
 	if !isValidStatus(req.Status) {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid status", nil)
+		response.SendError(c, response.InvalidField(apperr.InvalidStartupStatus, "status", "oneof", "invalid status"))
 		return
 	}
 
 	startup, err := h.service.UpdateStartup(c.Request.Context(), Startup{
-		ID:          id,
-		Name:        req.Name,
-		Description: req.Description,
-		LogoURL:     req.LogoURL,
-		Status:      req.Status,
-	})
+		ID:              id,
+		Name:            req.Name,
+		Description:     req.Description,
+		LogoURL:         req.LogoURL,
+		Status:          req.Status,
+		Country:         req.Country,
+		Region:          req.Region,
+		FailureReason:   req.FailureReason,
+		LifespanMonths:  req.LifespanMonths,
+		PeakMRR:         req.PeakMRR,
+		UsersAtShutdown: req.UsersAtShutdown,
+	}, auth.UserID(c))
 	if err != nil {
-		if err == ErrStartupNotFound {
-			response.SendAPIResponse(c, http.StatusNotFound, false, "startup not found", nil)
-			return
-		}
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
 
 	response.SendAPIResponse(c, http.StatusOK, true, "startup updated", startup)
 }
 
-// @Summary      Delete a startup
-// @Description  Deletes a startup by ID
-// @Tags         startups
-// @Produce      json
-// @Param        id   path      int  true  "Startup ID"
-// @Success      200  {object}  response.APIResponse "Startup deleted successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid startup ID"
-// @Failure      404  {object}  response.APIResponse "Startup not found"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /startups/{id} [delete]
 func (h *StartupHandler) deleteStartup(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || id <= 0 {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid startup id", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
 		return
 	}
 
-	if err := h.service.DeleteStartup(c.Request.Context(), id); err != nil {
-		if err == ErrStartupNotFound {
-			response.SendAPIResponse(c, http.StatusNotFound, false, "startup not found", nil)
-			return
-		}
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+	anyOwner := auth.Can(auth.Role(c), auth.PermDeleteListings)
+	if err := h.service.DeleteStartup(c.Request.Context(), id, auth.UserID(c), anyOwner); err != nil {
+		response.SendError(c, err)
 		return
 	}
 
 	response.SendAPIResponse(c, http.StatusOK, true, "startup deleted", nil)
 }
 
-// @Summary      Get startup by ID
-// @Description  Retrieves a single startup by its ID
-// @Tags         startups
-// @Produce      json
-// @Param        id   path      int  true  "Startup ID"
-// @Success      200  {object}  response.APIResponse{data=Startup} "Startup retrieved successfully"
-// @Failure      400  {object}  response.APIResponse "Invalid startup ID"
-// @Failure      404  {object}  response.APIResponse "Startup not found"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /startups/{id} [get]
+func (h *StartupHandler) restoreStartup(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
+		return
+	}
+
+	anyOwner := auth.Can(auth.Role(c), auth.PermDeleteListings)
+	startup, err := h.service.RestoreStartup(c.Request.Context(), id, auth.UserID(c), anyOwner)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+
+	response.SendAPIResponse(c, http.StatusOK, true, "startup restored", startup)
+}
+
 func (h *StartupHandler) getStartupByID(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || id <= 0 {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid startup id", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
 		return
 	}
 
 	startup, err := h.service.GetStartupByID(c.Request.Context(), id)
 	if err != nil {
-		if err == ErrStartupNotFound {
-			response.SendAPIResponse(c, http.StatusNotFound, false, "startup not found", nil)
-			return
-		}
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
+	if h.views != nil {
+		h.views.Record(c, startup.ID)
+	}
+
+	response.SendAPIResponse(c, http.StatusOK, true, "startup fetched", startup)
+}
+
+func (h *StartupHandler) getStartupBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	if len(slug) > 80 {
+		response.SendError(c, apperr.New(apperr.InvalidRequest, "invalid startup slug"))
+		return
+	}
+
+	startup, err := h.service.GetStartupBySlug(c.Request.Context(), slug)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	if h.views != nil {
+		h.views.Record(c, startup.ID)
+	}
 
 	response.SendAPIResponse(c, http.StatusOK, true, "startup fetched", startup)
 }
 
-// @Summary      List all startups
-// @Description  Retrieves a paginated list of all startups
-// @Tags         startups
-// @Produce      json
-// @Param        page   query     int  false  "Page number" default(1)
-// @Param        limit  query     int  false  "Items per page" default(10)
-// @Success      200  {object}  response.APIResponse{data=StartupList} "Startups retrieved successfully"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /startups [get]
 func (h *StartupHandler) listStartups(c *gin.Context) {
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
+	p, err := pagination.FromRequest(c, pagination.DefaultLimit)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
 	}
 
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if err != nil || limit <= 0 {
-		limit = 10
+	filters := StartupFilters{Viewer: auth.UserID(c)}
+	if status := c.Query("status"); status != "" {
+		if !isValidStatus(status) {
+			response.SendError(c, response.InvalidField(apperr.InvalidRequest, "status", "oneof", "invalid status"))
+			return
+		}
+		filters.Status = &status
 	}
-	if limit > 100 {
-		limit = 100
+	if owner := strings.TrimSpace(c.Query("owner_uuid")); owner != "" {
+		filters.OwnerUUID = &owner
+	}
+	if category := strings.ToLower(strings.TrimSpace(c.Query("category"))); category != "" {
+		filters.Category = &category
+	}
+	if raw := c.Query("verified"); raw != "" {
+		verified, err := strconv.ParseBool(raw)
+		if err != nil {
+			response.SendError(c, response.InvalidField(apperr.InvalidRequest, "verified", "boolean", "invalid verified, expected true or false"))
+			return
+		}
+		filters.Verified = &verified
+	}
+	for _, f := range []struct {
+		field string
+		to    **time.Time
+	}{
+		{"created_after", &filters.CreatedAfter},
+		{"created_before", &filters.CreatedBefore},
+	} {
+		v := c.Query(f.field)
+		if v == "" {
+			continue
+		}
+		t, err := parseInstant(v)
+		if err != nil {
+			response.SendError(c, response.InvalidField(apperr.InvalidRequest, f.field, "datetime", "invalid "+f.field+", expected RFC 3339 or YYYY-MM-DD"))
+			return
+		}
+		*f.to = &t
+	}
+	if sort := c.Query("sort"); sort != "" {
+		if !slices.Contains(StartupSorts, sort) {
+			response.SendError(c, response.InvalidField(apperr.InvalidRequest, "sort", "oneof", "sort must be one of newest, name or most_assets"))
+			return
+		}
+		filters.Sort = StartupSort(sort)
 	}
 
-	startupsList, total, err := h.service.ListStartups(c.Request.Context(), page, limit)
+	country, region, err := geo.Normalize(c.Query("country"), c.Query("region"))
 	if err != nil {
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
+	if country != "" {
+		filters.Country = &country
+	}
+	if region != "" {
+		filters.Region = &region
+	}
 
-	data := StartupList{Items: startupsList, Total: total, Page: page, Limit: limit}
-	response.SendAPIResponse(c, http.StatusOK, true, "startups listed", data)
+	startupsList, total, err := h.service.ListStartups(c.Request.Context(), filters, p.Page, p.Limit)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+
+	response.SendPage(c, "startups listed", startupsList, total, p)
 }
 
-// @Summary      Delete all startups
-// @Description  Soft deletes all startups by setting is_deleted to true
-// @Tags         startups
-// @Produce      json
-// @Success      200  {object}  response.APIResponse "All startups deleted successfully"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /startups [delete]
-func (h *StartupHandler) deleteAllStartups(c *gin.Context) {
-	if err := h.service.DeleteAllStartups(c.Request.Context()); err != nil {
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+// parseInstant reads an RFC 3339 timestamp or a YYYY-MM-DD date (midnight UTC).
+func parseInstant(v string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, v)
+	}
+	return t, err
+}
+
+func (h *StartupHandler) searchStartups(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" || len(q) > 200 {
+		response.SendError(c, response.InvalidField(apperr.InvalidRequest, "q", "len", "q must be between 1 and 200 characters"))
+		return
+	}
+
+	p, err := pagination.FromRequest(c, pagination.DefaultLimit)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
 		return
 	}
 
-	response.SendAPIResponse(c, http.StatusOK, true, "all startups deleted", nil)
+	startupsList, total, err := h.service.SearchStartups(c.Request.Context(), q, p.Page, p.Limit)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+
+	response.SendPage(c, "startups found", startupsList, total, p)
 }
 
-// @Summary      Get startups by UUID
-// @Description  Retrieves startups by user's UUID
-// @Tags         startups
-// @Produce      json
-// @Param        uuid   path      string  true  "user UUID"
-// @Success      200  {object}  response.APIResponse{data=StartupList} "Startups retrieved successfully"
-// @Failure      500  {object}  response.APIResponse "Internal server error"
-// @Router       /startups/user/{uuid} [get]
 func (h *StartupHandler) ListStartupsByUser(c *gin.Context) {
 	uuid := c.Param("uuid")
 
 	startups, err := h.service.ListStartupsByUser(c.Request.Context(), uuid)
 	if err != nil {
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
+		return
+	}
+
+	if startups == nil {
+		startups = []Startup{}
+	}
+	data := response.Paginated[Startup]{Items: startups, Total: int64(len(startups)), Page: 1, Limit: len(startups)}
+	response.SendAPIResponse(c, http.StatusOK, true, "startup fetched by uuid", data)
+}
+
+// BulkHandler serves the filtered bulk operations on startups under /admin.
+type BulkHandler struct {
+	service StartupService
+	token   string
+}
+
+// NewBulkHandler serves the bulk routes to callers presenting token as a bearer token,
+// like the other admin endpoints.
+func NewBulkHandler(service StartupService, token string) *BulkHandler {
+	return &BulkHandler{service: service, token: token}
+}
+
+func (h *BulkHandler) RegisterRoutes(router *gin.Engine) {
+	group := router.Group("/admin/startups", admin.RequireToken(h.token))
+	group.DELETE("", h.bulkDelete)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *BulkHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodDelete,
+			Path:        "/admin/startups",
+			Tag:         "admin",
+			Summary:     "Bulk delete startups",
+			Description: "Soft deletes the startups matching the filters; at least one filter is required. With dry_run=true only the matching count is returned",
+			Params:      admin.BulkParams("active", "failed", "sold"),
+			Response:    admin.BulkResult{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError},
+			Auth:        true,
+		},
+	}
+}
+
+func (h *BulkHandler) bulkDelete(c *gin.Context) {
+	filter, dryRun, err := admin.ParseBulkFilter(c, "active", "failed", "sold")
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+
+	result, err := h.service.BulkDeleteStartups(c.Request.Context(), filter, dryRun)
+	if err != nil {
+		response.SendError(c, err)
 		return
 	}
 
-	StartupList := StartupList{Items: startups, Total: int64(len(startups))}
-	response.SendAPIResponse(c, http.StatusOK, true, "startup fetched by uuid", StartupList)
+	msg := "startups deleted"
+	if dryRun {
+		msg = "bulk delete previewed"
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, msg, result)
+}
+
+// DeletedHandler lists soft-deleted startups under /admin for review before restoring.
+type DeletedHandler struct {
+	service StartupService
+	token   string
+}
+
+// NewDeletedHandler serves the listing to callers presenting token as a bearer token,
+// like the other admin endpoints, and to admins signed in with their own access token.
+func NewDeletedHandler(service StartupService, token string) *DeletedHandler {
+	return &DeletedHandler{service: service, token: token}
+}
+
+func (h *DeletedHandler) RegisterRoutes(router *gin.Engine) {
+	group := router.Group("/admin/startups", admin.RequireTokenOrPermission(h.token, auth.PermDeleteListings))
+	group.GET("/deleted", h.listDeleted)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *DeletedHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/admin/startups/deleted",
+			Tag:         "admin",
+			Summary:     "List deleted startups",
+			Description: "Soft-deleted startups that POST /startups/:id/restore can bring back, most recently deleted first. Startups hidden by a ban are left out.",
+			Params: []openapi.Param{
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 10)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[DeletedStartup]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+func (h *DeletedHandler) listDeleted(c *gin.Context) {
+	p, err := pagination.FromRequest(c, pagination.DefaultLimit)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	list, total, err := h.service.ListDeletedStartups(c.Request.Context(), p.Page, p.Limit)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "deleted startups listed", list, total, p)
 }
@@ -7,12 +7,16 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"grveyard/pkg/admin"
+	"grveyard/pkg/auth"
 	"grveyard/pkg/response"
+	"grveyard/pkg/testhelpers"
 )
 
 type mockStartupService struct {
@@ -25,32 +29,56 @@ func (m *mockStartupService) CreateStartup(ctx context.Context, input Startup) (
 	return startup, args.Error(1)
 }
 
-func (m *mockStartupService) UpdateStartup(ctx context.Context, input Startup) (Startup, error) {
-	args := m.Called(ctx, input)
+func (m *mockStartupService) UpdateStartup(ctx context.Context, input Startup, userUUID string) (Startup, error) {
+	args := m.Called(ctx, input, userUUID)
 	startup, _ := args.Get(0).(Startup)
 	return startup, args.Error(1)
 }
 
-func (m *mockStartupService) DeleteStartup(ctx context.Context, id int64) error {
-	args := m.Called(ctx, id)
+func (m *mockStartupService) DeleteStartup(ctx context.Context, id int64, userUUID string, anyOwner bool) error {
+	args := m.Called(ctx, id, userUUID, anyOwner)
 	return args.Error(0)
 }
 
+func (m *mockStartupService) RestoreStartup(ctx context.Context, id int64, userUUID string, anyOwner bool) (Startup, error) {
+	args := m.Called(ctx, id, userUUID, anyOwner)
+	startup, _ := args.Get(0).(Startup)
+	return startup, args.Error(1)
+}
+
+func (m *mockStartupService) ListDeletedStartups(ctx context.Context, page, limit int) ([]DeletedStartup, int64, error) {
+	args := m.Called(ctx, page, limit)
+	startups, _ := args.Get(0).([]DeletedStartup)
+	return startups, args.Get(1).(int64), args.Error(2)
+}
+
 func (m *mockStartupService) GetStartupByID(ctx context.Context, id int64) (Startup, error) {
 	args := m.Called(ctx, id)
 	startup, _ := args.Get(0).(Startup)
 	return startup, args.Error(1)
 }
 
-func (m *mockStartupService) ListStartups(ctx context.Context, page, limit int) ([]Startup, int64, error) {
-	args := m.Called(ctx, page, limit)
+func (m *mockStartupService) GetStartupBySlug(ctx context.Context, slug string) (Startup, error) {
+	args := m.Called(ctx, slug)
+	startup, _ := args.Get(0).(Startup)
+	return startup, args.Error(1)
+}
+
+func (m *mockStartupService) ListStartups(ctx context.Context, filters StartupFilters, page, limit int) ([]Startup, int64, error) {
+	args := m.Called(ctx, filters, page, limit)
 	startups, _ := args.Get(0).([]Startup)
 	return startups, args.Get(1).(int64), args.Error(2)
 }
 
-func (m *mockStartupService) DeleteAllStartups(ctx context.Context) error {
-	args := m.Called(ctx)
-	return args.Error(0)
+func (m *mockStartupService) BulkDeleteStartups(ctx context.Context, filter admin.BulkFilter, dryRun bool) (admin.BulkResult, error) {
+	args := m.Called(ctx, filter, dryRun)
+	return args.Get(0).(admin.BulkResult), args.Error(1)
+}
+
+func (m *mockStartupService) SearchStartups(ctx context.Context, query string, page, limit int) ([]Startup, int64, error) {
+	args := m.Called(ctx, query, page, limit)
+	startups, _ := args.Get(0).([]Startup)
+	return startups, args.Get(1).(int64), args.Error(2)
 }
 
 func (m *mockStartupService) ListStartupsByUser(ctx context.Context, uuid string) ([]Startup, error) {
@@ -62,6 +90,7 @@ func (m *mockStartupService) ListStartupsByUser(ctx context.Context, uuid string
 func setupRouter(service StartupService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
+	r.Use(testhelpers.AuthAs("user-uuid-1", "founder"))
 	h := NewStartupHandler(service)
 	h.RegisterRoutes(r)
 	return r
@@ -99,6 +128,27 @@ func TestStartupHandler_CreateStartup_Success(t *testing.T) {
 	svc.AssertExpectations(t)
 }
 
+func TestStartupHandler_CreateStartup_PostMortem(t *testing.T) {
+	svc := new(mockStartupService)
+	r := setupRouter(svc)
+
+	svc.On("CreateStartup", mock.Anything, mock.MatchedBy(func(input Startup) bool {
+		return input.FailureReason == "no market" && *input.LifespanMonths == 18 && *input.PeakMRR == 4200.5 && *input.UsersAtShutdown == 0
+	})).Return(Startup{ID: 1}, nil)
+
+	post := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/startups", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusCreated, post(`{"name":"Acme","owner_uuid":"user-uuid-1","failure_reason":"no market","lifespan_months":18,"peak_mrr":4200.5,"users_at_shutdown":0}`))
+	require.Equal(t, http.StatusBadRequest, post(`{"name":"Acme","owner_uuid":"user-uuid-1","peak_mrr":-1}`))
+	svc.AssertExpectations(t)
+}
+
 func TestStartupHandler_CreateStartup_InvalidPayload(t *testing.T) {
 	svc := new(mockStartupService)
 	r := setupRouter(svc)
@@ -113,7 +163,8 @@ func TestStartupHandler_CreateStartup_InvalidPayload(t *testing.T) {
 	var resp response.APIResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
 	require.False(t, resp.Success)
-	require.Equal(t, "invalid request payload", resp.Message)
+	require.Equal(t, "validation failed", resp.Message)
+	require.Contains(t, w.Body.String(), `{"field":"name","rule":"required","message":"name is required"}`)
 
 	svc.AssertNotCalled(t, "CreateStartup", mock.Anything, mock.Anything)
 }
@@ -153,14 +204,40 @@ func TestStartupHandler_UpdateStartup_InvalidID(t *testing.T) {
 	require.False(t, resp.Success)
 	require.Equal(t, "invalid startup id", resp.Message)
 
-	svc.AssertNotCalled(t, "UpdateStartup", mock.Anything, mock.Anything)
+	svc.AssertNotCalled(t, "UpdateStartup", mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestStartupHandler_DeleteStartup_NotFound(t *testing.T) {
+func TestStartupHandler_UpdateStartup_NotOwner(t *testing.T) {
 	svc := new(mockStartupService)
 	r := setupRouter(svc)
 
-	svc.On("DeleteStartup", mock.Anything, int64(42)).Return(ErrStartupNotFound)
+	svc.On("UpdateStartup", mock.Anything, mock.MatchedBy(func(input Startup) bool {
+		return input.ID == 42
+	}), "user-uuid-1").Return(Startup{}, ErrNotOwner)
+
+	req := httptest.NewRequest(http.MethodPut, "/startups/42", strings.NewReader(`{"name":"Acme"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	var resp response.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.False(t, resp.Success)
+	require.Equal(t, "only the startup owner can change it", resp.Message)
+
+	svc.AssertExpectations(t)
+}
+
+func TestStartupHandler_DeleteStartup_NotFound(t *testing.T) {
+	svc := new(mockStartupService)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(testhelpers.AuthAs("admin-uuid-1", auth.RoleAdmin))
+	NewStartupHandler(svc).RegisterRoutes(r)
+
+	svc.On("DeleteStartup", mock.Anything, int64(42), "admin-uuid-1", true).Return(ErrStartupNotFound)
 
 	req := httptest.NewRequest(http.MethodDelete, "/startups/42", nil)
 	w := httptest.NewRecorder()
@@ -176,6 +253,72 @@ func TestStartupHandler_DeleteStartup_NotFound(t *testing.T) {
 	svc.AssertExpectations(t)
 }
 
+func TestStartupHandler_GetStartupBySlug(t *testing.T) {
+	svc := new(mockStartupService)
+	r := setupRouter(svc)
+
+	svc.On("GetStartupBySlug", mock.Anything, "acme-crm").Return(Startup{ID: 7, Name: "Acme CRM", Slug: "acme-crm"}, nil)
+	svc.On("GetStartupBySlug", mock.Anything, "missing").Return(Startup{}, ErrStartupNotFound)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/startups/slug/acme-crm", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data Startup `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "acme-crm", resp.Data.Slug)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/startups/slug/missing", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	svc.AssertExpectations(t)
+}
+
+func TestStartupHandler_DeleteStartup_NotOwner(t *testing.T) {
+	svc := new(mockStartupService)
+	r := setupRouter(svc)
+
+	// Founders only delete their own startups
+	svc.On("DeleteStartup", mock.Anything, int64(42), "user-uuid-1", false).Return(ErrNotOwner)
+
+	req := httptest.NewRequest(http.MethodDelete, "/startups/42", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestStartupHandler_RestoreStartup(t *testing.T) {
+	svc := new(mockStartupService)
+	r := setupRouter(svc)
+
+	svc.On("RestoreStartup", mock.Anything, int64(42), "user-uuid-1", false).Return(Startup{ID: 42, OwnerUUID: "user-uuid-1"}, nil)
+	svc.On("RestoreStartup", mock.Anything, int64(43), "user-uuid-1", false).Return(Startup{}, ErrStartupNotFound)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/startups/42/restore", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/startups/43/restore", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	// Admins restore any owner's startup
+	adminRouter := gin.New()
+	adminRouter.Use(testhelpers.AuthAs("admin-uuid-1", auth.RoleAdmin))
+	NewStartupHandler(svc).RegisterRoutes(adminRouter)
+	svc.On("RestoreStartup", mock.Anything, int64(42), "admin-uuid-1", true).Return(Startup{ID: 42, OwnerUUID: "user-uuid-1"}, nil)
+	w = httptest.NewRecorder()
+	adminRouter.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/startups/42/restore", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	svc.AssertExpectations(t)
+}
+
 // func TestStartupHandler_ListStartups_Success(t *testing.T) {
 // 	svc := new(mockStartupService)
 // 	r := setupRouter(svc)
@@ -212,3 +355,88 @@ func TestStartupHandler_DeleteStartup_NotFound(t *testing.T) {
 
 // 	svc.AssertExpectations(t)
 // }
+
+func TestStartupHandler_ListStartups_Filters(t *testing.T) {
+	svc := new(mockStartupService)
+	r := setupRouter(svc)
+
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	svc.On("ListStartups", mock.Anything, mock.MatchedBy(func(f StartupFilters) bool {
+		return *f.Status == "sold" && *f.OwnerUUID == "owner-1" &&
+			f.CreatedAfter.Equal(after) && f.CreatedBefore.Equal(before) && f.Sort == SortMostAssets
+	}), 1, 10).Return([]Startup{{ID: 1}}, int64(1), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/startups?status=sold&owner_uuid=owner-1&created_after=2024-01-01&created_before=2024-06-01T12:00:00Z&sort=most_assets", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+
+	for _, query := range []string{"status=gone", "sort=oldest", "created_after=yesterday"} {
+		req := httptest.NewRequest(http.MethodGet, "/startups?"+query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestStartupHandler_SearchStartups(t *testing.T) {
+	svc := new(mockStartupService)
+	r := setupRouter(svc)
+
+	svc.On("SearchStartups", mock.Anything, "fintech", 2, 5).Return([]Startup{{ID: 3, Name: "Ledgerly"}}, int64(6), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/startups/search?q=fintech&page=2&limit=5", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp response.APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "startups found", resp.Message)
+	require.NotNil(t, resp.Links)
+	svc.AssertExpectations(t)
+}
+
+func TestBulkHandler_DeleteStartups(t *testing.T) {
+	svc := new(mockStartupService)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewBulkHandler(svc, "secret").RegisterRoutes(r)
+
+	svc.On("BulkDeleteStartups", mock.Anything, admin.BulkFilter{Status: "failed"}, false).
+		Return(admin.BulkResult{Matched: 3}, nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/startups?status=failed", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"matched":3`)
+	svc.AssertExpectations(t)
+}
+
+func TestDeletedHandler_ListDeleted(t *testing.T) {
+	svc := new(mockStartupService)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewDeletedHandler(svc, "secret").RegisterRoutes(r)
+
+	svc.On("ListDeletedStartups", mock.Anything, 1, 10).
+		Return([]DeletedStartup{{Startup: Startup{ID: 5, Name: "Gone"}}}, int64(1), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/startups/deleted", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req.Header.Set("Authorization", "Bearer secret")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"name":"Gone"`)
+	svc.AssertExpectations(t)
+}
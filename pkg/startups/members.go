@@ -0,0 +1,176 @@
+package startups
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/response"
+)
+
+// Team roles. The owner is the startup's owner_uuid; editors may update the startup
+// and viewers may only see its team.
+const (
+	MemberOwner  = "owner"
+	MemberEditor = "editor"
+	MemberViewer = "viewer"
+)
+
+var (
+	ErrNotEditor          = apperr.New(apperr.Forbidden, "only the startup owner and editors can change it")
+	ErrNotMember          = apperr.New(apperr.Forbidden, "only the startup's team can see its members")
+	ErrMemberNotFound     = apperr.New(apperr.MemberNotFound, "member not found")
+	ErrMemberUserNotFound = apperr.New(apperr.UserNotFound, "user not found")
+	ErrMemberIsOwner      = response.InvalidField(apperr.InvalidRequest, "user_uuid", "ne", "the owner is already on the team")
+)
+
+// Member is a user on a startup's team.
+type Member struct {
+	StartupID int64     `json:"startup_id"`
+	UserUUID  string    `json:"user_uuid"`
+	Role      string    `json:"role"`
+	AddedBy   string    `json:"added_by,omitempty"` // empty for the owner
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MemberRepository interface {
+	// AddMember adds an active user to the team, or changes their role if they are
+	// already on it. It fails with ErrMemberUserNotFound if the user does not exist
+	// or is banned.
+	AddMember(ctx context.Context, m Member) (Member, error)
+	// RemoveMember fails with ErrMemberNotFound if the user is not on the team.
+	RemoveMember(ctx context.Context, startupID int64, userUUID string) error
+	// ListMembers returns the owner followed by the other members, oldest first.
+	ListMembers(ctx context.Context, startupID int64) ([]Member, error)
+}
+
+// MemberService manages startup teams. Only the owner may add and remove members;
+// members may also leave on their own.
+type MemberService struct {
+	repo     MemberRepository
+	startups StartupRepository
+}
+
+func NewMemberService(repo MemberRepository, startups StartupRepository) *MemberService {
+	return &MemberService{repo: repo, startups: startups}
+}
+
+// Add puts memberUUID on the team of a startup owned by userUUID with role, which is
+// MemberEditor or MemberViewer.
+func (s *MemberService) Add(ctx context.Context, startupID int64, userUUID, memberUUID, role string) (Member, error) {
+	st, err := s.startups.GetStartupByID(ctx, startupID)
+	if err != nil {
+		return Member{}, err
+	}
+	if st.OwnerUUID != userUUID {
+		return Member{}, ErrNotOwner
+	}
+	if memberUUID == st.OwnerUUID {
+		return Member{}, ErrMemberIsOwner
+	}
+	return s.repo.AddMember(ctx, Member{StartupID: startupID, UserUUID: memberUUID, Role: role, AddedBy: userUUID})
+}
+
+// Remove takes memberUUID off the team. The owner may remove anyone, members only
+// themselves.
+func (s *MemberService) Remove(ctx context.Context, startupID int64, userUUID, memberUUID string) error {
+	st, err := s.startups.GetStartupByID(ctx, startupID)
+	if err != nil {
+		return err
+	}
+	if st.OwnerUUID != userUUID && memberUUID != userUUID {
+		return ErrNotOwner
+	}
+	return s.repo.RemoveMember(ctx, startupID, memberUUID)
+}
+
+// List returns the team of a startup that userUUID is on.
+func (s *MemberService) List(ctx context.Context, startupID int64, userUUID string) ([]Member, error) {
+	st, err := s.startups.GetStartupByID(ctx, startupID)
+	if err != nil {
+		return nil, err
+	}
+	role, err := memberRole(ctx, s.startups, st, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, ErrNotMember
+	}
+	return s.repo.ListMembers(ctx, startupID)
+}
+
+// memberRole returns userUUID's role on the startup's team, or "" if they are not on it.
+func memberRole(ctx context.Context, repo StartupRepository, st Startup, userUUID string) (string, error) {
+	if st.OwnerUUID == userUUID {
+		return MemberOwner, nil
+	}
+	return repo.MemberRole(ctx, st.ID, userUUID)
+}
+
+type postgresMemberRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresMemberRepository(pool *pgxpool.Pool) MemberRepository {
+	return &postgresMemberRepository{pool: pool}
+}
+
+func (r *postgresMemberRepository) AddMember(ctx context.Context, m Member) (Member, error) {
+	var added Member
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO startup_members (startup_id, user_uuid, role, added_by)
+		SELECT $1, $2, $3, $4
+		WHERE EXISTS (SELECT 1 FROM users WHERE uuid = $2 AND is_deleted = false AND banned_at IS NULL)
+		ON CONFLICT (startup_id, user_uuid) DO UPDATE SET role = EXCLUDED.role
+		RETURNING startup_id, user_uuid, role, added_by, created_at`, m.StartupID, m.UserUUID, m.Role, m.AddedBy,
+	).Scan(&added.StartupID, &added.UserUUID, &added.Role, &added.AddedBy, &added.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Member{}, ErrMemberUserNotFound
+	}
+	return added, err
+}
+
+func (r *postgresMemberRepository) RemoveMember(ctx context.Context, startupID int64, userUUID string) error {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM startup_members WHERE startup_id = $1 AND user_uuid = $2`, startupID, userUUID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+func (r *postgresMemberRepository) ListMembers(ctx context.Context, startupID int64) ([]Member, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT startup_id, user_uuid, role, added_by, created_at
+		FROM (
+			SELECT id AS startup_id, owner_uuid AS user_uuid, 'owner' AS role, '' AS added_by, created_at, 0 AS rank
+			FROM startups
+			WHERE id = $1
+			UNION ALL
+			SELECT startup_id, user_uuid, role, added_by, created_at, 1
+			FROM startup_members
+			WHERE startup_id = $1
+		) team
+		ORDER BY rank, created_at, user_uuid`, startupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Member{}
+	for rows.Next() {
+		var m Member
+		if err := rows.Scan(&m.StartupID, &m.UserUUID, &m.Role, &m.AddedBy, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
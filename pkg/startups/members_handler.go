@@ -0,0 +1,122 @@
+package startups
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+type addMemberRequest struct {
+	UserUUID string `json:"user_uuid" binding:"required,max=64"`
+	Role     string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type MemberHandler struct {
+	service *MemberService
+}
+
+func NewMemberHandler(service *MemberService) *MemberHandler {
+	return &MemberHandler{service: service}
+}
+
+func (h *MemberHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/startups/:id/members", auth.Required(), h.list)
+	router.POST("/startups/:id/members", auth.Required(), h.add)
+	router.DELETE("/startups/:id/members/:uuid", auth.Required(), h.remove)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *MemberHandler) Operations() []openapi.Operation {
+	params := []openapi.Param{openapi.Path("id", "integer", "Startup ID")}
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/startups/:id/members",
+			Tag:         "startups",
+			Summary:     "List a startup's team",
+			Description: "The owner followed by the other members and their roles. Only the team may see it.",
+			Params:      params,
+			Response:    []Member{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/startups/:id/members",
+			Tag:         "startups",
+			Summary:     "Add a team member",
+			Description: "Adds a user to the startup's team as an editor, who may update the startup, or a viewer. Adding someone already on the team changes their role. Only the owner may add members.",
+			Params:      params,
+			Request:     addMemberRequest{},
+			Response:    Member{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/startups/:id/members/:uuid",
+			Tag:         "startups",
+			Summary:     "Remove a team member",
+			Description: "The owner may remove anyone from the team; members may remove themselves.",
+			Params:      append(params, openapi.Path("uuid", "string", "Member's user UUID")),
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+	}
+}
+
+func (h *MemberHandler) list(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
+		return
+	}
+
+	members, err := h.service.List(c.Request.Context(), id, auth.UserID(c))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "members listed", members)
+}
+
+func (h *MemberHandler) add(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
+		return
+	}
+
+	var req addMemberRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	member, err := h.service.Add(c.Request.Context(), id, auth.UserID(c), req.UserUUID, req.Role)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "member added", member)
+}
+
+func (h *MemberHandler) remove(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
+		return
+	}
+
+	if err := h.service.Remove(c.Request.Context(), id, auth.UserID(c), c.Param("uuid")); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "member removed", nil)
+}
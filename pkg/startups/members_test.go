@@ -0,0 +1,134 @@
+package startups
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+// fakeMemberRepository keeps the members of every startup in memory, by user UUID.
+type fakeMemberRepository struct {
+	members map[int64]map[string]string
+}
+
+func (f *fakeMemberRepository) AddMember(_ context.Context, m Member) (Member, error) {
+	if m.UserUUID == "ghost" {
+		return Member{}, ErrMemberUserNotFound
+	}
+	if f.members[m.StartupID] == nil {
+		f.members[m.StartupID] = map[string]string{}
+	}
+	f.members[m.StartupID][m.UserUUID] = m.Role
+	return m, nil
+}
+
+func (f *fakeMemberRepository) RemoveMember(_ context.Context, startupID int64, userUUID string) error {
+	if _, ok := f.members[startupID][userUUID]; !ok {
+		return ErrMemberNotFound
+	}
+	delete(f.members[startupID], userUUID)
+	return nil
+}
+
+func (f *fakeMemberRepository) ListMembers(_ context.Context, startupID int64) ([]Member, error) {
+	list := []Member{}
+	for uuid, role := range f.members[startupID] {
+		list = append(list, Member{StartupID: startupID, UserUUID: uuid, Role: role})
+	}
+	return list, nil
+}
+
+// teamStartupRepository answers MemberRole from the fake member repository.
+type teamStartupRepository struct {
+	*mockStartupRepository
+	members *fakeMemberRepository
+}
+
+func (r teamStartupRepository) MemberRole(_ context.Context, startupID int64, userUUID string) (string, error) {
+	return r.members.members[startupID][userUUID], nil
+}
+
+// newTestMemberService serves startup 1, owned by "owner".
+func newTestMemberService() (*MemberService, *fakeMemberRepository) {
+	repo := &fakeMemberRepository{members: map[int64]map[string]string{}}
+	startups := new(mockStartupRepository)
+	startups.On("GetStartupByID", mock.Anything, int64(1)).Return(Startup{ID: 1, OwnerUUID: "owner"}, nil)
+	startups.On("GetStartupByID", mock.Anything, int64(2)).Return(Startup{}, ErrStartupNotFound)
+	return NewMemberService(repo, teamStartupRepository{startups, repo}), repo
+}
+
+func TestMemberService(t *testing.T) {
+	svc, repo := newTestMemberService()
+	ctx := context.Background()
+
+	_, err := svc.Add(ctx, 1, "someone-else", "editor-1", MemberEditor)
+	require.ErrorIs(t, err, ErrNotOwner)
+	_, err = svc.Add(ctx, 1, "owner", "owner", MemberEditor)
+	require.ErrorIs(t, err, ErrMemberIsOwner)
+	_, err = svc.Add(ctx, 2, "owner", "editor-1", MemberEditor)
+	require.ErrorIs(t, err, ErrStartupNotFound)
+
+	_, err = svc.Add(ctx, 1, "owner", "editor-1", MemberEditor)
+	require.NoError(t, err)
+	_, err = svc.Add(ctx, 1, "owner", "viewer-1", MemberViewer)
+	require.NoError(t, err)
+	require.Equal(t, MemberEditor, repo.members[1]["editor-1"])
+
+	// Only the team sees the team
+	list, err := svc.List(ctx, 1, "viewer-1")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	_, err = svc.List(ctx, 1, "someone-else")
+	require.ErrorIs(t, err, ErrNotMember)
+
+	// Members cannot remove each other, but may leave
+	require.ErrorIs(t, svc.Remove(ctx, 1, "viewer-1", "editor-1"), ErrNotOwner)
+	require.NoError(t, svc.Remove(ctx, 1, "viewer-1", "viewer-1"))
+	require.NoError(t, svc.Remove(ctx, 1, "owner", "editor-1"))
+	require.ErrorIs(t, svc.Remove(ctx, 1, "owner", "editor-1"), ErrMemberNotFound)
+	require.Empty(t, repo.members[1])
+}
+
+func TestMemberHandler_Add(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, repo := newTestMemberService()
+	r := gin.New()
+	r.Use(testhelpers.AuthAs("owner", "founder"))
+	NewMemberHandler(svc).RegisterRoutes(r)
+
+	add := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/startups/1/members", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	require.Equal(t, http.StatusBadRequest, add(`{"user_uuid":"editor-1","role":"owner"}`))
+	require.Equal(t, http.StatusNotFound, add(`{"user_uuid":"ghost","role":"viewer"}`))
+	require.Equal(t, http.StatusOK, add(`{"user_uuid":"editor-1","role":"editor"}`))
+	require.Equal(t, MemberEditor, repo.members[1]["editor-1"])
+}
+
+func TestStartupHandler_DeleteStartup_EditorForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, _ := newTestMemberService()
+	_, err := svc.Add(context.Background(), 1, "owner", "editor-1", MemberEditor)
+	require.NoError(t, err)
+
+	// Editors may update the startup but only the owner or an admin may delete it
+	r := gin.New()
+	r.Use(testhelpers.AuthAs("editor-1", "founder"))
+	NewStartupHandler(NewStartupService(svc.startups, nil, nil, nil, nil)).RegisterRoutes(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/startups/1", nil))
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -3,18 +3,30 @@ package startups
 import "time"
 
 type Startup struct {
-	ID          int64     `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	LogoURL     string    `json:"logo_url"`
-	OwnerUUID   string    `json:"owner_uuid"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"` // set from the name on create and never changed
+	Description string `json:"description"`
+	LogoURL     string `json:"logo_url"`
+	OwnerUUID   string `json:"owner_uuid"`
+	Status      string `json:"status"`
+	Country     string `json:"country,omitempty"` // ISO 3166-1 alpha-2 where the entity is registered
+	Region      string `json:"region,omitempty"`  // ISO 3166-2
+	// Verified is the badge an admin grants after reviewing the founder's proof
+	Verified bool `json:"verified"`
+	// Post-mortem, all optional: why the startup failed and how far it got
+	FailureReason   string    `json:"failure_reason,omitempty"`
+	LifespanMonths  *int32    `json:"lifespan_months,omitempty"`
+	PeakMRR         *float64  `json:"peak_mrr,omitempty"` // USD
+	UsersAtShutdown *int64    `json:"users_at_shutdown,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	// Highlight is an excerpt of the name and description with matched words in
+	// <mark> tags, HTML-escaped otherwise. Only the Postgres search sets it.
+	Highlight string `json:"highlight,omitempty"`
 }
 
-type StartupList struct {
-	Items []Startup `json:"items"`
-	Total int64     `json:"total"`
-	Page  int       `json:"page"`
-	Limit int       `json:"limit"`
+// DeletedStartup is a soft-deleted startup that POST /startups/:id/restore can bring back.
+type DeletedStartup struct {
+	Startup
+	DeletedAt time.Time `json:"deleted_at"`
 }
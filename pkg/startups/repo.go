@@ -3,21 +3,85 @@ package startups
 import (
 	"context"
 	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/admin"
+	"grveyard/pkg/apperr"
 )
 
-var ErrStartupNotFound = errors.New("startup not found")
+var ErrStartupNotFound = apperr.New(apperr.StartupNotFound, "startup not found")
+
+// errSlugTaken is returned by CreateStartup when another startup has the slug.
+var errSlugTaken = errors.New("startup slug taken")
 
 type StartupRepository interface {
 	CreateStartup(ctx context.Context, input Startup) (Startup, error)
 	UpdateStartup(ctx context.Context, input Startup) (Startup, error)
 	DeleteStartup(ctx context.Context, id int64) error
-	DeleteAllStartups(ctx context.Context) error
+	// RestoreStartup undoes DeleteStartup for a startup owned by ownerUUID, or by
+	// anyone when ownerUUID is empty. Startups hidden by their owner's ban are not
+	// restored; unbanning brings them back.
+	RestoreStartup(ctx context.Context, id int64, ownerUUID string) (Startup, error)
+	// ListDeletedStartups returns the startups RestoreStartup can bring back, most
+	// recently deleted first, and their total.
+	ListDeletedStartups(ctx context.Context, limit, offset int) ([]DeletedStartup, int64, error)
+	BulkDeleteStartups(ctx context.Context, filter admin.BulkFilter, dryRun bool) ([]int64, error)
 	GetStartupByID(ctx context.Context, id int64) (Startup, error)
-	ListStartups(ctx context.Context, limit, offset int) ([]Startup, int64, error)
+	GetStartupBySlug(ctx context.Context, slug string) (Startup, error)
+	// MemberRole returns userUUID's role on the startup's team, or "" if they are not
+	// a member. The owner has no member role; compare against OwnerUUID.
+	MemberRole(ctx context.Context, startupID int64, userUUID string) (string, error)
+	ListStartups(ctx context.Context, filters StartupFilters, limit, offset int) ([]Startup, int64, error)
 	ListStartupsByUser(ctx context.Context, uuid string) ([]Startup, error)
+	SearchStartups(ctx context.Context, query string, limit, offset int) ([]Startup, int64, error)
+	GetStartupsByIDs(ctx context.Context, ids []int64) ([]Startup, error)
+}
+
+type StartupFilters struct {
+	Status    *string
+	OwnerUUID *string
+	Category  *string // category slug
+	Verified  *bool
+	// CreatedAfter and CreatedBefore bound created_at; the first is inclusive, the
+	// second exclusive.
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Country       *string // ISO 3166-1 alpha-2
+	Region        *string // ISO 3166-2
+	// Viewer is the signed-in caller, if any; startups of founders who blocked them
+	// are left out.
+	Viewer string
+	Sort   StartupSort // "" lists in id order
+}
+
+type StartupSort string
+
+const (
+	SortNewest StartupSort = "newest"
+	SortName   StartupSort = "name"
+	// SortMostAssets puts startups whose founders list the most assets first.
+	SortMostAssets StartupSort = "most_assets"
+)
+
+// StartupSorts lists the accepted values of the sort query parameter.
+var StartupSorts = []string{string(SortNewest), string(SortName), string(SortMostAssets)}
+
+// startupOrderBy maps each sort to its ORDER BY; every order ends with id so pages
+// are stable.
+var startupOrderBy = map[StartupSort]string{
+	"":         "id",
+	SortNewest: "created_at DESC, id DESC",
+	SortName:   "lower(name), id",
+	SortMostAssets: `(SELECT COUNT(*) FROM assets a
+                      WHERE a.user_uuid = startups.owner_uuid AND a.is_deleted = false) DESC, id`,
 }
 
 type postgresStartupRepository struct {
@@ -29,14 +93,19 @@ func NewPostgresStartupRepository(pool *pgxpool.Pool) StartupRepository {
 }
 
 func (r *postgresStartupRepository) CreateStartup(ctx context.Context, input Startup) (Startup, error) {
-	query := `INSERT INTO startups (name, description, logo_url, owner_uuid, status, created_at)
-			  VALUES ($1, $2, $3, $4, $5, NOW())
-			  RETURNING id, name, description, logo_url, owner_uuid, status, created_at`
+	query := `INSERT INTO startups (name, description, logo_url, owner_uuid, status, sold_at, country, region, failure_reason, lifespan_months, peak_mrr, users_at_shutdown, slug, created_at)
+			  VALUES ($1, $2, $3, $4, $5, CASE WHEN $5 = 'sold' THEN NOW() END, $6, $7, $8, $9, $10, $11, $12, NOW())
+			  RETURNING id, name, slug, description, logo_url, owner_uuid, status, country, region, failure_reason, lifespan_months, peak_mrr, users_at_shutdown, verified_at IS NOT NULL, created_at`
 
-	row := r.pool.QueryRow(ctx, query, input.Name, input.Description, input.LogoURL, input.OwnerUUID, input.Status)
+	row := r.pool.QueryRow(ctx, query, input.Name, input.Description, input.LogoURL, input.OwnerUUID, input.Status, input.Country, input.Region,
+		input.FailureReason, input.LifespanMonths, input.PeakMRR, input.UsersAtShutdown, input.Slug)
 
 	var created Startup
-	if err := row.Scan(&created.ID, &created.Name, &created.Description, &created.LogoURL, &created.OwnerUUID, &created.Status, &created.CreatedAt); err != nil {
+	if err := row.Scan(&created.ID, &created.Name, &created.Slug, &created.Description, &created.LogoURL, &created.OwnerUUID, &created.Status, &created.Country, &created.Region, &created.FailureReason, &created.LifespanMonths, &created.PeakMRR, &created.UsersAtShutdown, &created.Verified, &created.CreatedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_startups_slug" {
+			return Startup{}, errSlugTaken
+		}
 		return Startup{}, err
 	}
 
@@ -45,14 +114,17 @@ func (r *postgresStartupRepository) CreateStartup(ctx context.Context, input Sta
 
 func (r *postgresStartupRepository) UpdateStartup(ctx context.Context, input Startup) (Startup, error) {
 	query := `UPDATE startups
-			  SET name = $1, description = $2, logo_url = $3, status = $4
+			  SET name = $1, description = $2, logo_url = $3, status = $4,
+			      sold_at = CASE WHEN $4 <> 'sold' THEN NULL WHEN status = 'sold' THEN sold_at ELSE NOW() END, country = $6, region = $7,
+			      failure_reason = $8, lifespan_months = $9, peak_mrr = $10, users_at_shutdown = $11, updated_at = NOW()
 			  WHERE id = $5
-			  RETURNING id, name, description, logo_url, owner_uuid, status, created_at`
+			  RETURNING id, name, slug, description, logo_url, owner_uuid, status, country, region, failure_reason, lifespan_months, peak_mrr, users_at_shutdown, verified_at IS NOT NULL, created_at`
 
-	row := r.pool.QueryRow(ctx, query, input.Name, input.Description, input.LogoURL, input.Status, input.ID)
+	row := r.pool.QueryRow(ctx, query, input.Name, input.Description, input.LogoURL, input.Status, input.ID, input.Country, input.Region,
+		input.FailureReason, input.LifespanMonths, input.PeakMRR, input.UsersAtShutdown)
 
 	var updated Startup
-	if err := row.Scan(&updated.ID, &updated.Name, &updated.Description, &updated.LogoURL, &updated.OwnerUUID, &updated.Status, &updated.CreatedAt); err != nil {
+	if err := row.Scan(&updated.ID, &updated.Name, &updated.Slug, &updated.Description, &updated.LogoURL, &updated.OwnerUUID, &updated.Status, &updated.Country, &updated.Region, &updated.FailureReason, &updated.LifespanMonths, &updated.PeakMRR, &updated.UsersAtShutdown, &updated.Verified, &updated.CreatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return Startup{}, ErrStartupNotFound
 		}
@@ -63,7 +135,7 @@ func (r *postgresStartupRepository) UpdateStartup(ctx context.Context, input Sta
 }
 
 func (r *postgresStartupRepository) DeleteStartup(ctx context.Context, id int64) error {
-	cmd, err := r.pool.Exec(ctx, "UPDATE startups SET is_deleted = true WHERE id = $1 AND is_deleted = false", id)
+	cmd, err := r.pool.Exec(ctx, "UPDATE startups SET is_deleted = true, deleted_at = NOW() WHERE id = $1 AND is_deleted = false", id)
 	if err != nil {
 		return err
 	}
@@ -73,15 +145,69 @@ func (r *postgresStartupRepository) DeleteStartup(ctx context.Context, id int64)
 	return nil
 }
 
+func (r *postgresStartupRepository) RestoreStartup(ctx context.Context, id int64, ownerUUID string) (Startup, error) {
+	query := `UPDATE startups SET is_deleted = false, deleted_at = NULL, updated_at = NOW()
+              WHERE id = $1 AND is_deleted = true AND hidden_by_ban = false AND ($2::text = '' OR owner_uuid = $2)
+              RETURNING id, name, slug, description, logo_url, owner_uuid, status, country, region, failure_reason, lifespan_months, peak_mrr, users_at_shutdown, verified_at IS NOT NULL, created_at`
+
+	var s Startup
+	if err := r.pool.QueryRow(ctx, query, id, ownerUUID).Scan(&s.ID, &s.Name, &s.Slug, &s.Description, &s.LogoURL, &s.OwnerUUID, &s.Status, &s.Country, &s.Region, &s.FailureReason, &s.LifespanMonths, &s.PeakMRR, &s.UsersAtShutdown, &s.Verified, &s.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Startup{}, ErrStartupNotFound
+		}
+		return Startup{}, err
+	}
+
+	return s, nil
+}
+
+func (r *postgresStartupRepository) ListDeletedStartups(ctx context.Context, limit, offset int) ([]DeletedStartup, int64, error) {
+	query := `SELECT id, name, slug, description, logo_url, owner_uuid, status, country, region, failure_reason, lifespan_months, peak_mrr, users_at_shutdown, verified_at IS NOT NULL, created_at,
+                     COALESCE(deleted_at, updated_at), COUNT(*) OVER() AS total
+              FROM startups
+              WHERE is_deleted = true AND hidden_by_ban = false
+              ORDER BY COALESCE(deleted_at, updated_at) DESC, id DESC
+              LIMIT $1 OFFSET $2`
+
+	rows, err := r.pool.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var total int64
+	startups := make([]DeletedStartup, 0)
+	for rows.Next() {
+		var d DeletedStartup
+		s := &d.Startup
+		if err := rows.Scan(&s.ID, &s.Name, &s.Slug, &s.Description, &s.LogoURL, &s.OwnerUUID, &s.Status, &s.Country, &s.Region, &s.FailureReason, &s.LifespanMonths, &s.PeakMRR, &s.UsersAtShutdown, &s.Verified, &s.CreatedAt, &d.DeletedAt, &total); err != nil {
+			return nil, 0, err
+		}
+		startups = append(startups, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// A page past the end has no rows to carry the total
+	if len(startups) == 0 && offset > 0 {
+		if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM startups WHERE is_deleted = true AND hidden_by_ban = false").Scan(&total); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return startups, total, nil
+}
+
 func (r *postgresStartupRepository) GetStartupByID(ctx context.Context, id int64) (Startup, error) {
-	query := `SELECT id, name, description, logo_url, owner_uuid, status, created_at
+	query := `SELECT id, name, slug, description, logo_url, owner_uuid, status, country, region, failure_reason, lifespan_months, peak_mrr, users_at_shutdown, verified_at IS NOT NULL, created_at
               FROM startups
               WHERE id = $1 AND is_deleted = false`
 
 	row := r.pool.QueryRow(ctx, query, id)
 
 	var s Startup
-	if err := row.Scan(&s.ID, &s.Name, &s.Description, &s.LogoURL, &s.OwnerUUID, &s.Status, &s.CreatedAt); err != nil {
+	if err := row.Scan(&s.ID, &s.Name, &s.Slug, &s.Description, &s.LogoURL, &s.OwnerUUID, &s.Status, &s.Country, &s.Region, &s.FailureReason, &s.LifespanMonths, &s.PeakMRR, &s.UsersAtShutdown, &s.Verified, &s.CreatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return Startup{}, ErrStartupNotFound
 		}
@@ -91,23 +217,119 @@ func (r *postgresStartupRepository) GetStartupByID(ctx context.Context, id int64
 	return s, nil
 }
 
-func (r *postgresStartupRepository) ListStartups(ctx context.Context, limit, offset int) ([]Startup, int64, error) {
-	query := `SELECT id, name, description, logo_url, owner_uuid, status, created_at
+func (r *postgresStartupRepository) GetStartupBySlug(ctx context.Context, slug string) (Startup, error) {
+	query := `SELECT id, name, slug, description, logo_url, owner_uuid, status, country, region, failure_reason, lifespan_months, peak_mrr, users_at_shutdown, verified_at IS NOT NULL, created_at
               FROM startups
-              WHERE is_deleted = false
-              ORDER BY id
-              LIMIT $1 OFFSET $2`
+              WHERE slug = $1 AND is_deleted = false`
 
-	rows, err := r.pool.Query(ctx, query, limit, offset)
+	row := r.pool.QueryRow(ctx, query, slug)
+
+	var s Startup
+	if err := row.Scan(&s.ID, &s.Name, &s.Slug, &s.Description, &s.LogoURL, &s.OwnerUUID, &s.Status, &s.Country, &s.Region, &s.FailureReason, &s.LifespanMonths, &s.PeakMRR, &s.UsersAtShutdown, &s.Verified, &s.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Startup{}, ErrStartupNotFound
+		}
+		return Startup{}, err
+	}
+
+	return s, nil
+}
+
+func (r *postgresStartupRepository) MemberRole(ctx context.Context, startupID int64, userUUID string) (string, error) {
+	var role string
+	err := r.pool.QueryRow(ctx, "SELECT role FROM startup_members WHERE startup_id = $1 AND user_uuid = $2", startupID, userUUID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return role, err
+}
+
+func (r *postgresStartupRepository) ListStartups(ctx context.Context, filters StartupFilters, limit, offset int) ([]Startup, int64, error) {
+	orderBy, ok := startupOrderBy[filters.Sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown startup sort %q", filters.Sort)
+	}
+
+	whereClauses := []string{"is_deleted = false"}
+	args := []interface{}{}
+	argPos := 1
+
+	if filters.Status != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("status = $%d", argPos))
+		args = append(args, *filters.Status)
+		argPos++
+	}
+
+	if filters.OwnerUUID != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("owner_uuid = $%d", argPos))
+		args = append(args, *filters.OwnerUUID)
+		argPos++
+	}
+
+	if filters.Category != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("EXISTS (SELECT 1 FROM startup_categories sc WHERE sc.startup_id = startups.id AND sc.category_slug = $%d)", argPos))
+		args = append(args, *filters.Category)
+		argPos++
+	}
+
+	if filters.Verified != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("(verified_at IS NOT NULL) = $%d", argPos))
+		args = append(args, *filters.Verified)
+		argPos++
+	}
+
+	if filters.CreatedAfter != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("created_at >= $%d", argPos))
+		args = append(args, *filters.CreatedAfter)
+		argPos++
+	}
+
+	if filters.CreatedBefore != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("created_at < $%d", argPos))
+		args = append(args, *filters.CreatedBefore)
+		argPos++
+	}
+
+	if filters.Country != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("country = $%d", argPos))
+		args = append(args, *filters.Country)
+		argPos++
+	}
+
+	if filters.Region != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("region = $%d", argPos))
+		args = append(args, *filters.Region)
+		argPos++
+	}
+
+	if filters.Viewer != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("NOT EXISTS (SELECT 1 FROM blocked_users b WHERE b.blocker_uuid = startups.owner_uuid AND b.blocked_uuid = $%d)", argPos))
+		args = append(args, filters.Viewer)
+		argPos++
+	}
+
+	whereSQL := "WHERE " + strings.Join(whereClauses, " AND ")
+
+	// The window count is computed before LIMIT, so the page and the total come back
+	// in one round trip
+	query := fmt.Sprintf(`SELECT id, name, slug, description, logo_url, owner_uuid, status, country, region, failure_reason, lifespan_months, peak_mrr, users_at_shutdown, verified_at IS NOT NULL, created_at,
+                     COUNT(*) OVER() AS total
+              FROM startups
+              %s
+              ORDER BY %s
+              LIMIT $%d OFFSET $%d`, whereSQL, orderBy, argPos, argPos+1)
+
+	rows, err := r.pool.Query(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer rows.Close()
 
+	var total int64
 	startups := make([]Startup, 0)
 	for rows.Next() {
 		var s Startup
-		if err := rows.Scan(&s.ID, &s.Name, &s.Description, &s.LogoURL, &s.OwnerUUID, &s.Status, &s.CreatedAt); err != nil {
+		if err := rows.Scan(&s.ID, &s.Name, &s.Slug, &s.Description, &s.LogoURL, &s.OwnerUUID, &s.Status, &s.Country, &s.Region, &s.FailureReason, &s.LifespanMonths, &s.PeakMRR, &s.UsersAtShutdown, &s.Verified, &s.CreatedAt, &total); err != nil {
 			return nil, 0, err
 		}
 		startups = append(startups, s)
@@ -117,22 +339,77 @@ func (r *postgresStartupRepository) ListStartups(ctx context.Context, limit, off
 		return nil, 0, err
 	}
 
-	var total int64
-	countRow := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM startups WHERE is_deleted = false")
-	if err := countRow.Scan(&total); err != nil {
-		return nil, 0, err
+	// A page past the end has no rows to carry the total
+	if len(startups) == 0 && offset > 0 {
+		countRow := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM startups "+whereSQL, args...)
+		if err := countRow.Scan(&total); err != nil {
+			return nil, 0, err
+		}
 	}
 
 	return startups, total, nil
 }
 
-func (r *postgresStartupRepository) DeleteAllStartups(ctx context.Context) error {
-	_, err := r.pool.Exec(ctx, "UPDATE startups SET is_deleted = true WHERE is_deleted = false")
-	return err
+// BulkDeleteStartups soft deletes the startups matching filter and returns their ids. A
+// dry run performs the update to collect the ids and rolls back.
+func (r *postgresStartupRepository) BulkDeleteStartups(ctx context.Context, filter admin.BulkFilter, dryRun bool) ([]int64, error) {
+	whereClauses := []string{"is_deleted = false"}
+	args := []interface{}{}
+
+	if filter.OwnerUUID != "" {
+		args = append(args, filter.OwnerUUID)
+		whereClauses = append(whereClauses, fmt.Sprintf("owner_uuid = $%d", len(args)))
+	}
+
+	if filter.CreatedBefore != nil {
+		args = append(args, *filter.CreatedBefore)
+		whereClauses = append(whereClauses, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		whereClauses = append(whereClauses, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	query := `UPDATE startups SET is_deleted = true, deleted_at = NOW(), updated_at = NOW()
+              WHERE ` + strings.Join(whereClauses, " AND ") + `
+              RETURNING id`
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return ids, nil
+	}
+	return ids, tx.Commit(ctx)
 }
 
 func (r *postgresStartupRepository) ListStartupsByUser(ctx context.Context, uuid string) ([]Startup, error) {
-	query := `SELECT * FROM startups WHERE owner_uuid = $1`
+	query := `SELECT id, name, slug, description, logo_url, owner_uuid, status, country, region, failure_reason, lifespan_months, peak_mrr, users_at_shutdown, verified_at IS NOT NULL, created_at
+              FROM startups
+              WHERE owner_uuid = $1 AND is_deleted = false
+              ORDER BY id`
 
 	rows, err := r.pool.Query(ctx, query, uuid)
 	if err != nil {
@@ -143,7 +420,7 @@ func (r *postgresStartupRepository) ListStartupsByUser(ctx context.Context, uuid
 	startups := make([]Startup, 0)
 	for rows.Next() {
 		var s Startup
-		if err := rows.Scan(&s.ID, &s.Name, &s.Description, &s.LogoURL, &s.OwnerUUID, &s.Status, &s.CreatedAt); err != nil {
+		if err := rows.Scan(&s.ID, &s.Name, &s.Slug, &s.Description, &s.LogoURL, &s.OwnerUUID, &s.Status, &s.Country, &s.Region, &s.FailureReason, &s.LifespanMonths, &s.PeakMRR, &s.UsersAtShutdown, &s.Verified, &s.CreatedAt); err != nil {
 			return nil, err
 		}
 		startups = append(startups, s)
@@ -151,3 +428,100 @@ func (r *postgresStartupRepository) ListStartupsByUser(ctx context.Context, uuid
 
 	return startups, nil
 }
+
+// Markers ts_headline puts around matches. They are swapped for <mark> tags after
+// the rest of the text is HTML-escaped, so highlights are safe to render.
+const (
+	highlightStart = "\x01"
+	highlightStop  = "\x02"
+)
+
+var highlightOptions = fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=" … "`, highlightStart, highlightStop)
+
+// SearchStartups is the SQL fallback for search: Postgres full-text search over name
+// and description, each word matched as a prefix. Results are ranked with matches in
+// the name first, then newest first, and carry a highlighted excerpt.
+func (r *postgresStartupRepository) SearchStartups(ctx context.Context, query string, limit, offset int) ([]Startup, int64, error) {
+	tsquery := prefixTSQuery(query)
+	if tsquery == "" {
+		return []Startup{}, 0, nil
+	}
+	from := `FROM startups s, to_tsquery('english', $1) q
+              WHERE s.is_deleted = false AND s.search_vector @@ q`
+
+	rows, err := r.pool.Query(ctx, `SELECT s.id, s.name, s.slug, s.description, s.logo_url, s.owner_uuid, s.status, s.country, s.region, s.failure_reason, s.lifespan_months, s.peak_mrr, s.users_at_shutdown, s.verified_at IS NOT NULL, s.created_at,
+                     ts_headline('english', s.name || ': ' || COALESCE(s.description, ''), q, $4)
+              `+from+`
+              ORDER BY ts_rank(s.search_vector, q) DESC, s.created_at DESC, s.id DESC
+              LIMIT $2 OFFSET $3`, tsquery, limit, offset, highlightOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	startups := make([]Startup, 0)
+	for rows.Next() {
+		var s Startup
+		if err := rows.Scan(&s.ID, &s.Name, &s.Slug, &s.Description, &s.LogoURL, &s.OwnerUUID, &s.Status, &s.Country, &s.Region, &s.FailureReason, &s.LifespanMonths, &s.PeakMRR, &s.UsersAtShutdown, &s.Verified, &s.CreatedAt, &s.Highlight); err != nil {
+			return nil, 0, err
+		}
+		s.Highlight = markHighlight(s.Highlight)
+		startups = append(startups, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) "+from, tsquery).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	return startups, total, nil
+}
+
+// GetStartupsByIDs loads startups in the order of ids; unknown or deleted ids are skipped.
+func (r *postgresStartupRepository) GetStartupsByIDs(ctx context.Context, ids []int64) ([]Startup, error) {
+	query := `SELECT id, name, slug, description, logo_url, owner_uuid, status, country, region, failure_reason, lifespan_months, peak_mrr, users_at_shutdown, verified_at IS NOT NULL, created_at
+              FROM startups
+              WHERE id = ANY($1) AND is_deleted = false
+              ORDER BY array_position($1, id::bigint)`
+
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	startups := make([]Startup, 0, len(ids))
+	for rows.Next() {
+		var s Startup
+		if err := rows.Scan(&s.ID, &s.Name, &s.Slug, &s.Description, &s.LogoURL, &s.OwnerUUID, &s.Status, &s.Country, &s.Region, &s.FailureReason, &s.LifespanMonths, &s.PeakMRR, &s.UsersAtShutdown, &s.Verified, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		startups = append(startups, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return startups, nil
+}
+
+// prefixTSQuery turns free text into a to_tsquery expression that matches every word
+// as a prefix, e.g. "grave yard" becomes "grave:* & yard:*". Punctuation is dropped so
+// user input cannot inject tsquery operators; it returns "" if no words are left.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// markHighlight HTML-escapes a ts_headline excerpt and wraps its matches in <mark>.
+func markHighlight(s string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(s))
+}
@@ -3,40 +3,22 @@ package startups
 import (
 	"context"
 	"fmt"
-	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/require"
-)
-
-func setupTestPool(t *testing.T) *pgxpool.Pool {
-	t.Helper()
-
-	dsn := os.Getenv("DATABASE_URL_FOR_TEST")
-	if dsn == "" {
-		t.Skip("DATABASE_URL_FOR_TEST not set; skipping repository tests")
-	}
 
-	ctx := context.Background()
-	cfg, err := pgxpool.ParseConfig(dsn)
-	require.NoError(t, err)
-
-	pool, err := pgxpool.NewWithConfig(ctx, cfg)
-	require.NoError(t, err)
-	require.NoError(t, pool.Ping(ctx))
+	"grveyard/pkg/testhelpers"
+)
 
-	t.Cleanup(pool.Close)
-	return pool
-}
+func TestMain(m *testing.M) { testhelpers.Main(m) }
 
-func cleanDatabase(t *testing.T, pool *pgxpool.Pool) {
+func setupTestPool(t *testing.T) *pgxpool.Pool {
 	t.Helper()
 
-	ctx := context.Background()
-	_, err := pool.Exec(ctx, "TRUNCATE TABLE messages, chats, assets, startups, users RESTART IDENTITY CASCADE")
-	require.NoError(t, err)
+	return testhelpers.Pool(t)
 }
 
 func insertTestUserUUID(t *testing.T, pool *pgxpool.Pool, name string) string {
@@ -53,8 +35,8 @@ func insertTestUserUUID(t *testing.T, pool *pgxpool.Pool, name string) string {
 }
 
 func TestPostgresStartupRepository_CreateStartup(t *testing.T) {
+	t.Parallel()
 	pool := setupTestPool(t)
-	// cleanDatabase(t, pool)
 
 	repo := NewPostgresStartupRepository(pool)
 	ctx := context.Background()
@@ -62,6 +44,7 @@ func TestPostgresStartupRepository_CreateStartup(t *testing.T) {
 
 	created, err := repo.CreateStartup(ctx, Startup{
 		Name:        "Acme",
+		Slug:        "acme",
 		Description: "Acme desc",
 		LogoURL:     "https://example.com/logo.png",
 		OwnerUUID:   ownerUUID,
@@ -73,9 +56,69 @@ func TestPostgresStartupRepository_CreateStartup(t *testing.T) {
 	require.Equal(t, "Acme", created.Name)
 }
 
+func TestPostgresStartupRepository_Slug(t *testing.T) {
+	t.Parallel()
+	pool := setupTestPool(t)
+
+	repo := NewPostgresStartupRepository(pool)
+	ctx := context.Background()
+	ownerUUID := insertTestUserUUID(t, pool, "Slugger")
+
+	created, err := repo.CreateStartup(ctx, Startup{Name: "Acme CRM", Slug: "acme-crm", OwnerUUID: ownerUUID, Status: "failed"})
+	require.NoError(t, err)
+	require.Equal(t, "acme-crm", created.Slug)
+
+	_, err = repo.CreateStartup(ctx, Startup{Name: "Acme CRM", Slug: "acme-crm", OwnerUUID: ownerUUID, Status: "failed"})
+	require.ErrorIs(t, err, errSlugTaken)
+
+	got, err := repo.GetStartupBySlug(ctx, "acme-crm")
+	require.NoError(t, err)
+	require.Equal(t, created.ID, got.ID)
+
+	// Renaming keeps the slug
+	_, err = repo.UpdateStartup(ctx, Startup{ID: created.ID, Name: "Acme Sales", Status: "failed"})
+	require.NoError(t, err)
+	got, err = repo.GetStartupBySlug(ctx, "acme-crm")
+	require.NoError(t, err)
+	require.Equal(t, "Acme Sales", got.Name)
+
+	require.NoError(t, repo.DeleteStartup(ctx, created.ID))
+	_, err = repo.GetStartupBySlug(ctx, "acme-crm")
+	require.ErrorIs(t, err, ErrStartupNotFound)
+}
+
+func TestPostgresStartupRepository_PostMortem(t *testing.T) {
+	t.Parallel()
+	pool := setupTestPool(t)
+
+	repo := NewPostgresStartupRepository(pool)
+	ctx := context.Background()
+	ownerUUID := insertTestUserUUID(t, pool, "Founder")
+	lifespan, mrr, users := int32(18), 4200.5, int64(1300)
+
+	created, err := repo.CreateStartup(ctx, Startup{Name: "Acme", Slug: "acme", OwnerUUID: ownerUUID, Status: "failed",
+		FailureReason: "no market", LifespanMonths: &lifespan, PeakMRR: &mrr, UsersAtShutdown: &users})
+	require.NoError(t, err)
+
+	got, err := repo.GetStartupByID(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, "no market", got.FailureReason)
+	require.Equal(t, lifespan, *got.LifespanMonths)
+	require.Equal(t, mrr, *got.PeakMRR)
+	require.Equal(t, users, *got.UsersAtShutdown)
+
+	// Updating without the fields clears them
+	updated, err := repo.UpdateStartup(ctx, Startup{ID: created.ID, Name: "Acme", Status: "failed"})
+	require.NoError(t, err)
+	require.Empty(t, updated.FailureReason)
+	require.Nil(t, updated.LifespanMonths)
+	require.Nil(t, updated.PeakMRR)
+	require.Nil(t, updated.UsersAtShutdown)
+}
+
 func TestPostgresStartupRepository_UpdateStartup(t *testing.T) {
+	t.Parallel()
 	pool := setupTestPool(t)
-	// cleanDatabase(t, pool)
 
 	repo := NewPostgresStartupRepository(pool)
 	ctx := context.Background()
@@ -83,6 +126,7 @@ func TestPostgresStartupRepository_UpdateStartup(t *testing.T) {
 
 	created, err := repo.CreateStartup(ctx, Startup{
 		Name:        "Old",
+		Slug:        "old",
 		Description: "Old desc",
 		LogoURL:     "old.png",
 		OwnerUUID:   ownerUUID,
@@ -108,8 +152,8 @@ func TestPostgresStartupRepository_UpdateStartup(t *testing.T) {
 }
 
 func TestPostgresStartupRepository_DeleteStartup(t *testing.T) {
+	t.Parallel()
 	pool := setupTestPool(t)
-	// cleanDatabase(t, pool)
 
 	repo := NewPostgresStartupRepository(pool)
 	ctx := context.Background()
@@ -117,6 +161,7 @@ func TestPostgresStartupRepository_DeleteStartup(t *testing.T) {
 
 	created, err := repo.CreateStartup(ctx, Startup{
 		Name:        "DeleteMe",
+		Slug:        "deleteme",
 		Description: "To be deleted",
 		LogoURL:     "del.png",
 		OwnerUUID:   ownerUUID,
@@ -130,6 +175,51 @@ func TestPostgresStartupRepository_DeleteStartup(t *testing.T) {
 	require.ErrorIs(t, err, ErrStartupNotFound)
 }
 
+func TestPostgresStartupRepository_RestoreStartup(t *testing.T) {
+	t.Parallel()
+	pool := setupTestPool(t)
+
+	repo := NewPostgresStartupRepository(pool)
+	ctx := context.Background()
+	ownerUUID := insertTestUserUUID(t, pool, "Dana")
+
+	created, err := repo.CreateStartup(ctx, Startup{Name: "Comeback", Slug: "comeback", OwnerUUID: ownerUUID, Status: "failed"})
+	require.NoError(t, err)
+	banned, err := repo.CreateStartup(ctx, Startup{Name: "Banned", Slug: "banned", OwnerUUID: ownerUUID, Status: "failed"})
+	require.NoError(t, err)
+
+	_, err = repo.RestoreStartup(ctx, created.ID, "")
+	require.ErrorIs(t, err, ErrStartupNotFound, "only deleted startups can be restored")
+
+	require.NoError(t, repo.DeleteStartup(ctx, created.ID))
+	_, err = pool.Exec(ctx, "UPDATE startups SET is_deleted = true, hidden_by_ban = true WHERE id = $1", banned.ID)
+	require.NoError(t, err)
+
+	deleted, total, err := repo.ListDeletedStartups(ctx, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Equal(t, created.ID, deleted[0].ID)
+	require.False(t, deleted[0].DeletedAt.IsZero())
+
+	// A page past the end still reports the total
+	deleted, total, err = repo.ListDeletedStartups(ctx, 10, 10)
+	require.NoError(t, err)
+	require.Empty(t, deleted)
+	require.EqualValues(t, 1, total)
+
+	_, err = repo.RestoreStartup(ctx, created.ID, "someone-else")
+	require.ErrorIs(t, err, ErrStartupNotFound)
+	restored, err := repo.RestoreStartup(ctx, created.ID, ownerUUID)
+	require.NoError(t, err)
+	require.Equal(t, "Comeback", restored.Name)
+	_, err = repo.GetStartupByID(ctx, created.ID)
+	require.NoError(t, err)
+
+	// Listings hidden by a ban wait for the unban
+	_, err = repo.RestoreStartup(ctx, banned.ID, "")
+	require.ErrorIs(t, err, ErrStartupNotFound)
+}
+
 // func TestPostgresStartupRepository_ListStartups(t *testing.T) {
 // 	pool := setupTestPool(t)
 // 	// cleanDatabase(t, pool)
@@ -178,3 +268,340 @@ func TestPostgresStartupRepository_DeleteStartup(t *testing.T) {
 // 	require.ErrorAs(t, err, &pgErr)
 // 	require.Equal(t, "23503", pgErr.Code)
 // }
+
+func TestPostgresStartupRepository_SearchStartups(t *testing.T) {
+	t.Parallel()
+	pool := setupTestPool(t)
+
+	repo := NewPostgresStartupRepository(pool)
+	ctx := context.Background()
+	ownerUUID := insertTestUserUUID(t, pool, "Searcher")
+	word := fmt.Sprintf("quokka%d", time.Now().UnixNano())
+
+	inDescription, err := repo.CreateStartup(ctx, Startup{Name: "Ledger", Slug: "ledger", Description: "Invoicing for " + word + " farmers & <friends>", OwnerUUID: ownerUUID, Status: "failed"})
+	require.NoError(t, err)
+	inName, err := repo.CreateStartup(ctx, Startup{Name: word + " Labs", Slug: "labs", Description: "Analytics", OwnerUUID: ownerUUID, Status: "failed"})
+	require.NoError(t, err)
+	deleted, err := repo.CreateStartup(ctx, Startup{Name: word + " Gone", Slug: "gone", OwnerUUID: ownerUUID, Status: "failed"})
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteStartup(ctx, deleted.ID))
+
+	// Name matches rank above description matches; words match as prefixes
+	list, total, err := repo.SearchStartups(ctx, word[:len(word)-2], 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+	require.Equal(t, []int64{inName.ID, inDescription.ID}, []int64{list[0].ID, list[1].ID})
+	require.Contains(t, list[1].Highlight, "<mark>"+word+"</mark>")
+	require.Contains(t, list[1].Highlight, "&lt;friends&gt;")
+
+	// Every word has to match
+	list, total, err = repo.SearchStartups(ctx, word+" farmers", 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Equal(t, inDescription.ID, list[0].ID)
+
+	// tsquery operators are not interpreted
+	list, total, err = repo.SearchStartups(ctx, "!&|", 10, 0)
+	require.NoError(t, err)
+	require.Zero(t, total)
+	require.Empty(t, list)
+}
+
+func TestPrefixTSQuery(t *testing.T) {
+	require.Equal(t, "grave:* & yard:*", prefixTSQuery("Grave  yard!"))
+	require.Equal(t, "a:* & b:*", prefixTSQuery("a:* | !b"))
+	require.Equal(t, "", prefixTSQuery("&|!"))
+}
+
+func TestPostgresStartupRepository_ListStartups_FiltersAndSort(t *testing.T) {
+	t.Parallel()
+	pool := setupTestPool(t)
+
+	repo := NewPostgresStartupRepository(pool)
+	ctx := context.Background()
+	busy := insertTestUserUUID(t, pool, "Busy")
+	quiet := insertTestUserUUID(t, pool, "Quiet")
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(busy))
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(busy))
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(quiet))
+
+	// A range of past hours no other test writes to keeps the rows to ours
+	base := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(time.Now().UnixNano()%100000) * 24 * time.Hour)
+	create := func(name, owner, status string, age int) Startup {
+		t.Helper()
+		s, err := repo.CreateStartup(ctx, Startup{Name: name, Slug: strings.ToLower(name), OwnerUUID: owner, Status: status})
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx, "UPDATE startups SET created_at = $1 WHERE id = $2", base.Add(time.Duration(age)*time.Hour), s.ID)
+		require.NoError(t, err)
+		return s
+	}
+	zeta := create("zeta", quiet, "failed", 0)
+	alpha := create("Alpha", quiet, "failed", 1)
+	mid := create("mid", busy, "failed", 2)
+	sold := create("sold", busy, "sold", 3)
+
+	after, before := base, base.Add(4*time.Hour)
+	list := func(f StartupFilters) []int64 {
+		t.Helper()
+		f.CreatedAfter, f.CreatedBefore = &after, &before
+		got, total, err := repo.ListStartups(ctx, f, 10, 0)
+		require.NoError(t, err)
+		require.EqualValues(t, len(got), total)
+		ids := make([]int64, len(got))
+		for i, s := range got {
+			ids[i] = s.ID
+		}
+		return ids
+	}
+
+	require.Equal(t, []int64{zeta.ID, alpha.ID, mid.ID, sold.ID}, list(StartupFilters{}))
+	require.Equal(t, []int64{sold.ID, mid.ID, alpha.ID, zeta.ID}, list(StartupFilters{Sort: SortNewest}))
+	require.Equal(t, []int64{alpha.ID, mid.ID, sold.ID, zeta.ID}, list(StartupFilters{Sort: SortName}))
+	require.Equal(t, []int64{mid.ID, sold.ID, zeta.ID, alpha.ID}, list(StartupFilters{Sort: SortMostAssets}))
+
+	status, owner := "sold", quiet
+	require.Equal(t, []int64{sold.ID}, list(StartupFilters{Status: &status}))
+	require.Equal(t, []int64{zeta.ID, alpha.ID}, list(StartupFilters{OwnerUUID: &owner}))
+
+	after, before = base.Add(time.Hour), base.Add(3*time.Hour)
+	require.Equal(t, []int64{alpha.ID, mid.ID}, list(StartupFilters{}))
+}
+
+func TestPostgresCategoryRepository(t *testing.T) {
+	t.Parallel()
+	pool := setupTestPool(t)
+
+	repo := NewPostgresCategoryRepository(pool)
+	startups := NewPostgresStartupRepository(pool)
+	ctx := context.Background()
+	ownerUUID := insertTestUserUUID(t, pool, "Tagger")
+
+	all, err := repo.ListCategories(ctx)
+	require.NoError(t, err)
+	require.Contains(t, all, Category{Slug: "fintech", Name: "Fintech"})
+
+	tagged, err := startups.CreateStartup(ctx, Startup{Name: "Ledgerly", Slug: "ledgerly", OwnerUUID: ownerUUID, Status: "failed"})
+	require.NoError(t, err)
+	untagged, err := startups.CreateStartup(ctx, Startup{Name: "Shoply", Slug: "shoply", OwnerUUID: ownerUUID, Status: "failed"})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.SetStartupCategories(ctx, tagged.ID, []string{"saas", "fintech"}))
+	list, err := repo.ListStartupCategories(ctx, tagged.ID)
+	require.NoError(t, err)
+	require.Equal(t, []Category{{Slug: "fintech", Name: "Fintech"}, {Slug: "saas", Name: "SaaS"}}, list)
+
+	category := "fintech"
+	found, total, err := startups.ListStartups(ctx, StartupFilters{OwnerUUID: &ownerUUID, Category: &category}, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Equal(t, tagged.ID, found[0].ID)
+
+	// Setting replaces the previous categories
+	require.NoError(t, repo.SetStartupCategories(ctx, tagged.ID, []string{}))
+	list, err = repo.ListStartupCategories(ctx, tagged.ID)
+	require.NoError(t, err)
+	require.Empty(t, list)
+	list, err = repo.ListStartupCategories(ctx, untagged.ID)
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
+
+func TestPostgresTransferRepository(t *testing.T) {
+	t.Parallel()
+	pool := setupTestPool(t)
+
+	repo := NewPostgresTransferRepository(pool)
+	startups := NewPostgresStartupRepository(pool)
+	ctx := context.Background()
+	sellerUUID := insertTestUserUUID(t, pool, "Seller")
+	buyerUUID := insertTestUserUUID(t, pool, "Buyer")
+
+	st, err := startups.CreateStartup(ctx, Startup{Name: "Handoff", Slug: "handoff", OwnerUUID: sellerUUID, Status: "sold"})
+	require.NoError(t, err)
+
+	_, err = repo.Transfer(ctx, st.ID, sellerUUID, "no-such-user")
+	require.ErrorIs(t, err, ErrNewOwnerNotFound)
+
+	transfer, err := repo.Transfer(ctx, st.ID, sellerUUID, buyerUUID)
+	require.NoError(t, err)
+	require.Equal(t, sellerUUID, transfer.FromUUID)
+	require.Equal(t, buyerUUID, transfer.ToUUID)
+
+	got, err := startups.GetStartupByID(ctx, st.ID)
+	require.NoError(t, err)
+	require.Equal(t, buyerUUID, got.OwnerUUID)
+
+	// The seller no longer owns it, so a second transfer from them fails
+	_, err = repo.Transfer(ctx, st.ID, sellerUUID, buyerUUID)
+	require.ErrorIs(t, err, ErrNotOwner)
+
+	history, err := repo.ListTransfers(ctx, st.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, transfer.ID, history[0].ID)
+}
+
+func TestPostgresViewRepository(t *testing.T) {
+	t.Parallel()
+	pool := setupTestPool(t)
+
+	repo := NewPostgresViewRepository(pool)
+	startups := NewPostgresStartupRepository(pool)
+	ctx := context.Background()
+	ownerUUID := insertTestUserUUID(t, pool, "Viewed")
+	today := time.Date(2026, 5, 6, 0, 0, 0, 0, time.UTC)
+	day := func(ago int) time.Time { return today.AddDate(0, 0, -ago) }
+
+	steady, err := startups.CreateStartup(ctx, Startup{Name: "Steady", Slug: "steady", OwnerUUID: ownerUUID, Status: "failed"})
+	require.NoError(t, err)
+	rising, err := startups.CreateStartup(ctx, Startup{Name: "Rising", Slug: "rising", OwnerUUID: ownerUUID, Status: "failed"})
+	require.NoError(t, err)
+	stale, err := startups.CreateStartup(ctx, Startup{Name: "Stale", Slug: "stale", OwnerUUID: ownerUUID, Status: "failed"})
+	require.NoError(t, err)
+
+	// steady: 4 views spread over the week; rising: 3 views today; stale: only
+	// views older than the window
+	require.NoError(t, repo.RecordViews(ctx, []View{
+		{StartupID: steady.ID, Viewer: "a", Day: day(6)},
+		{StartupID: steady.ID, Viewer: "b", Day: day(5)},
+		{StartupID: steady.ID, Viewer: "c", Day: day(4)},
+		{StartupID: steady.ID, Viewer: "d", Day: day(3)},
+		{StartupID: rising.ID, Viewer: "a", Day: day(0)},
+		{StartupID: rising.ID, Viewer: "b", Day: day(0)},
+		{StartupID: rising.ID, Viewer: "c", Day: day(0)},
+		{StartupID: stale.ID, Viewer: "a", Day: day(10)},
+	}))
+	// A repeat view is ignored
+	require.NoError(t, repo.RecordViews(ctx, []View{{StartupID: rising.ID, Viewer: "a", Day: day(0)}}))
+
+	list, err := repo.Trending(ctx, today, TrendingDays, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, rising.ID, list[0].ID)
+	require.EqualValues(t, 3, list[0].Views)
+	require.Equal(t, "rising", list[0].Slug)
+	require.Equal(t, steady.ID, list[1].ID)
+	require.EqualValues(t, 4, list[1].Views)
+
+	require.NoError(t, startups.DeleteStartup(ctx, rising.ID))
+	list, err = repo.Trending(ctx, today, TrendingDays, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	pruned, err := repo.PruneViews(ctx, day(5))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, pruned) // steady's oldest and stale's
+}
+
+func TestPostgresVerificationRepository(t *testing.T) {
+	t.Parallel()
+	pool := setupTestPool(t)
+
+	repo := NewPostgresVerificationRepository(pool)
+	startups := NewPostgresStartupRepository(pool)
+	ctx := context.Background()
+	ownerUUID := insertTestUserUUID(t, pool, "Founder")
+
+	st, err := startups.CreateStartup(ctx, Startup{Name: "Proven", Slug: "proven", OwnerUUID: ownerUUID, Status: "failed"})
+	require.NoError(t, err)
+	require.False(t, st.Verified)
+
+	_, err = repo.Latest(ctx, st.ID)
+	require.ErrorIs(t, err, ErrVerificationNotFound)
+	_, err = repo.Decide(ctx, st.ID, true, "")
+	require.ErrorIs(t, err, ErrVerificationNotFound)
+
+	first, err := repo.Submit(ctx, VerificationRequest{StartupID: st.ID, SubmittedBy: ownerUUID, ProofURL: "https://example.com/registry"})
+	require.NoError(t, err)
+	require.Equal(t, VerificationPending, first.Status)
+	_, err = repo.Submit(ctx, VerificationRequest{StartupID: st.ID, SubmittedBy: ownerUUID, ProofURL: "https://example.com/other"})
+	require.ErrorIs(t, err, ErrVerificationPending)
+
+	rejected, err := repo.Decide(ctx, st.ID, false, "registry entry is for another company")
+	require.NoError(t, err)
+	require.Equal(t, VerificationRejected, rejected.Status)
+	require.NotNil(t, rejected.ReviewedAt)
+
+	// A rejected request makes room for a new one
+	second, err := repo.Submit(ctx, VerificationRequest{StartupID: st.ID, SubmittedBy: ownerUUID, ProofURL: "https://example.com/registry-2"})
+	require.NoError(t, err)
+	pending, total, err := repo.List(ctx, VerificationPending, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Equal(t, second.ID, pending[0].ID)
+	_, total, err = repo.List(ctx, "", 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+
+	approved, err := repo.Decide(ctx, st.ID, true, "")
+	require.NoError(t, err)
+	require.Equal(t, VerificationApproved, approved.Status)
+	latest, err := repo.Latest(ctx, st.ID)
+	require.NoError(t, err)
+	require.Equal(t, second.ID, latest.ID)
+
+	got, err := startups.GetStartupByID(ctx, st.ID)
+	require.NoError(t, err)
+	require.True(t, got.Verified)
+
+	_, err = startups.CreateStartup(ctx, Startup{Name: "Unproven", Slug: "unproven", OwnerUUID: ownerUUID, Status: "failed"})
+	require.NoError(t, err)
+	verified := true
+	list, total, err := startups.ListStartups(ctx, StartupFilters{Verified: &verified}, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Equal(t, st.ID, list[0].ID)
+	verified = false
+	list, _, err = startups.ListStartups(ctx, StartupFilters{Verified: &verified}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "Unproven", list[0].Name)
+}
+
+func TestPostgresMemberRepository(t *testing.T) {
+	t.Parallel()
+	pool := setupTestPool(t)
+
+	repo := NewPostgresMemberRepository(pool)
+	startups := NewPostgresStartupRepository(pool)
+	transfers := NewPostgresTransferRepository(pool)
+	ctx := context.Background()
+	ownerUUID := insertTestUserUUID(t, pool, "Owner")
+	editorUUID := insertTestUserUUID(t, pool, "Editor")
+
+	st, err := startups.CreateStartup(ctx, Startup{Name: "Team", Slug: "team", OwnerUUID: ownerUUID, Status: "sold"})
+	require.NoError(t, err)
+
+	_, err = repo.AddMember(ctx, Member{StartupID: st.ID, UserUUID: "no-such-user", Role: MemberEditor, AddedBy: ownerUUID})
+	require.ErrorIs(t, err, ErrMemberUserNotFound)
+
+	_, err = repo.AddMember(ctx, Member{StartupID: st.ID, UserUUID: editorUUID, Role: MemberViewer, AddedBy: ownerUUID})
+	require.NoError(t, err)
+	// Adding again changes the role
+	added, err := repo.AddMember(ctx, Member{StartupID: st.ID, UserUUID: editorUUID, Role: MemberEditor, AddedBy: ownerUUID})
+	require.NoError(t, err)
+	require.Equal(t, MemberEditor, added.Role)
+
+	role, err := startups.MemberRole(ctx, st.ID, editorUUID)
+	require.NoError(t, err)
+	require.Equal(t, MemberEditor, role)
+	role, err = startups.MemberRole(ctx, st.ID, ownerUUID)
+	require.NoError(t, err)
+	require.Empty(t, role)
+
+	team, err := repo.ListMembers(ctx, st.ID)
+	require.NoError(t, err)
+	require.Len(t, team, 2)
+	require.Equal(t, Member{StartupID: st.ID, UserUUID: ownerUUID, Role: MemberOwner, CreatedAt: team[0].CreatedAt}, team[0])
+	require.Equal(t, editorUUID, team[1].UserUUID)
+
+	// A member who takes over the startup leaves the member list
+	_, err = transfers.Transfer(ctx, st.ID, ownerUUID, editorUUID)
+	require.NoError(t, err)
+	team, err = repo.ListMembers(ctx, st.ID)
+	require.NoError(t, err)
+	require.Len(t, team, 1)
+	require.Equal(t, editorUUID, team[0].UserUUID)
+
+	require.ErrorIs(t, repo.RemoveMember(ctx, st.ID, editorUUID), ErrMemberNotFound)
+}
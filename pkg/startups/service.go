@@ -1,48 +1,193 @@
 package startups
 
-import "context"
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+
+	"grveyard/pkg/activity"
+	"grveyard/pkg/admin"
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/bookmarks"
+	"grveyard/pkg/geo"
+	"grveyard/pkg/requestid"
+	"grveyard/pkg/search"
+	"grveyard/pkg/seo"
+	"grveyard/pkg/users"
+)
 
 type StartupService interface {
 	CreateStartup(ctx context.Context, input Startup) (Startup, error)
-	UpdateStartup(ctx context.Context, input Startup) (Startup, error)
-	DeleteStartup(ctx context.Context, id int64) error
-	DeleteAllStartups(ctx context.Context) error
+	// UpdateStartup changes a startup that userUUID owns or is an editor of.
+	UpdateStartup(ctx context.Context, input Startup, userUUID string) (Startup, error)
+	// DeleteStartup soft deletes a startup. Only the owner may delete it unless
+	// anyOwner is set.
+	DeleteStartup(ctx context.Context, id int64, userUUID string, anyOwner bool) error
+	// RestoreStartup undoes a delete. Only the owner may restore a startup unless
+	// anyOwner is set.
+	RestoreStartup(ctx context.Context, id int64, userUUID string, anyOwner bool) (Startup, error)
+	ListDeletedStartups(ctx context.Context, page, limit int) ([]DeletedStartup, int64, error)
+	BulkDeleteStartups(ctx context.Context, filter admin.BulkFilter, dryRun bool) (admin.BulkResult, error)
 	GetStartupByID(ctx context.Context, id int64) (Startup, error)
-	ListStartups(ctx context.Context, page, limit int) ([]Startup, int64, error)
+	GetStartupBySlug(ctx context.Context, slug string) (Startup, error)
+	ListStartups(ctx context.Context, filters StartupFilters, page, limit int) ([]Startup, int64, error)
 	ListStartupsByUser(ctx context.Context, uuid string) ([]Startup, error)
+	SearchStartups(ctx context.Context, query string, page, limit int) ([]Startup, int64, error)
 }
 
+var ErrNotOwner = apperr.New(apperr.Forbidden, "only the startup owner can change it")
+
+// slugAttempts bounds how often CreateStartup retries a taken slug with a new suffix.
+const slugAttempts = 5
+
 type startupService struct {
-	repo StartupRepository
+	repo      StartupRepository
+	index     search.Index       // optional
+	feed      activity.Recorder  // optional
+	followers bookmarks.Notifier // optional
+	verifier  users.Verifier     // optional
+	newSuffix func() string
 }
 
-func NewStartupService(repo StartupRepository) StartupService {
-	return &startupService{repo: repo}
+// NewStartupService creates the startup service. index may be nil, in which case
+// search runs against Postgres, feed may be nil to leave new listings out of the
+// activity feed, followers may be nil to skip telling users who bookmarked a
+// startup about status changes, and verifier may be nil to let unverified owners list.
+func NewStartupService(repo StartupRepository, index search.Index, feed activity.Recorder, followers bookmarks.Notifier, verifier users.Verifier) StartupService {
+	return &startupService{repo: repo, index: index, feed: feed, followers: followers, verifier: verifier, newSuffix: newSlugSuffix}
+}
+
+// newSlugSuffix returns six random hex digits to tell apart startups with the same name.
+func newSlugSuffix() string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return hex.EncodeToString(b)
 }
 
 func (s *startupService) CreateStartup(ctx context.Context, input Startup) (Startup, error) {
+	var err error
+	if input.Country, input.Region, err = geo.Normalize(input.Country, input.Region); err != nil {
+		return Startup{}, err
+	}
+	if s.verifier != nil {
+		if err := s.verifier.RequireVerified(ctx, input.OwnerUUID); err != nil {
+			return Startup{}, err
+		}
+	}
 	if input.Status == "" {
 		input.Status = "failed"
 	}
-	return s.repo.CreateStartup(ctx, input)
+	created, err := s.createWithSlug(ctx, input)
+	if err != nil {
+		return Startup{}, err
+	}
+	indexStartup(ctx, s.index, created)
+	if s.feed != nil {
+		s.feed.Record(ctx, activity.StartupListed, created.ID)
+	}
+	return created, nil
 }
 
-func (s *startupService) UpdateStartup(ctx context.Context, input Startup) (Startup, error) {
+func (s *startupService) UpdateStartup(ctx context.Context, input Startup, userUUID string) (Startup, error) {
+	var err error
+	if input.Country, input.Region, err = geo.Normalize(input.Country, input.Region); err != nil {
+		return Startup{}, err
+	}
 	if input.Status == "" {
 		input.Status = "failed"
 	}
-	return s.repo.UpdateStartup(ctx, input)
+	previous, err := s.repo.GetStartupByID(ctx, input.ID)
+	if err != nil {
+		return Startup{}, err
+	}
+	role, err := memberRole(ctx, s.repo, previous, userUUID)
+	if err != nil {
+		return Startup{}, err
+	}
+	if role != MemberOwner && role != MemberEditor {
+		return Startup{}, ErrNotEditor
+	}
+	updated, err := s.repo.UpdateStartup(ctx, input)
+	if err != nil {
+		return Startup{}, err
+	}
+	indexStartup(ctx, s.index, updated)
+	if s.followers != nil && previous.Status != updated.Status {
+		s.followers.StatusChanged(ctx, updated.ID)
+	}
+	return updated, nil
 }
 
-func (s *startupService) DeleteStartup(ctx context.Context, id int64) error {
-	return s.repo.DeleteStartup(ctx, id)
+func (s *startupService) DeleteStartup(ctx context.Context, id int64, userUUID string, anyOwner bool) error {
+	if !anyOwner {
+		st, err := s.repo.GetStartupByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if st.OwnerUUID != userUUID {
+			return ErrNotOwner
+		}
+	}
+	if err := s.repo.DeleteStartup(ctx, id); err != nil {
+		return err
+	}
+	if s.index != nil {
+		logIndexErr(ctx, "delete", s.index.Delete(ctx, search.StartupsIndex, id))
+	}
+	return nil
+}
+
+func (s *startupService) RestoreStartup(ctx context.Context, id int64, userUUID string, anyOwner bool) (Startup, error) {
+	owner := userUUID
+	if anyOwner {
+		owner = ""
+	}
+	restored, err := s.repo.RestoreStartup(ctx, id, owner)
+	if err != nil {
+		return Startup{}, err
+	}
+	indexStartup(ctx, s.index, restored)
+	return restored, nil
+}
+
+func (s *startupService) ListDeletedStartups(ctx context.Context, page, limit int) ([]DeletedStartup, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	return s.repo.ListDeletedStartups(ctx, limit, (page-1)*limit)
+}
+
+// createWithSlug creates the startup under its slugified name, or a suffixed one when
+// another startup has it.
+func (s *startupService) createWithSlug(ctx context.Context, input Startup) (Startup, error) {
+	base := seo.Slugify(input.Name)
+	if base == "" {
+		base = "startup"
+	}
+	input.Slug = base
+	for attempt := 1; ; attempt++ {
+		created, err := s.repo.CreateStartup(ctx, input)
+		if !errors.Is(err, errSlugTaken) || attempt == slugAttempts {
+			return created, err
+		}
+		input.Slug = base + "-" + s.newSuffix()
+	}
+}
+
+func (s *startupService) GetStartupBySlug(ctx context.Context, slug string) (Startup, error) {
+	return s.repo.GetStartupBySlug(ctx, slug)
 }
 
 func (s *startupService) GetStartupByID(ctx context.Context, id int64) (Startup, error) {
 	return s.repo.GetStartupByID(ctx, id)
 }
 
-func (s *startupService) ListStartups(ctx context.Context, page, limit int) ([]Startup, int64, error) {
+func (s *startupService) ListStartups(ctx context.Context, filters StartupFilters, page, limit int) ([]Startup, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -50,13 +195,68 @@ func (s *startupService) ListStartups(ctx context.Context, page, limit int) ([]S
 		limit = 10
 	}
 	offset := (page - 1) * limit
-	return s.repo.ListStartups(ctx, limit, offset)
+	return s.repo.ListStartups(ctx, filters, limit, offset)
 }
 
-func (s *startupService) DeleteAllStartups(ctx context.Context) error {
-	return s.repo.DeleteAllStartups(ctx)
+// SearchStartups ranks with the search engine when configured and loads the hits from
+// Postgres; without an engine, or if it fails, it runs Postgres full-text search.
+func (s *startupService) SearchStartups(ctx context.Context, query string, page, limit int) ([]Startup, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	if s.index != nil {
+		ids, total, err := s.index.Search(ctx, search.StartupsIndex, query, limit, offset)
+		if err == nil {
+			if len(ids) == 0 {
+				return []Startup{}, total, nil
+			}
+			list, err := s.repo.GetStartupsByIDs(ctx, ids)
+			return list, total, err
+		}
+		logIndexErr(ctx, "search", err)
+	}
+	return s.repo.SearchStartups(ctx, query, limit, offset)
+}
+
+// BulkDeleteStartups soft deletes the startups matching filter, or on a dry run only
+// counts them, and drops the deleted ones from the search index.
+func (s *startupService) BulkDeleteStartups(ctx context.Context, filter admin.BulkFilter, dryRun bool) (admin.BulkResult, error) {
+	ids, err := s.repo.BulkDeleteStartups(ctx, filter, dryRun)
+	if err != nil {
+		return admin.BulkResult{}, err
+	}
+	if !dryRun && s.index != nil && len(ids) > 0 {
+		logIndexErr(ctx, "bulk delete", s.index.Delete(ctx, search.StartupsIndex, ids...))
+	}
+	return admin.BulkResult{Matched: int64(len(ids)), DryRun: dryRun}, nil
 }
 
 func (s *startupService) ListStartupsByUser(ctx context.Context, uuid string) ([]Startup, error) {
 	return s.repo.ListStartupsByUser(ctx, uuid)
 }
+
+// indexStartup publishes the startup to the search engine. Failures are logged and
+// never fail the write; the SQL fallback keeps search usable meanwhile.
+func indexStartup(ctx context.Context, index search.Index, st Startup) {
+	if index == nil {
+		return
+	}
+	logIndexErr(ctx, "upsert", index.Upsert(ctx, search.StartupsIndex, search.Document{
+		"id":          st.ID,
+		"name":        st.Name,
+		"description": st.Description,
+		"status":      st.Status,
+		"owner_uuid":  st.OwnerUUID,
+	}))
+}
+
+func logIndexErr(ctx context.Context, op string, err error) {
+	if err != nil {
+		log.Printf("[%s] search index %s startups: %v", requestid.FromContext(ctx), op, err)
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/admin"
 )
 
 type mockStartupRepository struct {
@@ -30,21 +32,57 @@ func (m *mockStartupRepository) DeleteStartup(ctx context.Context, id int64) err
 	return args.Error(0)
 }
 
+func (m *mockStartupRepository) RestoreStartup(ctx context.Context, id int64, ownerUUID string) (Startup, error) {
+	args := m.Called(ctx, id, ownerUUID)
+	startup, _ := args.Get(0).(Startup)
+	return startup, args.Error(1)
+}
+
+func (m *mockStartupRepository) ListDeletedStartups(ctx context.Context, limit, offset int) ([]DeletedStartup, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	startups, _ := args.Get(0).([]DeletedStartup)
+	return startups, args.Get(1).(int64), args.Error(2)
+}
+
 func (m *mockStartupRepository) GetStartupByID(ctx context.Context, id int64) (Startup, error) {
 	args := m.Called(ctx, id)
 	startup, _ := args.Get(0).(Startup)
 	return startup, args.Error(1)
 }
 
-func (m *mockStartupRepository) ListStartups(ctx context.Context, limit, offset int) ([]Startup, int64, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *mockStartupRepository) GetStartupBySlug(ctx context.Context, slug string) (Startup, error) {
+	args := m.Called(ctx, slug)
+	startup, _ := args.Get(0).(Startup)
+	return startup, args.Error(1)
+}
+
+func (m *mockStartupRepository) MemberRole(ctx context.Context, startupID int64, userUUID string) (string, error) {
+	args := m.Called(ctx, startupID, userUUID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockStartupRepository) ListStartups(ctx context.Context, filters StartupFilters, limit, offset int) ([]Startup, int64, error) {
+	args := m.Called(ctx, filters, limit, offset)
 	startups, _ := args.Get(0).([]Startup)
 	return startups, args.Get(1).(int64), args.Error(2)
 }
 
-func (m *mockStartupRepository) DeleteAllStartups(ctx context.Context) error {
-	args := m.Called(ctx)
-	return args.Error(0)
+func (m *mockStartupRepository) BulkDeleteStartups(ctx context.Context, filter admin.BulkFilter, dryRun bool) ([]int64, error) {
+	args := m.Called(ctx, filter, dryRun)
+	ids, _ := args.Get(0).([]int64)
+	return ids, args.Error(1)
+}
+
+func (m *mockStartupRepository) SearchStartups(ctx context.Context, query string, limit, offset int) ([]Startup, int64, error) {
+	args := m.Called(ctx, query, limit, offset)
+	startups, _ := args.Get(0).([]Startup)
+	return startups, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockStartupRepository) GetStartupsByIDs(ctx context.Context, ids []int64) ([]Startup, error) {
+	args := m.Called(ctx, ids)
+	startups, _ := args.Get(0).([]Startup)
+	return startups, args.Error(1)
 }
 
 func (m *mockStartupRepository) ListStartupsByUser(ctx context.Context, uuid string) ([]Startup, error) {
@@ -55,7 +93,7 @@ func (m *mockStartupRepository) ListStartupsByUser(ctx context.Context, uuid str
 
 func TestStartupService_CreateStartup_DefaultStatus(t *testing.T) {
 	repo := new(mockStartupRepository)
-	service := NewStartupService(repo)
+	service := NewStartupService(repo, nil, nil, nil, nil)
 
 	repo.On("CreateStartup", mock.Anything, mock.MatchedBy(func(input Startup) bool {
 		return input.Status == "failed" && input.Name == "Demo"
@@ -70,22 +108,101 @@ func TestStartupService_CreateStartup_DefaultStatus(t *testing.T) {
 
 func TestStartupService_UpdateStartup_DefaultStatus(t *testing.T) {
 	repo := new(mockStartupRepository)
-	service := NewStartupService(repo)
+	service := NewStartupService(repo, nil, nil, nil, nil)
 
+	repo.On("GetStartupByID", mock.Anything, int64(10)).Return(Startup{ID: 10, OwnerUUID: "owner-1"}, nil)
 	repo.On("UpdateStartup", mock.Anything, mock.MatchedBy(func(input Startup) bool {
 		return input.Status == "failed" && input.ID == 10
 	})).Return(Startup{ID: 10, Name: "Demo", Status: "failed"}, nil)
 
-	result, err := service.UpdateStartup(context.Background(), Startup{ID: 10, Name: "Demo"})
+	result, err := service.UpdateStartup(context.Background(), Startup{ID: 10, Name: "Demo"}, "owner-1")
 
 	require.NoError(t, err)
 	require.Equal(t, "failed", result.Status)
 	repo.AssertExpectations(t)
 }
 
+func TestStartupService_UpdateStartup_NotOwner(t *testing.T) {
+	repo := new(mockStartupRepository)
+	service := NewStartupService(repo, nil, nil, nil, nil)
+
+	repo.On("GetStartupByID", mock.Anything, int64(10)).Return(Startup{ID: 10, OwnerUUID: "owner-1"}, nil)
+
+	repo.On("MemberRole", mock.Anything, int64(10), "someone-else").Return("", nil)
+	repo.On("MemberRole", mock.Anything, int64(10), "viewer-1").Return(MemberViewer, nil)
+
+	_, err := service.UpdateStartup(context.Background(), Startup{ID: 10, Name: "Demo"}, "someone-else")
+	require.ErrorIs(t, err, ErrNotEditor)
+	_, err = service.UpdateStartup(context.Background(), Startup{ID: 10, Name: "Demo"}, "viewer-1")
+	require.ErrorIs(t, err, ErrNotEditor)
+
+	repo.AssertNotCalled(t, "UpdateStartup", mock.Anything, mock.Anything)
+}
+
+func TestStartupService_UpdateStartup_Editor(t *testing.T) {
+	repo := new(mockStartupRepository)
+	service := NewStartupService(repo, nil, nil, nil, nil)
+
+	repo.On("GetStartupByID", mock.Anything, int64(10)).Return(Startup{ID: 10, OwnerUUID: "owner-1"}, nil)
+	repo.On("MemberRole", mock.Anything, int64(10), "editor-1").Return(MemberEditor, nil)
+	repo.On("UpdateStartup", mock.Anything, mock.Anything).Return(Startup{ID: 10, OwnerUUID: "owner-1", Name: "Renamed"}, nil)
+
+	result, err := service.UpdateStartup(context.Background(), Startup{ID: 10, Name: "Renamed"}, "editor-1")
+
+	require.NoError(t, err)
+	require.Equal(t, "owner-1", result.OwnerUUID)
+	repo.AssertExpectations(t)
+}
+
+func TestStartupService_DeleteStartup_NotOwner(t *testing.T) {
+	repo := new(mockStartupRepository)
+	service := NewStartupService(repo, nil, nil, nil, nil)
+
+	repo.On("GetStartupByID", mock.Anything, int64(10)).Return(Startup{ID: 10, OwnerUUID: "owner-1"}, nil)
+	repo.On("DeleteStartup", mock.Anything, int64(10)).Return(nil)
+
+	require.ErrorIs(t, service.DeleteStartup(context.Background(), 10, "editor-1", false), ErrNotOwner)
+	repo.AssertNotCalled(t, "DeleteStartup", mock.Anything, mock.Anything)
+	require.NoError(t, service.DeleteStartup(context.Background(), 10, "owner-1", false))
+	repo.AssertExpectations(t)
+}
+
+func TestStartupService_CreateStartup_Slug(t *testing.T) {
+	repo := new(mockStartupRepository)
+	service := NewStartupService(repo, nil, nil, nil, nil)
+	service.(*startupService).newSuffix = func() string { return "a1b2c3" }
+
+	repo.On("CreateStartup", mock.Anything, mock.MatchedBy(func(input Startup) bool {
+		return input.Slug == "cafe-deja-vu"
+	})).Return(Startup{}, errSlugTaken).Once()
+	repo.On("CreateStartup", mock.Anything, mock.MatchedBy(func(input Startup) bool {
+		return input.Slug == "cafe-deja-vu-a1b2c3"
+	})).Return(Startup{ID: 2, Slug: "cafe-deja-vu-a1b2c3"}, nil).Once()
+
+	result, err := service.CreateStartup(context.Background(), Startup{Name: "Café Déjà Vu!"})
+
+	require.NoError(t, err)
+	require.Equal(t, "cafe-deja-vu-a1b2c3", result.Slug)
+	repo.AssertExpectations(t)
+}
+
+func TestStartupService_CreateStartup_SlugFallback(t *testing.T) {
+	repo := new(mockStartupRepository)
+	service := NewStartupService(repo, nil, nil, nil, nil)
+
+	repo.On("CreateStartup", mock.Anything, mock.MatchedBy(func(input Startup) bool {
+		return input.Slug == "startup"
+	})).Return(Startup{ID: 3, Slug: "startup"}, nil)
+
+	_, err := service.CreateStartup(context.Background(), Startup{Name: "स्टार्टअप"})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
 // func TestStartupService_ListStartups_Pagination(t *testing.T) {
 // 	repo := new(mockStartupRepository)
-// 	service := NewStartupService(repo)
+// 	service := NewStartupService(repo, nil, nil)
 
 // 	repo.On("ListStartups", mock.Anything, 10, 0).Return([]Startup{}, int64(0), nil)
 
@@ -97,7 +214,7 @@ func TestStartupService_UpdateStartup_DefaultStatus(t *testing.T) {
 
 func TestStartupService_GetStartup_ErrorPropagation(t *testing.T) {
 	repo := new(mockStartupRepository)
-	service := NewStartupService(repo)
+	service := NewStartupService(repo, nil, nil, nil, nil)
 
 	repo.On("GetStartupByID", mock.Anything, int64(99)).Return(Startup{}, ErrStartupNotFound)
 
@@ -109,12 +226,41 @@ func TestStartupService_GetStartup_ErrorPropagation(t *testing.T) {
 
 func TestStartupService_DeleteStartup_ErrorPropagation(t *testing.T) {
 	repo := new(mockStartupRepository)
-	service := NewStartupService(repo)
+	service := NewStartupService(repo, nil, nil, nil, nil)
 
 	repo.On("DeleteStartup", mock.Anything, int64(42)).Return(errors.New("boom"))
 
-	err := service.DeleteStartup(context.Background(), 42)
+	err := service.DeleteStartup(context.Background(), 42, "admin", true)
 
 	require.EqualError(t, err, "boom")
 	repo.AssertExpectations(t)
 }
+
+func TestStartupService_RestoreStartup(t *testing.T) {
+	repo := new(mockStartupRepository)
+	service := NewStartupService(repo, nil, nil, nil, nil)
+
+	repo.On("RestoreStartup", mock.Anything, int64(42), "owner").Return(Startup{ID: 42}, nil)
+	repo.On("RestoreStartup", mock.Anything, int64(43), "").Return(Startup{ID: 43}, nil)
+
+	_, err := service.RestoreStartup(context.Background(), 42, "owner", false)
+	require.NoError(t, err)
+	// Admins restore regardless of owner
+	_, err = service.RestoreStartup(context.Background(), 43, "admin", true)
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestStartupService_SearchStartups_WithoutIndex(t *testing.T) {
+	repo := new(mockStartupRepository)
+	service := NewStartupService(repo, nil, nil, nil, nil)
+
+	repo.On("SearchStartups", mock.Anything, "ai", 10, 0).Return([]Startup{{ID: 1}}, int64(1), nil)
+
+	list, total, err := service.SearchStartups(context.Background(), "ai", 0, 0)
+
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Len(t, list, 1)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,156 @@
+package startups
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/response"
+	"grveyard/pkg/search"
+)
+
+var (
+	ErrNotSold          = apperr.New(apperr.InvalidStartupStatus, "only sold startups can be transferred")
+	ErrNewOwnerNotFound = apperr.New(apperr.UserNotFound, "new owner not found")
+	ErrTransferToSelf   = response.InvalidField(apperr.InvalidRequest, "new_owner_uuid", "ne", "the startup already belongs to this user")
+)
+
+// OwnershipTransfer records a startup changing hands after a sale.
+type OwnershipTransfer struct {
+	ID            int64     `json:"id"`
+	StartupID     int64     `json:"startup_id"`
+	FromUUID      string    `json:"from_uuid"`
+	ToUUID        string    `json:"to_uuid"`
+	TransferredAt time.Time `json:"transferred_at"`
+}
+
+type TransferRepository interface {
+	// Transfer moves the startup from fromUUID to toUUID, removes its members and
+	// records it. It fails with ErrNotOwner if fromUUID no longer owns the startup
+	// and ErrNewOwnerNotFound if toUUID is not an active user.
+	Transfer(ctx context.Context, startupID int64, fromUUID, toUUID string) (OwnershipTransfer, error)
+	// ListTransfers returns the startup's ownership history, oldest first.
+	ListTransfers(ctx context.Context, startupID int64) ([]OwnershipTransfer, error)
+}
+
+// TransferService hands sold startups over to their buyers.
+type TransferService struct {
+	repo     TransferRepository
+	startups StartupRepository
+	index    search.Index // optional
+}
+
+// NewTransferService creates the transfer service. index may be nil; otherwise the
+// startup's new owner is published to it.
+func NewTransferService(repo TransferRepository, startups StartupRepository, index search.Index) *TransferService {
+	return &TransferService{repo: repo, startups: startups, index: index}
+}
+
+// Transfer makes newOwnerUUID the owner of a sold startup owned by userUUID.
+func (s *TransferService) Transfer(ctx context.Context, startupID int64, userUUID, newOwnerUUID string) (OwnershipTransfer, error) {
+	st, err := s.startups.GetStartupByID(ctx, startupID)
+	if err != nil {
+		return OwnershipTransfer{}, err
+	}
+	if st.OwnerUUID != userUUID {
+		return OwnershipTransfer{}, ErrNotOwner
+	}
+	if st.Status != "sold" {
+		return OwnershipTransfer{}, ErrNotSold
+	}
+	if newOwnerUUID == userUUID {
+		return OwnershipTransfer{}, ErrTransferToSelf
+	}
+
+	transfer, err := s.repo.Transfer(ctx, startupID, userUUID, newOwnerUUID)
+	if err != nil {
+		return OwnershipTransfer{}, err
+	}
+	st.OwnerUUID = newOwnerUUID
+	indexStartup(ctx, s.index, st)
+	return transfer, nil
+}
+
+func (s *TransferService) History(ctx context.Context, startupID int64) ([]OwnershipTransfer, error) {
+	if _, err := s.startups.GetStartupByID(ctx, startupID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTransfers(ctx, startupID)
+}
+
+type postgresTransferRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresTransferRepository(pool *pgxpool.Pool) TransferRepository {
+	return &postgresTransferRepository{pool: pool}
+}
+
+func (r *postgresTransferRepository) Transfer(ctx context.Context, startupID int64, fromUUID, toUUID string) (OwnershipTransfer, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return OwnershipTransfer{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM users WHERE uuid = $1 AND is_deleted = false AND banned_at IS NULL)`,
+		toUUID).Scan(&exists); err != nil {
+		return OwnershipTransfer{}, err
+	}
+	if !exists {
+		return OwnershipTransfer{}, ErrNewOwnerNotFound
+	}
+
+	// The owner check in the WHERE clause guards against a concurrent transfer.
+	cmd, err := tx.Exec(ctx, `
+		UPDATE startups SET owner_uuid = $3, updated_at = NOW()
+		WHERE id = $1 AND owner_uuid = $2 AND is_deleted = false`, startupID, fromUUID, toUUID)
+	if err != nil {
+		return OwnershipTransfer{}, err
+	}
+	if cmd.RowsAffected() == 0 {
+		return OwnershipTransfer{}, ErrNotOwner
+	}
+
+	// The new owner picks their own team, so the seller's editors and viewers
+	// lose access along with the seller
+	if _, err := tx.Exec(ctx, `DELETE FROM startup_members WHERE startup_id = $1`, startupID); err != nil {
+		return OwnershipTransfer{}, err
+	}
+
+	var t OwnershipTransfer
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO startup_ownership_transfers (startup_id, from_uuid, to_uuid)
+		VALUES ($1, $2, $3)
+		RETURNING id, startup_id, from_uuid, to_uuid, transferred_at`, startupID, fromUUID, toUUID,
+	).Scan(&t.ID, &t.StartupID, &t.FromUUID, &t.ToUUID, &t.TransferredAt); err != nil {
+		return OwnershipTransfer{}, err
+	}
+	return t, tx.Commit(ctx)
+}
+
+func (r *postgresTransferRepository) ListTransfers(ctx context.Context, startupID int64) ([]OwnershipTransfer, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, startup_id, from_uuid, to_uuid, transferred_at
+		FROM startup_ownership_transfers
+		WHERE startup_id = $1
+		ORDER BY transferred_at, id`, startupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []OwnershipTransfer{}
+	for rows.Next() {
+		var t OwnershipTransfer
+		if err := rows.Scan(&t.ID, &t.StartupID, &t.FromUUID, &t.ToUUID, &t.TransferredAt); err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,95 @@
+package startups
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+type transferRequest struct {
+	NewOwnerUUID string `json:"new_owner_uuid" binding:"required,max=64"`
+}
+
+type TransferHandler struct {
+	service *TransferService
+}
+
+func NewTransferHandler(service *TransferService) *TransferHandler {
+	return &TransferHandler{service: service}
+}
+
+func (h *TransferHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/startups/:id/transfer", auth.Required(), h.transfer)
+	router.GET("/startups/:id/ownership-history", h.history)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *TransferHandler) Operations() []openapi.Operation {
+	params := []openapi.Param{openapi.Path("id", "integer", "Startup ID")}
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/startups/:id/transfer",
+			Tag:         "startups",
+			Summary:     "Transfer a startup to its buyer",
+			Description: "Makes new_owner_uuid the owner of a sold startup and records the transfer in its ownership history. Only the current owner may transfer it, and only once it is marked sold (INVALID_STARTUP_STATUS otherwise).",
+			Params:      params,
+			Request:     transferRequest{},
+			Response:    OwnershipTransfer{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/startups/:id/ownership-history",
+			Tag:         "startups",
+			Summary:     "Get a startup's ownership history",
+			Description: "Every transfer of the startup, oldest first",
+			Params:      params,
+			Response:    []OwnershipTransfer{},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+	}
+}
+
+func (h *TransferHandler) transfer(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
+		return
+	}
+
+	var req transferRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	transfer, err := h.service.Transfer(c.Request.Context(), id, auth.UserID(c), req.NewOwnerUUID)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "startup transferred", transfer)
+}
+
+func (h *TransferHandler) history(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
+		return
+	}
+
+	list, err := h.service.History(c.Request.Context(), id)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "ownership history listed", list)
+}
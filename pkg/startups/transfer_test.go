@@ -0,0 +1,90 @@
+package startups
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+type fakeTransferRepository struct {
+	transfers []OwnershipTransfer
+}
+
+func (f *fakeTransferRepository) Transfer(_ context.Context, startupID int64, fromUUID, toUUID string) (OwnershipTransfer, error) {
+	if toUUID == "ghost" {
+		return OwnershipTransfer{}, ErrNewOwnerNotFound
+	}
+	t := OwnershipTransfer{ID: int64(len(f.transfers) + 1), StartupID: startupID, FromUUID: fromUUID, ToUUID: toUUID}
+	f.transfers = append(f.transfers, t)
+	return t, nil
+}
+
+func (f *fakeTransferRepository) ListTransfers(_ context.Context, startupID int64) ([]OwnershipTransfer, error) {
+	return f.transfers, nil
+}
+
+func TestTransferService_Transfer(t *testing.T) {
+	repo := &fakeTransferRepository{}
+	startups := new(mockStartupRepository)
+	startups.On("GetStartupByID", mock.Anything, int64(1)).Return(Startup{ID: 1, OwnerUUID: "seller", Status: "sold"}, nil)
+	startups.On("GetStartupByID", mock.Anything, int64(2)).Return(Startup{ID: 2, OwnerUUID: "seller", Status: "failed"}, nil)
+	startups.On("GetStartupByID", mock.Anything, int64(3)).Return(Startup{}, ErrStartupNotFound)
+	svc := NewTransferService(repo, startups, nil)
+	ctx := context.Background()
+
+	_, err := svc.Transfer(ctx, 1, "someone-else", "buyer")
+	require.ErrorIs(t, err, ErrNotOwner)
+	_, err = svc.Transfer(ctx, 2, "seller", "buyer")
+	require.ErrorIs(t, err, ErrNotSold)
+	_, err = svc.Transfer(ctx, 3, "seller", "buyer")
+	require.ErrorIs(t, err, ErrStartupNotFound)
+	_, err = svc.Transfer(ctx, 1, "seller", "seller")
+	require.ErrorIs(t, err, ErrTransferToSelf)
+	_, err = svc.Transfer(ctx, 1, "seller", "ghost")
+	require.ErrorIs(t, err, ErrNewOwnerNotFound)
+	require.Empty(t, repo.transfers)
+
+	transfer, err := svc.Transfer(ctx, 1, "seller", "buyer")
+	require.NoError(t, err)
+	require.Equal(t, OwnershipTransfer{ID: 1, StartupID: 1, FromUUID: "seller", ToUUID: "buyer"}, transfer)
+}
+
+func TestTransfer_RevokesPreviousEditors(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+	pool := setupTestPool(t)
+
+	startups := NewPostgresStartupRepository(pool)
+	members := NewPostgresMemberRepository(pool)
+	ctx := context.Background()
+	sellerUUID := insertTestUserUUID(t, pool, "Seller")
+	editorUUID := insertTestUserUUID(t, pool, "Editor")
+	buyerUUID := insertTestUserUUID(t, pool, "Buyer")
+
+	st, err := startups.CreateStartup(ctx, Startup{Name: "Handover", Slug: "handover", OwnerUUID: sellerUUID, Status: "sold"})
+	require.NoError(t, err)
+	_, err = members.AddMember(ctx, Member{StartupID: st.ID, UserUUID: editorUUID, Role: MemberEditor, AddedBy: sellerUUID})
+	require.NoError(t, err)
+
+	_, err = NewTransferService(NewPostgresTransferRepository(pool), startups, nil).Transfer(ctx, st.ID, sellerUUID, buyerUUID)
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(testhelpers.AuthAs(editorUUID, "founder"))
+	NewStartupHandler(NewStartupService(startups, nil, nil, nil, nil)).RegisterRoutes(r)
+
+	req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/startups/%d", st.ID), strings.NewReader(`{"name":"Hijacked"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,210 @@
+package startups
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/pagination"
+)
+
+const (
+	VerificationPending  = "pending"
+	VerificationApproved = "approved"
+	VerificationRejected = "rejected"
+)
+
+var (
+	ErrVerificationPending  = apperr.New(apperr.VerificationPending, "the startup already has a pending verification request")
+	ErrVerificationNotFound = apperr.New(apperr.VerificationNotFound, "verification request not found")
+	ErrAlreadyVerified      = apperr.New(apperr.AlreadyVerified, "startup is already verified")
+)
+
+// VerificationRequest is a founder's proof that they run the startup, waiting for or
+// decided by an admin. Approving one sets the startup's verified flag.
+type VerificationRequest struct {
+	ID          int64      `json:"id"`
+	StartupID   int64      `json:"startup_id"`
+	SubmittedBy string     `json:"submitted_by"`
+	ProofURL    string     `json:"proof_url"`
+	Notes       string     `json:"notes"`
+	Status      string     `json:"status"`
+	ReviewNote  string     `json:"review_note"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type VerificationRepository interface {
+	// Submit stores a pending request. It fails with ErrVerificationPending if the
+	// startup already has one.
+	Submit(ctx context.Context, req VerificationRequest) (VerificationRequest, error)
+	// Latest returns the startup's most recent request, or ErrVerificationNotFound.
+	Latest(ctx context.Context, startupID int64) (VerificationRequest, error)
+	// List returns requests with the given status, or all when status is empty,
+	// oldest first, and the total count.
+	List(ctx context.Context, status string, limit, offset int) ([]VerificationRequest, int64, error)
+	// Decide approves or rejects the startup's pending request; approving also marks
+	// the startup verified. It fails with ErrVerificationNotFound if there is no
+	// pending request.
+	Decide(ctx context.Context, startupID int64, approve bool, note string) (VerificationRequest, error)
+}
+
+// VerificationService lets founders ask for the verified badge and admins grant it.
+type VerificationService struct {
+	repo     VerificationRepository
+	startups StartupRepository
+}
+
+func NewVerificationService(repo VerificationRepository, startups StartupRepository) *VerificationService {
+	return &VerificationService{repo: repo, startups: startups}
+}
+
+// Submit files a verification request for a startup owned by userUUID.
+func (s *VerificationService) Submit(ctx context.Context, startupID int64, userUUID, proofURL, notes string) (VerificationRequest, error) {
+	st, err := s.startups.GetStartupByID(ctx, startupID)
+	if err != nil {
+		return VerificationRequest{}, err
+	}
+	if st.OwnerUUID != userUUID {
+		return VerificationRequest{}, ErrNotOwner
+	}
+	if st.Verified {
+		return VerificationRequest{}, ErrAlreadyVerified
+	}
+	return s.repo.Submit(ctx, VerificationRequest{StartupID: startupID, SubmittedBy: userUUID, ProofURL: proofURL, Notes: notes})
+}
+
+// Status returns the latest verification request of a startup owned by userUUID.
+func (s *VerificationService) Status(ctx context.Context, startupID int64, userUUID string) (VerificationRequest, error) {
+	st, err := s.startups.GetStartupByID(ctx, startupID)
+	if err != nil {
+		return VerificationRequest{}, err
+	}
+	if st.OwnerUUID != userUUID {
+		return VerificationRequest{}, ErrNotOwner
+	}
+	return s.repo.Latest(ctx, startupID)
+}
+
+// List returns the review queue, optionally only requests in status.
+func (s *VerificationService) List(ctx context.Context, status string, p pagination.Params) ([]VerificationRequest, int64, error) {
+	return s.repo.List(ctx, status, p.Limit, p.Offset())
+}
+
+// Approve grants the startup's pending request and marks the startup verified.
+func (s *VerificationService) Approve(ctx context.Context, startupID int64, note string) (VerificationRequest, error) {
+	return s.repo.Decide(ctx, startupID, true, note)
+}
+
+// Reject turns down the startup's pending request; the founder may submit another.
+func (s *VerificationService) Reject(ctx context.Context, startupID int64, reason string) (VerificationRequest, error) {
+	return s.repo.Decide(ctx, startupID, false, reason)
+}
+
+type postgresVerificationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresVerificationRepository(pool *pgxpool.Pool) VerificationRepository {
+	return &postgresVerificationRepository{pool: pool}
+}
+
+const verificationColumns = `id, startup_id, submitted_by, proof_url, notes, status, review_note, reviewed_at, created_at`
+
+func scanVerification(row pgx.Row) (VerificationRequest, error) {
+	var v VerificationRequest
+	err := row.Scan(&v.ID, &v.StartupID, &v.SubmittedBy, &v.ProofURL, &v.Notes, &v.Status, &v.ReviewNote, &v.ReviewedAt, &v.CreatedAt)
+	return v, err
+}
+
+func (r *postgresVerificationRepository) Submit(ctx context.Context, req VerificationRequest) (VerificationRequest, error) {
+	v, err := scanVerification(r.pool.QueryRow(ctx, `
+		INSERT INTO startup_verifications (startup_id, submitted_by, proof_url, notes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+verificationColumns, req.StartupID, req.SubmittedBy, req.ProofURL, req.Notes))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_startup_verifications_pending" {
+		return VerificationRequest{}, ErrVerificationPending
+	}
+	return v, err
+}
+
+func (r *postgresVerificationRepository) Latest(ctx context.Context, startupID int64) (VerificationRequest, error) {
+	v, err := scanVerification(r.pool.QueryRow(ctx, `
+		SELECT `+verificationColumns+`
+		FROM startup_verifications
+		WHERE startup_id = $1
+		ORDER BY id DESC
+		LIMIT 1`, startupID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return VerificationRequest{}, ErrVerificationNotFound
+	}
+	return v, err
+}
+
+func (r *postgresVerificationRepository) List(ctx context.Context, status string, limit, offset int) ([]VerificationRequest, int64, error) {
+	var total int64
+	if err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM startup_verifications WHERE $1::text = '' OR status = $1`, status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+verificationColumns+`
+		FROM startup_verifications
+		WHERE $1::text = '' OR status = $1
+		ORDER BY id
+		LIMIT $2 OFFSET $3`, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	list := []VerificationRequest{}
+	for rows.Next() {
+		v, err := scanVerification(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, v)
+	}
+	return list, total, rows.Err()
+}
+
+func (r *postgresVerificationRepository) Decide(ctx context.Context, startupID int64, approve bool, note string) (VerificationRequest, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return VerificationRequest{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	status := VerificationRejected
+	if approve {
+		status = VerificationApproved
+	}
+	v, err := scanVerification(tx.QueryRow(ctx, `
+		UPDATE startup_verifications
+		SET status = $2, review_note = $3, reviewed_at = NOW()
+		WHERE startup_id = $1 AND status = 'pending'
+		RETURNING `+verificationColumns, startupID, status, note))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return VerificationRequest{}, ErrVerificationNotFound
+	}
+	if err != nil {
+		return VerificationRequest{}, err
+	}
+
+	if approve {
+		if _, err := tx.Exec(ctx, `
+			UPDATE startups SET verified_at = NOW(), updated_at = NOW()
+			WHERE id = $1 AND verified_at IS NULL`, startupID); err != nil {
+			return VerificationRequest{}, err
+		}
+	}
+	return v, tx.Commit(ctx)
+}
@@ -0,0 +1,239 @@
+package startups
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/admin"
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+type verificationRequest struct {
+	ProofURL string `json:"proof_url" binding:"required,url,max=500"`
+	Notes    string `json:"notes" binding:"max=2000"`
+}
+
+type approveVerificationRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+type rejectVerificationRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// VerificationHandler serves founders' verification requests.
+type VerificationHandler struct {
+	service *VerificationService
+}
+
+func NewVerificationHandler(service *VerificationService) *VerificationHandler {
+	return &VerificationHandler{service: service}
+}
+
+func (h *VerificationHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/startups/:id/verification", auth.Required(), h.submit)
+	router.GET("/startups/:id/verification", auth.Required(), h.status)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *VerificationHandler) Operations() []openapi.Operation {
+	params := []openapi.Param{openapi.Path("id", "integer", "Startup ID")}
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/startups/:id/verification",
+			Tag:         "startups",
+			Summary:     "Request verification",
+			Description: "Submits proof that the owner runs the startup, such as a company registry entry, for an admin to review. A startup has at most one pending request (VERIFICATION_PENDING); verified startups cannot ask again (ALREADY_VERIFIED).",
+			Params:      params,
+			Request:     verificationRequest{},
+			Response:    VerificationRequest{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/startups/:id/verification",
+			Tag:         "startups",
+			Summary:     "Get verification status",
+			Description: "The startup's latest verification request, with the admin's note once decided. Only the owner may see it.",
+			Params:      params,
+			Response:    VerificationRequest{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+	}
+}
+
+func (h *VerificationHandler) submit(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
+		return
+	}
+
+	var req verificationRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	v, err := h.service.Submit(c.Request.Context(), id, auth.UserID(c), req.ProofURL, req.Notes)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusCreated, true, "verification requested", v)
+}
+
+func (h *VerificationHandler) status(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
+		return
+	}
+
+	v, err := h.service.Status(c.Request.Context(), id, auth.UserID(c))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "verification fetched", v)
+}
+
+// VerificationReviewHandler serves the verification queue under /admin.
+type VerificationReviewHandler struct {
+	service *VerificationService
+	token   string
+}
+
+// NewVerificationReviewHandler serves the review routes to callers presenting token
+// as a bearer token, like the other admin endpoints, and to admins signed in with
+// their own access token.
+func NewVerificationReviewHandler(service *VerificationService, token string) *VerificationReviewHandler {
+	return &VerificationReviewHandler{service: service, token: token}
+}
+
+func (h *VerificationReviewHandler) RegisterRoutes(router *gin.Engine) {
+	group := router.Group("/admin/startups", admin.RequireTokenOrPermission(h.token, auth.PermVerifyStartups))
+	group.GET("/verifications", h.list)
+	group.POST("/:id/verify", h.approve)
+	group.POST("/:id/reject-verification", h.reject)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *VerificationReviewHandler) Operations() []openapi.Operation {
+	params := []openapi.Param{openapi.Path("id", "integer", "Startup ID")}
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/admin/startups/verifications",
+			Tag:         "admin",
+			Summary:     "Verification queue",
+			Description: "Verification requests oldest first, optionally filtered by status",
+			Params: []openapi.Param{
+				openapi.Query("status", "string", "pending, approved or rejected (default all)", false),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 10)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[VerificationRequest]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/admin/startups/:id/verify",
+			Tag:         "admin",
+			Summary:     "Verify a startup",
+			Description: "Approves the startup's pending verification request and sets its verified flag. VERIFICATION_NOT_FOUND if nothing is pending.",
+			Params:      params,
+			Request:     approveVerificationRequest{},
+			Response:    VerificationRequest{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/admin/startups/:id/reject-verification",
+			Tag:         "admin",
+			Summary:     "Reject a verification request",
+			Description: "Turns down the startup's pending verification request with a reason the founder can see. They may submit new proof afterwards.",
+			Params:      params,
+			Request:     rejectVerificationRequest{},
+			Response:    VerificationRequest{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+	}
+}
+
+func (h *VerificationReviewHandler) list(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", VerificationPending, VerificationApproved, VerificationRejected:
+	default:
+		response.SendError(c, response.InvalidField(apperr.InvalidRequest, "status", "oneof", "invalid verification status"))
+		return
+	}
+
+	p, err := pagination.FromRequest(c, pagination.DefaultLimit)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	list, total, err := h.service.List(c.Request.Context(), status, p)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "verification requests listed", list, total, p)
+}
+
+func (h *VerificationReviewHandler) approve(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
+		return
+	}
+
+	// The note is optional, so an empty body is fine
+	var req approveVerificationRequest
+	if c.Request.ContentLength != 0 && !validation.BindJSON(c, &req) {
+		return
+	}
+
+	v, err := h.service.Approve(c.Request.Context(), id, req.Note)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "startup verified", v)
+}
+
+func (h *VerificationReviewHandler) reject(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid startup id"))
+		return
+	}
+
+	var req rejectVerificationRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	v, err := h.service.Reject(c.Request.Context(), id, req.Reason)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "verification rejected", v)
+}
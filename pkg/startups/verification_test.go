@@ -0,0 +1,131 @@
+package startups
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+// fakeVerificationRepository keeps requests in memory, newest last.
+type fakeVerificationRepository struct {
+	requests []VerificationRequest
+}
+
+func (f *fakeVerificationRepository) Submit(_ context.Context, req VerificationRequest) (VerificationRequest, error) {
+	for _, v := range f.requests {
+		if v.StartupID == req.StartupID && v.Status == VerificationPending {
+			return VerificationRequest{}, ErrVerificationPending
+		}
+	}
+	req.ID, req.Status = int64(len(f.requests)+1), VerificationPending
+	f.requests = append(f.requests, req)
+	return req, nil
+}
+
+func (f *fakeVerificationRepository) Latest(_ context.Context, startupID int64) (VerificationRequest, error) {
+	for i := len(f.requests) - 1; i >= 0; i-- {
+		if f.requests[i].StartupID == startupID {
+			return f.requests[i], nil
+		}
+	}
+	return VerificationRequest{}, ErrVerificationNotFound
+}
+
+func (f *fakeVerificationRepository) List(_ context.Context, status string, _, _ int) ([]VerificationRequest, int64, error) {
+	list := []VerificationRequest{}
+	for _, v := range f.requests {
+		if status == "" || v.Status == status {
+			list = append(list, v)
+		}
+	}
+	return list, int64(len(list)), nil
+}
+
+func (f *fakeVerificationRepository) Decide(_ context.Context, startupID int64, approve bool, note string) (VerificationRequest, error) {
+	for i, v := range f.requests {
+		if v.StartupID == startupID && v.Status == VerificationPending {
+			v.Status, v.ReviewNote = VerificationRejected, note
+			if approve {
+				v.Status = VerificationApproved
+			}
+			f.requests[i] = v
+			return v, nil
+		}
+	}
+	return VerificationRequest{}, ErrVerificationNotFound
+}
+
+func TestVerificationService_Submit(t *testing.T) {
+	repo := &fakeVerificationRepository{}
+	startups := new(mockStartupRepository)
+	startups.On("GetStartupByID", mock.Anything, int64(1)).Return(Startup{ID: 1, OwnerUUID: "owner"}, nil)
+	startups.On("GetStartupByID", mock.Anything, int64(2)).Return(Startup{ID: 2, OwnerUUID: "owner", Verified: true}, nil)
+	svc := NewVerificationService(repo, startups)
+	ctx := context.Background()
+
+	_, err := svc.Submit(ctx, 1, "someone-else", "https://example.com/proof", "")
+	require.ErrorIs(t, err, ErrNotOwner)
+	_, err = svc.Submit(ctx, 2, "owner", "https://example.com/proof", "")
+	require.ErrorIs(t, err, ErrAlreadyVerified)
+
+	_, err = svc.Status(ctx, 1, "owner")
+	require.ErrorIs(t, err, ErrVerificationNotFound)
+	v, err := svc.Submit(ctx, 1, "owner", "https://example.com/proof", "see page 2")
+	require.NoError(t, err)
+	require.Equal(t, "owner", v.SubmittedBy)
+	_, err = svc.Submit(ctx, 1, "owner", "https://example.com/proof", "")
+	require.ErrorIs(t, err, ErrVerificationPending)
+
+	_, err = svc.Reject(ctx, 1, "unreadable")
+	require.NoError(t, err)
+	status, err := svc.Status(ctx, 1, "owner")
+	require.NoError(t, err)
+	require.Equal(t, VerificationRejected, status.Status)
+	require.Equal(t, "unreadable", status.ReviewNote)
+	_, err = svc.Status(ctx, 1, "someone-else")
+	require.ErrorIs(t, err, ErrNotOwner)
+}
+
+func TestVerificationReviewHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &fakeVerificationRepository{requests: []VerificationRequest{{ID: 1, StartupID: 1, Status: VerificationPending}}}
+	svc := NewVerificationService(repo, new(mockStartupRepository))
+	r := gin.New()
+	r.Use(testhelpers.AuthAs("admin-uuid", "admin"))
+	NewVerificationReviewHandler(svc, "").RegisterRoutes(r)
+
+	do := func(method, path, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/admin/startups/verifications?status=pending", ""))
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/admin/startups/verifications?status=maybe", ""))
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/admin/startups/1/reject-verification", `{}`))
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/admin/startups/1/verify", ""))
+	require.Equal(t, VerificationApproved, repo.requests[0].Status)
+	require.Equal(t, http.StatusNotFound, do(http.MethodPost, "/admin/startups/1/verify", `{"note":"again"}`))
+}
+
+func TestVerificationReviewHandler_RequiresPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(testhelpers.AuthAs("user-uuid-1", "founder"))
+	NewVerificationReviewHandler(NewVerificationService(&fakeVerificationRepository{}, new(mockStartupRepository)), "").RegisterRoutes(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/startups/1/verify", nil))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,182 @@
+package startups
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/auth"
+)
+
+const (
+	// TrendingDays is how many days of views GET /startups/trending looks at.
+	TrendingDays = 7
+	// viewRetention is how long views are kept before PruneViews deletes them.
+	viewRetention = 90 * 24 * time.Hour
+	// maxPendingViews caps the views held between flushes; more are dropped.
+	maxPendingViews = 100000
+)
+
+// View is one viewer looking at a startup on a UTC day; repeat views that day count once.
+type View struct {
+	StartupID int64
+	Viewer    string // user UUID, or a hash of IP and user agent for anonymous viewers
+	Day       time.Time
+}
+
+// TrendingStartup is a startup with its views over the last TrendingDays days.
+type TrendingStartup struct {
+	Startup
+	Views int64 `json:"views"`
+}
+
+type ViewRepository interface {
+	// RecordViews stores views, ignoring ones already recorded.
+	RecordViews(ctx context.Context, views []View) error
+	// Trending returns up to limit startups viewed in the days up to and including
+	// today, ordered by velocity: each view weighs 1/(age in days + 1), so recent
+	// views count most.
+	Trending(ctx context.Context, today time.Time, days, limit int) ([]TrendingStartup, error)
+	// PruneViews deletes views from before cutoff.
+	PruneViews(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// ViewRecorder counts a startup page view; StartupHandler calls it on every fetch.
+type ViewRecorder interface {
+	Record(c *gin.Context, startupID int64)
+}
+
+// ViewService counts startup views without a write per page load: Record only adds
+// the view to an in-memory set, deduplicated per viewer and day, and Flush writes the
+// set in one statement. Flush runs as a background job, and once more on shutdown.
+type ViewService struct {
+	repo ViewRepository
+	now  func() time.Time
+
+	mu      sync.Mutex
+	pending map[View]struct{}
+	dropped int
+}
+
+func NewViewService(repo ViewRepository) *ViewService {
+	return &ViewService{repo: repo, now: time.Now, pending: map[View]struct{}{}}
+}
+
+func (s *ViewService) Record(c *gin.Context, startupID int64) {
+	viewer := auth.UserID(c)
+	if viewer == "" {
+		sum := sha256.Sum256([]byte(c.ClientIP() + "\x00" + c.Request.UserAgent()))
+		viewer = "anon:" + hex.EncodeToString(sum[:16])
+	}
+	v := View{StartupID: startupID, Viewer: viewer, Day: s.now().UTC().Truncate(24 * time.Hour)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pending[v]; !ok && len(s.pending) >= maxPendingViews {
+		s.dropped++
+		return
+	}
+	s.pending[v] = struct{}{}
+}
+
+// Flush writes the views recorded since the last flush. If the write fails they are
+// lost; view counts are best-effort.
+func (s *ViewService) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	pending, dropped := s.pending, s.dropped
+	s.pending, s.dropped = map[View]struct{}{}, 0
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		log.Printf("dropped %d startup views over the pending limit", dropped)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	views := make([]View, 0, len(pending))
+	for v := range pending {
+		views = append(views, v)
+	}
+	return s.repo.RecordViews(ctx, views)
+}
+
+// Trending returns the startups with the highest view velocity over the last
+// TrendingDays days.
+func (s *ViewService) Trending(ctx context.Context, limit int) ([]TrendingStartup, error) {
+	return s.repo.Trending(ctx, s.now().UTC().Truncate(24*time.Hour), TrendingDays, limit)
+}
+
+// PruneViews deletes views older than the retention period.
+func (s *ViewService) PruneViews(ctx context.Context) (int64, error) {
+	return s.repo.PruneViews(ctx, s.now().Add(-viewRetention))
+}
+
+type postgresViewRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresViewRepository(pool *pgxpool.Pool) ViewRepository {
+	return &postgresViewRepository{pool: pool}
+}
+
+func (r *postgresViewRepository) RecordViews(ctx context.Context, views []View) error {
+	ids := make([]int64, len(views))
+	viewers := make([]string, len(views))
+	days := make([]time.Time, len(views))
+	for i, v := range views {
+		ids[i], viewers[i], days[i] = v.StartupID, v.Viewer, v.Day
+	}
+	// The join skips startups deleted since the view
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO startup_views (startup_id, viewer, day)
+		SELECT v.startup_id, v.viewer, v.day
+		FROM unnest($1::int[], $2::text[], $3::date[]) AS v(startup_id, viewer, day)
+		JOIN startups s ON s.id = v.startup_id
+		ON CONFLICT DO NOTHING`, ids, viewers, days)
+	return err
+}
+
+func (r *postgresViewRepository) Trending(ctx context.Context, today time.Time, days, limit int) ([]TrendingStartup, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT s.id, s.name, s.slug, s.description, s.logo_url, s.owner_uuid, s.status, s.country, s.region, s.failure_reason, s.lifespan_months, s.peak_mrr, s.users_at_shutdown, s.verified_at IS NOT NULL, s.created_at,
+		       v.views
+		FROM (
+			SELECT startup_id, COUNT(*) AS views, SUM(1.0 / ($1::date - day + 1)) AS velocity
+			FROM startup_views
+			WHERE day > $1::date - $2::int
+			GROUP BY startup_id
+		) v
+		JOIN startups s ON s.id = v.startup_id
+		WHERE s.is_deleted = false
+		ORDER BY v.velocity DESC, v.views DESC, s.id DESC
+		LIMIT $3`, today, days, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []TrendingStartup{}
+	for rows.Next() {
+		var t TrendingStartup
+		s := &t.Startup
+		if err := rows.Scan(&s.ID, &s.Name, &s.Slug, &s.Description, &s.LogoURL, &s.OwnerUUID, &s.Status, &s.Country, &s.Region, &s.FailureReason, &s.LifespanMonths, &s.PeakMRR, &s.UsersAtShutdown, &s.Verified, &s.CreatedAt, &t.Views); err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+func (r *postgresViewRepository) PruneViews(ctx context.Context, cutoff time.Time) (int64, error) {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM startup_views WHERE day < $1::date`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return cmd.RowsAffected(), nil
+}
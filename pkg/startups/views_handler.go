@@ -0,0 +1,66 @@
+package startups
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+const (
+	DefaultTrendingLimit = 10
+	MaxTrendingLimit     = 50
+)
+
+type ViewHandler struct {
+	service *ViewService
+}
+
+func NewViewHandler(service *ViewService) *ViewHandler {
+	return &ViewHandler{service: service}
+}
+
+func (h *ViewHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/startups/trending", h.trending)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *ViewHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/startups/trending",
+			Tag:         "startups",
+			Summary:     "List trending startups",
+			Description: "Startups viewed most over the last 7 days, recent days weighing more. A viewer counts once per startup per day, and views show up here within a minute.",
+			Params: []openapi.Param{
+				openapi.Query("limit", "integer", "Number of startups, 1-50 (default 10)", false),
+			},
+			Response: []TrendingStartup{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+	}
+}
+
+func (h *ViewHandler) trending(c *gin.Context) {
+	limit := DefaultTrendingLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > MaxTrendingLimit {
+			response.SendError(c, response.InvalidField(apperr.InvalidRequest, "limit", "range", "limit must be between 1 and 50"))
+			return
+		}
+		limit = n
+	}
+
+	list, err := h.service.Trending(c.Request.Context(), limit)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "trending startups listed", list)
+}
@@ -0,0 +1,84 @@
+package startups
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+type fakeViewRepository struct {
+	recorded [][]View
+	today    time.Time
+	limit    int
+}
+
+func (f *fakeViewRepository) RecordViews(_ context.Context, views []View) error {
+	f.recorded = append(f.recorded, views)
+	return nil
+}
+
+func (f *fakeViewRepository) Trending(_ context.Context, today time.Time, _, limit int) ([]TrendingStartup, error) {
+	f.today, f.limit = today, limit
+	return []TrendingStartup{}, nil
+}
+
+func (f *fakeViewRepository) PruneViews(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestViewService_RecordAndFlush(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &fakeViewRepository{}
+	svc := NewViewService(repo)
+	svc.now = func() time.Time { return time.Date(2026, 5, 6, 23, 59, 0, 0, time.UTC) }
+
+	r := gin.New()
+	r.GET("/anon/:id", func(c *gin.Context) { svc.Record(c, 1) })
+	r.GET("/user/:id", testhelpers.AuthAs("user-uuid-1", "buyer"), func(c *gin.Context) { svc.Record(c, 1) })
+	view := func(path, userAgent string) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("User-Agent", userAgent)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	view("/anon/1", "firefox")
+	view("/anon/1", "firefox") // same client, same day
+	view("/anon/1", "safari")
+	view("/user/1", "firefox")
+	view("/user/1", "safari") // same user on another device
+
+	require.NoError(t, svc.Flush(context.Background()))
+	require.Len(t, repo.recorded, 1)
+	views := repo.recorded[0]
+	require.Len(t, views, 3)
+	require.Contains(t, views, View{StartupID: 1, Viewer: "user-uuid-1", Day: time.Date(2026, 5, 6, 0, 0, 0, 0, time.UTC)})
+
+	// Nothing new, nothing written
+	require.NoError(t, svc.Flush(context.Background()))
+	require.Len(t, repo.recorded, 1)
+}
+
+func TestViewHandler_Trending(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &fakeViewRepository{}
+	r := gin.New()
+	NewViewHandler(NewViewService(repo)).RegisterRoutes(r)
+
+	get := func(path string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+	require.Equal(t, http.StatusOK, get("/startups/trending"))
+	require.Equal(t, DefaultTrendingLimit, repo.limit)
+	require.Equal(t, http.StatusOK, get("/startups/trending?limit=3"))
+	require.Equal(t, 3, repo.limit)
+	require.Equal(t, http.StatusBadRequest, get("/startups/trending?limit=0"))
+	require.Equal(t, http.StatusBadRequest, get("/startups/trending?limit=51"))
+}
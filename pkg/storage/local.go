@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/config"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+// RoutePrefix is where LocalStorage serves presigned uploads and downloads.
+const RoutePrefix = "/storage/"
+
+// LocalStorage keeps files on disk for development and single-instance deployments.
+// Presigned URLs point back at this API and are checked with an HMAC, mirroring how
+// S3 presigned URLs behave.
+type LocalStorage struct {
+	dir       string
+	publicURL string
+	secret    []byte
+	maxUpload int64
+	now       func() time.Time
+}
+
+func NewLocalStorage(cfg config.StorageConfig) (*LocalStorage, error) {
+	if err := os.MkdirAll(cfg.LocalDir, 0o755); err != nil {
+		return nil, err
+	}
+	secret := []byte(cfg.SigningSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		log.Println("STORAGE_SIGNING_SECRET not set; presigned file links will stop working on restart")
+	}
+	return &LocalStorage{
+		dir:       cfg.LocalDir,
+		publicURL: cfg.PublicURL,
+		secret:    secret,
+		maxUpload: int64(cfg.MaxUploadBytes),
+		now:       time.Now,
+	}, nil
+}
+
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	dst := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temp file first so readers never see a half-written object
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if size >= 0 && n != size {
+		return errors.New("upload size does not match content length")
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// PresignUpload signs a PUT to this API. Unlike S3 the content type is part of the
+// signature, so the client must send exactly the returned headers.
+func (s *LocalStorage) PresignUpload(ctx context.Context, key, contentType string, expires time.Duration) (PresignedRequest, error) {
+	if err := ValidateKey(key); err != nil {
+		return PresignedRequest{}, err
+	}
+	if err := validateExpiry(expires); err != nil {
+		return PresignedRequest{}, err
+	}
+	expiresAt := s.now().Add(expires)
+	req := PresignedRequest{
+		Method:    http.MethodPut,
+		URL:       s.signedURL(http.MethodPut, key, contentType, expiresAt),
+		ExpiresAt: expiresAt,
+	}
+	if contentType != "" {
+		req.Headers = map[string]string{"Content-Type": contentType}
+	}
+	return req, nil
+}
+
+func (s *LocalStorage) PresignDownload(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+	if err := validateExpiry(expires); err != nil {
+		return "", err
+	}
+	return s.signedURL(http.MethodGet, key, "", s.now().Add(expires)), nil
+}
+
+func (s *LocalStorage) signedURL(method, key, contentType string, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("signature", s.signature(method, key, contentType, expires))
+	u := url.URL{Path: RoutePrefix + key}
+	return s.publicURL + u.EscapedPath() + "?" + q.Encode()
+}
+
+func (s *LocalStorage) signature(method, key, contentType, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(method + "\n" + key + "\n" + contentType + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks a presigned request's signature and expiry.
+func (s *LocalStorage) verify(method, key, contentType, expires, signature string) bool {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.now().Unix() > exp {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(s.signature(method, key, contentType, expires)))
+}
+
+// RegisterRoutes serves presigned local uploads and downloads. Request bodies on these
+// routes are not JSON and are capped by STORAGE_MAX_UPLOAD_BYTES instead of the API
+// body limit.
+func (s *LocalStorage) RegisterRoutes(router *gin.Engine) {
+	router.GET(RoutePrefix+"*key", s.download)
+	router.PUT(RoutePrefix+"*key", s.upload)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (s *LocalStorage) Operations() []openapi.Operation {
+	params := []openapi.Param{
+		openapi.Path("key", "string", "Object key, may contain slashes"),
+		openapi.Query("expires", "integer", "Unix time the link expires at", true),
+		openapi.Query("signature", "string", "Link signature", true),
+	}
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        RoutePrefix + "{key}",
+			Tag:         "storage",
+			Summary:     "Download a file",
+			Description: "Serves a file through a presigned link; only used with STORAGE_DRIVER=local",
+			Params:      params,
+			Raw:         true,
+			ContentType: "application/octet-stream",
+			Errors:      []int{http.StatusForbidden, http.StatusNotFound},
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        RoutePrefix + "{key}",
+			Tag:         "storage",
+			Summary:     "Upload a file",
+			Description: "Stores the raw request body through a presigned link; only used with STORAGE_DRIVER=local",
+			Params:      params,
+			Errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusRequestEntityTooLarge, http.StatusInternalServerError},
+		},
+	}
+}
+
+func (s *LocalStorage) download(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if ValidateKey(key) != nil || !s.verify(http.MethodGet, key, "", c.Query("expires"), c.Query("signature")) {
+		response.SendError(c, apperr.New(apperr.LinkExpired, "invalid or expired link"))
+		return
+	}
+
+	f, err := os.Open(s.path(key))
+	if err != nil {
+		response.SendError(c, apperr.New(apperr.FileNotFound, "file not found"))
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		response.SendError(c, apperr.New(apperr.FileNotFound, "file not found"))
+		return
+	}
+	http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), f)
+}
+
+func (s *LocalStorage) upload(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	contentType := c.GetHeader("Content-Type")
+	if ValidateKey(key) != nil || !s.verify(http.MethodPut, key, contentType, c.Query("expires"), c.Query("signature")) {
+		response.SendError(c, apperr.New(apperr.LinkExpired, "invalid or expired link"))
+		return
+	}
+	if c.Request.ContentLength > s.maxUpload {
+		response.SendError(c, apperr.New(apperr.PayloadTooLarge, "file too large"))
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, s.maxUpload)
+	if err := s.Put(c.Request.Context(), key, body, c.Request.ContentLength, contentType); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.SendError(c, apperr.New(apperr.PayloadTooLarge, "file too large"))
+			return
+		}
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "file uploaded", nil)
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"grveyard/pkg/awsauth"
+	"grveyard/pkg/config"
+)
+
+// S3Storage talks to S3 (or an S3-compatible store) over its REST API.
+type S3Storage struct {
+	bucket    string
+	region    string
+	endpoint  *url.URL
+	pathStyle bool
+	creds     awsauth.Credentials
+	client    *http.Client
+	now       func() time.Time
+}
+
+func NewS3Storage(cfg config.StorageConfig) (*S3Storage, error) {
+	endpoint := cfg.S3Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + cfg.S3Region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3_ENDPOINT %q", cfg.S3Endpoint)
+	}
+	creds := awsauth.CredentialsFromEnv()
+	if !creds.Valid() {
+		return nil, errors.New("S3 storage requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+
+	return &S3Storage{
+		bucket:    cfg.S3Bucket,
+		region:    cfg.S3Region,
+		endpoint:  u,
+		pathStyle: cfg.S3PathStyle,
+		creds:     creds,
+		client:    &http.Client{Timeout: 5 * time.Minute},
+		now:       time.Now,
+	}, nil
+}
+
+// objectURL addresses key virtual-hosted style (https://<bucket>.<endpoint>/<key>)
+// unless path style is forced.
+func (s *S3Storage) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	u.Path = "/" + key
+	if s.pathStyle {
+		u.Path = "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	u.RawPath = awsauth.EscapePath(u.Path)
+	return &u
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key).String(), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	// The body is streamed, so it is not hashed; TLS protects it in transit
+	req.Header.Set("X-Amz-Content-Sha256", awsauth.UnsignedPayload)
+	return s.do(req, http.StatusOK)
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", awsauth.PayloadHash(nil))
+	awsauth.Sign(req, awsauth.PayloadHash(nil), "s3", s.region, s.creds, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 GET: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("s3 GET %s: %s: %s", req.URL.Path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", awsauth.PayloadHash(nil))
+	return s.do(req, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+// PresignUpload signs a PUT. Only the host is part of the signature, so the returned
+// Content-Type header is advisory; validate uploaded objects before trusting them.
+func (s *S3Storage) PresignUpload(ctx context.Context, key, contentType string, expires time.Duration) (PresignedRequest, error) {
+	if err := ValidateKey(key); err != nil {
+		return PresignedRequest{}, err
+	}
+	if err := validateExpiry(expires); err != nil {
+		return PresignedRequest{}, err
+	}
+	now := s.now()
+	signed := awsauth.Presign(http.MethodPut, s.objectURL(key), "s3", s.region, s.creds, expires, now)
+
+	req := PresignedRequest{Method: http.MethodPut, URL: signed.String(), ExpiresAt: now.Add(expires)}
+	if contentType != "" {
+		req.Headers = map[string]string{"Content-Type": contentType}
+	}
+	return req, nil
+}
+
+func (s *S3Storage) PresignDownload(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+	if err := validateExpiry(expires); err != nil {
+		return "", err
+	}
+	return awsauth.Presign(http.MethodGet, s.objectURL(key), "s3", s.region, s.creds, expires, s.now()).String(), nil
+}
+
+func (s *S3Storage) do(req *http.Request, ok ...int) error {
+	awsauth.Sign(req, req.Header.Get("X-Amz-Content-Sha256"), "s3", s.region, s.creds, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("s3 %s: %w", req.Method, err)
+	}
+	defer resp.Body.Close()
+	for _, code := range ok {
+		if resp.StatusCode == code {
+			return nil
+		}
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(msg)))
+}
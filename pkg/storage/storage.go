@@ -0,0 +1,78 @@
+// Package storage keeps uploaded files (avatars, asset files, pitch decks, chat
+// attachments) in S3 or on local disk behind one interface. Clients normally upload
+// and download directly with presigned URLs, so file bytes never pass through the
+// JSON API.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"grveyard/pkg/config"
+)
+
+// MaxPresignExpiry is the longest lifetime S3 accepts for a presigned URL.
+const MaxPresignExpiry = 7 * 24 * time.Hour
+
+var (
+	ErrInvalidKey = errors.New("invalid storage key")
+	ErrNotFound   = errors.New("object not found")
+)
+
+// Storage stores blobs under slash-separated keys such as "avatars/<uuid>.png".
+type Storage interface {
+	// Put writes size bytes from body, replacing any existing object.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get opens key for reading; the caller closes it. Missing keys return ErrNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// PresignUpload returns a request the client can send to upload key directly.
+	PresignUpload(ctx context.Context, key, contentType string, expires time.Duration) (PresignedRequest, error)
+	// PresignDownload returns a URL the client can GET to download key.
+	PresignDownload(ctx context.Context, key string, expires time.Duration) (string, error)
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// PresignedRequest describes an upload the client performs itself.
+type PresignedRequest struct {
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers,omitempty"` // must be sent as-is
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// New builds the Storage selected by cfg.Driver.
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "s3":
+		return NewS3Storage(cfg)
+	case "local", "":
+		return NewLocalStorage(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// ValidateKey rejects keys that are empty, absolute or try to escape their prefix.
+func ValidateKey(key string) error {
+	if key == "" || len(key) > 1024 || strings.HasPrefix(key, "/") || strings.ContainsAny(key, "\\\x00") {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+func validateExpiry(expires time.Duration) error {
+	if expires <= 0 || expires > MaxPresignExpiry {
+		return fmt.Errorf("presign expiry must be between 1s and %s", MaxPresignExpiry)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/config"
+)
+
+func TestValidateKey(t *testing.T) {
+	require.NoError(t, ValidateKey("avatars/user-1.png"))
+	for _, key := range []string{"", "/etc/passwd", "a/../../b", "a//b", "a\\b", "./a"} {
+		require.ErrorIs(t, ValidateKey(key), ErrInvalidKey, key)
+	}
+}
+
+func newLocal(t *testing.T) (*LocalStorage, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	s, err := NewLocalStorage(config.StorageConfig{
+		LocalDir:       t.TempDir(),
+		PublicURL:      "https://api.example.com",
+		SigningSecret:  "secret",
+		MaxUploadBytes: 16,
+	})
+	require.NoError(t, err)
+	router := gin.New()
+	s.RegisterRoutes(router)
+	return s, router
+}
+
+// relative strips the public base URL so the request can be served by the test router.
+func relative(t *testing.T, raw string) string {
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	return u.RequestURI()
+}
+
+func TestLocalStorage_PresignedRoundTrip(t *testing.T) {
+	s, router := newLocal(t)
+	ctx := context.Background()
+
+	up, err := s.PresignUpload(ctx, "decks/pitch one.pdf", "application/pdf", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPut, up.Method)
+	require.True(t, strings.HasPrefix(up.URL, "https://api.example.com/storage/decks/pitch%20one.pdf?"))
+
+	req := httptest.NewRequest(up.Method, relative(t, up.URL), strings.NewReader("%PDF-1.7"))
+	req.Header.Set("Content-Type", up.Headers["Content-Type"])
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	down, err := s.PresignDownload(ctx, "decks/pitch one.pdf", time.Minute)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, relative(t, down), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "%PDF-1.7", w.Body.String())
+
+	rc, err := s.Get(ctx, "decks/pitch one.pdf")
+	require.NoError(t, err)
+	b, _ := io.ReadAll(rc)
+	rc.Close()
+	require.Equal(t, "%PDF-1.7", string(b))
+
+	require.NoError(t, s.Delete(ctx, "decks/pitch one.pdf"))
+	require.NoError(t, s.Delete(ctx, "decks/pitch one.pdf"), "deleting twice is fine")
+	_, err = s.Get(ctx, "decks/pitch one.pdf")
+	require.ErrorIs(t, err, ErrNotFound)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, relative(t, down), nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLocalStorage_RejectsBadLinks(t *testing.T) {
+	s, router := newLocal(t)
+	ctx := context.Background()
+	require.NoError(t, s.Put(ctx, "a.txt", strings.NewReader("hi"), 2, "text/plain"))
+
+	down, err := s.PresignDownload(ctx, "a.txt", time.Minute)
+	require.NoError(t, err)
+
+	// tampered key
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, strings.Replace(relative(t, down), "a.txt", "b.txt", 1), nil))
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// expired
+	s.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, relative(t, down), nil))
+	require.Equal(t, http.StatusForbidden, w.Code)
+	s.now = time.Now
+
+	// content type differs from the signed one
+	up, err := s.PresignUpload(ctx, "b.txt", "text/plain", time.Minute)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPut, relative(t, up.URL), strings.NewReader("x"))
+	req.Header.Set("Content-Type", "text/html")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// over the upload cap
+	req = httptest.NewRequest(http.MethodPut, relative(t, up.URL), strings.NewReader(strings.Repeat("x", 17)))
+	req.Header.Set("Content-Type", "text/plain")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	_, err = os.Stat(filepath.Join(s.dir, "b.txt"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestS3Storage(t *testing.T) {
+	var gotPath, gotBody, gotAuth, gotType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth, gotType = r.URL.EscapedPath(), r.Header.Get("Authorization"), r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		switch r.Method {
+		case http.MethodPut:
+			w.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			_, _ = w.Write([]byte("png"))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	s, err := NewS3Storage(config.StorageConfig{S3Bucket: "grveyard", S3Region: "auto", S3Endpoint: srv.URL, S3PathStyle: true})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "avatars/u 1.png", strings.NewReader("png"), 3, "image/png"))
+	require.Equal(t, "/grveyard/avatars/u%201.png", gotPath)
+	require.Equal(t, "png", gotBody)
+	require.Equal(t, "image/png", gotType)
+	require.Contains(t, gotAuth, "/auto/s3/aws4_request")
+
+	rc, err := s.Get(ctx, "avatars/u 1.png")
+	require.NoError(t, err)
+	b, _ := io.ReadAll(rc)
+	rc.Close()
+	require.Equal(t, "png", string(b))
+
+	require.NoError(t, s.Delete(ctx, "avatars/u 1.png"))
+
+	up, err := s.PresignUpload(ctx, "avatars/u.png", "image/png", 15*time.Minute)
+	require.NoError(t, err)
+	u, err := url.Parse(up.URL)
+	require.NoError(t, err)
+	require.Equal(t, "/grveyard/avatars/u.png", u.Path)
+	require.Equal(t, "900", u.Query().Get("X-Amz-Expires"))
+	require.NotEmpty(t, u.Query().Get("X-Amz-Signature"))
+
+	_, err = s.PresignDownload(ctx, "avatars/u.png", 8*24*time.Hour)
+	require.Error(t, err)
+}
+
+func TestS3Storage_VirtualHostedURL(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	s, err := NewS3Storage(config.StorageConfig{S3Bucket: "grveyard", S3Region: "eu-west-1"})
+	require.NoError(t, err)
+
+	require.Equal(t, "https://grveyard.s3.eu-west-1.amazonaws.com/assets/1/file.zip", s.objectURL("assets/1/file.zip").String())
+}
@@ -0,0 +1,67 @@
+package suggest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+const maxQueryLen = 100
+
+type SuggestHandler struct {
+	service *Service
+}
+
+func NewSuggestHandler(service *Service) *SuggestHandler {
+	return &SuggestHandler{service: service}
+}
+
+func (h *SuggestHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/search/suggest", h.suggest)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *SuggestHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/search/suggest",
+			Tag:         "search",
+			Summary:     "Search suggestions",
+			Description: "Completions for a partially typed query: titles of listed assets, startup names and asset types whose text or any word starts with q. Closer and more viewed matches rank first. Results are cached for a minute; a slow lookup returns an empty list rather than holding up the search box.",
+			Params: []openapi.Param{
+				openapi.Query("q", "string", "What the user has typed so far (1-100 characters)", true),
+				openapi.Query("limit", "integer", "Number of suggestions (default 5, max 10)", false),
+			},
+			Response: []Suggestion{},
+			Errors:   []int{http.StatusBadRequest},
+		},
+	}
+}
+
+func (h *SuggestHandler) suggest(c *gin.Context) {
+	q := c.Query("q")
+	if strings.TrimSpace(q) == "" || utf8.RuneCountInString(q) > maxQueryLen {
+		response.SendError(c, response.InvalidField(apperr.InvalidRequest, "q", "len", "q must be between 1 and 100 characters"))
+		return
+	}
+	limit := DefaultLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > MaxLimit {
+			response.SendError(c, response.InvalidField(apperr.InvalidRequest, "limit", "range", "limit must be between 1 and 10"))
+			return
+		}
+		limit = n
+	}
+
+	c.Header("Cache-Control", "public, max-age=60")
+	response.SendAPIResponse(c, http.StatusOK, true, "suggestions fetched", h.service.Suggest(c.Request.Context(), q, limit))
+}
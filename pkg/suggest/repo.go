@@ -0,0 +1,79 @@
+package suggest
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/db"
+)
+
+// candidatesPerKind bounds how many listings of each kind are ranked per query.
+const candidatesPerKind = 50
+
+type SuggestRepository interface {
+	// Candidates returns listed assets, startups and asset types matching prefix,
+	// with popularity counted since viewsSince.
+	Candidates(ctx context.Context, prefix string, viewsSince time.Time) ([]Candidate, error)
+}
+
+type postgresSuggestRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresSuggestRepository(pool *pgxpool.Pool) SuggestRepository {
+	return &postgresSuggestRepository{pool: pool}
+}
+
+// Candidates matches the start of the title or of any later word, so "app" finds
+// both "Appstore clone" and "Recipe app". Leading matches are preferred when a kind
+// has more than candidatesPerKind; popularity is only counted for those kept.
+func (r *postgresSuggestRepository) Candidates(ctx context.Context, prefix string, viewsSince time.Time) ([]Candidate, error) {
+	escaped := db.EscapeLike(strings.ToLower(prefix))
+	leading, word := escaped+"%", "% "+escaped+"%"
+
+	rows, err := r.pool.Query(ctx, `
+		WITH listings AS (
+			(SELECT 'asset' AS kind, a.id, a.title AS text, lower(a.title) LIKE $1 AS leading
+			 FROM assets a
+			 WHERE a.is_active = true AND a.is_sold = false AND a.is_deleted = false
+			   AND (lower(a.title) LIKE $1 OR lower(a.title) LIKE $2)
+			 ORDER BY leading DESC, a.id DESC
+			 LIMIT $4)
+			UNION ALL
+			(SELECT 'startup', s.id, s.name, lower(s.name) LIKE $1 AS leading
+			 FROM startups s
+			 WHERE s.is_deleted = false
+			   AND (lower(s.name) LIKE $1 OR lower(s.name) LIKE $2)
+			 ORDER BY leading DESC, s.id DESC
+			 LIMIT $4)
+		)
+		SELECT l.kind, l.id, l.text, l.leading,
+			(SELECT COUNT(*) FROM events e
+			 WHERE e.name = 'listing_viewed' AND e.occurred_at >= $3
+			   AND e.properties->>'listing_type' = l.kind AND e.properties->>'listing_id' = l.id::text)
+			+ (SELECT COUNT(*) FROM startup_bookmarks b WHERE l.kind = 'startup' AND b.startup_id = l.id)
+		FROM listings l
+		UNION ALL
+		SELECT 'tag', 0, a.asset_type, true, COUNT(*)
+		FROM assets a
+		WHERE a.is_active = true AND a.is_sold = false AND a.is_deleted = false AND a.asset_type LIKE $1
+		GROUP BY a.asset_type`,
+		leading, word, viewsSince, candidatesPerKind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []Candidate
+	for rows.Next() {
+		var c Candidate
+		if err := rows.Scan(&c.Kind, &c.ID, &c.Text, &c.Leading, &c.Popularity); err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
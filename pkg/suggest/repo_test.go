@@ -0,0 +1,49 @@
+package suggest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestPostgresSuggestRepository_Candidates(t *testing.T) {
+	t.Parallel()
+	pool := testhelpers.Pool(t)
+	repo := NewPostgresSuggestRepository(pool)
+	ctx := context.Background()
+
+	seller := testhelpers.NewUser(t, pool, testhelpers.WithRole("founder"))
+	leading := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetTitle("Codex notes"), testhelpers.WithAssetType("research"))
+	inner := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetTitle("Legacy codebase"), testhelpers.WithAssetType("codebase"))
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetTitle("Code sold"), testhelpers.WithAssetSold())
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetTitle("Unicode_tools"))
+	startup := testhelpers.NewStartup(t, pool, testhelpers.WithStartupOwner(seller.UUID), testhelpers.WithStartupName("CodeCraft"))
+
+	_, err := pool.Exec(ctx, `INSERT INTO events (name, properties, occurred_at) VALUES
+		('listing_viewed', jsonb_build_object('listing_type', 'asset', 'listing_id', $1::text), NOW()),
+		('listing_viewed', jsonb_build_object('listing_type', 'asset', 'listing_id', $1::text), NOW()),
+		('listing_viewed', jsonb_build_object('listing_type', 'asset', 'listing_id', $1::text), NOW() - INTERVAL '60 days')`, inner.ID)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `INSERT INTO startup_bookmarks (user_uuid, startup_id) VALUES ($1, $2)`, seller.UUID, startup.ID)
+	require.NoError(t, err)
+
+	got, err := repo.Candidates(ctx, "Code", time.Now().Add(-popularOver))
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []Candidate{
+		{Suggestion: Suggestion{Text: "Codex notes", Kind: KindAsset, ID: leading.ID}, Leading: true},
+		{Suggestion: Suggestion{Text: "Legacy codebase", Kind: KindAsset, ID: inner.ID}, Popularity: 2},
+		{Suggestion: Suggestion{Text: "CodeCraft", Kind: KindStartup, ID: startup.ID}, Leading: true, Popularity: 1},
+		{Suggestion: Suggestion{Text: "codebase", Kind: KindTag}, Leading: true, Popularity: 1},
+	}, got)
+
+	got, err = repo.Candidates(ctx, "_", time.Now())
+	require.NoError(t, err)
+	require.Empty(t, got, "LIKE wildcards must match literally")
+}
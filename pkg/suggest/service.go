@@ -0,0 +1,112 @@
+package suggest
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"grveyard/pkg/requestid"
+)
+
+const (
+	// MaxLimit is the most suggestions returned; DefaultLimit when none is asked for.
+	MaxLimit     = 10
+	DefaultLimit = 5
+
+	budget       = 150 * time.Millisecond
+	cacheTTL     = time.Minute
+	cacheEntries = 5000
+	popularOver  = 30 * 24 * time.Hour
+)
+
+type cached struct {
+	suggestions []Suggestion
+	expires     time.Time
+}
+
+// Service ranks and caches suggestions per normalized query. Lookups that exceed the
+// latency budget or fail return no suggestions rather than an error, since the
+// search box works without them; those results are not cached.
+type Service struct {
+	repo SuggestRepository
+	now  func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cached
+}
+
+func NewService(repo SuggestRepository) *Service {
+	return &Service{repo: repo, now: time.Now, cache: map[string]cached{}}
+}
+
+// Suggest returns up to limit completions for query.
+func (s *Service) Suggest(ctx context.Context, query string, limit int) []Suggestion {
+	if limit <= 0 || limit > MaxLimit {
+		limit = DefaultLimit
+	}
+	key := normalize(query)
+	if key == "" {
+		return []Suggestion{}
+	}
+
+	list, ok := s.lookup(key)
+	if !ok {
+		ctx, cancel := context.WithTimeout(ctx, budget)
+		defer cancel()
+		candidates, err := s.repo.Candidates(ctx, key, s.now().Add(-popularOver))
+		if err != nil {
+			log.Printf("[%s] search suggestions for %q failed: %v", requestid.FromContext(ctx), key, err)
+			return []Suggestion{}
+		}
+		list = rank(key, candidates, MaxLimit)
+		s.store(key, list)
+	}
+
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
+
+// normalize lowercases query and collapses whitespace, so "Recipe  App" and
+// "recipe app" share a cache entry. Trailing space is kept: "app " should only
+// complete to texts with another word after "app".
+func normalize(query string) string {
+	trailing := strings.HasSuffix(query, " ")
+	q := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if trailing && q != "" {
+		q += " "
+	}
+	return q
+}
+
+func (s *Service) lookup(key string) ([]Suggestion, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.cache[key]
+	if !ok || !s.now().Before(c.expires) {
+		return nil, false
+	}
+	return c.suggestions, true
+}
+
+// store evicts expired entries once the cache is full, and everything if that is not
+// enough.
+func (s *Service) store(key string, list []Suggestion) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if len(s.cache) >= cacheEntries {
+		for k, c := range s.cache {
+			if !now.Before(c.expires) {
+				delete(s.cache, k)
+			}
+		}
+		if len(s.cache) >= cacheEntries {
+			clear(s.cache)
+		}
+	}
+	s.cache[key] = cached{suggestions: list, expires: now.Add(cacheTTL)}
+}
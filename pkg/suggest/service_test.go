@@ -0,0 +1,68 @@
+package suggest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSuggestRepository struct {
+	mock.Mock
+}
+
+func (m *mockSuggestRepository) Candidates(ctx context.Context, prefix string, viewsSince time.Time) ([]Candidate, error) {
+	args := m.Called(ctx, prefix, viewsSince)
+	out, _ := args.Get(0).([]Candidate)
+	return out, args.Error(1)
+}
+
+func candidates(n int) []Candidate {
+	list := make([]Candidate, n)
+	for i := range list {
+		list[i] = Candidate{Suggestion: Suggestion{Text: string(rune('a'+i)) + "pp", Kind: KindAsset, ID: int64(i + 1)}, Popularity: int64(n - i)}
+	}
+	return list
+}
+
+func TestService_Suggest_CachesPerQuery(t *testing.T) {
+	repo := new(mockSuggestRepository)
+	now := time.Now()
+	repo.On("Candidates", mock.Anything, "recipe", now.Add(-popularOver)).Return(candidates(12), nil).Once()
+	s := NewService(repo)
+	s.now = func() time.Time { return now }
+
+	require.Len(t, s.Suggest(context.Background(), "Recipe", 3), 3)
+	require.Len(t, s.Suggest(context.Background(), " recipe ", MaxLimit), MaxLimit)
+	require.Len(t, s.Suggest(context.Background(), "recipe", 0), DefaultLimit)
+	repo.AssertNumberOfCalls(t, "Candidates", 1)
+
+	now = now.Add(cacheTTL)
+	repo.On("Candidates", mock.Anything, "recipe", now.Add(-popularOver)).Return([]Candidate(nil), nil).Once()
+	require.Empty(t, s.Suggest(context.Background(), "recipe", 3))
+}
+
+func TestService_Suggest_FailuresAreEmptyAndNotCached(t *testing.T) {
+	repo := new(mockSuggestRepository)
+	repo.On("Candidates", mock.Anything, "app", mock.Anything).Return(nil, context.DeadlineExceeded).Once()
+	repo.On("Candidates", mock.Anything, "app", mock.Anything).Return(candidates(1), nil).Once()
+	s := NewService(repo)
+
+	require.Equal(t, []Suggestion{}, s.Suggest(context.Background(), "app", 5))
+	require.Len(t, s.Suggest(context.Background(), "app", 5), 1)
+}
+
+func TestService_Suggest_AppliesBudget(t *testing.T) {
+	repo := new(mockSuggestRepository)
+	repo.On("Candidates", mock.Anything, "app", mock.Anything).Return(nil, errors.New("slow")).Run(func(args mock.Arguments) {
+		deadline, ok := args.Get(0).(context.Context).Deadline()
+		require.True(t, ok)
+		require.WithinDuration(t, time.Now().Add(budget), deadline, budget)
+	})
+	s := NewService(repo)
+
+	require.Empty(t, s.Suggest(context.Background(), "app", 5))
+}
@@ -0,0 +1,90 @@
+// Package suggest completes what a user is typing into the search box with asset
+// titles, startup names and asset types. Matches are ranked by how closely they
+// match the prefix and by recent popularity, and cached briefly so every keystroke
+// does not reach Postgres.
+package suggest
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Kind is what a suggestion completes to.
+type Kind string
+
+const (
+	KindAsset   Kind = "asset"
+	KindStartup Kind = "startup"
+	KindTag     Kind = "tag" // an asset type, e.g. "codebase"
+)
+
+// Suggestion is one completion. ID is set for assets and startups so clients can
+// link straight to the listing.
+type Suggestion struct {
+	Text string `json:"text"`
+	Kind Kind   `json:"kind"`
+	ID   int64  `json:"id,omitempty"`
+}
+
+// Candidate is a prefix match before ranking. Leading is true when the text starts
+// with the query rather than one of its later words. Popularity is recent views
+// (plus followers, for startups) or, for tags, the number of listed assets.
+type Candidate struct {
+	Suggestion
+	Leading    bool
+	Popularity int64
+}
+
+const (
+	exactBonus   = 3.0
+	leadingBonus = 2.0
+)
+
+// rank orders candidates for query and keeps the best limit, dropping repeated texts
+// of the same kind. Popularity is damped logarithmically so a popular listing beats
+// a closer match only when it is much more popular.
+func rank(query string, candidates []Candidate, limit int) []Suggestion {
+	type scored struct {
+		Candidate
+		score float64
+	}
+	best := map[string]scored{}
+	for _, c := range candidates {
+		s := math.Log1p(float64(c.Popularity))
+		if c.Leading {
+			s += leadingBonus
+		}
+		if strings.EqualFold(c.Text, query) {
+			s += exactBonus
+		}
+		key := string(c.Kind) + "\x00" + strings.ToLower(c.Text)
+		if prev, ok := best[key]; !ok || s > prev.score {
+			best[key] = scored{c, s}
+		}
+	}
+
+	list := make([]scored, 0, len(best))
+	for _, s := range best {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if len(a.Text) != len(b.Text) {
+			return len(a.Text) < len(b.Text)
+		}
+		return a.Text < b.Text
+	})
+
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	out := make([]Suggestion, len(list))
+	for i, s := range list {
+		out[i] = s.Suggestion
+	}
+	return out
+}
@@ -0,0 +1,47 @@
+package suggest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRank(t *testing.T) {
+	candidates := []Candidate{
+		{Suggestion: Suggestion{Text: "Recipe app", Kind: KindAsset, ID: 1}, Popularity: 400},
+		{Suggestion: Suggestion{Text: "Appstore clone", Kind: KindAsset, ID: 2}, Leading: true, Popularity: 3},
+		{Suggestion: Suggestion{Text: "Apple pie", Kind: KindStartup, ID: 3}, Leading: true},
+		{Suggestion: Suggestion{Text: "Todo app", Kind: KindAsset, ID: 4}, Popularity: 2},
+		{Suggestion: Suggestion{Text: "App", Kind: KindStartup, ID: 5}, Leading: true},
+	}
+
+	got := rank("app", candidates, 4)
+
+	require.Equal(t, []Suggestion{
+		{Text: "Recipe app", Kind: KindAsset, ID: 1}, // log1p(400) ≈ 6.0
+		{Text: "App", Kind: KindStartup, ID: 5},      // exact + leading = 5
+		{Text: "Appstore clone", Kind: KindAsset, ID: 2},
+		{Text: "Apple pie", Kind: KindStartup, ID: 3},
+	}, got)
+}
+
+func TestRank_DropsDuplicateTexts(t *testing.T) {
+	candidates := []Candidate{
+		{Suggestion: Suggestion{Text: "Codebase", Kind: KindAsset, ID: 1}, Leading: true},
+		{Suggestion: Suggestion{Text: "codebase", Kind: KindAsset, ID: 2}, Leading: true, Popularity: 10},
+		{Suggestion: Suggestion{Text: "codebase", Kind: KindTag}, Leading: true, Popularity: 1},
+	}
+
+	got := rank("code", candidates, 10)
+
+	require.Equal(t, []Suggestion{
+		{Text: "codebase", Kind: KindAsset, ID: 2},
+		{Text: "codebase", Kind: KindTag},
+	}, got)
+}
+
+func TestNormalize(t *testing.T) {
+	require.Equal(t, "recipe app", normalize("  Recipe   App"))
+	require.Equal(t, "app ", normalize("App  "))
+	require.Equal(t, "", normalize("   "))
+}
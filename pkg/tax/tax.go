@@ -0,0 +1,124 @@
+// Package tax works out the GST/VAT owed on a sale from the seller's and buyer's
+// countries.
+package tax
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Any matches every country in a rule.
+const Any = "*"
+
+// Rule charges Rate percent under Name on sales from SellerCountry to BuyerCountry.
+// Either country may be Any.
+type Rule struct {
+	SellerCountry string
+	BuyerCountry  string
+	Name          string
+	Rate          float64
+}
+
+// Rules is an ordered rule set; see Lookup for how a rule is chosen.
+type Rules []Rule
+
+// ParseRules reads a comma separated list of SELLER:BUYER:NAME:RATE entries, e.g.
+// "IN:IN:GST:18,GB:*:VAT:20". Countries are ISO 3166-1 alpha-2 codes or *, and RATE
+// is a percentage. An empty spec yields no rules, so no tax is charged.
+func ParseRules(spec string) (Rules, error) {
+	var rules Rules
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("tax rule %q: expected SELLER:BUYER:NAME:RATE", entry)
+		}
+		seller, buyer := parseCountry(parts[0]), parseCountry(parts[1])
+		if seller == "" || buyer == "" {
+			return nil, fmt.Errorf("tax rule %q: countries must be two-letter codes or *", entry)
+		}
+		name := strings.TrimSpace(parts[2])
+		if name == "" {
+			return nil, fmt.Errorf("tax rule %q: missing name", entry)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(parts[3]), 64)
+		if err != nil || rate < 0 || rate > 100 {
+			return nil, fmt.Errorf("tax rule %q: rate must be a percentage between 0 and 100", entry)
+		}
+		rules = append(rules, Rule{SellerCountry: seller, BuyerCountry: buyer, Name: name, Rate: rate})
+	}
+	return rules, nil
+}
+
+func parseCountry(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == Any || len(s) == 2 {
+		return s
+	}
+	return ""
+}
+
+// Lookup returns the rule for a sale from seller to buyer. An exact match on both
+// countries beats one on the seller alone, which beats one on the buyer alone, which
+// beats *:*; among equally specific rules the first wins. A sale with an unknown
+// country only matches rules that use * for it.
+func (r Rules) Lookup(seller, buyer string) (Rule, bool) {
+	best, bestScore := Rule{}, -1
+	for _, rule := range r {
+		score := 0
+		switch rule.SellerCountry {
+		case Any:
+		case seller:
+			score += 2
+		default:
+			continue
+		}
+		switch rule.BuyerCountry {
+		case Any:
+		case buyer:
+			score++
+		default:
+			continue
+		}
+		if score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// Breakdown is the tax owed on a sale. Net is the agreed price and tax is added on top
+// of it; Name is empty and Tax zero when no rule applies.
+type Breakdown struct {
+	SellerCountry string  `json:"seller_country,omitempty"`
+	BuyerCountry  string  `json:"buyer_country,omitempty"`
+	Name          string  `json:"tax_name,omitempty"`
+	Rate          float64 `json:"tax_rate"`
+	Net           float64 `json:"net_amount"`
+	Tax           float64 `json:"tax_amount"`
+	Gross         float64 `json:"gross_amount"`
+}
+
+// Calculate applies the matching rule to net, rounding the tax to the cent.
+func (r Rules) Calculate(seller, buyer string, net float64) Breakdown {
+	b := Breakdown{SellerCountry: seller, BuyerCountry: buyer, Net: net, Gross: net}
+	if seller == "" {
+		seller = Any
+	}
+	if buyer == "" {
+		buyer = Any
+	}
+	rule, ok := r.Lookup(seller, buyer)
+	if !ok {
+		return b
+	}
+	b.Name, b.Rate = rule.Name, rule.Rate
+	b.Tax = math.Round(net*rule.Rate) / 100
+	b.Gross = math.Round((net+b.Tax)*100) / 100
+	return b
+}
@@ -0,0 +1,79 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(" in:IN:GST:18, GB:*:VAT:20 ,")
+
+	require.NoError(t, err)
+	require.Equal(t, Rules{
+		{SellerCountry: "IN", BuyerCountry: "IN", Name: "GST", Rate: 18},
+		{SellerCountry: "GB", BuyerCountry: "*", Name: "VAT", Rate: 20},
+	}, rules)
+}
+
+func TestParseRules_Empty(t *testing.T) {
+	rules, err := ParseRules("")
+
+	require.NoError(t, err)
+	require.Empty(t, rules)
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"IN:IN:GST",
+		"IND:IN:GST:18",
+		"IN:IN::18",
+		"IN:IN:GST:abc",
+		"IN:IN:GST:120",
+	} {
+		_, err := ParseRules(spec)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestRules_Calculate(t *testing.T) {
+	rules, err := ParseRules("*:*:Sales tax:5,GB:*:VAT:20,*:DE:VAT:19,IN:IN:GST:18")
+	require.NoError(t, err)
+
+	cases := []struct {
+		seller, buyer string
+		name          string
+		tax, gross    float64
+	}{
+		{"IN", "IN", "GST", 18, 118},
+		{"GB", "DE", "VAT", 20, 120},
+		{"FR", "DE", "VAT", 19, 119},
+		{"IN", "US", "Sales tax", 5, 105},
+		{"", "", "Sales tax", 5, 105},
+	}
+	for _, tc := range cases {
+		b := rules.Calculate(tc.seller, tc.buyer, 100)
+		require.Equal(t, tc.name, b.Name, "%s -> %s", tc.seller, tc.buyer)
+		require.Equal(t, tc.tax, b.Tax, "%s -> %s", tc.seller, tc.buyer)
+		require.Equal(t, tc.gross, b.Gross, "%s -> %s", tc.seller, tc.buyer)
+	}
+}
+
+func TestRules_Calculate_NoMatch(t *testing.T) {
+	rules, err := ParseRules("IN:IN:GST:18")
+	require.NoError(t, err)
+
+	b := rules.Calculate("US", "IN", 99.99)
+
+	require.Equal(t, Breakdown{SellerCountry: "US", BuyerCountry: "IN", Net: 99.99, Gross: 99.99}, b)
+}
+
+func TestRules_Calculate_RoundsToCents(t *testing.T) {
+	rules, err := ParseRules("*:*:VAT:17.5")
+	require.NoError(t, err)
+
+	b := rules.Calculate("GB", "GB", 10.99)
+
+	require.Equal(t, 1.92, b.Tax)
+	require.Equal(t, 12.91, b.Gross)
+}
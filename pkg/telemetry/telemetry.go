@@ -0,0 +1,59 @@
+package telemetry
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is reported on every span unless OTEL_SERVICE_NAME overrides it.
+const ServiceName = "grveyard-backend"
+
+// Setup installs the global tracer provider and W3C trace-context propagator.
+// Spans are exported over OTLP/HTTP when OTEL_EXPORTER_OTLP_ENDPOINT (or the traces
+// specific variant) is set; otherwise tracing stays a no-op. The exporter reads the
+// standard OTEL_EXPORTER_OTLP_* variables for headers, TLS and timeouts.
+// The returned shutdown flushes pending spans and must be called before exit.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+	// resource.Default reads OTEL_SERVICE_NAME; let it win over the built-in name.
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		res, _ = resource.Merge(res, resource.NewSchemaless(semconv.ServiceName(name)))
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	log.Println("OpenTelemetry tracing enabled")
+
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the tracer used for hand-written spans in this service.
+func Tracer() trace.Tracer {
+	return otel.Tracer("grveyard")
+}
@@ -0,0 +1,18 @@
+package testhelpers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/auth"
+)
+
+// AuthAs marks every request as coming from userUUID with role, as auth.Authenticate
+// does for a valid access token, so handler tests can reach routes behind
+// auth.Required without signing tokens.
+func AuthAs(userUUID, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(auth.UserIDKey, userUUID)
+		c.Set(auth.RoleKey, role)
+		c.Next()
+	}
+}
@@ -0,0 +1,230 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/chat"
+	"grveyard/pkg/response"
+	"grveyard/pkg/users"
+)
+
+// TestPassword is the password SignUp gives every user.
+const TestPassword = "correct-horse-battery"
+
+// Client makes requests to a Server, optionally as a signed-in user. A signed-in
+// client sends the access token from login with every request.
+type Client struct {
+	server      *Server
+	http        *http.Client
+	User        users.User
+	AccessToken string
+}
+
+// Client returns an anonymous client.
+func (s *Server) Client() *Client {
+	return &Client{server: s, http: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// SignUp creates a user with the given role through POST /users, verifies their
+// email through the OTP flow so they may list, and signs them in.
+func (s *Server) SignUp(t *testing.T, name, role string) *Client {
+	t.Helper()
+
+	email := fmt.Sprintf("%s-%s@example.com", strings.ToLower(strings.ReplaceAll(name, " ", "-")), uuid.NewString()[:8])
+	res := s.Client().Post(t, "/users", map[string]any{
+		"name":     name,
+		"email":    email,
+		"role":     role,
+		"password": TestPassword,
+		"uuid":     uuid.NewString(),
+	})
+	res.RequireStatus(t, http.StatusCreated)
+	s.VerifyEmail(t, email)
+	return s.Login(t, email, TestPassword)
+}
+
+var otpCode = regexp.MustCompile(`\b\d{6}\b`)
+
+// VerifyEmail requests an OTP for email, reads it from the sandbox outbox and submits it.
+func (s *Server) VerifyEmail(t *testing.T, email string) {
+	t.Helper()
+
+	s.Client().Post(t, "/getOTP", map[string]any{"email": email}).RequireStatus(t, http.StatusOK)
+	var code string
+	for _, sent := range s.Emails.Recent() {
+		if sent.To == email {
+			code = otpCode.FindString(sent.PlainTextContent)
+			break
+		}
+	}
+	require.NotEmpty(t, code, "no OTP email sent to %s", email)
+	s.Client().Post(t, "/verifyOTP", map[string]any{"email": email, "code": code}).RequireStatus(t, http.StatusOK)
+}
+
+// Login signs in through POST /users/login, failing the test on bad credentials.
+func (s *Server) Login(t *testing.T, email, password string) *Client {
+	t.Helper()
+
+	c := s.Client()
+	res := c.Post(t, "/users/login", map[string]any{"email": email, "password": password})
+	res.RequireStatus(t, http.StatusOK)
+	res.Decode(t, &c.User)
+	var tok struct {
+		AccessToken string `json:"access_token"`
+	}
+	res.Decode(t, &tok)
+	c.AccessToken = tok.AccessToken
+	return c
+}
+
+// Response is a completed request with its body read.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// RequireStatus fails the test, printing the body, unless the status is want.
+func (r *Response) RequireStatus(t *testing.T, want int) {
+	t.Helper()
+	require.Equal(t, want, r.StatusCode, "body: %s", r.Body)
+}
+
+// API decodes the standard response envelope.
+func (r *Response) API(t *testing.T) response.APIResponse {
+	t.Helper()
+	var env response.APIResponse
+	require.NoError(t, json.Unmarshal(r.Body, &env), "body: %s", r.Body)
+	return env
+}
+
+// Decode unmarshals the envelope's data field into v.
+func (r *Response) Decode(t *testing.T, v any) {
+	t.Helper()
+	var env struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(r.Body, &env), "body: %s", r.Body)
+	require.NoError(t, json.Unmarshal(env.Data, v), "data: %s", env.Data)
+}
+
+func (c *Client) Get(t *testing.T, path string) *Response {
+	t.Helper()
+	return c.Do(t, http.MethodGet, path, nil)
+}
+
+func (c *Client) Post(t *testing.T, path string, body any) *Response {
+	t.Helper()
+	return c.Do(t, http.MethodPost, path, body)
+}
+
+func (c *Client) Put(t *testing.T, path string, body any) *Response {
+	t.Helper()
+	return c.Do(t, http.MethodPut, path, body)
+}
+
+func (c *Client) Patch(t *testing.T, path string, body any) *Response {
+	t.Helper()
+	return c.Do(t, http.MethodPatch, path, body)
+}
+
+func (c *Client) Delete(t *testing.T, path string) *Response {
+	t.Helper()
+	return c.Do(t, http.MethodDelete, path, nil)
+}
+
+// Do sends body, if any, as JSON and reads the whole response.
+func (c *Client) Do(t *testing.T, method, path string, body any) *Response {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, c.url(t, "http", path), reader)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/json")
+	if c.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.http.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	raw, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return &Response{StatusCode: res.StatusCode, Header: res.Header, Body: raw}
+}
+
+// DialChat opens the chat websocket as the signed-in user. The connection is
+// closed when the test ends.
+func (c *Client) DialChat(t *testing.T) *ChatConn {
+	t.Helper()
+	require.NotEmpty(t, c.User.UUID, "DialChat needs a signed-in client")
+
+	header := http.Header{"Authorization": {"Bearer " + c.AccessToken}}
+	conn, res, err := websocket.DefaultDialer.Dial(c.url(t, "ws", "/ws/chat"), header)
+	if res != nil {
+		res.Body.Close()
+	}
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return &ChatConn{conn: conn}
+}
+
+// url resolves path against the server.
+func (c *Client) url(t *testing.T, scheme, path string) string {
+	t.Helper()
+	u, err := url.Parse(c.server.URL + path)
+	require.NoError(t, err)
+	u.Scheme = scheme
+	return u.String()
+}
+
+// ChatConn is an open chat websocket.
+type ChatConn struct {
+	conn *websocket.Conn
+}
+
+// Send writes a text message to receiverUUID.
+func (cc *ChatConn) Send(t *testing.T, receiverUUID, content string) {
+	t.Helper()
+	require.NoError(t, cc.conn.WriteJSON(chat.Message{
+		ID:         uuid.NewString(),
+		ReceiverID: receiverUUID,
+		Content:    content,
+		Timestamp:  time.Now(),
+	}))
+}
+
+// Next reads frames until one decodes as a chat message with content, skipping
+// acknowledgements and other events, and fails the test after five seconds.
+func (cc *ChatConn) Next(t *testing.T) chat.Message {
+	t.Helper()
+	require.NoError(t, cc.conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		var msg chat.Message
+		require.NoError(t, cc.conn.ReadJSON(&msg))
+		if msg.Content != "" {
+			return msg
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/assets"
+	"grveyard/pkg/chat"
+	"grveyard/pkg/notifications"
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	testhelpers.Main(m)
+}
+
+func TestJourney_ListChatAndSell(t *testing.T) {
+	t.Parallel()
+	srv := NewTestServer(t)
+
+	founder := srv.SignUp(t, "Fiona Founder", "founder")
+	buyer := srv.SignUp(t, "Bob Buyer", "buyer")
+
+	res := founder.Post(t, "/assets", map[string]any{
+		"user_uuid":  founder.User.UUID,
+		"title":      "shutdownlabs.io",
+		"asset_type": "domain",
+		"price":      250,
+	})
+	res.RequireStatus(t, http.StatusCreated)
+	var asset assets.Asset
+	res.Decode(t, &asset)
+
+	founderChat := founder.DialChat(t)
+	buyerChat := buyer.DialChat(t)
+	buyerChat.Send(t, founder.User.UUID, "Is the domain still available?")
+	got := founderChat.Next(t)
+	require.Equal(t, buyer.User.UUID, got.SenderID)
+	require.Equal(t, "Is the domain still available?", got.Content)
+
+	res = buyer.Get(t, "/messages?peer_id="+founder.User.UUID)
+	res.RequireStatus(t, http.StatusOK)
+	var history struct {
+		Messages []chat.MessageHistoryItem `json:"messages"`
+	}
+	res.Decode(t, &history)
+	require.Len(t, history.Messages, 1)
+
+	buyer.Patch(t, fmt.Sprintf("/assets/%d/mark-sold", asset.ID), nil).RequireStatus(t, http.StatusForbidden)
+	founder.Patch(t, fmt.Sprintf("/assets/%d/mark-sold", asset.ID), nil).RequireStatus(t, http.StatusOK)
+	founder.Patch(t, fmt.Sprintf("/assets/%d/mark-sold", asset.ID), nil).RequireStatus(t, http.StatusConflict)
+
+	res = buyer.Get(t, fmt.Sprintf("/assets/%d", asset.ID))
+	res.RequireStatus(t, http.StatusOK)
+	res.Decode(t, &asset)
+	require.True(t, asset.IsSold)
+
+	// The sale reaches the founder's inbox asynchronously; chat messages only
+	// notify receivers who are offline
+	require.Eventually(t, func() bool {
+		var count notifications.UnreadCount
+		founder.Get(t, "/users/"+founder.User.UUID+"/notifications/unread-count").Decode(t, &count)
+		return count.Unread == 1
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -0,0 +1,187 @@
+// Package e2e runs the whole API in-process against the test database, for tests
+// that walk a user journey across handlers ("sign up → list an asset → chat → mark
+// sold"). It lives apart from testhelpers because it imports every feature package,
+// and those packages' own tests import testhelpers.
+package e2e
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/activity"
+	"grveyard/pkg/analytics"
+	"grveyard/pkg/assets"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/bookmarks"
+	"grveyard/pkg/buy"
+	"grveyard/pkg/chat"
+	"grveyard/pkg/config"
+	"grveyard/pkg/dashboard"
+	"grveyard/pkg/documents"
+	"grveyard/pkg/errorreport"
+	"grveyard/pkg/favorites"
+	"grveyard/pkg/idempotency"
+	"grveyard/pkg/images"
+	"grveyard/pkg/notifications"
+	"grveyard/pkg/otp"
+	"grveyard/pkg/reports"
+	"grveyard/pkg/requestid"
+	"grveyard/pkg/sendemail"
+	"grveyard/pkg/startups"
+	"grveyard/pkg/storage"
+	"grveyard/pkg/testhelpers"
+	"grveyard/pkg/users"
+	"grveyard/pkg/validation"
+)
+
+// Server is a running API backed by real services and repositories. Outside
+// dependencies are swapped for local ones: email goes to a sandbox outbox, files to
+// a temporary directory, and search falls back to SQL.
+type Server struct {
+	URL    string
+	Pool   *pgxpool.Pool
+	Emails *sendemail.SandboxEmailService
+	Chat   *chat.ConnectionManager
+	Tokens *auth.Issuer // signs the tokens login returns
+}
+
+// NewTestServer starts the API on a random local port with its own database schema
+// (see testhelpers.Pool), skipping the test when no database is configured. The
+// server, its workers and the schema are torn down when the test ends.
+//
+// The wiring follows cmd/serve.go minus what needs outside services or is covered
+// elsewhere: tracing, compression, ACME, scheduled jobs and the optional admin, SEO
+// and unsubscribe routes.
+func NewTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	pool := testhelpers.Pool(t)
+	ts := httptest.NewUnstartedServer(nil)
+	baseURL := "http://" + ts.Listener.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	emails := sendemail.NewSandboxEmailService("")
+	emailService := sendemail.WithSuppression(emails, sendemail.NewPostgresSuppressionRepository(pool))
+
+	blobStore, err := storage.NewLocalStorage(config.StorageConfig{
+		Driver:         "local",
+		LocalDir:       t.TempDir(),
+		PublicURL:      baseURL,
+		SigningSecret:  "e2e",
+		MaxUploadBytes: 5 << 20,
+	})
+	require.NoError(t, err)
+
+	chatManager := chat.NewConnectionManager()
+	chatHandler := chat.NewHandler(chatManager)
+	chatHandler.SetRepository(chat.NewPostgresMessageStore(pool))
+
+	inboxRepo := notifications.NewPostgresInboxRepository(pool)
+	notifier := notifications.NewOrchestrator(
+		notifications.NewPostgresRecipientRepository(pool),
+		notifications.NewEmailChannel(emailService, nil),
+		notifications.NewPushChannel(chatManager),
+		notifications.NewInAppChannel(inboxRepo),
+	)
+	notifier.Start(ctx)
+	chatHandler.SetNotifier(notifier)
+
+	usersRepo := users.NewPostgresUserRepository(pool)
+	imageCfg := images.DefaultConfig()
+	imageCfg.MaxBytes = 5 << 20
+	imageService := images.NewService(images.NewPostgresImageRepository(pool), blobStore, imageCfg)
+	imageService.Start(ctx)
+
+	router := gin.New()
+	router.Use(requestid.Middleware(), errorreport.Recovery())
+	tokens := auth.NewIssuer(auth.RandomSecret(), 0)
+	router.Use(auth.Authenticate(tokens))
+	router.Use(validation.BodyLimit(validation.DefaultMaxBody, storage.RoutePrefix), validation.RequireJSON("/email/unsubscribe", storage.RoutePrefix))
+	idempotencyCfg := idempotency.DefaultConfig()
+	idempotencyCfg.MaxBody = validation.DefaultMaxBody
+	router.Use(idempotency.Middleware(idempotency.NewPostgresStore(pool), idempotencyCfg))
+
+	feed := activity.NewService(activity.NewPostgresActivityRepository(pool))
+	activity.NewActivityHandler(feed).RegisterRoutes(router)
+	usersService := users.NewUserService(usersRepo, nil)
+	followers := bookmarks.NewService(bookmarks.NewPostgresBookmarkRepository(pool), notifier)
+	bookmarks.NewBookmarkHandler(followers).RegisterRoutes(router)
+	watchers := favorites.NewService(favorites.NewPostgresFavoriteRepository(pool), notifier)
+	favorites.NewFavoriteHandler(watchers).RegisterRoutes(router)
+	startupsRepo := startups.NewPostgresStartupRepository(pool)
+	startupsService := startups.NewStartupService(startupsRepo, nil, feed, followers, usersService)
+	startups.NewStartupHandler(startupsService).RegisterRoutes(router)
+	startups.NewDeletedHandler(startupsService, "").RegisterRoutes(router)
+	startups.NewCategoryHandler(startups.NewCategoryService(startups.NewPostgresCategoryRepository(pool), startupsRepo)).RegisterRoutes(router)
+	startups.NewTransferHandler(startups.NewTransferService(startups.NewPostgresTransferRepository(pool), startupsRepo, nil)).RegisterRoutes(router)
+	verifications := startups.NewVerificationService(startups.NewPostgresVerificationRepository(pool), startupsRepo)
+	startups.NewVerificationHandler(verifications).RegisterRoutes(router)
+	startups.NewVerificationReviewHandler(verifications, "").RegisterRoutes(router)
+	startups.NewMemberHandler(startups.NewMemberService(startups.NewPostgresMemberRepository(pool), startupsRepo)).RegisterRoutes(router)
+	startups.NewViewHandler(startups.NewViewService(startups.NewPostgresViewRepository(pool))).RegisterRoutes(router)
+	assets.NewAssetHandler(assets.NewAssetService(assets.NewPostgresAssetRepository(pool), nil, feed, usersService, watchers)).RegisterRoutes(router)
+	buy.NewBuyHandler(buy.NewBuyService(buy.NewPostgresBuyRepository(pool), notifier, feed, followers, watchers, nil, nil, nil)).RegisterRoutes(router)
+	authService := auth.NewService(auth.NewPostgresRefreshRepository(pool), tokens, 0)
+	twoFactor := users.NewTwoFactorService(users.NewPostgresTwoFactorRepository(pool), usersRepo, "Graveyard")
+	usersHandler := users.NewUserHandler(usersService, authService)
+	usersHandler.SetTwoFactor(twoFactor)
+	authEvents := auth.NewEventLog(auth.NewPostgresEventRepository(pool))
+	usersHandler.SetEvents(authEvents)
+	referrals := users.NewReferralService(users.NewPostgresReferralRepository(pool))
+	usersHandler.SetReferrals(referrals)
+	usersHandler.RegisterRoutes(router)
+	users.NewTwoFactorHandler(twoFactor).RegisterRoutes(router)
+	blocks := users.NewBlockService(users.NewPostgresBlockRepository(pool), usersRepo)
+	users.NewBlockHandler(blocks).RegisterRoutes(router)
+	users.NewReferralHandler(referrals).RegisterRoutes(router)
+	users.NewStatsHandler(users.NewStatsService(users.NewPostgresStatsRepository(pool), usersRepo)).RegisterRoutes(router)
+	chatHandler.SetBlocks(blocks)
+	// Without an admin token only signed-in admins can ban
+	bans := users.NewBanService(users.NewPostgresBanRepository(pool), usersRepo, authService, chatManager)
+	users.NewBanHandler(bans, "").RegisterRoutes(router)
+	chatHandler.SetBans(bans)
+	authHandler := auth.NewAuthHandler(authService)
+	authHandler.SetEvents(authEvents)
+	authHandler.RegisterRoutes(router)
+	auth.NewEventsHandler(authEvents).RegisterRoutes(router)
+	resetHandler := users.NewPasswordResetHandler(users.NewPasswordResetService(users.NewPostgresPasswordResetRepository(pool), usersRepo, emailService, "", authService))
+	resetHandler.SetEvents(authEvents)
+	resetHandler.RegisterRoutes(router)
+	otpHandler := otp.NewOTPHandler(otp.NewOTPService(otp.NewPostgresOTPRepository(pool), usersRepo, emailService))
+	otpHandler.SetEvents(authEvents)
+	otpHandler.RegisterRoutes(router)
+	images.NewImageHandler(imageService).RegisterRoutes(router)
+	documents.NewDocumentHandler(documents.NewService(documents.NewPostgresDocumentRepository(pool), blobStore)).RegisterRoutes(router)
+	notifications.NewInboxHandler(notifications.NewInbox(inboxRepo)).RegisterRoutes(router)
+	notifications.NewPreferencesHandler(notifications.NewPostgresPreferencesRepository(pool)).RegisterRoutes(router)
+	analytics.NewEventsHandler(analytics.NewService(analytics.NewPostgresSink(pool))).RegisterRoutes(router)
+	reports.NewReportHandler(reports.NewService(reports.NewPostgresReportRepository(pool), notifier)).RegisterRoutes(router)
+	dashboard.NewDashboardHandler(dashboard.NewService(dashboard.NewPostgresDashboardRepository(pool))).RegisterRoutes(router)
+	blobStore.RegisterRoutes(router)
+	sendemail.NewDevHandler(emails).RegisterRoutes(router)
+	router.GET("/ws/chat", auth.Required(), chatHandler.HandleWebSocketGin)
+	router.GET("/chat/status", chatHandler.GetStatusGin)
+	router.GET("/messages", auth.Required(), chatHandler.GetMessagesGin)
+
+	ts.Config.Handler = router
+	ts.Start()
+
+	// Registered after testhelpers.Pool, so this runs before the pool is closed
+	t.Cleanup(func() {
+		shutdownCtx, done := context.WithTimeout(context.Background(), 5*time.Second)
+		defer done()
+		_ = chatHandler.Shutdown(shutdownCtx)
+		ts.Close()
+		cancel()
+		_ = notifier.Wait(shutdownCtx)
+		_ = imageService.Wait(shutdownCtx)
+	})
+
+	return &Server{URL: baseURL, Pool: pool, Emails: emails, Chat: chatManager, Tokens: tokens}
+}
@@ -0,0 +1,471 @@
+package testhelpers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// Querier is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx, so fixtures can be
+// inserted inside a test transaction as well as directly.
+type Querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// TB is the subset of testing.TB the factories use, so they can also run outside
+// tests (see the seed package). *testing.T and *testing.B satisfy it.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	FailNow()
+}
+
+// Factories insert a complete, valid row with unique defaults and return what was
+// stored. Options override individual columns; parents a row needs (an owner, a
+// sender) are created on the fly unless one is passed in:
+//
+//	buyer := testhelpers.NewUser(t, db, testhelpers.WithRole("buyer"), testhelpers.WithVerified())
+//	asset := testhelpers.NewAsset(t, db, testhelpers.WithAssetSold())
+//	testhelpers.NewTransaction(t, db, testhelpers.WithTransactionAsset(asset.ID), testhelpers.WithBuyer(buyer.ID))
+
+// UserFixture is a users row created by NewUser.
+type UserFixture struct {
+	ID            int64
+	UUID          string
+	Name          string
+	Email         string
+	Role          string
+	PasswordHash  string
+	ProfilePicURL string
+	Locale        string
+	VerifiedAt    *time.Time
+	IsDeleted     bool
+}
+
+type UserOption func(*UserFixture)
+
+func WithName(name string) UserOption {
+	return func(u *UserFixture) { u.Name = name }
+}
+
+func WithEmail(email string) UserOption {
+	return func(u *UserFixture) { u.Email = email }
+}
+
+func WithRole(role string) UserOption {
+	return func(u *UserFixture) { u.Role = role }
+}
+
+func WithUUID(uuid string) UserOption {
+	return func(u *UserFixture) { u.UUID = uuid }
+}
+
+func WithLocale(locale string) UserOption {
+	return func(u *UserFixture) { u.Locale = locale }
+}
+
+func WithPasswordHash(hash string) UserOption {
+	return func(u *UserFixture) { u.PasswordHash = hash }
+}
+
+func WithProfilePic(url string) UserOption {
+	return func(u *UserFixture) { u.ProfilePicURL = url }
+}
+
+func WithUserDeleted() UserOption {
+	return func(u *UserFixture) { u.IsDeleted = true }
+}
+
+// WithVerified marks the user as having verified their email just now.
+func WithVerified() UserOption {
+	return WithVerifiedAt(time.Now())
+}
+
+func WithVerifiedAt(at time.Time) UserOption {
+	return func(u *UserFixture) { u.VerifiedAt = &at }
+}
+
+// NewUser inserts a founder with a unique name, email and UUID.
+func NewUser(t TB, db Querier, opts ...UserOption) UserFixture {
+	t.Helper()
+
+	suffix := nextSuffix()
+	u := UserFixture{
+		Name:         fmt.Sprintf("test-user-%d", suffix),
+		Role:         "founder",
+		PasswordHash: "hash",
+		UUID:         fmt.Sprintf("uuid-%d", suffix),
+		Locale:       "en",
+	}
+	for _, opt := range opts {
+		opt(&u)
+	}
+	if u.Email == "" {
+		u.Email = u.Name + "@example.com"
+	}
+
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO users (name, email, role, password_hash, profile_pic_url, uuid, locale, verified_at, is_deleted)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9)
+		RETURNING id`,
+		u.Name, u.Email, u.Role, u.PasswordHash, u.ProfilePicURL, u.UUID, u.Locale, u.VerifiedAt, u.IsDeleted,
+	).Scan(&u.ID)
+	require.NoError(t, err)
+	return u
+}
+
+// StartupFixture is a startups row created by NewStartup.
+type StartupFixture struct {
+	ID          int64
+	Name        string
+	Slug        string
+	Description string
+	LogoURL     string
+	OwnerUUID   string
+	Status      string
+	SoldAt      *time.Time
+	IsDeleted   bool
+}
+
+type StartupOption func(*StartupFixture)
+
+func WithStartupOwner(uuid string) StartupOption {
+	return func(s *StartupFixture) { s.OwnerUUID = uuid }
+}
+
+func WithStartupName(name string) StartupOption {
+	return func(s *StartupFixture) { s.Name = name }
+}
+
+func WithStartupSlug(slug string) StartupOption {
+	return func(s *StartupFixture) { s.Slug = slug }
+}
+
+func WithStartupDescription(d string) StartupOption {
+	return func(s *StartupFixture) { s.Description = d }
+}
+
+func WithStartupLogo(url string) StartupOption {
+	return func(s *StartupFixture) { s.LogoURL = url }
+}
+
+func WithStartupStatus(status string) StartupOption {
+	return func(s *StartupFixture) { s.Status = status }
+}
+
+func WithStartupDeleted() StartupOption {
+	return func(s *StartupFixture) { s.IsDeleted = true }
+}
+
+// WithStartupSold sets status to sold with sold_at now, as the buy flow does.
+func WithStartupSold() StartupOption {
+	return func(s *StartupFixture) {
+		now := time.Now()
+		s.Status = "sold"
+		s.SoldAt = &now
+	}
+}
+
+// NewStartup inserts an active startup, creating an owner unless WithStartupOwner is given.
+func NewStartup(t TB, db Querier, opts ...StartupOption) StartupFixture {
+	t.Helper()
+
+	suffix := nextSuffix()
+	s := StartupFixture{
+		Name:   fmt.Sprintf("test-startup-%d", suffix),
+		Slug:   fmt.Sprintf("test-startup-%d", suffix),
+		Status: "active",
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	if s.OwnerUUID == "" {
+		s.OwnerUUID = NewUser(t, db).UUID
+	}
+
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO startups (name, slug, description, logo_url, owner_uuid, status, sold_at, is_deleted)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6, $7, $8)
+		RETURNING id`,
+		s.Name, s.Slug, s.Description, s.LogoURL, s.OwnerUUID, s.Status, s.SoldAt, s.IsDeleted,
+	).Scan(&s.ID)
+	require.NoError(t, err)
+	return s
+}
+
+// AssetFixture is an assets row created by NewAsset.
+type AssetFixture struct {
+	ID           int64
+	UserUUID     string
+	Title        string
+	Description  string
+	AssetType    string
+	ImageURL     string
+	Price        float64
+	IsNegotiable bool
+	IsSold       bool
+	SoldAt       *time.Time
+	IsActive     bool
+	IsDeleted    bool
+}
+
+type AssetOption func(*AssetFixture)
+
+func WithAssetOwner(uuid string) AssetOption {
+	return func(a *AssetFixture) { a.UserUUID = uuid }
+}
+
+func WithAssetTitle(title string) AssetOption {
+	return func(a *AssetFixture) { a.Title = title }
+}
+
+func WithAssetDescription(d string) AssetOption {
+	return func(a *AssetFixture) { a.Description = d }
+}
+
+func WithAssetType(typ string) AssetOption {
+	return func(a *AssetFixture) { a.AssetType = typ }
+}
+
+func WithAssetImage(url string) AssetOption {
+	return func(a *AssetFixture) { a.ImageURL = url }
+}
+
+func WithPrice(price float64) AssetOption {
+	return func(a *AssetFixture) { a.Price = price }
+}
+
+func WithFixedPrice() AssetOption {
+	return func(a *AssetFixture) { a.IsNegotiable = false }
+}
+
+func WithAssetInactive() AssetOption {
+	return func(a *AssetFixture) { a.IsActive = false }
+}
+
+func WithAssetDeleted() AssetOption {
+	return func(a *AssetFixture) { a.IsDeleted = true }
+}
+
+// WithAssetSold marks the asset sold with sold_at now, as the buy flow does.
+func WithAssetSold() AssetOption {
+	return func(a *AssetFixture) {
+		now := time.Now()
+		a.IsSold = true
+		a.SoldAt = &now
+	}
+}
+
+// NewAsset inserts an active, unsold research asset, creating an owner unless
+// WithAssetOwner is given.
+func NewAsset(t TB, db Querier, opts ...AssetOption) AssetFixture {
+	t.Helper()
+
+	a := AssetFixture{
+		Title:        fmt.Sprintf("test-asset-%d", nextSuffix()),
+		AssetType:    "research",
+		IsNegotiable: true,
+		IsActive:     true,
+	}
+	for _, opt := range opts {
+		opt(&a)
+	}
+	if a.UserUUID == "" {
+		a.UserUUID = NewUser(t, db).UUID
+	}
+
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO assets (user_uuid, title, description, asset_type, image_url, price, is_negotiable, is_sold, sold_at, is_active, is_deleted)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11)
+		RETURNING id`,
+		a.UserUUID, a.Title, a.Description, a.AssetType, a.ImageURL, a.Price, a.IsNegotiable, a.IsSold, a.SoldAt, a.IsActive, a.IsDeleted,
+	).Scan(&a.ID)
+	require.NoError(t, err)
+	return a
+}
+
+// MessageFixture is a messages row created by NewMessage. Sender and receiver are
+// users.id values, not UUIDs.
+type MessageFixture struct {
+	ID          int64
+	SenderID    int64
+	ReceiverID  int64
+	Content     string
+	MessageType int16
+	IsRead      bool
+	MessagedAt  int64
+}
+
+type MessageOption func(*MessageFixture)
+
+func WithSender(userID int64) MessageOption {
+	return func(m *MessageFixture) { m.SenderID = userID }
+}
+
+func WithReceiver(userID int64) MessageOption {
+	return func(m *MessageFixture) { m.ReceiverID = userID }
+}
+
+func WithContent(content string) MessageOption {
+	return func(m *MessageFixture) { m.Content = content }
+}
+
+func WithMessageType(typ int16) MessageOption {
+	return func(m *MessageFixture) { m.MessageType = typ }
+}
+
+func WithRead() MessageOption {
+	return func(m *MessageFixture) { m.IsRead = true }
+}
+
+func WithMessagedAt(at time.Time) MessageOption {
+	return func(m *MessageFixture) { m.MessagedAt = at.Unix() }
+}
+
+// NewMessage inserts an unread text message sent now, creating sender and receiver
+// unless they are given.
+func NewMessage(t TB, db Querier, opts ...MessageOption) MessageFixture {
+	t.Helper()
+
+	m := MessageFixture{
+		Content:    fmt.Sprintf("test-message-%d", nextSuffix()),
+		MessagedAt: time.Now().Unix(),
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	if m.SenderID == 0 {
+		m.SenderID = NewUser(t, db).ID
+	}
+	if m.ReceiverID == 0 {
+		m.ReceiverID = NewUser(t, db).ID
+	}
+
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO messages (sender_id, receiver_id, content, message_type, is_read, messaged_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		m.SenderID, m.ReceiverID, m.Content, m.MessageType, m.IsRead, m.MessagedAt,
+	).Scan(&m.ID)
+	require.NoError(t, err)
+	return m
+}
+
+// OTPFixture is an otps row created by NewOTP.
+type OTPFixture struct {
+	ID        int64
+	Email     string
+	Code      string
+	ExpiresAt time.Time
+	Verified  bool
+	CreatedAt time.Time
+}
+
+type OTPOption func(*OTPFixture)
+
+func WithOTPEmail(email string) OTPOption {
+	return func(o *OTPFixture) { o.Email = email }
+}
+
+func WithOTPCode(code string) OTPOption {
+	return func(o *OTPFixture) { o.Code = code }
+}
+
+func WithOTPExpiresAt(at time.Time) OTPOption {
+	return func(o *OTPFixture) { o.ExpiresAt = at }
+}
+
+func WithOTPVerified() OTPOption {
+	return func(o *OTPFixture) { o.Verified = true }
+}
+
+func WithOTPCreatedAt(at time.Time) OTPOption {
+	return func(o *OTPFixture) { o.CreatedAt = at }
+}
+
+// WithOTPExpired makes the code expire a minute ago.
+func WithOTPExpired() OTPOption {
+	return WithOTPExpiresAt(time.Now().Add(-time.Minute))
+}
+
+// NewOTP inserts an unverified code valid for ten minutes, like the OTP service.
+func NewOTP(t TB, db Querier, opts ...OTPOption) OTPFixture {
+	t.Helper()
+
+	now := time.Now()
+	o := OTPFixture{
+		Email:     fmt.Sprintf("otp-%d@example.com", nextSuffix()),
+		Code:      "123456",
+		ExpiresAt: now.Add(10 * time.Minute),
+		CreatedAt: now,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO otps (email, code, expires_at, verified, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`,
+		o.Email, o.Code, o.ExpiresAt, o.Verified, o.CreatedAt,
+	).Scan(&o.ID)
+	require.NoError(t, err)
+	return o
+}
+
+// TransactionFixture is a transactions row created by NewTransaction.
+type TransactionFixture struct {
+	ID         int64
+	AssetID    int64
+	BuyerID    int64
+	FinalPrice float64
+	TaxName    string
+	TaxRate    float64
+	TaxAmount  float64
+}
+
+type TransactionOption func(*TransactionFixture)
+
+func WithTransactionAsset(assetID int64) TransactionOption {
+	return func(tx *TransactionFixture) { tx.AssetID = assetID }
+}
+
+func WithBuyer(userID int64) TransactionOption {
+	return func(tx *TransactionFixture) { tx.BuyerID = userID }
+}
+
+func WithFinalPrice(price float64) TransactionOption {
+	return func(tx *TransactionFixture) { tx.FinalPrice = price }
+}
+
+func WithTax(name string, rate, amount float64) TransactionOption {
+	return func(tx *TransactionFixture) { tx.TaxName, tx.TaxRate, tx.TaxAmount = name, rate, amount }
+}
+
+// NewTransaction records a sale, creating a sold asset and a buyer unless given.
+func NewTransaction(t TB, db Querier, opts ...TransactionOption) TransactionFixture {
+	t.Helper()
+
+	tx := TransactionFixture{FinalPrice: 100}
+	for _, opt := range opts {
+		opt(&tx)
+	}
+	if tx.AssetID == 0 {
+		tx.AssetID = NewAsset(t, db, WithAssetSold(), WithPrice(tx.FinalPrice)).ID
+	}
+	if tx.BuyerID == 0 {
+		tx.BuyerID = NewUser(t, db, WithRole("buyer")).ID
+	}
+
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO transactions (asset_id, buyer_id, final_price, tax_name, tax_rate, tax_amount)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		tx.AssetID, tx.BuyerID, tx.FinalPrice, tx.TaxName, tx.TaxRate, tx.TaxAmount,
+	).Scan(&tx.ID)
+	require.NoError(t, err)
+	return tx
+}
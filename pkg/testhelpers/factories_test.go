@@ -0,0 +1,64 @@
+package testhelpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) { Main(m) }
+
+func TestFactories_InsertRowsWithOverrides(t *testing.T) {
+	t.Parallel()
+	pool := Pool(t)
+	ctx := context.Background()
+
+	buyer := NewUser(t, pool, WithRole("buyer"), WithVerified(), WithLocale("hi"))
+	var role, locale string
+	var verified bool
+	require.NoError(t, pool.QueryRow(ctx, "SELECT role, locale, verified_at IS NOT NULL FROM users WHERE id = $1", buyer.ID).Scan(&role, &locale, &verified))
+	require.Equal(t, "buyer", role)
+	require.Equal(t, "hi", locale)
+	require.True(t, verified)
+
+	startup := NewStartup(t, pool, WithStartupSold())
+	require.NotEmpty(t, startup.OwnerUUID)
+	var status string
+	require.NoError(t, pool.QueryRow(ctx, "SELECT status FROM startups WHERE id = $1", startup.ID).Scan(&status))
+	require.Equal(t, "sold", status)
+
+	asset := NewAsset(t, pool, WithAssetOwner(buyer.UUID), WithAssetType("domain"), WithPrice(250))
+	sale := NewTransaction(t, pool, WithTransactionAsset(asset.ID), WithBuyer(buyer.ID), WithFinalPrice(200))
+	require.NotZero(t, sale.ID)
+
+	msg := NewMessage(t, pool, WithSender(buyer.ID), WithRead())
+	require.NotZero(t, msg.ReceiverID)
+
+	otp := NewOTP(t, pool, WithOTPEmail(buyer.Email), WithOTPExpired())
+	var expired bool
+	require.NoError(t, pool.QueryRow(ctx, "SELECT expires_at < NOW() FROM otps WHERE id = $1", otp.ID).Scan(&expired))
+	require.True(t, expired)
+}
+
+func TestLoadFixtures_ResolvesRefs(t *testing.T) {
+	t.Parallel()
+	pool := Pool(t)
+	ctx := context.Background()
+
+	f := LoadFixtures(t, pool, "fixtures/marketplace.yaml")
+
+	founder := f.User(t, "founder")
+	domain := f.Asset(t, "domain")
+	require.Equal(t, founder.UUID, domain.UserUUID)
+	require.Equal(t, founder.UUID, f.Startups["shutdown"].OwnerUUID)
+
+	sale := f.Transactions["domain-sale"]
+	require.Equal(t, domain.ID, sale.AssetID)
+	require.Equal(t, f.User(t, "buyer").ID, sale.BuyerID)
+
+	var messages int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM messages WHERE $1 IN (sender_id, receiver_id)", founder.ID).Scan(&messages))
+	require.Equal(t, 2, messages)
+	require.Equal(t, f.User(t, "newcomer").Email, f.OTPs["pending"].Email)
+}
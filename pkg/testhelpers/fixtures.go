@@ -0,0 +1,355 @@
+package testhelpers
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+)
+
+// LoadFixtures inserts the rows declared in a YAML (or JSON) file using the
+// factories, so a scenario with several related rows reads as data rather than a
+// page of setup code:
+//
+//	users:
+//	  - ref: founder
+//	  - ref: buyer
+//	    role: buyer
+//	assets:
+//	  - ref: domain
+//	    owner: founder
+//	    type: domain
+//	    price: 250
+//	messages:
+//	  - from: buyer
+//	    to: founder
+//	    content: is this still for sale?
+//	    sent_ago: 2h
+//	transactions:
+//	  - asset: domain
+//	    buyer: buyer
+//
+// Rows may name themselves with ref and point at earlier rows by ref: owner, from,
+// to, buyer and user refer to users, asset to assets. Sections are inserted in the
+// order above regardless of their order in the file, and every column left out gets
+// the factory default. path is resolved against the test's package directory first,
+// then against this package, which holds the shared files under fixtures/.
+func LoadFixtures(t *testing.T, db Querier, path string) *Fixtures {
+	t.Helper()
+
+	raw, err := os.ReadFile(fixturePath(path))
+	require.NoError(t, err, "read fixtures")
+
+	var file fixtureFile
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	require.NoError(t, dec.Decode(&file), "parse fixtures %s", path)
+
+	f := &Fixtures{
+		Users:        map[string]UserFixture{},
+		Startups:     map[string]StartupFixture{},
+		Assets:       map[string]AssetFixture{},
+		Messages:     map[string]MessageFixture{},
+		OTPs:         map[string]OTPFixture{},
+		Transactions: map[string]TransactionFixture{},
+	}
+	for _, spec := range file.Users {
+		f.addUser(t, db, spec)
+	}
+	for _, spec := range file.Startups {
+		f.addStartup(t, db, spec)
+	}
+	for _, spec := range file.Assets {
+		f.addAsset(t, db, spec)
+	}
+	for _, spec := range file.Messages {
+		f.addMessage(t, db, spec)
+	}
+	for _, spec := range file.OTPs {
+		f.addOTP(t, db, spec)
+	}
+	for _, spec := range file.Transactions {
+		f.addTransaction(t, db, spec)
+	}
+	return f
+}
+
+// Fixtures holds the rows LoadFixtures inserted, keyed by ref. Rows without a ref
+// are inserted but not recorded.
+type Fixtures struct {
+	Users        map[string]UserFixture
+	Startups     map[string]StartupFixture
+	Assets       map[string]AssetFixture
+	Messages     map[string]MessageFixture
+	OTPs         map[string]OTPFixture
+	Transactions map[string]TransactionFixture
+}
+
+// User returns the user declared with ref, failing the test if there is none.
+func (f *Fixtures) User(t *testing.T, ref string) UserFixture {
+	t.Helper()
+	u, ok := f.Users[ref]
+	require.True(t, ok, "fixtures: no user %q", ref)
+	return u
+}
+
+// Asset returns the asset declared with ref, failing the test if there is none.
+func (f *Fixtures) Asset(t *testing.T, ref string) AssetFixture {
+	t.Helper()
+	a, ok := f.Assets[ref]
+	require.True(t, ok, "fixtures: no asset %q", ref)
+	return a
+}
+
+type fixtureFile struct {
+	Users        []userSpec        `yaml:"users"`
+	Startups     []startupSpec     `yaml:"startups"`
+	Assets       []assetSpec       `yaml:"assets"`
+	Messages     []messageSpec     `yaml:"messages"`
+	OTPs         []otpSpec         `yaml:"otps"`
+	Transactions []transactionSpec `yaml:"transactions"`
+}
+
+type userSpec struct {
+	Ref      string `yaml:"ref"`
+	Name     string `yaml:"name"`
+	Email    string `yaml:"email"`
+	Role     string `yaml:"role"`
+	Locale   string `yaml:"locale"`
+	Verified bool   `yaml:"verified"`
+	Deleted  bool   `yaml:"deleted"`
+}
+
+type startupSpec struct {
+	Ref         string `yaml:"ref"`
+	Owner       string `yaml:"owner"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Status      string `yaml:"status"`
+	Sold        bool   `yaml:"sold"`
+	Deleted     bool   `yaml:"deleted"`
+}
+
+type assetSpec struct {
+	Ref         string   `yaml:"ref"`
+	Owner       string   `yaml:"owner"`
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Type        string   `yaml:"type"`
+	Price       *float64 `yaml:"price"`
+	FixedPrice  bool     `yaml:"fixed_price"`
+	Sold        bool     `yaml:"sold"`
+	Inactive    bool     `yaml:"inactive"`
+	Deleted     bool     `yaml:"deleted"`
+}
+
+type messageSpec struct {
+	Ref     string `yaml:"ref"`
+	From    string `yaml:"from"`
+	To      string `yaml:"to"`
+	Content string `yaml:"content"`
+	Type    int16  `yaml:"type"`
+	Read    bool   `yaml:"read"`
+	// SentAgo is a time.ParseDuration string such as "90m"
+	SentAgo string `yaml:"sent_ago"`
+}
+
+type otpSpec struct {
+	Ref      string `yaml:"ref"`
+	User     string `yaml:"user"`
+	Email    string `yaml:"email"`
+	Code     string `yaml:"code"`
+	Verified bool   `yaml:"verified"`
+	Expired  bool   `yaml:"expired"`
+}
+
+type transactionSpec struct {
+	Ref   string   `yaml:"ref"`
+	Asset string   `yaml:"asset"`
+	Buyer string   `yaml:"buyer"`
+	Price *float64 `yaml:"price"`
+}
+
+func (f *Fixtures) addUser(t *testing.T, db Querier, s userSpec) {
+	t.Helper()
+
+	var opts []UserOption
+	if s.Name != "" {
+		opts = append(opts, WithName(s.Name))
+	}
+	if s.Email != "" {
+		opts = append(opts, WithEmail(s.Email))
+	}
+	if s.Role != "" {
+		opts = append(opts, WithRole(s.Role))
+	}
+	if s.Locale != "" {
+		opts = append(opts, WithLocale(s.Locale))
+	}
+	if s.Verified {
+		opts = append(opts, WithVerified())
+	}
+	if s.Deleted {
+		opts = append(opts, WithUserDeleted())
+	}
+	u := NewUser(t, db, opts...)
+	record(t, f.Users, "user", s.Ref, u)
+}
+
+func (f *Fixtures) addStartup(t *testing.T, db Querier, s startupSpec) {
+	t.Helper()
+
+	var opts []StartupOption
+	if s.Owner != "" {
+		opts = append(opts, WithStartupOwner(f.User(t, s.Owner).UUID))
+	}
+	if s.Name != "" {
+		opts = append(opts, WithStartupName(s.Name))
+	}
+	if s.Description != "" {
+		opts = append(opts, WithStartupDescription(s.Description))
+	}
+	if s.Status != "" {
+		opts = append(opts, WithStartupStatus(s.Status))
+	}
+	if s.Sold {
+		opts = append(opts, WithStartupSold())
+	}
+	if s.Deleted {
+		opts = append(opts, WithStartupDeleted())
+	}
+	st := NewStartup(t, db, opts...)
+	record(t, f.Startups, "startup", s.Ref, st)
+}
+
+func (f *Fixtures) addAsset(t *testing.T, db Querier, s assetSpec) {
+	t.Helper()
+
+	var opts []AssetOption
+	if s.Owner != "" {
+		opts = append(opts, WithAssetOwner(f.User(t, s.Owner).UUID))
+	}
+	if s.Title != "" {
+		opts = append(opts, WithAssetTitle(s.Title))
+	}
+	if s.Description != "" {
+		opts = append(opts, WithAssetDescription(s.Description))
+	}
+	if s.Type != "" {
+		opts = append(opts, WithAssetType(s.Type))
+	}
+	if s.Price != nil {
+		opts = append(opts, WithPrice(*s.Price))
+	}
+	if s.FixedPrice {
+		opts = append(opts, WithFixedPrice())
+	}
+	if s.Sold {
+		opts = append(opts, WithAssetSold())
+	}
+	if s.Inactive {
+		opts = append(opts, WithAssetInactive())
+	}
+	if s.Deleted {
+		opts = append(opts, WithAssetDeleted())
+	}
+	a := NewAsset(t, db, opts...)
+	record(t, f.Assets, "asset", s.Ref, a)
+}
+
+func (f *Fixtures) addMessage(t *testing.T, db Querier, s messageSpec) {
+	t.Helper()
+
+	var opts []MessageOption
+	if s.From != "" {
+		opts = append(opts, WithSender(f.User(t, s.From).ID))
+	}
+	if s.To != "" {
+		opts = append(opts, WithReceiver(f.User(t, s.To).ID))
+	}
+	if s.Content != "" {
+		opts = append(opts, WithContent(s.Content))
+	}
+	if s.Type != 0 {
+		opts = append(opts, WithMessageType(s.Type))
+	}
+	if s.Read {
+		opts = append(opts, WithRead())
+	}
+	if s.SentAgo != "" {
+		ago, err := time.ParseDuration(s.SentAgo)
+		require.NoError(t, err, "fixtures: message sent_ago")
+		opts = append(opts, WithMessagedAt(time.Now().Add(-ago)))
+	}
+	m := NewMessage(t, db, opts...)
+	record(t, f.Messages, "message", s.Ref, m)
+}
+
+func (f *Fixtures) addOTP(t *testing.T, db Querier, s otpSpec) {
+	t.Helper()
+
+	var opts []OTPOption
+	switch {
+	case s.Email != "":
+		opts = append(opts, WithOTPEmail(s.Email))
+	case s.User != "":
+		opts = append(opts, WithOTPEmail(f.User(t, s.User).Email))
+	}
+	if s.Code != "" {
+		opts = append(opts, WithOTPCode(s.Code))
+	}
+	if s.Verified {
+		opts = append(opts, WithOTPVerified())
+	}
+	if s.Expired {
+		opts = append(opts, WithOTPExpired())
+	}
+	o := NewOTP(t, db, opts...)
+	record(t, f.OTPs, "otp", s.Ref, o)
+}
+
+func (f *Fixtures) addTransaction(t *testing.T, db Querier, s transactionSpec) {
+	t.Helper()
+
+	var opts []TransactionOption
+	if s.Asset != "" {
+		opts = append(opts, WithTransactionAsset(f.Asset(t, s.Asset).ID))
+	}
+	if s.Buyer != "" {
+		opts = append(opts, WithBuyer(f.User(t, s.Buyer).ID))
+	}
+	if s.Price != nil {
+		opts = append(opts, WithFinalPrice(*s.Price))
+	}
+	tx := NewTransaction(t, db, opts...)
+	record(t, f.Transactions, "transaction", s.Ref, tx)
+}
+
+func record[T any](t *testing.T, rows map[string]T, kind, ref string, row T) {
+	t.Helper()
+	if ref == "" {
+		return
+	}
+	_, dup := rows[ref]
+	require.False(t, dup, "fixtures: duplicate %s ref %q", kind, ref)
+	rows[ref] = row
+}
+
+func fixturePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		return path
+	}
+	return filepath.Join(filepath.Dir(file), path)
+}
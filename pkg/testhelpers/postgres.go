@@ -0,0 +1,142 @@
+package testhelpers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	"grveyard/db"
+)
+
+// Integration tests get their database from, in order:
+//
+//   - DATABASE_URL_FOR_TEST, an existing database with the schema applied (CI)
+//   - TEST_DB_CONTAINER=1, a disposable Postgres started with testcontainers-go
+//
+// and are skipped otherwise. The container belongs to the test package: the first
+// test to need it starts it, and Main terminates it when the package finishes. Should
+// the process die before that, the testcontainers reaper removes it.
+const (
+	containerImage = "postgres:16-alpine"
+	dbPassword     = "grveyard"
+)
+
+var (
+	pkgDSNOnce   sync.Once
+	pkgDSN       string
+	pkgDSNErr    error
+	pkgContainer *postgres.PostgresContainer
+)
+
+// Main runs a package's tests and terminates the package's Postgres container
+// afterwards. Packages with integration tests call it from TestMain:
+//
+//	func TestMain(m *testing.M) { testhelpers.Main(m) }
+func Main(m *testing.M) {
+	code := m.Run()
+	if pkgContainer != nil {
+		if err := testcontainers.TerminateContainer(pkgContainer); err != nil {
+			fmt.Fprintf(os.Stderr, "testhelpers: terminate postgres container: %v\n", err)
+		}
+	}
+	os.Exit(code)
+}
+
+// Pool connects to the test database, skipping the test when none is configured.
+// Each test gets its own freshly migrated schema, set as the pool's search_path and
+// dropped when the test ends, so tests see only their own rows and may call
+// t.Parallel against a single database.
+func Pool(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := DatabaseURL(t)
+	ctx := context.Background()
+	schema := "test_" + randomSuffix()
+
+	admin, err := pgx.Connect(ctx, dsn)
+	require.NoError(t, err)
+	defer admin.Close(ctx)
+	_, err = admin.Exec(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn, err := pgx.Connect(context.Background(), dsn)
+		if err != nil {
+			t.Logf("drop schema %s: %v", schema, err)
+			return
+		}
+		defer conn.Close(context.Background())
+		if _, err := conn.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			t.Logf("drop schema %s: %v", schema, err)
+		}
+	})
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	require.NoError(t, err)
+	cfg.MaxConns = 4
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	require.NoError(t, err)
+	// Registered after the schema cleanup so it runs first: DROP SCHEMA waits for
+	// the pool's connections to go away
+	t.Cleanup(pool.Close)
+	require.NoError(t, db.ApplySchema(ctx, pool))
+	return pool
+}
+
+// DatabaseURL returns the DSN of the package's test database, starting the package's
+// container on first use when TEST_DB_CONTAINER=1.
+func DatabaseURL(t testing.TB) string {
+	t.Helper()
+
+	if dsn := os.Getenv("DATABASE_URL_FOR_TEST"); dsn != "" {
+		return dsn
+	}
+	if os.Getenv("TEST_DB_CONTAINER") != "1" {
+		t.Skip("DATABASE_URL_FOR_TEST not set and TEST_DB_CONTAINER != 1; skipping integration tests")
+	}
+
+	pkgDSNOnce.Do(func() {
+		pkgDSN, pkgDSNErr = startContainer(context.Background())
+	})
+	require.NoError(t, pkgDSNErr, "start test Postgres container")
+	return pkgDSN
+}
+
+// startContainer runs Postgres for this package and returns its DSN. Durability is
+// switched off since the data only lives as long as the tests, and the connection
+// limit raised for packages whose tests run in parallel.
+func startContainer(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	ctr, err := postgres.Run(ctx, containerImage,
+		postgres.WithPassword(dbPassword),
+		postgres.BasicWaitStrategies(),
+		testcontainers.WithCmdArgs("-c", "full_page_writes=off", "-c", "max_connections=200"),
+		testcontainers.WithLabels(map[string]string{"grveyard.test": "true"}),
+	)
+	// Run may hand back a container that failed to become ready; Main still stops it
+	pkgContainer = ctr
+	if err != nil {
+		return "", err
+	}
+	return ctr.ConnectionString(ctx, "sslmode=disable")
+}
+
+func randomSuffix() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,291 @@
+// Package seed fills a database with pseudo-random but realistic marketplace data
+// for load tests and staging. Output depends only on Config: the same Seed and
+// volumes produce the same users, listings, chats and sales every time.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"grveyard/pkg/testhelpers"
+)
+
+// PasswordHash is the bcrypt hash every generated user gets, matching db/seed.sql:
+// the password is "graveyard".
+const PasswordHash = "$2a$10$Pb0sHpllSrgsoqC/yCfsV.coHXJoIazmsCK7obzZbh7PTNqkyXJgC"
+
+// Config sets the volume of generated data. Zero fields take the defaults below.
+type Config struct {
+	Seed     uint64 // RNG seed; different seeds produce disjoint users
+	Users    int    // total users, roughly one founder for every two buyers (default 50)
+	Startups int    // max startups per founder (default 2)
+	Assets   int    // max assets per founder (default 4)
+	Chats    int    // max conversations per buyer (default 3)
+	Messages int    // max messages per conversation (default 8)
+	SoldPct  int    // share of assets sold to a buyer, in percent (default 15)
+	Now      time.Time
+}
+
+func (c Config) withDefaults() Config {
+	if c.Users <= 0 {
+		c.Users = 50
+	}
+	if c.Startups <= 0 {
+		c.Startups = 2
+	}
+	if c.Assets <= 0 {
+		c.Assets = 4
+	}
+	if c.Chats <= 0 {
+		c.Chats = 3
+	}
+	if c.Messages <= 0 {
+		c.Messages = 8
+	}
+	if c.SoldPct <= 0 {
+		c.SoldPct = 15
+	}
+	if c.Now.IsZero() {
+		c.Now = time.Now()
+	}
+	return c
+}
+
+// Summary counts the rows Generate inserted.
+type Summary struct {
+	Users        int
+	Startups     int
+	Assets       int
+	Messages     int
+	Transactions int
+}
+
+// ErrAlreadyGenerated is returned when the data for Config.Seed is already present.
+var ErrAlreadyGenerated = errors.New("seed: data for this seed already exists")
+
+// Generate inserts the data described by cfg. Rows are inserted one by one through
+// the testhelpers factories, so run it inside a transaction if a partial result on
+// error is unwanted.
+func Generate(ctx context.Context, db testhelpers.Querier, cfg Config) (sum Summary, err error) {
+	cfg = cfg.withDefaults()
+	g := &generator{
+		db:  db,
+		cfg: cfg,
+		rng: rand.New(rand.NewPCG(cfg.Seed, 0x67726576)),
+	}
+
+	var exists bool
+	if err := db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE email LIKE $1)", "%"+g.domain()).Scan(&exists); err != nil {
+		return sum, err
+	}
+	if exists {
+		return sum, ErrAlreadyGenerated
+	}
+
+	// Factories report failures through t.FailNow; turn that back into an error
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(failNow); !ok {
+				panic(r)
+			}
+			sum, err = g.sum, g.t.err
+		}
+	}()
+
+	var founders, buyers []testhelpers.UserFixture
+	for i := range cfg.Users {
+		if err := ctx.Err(); err != nil {
+			return g.sum, err
+		}
+		u := g.user(i)
+		if u.Role == "founder" {
+			founders = append(founders, u)
+		} else {
+			buyers = append(buyers, u)
+		}
+	}
+
+	for _, f := range founders {
+		if err := ctx.Err(); err != nil {
+			return g.sum, err
+		}
+		for range g.rng.IntN(cfg.Startups + 1) {
+			g.startup(f)
+		}
+		for range 1 + g.rng.IntN(cfg.Assets) {
+			g.asset(f, buyers)
+		}
+	}
+
+	if len(founders) > 0 {
+		for _, b := range buyers {
+			if err := ctx.Err(); err != nil {
+				return g.sum, err
+			}
+			for range g.rng.IntN(cfg.Chats + 1) {
+				g.conversation(b, founders[g.rng.IntN(len(founders))])
+			}
+		}
+	}
+	return g.sum, nil
+}
+
+type generator struct {
+	db  testhelpers.Querier
+	cfg Config
+	rng *rand.Rand
+	t   tb
+	sum Summary
+}
+
+func (g *generator) user(i int) testhelpers.UserFixture {
+	role := "buyer"
+	if i%3 == 0 {
+		role = "founder"
+	}
+	first, last := pick(g.rng, firstNames), pick(g.rng, lastNames)
+	opts := []testhelpers.UserOption{
+		testhelpers.WithName(first + " " + last),
+		testhelpers.WithEmail(g.email(i, first+"."+last)),
+		testhelpers.WithRole(role),
+		testhelpers.WithUUID(g.uuid()),
+		testhelpers.WithPasswordHash(PasswordHash),
+	}
+	if g.rng.IntN(10) < 8 {
+		opts = append(opts, testhelpers.WithVerifiedAt(g.ago(90*24*time.Hour)))
+	}
+	if g.rng.IntN(10) == 0 {
+		opts = append(opts, testhelpers.WithLocale("hi"))
+	}
+	g.sum.Users++
+	return testhelpers.NewUser(&g.t, g.db, opts...)
+}
+
+// email is unique per seed and index. Each seed gets its own domain, which is how
+// Generate recognizes a previous run.
+func (g *generator) email(i int, name string) string {
+	return fmt.Sprintf("%s.%d%s", strings.ToLower(name), i, g.domain())
+}
+
+func (g *generator) domain() string {
+	return fmt.Sprintf("@seed%d.example.com", g.cfg.Seed)
+}
+
+func (g *generator) startup(owner testhelpers.UserFixture) {
+	product := pick(g.rng, products)
+	opts := []testhelpers.StartupOption{
+		testhelpers.WithStartupOwner(owner.UUID),
+		testhelpers.WithStartupName(pick(g.rng, prefixes) + product.suffix),
+		testhelpers.WithStartupDescription(fmt.Sprintf("%s for %s, %s", product.pitch, pick(g.rng, audiences), pick(g.rng, endings))),
+		testhelpers.WithStartupStatus("failed"),
+	}
+	switch g.rng.IntN(10) {
+	case 0:
+		opts = append(opts, testhelpers.WithStartupSold())
+	case 1, 2:
+		opts = append(opts, testhelpers.WithStartupStatus("active"))
+	}
+	g.sum.Startups++
+	testhelpers.NewStartup(&g.t, g.db, opts...)
+}
+
+func (g *generator) asset(owner testhelpers.UserFixture, buyers []testhelpers.UserFixture) {
+	kind := pick(g.rng, assetKinds)
+	product := pick(g.rng, products)
+	opts := []testhelpers.AssetOption{
+		testhelpers.WithAssetOwner(owner.UUID),
+		testhelpers.WithAssetType(kind.assetType),
+		testhelpers.WithAssetTitle(fmt.Sprintf(kind.title, strings.ToLower(pick(g.rng, prefixes)+product.suffix))),
+		testhelpers.WithAssetDescription(kind.description),
+		// Prices cluster around each kind's typical value, rounded to 10
+		testhelpers.WithPrice(float64(int(kind.price*(0.3+g.rng.Float64()*1.7)) / 10 * 10)),
+	}
+	if g.rng.IntN(4) == 0 {
+		opts = append(opts, testhelpers.WithFixedPrice())
+	}
+	sold := len(buyers) > 0 && g.rng.IntN(100) < g.cfg.SoldPct
+	if sold {
+		opts = append(opts, testhelpers.WithAssetSold())
+	}
+	g.sum.Assets++
+	a := testhelpers.NewAsset(&g.t, g.db, opts...)
+
+	if sold {
+		discount := 0.8 + g.rng.Float64()*0.2
+		g.sum.Transactions++
+		testhelpers.NewTransaction(&g.t, g.db,
+			testhelpers.WithTransactionAsset(a.ID),
+			testhelpers.WithBuyer(buyers[g.rng.IntN(len(buyers))].ID),
+			testhelpers.WithFinalPrice(float64(int(a.Price*discount))),
+		)
+	}
+}
+
+// conversation alternates messages between a buyer and a founder, oldest first,
+// leaving the latest few unread.
+func (g *generator) conversation(buyer, founder testhelpers.UserFixture) {
+	n := 1 + g.rng.IntN(g.cfg.Messages)
+	at := g.ago(30 * 24 * time.Hour)
+	for i := range n {
+		from, to, lines := buyer, founder, buyerLines
+		if i%2 == 1 {
+			from, to, lines = founder, buyer, founderLines
+		}
+		at = at.Add(time.Duration(1+g.rng.IntN(180)) * time.Minute)
+		opts := []testhelpers.MessageOption{
+			testhelpers.WithSender(from.ID),
+			testhelpers.WithReceiver(to.ID),
+			testhelpers.WithContent(pick(g.rng, lines)),
+			testhelpers.WithMessagedAt(at),
+		}
+		if i < n-2 {
+			opts = append(opts, testhelpers.WithRead())
+		}
+		g.sum.Messages++
+		testhelpers.NewMessage(&g.t, g.db, opts...)
+	}
+}
+
+// ago is a random moment up to max before Config.Now.
+func (g *generator) ago(max time.Duration) time.Time {
+	return g.cfg.Now.Add(-time.Duration(g.rng.Int64N(int64(max))))
+}
+
+func (g *generator) uuid() string {
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(g.rng.Uint32())
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return uuid.UUID(b).String()
+}
+
+func pick[T any](rng *rand.Rand, items []T) T {
+	return items[rng.IntN(len(items))]
+}
+
+// tb adapts the factories' failure reporting outside of tests.
+type tb struct {
+	err error
+}
+
+type failNow struct{}
+
+func (t *tb) Helper() {}
+
+func (t *tb) Errorf(format string, args ...any) {
+	if t.err == nil {
+		t.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (t *tb) FailNow() {
+	panic(failNow{})
+}
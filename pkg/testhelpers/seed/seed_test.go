@@ -0,0 +1,55 @@
+package seed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
+func TestGenerate_SameSeedSameData(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	cfg := Config{Seed: 7, Users: 12, Now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	first, second := testhelpers.Pool(t), testhelpers.Pool(t)
+	sum, err := Generate(ctx, first, cfg)
+	require.NoError(t, err)
+	require.Equal(t, 12, sum.Users)
+	require.NotZero(t, sum.Assets)
+	again, err := Generate(ctx, second, cfg)
+	require.NoError(t, err)
+	require.Equal(t, sum, again)
+	require.Equal(t, snapshot(t, first), snapshot(t, second))
+
+	_, err = Generate(ctx, first, cfg)
+	require.ErrorIs(t, err, ErrAlreadyGenerated)
+}
+
+// snapshot lists the generated rows without their serial IDs.
+func snapshot(t *testing.T, pool *pgxpool.Pool) []string {
+	t.Helper()
+	rows, err := pool.Query(context.Background(), `
+		SELECT u.uuid || '|' || u.email || '|' || u.role FROM users u
+		UNION ALL
+		SELECT a.user_uuid || '|' || a.title || '|' || a.price::text FROM assets a
+		UNION ALL
+		SELECT s.uuid || '|' || m.content || '|' || m.messaged_at::text FROM messages m JOIN users s ON s.id = m.sender_id
+		ORDER BY 1`)
+	require.NoError(t, err)
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var line string
+		require.NoError(t, rows.Scan(&line))
+		out = append(out, line)
+	}
+	require.NoError(t, rows.Err())
+	return out
+}
@@ -0,0 +1,87 @@
+package seed
+
+var firstNames = []string{
+	"Aarav", "Ananya", "Arjun", "Chloe", "Daniel", "Diya", "Elena", "Farhan", "Grace", "Hiro",
+	"Isha", "Jonas", "Kavya", "Liam", "Maya", "Mateo", "Neha", "Noah", "Olivia", "Priya",
+	"Rahul", "Rohan", "Sara", "Tariq", "Uma", "Vikram", "Wei", "Yusuf", "Zara", "Zoe",
+}
+
+var lastNames = []string{
+	"Agarwal", "Baker", "Chen", "Desai", "Evans", "Fernandes", "Garcia", "Gupta", "Haddad", "Iyer",
+	"Jensen", "Kapoor", "Kim", "Mehta", "Nakamura", "Okafor", "Patel", "Rathod", "Reddy", "Rossi",
+	"Schmidt", "Shah", "Singh", "Tanaka", "Verma", "Walker",
+}
+
+var prefixes = []string{
+	"Acme", "Bright", "Cloud", "Dash", "Echo", "Flux", "Grid", "Hive", "Insta", "Jolt",
+	"Kite", "Loop", "Mint", "Nova", "Orbit", "Pixel", "Quill", "Rally", "Spark", "Tidy",
+}
+
+type product struct {
+	suffix string
+	pitch  string
+}
+
+var products = []product{
+	{"CRM", "Lightweight CRM"},
+	{"Pay", "Split payments"},
+	{"Desk", "Shared help desk"},
+	{"Fit", "Habit-tracking fitness app"},
+	{"Eats", "Meal-kit delivery"},
+	{"Learn", "Micro-courses"},
+	{"Docs", "Collaborative contracts"},
+	{"Ship", "Last-mile logistics"},
+	{"Pets", "On-demand pet care"},
+	{"Ads", "Self-serve ad buying"},
+}
+
+var audiences = []string{
+	"freelancers", "small restaurants", "college students", "indie game studios", "dentists",
+	"remote teams", "farmers' co-ops", "wedding planners", "landlords", "local gyms",
+}
+
+var endings = []string{
+	"shut down after the seed round ran out",
+	"never found product-market fit",
+	"outcompeted by a free incumbent",
+	"founders moved on to new jobs",
+	"paused after a failed pivot",
+	"wound down when the main customer churned",
+}
+
+type assetKind struct {
+	assetType   string
+	title       string // %s is a lower-cased product name
+	description string
+	price       float64
+}
+
+var assetKinds = []assetKind{
+	{"domain", "%s.io", "Premium domain, renewed for two more years", 900},
+	{"domain", "%s.com", "Short .com with steady type-in traffic", 2500},
+	{"codebase", "%s codebase", "Go + React monorepo with auth, billing and CI", 6000},
+	{"codebase", "%s mobile app", "Flutter app with 4.5 stars and store listings included", 4000},
+	{"data", "%s user survey data", "Anonymised survey responses with summary deck", 350},
+	{"product", "%s SaaS", "Running product with a handful of paying customers", 12000},
+	{"research", "%s market research", "Interviews, personas and competitor teardown", 200},
+	{"other", "%s brand kit", "Logo, design system and social handles", 150},
+}
+
+var buyerLines = []string{
+	"Hi! Is this still available?",
+	"Could you share traffic or revenue numbers?",
+	"Would you consider a lower price?",
+	"What's included in the sale?",
+	"Can we do a quick call this week?",
+	"Does it come with the existing customer list?",
+	"Great, I'm ready to move ahead.",
+}
+
+var founderLines = []string{
+	"Yes, it's still available.",
+	"Sure, I'll send the numbers over shortly.",
+	"I'm open to reasonable offers.",
+	"Everything in the listing plus a handover call.",
+	"Happy to jump on a call, what time works?",
+	"Thanks for the interest!",
+}
@@ -0,0 +1,144 @@
+package users
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/apperr"
+)
+
+var (
+	ErrAccountBanned  = apperr.New(apperr.AccountBanned, "this account has been suspended")
+	ErrCannotBanAdmin = apperr.New(apperr.Forbidden, "admins cannot be banned")
+)
+
+type BanRepository interface {
+	// Ban marks the user banned, keeping the time of an earlier ban, and soft-deletes
+	// their listings, flagging them to come back on Unban. It returns ErrUserNotFound
+	// if there is no such user.
+	Ban(ctx context.Context, uuid, reason string) error
+	// Unban lifts a ban and restores the listings Ban hid. Lifting one that does not
+	// exist is not an error.
+	Unban(ctx context.Context, uuid string) error
+	IsBanned(ctx context.Context, uuid string) (bool, error)
+}
+
+// Disconnecter drops a user's live chat connection; *chat.ConnectionManager in
+// production.
+type Disconnecter interface {
+	Disconnect(userID string)
+}
+
+// BanService lets admins suspend accounts. A banned user cannot log in or refresh a
+// session, their listings disappear from the marketplace and they are dropped from
+// chat. Access tokens already issued stay valid until they expire.
+type BanService struct {
+	repo     BanRepository
+	users    UserRepository
+	sessions SessionRevoker
+	chat     Disconnecter // optional; if nil, chat connections are left open
+}
+
+func NewBanService(repo BanRepository, users UserRepository, sessions SessionRevoker, chat Disconnecter) *BanService {
+	return &BanService{repo: repo, users: users, sessions: sessions, chat: chat}
+}
+
+func (s *BanService) Ban(ctx context.Context, uuid, reason string) error {
+	u, err := s.users.GetUserByUUID(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if u.Role == RoleAdmin {
+		return ErrCannotBanAdmin
+	}
+	if err := s.repo.Ban(ctx, uuid, reason); err != nil {
+		return err
+	}
+	if err := s.sessions.LogoutUser(ctx, uuid); err != nil {
+		return err
+	}
+	if s.chat != nil {
+		s.chat.Disconnect(uuid)
+	}
+	return nil
+}
+
+func (s *BanService) Unban(ctx context.Context, uuid string) error {
+	if _, err := s.users.GetUserByUUID(ctx, uuid); err != nil {
+		return err
+	}
+	return s.repo.Unban(ctx, uuid)
+}
+
+// IsBanned reports whether the user is banned; chat uses it before accepting a
+// connection.
+func (s *BanService) IsBanned(ctx context.Context, uuid string) (bool, error) {
+	return s.repo.IsBanned(ctx, uuid)
+}
+
+type postgresBanRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresBanRepository(pool *pgxpool.Pool) BanRepository {
+	return &postgresBanRepository{pool: pool}
+}
+
+func (r *postgresBanRepository) Ban(ctx context.Context, uuid, reason string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	cmd, err := tx.Exec(ctx, `
+		UPDATE users SET banned_at = COALESCE(banned_at, NOW()), ban_reason = $2
+		WHERE uuid = $1 AND is_deleted = false`, uuid, reason)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE assets SET is_deleted = true, hidden_by_ban = true, updated_at = NOW()
+		WHERE user_uuid = $1 AND is_deleted = false`, uuid); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE startups SET is_deleted = true, hidden_by_ban = true, updated_at = NOW()
+		WHERE owner_uuid = $1 AND is_deleted = false`, uuid); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *postgresBanRepository) Unban(ctx context.Context, uuid string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE users SET banned_at = NULL, ban_reason = '' WHERE uuid = $1`, uuid); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE assets SET is_deleted = false, hidden_by_ban = false, updated_at = NOW()
+		WHERE user_uuid = $1 AND hidden_by_ban = true`, uuid); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE startups SET is_deleted = false, hidden_by_ban = false, updated_at = NOW()
+		WHERE owner_uuid = $1 AND hidden_by_ban = true`, uuid); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *postgresBanRepository) IsBanned(ctx context.Context, uuid string) (bool, error) {
+	var banned bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE uuid = $1 AND banned_at IS NOT NULL)`, uuid).Scan(&banned)
+	return banned, err
+}
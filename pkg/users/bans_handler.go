@@ -0,0 +1,84 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/admin"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+type banRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// BanHandler serves account bans under /admin.
+type BanHandler struct {
+	service *BanService
+	token   string
+}
+
+// NewBanHandler serves the ban routes to callers presenting token as a bearer token,
+// like the other admin endpoints, and to admins signed in with their own access
+// token.
+func NewBanHandler(service *BanService, token string) *BanHandler {
+	return &BanHandler{service: service, token: token}
+}
+
+func (h *BanHandler) RegisterRoutes(router *gin.Engine) {
+	group := router.Group("/admin/users", admin.RequireTokenOrPermission(h.token, auth.PermBanUsers))
+	group.POST("/:uuid/ban", h.ban)
+	group.POST("/:uuid/unban", h.unban)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *BanHandler) Operations() []openapi.Operation {
+	params := []openapi.Param{openapi.Path("uuid", "string", "User UUID")}
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/admin/users/:uuid/ban",
+			Tag:         "admin",
+			Summary:     "Ban a user",
+			Description: "Suspends the account: its sessions are revoked, logging in fails with ACCOUNT_BANNED, its listings are hidden and its chat connection is closed. Access tokens already issued work until they expire. Admins cannot be banned.",
+			Params:      params,
+			Request:     banRequest{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/admin/users/:uuid/unban",
+			Tag:         "admin",
+			Summary:     "Unban a user",
+			Description: "Lifts a ban and brings back the listings it hid. The user has to log in again.",
+			Params:      params,
+			Errors:      []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+	}
+}
+
+func (h *BanHandler) ban(c *gin.Context) {
+	var req banRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	if err := h.service.Ban(c.Request.Context(), c.Param("uuid"), req.Reason); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "user banned", nil)
+}
+
+func (h *BanHandler) unban(c *gin.Context) {
+	if err := h.service.Unban(c.Request.Context(), c.Param("uuid")); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "user unbanned", nil)
+}
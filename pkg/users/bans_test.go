@@ -0,0 +1,97 @@
+package users
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBanRepository struct {
+	banned map[string]string
+}
+
+func (f *fakeBanRepository) Ban(_ context.Context, uuid, reason string) error {
+	f.banned[uuid] = reason
+	return nil
+}
+
+func (f *fakeBanRepository) Unban(_ context.Context, uuid string) error {
+	delete(f.banned, uuid)
+	return nil
+}
+
+func (f *fakeBanRepository) IsBanned(_ context.Context, uuid string) (bool, error) {
+	_, ok := f.banned[uuid]
+	return ok, nil
+}
+
+type fakeRevoker struct{ loggedOut []string }
+
+func (f *fakeRevoker) LogoutUser(_ context.Context, userUUID string) error {
+	f.loggedOut = append(f.loggedOut, userUUID)
+	return nil
+}
+
+type fakeDisconnecter struct{ dropped []string }
+
+func (f *fakeDisconnecter) Disconnect(userID string) { f.dropped = append(f.dropped, userID) }
+
+func TestBanService_Ban(t *testing.T) {
+	repo := &fakeBanRepository{banned: map[string]string{}}
+	users := new(mockUserRepository)
+	sessions := &fakeRevoker{}
+	chat := &fakeDisconnecter{}
+	svc := NewBanService(repo, users, sessions, chat)
+	ctx := context.Background()
+
+	users.On("GetUserByUUID", mock.Anything, "u1").Return(User{UUID: "u1", Role: "founder"}, nil)
+	users.On("GetUserByUUID", mock.Anything, "admin").Return(User{UUID: "admin", Role: RoleAdmin}, nil)
+	users.On("GetUserByUUID", mock.Anything, "missing").Return(User{}, ErrUserNotFound)
+
+	require.NoError(t, svc.Ban(ctx, "u1", "spam"))
+	require.Equal(t, "spam", repo.banned["u1"])
+	require.Equal(t, []string{"u1"}, sessions.loggedOut)
+	require.Equal(t, []string{"u1"}, chat.dropped)
+
+	require.ErrorIs(t, svc.Ban(ctx, "admin", "spam"), ErrCannotBanAdmin)
+	require.ErrorIs(t, svc.Ban(ctx, "missing", "spam"), ErrUserNotFound)
+	require.NotContains(t, repo.banned, "admin")
+
+	require.NoError(t, svc.Unban(ctx, "u1"))
+	banned, err := svc.IsBanned(ctx, "u1")
+	require.NoError(t, err)
+	require.False(t, banned)
+}
+
+func TestBanHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &fakeBanRepository{banned: map[string]string{}}
+	users := new(mockUserRepository)
+	users.On("GetUserByUUID", mock.Anything, "u1").Return(User{UUID: "u1", Role: "buyer"}, nil)
+	router := gin.New()
+	NewBanHandler(NewBanService(repo, users, &fakeRevoker{}, nil), "admin-token").RegisterRoutes(router)
+
+	post := func(path, body, token string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusUnauthorized, post("/admin/users/u1/ban", `{"reason":"spam"}`, ""))
+	require.Equal(t, http.StatusBadRequest, post("/admin/users/u1/ban", `{}`, "admin-token"))
+	require.Equal(t, http.StatusOK, post("/admin/users/u1/ban", `{"reason":"spam"}`, "admin-token"))
+	require.Contains(t, repo.banned, "u1")
+	require.Equal(t, http.StatusOK, post("/admin/users/u1/unban", "", "admin-token"))
+	require.NotContains(t, repo.banned, "u1")
+}
@@ -0,0 +1,118 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/apperr"
+)
+
+var ErrCannotBlockSelf = apperr.New(apperr.CannotBlockSelf, "you cannot block yourself")
+
+// BlockedUser is an entry in a user's block list.
+type BlockedUser struct {
+	UUID      string    `json:"uuid"`
+	Name      string    `json:"name"`
+	BlockedAt time.Time `json:"blocked_at"`
+}
+
+type BlockRepository interface {
+	// Block records that blocker blocked target; blocking again is a no-op.
+	Block(ctx context.Context, blockerUUID, targetUUID string) error
+	Unblock(ctx context.Context, blockerUUID, targetUUID string) error
+	// ListBlocked returns the users blocker blocked, most recent first.
+	ListBlocked(ctx context.Context, blockerUUID string) ([]BlockedUser, error)
+	// EitherBlocked reports whether a blocked b or b blocked a.
+	EitherBlocked(ctx context.Context, a, b string) (bool, error)
+}
+
+// BlockService lets users block others. A block stops chat between the two users in
+// both directions and hides the blocker's listings from the blocked user.
+type BlockService struct {
+	repo  BlockRepository
+	users UserRepository
+}
+
+func NewBlockService(repo BlockRepository, users UserRepository) *BlockService {
+	return &BlockService{repo: repo, users: users}
+}
+
+func (s *BlockService) Block(ctx context.Context, blockerUUID, targetUUID string) error {
+	if blockerUUID == targetUUID {
+		return ErrCannotBlockSelf
+	}
+	if _, err := s.users.GetUserByUUID(ctx, targetUUID); err != nil {
+		return err
+	}
+	return s.repo.Block(ctx, blockerUUID, targetUUID)
+}
+
+// Unblock lifts a block. Lifting one that does not exist is not an error.
+func (s *BlockService) Unblock(ctx context.Context, blockerUUID, targetUUID string) error {
+	return s.repo.Unblock(ctx, blockerUUID, targetUUID)
+}
+
+func (s *BlockService) ListBlocked(ctx context.Context, blockerUUID string) ([]BlockedUser, error) {
+	return s.repo.ListBlocked(ctx, blockerUUID)
+}
+
+// IsBlocked reports whether either user blocked the other; chat uses it before
+// delivering a message.
+func (s *BlockService) IsBlocked(ctx context.Context, a, b string) (bool, error) {
+	return s.repo.EitherBlocked(ctx, a, b)
+}
+
+type postgresBlockRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresBlockRepository(pool *pgxpool.Pool) BlockRepository {
+	return &postgresBlockRepository{pool: pool}
+}
+
+func (r *postgresBlockRepository) Block(ctx context.Context, blockerUUID, targetUUID string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO blocked_users (blocker_uuid, blocked_uuid) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, blockerUUID, targetUUID)
+	return err
+}
+
+func (r *postgresBlockRepository) Unblock(ctx context.Context, blockerUUID, targetUUID string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM blocked_users WHERE blocker_uuid = $1 AND blocked_uuid = $2`, blockerUUID, targetUUID)
+	return err
+}
+
+func (r *postgresBlockRepository) ListBlocked(ctx context.Context, blockerUUID string) ([]BlockedUser, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT u.uuid, u.name, b.created_at
+		FROM blocked_users b
+		JOIN users u ON u.uuid = b.blocked_uuid
+		WHERE b.blocker_uuid = $1
+		ORDER BY b.created_at DESC, u.uuid`, blockerUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []BlockedUser{}
+	for rows.Next() {
+		var b BlockedUser
+		if err := rows.Scan(&b.UUID, &b.Name, &b.BlockedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, b)
+	}
+	return list, rows.Err()
+}
+
+func (r *postgresBlockRepository) EitherBlocked(ctx context.Context, a, b string) (bool, error) {
+	var blocked bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM blocked_users
+			WHERE (blocker_uuid = $1 AND blocked_uuid = $2) OR (blocker_uuid = $2 AND blocked_uuid = $1)
+		)`, a, b).Scan(&blocked)
+	return blocked, err
+}
@@ -0,0 +1,89 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+type BlockHandler struct {
+	service *BlockService
+}
+
+func NewBlockHandler(service *BlockService) *BlockHandler {
+	return &BlockHandler{service: service}
+}
+
+func (h *BlockHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/users/:uuid/blocked", auth.RequireSelf("uuid"), h.listBlocked)
+	router.POST("/users/:uuid/block/:target", auth.RequireSelf("uuid"), h.block)
+	router.DELETE("/users/:uuid/block/:target", auth.RequireSelf("uuid"), h.unblock)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *BlockHandler) Operations() []openapi.Operation {
+	params := []openapi.Param{
+		openapi.Path("uuid", "string", "Blocking user UUID"),
+		openapi.Path("target", "string", "UUID of the user to block"),
+	}
+	return []openapi.Operation{
+		{
+			Method:   http.MethodGet,
+			Path:     "/users/:uuid/blocked",
+			Tag:      "users",
+			Summary:  "List blocked users",
+			Params:   []openapi.Param{openapi.Path("uuid", "string", "User UUID")},
+			Response: []BlockedUser{},
+			Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/users/:uuid/block/:target",
+			Tag:         "users",
+			Summary:     "Block a user",
+			Description: "Chat messages between the two users are rejected in both directions, and the blocker's listings are left out of GET /assets and GET /startups for the target. Blocking again is a no-op.",
+			Params:      params,
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/users/:uuid/block/:target",
+			Tag:     "users",
+			Summary: "Unblock a user",
+			Params:  params,
+			Errors:  []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:    true,
+		},
+	}
+}
+
+func (h *BlockHandler) listBlocked(c *gin.Context) {
+	list, err := h.service.ListBlocked(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "blocked users listed", list)
+}
+
+func (h *BlockHandler) block(c *gin.Context) {
+	if err := h.service.Block(c.Request.Context(), c.Param("uuid"), c.Param("target")); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "user blocked", nil)
+}
+
+func (h *BlockHandler) unblock(c *gin.Context) {
+	if err := h.service.Unblock(c.Request.Context(), c.Param("uuid"), c.Param("target")); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "user unblocked", nil)
+}
@@ -1,102 +1,328 @@
 package users
 
 import (
+	"context"
+	"errors"
+	"log"
 	"net/http"
-	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
 	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
 
 	"github.com/gin-gonic/gin"
 )
 
+// SessionStarter issues the tokens users get at login; *auth.Service in production.
+type SessionStarter interface {
+	StartSession(ctx context.Context, userUUID, role string, device auth.Device) (auth.Session, error)
+}
+
+// TwoFactorChecker asks for the second factor at login; *TwoFactorService in
+// production.
+type TwoFactorChecker interface {
+	CheckLogin(ctx context.Context, userUUID, code string) error
+}
+
+// ReferralAttributor credits a signup to the owner of an invite code;
+// *ReferralService in production.
+type ReferralAttributor interface {
+	Attribute(ctx context.Context, code, referredUUID string) error
+}
+
 type UserHandler struct {
-	service UserService
+	service   UserService
+	sessions  SessionStarter     // optional; if nil, login returns the user without tokens
+	twoFactor TwoFactorChecker   // optional
+	events    auth.EventRecorder // optional
+	referrals ReferralAttributor // optional
 }
 
-func NewUserHandler(service UserService) *UserHandler {
-	return &UserHandler{service: service}
+func NewUserHandler(service UserService, sessions SessionStarter) *UserHandler {
+	return &UserHandler{service: service, sessions: sessions}
+}
+
+// SetTwoFactor makes login require totp_code for users who turned on two-factor
+// authentication.
+func (h *UserHandler) SetTwoFactor(twoFactor TwoFactorChecker) {
+	h.twoFactor = twoFactor
+}
+
+// SetEvents records logins and failed attempts in the security log.
+func (h *UserHandler) SetEvents(events auth.EventRecorder) {
+	h.events = events
+}
+
+// SetReferrals credits signups made with an invite code in referred_by.
+func (h *UserHandler) SetReferrals(referrals ReferralAttributor) {
+	h.referrals = referrals
 }
 
 func (h *UserHandler) RegisterRoutes(router *gin.Engine) {
 	router.POST("/users", h.createUser)
 	router.POST("/users/login", h.login)
 	router.GET("/users/checkVerification", h.checkVerification)
-	router.PUT("/users/:uuid", h.updateUser)
-	router.DELETE("/users/:uuid", h.deleteUser)
+	router.PUT("/users/:uuid", auth.RequireSelf("uuid"), h.updateUser)
+	router.PATCH("/users/:uuid", auth.RequireSelf("uuid"), h.patchUser)
+	router.DELETE("/users/:uuid", auth.RequireSelf("uuid"), h.deleteUser)
+	router.PUT("/users/:uuid/role", auth.RequirePermission(auth.PermManageRoles), h.setRole)
 	router.GET("/users", h.listUsers)
+	router.GET("/users/search", auth.Required(), h.searchUsers)
 	router.GET("/users/:uuid", h.getUserByUUID)
+	router.GET("/users/:uuid/profile", h.getPublicProfile)
+	router.GET("/u/:handle", h.getProfileByHandle)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *UserHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/users",
+			Tag:         "users",
+			Summary:     "Create user",
+			Description: "referred_by takes another user's invite code and credits them with the signup; unknown codes are ignored.",
+			Request:     createUserRequest{},
+			Response:    User{},
+			Status:      http.StatusCreated,
+			Errors:      []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/users/:uuid",
+			Tag:     "users",
+			Summary: "Update user (by UUID)",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Request:  updateUserRequest{},
+			Response: User{},
+			Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodPatch,
+			Path:        "/users/:uuid",
+			Tag:         "users",
+			Summary:     "Partially update user (by UUID)",
+			Description: "Only the fields present are changed. Changing country without region clears the region; an empty profile_pic_url or handle removes it. Handles are 3-30 letters, digits or underscores starting with a letter, stored lower-case, and answer 409 HANDLE_TAKEN when another user has them.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Request:  patchUserRequest{},
+			Response: User{},
+			Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/users/:uuid",
+			Tag:         "users",
+			Summary:     "Delete user (by UUID)",
+			Description: "Schedules the account for deletion 30 days later. Logging in before then cancels it; after it the account and its data are purged, or anonymized if it has sales history.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Response: deletionResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/users/:uuid/role",
+			Tag:         "users",
+			Summary:     "Set user role",
+			Description: "Sets the role to buyer, founder, moderator or admin. Admins only. The user's existing access tokens keep the old role until they refresh.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Request:  setRoleRequest{},
+			Response: User{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/checkVerification",
+			Tag:         "users",
+			Summary:     "Check and update verification (boolean only)",
+			Description: "Responds with a bare JSON boolean rather than the envelope; 401 with false when the email is missing or unverified",
+			Params: []openapi.Param{
+				openapi.Query("email", "string", "User email", true),
+			},
+			Response: false,
+			Raw:      true,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/users/:uuid",
+			Tag:     "users",
+			Summary: "Get user by UUID",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Response: User{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/profile",
+			Tag:         "users",
+			Summary:     "Get public seller profile",
+			Description: "The user without private fields such as email, plus listing counts and the average time they take to answer a chat over the last 90 days",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+			},
+			Response: PublicProfile{},
+			Errors:   []int{http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/u/:handle",
+			Tag:         "users",
+			Summary:     "Get public seller profile by handle",
+			Description: "Same as GET /users/:uuid/profile, for the handle set with PATCH /users/:uuid. Handles are case-insensitive.",
+			Params: []openapi.Param{
+				openapi.Path("handle", "string", "Profile handle"),
+			},
+			Response: PublicProfile{},
+			Errors:   []int{http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/users",
+			Tag:     "users",
+			Summary: "List users",
+			Params: []openapi.Param{
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 10)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[User]{},
+			Errors:   []int{http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/search",
+			Tag:         "users",
+			Summary:     "Search users",
+			Description: "Case-insensitive substring match on name, names starting with q first. Admins also match on email; everyone else gets results without emails.",
+			Params: []openapi.Param{
+				openapi.Query("q", "string", "Search terms (1-100 characters)", true),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 10)", false),
+			},
+			Response: response.Paginated[User]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError},
+			Auth:     true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/users/login",
+			Tag:         "users",
+			Summary:     "Login user (verify password)",
+			Description: "Returns the user with a signed access token to send as `Authorization: Bearer <access_token>`, and a refresh token for POST /auth/refresh. Users with two-factor authentication on also send totp_code; without it the login fails with TOTP_REQUIRED. Banned accounts get ACCOUNT_BANNED.",
+			Request:     loginRequest{},
+			Response:    loginResponse{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests, http.StatusInternalServerError},
+		},
+	}
 }
 
 type createUserRequest struct {
-	Name          string `json:"name" binding:"required"`
-	Email         string `json:"email" binding:"required"`
-	Role          string `json:"role" binding:"required"`
-	Password      string `json:"password" binding:"required"`
-	ProfilePicURL string `json:"profile_pic_url"`
-	UUID          string `json:"uuid"`
+	Name          string `json:"name" binding:"required,max=100"`
+	Email         string `json:"email" binding:"required,email,max=254"`
+	Role          string `json:"role" binding:"required,max=50"`
+	Password      string `json:"password" binding:"required,max=72"` // bcrypt ignores anything longer
+	ProfilePicURL string `json:"profile_pic_url" binding:"max=2048"`
+	UUID          string `json:"uuid" binding:"max=64"`
+	ReferredBy    string `json:"referred_by" binding:"max=32"` // invite code
 }
 
 type updateUserRequest struct {
-	Name          string `json:"name" binding:"required"`
-	Role          string `json:"role"`
-	ProfilePicURL string `json:"profile_pic_url"`
-	UUID          string `json:"uuid"`
+	Name          string `json:"name" binding:"required,max=100"`
+	Role          string `json:"role" binding:"max=50"`
+	ProfilePicURL string `json:"profile_pic_url" binding:"max=2048"`
+	UUID          string `json:"uuid" binding:"max=64"`
+	Locale        string `json:"locale" binding:"max=16"`
+	Country       string `json:"country" binding:"max=2"`
+	Region        string `json:"region" binding:"max=6"`
+}
+
+// patchUserRequest mirrors updateUserRequest with every field optional; the UUID
+// cannot be changed this way.
+type patchUserRequest struct {
+	Name          *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Role          *string `json:"role" binding:"omitempty,max=50"`
+	ProfilePicURL *string `json:"profile_pic_url" binding:"omitempty,max=2048"`
+	Locale        *string `json:"locale" binding:"omitempty,max=16"`
+	Country       *string `json:"country" binding:"omitempty,max=2"`
+	Region        *string `json:"region" binding:"omitempty,max=6"`
+	Handle        *string `json:"handle" binding:"omitempty,max=30"`
+}
+
+type setRoleRequest struct {
+	Role string `json:"role" binding:"required,max=50"`
 }
 
 type loginRequest struct {
-	Email    string `json:"email" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,max=72"`
+	TOTPCode string `json:"totp_code" binding:"omitempty,len=6,numeric"`
+	// DeviceName labels the session in GET /users/:uuid/sessions, e.g. "Jane's iPhone"
+	DeviceName string `json:"device_name" binding:"max=100"`
+}
+
+// loginResponse is the user plus their session tokens, with the user's fields at the
+// top level as before tokens were issued.
+type loginResponse struct {
+	User
+	*auth.Session
+}
+
+type deletionResponse struct {
+	PurgeAfter time.Time `json:"purge_after"`
 }
 
 type verifyEmailRequest struct {
-	Email string `json:"email" binding:"required"`
+	Email string `json:"email" binding:"required,email"`
 }
 
-// @Summary      Create user
-// @Tags         users
-// @Accept       json
-// @Produce      json
-// @Param        request body createUserRequest true "Create user request"
-// @Success      201 {object} response.APIResponse{data=User}
-// @Failure      400 {object} response.APIResponse
-// @Failure      500 {object} response.APIResponse
-// @Router       /users [post]
 func (h *UserHandler) createUser(c *gin.Context) {
 	var req createUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid request payload", nil)
+	if !validation.BindJSON(c, &req) {
 		return
 	}
 
 	u, err := h.service.CreateUser(c.Request.Context(), req.Name, req.Email, req.Role, req.Password, req.ProfilePicURL, req.UUID)
 	if err != nil {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
 		return
 	}
+	// The account exists either way; a failed attribution only loses the credit
+	if h.referrals != nil && req.ReferredBy != "" {
+		if err := h.referrals.Attribute(c.Request.Context(), req.ReferredBy, u.UUID); err != nil {
+			log.Printf("referrals: attribute %s to code %q: %v", u.UUID, req.ReferredBy, err)
+		}
+	}
 	response.SendAPIResponse(c, http.StatusCreated, true, "user created", u)
 }
 
-// @Summary      Update user (by UUID)
-// @Tags         users
-// @Accept       json
-// @Produce      json
-// @Param        uuid path string true "User UUID"
-// @Param        request body updateUserRequest true "Update user request"
-// @Success      200 {object} response.APIResponse{data=User}
-// @Failure      400 {object} response.APIResponse
-// @Failure      404 {object} response.APIResponse
-// @Failure      500 {object} response.APIResponse
-// @Router       /users/{uuid} [put]
 func (h *UserHandler) updateUser(c *gin.Context) {
 	currentUUID := c.Param("uuid")
 	if currentUUID == "" {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid user uuid", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid user uuid"))
 		return
 	}
 
 	var req updateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid request payload", nil)
+	if !validation.BindJSON(c, &req) {
 		return
 	}
 
@@ -105,53 +331,69 @@ func (h *UserHandler) updateUser(c *gin.Context) {
 		Role:          req.Role,
 		ProfilePicURL: req.ProfilePicURL,
 		UUID:          req.UUID,
+		Locale:        req.Locale,
+		Country:       req.Country,
+		Region:        req.Region,
 	})
 	if err != nil {
-		if err == ErrUserNotFound {
-			response.SendAPIResponse(c, http.StatusNotFound, false, "user not found", nil)
-			return
-		}
-		response.SendAPIResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "user updated", u)
+}
+
+func (h *UserHandler) patchUser(c *gin.Context) {
+	var req patchUserRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	u, err := h.service.PatchUserByUUID(c.Request.Context(), c.Param("uuid"), UserPatch{
+		Name:          req.Name,
+		Role:          req.Role,
+		ProfilePicURL: req.ProfilePicURL,
+		Locale:        req.Locale,
+		Country:       req.Country,
+		Region:        req.Region,
+		Handle:        req.Handle,
+	})
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
 		return
 	}
 	response.SendAPIResponse(c, http.StatusOK, true, "user updated", u)
 }
 
-// @Summary      Delete user (by UUID)
-// @Tags         users
-// @Produce      json
-// @Param        uuid path string true "User UUID"
-// @Success      200 {object} response.APIResponse
-// @Failure      400 {object} response.APIResponse
-// @Failure      404 {object} response.APIResponse
-// @Router       /users/{uuid} [delete]
 func (h *UserHandler) deleteUser(c *gin.Context) {
 	currentUUID := c.Param("uuid")
 	if currentUUID == "" {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid user uuid", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid user uuid"))
 		return
 	}
 
-	if err := h.service.DeleteUserByUUID(c.Request.Context(), currentUUID); err != nil {
-		if err == ErrUserNotFound {
-			response.SendAPIResponse(c, http.StatusNotFound, false, "user not found", nil)
-			return
-		}
-		response.SendAPIResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	purgeAfter, err := h.service.ScheduleDeletion(c.Request.Context(), currentUUID)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
 		return
 	}
-	response.SendAPIResponse(c, http.StatusOK, true, "user deleted", nil)
+	response.SendAPIResponse(c, http.StatusOK, true, "account scheduled for deletion", deletionResponse{PurgeAfter: purgeAfter})
+}
+
+func (h *UserHandler) setRole(c *gin.Context) {
+	var req setRoleRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	u, err := h.service.SetRole(c.Request.Context(), c.Param("uuid"), req.Role)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "role updated", u)
 }
 
 // checkVerification checks if user is verified within 30 days and updates verification timestamp.
 // Returns strictly true if verified and within window (and updates timestamp), false otherwise.
-// @Summary      Check and update verification (boolean only)
-// @Tags         users
-// @Produce      json
-// @Param        email query string true "User email"
-// @Success      200 {boolean} true
-// @Failure      401 {boolean} false
-// @Router       /users/checkVerification [get]
 func (h *UserHandler) checkVerification(c *gin.Context) {
 	email := c.Query("email")
 	if email == "" {
@@ -172,87 +414,114 @@ func (h *UserHandler) checkVerification(c *gin.Context) {
 	}
 }
 
-// @Summary      Get user by UUID
-// @Tags         users
-// @Produce      json
-// @Param        uuid path string true "User UUID"
-// @Success      200 {object} response.APIResponse{data=User}
-// @Failure      400 {object} response.APIResponse
-// @Failure      404 {object} response.APIResponse
-// @Router       /users/{uuid} [get]
 func (h *UserHandler) getUserByUUID(c *gin.Context) {
 	uid := c.Param("uuid")
 	if uid == "" {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid user uuid", nil)
+		response.SendError(c, apperr.New(apperr.InvalidID, "invalid user uuid"))
 		return
 	}
 
 	u, err := h.service.GetUserByUUID(c.Request.Context(), uid)
 	if err != nil {
-		if err == ErrUserNotFound {
-			response.SendAPIResponse(c, http.StatusNotFound, false, "user not found", nil)
-			return
-		}
-		response.SendAPIResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
 		return
 	}
 	response.SendAPIResponse(c, http.StatusOK, true, "user fetched", u)
 }
 
-// @Summary      List users
-// @Tags         users
-// @Produce      json
-// @Param        page  query int false "Page number" default(1)
-// @Param        limit query int false "Items per page" default(10)
-// @Success      200 {object} response.APIResponse{data=UserList}
-// @Failure      500 {object} response.APIResponse
-// @Router       /users [get]
+func (h *UserHandler) getPublicProfile(c *gin.Context) {
+	profile, err := h.service.GetPublicProfile(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "profile fetched", profile)
+}
+
+func (h *UserHandler) getProfileByHandle(c *gin.Context) {
+	profile, err := h.service.GetPublicProfileByHandle(c.Request.Context(), c.Param("handle"))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "profile fetched", profile)
+}
+
 func (h *UserHandler) listUsers(c *gin.Context) {
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
+	p, err := pagination.FromRequest(c, pagination.DefaultLimit)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	items, total, err := h.service.ListUsers(c.Request.Context(), p.Page, p.Limit)
+	if err != nil {
+		response.SendError(c, err)
+		return
 	}
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if err != nil || limit <= 0 {
-		limit = 10
+	response.SendPage(c, "users listed", items, total, p)
+}
+
+func (h *UserHandler) searchUsers(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" || utf8.RuneCountInString(q) > 100 {
+		response.SendError(c, response.InvalidField(apperr.InvalidRequest, "q", "len", "q must be between 1 and 100 characters"))
+		return
 	}
-	if limit > 100 {
-		limit = 100
+
+	p, err := pagination.FromRequest(c, pagination.DefaultLimit)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
 	}
 
-	items, total, err := h.service.ListUsers(c.Request.Context(), page, limit)
+	withEmail := auth.Can(auth.Role(c), auth.PermSearchEmails)
+	items, total, err := h.service.SearchUsers(c.Request.Context(), q, withEmail, p.Page, p.Limit)
 	if err != nil {
-		response.SendAPIResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		response.SendError(c, err)
 		return
 	}
-	data := UserList{Items: items, Total: total, Page: page, Limit: limit}
-	response.SendAPIResponse(c, http.StatusOK, true, "users listed", data)
+	response.SendPage(c, "users found", items, total, p)
 }
 
-// @Summary      Login user (verify password)
-// @Tags         users
-// @Accept       json
-// @Produce      json
-// @Param        request body loginRequest true "Login request"
-// @Success      200 {object} response.APIResponse{data=User}
-// @Failure      400 {object} response.APIResponse
-// @Failure      401 {object} response.APIResponse
-// @Failure      500 {object} response.APIResponse
-// @Router       /users/login [post]
 func (h *UserHandler) login(c *gin.Context) {
 	var req loginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.SendAPIResponse(c, http.StatusBadRequest, false, "invalid request payload", nil)
+	if !validation.BindJSON(c, &req) {
 		return
 	}
 	u, err := h.service.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
-		if err.Error() == "invalid credentials" {
-			response.SendAPIResponse(c, http.StatusUnauthorized, false, err.Error(), nil)
+		if h.events != nil && errors.Is(err, ErrInvalidCredentials) {
+			h.events.RecordByEmail(c, req.Email, auth.EventLoginFailed)
+		}
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+	if h.twoFactor != nil {
+		if err := h.twoFactor.CheckLogin(c.Request.Context(), u.UUID, req.TOTPCode); err != nil {
+			// Asking for the code is a step of the login, not a failure
+			if h.events != nil && !errors.Is(err, ErrTOTPRequired) {
+				h.events.Record(c, u.UUID, auth.EventLoginFailed)
+			}
+			response.SendError(c, err)
 			return
 		}
-		response.SendAPIResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	}
+	if err := h.service.CancelDeletion(c.Request.Context(), u.ID); err != nil {
+		response.SendError(c, err)
 		return
 	}
-	response.SendAPIResponse(c, http.StatusOK, true, "login successful", u)
+	resp := loginResponse{User: u}
+	if h.sessions != nil {
+		session, err := h.sessions.StartSession(c.Request.Context(), u.UUID, u.Role, auth.DeviceFromRequest(c, req.DeviceName))
+		if err != nil {
+			response.SendError(c, err)
+			return
+		}
+		resp.Session = &session
+	}
+	if h.events != nil {
+		h.events.Record(c, u.UUID, auth.EventLogin)
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "login successful", resp)
 }
@@ -3,7 +3,6 @@ package users
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -14,7 +13,10 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
 	"grveyard/pkg/response"
+	"grveyard/pkg/testhelpers"
 )
 
 type mockUserService struct {
@@ -39,6 +41,12 @@ func (m *mockUserService) UpdateUserByUUID(ctx context.Context, currentUUID stri
 	return user, args.Error(1)
 }
 
+func (m *mockUserService) PatchUserByUUID(ctx context.Context, uuid string, p UserPatch) (User, error) {
+	args := m.Called(ctx, uuid, p)
+	user, _ := args.Get(0).(User)
+	return user, args.Error(1)
+}
+
 func (m *mockUserService) DeleteUser(ctx context.Context, id int64) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -84,10 +92,64 @@ func (m *mockUserService) CheckAndUpdateVerification(ctx context.Context, email
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *mockUserService) RequireVerified(ctx context.Context, uuid string) error {
+	args := m.Called(ctx, uuid)
+	return args.Error(0)
+}
+
+func (m *mockUserService) CreateAdmin(ctx context.Context, name, email, password string) (User, error) {
+	args := m.Called(ctx, name, email, password)
+	user, _ := args.Get(0).(User)
+	return user, args.Error(1)
+}
+
+func (m *mockUserService) SetRole(ctx context.Context, uuid, role string) (User, error) {
+	args := m.Called(ctx, uuid, role)
+	user, _ := args.Get(0).(User)
+	return user, args.Error(1)
+}
+
+func (m *mockUserService) GetPublicProfile(ctx context.Context, uuid string) (PublicProfile, error) {
+	args := m.Called(ctx, uuid)
+	profile, _ := args.Get(0).(PublicProfile)
+	return profile, args.Error(1)
+}
+
+func (m *mockUserService) ScheduleDeletion(ctx context.Context, uuid string) (time.Time, error) {
+	args := m.Called(ctx, uuid)
+	at, _ := args.Get(0).(time.Time)
+	return at, args.Error(1)
+}
+
+func (m *mockUserService) CancelDeletion(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockUserService) PurgeDeletedAccounts(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	n, _ := args.Get(0).(int64)
+	return n, args.Error(1)
+}
+
+func (m *mockUserService) SearchUsers(ctx context.Context, query string, withEmail bool, page, limit int) ([]User, int64, error) {
+	args := m.Called(ctx, query, withEmail, page, limit)
+	users, _ := args.Get(0).([]User)
+	total, _ := args.Get(1).(int64)
+	return users, total, args.Error(2)
+}
+
+func (m *mockUserService) GetPublicProfileByHandle(ctx context.Context, handle string) (PublicProfile, error) {
+	args := m.Called(ctx, handle)
+	profile, _ := args.Get(0).(PublicProfile)
+	return profile, args.Error(1)
+}
+
 func setupUserRouter(service UserService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
-	h := NewUserHandler(service)
+	r.Use(testhelpers.AuthAs("admin-uuid", auth.RoleAdmin))
+	h := NewUserHandler(service, nil)
 	h.RegisterRoutes(r)
 	return r
 }
@@ -134,7 +196,9 @@ func TestUserHandler_CreateUser_InvalidPayload(t *testing.T) {
 	var resp response.APIResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
 	require.False(t, resp.Success)
-	require.Equal(t, "invalid request payload", resp.Message)
+	require.Equal(t, "validation failed", resp.Message)
+	require.Contains(t, w.Body.String(), `"field":"name"`)
+	require.Contains(t, w.Body.String(), `"field":"password"`)
 
 	svc.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
@@ -156,15 +220,41 @@ func TestUserHandler_UpdateUser_NotFound(t *testing.T) {
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
 	require.False(t, resp.Success)
 	require.Equal(t, "user not found", resp.Message)
+	require.Equal(t, apperr.UserNotFound, resp.ErrorCode)
 
 	svc.AssertExpectations(t)
 }
 
+func TestUserHandler_PatchUser(t *testing.T) {
+	svc := new(mockUserService)
+	r := setupUserRouter(svc)
+
+	svc.On("PatchUserByUUID", mock.Anything, "uuid-1", mock.MatchedBy(func(p UserPatch) bool {
+		return p.Name == nil && p.Role == nil && p.ProfilePicURL != nil && *p.ProfilePicURL == "pic.png"
+	})).Return(User{UUID: "uuid-1", Name: "Bob", ProfilePicURL: "pic.png"}, nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/users/uuid-1", strings.NewReader(`{"profile_pic_url":"pic.png"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+
+	// Present but empty names are rejected
+	req = httptest.NewRequest(http.MethodPatch, "/users/uuid-1", strings.NewReader(`{"name":""}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestUserHandler_DeleteUser_NotFound(t *testing.T) {
 	svc := new(mockUserService)
 	r := setupUserRouter(svc)
 
-	svc.On("DeleteUserByUUID", mock.Anything, "uuid-x").Return(ErrUserNotFound)
+	svc.On("ScheduleDeletion", mock.Anything, "uuid-x").Return(time.Time{}, ErrUserNotFound)
 
 	req := httptest.NewRequest(http.MethodDelete, "/users/uuid-x", nil)
 	w := httptest.NewRecorder()
@@ -180,11 +270,43 @@ func TestUserHandler_DeleteUser_NotFound(t *testing.T) {
 	svc.AssertExpectations(t)
 }
 
+func TestUserHandler_SetRole(t *testing.T) {
+	svc := new(mockUserService)
+	r := setupUserRouter(svc)
+
+	svc.On("SetRole", mock.Anything, "uuid-1", RoleModerator).Return(User{UUID: "uuid-1", Role: RoleModerator}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPut, "/users/uuid-1/role", strings.NewReader(`{"role":"moderator"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"role":"moderator"`)
+	svc.AssertExpectations(t)
+}
+
+func TestUserHandler_SetRole_AdminOnly(t *testing.T) {
+	svc := new(mockUserService)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(testhelpers.AuthAs("mod-uuid", RoleModerator))
+	NewUserHandler(svc, nil).RegisterRoutes(r)
+
+	req := httptest.NewRequest(http.MethodPut, "/users/mod-uuid/role", strings.NewReader(`{"role":"admin"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	svc.AssertNotCalled(t, "SetRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestUserHandler_Login_InvalidCredentials(t *testing.T) {
 	svc := new(mockUserService)
 	r := setupUserRouter(svc)
 
-	svc.On("Login", mock.Anything, "a@example.com", "bad").Return(User{}, errors.New("invalid credentials"))
+	svc.On("Login", mock.Anything, "a@example.com", "bad").Return(User{}, ErrInvalidCredentials)
 
 	req := httptest.NewRequest(http.MethodPost, "/users/login", strings.NewReader(`{"email":"a@example.com","password":"bad"}`))
 	req.Header.Set("Content-Type", "application/json")
@@ -197,10 +319,127 @@ func TestUserHandler_Login_InvalidCredentials(t *testing.T) {
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
 	require.False(t, resp.Success)
 	require.Equal(t, "invalid credentials", resp.Message)
+	require.Equal(t, apperr.InvalidCredentials, resp.ErrorCode)
 
 	svc.AssertExpectations(t)
 }
 
+type fakeSessions struct {
+	userUUID, role string
+}
+
+func (f *fakeSessions) StartSession(_ context.Context, userUUID, role string, _ auth.Device) (auth.Session, error) {
+	f.userUUID, f.role = userUUID, role
+	return auth.Session{AccessToken: "access", TokenType: auth.TokenType, ExpiresIn: 60, RefreshToken: "refresh", RefreshExpiresIn: 3600}, nil
+}
+
+func TestUserHandler_Login_IssuesToken(t *testing.T) {
+	svc := new(mockUserService)
+	sessions := &fakeSessions{}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewUserHandler(svc, sessions).RegisterRoutes(r)
+
+	svc.On("Login", mock.Anything, "a@example.com", "secret").Return(User{ID: 1, UUID: "uuid-1", Role: "founder"}, nil)
+	svc.On("CancelDeletion", mock.Anything, int64(1)).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/login", strings.NewReader(`{"email":"a@example.com","password":"secret"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data struct {
+			UUID         string `json:"uuid"`
+			AccessToken  string `json:"access_token"`
+			TokenType    string `json:"token_type"`
+			ExpiresIn    int64  `json:"expires_in"`
+			RefreshToken string `json:"refresh_token"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "uuid-1", resp.Data.UUID)
+	require.Equal(t, "access", resp.Data.AccessToken)
+	require.Equal(t, "Bearer", resp.Data.TokenType)
+	require.Equal(t, int64(60), resp.Data.ExpiresIn)
+	require.Equal(t, "refresh", resp.Data.RefreshToken)
+	require.Equal(t, "uuid-1", sessions.userUUID)
+	require.Equal(t, "founder", sessions.role)
+}
+
+type fakeTwoFactor struct {
+	code string // the only code accepted
+}
+
+func (f fakeTwoFactor) CheckLogin(_ context.Context, _, code string) error {
+	switch code {
+	case "":
+		return ErrTOTPRequired
+	case f.code:
+		return nil
+	}
+	return ErrInvalidTOTPCode
+}
+
+// fakeEvents records security log entries as "subject type", the subject being the
+// user UUID or email.
+type fakeEvents struct {
+	recorded []string
+}
+
+func (f *fakeEvents) Record(_ *gin.Context, userUUID, typ string) {
+	f.recorded = append(f.recorded, userUUID+" "+typ)
+}
+
+func (f *fakeEvents) RecordByEmail(_ *gin.Context, email, typ string) {
+	f.recorded = append(f.recorded, email+" "+typ)
+}
+
+func TestUserHandler_Login_RequiresTOTP(t *testing.T) {
+	svc := new(mockUserService)
+	sessions := &fakeSessions{}
+	events := &fakeEvents{}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewUserHandler(svc, sessions)
+	h.SetTwoFactor(fakeTwoFactor{code: "123456"})
+	h.SetEvents(events)
+	h.RegisterRoutes(r)
+
+	svc.On("Login", mock.Anything, "a@example.com", "secret").Return(User{ID: 1, UUID: "uuid-1", Role: "founder"}, nil)
+	svc.On("CancelDeletion", mock.Anything, int64(1)).Return(nil)
+
+	login := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/users/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := login(`{"email":"a@example.com","password":"secret"}`)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), string(apperr.TOTPRequired))
+	require.Empty(t, sessions.userUUID)
+
+	w = login(`{"email":"a@example.com","password":"secret","totp_code":"654321"}`)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), string(apperr.InvalidTOTPCode))
+	require.Empty(t, sessions.userUUID)
+	// A pending deletion is only withdrawn once the second factor is accepted
+	svc.AssertNotCalled(t, "CancelDeletion", mock.Anything, mock.Anything)
+
+	w = login(`{"email":"a@example.com","password":"secret","totp_code":"123456"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "uuid-1", sessions.userUUID)
+	svc.AssertCalled(t, "CancelDeletion", mock.Anything, int64(1))
+
+	// Only the wrong code counts as a failed login
+	require.Equal(t, []string{"uuid-1 login_failed", "uuid-1 login"}, events.recorded)
+}
+
 func TestUserHandler_GetUserByUUID_Success(t *testing.T) {
 	svc := new(mockUserService)
 	r := setupUserRouter(svc)
@@ -292,3 +531,27 @@ func TestUserHandler_CheckVerification_NotVerified(t *testing.T) {
 	require.Equal(t, "false", strings.TrimSpace(w.Body.String()))
 	svc.AssertExpectations(t)
 }
+
+func TestUserHandler_SearchUsers(t *testing.T) {
+	svc := new(mockUserService)
+	h := NewUserHandler(svc, nil)
+	gin.SetMode(gin.TestMode)
+
+	search := func(role, query string) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.Use(testhelpers.AuthAs("user-uuid", role))
+		h.RegisterRoutes(r)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/search?q="+query, nil))
+		return w
+	}
+
+	svc.On("SearchUsers", mock.Anything, "ali", false, 1, 10).Return([]User{{UUID: "u1", Name: "Alice"}}, int64(1), nil).Once()
+	require.Equal(t, http.StatusOK, search("buyer", "ali").Code)
+
+	svc.On("SearchUsers", mock.Anything, "ali", true, 1, 10).Return([]User{{UUID: "u1", Name: "Alice", Email: "a@example.com"}}, int64(1), nil).Once()
+	require.Equal(t, http.StatusOK, search(auth.RoleAdmin, "ali").Code)
+
+	require.Equal(t, http.StatusBadRequest, search("buyer", "%20").Code)
+	svc.AssertExpectations(t)
+}
@@ -0,0 +1,60 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+)
+
+var ErrCannotImpersonateAdmin = apperr.New(apperr.Forbidden, "admins cannot be impersonated")
+
+// ImpersonationIssuer signs impersonation tokens; *auth.Issuer in production.
+type ImpersonationIssuer interface {
+	IssueImpersonation(userUUID, role, actor string) (auth.Token, error)
+}
+
+// Impersonation is a short-lived access token acting as User. There is no refresh
+// token; support staff start a new impersonation when it expires.
+type Impersonation struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"` // seconds
+	User        User   `json:"user"`
+}
+
+// ImpersonationService lets support staff see the marketplace as a user does.
+// Requests made with the token are recorded in the audit log by
+// auth.RecordImpersonation.
+type ImpersonationService struct {
+	users  UserRepository
+	tokens ImpersonationIssuer
+	now    func() time.Time
+}
+
+func NewImpersonationService(users UserRepository, tokens ImpersonationIssuer) *ImpersonationService {
+	return &ImpersonationService{users: users, tokens: tokens, now: time.Now}
+}
+
+// Impersonate issues a token acting as the user on behalf of actor. Admins cannot be
+// impersonated, so the token never carries more than a moderator's permissions.
+func (s *ImpersonationService) Impersonate(ctx context.Context, actor, uuid string) (Impersonation, error) {
+	u, err := s.users.GetUserByUUID(ctx, uuid)
+	if err != nil {
+		return Impersonation{}, err
+	}
+	if u.Role == RoleAdmin {
+		return Impersonation{}, ErrCannotImpersonateAdmin
+	}
+	tok, err := s.tokens.IssueImpersonation(u.UUID, u.Role, actor)
+	if err != nil {
+		return Impersonation{}, err
+	}
+	return Impersonation{
+		AccessToken: tok.AccessToken,
+		TokenType:   auth.TokenType,
+		ExpiresIn:   int64(tok.ExpiresAt.Sub(s.now()) / time.Second),
+		User:        u,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/admin"
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/response"
+)
+
+// ImpersonationHandler serves impersonation and its audit log under /admin.
+type ImpersonationHandler struct {
+	service *ImpersonationService
+	audit   *auth.AuditLog
+	token   string
+}
+
+// NewImpersonationHandler serves the impersonation routes to callers presenting token
+// as a bearer token, like the other admin endpoints, and to admins signed in with
+// their own access token.
+func NewImpersonationHandler(service *ImpersonationService, audit *auth.AuditLog, token string) *ImpersonationHandler {
+	return &ImpersonationHandler{service: service, audit: audit, token: token}
+}
+
+func (h *ImpersonationHandler) RegisterRoutes(router *gin.Engine) {
+	group := router.Group("/admin", admin.RequireTokenOrPermission(h.token, auth.PermImpersonate))
+	group.POST("/impersonate/:uuid", h.impersonate)
+	group.GET("/audit-log", h.list)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *ImpersonationHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/admin/impersonate/:uuid",
+			Tag:         "admin",
+			Summary:     "Impersonate a user",
+			Description: "Returns an access token acting as the user for 10 minutes, without a refresh token. Starting the impersonation and every request made with the token are recorded in the audit log. Admins cannot be impersonated.",
+			Params:      []openapi.Param{openapi.Path("uuid", "string", "User UUID")},
+			Response:    Impersonation{},
+			Errors:      []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/admin/audit-log",
+			Tag:         "admin",
+			Summary:     "Impersonation audit log",
+			Description: "Impersonations started and requests made while impersonating, most recent first",
+			Params: []openapi.Param{
+				openapi.Query("user", "string", "Only entries for this impersonated user's UUID", false),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 10)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[auth.AuditEntry]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+func (h *ImpersonationHandler) impersonate(c *gin.Context) {
+	actor := auth.UserID(c)
+	if actor == "" {
+		actor = auth.ActorAdminToken
+	}
+	imp, err := h.service.Impersonate(c.Request.Context(), actor, c.Param("uuid"))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "impersonation started", imp)
+	h.audit.Record(c, actor, imp.User.UUID)
+}
+
+func (h *ImpersonationHandler) list(c *gin.Context) {
+	p, err := pagination.FromRequest(c, pagination.DefaultLimit)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	entries, total, err := h.audit.List(c.Request.Context(), c.Query("user"), p.Page, p.Limit)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "audit log fetched", entries, total, p)
+}
@@ -0,0 +1,36 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/auth"
+)
+
+func TestImpersonationService_Impersonate(t *testing.T) {
+	repo := new(mockUserRepository)
+	issuer := auth.NewIssuer("test-secret", time.Minute)
+	svc := NewImpersonationService(repo, issuer)
+	ctx := context.Background()
+
+	repo.On("GetUserByUUID", mock.Anything, "u1").Return(User{UUID: "u1", Role: "founder"}, nil)
+	repo.On("GetUserByUUID", mock.Anything, "admin").Return(User{UUID: "admin", Role: RoleAdmin}, nil)
+
+	imp, err := svc.Impersonate(ctx, "admin-1", "u1")
+	require.NoError(t, err)
+	require.Equal(t, "u1", imp.User.UUID)
+	require.InDelta(t, auth.ImpersonationTTL.Seconds(), imp.ExpiresIn, 1)
+
+	claims, err := issuer.Parse(imp.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, "u1", claims.Subject)
+	require.Equal(t, "founder", claims.Role)
+	require.Equal(t, "admin-1", claims.Actor.Subject)
+
+	_, err = svc.Impersonate(ctx, "admin-1", "admin")
+	require.ErrorIs(t, err, ErrCannotImpersonateAdmin)
+}
@@ -3,19 +3,66 @@ package users
 import "time"
 
 type User struct {
-	ID            int64      `json:"id"`
-	Name          string     `json:"name"`
-	Email         string     `json:"email"`
-	Role          string     `json:"role"`
-	ProfilePicURL string     `json:"profile_pic_url"`
-	UUID          string     `json:"uuid"`
-	VerifiedAt    *time.Time `json:"verified_at,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
+	ID              int64      `json:"id"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email"`
+	Role            string     `json:"role"`
+	ProfilePicURL   string     `json:"profile_pic_url"`
+	UUID            string     `json:"uuid"`
+	Handle          string     `json:"handle,omitempty"` // only populated by GetUserByUUID and PatchUserByUUID
+	Locale          string     `json:"locale"`
+	Country         string     `json:"country,omitempty"` // ISO 3166-1 alpha-2
+	Region          string     `json:"region,omitempty"`  // ISO 3166-2
+	VerifiedAt      *time.Time `json:"verified_at,omitempty"`
+	BannedAt        *time.Time `json:"banned_at,omitempty"` // populated by GetUserByID, GetUserByUUID and GetUserByEmail
+	CreatedAt       time.Time  `json:"created_at"`
+	EmailSuppressed bool       `json:"email_suppressed,omitempty"` // only populated by GetUserByUUID
+	// Identities are the external accounts the user linked, e.g. GitHub; only
+	// populated by GetUserByUUID.
+	Identities []LinkedIdentity `json:"identities,omitempty"`
 }
 
-type UserList struct {
-	Items []User `json:"items"`
-	Total int64  `json:"total"`
-	Page  int    `json:"page"`
-	Limit int    `json:"limit"`
+// UserPatch is a partial profile update; nil fields are left unchanged. Setting
+// Country without Region clears the region if the country changes.
+type UserPatch struct {
+	Name          *string
+	Role          *string
+	ProfilePicURL *string // "" removes the picture
+	Locale        *string
+	Country       *string
+	Region        *string
+	Handle        *string // "" removes the handle
+}
+
+// LinkedIdentity is an external account shown on a user's profile.
+type LinkedIdentity struct {
+	Provider   string `json:"provider"`
+	Username   string `json:"username"`
+	ProfileURL string `json:"profile_url"`
+}
+
+// PublicProfile is what anyone may see about a seller: no email or account settings,
+// plus aggregates over their listings and chat.
+type PublicProfile struct {
+	UUID          string           `json:"uuid"`
+	Handle        string           `json:"handle,omitempty"`
+	Name          string           `json:"name"`
+	Role          string           `json:"role"`
+	ProfilePicURL string           `json:"profile_pic_url"`
+	Country       string           `json:"country,omitempty"`
+	Region        string           `json:"region,omitempty"`
+	Verified      bool             `json:"verified"`
+	MemberSince   time.Time        `json:"member_since"`
+	Identities    []LinkedIdentity `json:"identities,omitempty"`
+	ProfileStats
+}
+
+// ProfileStats are the aggregates on a PublicProfile.
+type ProfileStats struct {
+	ActiveStartups int64 `json:"active_startups"` // not deleted and not sold
+	ActiveAssets   int64 `json:"active_assets"`   // listed and not sold
+	SoldCount      int64 `json:"sold_count"`      // assets and startups sold
+	// AvgResponseSeconds is how long the user takes to answer a chat, averaged over
+	// conversations in the last ProfileResponseWindow; nil if they answered none.
+	AvgResponseSeconds *int64 `json:"avg_response_seconds"`
 }
@@ -0,0 +1,106 @@
+package users
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password hashing algorithms, chosen with PASSWORD_HASH_ALGO.
+const (
+	AlgoBcrypt   = "bcrypt"
+	AlgoArgon2id = "argon2id"
+)
+
+// Argon2id parameters, OWASP's minimum recommendation: 19 MiB, two passes, one
+// thread.
+const (
+	argon2Memory  = 19 * 1024
+	argon2Time    = 2
+	argon2Threads = 1
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+)
+
+// PasswordHasher hashes new passwords with one algorithm and checks passwords
+// against hashes made with either. Hashes in the PHC string format
+// ($argon2id$v=19$m=...,t=...,p=...$salt$key) are Argon2id; anything else is bcrypt.
+type PasswordHasher struct {
+	algo       string
+	bcryptCost int
+}
+
+// NewPasswordHasher returns a hasher for algo, AlgoBcrypt or AlgoArgon2id.
+// bcryptCost is only used for bcrypt; 0 means bcrypt.DefaultCost.
+func NewPasswordHasher(algo string, bcryptCost int) *PasswordHasher {
+	if bcryptCost == 0 {
+		bcryptCost = bcrypt.DefaultCost
+	}
+	return &PasswordHasher{algo: algo, bcryptCost: bcryptCost}
+}
+
+// DefaultPasswordHasher uses bcrypt at its default cost.
+func DefaultPasswordHasher() *PasswordHasher {
+	return NewPasswordHasher(AlgoBcrypt, bcrypt.DefaultCost)
+}
+
+// Hash hashes password with h's algorithm and a random salt.
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.algo == AlgoArgon2id {
+		salt := make([]byte, argon2SaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		key := argon2.IDKey([]byte(password), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, argon2Memory, argon2Time, argon2Threads,
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+	}
+	b, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+	return string(b), err
+}
+
+// Verify reports whether password matches hash and, if it does, whether hash should
+// be replaced with Hash(password) because it was made with another algorithm or
+// weaker parameters than h uses.
+func (h *PasswordHasher) Verify(hash, password string) (ok, rehash bool) {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		var version int
+		var memory, time uint32
+		var threads uint8
+		parts := strings.Split(hash, "$")
+		if len(parts) != 6 {
+			return false, false
+		}
+		if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+			return false, false
+		}
+		if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+			return false, false
+		}
+		salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+		if err != nil {
+			return false, false
+		}
+		key, err := base64.RawStdEncoding.DecodeString(parts[5])
+		if err != nil || len(key) == 0 {
+			return false, false
+		}
+		got := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+		if subtle.ConstantTimeCompare(got, key) != 1 {
+			return false, false
+		}
+		weaker := memory < argon2Memory || time < argon2Time
+		return true, h.algo != AlgoArgon2id || weaker
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return true, h.algo != AlgoBcrypt || (err == nil && cost < h.bcryptCost)
+}
@@ -0,0 +1,79 @@
+package users
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordHasher_Argon2id(t *testing.T) {
+	h := NewPasswordHasher(AlgoArgon2id, 0)
+
+	hash, err := h.Hash("secret")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$"))
+
+	ok, rehash := h.Verify(hash, "secret")
+	require.True(t, ok)
+	require.False(t, rehash)
+
+	ok, _ = h.Verify(hash, "wrong")
+	require.False(t, ok)
+
+	other, err := h.Hash("secret")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other, "salts must differ")
+}
+
+func TestPasswordHasher_Bcrypt(t *testing.T) {
+	h := NewPasswordHasher(AlgoBcrypt, bcrypt.MinCost+1)
+
+	hash, err := h.Hash("secret")
+	require.NoError(t, err)
+	cost, err := bcrypt.Cost([]byte(hash))
+	require.NoError(t, err)
+	require.Equal(t, bcrypt.MinCost+1, cost)
+
+	ok, rehash := h.Verify(hash, "secret")
+	require.True(t, ok)
+	require.False(t, rehash)
+
+	ok, _ = h.Verify(hash, "wrong")
+	require.False(t, ok)
+}
+
+func TestPasswordHasher_RehashAcrossSettings(t *testing.T) {
+	cheap, err := NewPasswordHasher(AlgoBcrypt, bcrypt.MinCost).Hash("secret")
+	require.NoError(t, err)
+	argon, err := NewPasswordHasher(AlgoArgon2id, 0).Hash("secret")
+	require.NoError(t, err)
+
+	// Switching to Argon2id upgrades bcrypt hashes
+	ok, rehash := NewPasswordHasher(AlgoArgon2id, 0).Verify(cheap, "secret")
+	require.True(t, ok)
+	require.True(t, rehash)
+
+	// Switching back keeps Argon2id hashes working
+	ok, rehash = NewPasswordHasher(AlgoBcrypt, bcrypt.MinCost).Verify(argon, "secret")
+	require.True(t, ok)
+	require.True(t, rehash)
+
+	// Raising the cost upgrades older bcrypt hashes
+	ok, rehash = NewPasswordHasher(AlgoBcrypt, bcrypt.MinCost+1).Verify(cheap, "secret")
+	require.True(t, ok)
+	require.True(t, rehash)
+}
+
+func TestPasswordHasher_MalformedArgon2idHash(t *testing.T) {
+	h := NewPasswordHasher(AlgoArgon2id, 0)
+	for _, hash := range []string{
+		"$argon2id$",
+		"$argon2id$v=19$m=19456,t=2,p=1$!!$key",
+		"$argon2id$v=18$m=19456,t=2,p=1$c2FsdA$a2V5",
+	} {
+		ok, _ := h.Verify(hash, "secret")
+		require.False(t, ok, hash)
+	}
+}
@@ -0,0 +1,161 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Referral is a signup that came from a user's invite code.
+type Referral struct {
+	UUID     string    `json:"uuid"`
+	Name     string    `json:"name"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type ReferralRepository interface {
+	// InviteCode stores candidate as the user's invite code unless they already have
+	// one, and returns the code they end up with. It returns ErrUserNotFound for
+	// unknown or deleted users and errInviteCodeTaken if another user owns candidate.
+	InviteCode(ctx context.Context, uuid, candidate string) (string, error)
+	// Attribute records that referredUUID signed up with code. Unknown codes, a
+	// user's own code and users already attributed are ignored.
+	Attribute(ctx context.Context, code, referredUUID string) error
+	// ListReferrals returns the users referrer brought in, most recent first, and
+	// their total.
+	ListReferrals(ctx context.Context, referrerUUID string, limit, offset int) ([]Referral, int64, error)
+}
+
+var errInviteCodeTaken = errors.New("invite code taken")
+
+// ReferralService hands out invite codes and credits signups to the user whose code
+// was used, for growth tracking.
+type ReferralService struct {
+	repo    ReferralRepository
+	newCode func() (string, error)
+}
+
+func NewReferralService(repo ReferralRepository) *ReferralService {
+	return &ReferralService{repo: repo, newCode: newInviteCode}
+}
+
+// InviteCode returns the user's invite code, generating it the first time.
+func (s *ReferralService) InviteCode(ctx context.Context, uuid string) (string, error) {
+	for attempt := 0; ; attempt++ {
+		candidate, err := s.newCode()
+		if err != nil {
+			return "", err
+		}
+		code, err := s.repo.InviteCode(ctx, uuid, candidate)
+		if errors.Is(err, errInviteCodeTaken) && attempt < 2 {
+			continue
+		}
+		return code, err
+	}
+}
+
+// Attribute credits the signup of referredUUID to the owner of code. Codes are
+// case-insensitive; an empty code is a no-op.
+func (s *ReferralService) Attribute(ctx context.Context, code, referredUUID string) error {
+	code = normalizeInviteCode(code)
+	if code == "" {
+		return nil
+	}
+	return s.repo.Attribute(ctx, code, referredUUID)
+}
+
+func (s *ReferralService) ListReferrals(ctx context.Context, referrerUUID string, page, limit int) ([]Referral, int64, error) {
+	return s.repo.ListReferrals(ctx, referrerUUID, limit, (page-1)*limit)
+}
+
+func normalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// newInviteCode returns 8 characters of base32, short enough to read out loud.
+func newInviteCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.EncodeToString(b), nil
+}
+
+type postgresReferralRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresReferralRepository(pool *pgxpool.Pool) ReferralRepository {
+	return &postgresReferralRepository{pool: pool}
+}
+
+func (r *postgresReferralRepository) InviteCode(ctx context.Context, uuid, candidate string) (string, error) {
+	var code string
+	err := r.pool.QueryRow(ctx, `
+		UPDATE users SET invite_code = COALESCE(invite_code, $2)
+		WHERE uuid = $1 AND is_deleted = FALSE
+		RETURNING invite_code`, uuid, candidate).Scan(&code)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return "", errInviteCodeTaken
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrUserNotFound
+		}
+		return "", err
+	}
+	return code, nil
+}
+
+func (r *postgresReferralRepository) Attribute(ctx context.Context, code, referredUUID string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO referrals (referrer_uuid, referred_uuid, invite_code)
+		SELECT uuid, $2, invite_code FROM users
+		WHERE invite_code = $1 AND uuid <> $2 AND is_deleted = FALSE
+		ON CONFLICT (referred_uuid) DO NOTHING`, code, referredUUID)
+	return err
+}
+
+func (r *postgresReferralRepository) ListReferrals(ctx context.Context, referrerUUID string, limit, offset int) ([]Referral, int64, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT u.uuid, u.name, f.created_at
+		FROM referrals f
+		JOIN users u ON u.uuid = f.referred_uuid
+		WHERE f.referrer_uuid = $1 AND u.is_deleted = FALSE
+		ORDER BY f.created_at DESC, u.uuid
+		LIMIT $2 OFFSET $3`, referrerUUID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	list := []Referral{}
+	for rows.Next() {
+		var f Referral
+		if err := rows.Scan(&f.UUID, &f.Name, &f.JoinedAt); err != nil {
+			return nil, 0, err
+		}
+		list = append(list, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM referrals f
+		JOIN users u ON u.uuid = f.referred_uuid
+		WHERE f.referrer_uuid = $1 AND u.is_deleted = FALSE`, referrerUUID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
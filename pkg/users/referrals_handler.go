@@ -0,0 +1,87 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/pagination"
+	"grveyard/pkg/response"
+)
+
+type inviteCodeResponse struct {
+	Code string `json:"code"`
+}
+
+type ReferralHandler struct {
+	service *ReferralService
+}
+
+func NewReferralHandler(service *ReferralService) *ReferralHandler {
+	return &ReferralHandler{service: service}
+}
+
+func (h *ReferralHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/users/:uuid/invite-code", auth.RequireSelf("uuid"), h.inviteCode)
+	router.GET("/users/:uuid/referrals", auth.RequireSelf("uuid"), h.listReferrals)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *ReferralHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/invite-code",
+			Tag:         "users",
+			Summary:     "Get invite code",
+			Description: "The code is generated on the first call and stays the same afterwards. New users pass it as referred_by to POST /users.",
+			Params:      []openapi.Param{openapi.Path("uuid", "string", "User UUID")},
+			Response:    inviteCodeResponse{},
+			Errors:      []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/referrals",
+			Tag:         "users",
+			Summary:     "List referrals",
+			Description: "Users who signed up with the user's invite code, most recent first",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+				openapi.Query("page", "integer", "Page number (default 1)", false),
+				openapi.Query("limit", "integer", "Items per page (default 10)", false),
+				openapi.Query("cursor", "string", "Opaque cursor from links.next/links.prev", false),
+			},
+			Response: response.Paginated[Referral]{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+func (h *ReferralHandler) inviteCode(c *gin.Context) {
+	code, err := h.service.InviteCode(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "invite code fetched", inviteCodeResponse{Code: code})
+}
+
+func (h *ReferralHandler) listReferrals(c *gin.Context) {
+	p, err := pagination.FromRequest(c, pagination.DefaultLimit)
+	if err != nil {
+		response.SendError(c, apperr.Default(err, apperr.InvalidRequest))
+		return
+	}
+
+	list, total, err := h.service.ListReferrals(c.Request.Context(), c.Param("uuid"), p.Page, p.Limit)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendPage(c, "referrals fetched", list, total, p)
+}
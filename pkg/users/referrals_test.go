@@ -0,0 +1,126 @@
+package users
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReferralRepository struct {
+	codes    map[string]string // user UUID -> invite code
+	referred map[string]string // referred UUID -> referrer UUID
+}
+
+func (f *fakeReferralRepository) InviteCode(_ context.Context, uuid, candidate string) (string, error) {
+	if code, ok := f.codes[uuid]; ok {
+		return code, nil
+	}
+	for _, code := range f.codes {
+		if code == candidate {
+			return "", errInviteCodeTaken
+		}
+	}
+	f.codes[uuid] = candidate
+	return candidate, nil
+}
+
+func (f *fakeReferralRepository) Attribute(_ context.Context, code, referredUUID string) error {
+	for owner, c := range f.codes {
+		if c == code && owner != referredUUID {
+			if _, ok := f.referred[referredUUID]; !ok {
+				f.referred[referredUUID] = owner
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeReferralRepository) ListReferrals(_ context.Context, referrerUUID string, _, _ int) ([]Referral, int64, error) {
+	list := []Referral{}
+	for referred, referrer := range f.referred {
+		if referrer == referrerUUID {
+			list = append(list, Referral{UUID: referred})
+		}
+	}
+	return list, int64(len(list)), nil
+}
+
+func newFakeReferralRepository() *fakeReferralRepository {
+	return &fakeReferralRepository{codes: map[string]string{}, referred: map[string]string{}}
+}
+
+func TestReferralService_InviteCode(t *testing.T) {
+	repo := newFakeReferralRepository()
+	repo.codes["other"] = "TAKEN"
+	svc := NewReferralService(repo)
+	candidates := []string{"TAKEN", "FRESH", "UNUSED"}
+	svc.newCode = func() (string, error) {
+		c := candidates[0]
+		candidates = candidates[1:]
+		return c, nil
+	}
+	ctx := context.Background()
+
+	// A collision with another user's code is retried with a new one
+	code, err := svc.InviteCode(ctx, "u1")
+	require.NoError(t, err)
+	require.Equal(t, "FRESH", code)
+
+	// The code stays the same on later calls
+	code, err = svc.InviteCode(ctx, "u1")
+	require.NoError(t, err)
+	require.Equal(t, "FRESH", code)
+}
+
+func TestReferralService_Attribute(t *testing.T) {
+	repo := newFakeReferralRepository()
+	repo.codes["referrer"] = "ABCD2345"
+	svc := NewReferralService(repo)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Attribute(ctx, "", "u1"))
+	require.Empty(t, repo.referred)
+
+	require.NoError(t, svc.Attribute(ctx, " abcd2345 ", "u1"))
+	require.Equal(t, "referrer", repo.referred["u1"])
+
+	list, total, err := svc.ListReferrals(ctx, "referrer", 1, 10)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Equal(t, "u1", list[0].UUID)
+}
+
+func TestUserHandler_CreateUser_ReferredBy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(mockUserService)
+	repo := newFakeReferralRepository()
+	repo.codes["referrer"] = "ABCD2345"
+	router := gin.New()
+	h := NewUserHandler(svc, nil)
+	h.SetReferrals(NewReferralService(repo))
+	h.RegisterRoutes(router)
+
+	svc.On("CreateUser", mock.Anything, "Alice", "a@example.com", "buyer", "pass", "", "").Return(User{UUID: "new-user", Role: "buyer"}, nil)
+	svc.On("CreateUser", mock.Anything, "Bob", "b@example.com", "buyer", "pass", "", "").Return(User{UUID: "other-user", Role: "buyer"}, nil)
+
+	post := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusCreated, post(`{"name":"Alice","email":"a@example.com","role":"buyer","password":"pass","referred_by":"abcd2345"}`))
+	require.Equal(t, "referrer", repo.referred["new-user"])
+
+	// Unknown codes do not fail the signup
+	require.Equal(t, http.StatusCreated, post(`{"name":"Bob","email":"b@example.com","role":"buyer","password":"pass","referred_by":"NOPE"}`))
+	require.NotContains(t, repo.referred, "other-user")
+}
@@ -6,10 +6,17 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/db"
+	"grveyard/pkg/apperr"
 )
 
-var ErrUserNotFound = errors.New("user not found")
+var (
+	ErrUserNotFound = apperr.New(apperr.UserNotFound, "user not found")
+	ErrHandleTaken  = apperr.New(apperr.HandleTaken, "handle is taken")
+)
 
 //go:generate mockgen -destination=./mock_users_repo.go -package=users . UserRepository
 
@@ -17,17 +24,37 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, name, email, role, passwordHash, profilePicURL, uuid string) (User, error)
 	UpdateUser(ctx context.Context, u User) (User, error)
 	UpdateUserByUUID(ctx context.Context, currentUUID string, u User) (User, error)
+	// PatchUserByUUID returns ErrHandleTaken if p.Handle belongs to someone else.
+	PatchUserByUUID(ctx context.Context, uuid string, p UserPatch) (User, error)
 	DeleteUser(ctx context.Context, id int64) error
 	DeleteUserByUUID(ctx context.Context, uuid string) error
 	GetUserByID(ctx context.Context, id int64) (User, error)
 	GetUserByUUID(ctx context.Context, uuid string) (User, error)
+	// GetUUIDByHandle resolves a profile handle to the user's UUID.
+	GetUUIDByHandle(ctx context.Context, handle string) (string, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByEmailIncludingDeleted(ctx context.Context, email string) (User, error)
 	ReviveUserByEmail(ctx context.Context, email, name, role, passwordHash, profilePicURL, uuid string) (User, error)
 	ListUsers(ctx context.Context, limit, offset int) ([]User, int64, error)
+	// SearchUsers matches query against names, and emails when withEmail is set,
+	// prefix matches first.
+	SearchUsers(ctx context.Context, query string, withEmail bool, limit, offset int) ([]User, int64, error)
 	// Auth helpers
 	GetUserAuthByEmail(ctx context.Context, email string) (int64, string, error)
 	UpdateVerifiedAtByEmail(ctx context.Context, email string, ts time.Time) error
+	UpdatePasswordByEmail(ctx context.Context, email, passwordHash string) error
+	UpdateRoleByUUID(ctx context.Context, uuid, role string) (User, error)
+	// MarkForDeletion records a deletion request, keeping the time of an earlier one,
+	// and returns when it was made.
+	MarkForDeletion(ctx context.Context, uuid string) (time.Time, error)
+	// CancelDeletion withdraws a pending deletion request, reporting whether there was one.
+	CancelDeletion(ctx context.Context, id int64) (bool, error)
+	// PurgeDeletions removes accounts whose deletion was requested before before and
+	// returns how many.
+	PurgeDeletions(ctx context.Context, before time.Time) (int64, error)
+	// GetProfileStats aggregates the user's listings, and their chat replies to
+	// messages received since since.
+	GetProfileStats(ctx context.Context, uuid string, since time.Time) (ProfileStats, error)
 }
 
 type postgresUserRepository struct {
@@ -41,11 +68,11 @@ func NewPostgresUserRepository(pool *pgxpool.Pool) UserRepository {
 func (r *postgresUserRepository) CreateUser(ctx context.Context, name, email, role, passwordHash, profilePicURL, uuid string) (User, error) {
 	query := `INSERT INTO users (name, email, role, password_hash, profile_pic_url, uuid, created_at)
 	          VALUES ($1, $2, $3, $4, $5, $6, NOW())
-	          RETURNING id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, verified_at, created_at`
+	          RETURNING id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, locale, country, region, verified_at, created_at`
 	row := r.pool.QueryRow(ctx, query, name, email, role, passwordHash, profilePicURL, uuid)
 
 	var u User
-	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.VerifiedAt, &u.CreatedAt); err != nil {
+	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.Locale, &u.Country, &u.Region, &u.VerifiedAt, &u.CreatedAt); err != nil {
 		return User{}, err
 	}
 	return u, nil
@@ -53,13 +80,14 @@ func (r *postgresUserRepository) CreateUser(ctx context.Context, name, email, ro
 
 func (r *postgresUserRepository) UpdateUser(ctx context.Context, u User) (User, error) {
 	query := `UPDATE users
-	          SET name = $1, role = $2, profile_pic_url = $3, uuid = $4
+	          SET name = $1, role = COALESCE(NULLIF($2, ''), role), profile_pic_url = $3, uuid = $4, locale = COALESCE(NULLIF($6, ''), locale),
+	              country = COALESCE(NULLIF($7, ''), country), region = COALESCE(NULLIF($8, ''), region)
 	          WHERE id = $5 AND is_deleted = false
-	          RETURNING id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, verified_at, created_at`
-	row := r.pool.QueryRow(ctx, query, u.Name, u.Role, u.ProfilePicURL, u.UUID, u.ID)
+	          RETURNING id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, locale, country, region, verified_at, created_at`
+	row := r.pool.QueryRow(ctx, query, u.Name, u.Role, u.ProfilePicURL, u.UUID, u.ID, u.Locale, u.Country, u.Region)
 
 	var out User
-	if err := row.Scan(&out.ID, &out.Name, &out.Email, &out.Role, &out.ProfilePicURL, &out.UUID, &out.VerifiedAt, &out.CreatedAt); err != nil {
+	if err := row.Scan(&out.ID, &out.Name, &out.Email, &out.Role, &out.ProfilePicURL, &out.UUID, &out.Locale, &out.Country, &out.Region, &out.VerifiedAt, &out.CreatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return User{}, ErrUserNotFound
 		}
@@ -70,13 +98,14 @@ func (r *postgresUserRepository) UpdateUser(ctx context.Context, u User) (User,
 
 func (r *postgresUserRepository) UpdateUserByUUID(ctx context.Context, currentUUID string, u User) (User, error) {
 	query := `UPDATE users
-			  SET name = $1, role = $2, profile_pic_url = $3, uuid = $4
+			  SET name = $1, role = COALESCE(NULLIF($2, ''), role), profile_pic_url = $3, uuid = $4, locale = COALESCE(NULLIF($6, ''), locale),
+			      country = COALESCE(NULLIF($7, ''), country), region = COALESCE(NULLIF($8, ''), region)
 			  WHERE uuid = $5 AND is_deleted = false
-	          RETURNING id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, verified_at, created_at`
-	row := r.pool.QueryRow(ctx, query, u.Name, u.Role, u.ProfilePicURL, u.UUID, currentUUID)
+	          RETURNING id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, locale, country, region, verified_at, created_at`
+	row := r.pool.QueryRow(ctx, query, u.Name, u.Role, u.ProfilePicURL, u.UUID, currentUUID, u.Locale, u.Country, u.Region)
 
 	var out User
-	if err := row.Scan(&out.ID, &out.Name, &out.Email, &out.Role, &out.ProfilePicURL, &out.UUID, &out.VerifiedAt, &out.CreatedAt); err != nil {
+	if err := row.Scan(&out.ID, &out.Name, &out.Email, &out.Role, &out.ProfilePicURL, &out.UUID, &out.Locale, &out.Country, &out.Region, &out.VerifiedAt, &out.CreatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return User{}, ErrUserNotFound
 		}
@@ -85,6 +114,30 @@ func (r *postgresUserRepository) UpdateUserByUUID(ctx context.Context, currentUU
 	return out, nil
 }
 
+func (r *postgresUserRepository) PatchUserByUUID(ctx context.Context, uuid string, p UserPatch) (User, error) {
+	query := `UPDATE users
+			  SET name = COALESCE($2, name), role = COALESCE($3, role), profile_pic_url = COALESCE($4, profile_pic_url),
+			      locale = COALESCE($5, locale), country = COALESCE($6, country),
+			      region = CASE WHEN $7::text IS NOT NULL THEN $7 WHEN $6::text <> country THEN '' ELSE region END,
+			      handle = CASE WHEN $8::text IS NOT NULL THEN NULLIF($8, '') ELSE handle END
+			  WHERE uuid = $1 AND is_deleted = false
+	          RETURNING id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, COALESCE(handle, ''), locale, country, region, verified_at, created_at`
+	row := r.pool.QueryRow(ctx, query, uuid, p.Name, p.Role, p.ProfilePicURL, p.Locale, p.Country, p.Region, p.Handle)
+
+	var out User
+	if err := row.Scan(&out.ID, &out.Name, &out.Email, &out.Role, &out.ProfilePicURL, &out.UUID, &out.Handle, &out.Locale, &out.Country, &out.Region, &out.VerifiedAt, &out.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return User{}, ErrUserNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_users_handle" {
+			return User{}, ErrHandleTaken
+		}
+		return User{}, err
+	}
+	return out, nil
+}
+
 func (r *postgresUserRepository) DeleteUser(ctx context.Context, id int64) error {
 	cmd, err := r.pool.Exec(ctx, "UPDATE users SET email = NULL, is_deleted = true WHERE id = $1 AND is_deleted = false", id)
 	if err != nil {
@@ -107,15 +160,76 @@ func (r *postgresUserRepository) DeleteUserByUUID(ctx context.Context, uuid stri
 	return nil
 }
 
+func (r *postgresUserRepository) MarkForDeletion(ctx context.Context, uuid string) (time.Time, error) {
+	var requestedAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		UPDATE users SET deletion_requested_at = COALESCE(deletion_requested_at, NOW())
+		WHERE uuid = $1 AND is_deleted = false
+		RETURNING deletion_requested_at`, uuid).Scan(&requestedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, ErrUserNotFound
+	}
+	return requestedAt, err
+}
+
+func (r *postgresUserRepository) CancelDeletion(ctx context.Context, id int64) (bool, error) {
+	cmd, err := r.pool.Exec(ctx, `UPDATE users SET deletion_requested_at = NULL WHERE id = $1 AND deletion_requested_at IS NOT NULL`, id)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+// purgeDeletions runs in order in one transaction. Accounts referenced by a sale are
+// anonymized rather than deleted, so transactions keep their buyer and asset; their
+// unsold listings are soft-deleted for purge-soft-deleted to remove. Every other
+// account is deleted, cascading to everything it owns.
+var purgeDeletions = []string{
+	`UPDATE assets a SET is_deleted = true
+	 FROM users u
+	 WHERE a.user_uuid = u.uuid AND u.deletion_requested_at < $1 AND a.is_deleted = false`,
+	`UPDATE startups s SET is_deleted = true
+	 FROM users u
+	 WHERE s.owner_uuid = u.uuid AND u.deletion_requested_at < $1 AND s.is_deleted = false`,
+	`DELETE FROM users u
+	 WHERE u.deletion_requested_at < $1
+	   AND NOT EXISTS (SELECT 1 FROM transactions t WHERE t.buyer_id = u.id)
+	   AND NOT EXISTS (SELECT 1 FROM transactions t JOIN assets a ON a.id = t.asset_id WHERE a.user_uuid = u.uuid)`,
+	`UPDATE users
+	 SET name = 'Deleted user', email = NULL, password_hash = '', profile_pic_url = NULL,
+	     is_deleted = true, deletion_requested_at = NULL
+	 WHERE deletion_requested_at < $1`,
+}
+
+func (r *postgresUserRepository) PurgeDeletions(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var purged int64
+	for i, stmt := range purgeDeletions {
+		cmd, err := tx.Exec(ctx, stmt, before)
+		if err != nil {
+			return 0, err
+		}
+		if i >= 2 {
+			purged += cmd.RowsAffected()
+		}
+	}
+	return purged, tx.Commit(ctx)
+}
+
 func (r *postgresUserRepository) GetUserByEmailIncludingDeleted(ctx context.Context, email string) (User, error) {
-	query := `SELECT id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, verified_at, created_at, is_deleted
+	query := `SELECT id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, locale, country, region, verified_at, created_at, is_deleted
 			  FROM users
 			  WHERE email = $1`
 	row := r.pool.QueryRow(ctx, query, email)
 
 	var u User
 	var isDeleted bool
-	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.VerifiedAt, &u.CreatedAt, &isDeleted); err != nil {
+	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.Locale, &u.Country, &u.Region, &u.VerifiedAt, &u.CreatedAt, &isDeleted); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return User{}, ErrUserNotFound
 		}
@@ -131,11 +245,11 @@ func (r *postgresUserRepository) ReviveUserByEmail(ctx context.Context, email, n
 	query := `UPDATE users
 			  SET name = $1, role = $2, password_hash = $3, profile_pic_url = $4, uuid = $5, is_deleted = false
 			  WHERE email = $6
-			  RETURNING id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, verified_at, created_at`
+			  RETURNING id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, locale, country, region, verified_at, created_at`
 	row := r.pool.QueryRow(ctx, query, name, role, passwordHash, profilePicURL, uuid, email)
 
 	var u User
-	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.VerifiedAt, &u.CreatedAt); err != nil {
+	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.Locale, &u.Country, &u.Region, &u.VerifiedAt, &u.CreatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return User{}, ErrUserNotFound
 		}
@@ -145,13 +259,13 @@ func (r *postgresUserRepository) ReviveUserByEmail(ctx context.Context, email, n
 }
 
 func (r *postgresUserRepository) GetUserByID(ctx context.Context, id int64) (User, error) {
-	query := `SELECT id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, verified_at, created_at
+	query := `SELECT id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, locale, country, region, verified_at, banned_at, created_at
               FROM users
               WHERE id = $1 AND is_deleted = false`
 	row := r.pool.QueryRow(ctx, query, id)
 
 	var u User
-	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.VerifiedAt, &u.CreatedAt); err != nil {
+	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.Locale, &u.Country, &u.Region, &u.VerifiedAt, &u.BannedAt, &u.CreatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return User{}, ErrUserNotFound
 		}
@@ -161,13 +275,16 @@ func (r *postgresUserRepository) GetUserByID(ctx context.Context, id int64) (Use
 }
 
 func (r *postgresUserRepository) GetUserByUUID(ctx context.Context, uuid string) (User, error) {
-	query := `SELECT id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, verified_at, created_at
+	query := `SELECT id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, COALESCE(handle, ''), locale, country, region, verified_at, banned_at, created_at,
+			         EXISTS (SELECT 1 FROM email_suppressions es WHERE es.email = LOWER(users.email)) AS email_suppressed,
+			         (SELECT json_agg(json_build_object('provider', ui.provider, 'username', ui.username, 'profile_url', ui.profile_url) ORDER BY ui.provider)
+			          FROM user_identities ui WHERE ui.user_uuid = users.uuid) AS identities
 			  FROM users
 			  WHERE uuid = $1 AND is_deleted = false`
 	row := r.pool.QueryRow(ctx, query, uuid)
 
 	var u User
-	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.VerifiedAt, &u.CreatedAt); err != nil {
+	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.Handle, &u.Locale, &u.Country, &u.Region, &u.VerifiedAt, &u.BannedAt, &u.CreatedAt, &u.EmailSuppressed, &u.Identities); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return User{}, ErrUserNotFound
 		}
@@ -176,14 +293,23 @@ func (r *postgresUserRepository) GetUserByUUID(ctx context.Context, uuid string)
 	return u, nil
 }
 
+func (r *postgresUserRepository) GetUUIDByHandle(ctx context.Context, handle string) (string, error) {
+	var uuid string
+	err := r.pool.QueryRow(ctx, `SELECT uuid FROM users WHERE handle = $1 AND is_deleted = false`, handle).Scan(&uuid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrUserNotFound
+	}
+	return uuid, err
+}
+
 func (r *postgresUserRepository) GetUserByEmail(ctx context.Context, email string) (User, error) {
-	query := `SELECT id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, verified_at, created_at
+	query := `SELECT id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, locale, country, region, verified_at, banned_at, created_at
 			  FROM users
 			  WHERE email = $1 AND is_deleted = false`
 	row := r.pool.QueryRow(ctx, query, email)
 
 	var u User
-	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.VerifiedAt, &u.CreatedAt); err != nil {
+	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.Locale, &u.Country, &u.Region, &u.VerifiedAt, &u.BannedAt, &u.CreatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return User{}, ErrUserNotFound
 		}
@@ -193,7 +319,8 @@ func (r *postgresUserRepository) GetUserByEmail(ctx context.Context, email strin
 }
 
 func (r *postgresUserRepository) ListUsers(ctx context.Context, limit, offset int) ([]User, int64, error) {
-	query := `SELECT id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, verified_at, created_at
+	query := `SELECT id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, locale, country, region, verified_at, created_at,
+                     COUNT(*) OVER() AS total
               FROM users
               WHERE is_deleted = false
               ORDER BY id
@@ -204,10 +331,11 @@ func (r *postgresUserRepository) ListUsers(ctx context.Context, limit, offset in
 	}
 	defer rows.Close()
 
+	var total int64
 	list := make([]User, 0)
 	for rows.Next() {
 		var u User
-		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.VerifiedAt, &u.CreatedAt); err != nil {
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.Locale, &u.Country, &u.Region, &u.VerifiedAt, &u.CreatedAt, &total); err != nil {
 			return nil, 0, err
 		}
 		list = append(list, u)
@@ -216,10 +344,12 @@ func (r *postgresUserRepository) ListUsers(ctx context.Context, limit, offset in
 		return nil, 0, err
 	}
 
-	var total int64
-	countRow := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE is_deleted = false")
-	if err := countRow.Scan(&total); err != nil {
-		return nil, 0, err
+	// A page past the end has no rows to carry the total
+	if len(list) == 0 && offset > 0 {
+		countRow := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE is_deleted = false")
+		if err := countRow.Scan(&total); err != nil {
+			return nil, 0, err
+		}
 	}
 
 	return list, total, nil
@@ -249,4 +379,117 @@ func (r *postgresUserRepository) UpdateVerifiedAtByEmail(ctx context.Context, em
 	return nil
 }
 
+func (r *postgresUserRepository) UpdatePasswordByEmail(ctx context.Context, email, passwordHash string) error {
+	cmd, err := r.pool.Exec(ctx, `UPDATE users SET password_hash = $1 WHERE email = $2 AND is_deleted = false`, passwordHash, email)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *postgresUserRepository) UpdateRoleByUUID(ctx context.Context, uuid, role string) (User, error) {
+	query := `UPDATE users SET role = $1
+	          WHERE uuid = $2 AND is_deleted = false
+	          RETURNING id, name, email, role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, locale, country, region, verified_at, created_at`
+	row := r.pool.QueryRow(ctx, query, role, uuid)
+
+	var out User
+	if err := row.Scan(&out.ID, &out.Name, &out.Email, &out.Role, &out.ProfilePicURL, &out.UUID, &out.Locale, &out.Country, &out.Region, &out.VerifiedAt, &out.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return User{}, ErrUserNotFound
+		}
+		return User{}, err
+	}
+	return out, nil
+}
+
 // Removed UpdateUserUUID: login no longer changes UUID
+
+func (r *postgresUserRepository) GetProfileStats(ctx context.Context, uuid string, since time.Time) (ProfileStats, error) {
+	var stats ProfileStats
+	err := r.pool.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM startups s WHERE s.owner_uuid = $1 AND s.is_deleted = false AND s.status <> 'sold'),
+			(SELECT COUNT(*) FROM assets a WHERE a.user_uuid = $1 AND a.is_active = true AND a.is_sold = false AND a.is_deleted = false),
+			(SELECT COUNT(*) FROM assets a WHERE a.user_uuid = $1 AND a.is_sold = true AND a.is_deleted = false)
+			+ (SELECT COUNT(*) FROM startups s WHERE s.owner_uuid = $1 AND s.status = 'sold' AND s.is_deleted = false)`, uuid).
+		Scan(&stats.ActiveStartups, &stats.ActiveAssets, &stats.SoldCount)
+	if err != nil {
+		return ProfileStats{}, err
+	}
+
+	// A response is timed from the first message of each run the other party sent,
+	// to the user's next message to them. System messages (type 3, chat.MessageTypeSystem)
+	// are not chat.
+	err = r.pool.QueryRow(ctx, `
+		WITH me AS (SELECT id FROM users WHERE uuid = $1),
+		conv AS (
+			SELECT m.sender_id, m.receiver_id, m.messaged_at,
+			       LAG(m.sender_id) OVER (
+			           PARTITION BY LEAST(m.sender_id, m.receiver_id), GREATEST(m.sender_id, m.receiver_id)
+			           ORDER BY m.messaged_at, m.id) AS prev_sender
+			FROM messages m, me
+			WHERE (m.sender_id = me.id OR m.receiver_id = me.id)
+			  AND m.message_type <> 3 AND m.messaged_at >= $2
+		)
+		SELECT ROUND(AVG(reply.messaged_at - c.messaged_at))::bigint
+		FROM conv c
+		CROSS JOIN me
+		CROSS JOIN LATERAL (
+			SELECT MIN(r.messaged_at) AS messaged_at
+			FROM messages r
+			WHERE r.sender_id = me.id AND r.receiver_id = c.sender_id
+			  AND r.messaged_at >= c.messaged_at AND r.message_type <> 3
+		) reply
+		WHERE c.receiver_id = me.id
+		  AND c.prev_sender IS DISTINCT FROM c.sender_id
+		  AND reply.messaged_at IS NOT NULL`, uuid, since.Unix()).
+		Scan(&stats.AvgResponseSeconds)
+	if err != nil {
+		return ProfileStats{}, err
+	}
+	return stats, nil
+}
+
+// SearchUsers is a case-insensitive substring match. The users table is small enough
+// that it does without a trigram index.
+func (r *postgresUserRepository) SearchUsers(ctx context.Context, query string, withEmail bool, limit, offset int) ([]User, int64, error) {
+	prefix := db.EscapeLike(query) + "%"
+	pattern := "%" + prefix
+	where := `WHERE is_deleted = false AND (name ILIKE $1 OR ($2 AND email ILIKE $1))`
+
+	rows, err := r.pool.Query(ctx, `SELECT id, name, COALESCE(email, ''), role, COALESCE(profile_pic_url, '') AS profile_pic_url, uuid, locale, country, region, verified_at, created_at,
+                     COUNT(*) OVER() AS total
+              FROM users
+              `+where+`
+              ORDER BY (name ILIKE $3 OR ($2 AND COALESCE(email ILIKE $3, false))) DESC, name, id
+              LIMIT $4 OFFSET $5`, pattern, withEmail, prefix, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var total int64
+	list := make([]User, 0)
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.ProfilePicURL, &u.UUID, &u.Locale, &u.Country, &u.Region, &u.VerifiedAt, &u.CreatedAt, &total); err != nil {
+			return nil, 0, err
+		}
+		list = append(list, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// A page past the end has no rows to carry the total
+	if len(list) == 0 && offset > 0 {
+		if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM users "+where, pattern, withEmail).Scan(&total); err != nil {
+			return nil, 0, err
+		}
+	}
+	return list, total, nil
+}
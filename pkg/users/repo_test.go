@@ -3,40 +3,21 @@ package users
 import (
 	"context"
 	"fmt"
-	"os"
 	"testing"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
 )
 
+func TestMain(m *testing.M) { testhelpers.Main(m) }
+
 func setupUserTestPool(t *testing.T) *pgxpool.Pool {
 	t.Helper()
 
-	dsn := os.Getenv("DATABASE_URL_FOR_TEST")
-	if dsn == "" {
-		t.Skip("DATABASE_URL_FOR_TEST not set; skipping user repository tests")
-	}
-
-	ctx := context.Background()
-	cfg, err := pgxpool.ParseConfig(dsn)
-	require.NoError(t, err)
-
-	pool, err := pgxpool.NewWithConfig(ctx, cfg)
-	require.NoError(t, err)
-	require.NoError(t, pool.Ping(ctx))
-
-	t.Cleanup(pool.Close)
-	return pool
-}
-
-func cleanUserTables(t *testing.T, pool *pgxpool.Pool) {
-	t.Helper()
-
-	ctx := context.Background()
-	_, err := pool.Exec(ctx, "TRUNCATE TABLE messages, assets, startups, users, otps RESTART IDENTITY CASCADE")
-	require.NoError(t, err)
+	return testhelpers.Pool(t)
 }
 
 func insertUser(t *testing.T, pool *pgxpool.Pool, name string) User {
@@ -51,8 +32,8 @@ func insertUser(t *testing.T, pool *pgxpool.Pool, name string) User {
 }
 
 func TestPostgresUserRepository_CreateUser(t *testing.T) {
+	t.Parallel()
 	pool := setupUserTestPool(t)
-	//cleanUserTables(t, pool)
 
 	repo := NewPostgresUserRepository(pool)
 	ctx := context.Background()
@@ -69,8 +50,8 @@ func TestPostgresUserRepository_CreateUser(t *testing.T) {
 }
 
 func TestPostgresUserRepository_UpdateUser(t *testing.T) {
+	t.Parallel()
 	pool := setupUserTestPool(t)
-	// cleanUserTables(t, pool)
 
 	repo := NewPostgresUserRepository(pool)
 	ctx := context.Background()
@@ -92,9 +73,67 @@ func TestPostgresUserRepository_UpdateUser(t *testing.T) {
 	require.Equal(t, "uuid-updated", updated.UUID)
 }
 
+func TestPostgresUserRepository_PatchUserByUUID(t *testing.T) {
+	t.Parallel()
+	pool := setupUserTestPool(t)
+
+	repo := NewPostgresUserRepository(pool)
+	ctx := context.Background()
+	created := testhelpers.NewUser(t, pool, testhelpers.WithName("Bob"), testhelpers.WithProfilePic("old.png"))
+	str := func(s string) *string { return &s }
+
+	_, err := repo.PatchUserByUUID(ctx, created.UUID, UserPatch{Country: str("IN"), Region: str("IN-MH")})
+	require.NoError(t, err)
+
+	updated, err := repo.PatchUserByUUID(ctx, created.UUID, UserPatch{ProfilePicURL: str("new.png")})
+	require.NoError(t, err)
+	require.Equal(t, "Bob", updated.Name)
+	require.Equal(t, "new.png", updated.ProfilePicURL)
+	require.Equal(t, "IN-MH", updated.Region)
+
+	// Moving country drops the old country's region
+	updated, err = repo.PatchUserByUUID(ctx, created.UUID, UserPatch{Country: str("US")})
+	require.NoError(t, err)
+	require.Equal(t, "US", updated.Country)
+	require.Empty(t, updated.Region)
+
+	_, err = repo.PatchUserByUUID(ctx, "missing", UserPatch{Name: str("X")})
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestPostgresUserRepository_Handle(t *testing.T) {
+	t.Parallel()
+	pool := setupUserTestPool(t)
+
+	repo := NewPostgresUserRepository(pool)
+	ctx := context.Background()
+	jane := testhelpers.NewUser(t, pool)
+	other := testhelpers.NewUser(t, pool)
+	str := func(s string) *string { return &s }
+
+	_, err := repo.GetUUIDByHandle(ctx, "jane")
+	require.ErrorIs(t, err, ErrUserNotFound)
+
+	updated, err := repo.PatchUserByUUID(ctx, jane.UUID, UserPatch{Handle: str("jane")})
+	require.NoError(t, err)
+	require.Equal(t, "jane", updated.Handle)
+	uuid, err := repo.GetUUIDByHandle(ctx, "jane")
+	require.NoError(t, err)
+	require.Equal(t, jane.UUID, uuid)
+
+	_, err = repo.PatchUserByUUID(ctx, other.UUID, UserPatch{Handle: str("jane")})
+	require.ErrorIs(t, err, ErrHandleTaken)
+
+	// Removing the handle frees it
+	_, err = repo.PatchUserByUUID(ctx, jane.UUID, UserPatch{Handle: str("")})
+	require.NoError(t, err)
+	_, err = repo.PatchUserByUUID(ctx, other.UUID, UserPatch{Handle: str("jane")})
+	require.NoError(t, err)
+}
+
 func TestPostgresUserRepository_DeleteUser(t *testing.T) {
+	t.Parallel()
 	pool := setupUserTestPool(t)
-	// cleanUserTables(t, pool)
 
 	repo := NewPostgresUserRepository(pool)
 	ctx := context.Background()
@@ -107,8 +146,8 @@ func TestPostgresUserRepository_DeleteUser(t *testing.T) {
 }
 
 func TestPostgresUserRepository_ListUsers(t *testing.T) {
+	t.Parallel()
 	pool := setupUserTestPool(t)
-	cleanUserTables(t, pool)
 
 	repo := NewPostgresUserRepository(pool)
 	ctx := context.Background()
@@ -125,9 +164,62 @@ func TestPostgresUserRepository_ListUsers(t *testing.T) {
 	require.Equal(t, "Second", users[1].Name)
 }
 
+func TestPostgresUserRepository_ListUsers_PastLastPage(t *testing.T) {
+	t.Parallel()
+	pool := setupUserTestPool(t)
+
+	repo := NewPostgresUserRepository(pool)
+	insertUser(t, pool, "First")
+	insertUser(t, pool, "Second")
+
+	users, total, err := repo.ListUsers(context.Background(), 10, 10)
+
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+	require.Empty(t, users)
+}
+
+func TestPostgresUserRepository_SearchUsers(t *testing.T) {
+	t.Parallel()
+	pool := setupUserTestPool(t)
+
+	repo := NewPostgresUserRepository(pool)
+	ctx := context.Background()
+	alice := testhelpers.NewUser(t, pool, testhelpers.WithName("Alice Smith"), testhelpers.WithEmail("alice@example.com"))
+	testhelpers.NewUser(t, pool, testhelpers.WithName("Malika"), testhelpers.WithEmail("m@example.com"))
+	testhelpers.NewUser(t, pool, testhelpers.WithName("Bob"), testhelpers.WithEmail("bob@example.com"))
+	testhelpers.NewUser(t, pool, testhelpers.WithName("Alison"), testhelpers.WithUserDeleted())
+
+	list, total, err := repo.SearchUsers(ctx, "ALI", false, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+	require.Equal(t, alice.UUID, list[0].UUID, "prefix matches come first")
+	require.Equal(t, "Malika", list[1].Name)
+
+	// A page past the end still reports the total
+	list, total, err = repo.SearchUsers(ctx, "ALI", false, 10, 10)
+	require.NoError(t, err)
+	require.Empty(t, list)
+	require.EqualValues(t, 2, total)
+
+	// Emails only match when asked
+	_, total, err = repo.SearchUsers(ctx, "bob@", false, 10, 0)
+	require.NoError(t, err)
+	require.Zero(t, total)
+	list, total, err = repo.SearchUsers(ctx, "alice@", true, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Equal(t, alice.UUID, list[0].UUID)
+
+	// Wildcards match literally
+	_, total, err = repo.SearchUsers(ctx, "%", false, 10, 0)
+	require.NoError(t, err)
+	require.Zero(t, total)
+}
+
 func TestPostgresUserRepository_UpdateUser_NotFound(t *testing.T) {
+	t.Parallel()
 	pool := setupUserTestPool(t)
-	// cleanUserTables(t, pool)
 
 	repo := NewPostgresUserRepository(pool)
 	ctx := context.Background()
@@ -136,3 +228,346 @@ func TestPostgresUserRepository_UpdateUser_NotFound(t *testing.T) {
 
 	require.ErrorIs(t, err, ErrUserNotFound)
 }
+
+func TestPostgresUserRepository_UpdatePasswordByEmail(t *testing.T) {
+	t.Parallel()
+	pool := setupUserTestPool(t)
+
+	repo := NewPostgresUserRepository(pool)
+	ctx := context.Background()
+	created := insertUser(t, pool, "Dana")
+
+	require.NoError(t, repo.UpdatePasswordByEmail(ctx, created.Email, "new-hash"))
+	_, hash, err := repo.GetUserAuthByEmail(ctx, created.Email)
+	require.NoError(t, err)
+	require.Equal(t, "new-hash", hash)
+
+	require.ErrorIs(t, repo.UpdatePasswordByEmail(ctx, "missing@example.com", "x"), ErrUserNotFound)
+}
+
+func TestPostgresPasswordResetRepository(t *testing.T) {
+	t.Parallel()
+	pool := setupUserTestPool(t)
+
+	repo := NewPostgresPasswordResetRepository(pool)
+	ctx := context.Background()
+	expires := time.Now().Add(time.Hour)
+
+	require.NoError(t, repo.CreateResetToken(ctx, "a@example.com", "hash-1", expires))
+	require.NoError(t, repo.CreateResetToken(ctx, "a@example.com", "hash-2", expires))
+	require.NoError(t, repo.CreateResetToken(ctx, "b@example.com", "hash-3", expires))
+	require.NoError(t, repo.CreateResetToken(ctx, "b@example.com", "hash-old", time.Now().Add(-time.Minute)))
+
+	count, err := repo.CountResetTokensInLastHour(ctx, "a@example.com")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	email, err := repo.ConsumeResetToken(ctx, "hash-1")
+	require.NoError(t, err)
+	require.Equal(t, "a@example.com", email)
+
+	// Consuming one token spends the email's others, but not other emails'
+	_, err = repo.ConsumeResetToken(ctx, "hash-1")
+	require.ErrorIs(t, err, ErrInvalidResetToken)
+	_, err = repo.ConsumeResetToken(ctx, "hash-2")
+	require.ErrorIs(t, err, ErrInvalidResetToken)
+	_, err = repo.ConsumeResetToken(ctx, "hash-old")
+	require.ErrorIs(t, err, ErrInvalidResetToken)
+	email, err = repo.ConsumeResetToken(ctx, "hash-3")
+	require.NoError(t, err)
+	require.Equal(t, "b@example.com", email)
+
+	require.NoError(t, repo.DeleteExpiredResetTokens(ctx))
+	count, err = repo.CountResetTokensInLastHour(ctx, "b@example.com")
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestPostgresTwoFactorRepository(t *testing.T) {
+	t.Parallel()
+	pool := setupUserTestPool(t)
+
+	repo := NewPostgresTwoFactorRepository(pool)
+	ctx := context.Background()
+	u := insertUser(t, pool, "Tess")
+
+	_, err := repo.GetTOTP(ctx, u.UUID)
+	require.ErrorIs(t, err, ErrTOTPNotEnrolled)
+
+	require.NoError(t, repo.SaveTOTPSecret(ctx, u.UUID, "SECRETONE"))
+	require.NoError(t, repo.SaveTOTPSecret(ctx, u.UUID, "SECRETTWO"))
+	state, err := repo.GetTOTP(ctx, u.UUID)
+	require.NoError(t, err)
+	require.Equal(t, "SECRETTWO", state.Secret)
+	require.Nil(t, state.EnabledAt)
+
+	now := time.Now()
+	require.NoError(t, repo.RecordTOTPFailure(ctx, u.UUID, now.Add(-time.Hour)))
+	require.NoError(t, repo.RecordTOTPFailure(ctx, u.UUID, now.Add(-time.Minute)))
+	require.NoError(t, repo.RecordTOTPFailure(ctx, u.UUID, now))
+	state, err = repo.GetTOTP(ctx, u.UUID)
+	require.NoError(t, err)
+	require.Equal(t, 2, state.FailedAttempts) // the hour-old failure is outside the window
+
+	used, err := repo.UseTOTPStep(ctx, u.UUID, 100)
+	require.NoError(t, err)
+	require.True(t, used)
+	used, err = repo.UseTOTPStep(ctx, u.UUID, 100)
+	require.NoError(t, err)
+	require.False(t, used)
+
+	state, err = repo.GetTOTP(ctx, u.UUID)
+	require.NoError(t, err)
+	require.NotNil(t, state.EnabledAt)
+	require.Equal(t, int64(100), state.LastStep)
+	require.Zero(t, state.FailedAttempts)
+	require.ErrorIs(t, repo.SaveTOTPSecret(ctx, u.UUID, "SECRETTHREE"), ErrTOTPAlreadyEnabled)
+
+	require.NoError(t, repo.DeleteTOTP(ctx, u.UUID))
+	require.ErrorIs(t, repo.DeleteTOTP(ctx, u.UUID), ErrTOTPNotEnrolled)
+}
+
+func TestPostgresBlockRepository(t *testing.T) {
+	t.Parallel()
+	pool := setupUserTestPool(t)
+
+	repo := NewPostgresBlockRepository(pool)
+	ctx := context.Background()
+	blocker := insertUser(t, pool, "Bea")
+	target := insertUser(t, pool, "Tom")
+	other := insertUser(t, pool, "Oli")
+
+	require.NoError(t, repo.Block(ctx, blocker.UUID, target.UUID))
+	require.NoError(t, repo.Block(ctx, blocker.UUID, target.UUID))
+
+	list, err := repo.ListBlocked(ctx, blocker.UUID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, target.UUID, list[0].UUID)
+	require.Equal(t, "Tom", list[0].Name)
+
+	for _, pair := range [][2]string{{blocker.UUID, target.UUID}, {target.UUID, blocker.UUID}} {
+		blocked, err := repo.EitherBlocked(ctx, pair[0], pair[1])
+		require.NoError(t, err)
+		require.True(t, blocked)
+	}
+	blocked, err := repo.EitherBlocked(ctx, blocker.UUID, other.UUID)
+	require.NoError(t, err)
+	require.False(t, blocked)
+
+	require.NoError(t, repo.Unblock(ctx, blocker.UUID, target.UUID))
+	require.NoError(t, repo.Unblock(ctx, blocker.UUID, target.UUID))
+	blocked, err = repo.EitherBlocked(ctx, target.UUID, blocker.UUID)
+	require.NoError(t, err)
+	require.False(t, blocked)
+}
+
+func TestPostgresBanRepository(t *testing.T) {
+	t.Parallel()
+	pool := setupUserTestPool(t)
+
+	repo := NewPostgresBanRepository(pool)
+	users := NewPostgresUserRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool)
+	listed := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID))
+	deleted := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetDeleted())
+	startup := testhelpers.NewStartup(t, pool, testhelpers.WithStartupOwner(seller.UUID))
+
+	isDeleted := func(table string, id int64) bool {
+		t.Helper()
+		var d bool
+		require.NoError(t, pool.QueryRow(ctx, "SELECT is_deleted FROM "+table+" WHERE id = $1", id).Scan(&d))
+		return d
+	}
+
+	require.ErrorIs(t, repo.Ban(ctx, "missing", "spam"), ErrUserNotFound)
+
+	require.NoError(t, repo.Ban(ctx, seller.UUID, "spam"))
+	banned, err := repo.IsBanned(ctx, seller.UUID)
+	require.NoError(t, err)
+	require.True(t, banned)
+	u, err := users.GetUserByUUID(ctx, seller.UUID)
+	require.NoError(t, err)
+	require.NotNil(t, u.BannedAt)
+	require.True(t, isDeleted("assets", listed.ID))
+	require.True(t, isDeleted("startups", startup.ID))
+
+	// Banning again keeps the first ban's time
+	require.NoError(t, repo.Ban(ctx, seller.UUID, "still spam"))
+	again, err := users.GetUserByUUID(ctx, seller.UUID)
+	require.NoError(t, err)
+	require.Equal(t, *u.BannedAt, *again.BannedAt)
+
+	// Unbanning brings back only what the ban hid
+	require.NoError(t, repo.Unban(ctx, seller.UUID))
+	banned, err = repo.IsBanned(ctx, seller.UUID)
+	require.NoError(t, err)
+	require.False(t, banned)
+	require.False(t, isDeleted("assets", listed.ID))
+	require.False(t, isDeleted("startups", startup.ID))
+	require.True(t, isDeleted("assets", deleted.ID))
+}
+
+func TestPostgresReferralRepository(t *testing.T) {
+	t.Parallel()
+	pool := setupUserTestPool(t)
+
+	repo := NewPostgresReferralRepository(pool)
+	ctx := context.Background()
+	referrer := testhelpers.NewUser(t, pool)
+	referred := testhelpers.NewUser(t, pool)
+	other := testhelpers.NewUser(t, pool)
+	candidate := fmt.Sprintf("C%d", time.Now().UnixNano())
+
+	_, err := repo.InviteCode(ctx, "missing", candidate)
+	require.ErrorIs(t, err, ErrUserNotFound)
+
+	code, err := repo.InviteCode(ctx, referrer.UUID, candidate)
+	require.NoError(t, err)
+	require.Equal(t, candidate, code)
+	// The first code sticks
+	code, err = repo.InviteCode(ctx, referrer.UUID, "IGNORED")
+	require.NoError(t, err)
+	require.Equal(t, candidate, code)
+	_, err = repo.InviteCode(ctx, other.UUID, candidate)
+	require.ErrorIs(t, err, errInviteCodeTaken)
+
+	require.NoError(t, repo.Attribute(ctx, candidate, referred.UUID))
+	require.NoError(t, repo.Attribute(ctx, candidate, referred.UUID))
+	require.NoError(t, repo.Attribute(ctx, candidate, referrer.UUID)) // own code
+	require.NoError(t, repo.Attribute(ctx, "UNKNOWN", other.UUID))
+
+	list, total, err := repo.ListReferrals(ctx, referrer.UUID, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Len(t, list, 1)
+	require.Equal(t, referred.UUID, list[0].UUID)
+	require.Equal(t, referred.Name, list[0].Name)
+}
+
+func TestPostgresUserRepository_GetProfileStats(t *testing.T) {
+	t.Parallel()
+	pool := setupUserTestPool(t)
+
+	repo := NewPostgresUserRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool)
+	buyer := testhelpers.NewUser(t, pool)
+	other := testhelpers.NewUser(t, pool)
+
+	testhelpers.NewStartup(t, pool, testhelpers.WithStartupOwner(seller.UUID))
+	testhelpers.NewStartup(t, pool, testhelpers.WithStartupOwner(seller.UUID), testhelpers.WithStartupSold())
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID))
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetSold())
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetDeleted())
+
+	since := time.Now().Add(-time.Hour)
+	stats, err := repo.GetProfileStats(ctx, seller.UUID, since)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, stats.ActiveStartups)
+	require.EqualValues(t, 1, stats.ActiveAssets)
+	require.EqualValues(t, 2, stats.SoldCount)
+	require.Nil(t, stats.AvgResponseSeconds)
+
+	base := time.Now().Add(-30 * time.Minute)
+	at := func(d time.Duration) testhelpers.MessageOption { return testhelpers.WithMessagedAt(base.Add(d)) }
+	// Two buyer messages in a row answered after 100s, then one answered after 300s
+	testhelpers.NewMessage(t, pool, testhelpers.WithSender(buyer.ID), testhelpers.WithReceiver(seller.ID), at(0))
+	testhelpers.NewMessage(t, pool, testhelpers.WithSender(buyer.ID), testhelpers.WithReceiver(seller.ID), at(50*time.Second))
+	testhelpers.NewMessage(t, pool, testhelpers.WithSender(seller.ID), testhelpers.WithReceiver(buyer.ID), at(100*time.Second))
+	testhelpers.NewMessage(t, pool, testhelpers.WithSender(buyer.ID), testhelpers.WithReceiver(seller.ID), at(200*time.Second))
+	testhelpers.NewMessage(t, pool, testhelpers.WithSender(seller.ID), testhelpers.WithReceiver(buyer.ID), at(500*time.Second))
+	// Unanswered, and too old, messages don't count
+	testhelpers.NewMessage(t, pool, testhelpers.WithSender(other.ID), testhelpers.WithReceiver(seller.ID), at(0))
+	testhelpers.NewMessage(t, pool, testhelpers.WithSender(other.ID), testhelpers.WithReceiver(seller.ID), testhelpers.WithMessagedAt(since.Add(-time.Hour)))
+
+	stats, err = repo.GetProfileStats(ctx, seller.UUID, since)
+	require.NoError(t, err)
+	require.NotNil(t, stats.AvgResponseSeconds)
+	require.EqualValues(t, 200, *stats.AvgResponseSeconds)
+}
+
+func TestPostgresUserRepository_Deletion(t *testing.T) {
+	t.Parallel()
+	pool := setupUserTestPool(t)
+
+	repo := NewPostgresUserRepository(pool)
+	ctx := context.Background()
+	quiet := testhelpers.NewUser(t, pool)
+	seller := testhelpers.NewUser(t, pool)
+	kept := testhelpers.NewUser(t, pool)
+	sold := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetSold())
+	testhelpers.NewTransaction(t, pool, testhelpers.WithTransactionAsset(sold.ID))
+	listed := testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID))
+
+	_, err := repo.MarkForDeletion(ctx, "missing")
+	require.ErrorIs(t, err, ErrUserNotFound)
+
+	first, err := repo.MarkForDeletion(ctx, quiet.UUID)
+	require.NoError(t, err)
+	again, err := repo.MarkForDeletion(ctx, quiet.UUID)
+	require.NoError(t, err)
+	require.True(t, first.Equal(again), "a second request keeps the first date")
+	_, err = repo.MarkForDeletion(ctx, seller.UUID)
+	require.NoError(t, err)
+	_, err = repo.MarkForDeletion(ctx, kept.UUID)
+	require.NoError(t, err)
+
+	cancelled, err := repo.CancelDeletion(ctx, kept.ID)
+	require.NoError(t, err)
+	require.True(t, cancelled)
+	cancelled, err = repo.CancelDeletion(ctx, kept.ID)
+	require.NoError(t, err)
+	require.False(t, cancelled)
+
+	purged, err := repo.PurgeDeletions(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, purged, "requests inside the window are kept")
+
+	purged, err = repo.PurgeDeletions(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, purged)
+
+	var remaining int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM users WHERE uuid = $1`, quiet.UUID).Scan(&remaining))
+	require.Zero(t, remaining)
+
+	// The seller has a sale, so they are anonymized rather than deleted
+	var name string
+	var email *string
+	var isDeleted bool
+	require.NoError(t, pool.QueryRow(ctx, `SELECT name, email, is_deleted FROM users WHERE uuid = $1`, seller.UUID).Scan(&name, &email, &isDeleted))
+	require.Equal(t, "Deleted user", name)
+	require.Nil(t, email)
+	require.True(t, isDeleted)
+
+	var listingDeleted bool
+	require.NoError(t, pool.QueryRow(ctx, `SELECT is_deleted FROM assets WHERE id = $1`, listed.ID).Scan(&listingDeleted))
+	require.True(t, listingDeleted)
+
+	_, err = repo.GetUserByUUID(ctx, kept.UUID)
+	require.NoError(t, err)
+}
+
+func TestPostgresStatsRepository_ListingsByType(t *testing.T) {
+	t.Parallel()
+	pool := setupUserTestPool(t)
+
+	repo := NewPostgresStatsRepository(pool)
+	ctx := context.Background()
+	seller := testhelpers.NewUser(t, pool)
+
+	counts, err := repo.ListingsByType(ctx, seller.UUID)
+	require.NoError(t, err)
+	require.Empty(t, counts)
+
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetType("domain"))
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetType("domain"))
+	testhelpers.NewAsset(t, pool, testhelpers.WithAssetOwner(seller.UUID), testhelpers.WithAssetType("codebase"), testhelpers.WithAssetSold())
+	testhelpers.NewStartup(t, pool, testhelpers.WithStartupOwner(seller.UUID))
+
+	counts, err = repo.ListingsByType(ctx, seller.UUID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{"domain": 2, ListingTypeStartup: 1}, counts)
+}
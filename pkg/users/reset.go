@@ -0,0 +1,239 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/i18n"
+	sendemail "grveyard/pkg/sendemail"
+)
+
+const (
+	// PasswordResetTTL is how long a reset link stays usable.
+	PasswordResetTTL = time.Hour
+	// maxResetsPerHour caps reset emails per address; further requests are dropped
+	// silently so the endpoint cannot be used to flood an inbox.
+	maxResetsPerHour = 3
+)
+
+var ErrInvalidResetToken = apperr.New(apperr.InvalidResetToken, "invalid or expired password reset token")
+
+type PasswordResetRepository interface {
+	CreateResetToken(ctx context.Context, email, tokenHash string, expiresAt time.Time) error
+	// ConsumeResetToken marks the unused, unexpired token with tokenHash and every
+	// other open token for the same email as used, returning the email.
+	ConsumeResetToken(ctx context.Context, tokenHash string) (string, error)
+	CountResetTokensInLastHour(ctx context.Context, email string) (int, error)
+	DeleteExpiredResetTokens(ctx context.Context) error
+}
+
+// SessionRevoker signs a user out everywhere; *auth.Service in production.
+type SessionRevoker interface {
+	LogoutUser(ctx context.Context, userUUID string) error
+}
+
+// PasswordResetService emails single-use reset tokens and sets the new password
+// when one comes back. Tokens are stored hashed.
+type PasswordResetService struct {
+	resets    PasswordResetRepository
+	users     UserRepository
+	es        sendemail.EmailService
+	resetURL  string         // page the emailed link opens; "" sends the bare token
+	sessions  SessionRevoker // optional; signs the user out after a reset
+	passwords *PasswordHasher
+}
+
+// NewPasswordResetService returns a PasswordResetService. The emailed link is
+// resetURL with the token added as the "token" query parameter.
+func NewPasswordResetService(resets PasswordResetRepository, users UserRepository, es sendemail.EmailService, resetURL string, sessions SessionRevoker) *PasswordResetService {
+	return &PasswordResetService{resets: resets, users: users, es: es, resetURL: resetURL, sessions: sessions, passwords: DefaultPasswordHasher()}
+}
+
+// SetPasswordHasher hashes new passwords with passwords instead of
+// DefaultPasswordHasher.
+func (s *PasswordResetService) SetPasswordHasher(passwords *PasswordHasher) {
+	s.passwords = passwords
+}
+
+// RequestReset emails a reset token to email. It returns nil for unknown emails and
+// when the hourly limit is reached, so callers cannot tell which addresses have an
+// account.
+func (s *PasswordResetService) RequestReset(ctx context.Context, email string) error {
+	u, err := s.users.GetUserByEmail(ctx, email)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	count, err := s.resets.CountResetTokensInLastHour(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to check reset count: %w", err)
+	}
+	if count >= maxResetsPerHour {
+		log.Printf("password reset: hourly limit reached for user %s", u.UUID)
+		return nil
+	}
+
+	token, err := newResetToken()
+	if err != nil {
+		return err
+	}
+	if err := s.resets.CreateResetToken(ctx, email, hashResetToken(token), time.Now().Add(PasswordResetTTL)); err != nil {
+		return fmt.Errorf("failed to create reset token: %w", err)
+	}
+	if err := s.sendResetEmail(ctx, email, token, u.Locale); err != nil {
+		return fmt.Errorf("failed to send reset email: %w", err)
+	}
+
+	_ = s.resets.DeleteExpiredResetTokens(ctx)
+
+	return nil
+}
+
+// ResetPassword sets password for the owner of token and invalidates the token,
+// along with any other outstanding ones for that email, which it returns.
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token, password string) (string, error) {
+	hash, err := s.passwords.Hash(password)
+	if err != nil {
+		return "", err
+	}
+
+	email, err := s.resets.ConsumeResetToken(ctx, hashResetToken(token))
+	if err != nil {
+		return "", err
+	}
+	if err := s.users.UpdatePasswordByEmail(ctx, email, hash); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return "", ErrInvalidResetToken
+		}
+		return "", err
+	}
+
+	if s.sessions != nil {
+		u, err := s.users.GetUserByEmail(ctx, email)
+		if err != nil {
+			return "", err
+		}
+		if err := s.sessions.LogoutUser(ctx, u.UUID); err != nil {
+			return "", fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+	}
+	return email, nil
+}
+
+func (s *PasswordResetService) sendResetEmail(ctx context.Context, toEmail, token, locale string) error {
+	expiryMinutes := int(PasswordResetTTL / time.Minute)
+	link := ""
+	if s.resetURL != "" {
+		u, err := url.Parse(s.resetURL)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		q.Set("token", token)
+		u.RawQuery = q.Encode()
+		link = u.String()
+	}
+
+	subject := i18n.T(locale, "email.password_reset.subject")
+	target := link
+	if target == "" {
+		target = token
+	}
+	plainTextContent := i18n.T(locale, "email.password_reset.text", target, expiryMinutes)
+	htmlContent, err := sendemail.RenderHTML("password_reset.html", struct {
+		Heading, Intro, URL, CTA, TokenLabel, Token, Expiry, Ignore string
+	}{
+		Heading:    i18n.T(locale, "email.password_reset.heading"),
+		Intro:      i18n.T(locale, "email.password_reset.intro"),
+		URL:        link,
+		CTA:        i18n.T(locale, "email.password_reset.cta"),
+		TokenLabel: i18n.T(locale, "email.password_reset.token"),
+		Token:      token,
+		Expiry:     i18n.T(locale, "email.password_reset.expiry", expiryMinutes),
+		Ignore:     i18n.T(locale, "email.password_reset.ignore"),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.es.SendEmail(ctx, subject, toEmail, plainTextContent, htmlContent)
+}
+
+func newResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type postgresPasswordResetRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresPasswordResetRepository(pool *pgxpool.Pool) PasswordResetRepository {
+	return &postgresPasswordResetRepository{pool: pool}
+}
+
+func (r *postgresPasswordResetRepository) CreateResetToken(ctx context.Context, email, tokenHash string, expiresAt time.Time) error {
+	_, err := r.pool.Exec(ctx, `INSERT INTO password_reset_tokens (email, token_hash, expires_at) VALUES ($1, $2, $3)`, email, tokenHash, expiresAt)
+	return err
+}
+
+func (r *postgresPasswordResetRepository) ConsumeResetToken(ctx context.Context, tokenHash string) (string, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	var email string
+	err = tx.QueryRow(ctx, `
+		UPDATE password_reset_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING email`, tokenHash).Scan(&email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrInvalidResetToken
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE password_reset_tokens SET used_at = NOW() WHERE email = $1 AND used_at IS NULL`, email); err != nil {
+		return "", err
+	}
+	return email, tx.Commit(ctx)
+}
+
+func (r *postgresPasswordResetRepository) CountResetTokensInLastHour(ctx context.Context, email string) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM password_reset_tokens
+		WHERE email = $1 AND created_at > NOW() - INTERVAL '1 hour'`, email).Scan(&count)
+	return count, err
+}
+
+func (r *postgresPasswordResetRepository) DeleteExpiredResetTokens(ctx context.Context) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM password_reset_tokens WHERE expires_at < NOW()`)
+	return err
+}
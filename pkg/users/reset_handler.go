@@ -0,0 +1,92 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+type PasswordResetHandler struct {
+	service *PasswordResetService
+	events  auth.EventRecorder // optional
+}
+
+func NewPasswordResetHandler(service *PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{service: service}
+}
+
+// SetEvents records password changes in the security log.
+func (h *PasswordResetHandler) SetEvents(events auth.EventRecorder) {
+	h.events = events
+}
+
+func (h *PasswordResetHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/users/forgot-password", h.forgotPassword)
+	router.POST("/users/reset-password", h.resetPassword)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *PasswordResetHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/users/forgot-password",
+			Tag:         "users",
+			Summary:     "Request a password reset email",
+			Description: "Emails a reset link valid for one hour. Responds the same whether or not the email has an account, and sends at most three emails per address an hour.",
+			Request:     forgotPasswordRequest{},
+			Errors:      []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/users/reset-password",
+			Tag:         "users",
+			Summary:     "Reset password",
+			Description: "Sets a new password using the token from the reset email. The token works once, and the user is logged out of every device.",
+			Request:     resetPasswordRequest{},
+			Errors:      []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+	}
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email,max=254"`
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token" binding:"required,max=128"`
+	Password string `json:"password" binding:"required,max=72"` // bcrypt ignores anything longer
+}
+
+func (h *PasswordResetHandler) forgotPassword(c *gin.Context) {
+	var req forgotPasswordRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	if err := h.service.RequestReset(c.Request.Context(), req.Email); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "if that email has an account, a reset link has been sent", nil)
+}
+
+func (h *PasswordResetHandler) resetPassword(c *gin.Context) {
+	var req resetPasswordRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	email, err := h.service.ResetPassword(c.Request.Context(), req.Token, req.Password)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	if h.events != nil {
+		h.events.RecordByEmail(c, email, auth.EventPasswordChanged)
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "password reset", nil)
+}
@@ -0,0 +1,117 @@
+package users
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type fakeResetRepo struct {
+	tokens map[string]string // hash -> email
+	count  int
+}
+
+func (f *fakeResetRepo) CreateResetToken(_ context.Context, email, tokenHash string, _ time.Time) error {
+	f.tokens[tokenHash] = email
+	return nil
+}
+
+func (f *fakeResetRepo) ConsumeResetToken(_ context.Context, tokenHash string) (string, error) {
+	email, ok := f.tokens[tokenHash]
+	if !ok {
+		return "", ErrInvalidResetToken
+	}
+	delete(f.tokens, tokenHash)
+	return email, nil
+}
+
+func (f *fakeResetRepo) CountResetTokensInLastHour(context.Context, string) (int, error) {
+	return f.count, nil
+}
+
+func (f *fakeResetRepo) DeleteExpiredResetTokens(context.Context) error { return nil }
+
+type recordingEmailService struct {
+	to, text []string
+}
+
+func (r *recordingEmailService) SendEmail(_ context.Context, _, toEmail, plainTextContent, _ string) error {
+	r.to = append(r.to, toEmail)
+	r.text = append(r.text, plainTextContent)
+	return nil
+}
+
+type recordingRevoker struct {
+	users []string
+}
+
+func (r *recordingRevoker) LogoutUser(_ context.Context, userUUID string) error {
+	r.users = append(r.users, userUUID)
+	return nil
+}
+
+func TestPasswordResetService_RequestAndReset(t *testing.T) {
+	ctx := context.Background()
+	users := new(mockUserRepository)
+	resets := &fakeResetRepo{tokens: map[string]string{}}
+	emails := &recordingEmailService{}
+	sessions := &recordingRevoker{}
+	svc := NewPasswordResetService(resets, users, emails, "https://grveyard.example/reset?src=email", sessions)
+
+	u := User{UUID: "uuid-1", Email: "a@example.com", Locale: "en"}
+	users.On("GetUserByEmail", mock.Anything, "a@example.com").Return(u, nil)
+
+	require.NoError(t, svc.RequestReset(ctx, "a@example.com"))
+	require.Equal(t, []string{"a@example.com"}, emails.to)
+
+	// The plain-text body carries the link; pull the token back out of it
+	var link string
+	for _, f := range strings.Fields(emails.text[0]) {
+		if strings.HasPrefix(f, "https://") {
+			link = strings.TrimSuffix(f, ".")
+		}
+	}
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	require.Equal(t, "email", parsed.Query().Get("src"))
+	token := parsed.Query().Get("token")
+	require.NotEmpty(t, token)
+	require.NotContains(t, resets.tokens, token, "stored hashed")
+
+	users.On("UpdatePasswordByEmail", mock.Anything, "a@example.com", mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("new-password")) == nil
+	})).Return(nil).Once()
+
+	email, err := svc.ResetPassword(ctx, token, "new-password")
+	require.NoError(t, err)
+	require.Equal(t, "a@example.com", email)
+	require.Equal(t, []string{"uuid-1"}, sessions.users)
+
+	_, err = svc.ResetPassword(ctx, token, "again")
+	require.ErrorIs(t, err, ErrInvalidResetToken)
+	users.AssertExpectations(t)
+}
+
+func TestPasswordResetService_RequestReset_Silent(t *testing.T) {
+	ctx := context.Background()
+	users := new(mockUserRepository)
+	resets := &fakeResetRepo{tokens: map[string]string{}}
+	emails := &recordingEmailService{}
+	svc := NewPasswordResetService(resets, users, emails, "", nil)
+
+	users.On("GetUserByEmail", mock.Anything, "missing@example.com").Return(User{}, ErrUserNotFound)
+	require.NoError(t, svc.RequestReset(ctx, "missing@example.com"))
+
+	users.On("GetUserByEmail", mock.Anything, "a@example.com").Return(User{UUID: "uuid-1"}, nil)
+	resets.count = maxResetsPerHour
+	require.NoError(t, svc.RequestReset(ctx, "a@example.com"))
+
+	require.Empty(t, emails.to)
+	require.Empty(t, resets.tokens)
+}
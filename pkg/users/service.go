@@ -3,62 +3,182 @@ package users
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
 	"time"
 
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/geo"
+	"grveyard/pkg/i18n"
+
+	"github.com/google/uuid"
 	"github.com/jackc/pgconn"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService interface {
 	CreateUser(ctx context.Context, name, email, role, password, profilePicURL, uuid string) (User, error)
 	UpdateUser(ctx context.Context, u User) (User, error)
 	UpdateUserByUUID(ctx context.Context, currentUUID string, u User) (User, error)
+	PatchUserByUUID(ctx context.Context, uuid string, p UserPatch) (User, error)
 	DeleteUser(ctx context.Context, id int64) error
 	DeleteUserByUUID(ctx context.Context, uuid string) error
 	GetUserByID(ctx context.Context, id int64) (User, error)
 	GetUserByUUID(ctx context.Context, uuid string) (User, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	ListUsers(ctx context.Context, page, limit int) ([]User, int64, error)
+	SearchUsers(ctx context.Context, query string, withEmail bool, page, limit int) ([]User, int64, error)
+	// Login returns ErrInvalidCredentials for a wrong email or password and
+	// ErrAccountBanned when an admin banned the account.
 	Login(ctx context.Context, email, password string) (User, error)
 	CheckAndUpdateVerification(ctx context.Context, email string) (bool, error)
+	RequireVerified(ctx context.Context, uuid string) error
+	CreateAdmin(ctx context.Context, name, email, password string) (User, error)
+	SetRole(ctx context.Context, uuid, role string) (User, error)
+	GetPublicProfile(ctx context.Context, uuid string) (PublicProfile, error)
+	GetPublicProfileByHandle(ctx context.Context, handle string) (PublicProfile, error)
+	ScheduleDeletion(ctx context.Context, uuid string) (time.Time, error)
+	// CancelDeletion keeps an account scheduled for deletion. Login calls it only
+	// once the password and any second factor were accepted.
+	CancelDeletion(ctx context.Context, id int64) error
+	PurgeDeletedAccounts(ctx context.Context) (int64, error)
+}
+
+var (
+	ErrInvalidRole        = apperr.New(apperr.InvalidRole, "invalid role")
+	ErrUserExists         = apperr.New(apperr.UserExists, "user exists with that email")
+	ErrInvalidCredentials = apperr.New(apperr.InvalidCredentials, "invalid credentials")
+	ErrUnsupportedLocale  = apperr.New(apperr.UnsupportedLocale, "unsupported locale")
+	ErrNotVerified        = apperr.New(apperr.VerificationRequired, "email verification required")
+	ErrInvalidHandle      = apperr.New(apperr.InvalidHandle, "handle must be 3-30 letters, digits or underscores, starting with a letter")
+)
+
+// handlePattern is checked after lower-casing, so handles are case-insensitive.
+var handlePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{2,29}$`)
+
+// VerificationWindow is how long an OTP verification stays valid.
+const VerificationWindow = 30 * 24 * time.Hour
+
+// ProfileResponseWindow is how far back a public profile's response time looks.
+const ProfileResponseWindow = 90 * 24 * time.Hour
+
+// DeletionGracePeriod is how long a deleted account can be recovered by logging in
+// before it is purged.
+const DeletionGracePeriod = 30 * 24 * time.Hour
+
+// Verifier gates actions that need a recently verified email. Other services take it
+// as an optional dependency.
+type Verifier interface {
+	RequireVerified(ctx context.Context, uuid string) error
 }
 
+// RoleAdmin and RoleModerator cannot be chosen at signup; admins are created with
+// CreateAdmin and either can be granted by an admin with SetRole.
+const (
+	RoleAdmin     = auth.RoleAdmin
+	RoleModerator = auth.RoleModerator
+)
+
+const minAdminPasswordLen = 12
+
 type userService struct {
-	repo UserRepository
+	repo      UserRepository
+	passwords *PasswordHasher
 }
 
-func NewUserService(repo UserRepository) UserService {
-	return &userService{repo: repo}
+// NewUserService returns a UserService hashing passwords with passwords, or with
+// DefaultPasswordHasher if it is nil.
+func NewUserService(repo UserRepository, passwords *PasswordHasher) UserService {
+	if passwords == nil {
+		passwords = DefaultPasswordHasher()
+	}
+	return &userService{repo: repo, passwords: passwords}
 }
 
 func (s *userService) CreateUser(ctx context.Context, name, email, role, password, profilePicURL, uuid string) (User, error) {
 	if role != "buyer" && role != "founder" {
-		return User{}, errors.New("invalid role")
+		return User{}, ErrInvalidRole
 	}
-	hashBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hash, err := s.passwords.Hash(password)
 	if err != nil {
 		return User{}, err
 	}
-	u, err := s.repo.CreateUser(ctx, name, email, role, string(hashBytes), profilePicURL, uuid)
+	u, err := s.repo.CreateUser(ctx, name, email, role, hash, profilePicURL, uuid)
 	if err != nil {
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
-			return User{}, errors.New("user exists with that email")
+			return User{}, ErrUserExists
 		}
 		return User{}, err
 	}
 	return u, nil
 }
 
+// CreateAdmin creates an already verified admin account with a generated UUID.
+func (s *userService) CreateAdmin(ctx context.Context, name, email, password string) (User, error) {
+	if name == "" || email == "" {
+		return User{}, errors.New("name and email are required")
+	}
+	if len(password) < minAdminPasswordLen {
+		return User{}, fmt.Errorf("admin password must be at least %d characters", minAdminPasswordLen)
+	}
+	hash, err := s.passwords.Hash(password)
+	if err != nil {
+		return User{}, err
+	}
+	u, err := s.repo.CreateUser(ctx, name, email, RoleAdmin, hash, "", uuid.NewString())
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return User{}, ErrUserExists
+		}
+		return User{}, err
+	}
+
+	now := time.Now()
+	if err := s.repo.UpdateVerifiedAtByEmail(ctx, email, now); err != nil {
+		return User{}, err
+	}
+	u.VerifiedAt = &now
+	return u, nil
+}
+
+// SetRole changes any user's role, including to and from the staff roles.
+func (s *userService) SetRole(ctx context.Context, uuid, role string) (User, error) {
+	switch role {
+	case "buyer", "founder", RoleModerator, RoleAdmin:
+	default:
+		return User{}, ErrInvalidRole
+	}
+	return s.repo.UpdateRoleByUUID(ctx, uuid, role)
+}
+
 func (s *userService) UpdateUser(ctx context.Context, u User) (User, error) {
 	if u.Role != "" && u.Role != "buyer" && u.Role != "founder" {
-		return User{}, errors.New("invalid role")
+		return User{}, ErrInvalidRole
+	}
+	locale, err := normalizeLocale(u.Locale)
+	if err != nil {
+		return User{}, err
+	}
+	u.Locale = locale
+	if u.Country, u.Region, err = geo.Normalize(u.Country, u.Region); err != nil {
+		return User{}, err
 	}
 	return s.repo.UpdateUser(ctx, u)
 }
 
 func (s *userService) UpdateUserByUUID(ctx context.Context, currentUUID string, u User) (User, error) {
 	if u.Role != "" && u.Role != "buyer" && u.Role != "founder" {
-		return User{}, errors.New("invalid role")
+		return User{}, ErrInvalidRole
+	}
+	locale, err := normalizeLocale(u.Locale)
+	if err != nil {
+		return User{}, err
+	}
+	u.Locale = locale
+	if u.Country, u.Region, err = geo.Normalize(u.Country, u.Region); err != nil {
+		return User{}, err
 	}
 
 	if u.UUID == "" {
@@ -67,6 +187,53 @@ func (s *userService) UpdateUserByUUID(ctx context.Context, currentUUID string,
 	return s.repo.UpdateUserByUUID(ctx, currentUUID, u)
 }
 
+// PatchUserByUUID applies the fields set in p, validating them as UpdateUserByUUID
+// does.
+func (s *userService) PatchUserByUUID(ctx context.Context, uuid string, p UserPatch) (User, error) {
+	if p.Role != nil && *p.Role != "buyer" && *p.Role != "founder" {
+		return User{}, ErrInvalidRole
+	}
+	if p.Locale != nil {
+		locale, err := normalizeLocale(*p.Locale)
+		if err != nil {
+			return User{}, err
+		}
+		if locale == "" {
+			p.Locale = nil
+		} else {
+			p.Locale = &locale
+		}
+	}
+	if p.Handle != nil {
+		handle := strings.ToLower(strings.TrimSpace(*p.Handle))
+		if handle != "" && !handlePattern.MatchString(handle) {
+			return User{}, ErrInvalidHandle
+		}
+		p.Handle = &handle
+	}
+	if p.Country != nil || p.Region != nil {
+		var country, region string
+		if p.Country != nil {
+			country = *p.Country
+		}
+		if p.Region != nil {
+			region = *p.Region
+		}
+		country, region, err := geo.Normalize(country, region)
+		if err != nil {
+			return User{}, err
+		}
+		// A region implies its country
+		if p.Country != nil || region != "" {
+			p.Country = &country
+		}
+		if p.Region != nil {
+			p.Region = &region
+		}
+	}
+	return s.repo.PatchUserByUUID(ctx, uuid, p)
+}
+
 func (s *userService) DeleteUser(ctx context.Context, id int64) error {
 	return s.repo.DeleteUser(ctx, id)
 }
@@ -75,6 +242,29 @@ func (s *userService) DeleteUserByUUID(ctx context.Context, uuid string) error {
 	return s.repo.DeleteUserByUUID(ctx, uuid)
 }
 
+// ScheduleDeletion marks the account for deletion and returns when it will be
+// purged. Asking again does not push the date back.
+func (s *userService) ScheduleDeletion(ctx context.Context, uuid string) (time.Time, error) {
+	requestedAt, err := s.repo.MarkForDeletion(ctx, uuid)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return requestedAt.Add(DeletionGracePeriod), nil
+}
+
+// CancelDeletion withdraws a pending deletion request. Logging in during the grace
+// period keeps the account.
+func (s *userService) CancelDeletion(ctx context.Context, id int64) error {
+	_, err := s.repo.CancelDeletion(ctx, id)
+	return err
+}
+
+// PurgeDeletedAccounts removes accounts whose grace period has ended. Accounts with
+// sales history are anonymized instead so transactions stay intact.
+func (s *userService) PurgeDeletedAccounts(ctx context.Context) (int64, error) {
+	return s.repo.PurgeDeletions(ctx, time.Now().Add(-DeletionGracePeriod))
+}
+
 func (s *userService) GetUserByID(ctx context.Context, id int64) (User, error) {
 	return s.repo.GetUserByID(ctx, id)
 }
@@ -83,6 +273,39 @@ func (s *userService) GetUserByUUID(ctx context.Context, uuid string) (User, err
 	return s.repo.GetUserByUUID(ctx, uuid)
 }
 
+// GetPublicProfile returns what anyone may see about the user.
+func (s *userService) GetPublicProfile(ctx context.Context, uuid string) (PublicProfile, error) {
+	u, err := s.repo.GetUserByUUID(ctx, uuid)
+	if err != nil {
+		return PublicProfile{}, err
+	}
+	stats, err := s.repo.GetProfileStats(ctx, uuid, time.Now().Add(-ProfileResponseWindow))
+	if err != nil {
+		return PublicProfile{}, err
+	}
+	return PublicProfile{
+		UUID:          u.UUID,
+		Handle:        u.Handle,
+		Name:          u.Name,
+		Role:          u.Role,
+		ProfilePicURL: u.ProfilePicURL,
+		Country:       u.Country,
+		Region:        u.Region,
+		Verified:      u.VerifiedAt != nil,
+		MemberSince:   u.CreatedAt,
+		Identities:    u.Identities,
+		ProfileStats:  stats,
+	}, nil
+}
+
+func (s *userService) GetPublicProfileByHandle(ctx context.Context, handle string) (PublicProfile, error) {
+	uuid, err := s.repo.GetUUIDByHandle(ctx, strings.ToLower(handle))
+	if err != nil {
+		return PublicProfile{}, err
+	}
+	return s.GetPublicProfile(ctx, uuid)
+}
+
 func (s *userService) GetUserByEmail(ctx context.Context, email string) (User, error) {
 	return s.repo.GetUserByEmail(ctx, email)
 }
@@ -98,19 +321,56 @@ func (s *userService) ListUsers(ctx context.Context, page, limit int) ([]User, i
 	return s.repo.ListUsers(ctx, limit, offset)
 }
 
+// SearchUsers finds users by name, or also by email when withEmail is set. Emails
+// are left out of the results unless withEmail is set.
+func (s *userService) SearchUsers(ctx context.Context, query string, withEmail bool, page, limit int) ([]User, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	list, total, err := s.repo.SearchUsers(ctx, query, withEmail, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !withEmail {
+		for i := range list {
+			list[i].Email = ""
+		}
+	}
+	return list, total, nil
+}
+
 func (s *userService) Login(ctx context.Context, email, password string) (User, error) {
 	id, hash, err := s.repo.GetUserAuthByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, ErrUserNotFound) {
-			return User{}, errors.New("invalid credentials")
+			return User{}, ErrInvalidCredentials
 		}
 		return User{}, err
 	}
-	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
-		return User{}, errors.New("invalid credentials")
+	ok, rehash := s.passwords.Verify(hash, password)
+	if !ok {
+		return User{}, ErrInvalidCredentials
 	}
-
-	return s.repo.GetUserByID(ctx, id)
+	// Hashes from another algorithm or a lower cost are upgraded as users log in. A
+	// failure leaves the old hash, which still works.
+	if rehash {
+		if newHash, err := s.passwords.Hash(password); err != nil {
+			log.Printf("users: rehash password: %v", err)
+		} else if err := s.repo.UpdatePasswordByEmail(ctx, email, newHash); err != nil {
+			log.Printf("users: rehash password: %v", err)
+		}
+	}
+	u, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	if u.BannedAt != nil {
+		return User{}, ErrAccountBanned
+	}
+	return u, nil
 }
 
 func (s *userService) CheckAndUpdateVerification(ctx context.Context, email string) (bool, error) {
@@ -122,7 +382,7 @@ func (s *userService) CheckAndUpdateVerification(ctx context.Context, email stri
 	now := time.Now()
 	within := false
 	if u.VerifiedAt != nil {
-		if now.Sub(*u.VerifiedAt) <= 30*24*time.Hour {
+		if now.Sub(*u.VerifiedAt) <= VerificationWindow {
 			within = true
 		}
 	}
@@ -135,3 +395,27 @@ func (s *userService) CheckAndUpdateVerification(ctx context.Context, email stri
 
 	return within, nil
 }
+
+// RequireVerified returns ErrNotVerified unless the user verified their email within
+// VerificationWindow.
+func (s *userService) RequireVerified(ctx context.Context, uuid string) error {
+	u, err := s.repo.GetUserByUUID(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if u.VerifiedAt == nil || time.Since(*u.VerifiedAt) > VerificationWindow {
+		return ErrNotVerified
+	}
+	return nil
+}
+
+// normalizeLocale validates an optional locale; "" means keep the current one.
+func normalizeLocale(locale string) (string, error) {
+	if locale == "" {
+		return "", nil
+	}
+	if !i18n.IsSupported(locale) {
+		return "", ErrUnsupportedLocale
+	}
+	return i18n.Normalize(locale), nil
+}
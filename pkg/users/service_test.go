@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
+
+	"grveyard/pkg/geo"
 )
 
 type mockUserRepository struct {
@@ -33,6 +35,12 @@ func (m *mockUserRepository) UpdateUserByUUID(ctx context.Context, currentUUID s
 	return user, args.Error(1)
 }
 
+func (m *mockUserRepository) PatchUserByUUID(ctx context.Context, uuid string, p UserPatch) (User, error) {
+	args := m.Called(ctx, uuid, p)
+	user, _ := args.Get(0).(User)
+	return user, args.Error(1)
+}
+
 func (m *mockUserRepository) DeleteUser(ctx context.Context, id int64) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -89,9 +97,55 @@ func (m *mockUserRepository) UpdateVerifiedAtByEmail(ctx context.Context, email
 	return args.Error(0)
 }
 
+func (m *mockUserRepository) UpdatePasswordByEmail(ctx context.Context, email, passwordHash string) error {
+	args := m.Called(ctx, email, passwordHash)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) UpdateRoleByUUID(ctx context.Context, uuid, role string) (User, error) {
+	args := m.Called(ctx, uuid, role)
+	user, _ := args.Get(0).(User)
+	return user, args.Error(1)
+}
+
+func (m *mockUserRepository) GetProfileStats(ctx context.Context, uuid string, since time.Time) (ProfileStats, error) {
+	args := m.Called(ctx, uuid, since)
+	stats, _ := args.Get(0).(ProfileStats)
+	return stats, args.Error(1)
+}
+
+func (m *mockUserRepository) MarkForDeletion(ctx context.Context, uuid string) (time.Time, error) {
+	args := m.Called(ctx, uuid)
+	at, _ := args.Get(0).(time.Time)
+	return at, args.Error(1)
+}
+
+func (m *mockUserRepository) CancelDeletion(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockUserRepository) PurgeDeletions(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	n, _ := args.Get(0).(int64)
+	return n, args.Error(1)
+}
+
+func (m *mockUserRepository) SearchUsers(ctx context.Context, query string, withEmail bool, limit, offset int) ([]User, int64, error) {
+	args := m.Called(ctx, query, withEmail, limit, offset)
+	users, _ := args.Get(0).([]User)
+	total, _ := args.Get(1).(int64)
+	return users, total, args.Error(2)
+}
+
+func (m *mockUserRepository) GetUUIDByHandle(ctx context.Context, handle string) (string, error) {
+	args := m.Called(ctx, handle)
+	return args.String(0), args.Error(1)
+}
+
 func TestUserService_CreateUser_InvalidRole(t *testing.T) {
 	repo := new(mockUserRepository)
-	service := NewUserService(repo)
+	service := NewUserService(repo, nil)
 
 	_, err := service.CreateUser(context.Background(), "Name", "a@example.com", "wrong", "pass", "", "uuid")
 
@@ -101,7 +155,7 @@ func TestUserService_CreateUser_InvalidRole(t *testing.T) {
 
 func TestUserService_CreateUser_DuplicateEmail(t *testing.T) {
 	repo := new(mockUserRepository)
-	service := NewUserService(repo)
+	service := NewUserService(repo, nil)
 
 	repo.On("CreateUser", mock.Anything, "Name", "a@example.com", "buyer", mock.Anything, "", "uuid").Return(User{}, &pgconn.PgError{Code: "23505"})
 
@@ -113,7 +167,7 @@ func TestUserService_CreateUser_DuplicateEmail(t *testing.T) {
 
 func TestUserService_UpdateUser_InvalidRole(t *testing.T) {
 	repo := new(mockUserRepository)
-	service := NewUserService(repo)
+	service := NewUserService(repo, nil)
 
 	_, err := service.UpdateUser(context.Background(), User{ID: 1, Name: "Bob", Role: "invalid"})
 
@@ -123,7 +177,7 @@ func TestUserService_UpdateUser_InvalidRole(t *testing.T) {
 
 func TestUserService_UpdateUserByUUID_FillUUID(t *testing.T) {
 	repo := new(mockUserRepository)
-	service := NewUserService(repo)
+	service := NewUserService(repo, nil)
 
 	repo.On("UpdateUserByUUID", mock.Anything, "current", mock.MatchedBy(func(u User) bool {
 		return u.UUID == "current" && u.Name == "Bob"
@@ -137,7 +191,7 @@ func TestUserService_UpdateUserByUUID_FillUUID(t *testing.T) {
 
 func TestUserService_ListUsers_Defaults(t *testing.T) {
 	repo := new(mockUserRepository)
-	service := NewUserService(repo)
+	service := NewUserService(repo, nil)
 
 	repo.On("ListUsers", mock.Anything, 10, 0).Return([]User{}, int64(0), nil)
 
@@ -149,7 +203,7 @@ func TestUserService_ListUsers_Defaults(t *testing.T) {
 
 func TestUserService_Login_InvalidPassword(t *testing.T) {
 	repo := new(mockUserRepository)
-	service := NewUserService(repo)
+	service := NewUserService(repo, nil)
 
 	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
 	require.NoError(t, err)
@@ -160,11 +214,12 @@ func TestUserService_Login_InvalidPassword(t *testing.T) {
 
 	require.EqualError(t, err, "invalid credentials")
 	repo.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "CancelDeletion", mock.Anything, mock.Anything)
 }
 
 func TestUserService_Login_UserNotFound(t *testing.T) {
 	repo := new(mockUserRepository)
-	service := NewUserService(repo)
+	service := NewUserService(repo, nil)
 
 	repo.On("GetUserAuthByEmail", mock.Anything, "a@example.com").Return(int64(0), "", ErrUserNotFound)
 
@@ -176,24 +231,66 @@ func TestUserService_Login_UserNotFound(t *testing.T) {
 
 func TestUserService_Login_Success(t *testing.T) {
 	repo := new(mockUserRepository)
-	service := NewUserService(repo)
+	service := NewUserService(repo, nil)
 
 	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
 	require.NoError(t, err)
 
 	repo.On("GetUserAuthByEmail", mock.Anything, "a@example.com").Return(int64(10), string(hash), nil)
 	repo.On("GetUserByID", mock.Anything, int64(10)).Return(User{ID: 10, Email: "a@example.com"}, nil)
+	// MinCost is below the default, so the hash is upgraded
+	repo.On("UpdatePasswordByEmail", mock.Anything, "a@example.com", mock.MatchedBy(func(h string) bool {
+		cost, err := bcrypt.Cost([]byte(h))
+		return err == nil && cost == bcrypt.DefaultCost
+	})).Return(nil)
 
 	u, err := service.Login(context.Background(), "a@example.com", "secret")
 
 	require.NoError(t, err)
 	require.Equal(t, int64(10), u.ID)
 	repo.AssertExpectations(t)
+	// The handler keeps the account only after the second factor
+	repo.AssertNotCalled(t, "CancelDeletion", mock.Anything, mock.Anything)
+}
+
+func TestUserService_Login_Banned(t *testing.T) {
+	repo := new(mockUserRepository)
+	passwords := NewPasswordHasher(AlgoBcrypt, bcrypt.MinCost)
+	service := NewUserService(repo, passwords)
+
+	hash, err := passwords.Hash("secret")
+	require.NoError(t, err)
+	bannedAt := time.Now()
+
+	repo.On("GetUserAuthByEmail", mock.Anything, "a@example.com").Return(int64(10), hash, nil)
+	repo.On("GetUserByID", mock.Anything, int64(10)).Return(User{ID: 10, BannedAt: &bannedAt}, nil)
+
+	_, err = service.Login(context.Background(), "a@example.com", "secret")
+
+	require.ErrorIs(t, err, ErrAccountBanned)
+	repo.AssertNotCalled(t, "CancelDeletion", mock.Anything, mock.Anything)
+}
+
+func TestUserService_Login_CurrentHashNotRewritten(t *testing.T) {
+	repo := new(mockUserRepository)
+	passwords := NewPasswordHasher(AlgoBcrypt, bcrypt.MinCost)
+	service := NewUserService(repo, passwords)
+
+	hash, err := passwords.Hash("secret")
+	require.NoError(t, err)
+
+	repo.On("GetUserAuthByEmail", mock.Anything, "a@example.com").Return(int64(10), hash, nil)
+	repo.On("GetUserByID", mock.Anything, int64(10)).Return(User{ID: 10}, nil)
+
+	_, err = service.Login(context.Background(), "a@example.com", "secret")
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "UpdatePasswordByEmail", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestUserService_CheckAndUpdateVerification_OutsideWindow(t *testing.T) {
 	repo := new(mockUserRepository)
-	service := NewUserService(repo)
+	service := NewUserService(repo, nil)
 
 	now := time.Now().Add(-40 * 24 * time.Hour)
 	user := User{Email: "a@example.com", VerifiedAt: &now}
@@ -210,7 +307,7 @@ func TestUserService_CheckAndUpdateVerification_OutsideWindow(t *testing.T) {
 
 func TestUserService_CheckAndUpdateVerification_WithinWindow(t *testing.T) {
 	repo := new(mockUserRepository)
-	service := NewUserService(repo)
+	service := NewUserService(repo, nil)
 
 	verified := time.Now().Add(-10 * 24 * time.Hour)
 	user := User{Email: "a@example.com", VerifiedAt: &verified}
@@ -224,3 +321,177 @@ func TestUserService_CheckAndUpdateVerification_WithinWindow(t *testing.T) {
 	require.True(t, within)
 	repo.AssertExpectations(t)
 }
+
+func TestUserService_UpdateUserByUUID_Locale(t *testing.T) {
+	repo := new(mockUserRepository)
+	service := NewUserService(repo, nil)
+
+	_, err := service.UpdateUserByUUID(context.Background(), "current", User{Name: "Bob", Locale: "xx"})
+	require.EqualError(t, err, "unsupported locale")
+
+	repo.On("UpdateUserByUUID", mock.Anything, "current", mock.MatchedBy(func(u User) bool {
+		return u.Locale == "hi"
+	})).Return(User{ID: 1, Name: "Bob", UUID: "current", Locale: "hi"}, nil)
+
+	u, err := service.UpdateUserByUUID(context.Background(), "current", User{Name: "Bob", Locale: "hi-IN"})
+	require.NoError(t, err)
+	require.Equal(t, "hi", u.Locale)
+	repo.AssertExpectations(t)
+}
+
+func TestUserService_PatchUserByUUID(t *testing.T) {
+	repo := new(mockUserRepository)
+	service := NewUserService(repo, nil)
+	ctx := context.Background()
+	str := func(s string) *string { return &s }
+
+	_, err := service.PatchUserByUUID(ctx, "current", UserPatch{Role: str("admin")})
+	require.EqualError(t, err, "invalid role")
+	_, err = service.PatchUserByUUID(ctx, "current", UserPatch{Region: str("XX-YY")})
+	require.ErrorIs(t, err, geo.ErrInvalidRegion)
+
+	// A region alone sets its country; untouched fields stay nil
+	repo.On("PatchUserByUUID", ctx, "current", mock.MatchedBy(func(p UserPatch) bool {
+		return p.Name == nil && p.ProfilePicURL == nil && *p.Country == "IN" && *p.Region == "IN-MH" && *p.Locale == "hi"
+	})).Return(User{UUID: "current", Country: "IN", Region: "IN-MH"}, nil)
+
+	u, err := service.PatchUserByUUID(ctx, "current", UserPatch{Region: str("in-mh"), Locale: str("hi-IN")})
+	require.NoError(t, err)
+	require.Equal(t, "IN-MH", u.Region)
+	repo.AssertExpectations(t)
+}
+
+func TestUserService_PatchUserByUUID_Handle(t *testing.T) {
+	repo := new(mockUserRepository)
+	service := NewUserService(repo, nil)
+	ctx := context.Background()
+	str := func(s string) *string { return &s }
+
+	for _, bad := range []string{"ab", "1abc", "has space", "dash-ed", "émile"} {
+		_, err := service.PatchUserByUUID(ctx, "current", UserPatch{Handle: str(bad)})
+		require.ErrorIs(t, err, ErrInvalidHandle, bad)
+	}
+
+	repo.On("PatchUserByUUID", ctx, "current", mock.MatchedBy(func(p UserPatch) bool {
+		return *p.Handle == "jane_doe"
+	})).Return(User{UUID: "current", Handle: "jane_doe"}, nil)
+	repo.On("PatchUserByUUID", ctx, "current", mock.MatchedBy(func(p UserPatch) bool {
+		return *p.Handle == ""
+	})).Return(User{UUID: "current"}, nil)
+
+	u, err := service.PatchUserByUUID(ctx, "current", UserPatch{Handle: str(" Jane_Doe ")})
+	require.NoError(t, err)
+	require.Equal(t, "jane_doe", u.Handle)
+	_, err = service.PatchUserByUUID(ctx, "current", UserPatch{Handle: str("")})
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestUserService_CreateAdmin(t *testing.T) {
+	repo := new(mockUserRepository)
+	service := NewUserService(repo, nil)
+
+	var hash string
+	repo.On("CreateUser", mock.Anything, "Ops", "ops@example.com", RoleAdmin, mock.Anything, "", mock.Anything).
+		Run(func(args mock.Arguments) { hash = args.String(4) }).
+		Return(User{ID: 9, Email: "ops@example.com", Role: RoleAdmin}, nil)
+	repo.On("UpdateVerifiedAtByEmail", mock.Anything, "ops@example.com", mock.Anything).Return(nil)
+
+	u, err := service.CreateAdmin(context.Background(), "Ops", "ops@example.com", "correct horse battery")
+
+	require.NoError(t, err)
+	require.Equal(t, RoleAdmin, u.Role)
+	require.NotNil(t, u.VerifiedAt)
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("correct horse battery")))
+	repo.AssertExpectations(t)
+}
+
+func TestUserService_CreateAdmin_ShortPassword(t *testing.T) {
+	repo := new(mockUserRepository)
+	service := NewUserService(repo, nil)
+
+	_, err := service.CreateAdmin(context.Background(), "Ops", "ops@example.com", "short")
+
+	require.EqualError(t, err, "admin password must be at least 12 characters")
+	repo.AssertNotCalled(t, "CreateUser")
+}
+
+func TestUserService_CreateUser_RejectsAdminRole(t *testing.T) {
+	repo := new(mockUserRepository)
+	service := NewUserService(repo, nil)
+
+	_, err := service.CreateUser(context.Background(), "Name", "a@example.com", RoleAdmin, "pass", "", "uuid")
+
+	require.EqualError(t, err, "invalid role")
+}
+
+func TestUserService_SetRole(t *testing.T) {
+	repo := new(mockUserRepository)
+	service := NewUserService(repo, nil)
+	ctx := context.Background()
+
+	repo.On("UpdateRoleByUUID", ctx, "uuid-1", RoleModerator).Return(User{UUID: "uuid-1", Role: RoleModerator}, nil).Once()
+	u, err := service.SetRole(ctx, "uuid-1", RoleModerator)
+	require.NoError(t, err)
+	require.Equal(t, RoleModerator, u.Role)
+
+	_, err = service.SetRole(ctx, "uuid-1", "owner")
+	require.ErrorIs(t, err, ErrInvalidRole)
+	repo.AssertExpectations(t)
+}
+
+func TestUserService_GetPublicProfile(t *testing.T) {
+	repo := new(mockUserRepository)
+	service := NewUserService(repo, nil)
+	ctx := context.Background()
+
+	joined := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	verified := joined.Add(time.Hour)
+	avg := int64(600)
+	repo.On("GetUserByUUID", ctx, "uuid-1").Return(User{UUID: "uuid-1", Name: "Sam", Email: "sam@example.com", Locale: "hi", VerifiedAt: &verified, CreatedAt: joined}, nil)
+	repo.On("GetProfileStats", ctx, "uuid-1", mock.AnythingOfType("time.Time")).Return(ProfileStats{ActiveAssets: 2, SoldCount: 1, AvgResponseSeconds: &avg}, nil)
+
+	profile, err := service.GetPublicProfile(ctx, "uuid-1")
+	require.NoError(t, err)
+	require.Equal(t, "Sam", profile.Name)
+	require.True(t, profile.Verified)
+	require.Equal(t, joined, profile.MemberSince)
+	require.EqualValues(t, 2, profile.ActiveAssets)
+	require.Equal(t, &avg, profile.AvgResponseSeconds)
+
+	repo.On("GetUserByUUID", ctx, "gone").Return(User{}, ErrUserNotFound)
+	_, err = service.GetPublicProfile(ctx, "gone")
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestUserService_RequireVerified(t *testing.T) {
+	repo := new(mockUserRepository)
+	service := NewUserService(repo, nil)
+
+	recent := time.Now().Add(-24 * time.Hour)
+	stale := time.Now().Add(-40 * 24 * time.Hour)
+	repo.On("GetUserByUUID", mock.Anything, "recent").Return(User{VerifiedAt: &recent}, nil)
+	repo.On("GetUserByUUID", mock.Anything, "stale").Return(User{VerifiedAt: &stale}, nil)
+	repo.On("GetUserByUUID", mock.Anything, "never").Return(User{}, nil)
+
+	require.NoError(t, service.RequireVerified(context.Background(), "recent"))
+	require.ErrorIs(t, service.RequireVerified(context.Background(), "stale"), ErrNotVerified)
+	require.ErrorIs(t, service.RequireVerified(context.Background(), "never"), ErrNotVerified)
+}
+
+func TestUserService_ScheduleDeletion(t *testing.T) {
+	repo := new(mockUserRepository)
+	service := NewUserService(repo, nil)
+	ctx := context.Background()
+
+	requestedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo.On("MarkForDeletion", ctx, "uuid-1").Return(requestedAt, nil)
+	repo.On("MarkForDeletion", ctx, "gone").Return(time.Time{}, ErrUserNotFound)
+
+	purgeAfter, err := service.ScheduleDeletion(ctx, "uuid-1")
+	require.NoError(t, err)
+	require.Equal(t, requestedAt.Add(DeletionGracePeriod), purgeAfter)
+
+	_, err = service.ScheduleDeletion(ctx, "gone")
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
@@ -0,0 +1,176 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Views are reported per day; the default window matches the seller dashboard's
+// 30-day count.
+const (
+	DefaultStatsDays = 30
+	MaxStatsDays     = 90
+)
+
+// ListingTypeStartup is the key counting startups in UserStats.ListingsByType; the
+// other keys are asset types.
+const ListingTypeStartup = "startup"
+
+// UserStats backs a seller's stats page in one response.
+type UserStats struct {
+	// ListingsByType counts active listings by asset type, plus startups; types with
+	// no listings are left out.
+	ListingsByType map[string]int64 `json:"listings_by_type"`
+	Sales          int64            `json:"sales"`
+	SalesValue     float64          `json:"sales_value"` // final prices, before tax
+	UnreadMessages int64            `json:"unread_messages"`
+	// Views has one entry per day, oldest first, including days without views.
+	Views []DailyViews `json:"views"`
+}
+
+// DailyViews counts listing_viewed analytics events for the seller's listings on a
+// UTC day. Events only exist when analytics are stored in Postgres.
+type DailyViews struct {
+	Day   string `json:"day"` // YYYY-MM-DD
+	Views int64  `json:"views"`
+}
+
+type StatsRepository interface {
+	// ListingsByType counts the user's active assets by type and their startups that
+	// are not sold, under ListingTypeStartup.
+	ListingsByType(ctx context.Context, userUUID string) (map[string]int64, error)
+	// Sales counts the sales of the user's assets and sums their final prices.
+	Sales(ctx context.Context, userUUID string) (int64, float64, error)
+	UnreadMessages(ctx context.Context, userUUID string) (int64, error)
+	// DailyViews counts views of the user's listings per day since since; days
+	// without views are left out.
+	DailyViews(ctx context.Context, userUUID string, since time.Time) (map[string]int64, error)
+}
+
+type StatsService struct {
+	repo  StatsRepository
+	users UserRepository
+	now   func() time.Time
+}
+
+func NewStatsService(repo StatsRepository, users UserRepository) *StatsService {
+	return &StatsService{repo: repo, users: users, now: time.Now}
+}
+
+// Stats returns the user's stats with views for the last days days, today included.
+func (s *StatsService) Stats(ctx context.Context, userUUID string, days int) (UserStats, error) {
+	if _, err := s.users.GetUserByUUID(ctx, userUUID); err != nil {
+		return UserStats{}, err
+	}
+
+	var stats UserStats
+	var err error
+	if stats.ListingsByType, err = s.repo.ListingsByType(ctx, userUUID); err != nil {
+		return UserStats{}, err
+	}
+	if stats.Sales, stats.SalesValue, err = s.repo.Sales(ctx, userUUID); err != nil {
+		return UserStats{}, err
+	}
+	if stats.UnreadMessages, err = s.repo.UnreadMessages(ctx, userUUID); err != nil {
+		return UserStats{}, err
+	}
+
+	today := s.now().UTC().Truncate(24 * time.Hour)
+	first := today.AddDate(0, 0, 1-days)
+	views, err := s.repo.DailyViews(ctx, userUUID, first)
+	if err != nil {
+		return UserStats{}, err
+	}
+	stats.Views = make([]DailyViews, 0, days)
+	for d := first; !d.After(today); d = d.AddDate(0, 0, 1) {
+		day := d.Format(time.DateOnly)
+		stats.Views = append(stats.Views, DailyViews{Day: day, Views: views[day]})
+	}
+	return stats, nil
+}
+
+type postgresStatsRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresStatsRepository(pool *pgxpool.Pool) StatsRepository {
+	return &postgresStatsRepository{pool: pool}
+}
+
+func (r *postgresStatsRepository) ListingsByType(ctx context.Context, userUUID string) (map[string]int64, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT asset_type, COUNT(*) FROM assets
+		WHERE user_uuid = $1 AND is_active = true AND is_sold = false AND is_deleted = false
+		GROUP BY asset_type
+		UNION ALL
+		SELECT $2::text, COUNT(*) FROM startups
+		WHERE owner_uuid = $1 AND status <> 'sold' AND is_deleted = false
+		HAVING COUNT(*) > 0`, userUUID, ListingTypeStartup)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var typ string
+		var n int64
+		if err := rows.Scan(&typ, &n); err != nil {
+			return nil, err
+		}
+		counts[typ] = n
+	}
+	return counts, rows.Err()
+}
+
+func (r *postgresStatsRepository) Sales(ctx context.Context, userUUID string) (int64, float64, error) {
+	var n int64
+	var value float64
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(t.final_price), 0)::float8
+		FROM transactions t
+		JOIN assets a ON a.id = t.asset_id
+		WHERE a.user_uuid = $1`, userUUID).Scan(&n, &value)
+	return n, value, err
+}
+
+func (r *postgresStatsRepository) UnreadMessages(ctx context.Context, userUUID string) (int64, error) {
+	var n int64
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM messages m
+		JOIN users u ON u.id = m.receiver_id
+		WHERE u.uuid = $1 AND m.is_read = false`, userUUID).Scan(&n)
+	return n, err
+}
+
+// DailyViews counts views of deleted listings too, like the seller dashboard.
+func (r *postgresStatsRepository) DailyViews(ctx context.Context, userUUID string, since time.Time) (map[string]int64, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT to_char(e.occurred_at, 'YYYY-MM-DD'), COUNT(*)
+		FROM events e
+		WHERE e.name = 'listing_viewed' AND e.occurred_at >= $2
+		  AND (
+			(e.properties->>'listing_type' = 'asset' AND e.properties->>'listing_id' IN
+				(SELECT id::text FROM assets WHERE user_uuid = $1))
+			OR (e.properties->>'listing_type' = 'startup' AND e.properties->>'listing_id' IN
+				(SELECT id::text FROM startups WHERE owner_uuid = $1))
+		  )
+		GROUP BY 1`, userUUID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	views := map[string]int64{}
+	for rows.Next() {
+		var day string
+		var n int64
+		if err := rows.Scan(&day, &n); err != nil {
+			return nil, err
+		}
+		views[day] = n
+	}
+	return views, rows.Err()
+}
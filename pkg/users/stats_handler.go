@@ -0,0 +1,64 @@
+package users
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+)
+
+type StatsHandler struct {
+	service *StatsService
+}
+
+func NewStatsHandler(service *StatsService) *StatsHandler {
+	return &StatsHandler{service: service}
+}
+
+func (h *StatsHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/users/:uuid/stats", auth.RequireSelf("uuid"), h.stats)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *StatsHandler) Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:uuid/stats",
+			Tag:         "users",
+			Summary:     "Seller stats",
+			Description: "Active listings by asset type (startups under \"startup\"), number and value of sales, unread messages and daily listing views, in one response. Views are only counted when analytics events are stored in Postgres.",
+			Params: []openapi.Param{
+				openapi.Path("uuid", "string", "User UUID"),
+				openapi.Query("days", "integer", "Days of views to return, today included (default 30, max 90)", false),
+			},
+			Response: UserStats{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+			Auth:     true,
+		},
+	}
+}
+
+func (h *StatsHandler) stats(c *gin.Context) {
+	days := DefaultStatsDays
+	if raw := c.Query("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > MaxStatsDays {
+			response.SendError(c, response.InvalidField(apperr.InvalidRequest, "days", "range", "days must be between 1 and 90"))
+			return
+		}
+		days = n
+	}
+
+	stats, err := h.service.Stats(c.Request.Context(), c.Param("uuid"), days)
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "stats fetched", stats)
+}
@@ -0,0 +1,80 @@
+package users
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/testhelpers"
+)
+
+type fakeStatsRepo struct {
+	viewsSince time.Time
+}
+
+func (f *fakeStatsRepo) ListingsByType(context.Context, string) (map[string]int64, error) {
+	return map[string]int64{"domain": 2, ListingTypeStartup: 1}, nil
+}
+
+func (f *fakeStatsRepo) Sales(context.Context, string) (int64, float64, error) {
+	return 3, 450.5, nil
+}
+
+func (f *fakeStatsRepo) UnreadMessages(context.Context, string) (int64, error) {
+	return 4, nil
+}
+
+func (f *fakeStatsRepo) DailyViews(_ context.Context, _ string, since time.Time) (map[string]int64, error) {
+	f.viewsSince = since
+	return map[string]int64{"2026-03-30": 7}, nil
+}
+
+func newTestStatsService(repo StatsRepository) *StatsService {
+	users := new(mockUserRepository)
+	users.On("GetUserByUUID", mock.Anything, "uuid-1").Return(User{UUID: "uuid-1"}, nil)
+	users.On("GetUserByUUID", mock.Anything, "missing").Return(User{}, ErrUserNotFound)
+	svc := NewStatsService(repo, users)
+	svc.now = func() time.Time { return time.Date(2026, 3, 31, 18, 30, 0, 0, time.UTC) }
+	return svc
+}
+
+func TestStatsService_FillsEveryDay(t *testing.T) {
+	repo := &fakeStatsRepo{}
+	stats, err := newTestStatsService(repo).Stats(context.Background(), "uuid-1", 3)
+	require.NoError(t, err)
+
+	require.Equal(t, time.Date(2026, 3, 29, 0, 0, 0, 0, time.UTC), repo.viewsSince)
+	require.Equal(t, []DailyViews{{"2026-03-29", 0}, {"2026-03-30", 7}, {"2026-03-31", 0}}, stats.Views)
+	require.EqualValues(t, 2, stats.ListingsByType["domain"])
+	require.EqualValues(t, 3, stats.Sales)
+	require.Equal(t, 450.5, stats.SalesValue)
+	require.EqualValues(t, 4, stats.UnreadMessages)
+
+	_, err = newTestStatsService(repo).Stats(context.Background(), "missing", 3)
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestStatsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(testhelpers.AuthAs("uuid-1", "founder"))
+	NewStatsHandler(newTestStatsService(&fakeStatsRepo{})).RegisterRoutes(r)
+
+	for path, want := range map[string]int{
+		"/users/uuid-1/stats":         http.StatusOK,
+		"/users/uuid-1/stats?days=90": http.StatusOK,
+		"/users/uuid-1/stats?days=0":  http.StatusBadRequest,
+		"/users/uuid-1/stats?days=91": http.StatusBadRequest,
+		"/users/uuid-2/stats":         http.StatusForbidden,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, want, w.Code, path)
+	}
+}
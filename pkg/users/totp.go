@@ -0,0 +1,276 @@
+package users
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"grveyard/pkg/apperr"
+)
+
+// Codes follow RFC 6238 with the parameters every authenticator app supports:
+// HMAC-SHA1, 6 digits, 30-second steps.
+const (
+	totpPeriod = 30
+	totpDigits = 6
+	// totpSkew accepts codes this many steps either side of now, for clock drift.
+	totpSkew = 1
+	// maxTOTPFailures wrong codes within totpLockout stop all codes from being
+	// accepted until the window passes, so the 6 digits cannot be brute forced.
+	maxTOTPFailures = 5
+	totpLockout     = 15 * time.Minute
+)
+
+var (
+	ErrTOTPRequired       = apperr.New(apperr.TOTPRequired, "two-factor code required")
+	ErrInvalidTOTPCode    = apperr.New(apperr.InvalidTOTPCode, "invalid two-factor code")
+	ErrTOTPNotEnrolled    = apperr.New(apperr.TOTPNotEnrolled, "two-factor authentication is not set up")
+	ErrTOTPAlreadyEnabled = apperr.New(apperr.TOTPAlreadyEnabled, "two-factor authentication is already enabled")
+	ErrTOTPLocked         = apperr.New(apperr.TOTPLocked, "too many wrong two-factor codes; try again later")
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPState is a user's authenticator secret. It protects logins once EnabledAt is
+// set, which happens when the first code from the app is verified.
+type TOTPState struct {
+	Secret         string
+	EnabledAt      *time.Time
+	LastStep       int64 // time step of the last accepted code, so codes cannot be replayed
+	FailedAttempts int
+	LastFailedAt   *time.Time
+}
+
+// TOTPEnrollment is what an authenticator app needs. ProvisioningURI is the
+// otpauth:// URI to show as a QR code; Secret is for typing in by hand.
+type TOTPEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorRepository interface {
+	// SaveTOTPSecret starts enrollment, replacing a secret that was never verified.
+	// It returns ErrTOTPAlreadyEnabled if two-factor is already on.
+	SaveTOTPSecret(ctx context.Context, userUUID, secret string) error
+	GetTOTP(ctx context.Context, userUUID string) (TOTPState, error)
+	// UseTOTPStep records an accepted code, enabling two-factor if it was pending and
+	// clearing failures. It returns false if step is not after the last one used.
+	UseTOTPStep(ctx context.Context, userUUID string, step int64) (bool, error)
+	// RecordTOTPFailure counts a wrong code at at, starting the count over when the
+	// previous failure is older than totpLockout.
+	RecordTOTPFailure(ctx context.Context, userUUID string, at time.Time) error
+	DeleteTOTP(ctx context.Context, userUUID string) error
+}
+
+// TwoFactorService enrolls users in TOTP two-factor authentication and checks their
+// codes at login.
+type TwoFactorService struct {
+	repo   TwoFactorRepository
+	users  UserRepository
+	issuer string // shown as the account's name in authenticator apps
+	now    func() time.Time
+}
+
+func NewTwoFactorService(repo TwoFactorRepository, users UserRepository, issuer string) *TwoFactorService {
+	return &TwoFactorService{repo: repo, users: users, issuer: issuer, now: time.Now}
+}
+
+// Enroll generates a new secret for the user. Two-factor stays off until Verify
+// accepts a code generated from it.
+func (s *TwoFactorService) Enroll(ctx context.Context, userUUID string) (TOTPEnrollment, error) {
+	u, err := s.users.GetUserByUUID(ctx, userUUID)
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+	raw := make([]byte, 20) // the HMAC-SHA1 key size RFC 4226 recommends
+	if _, err := rand.Read(raw); err != nil {
+		return TOTPEnrollment{}, err
+	}
+	secret := totpEncoding.EncodeToString(raw)
+	if err := s.repo.SaveTOTPSecret(ctx, userUUID, secret); err != nil {
+		return TOTPEnrollment{}, err
+	}
+	return TOTPEnrollment{Secret: secret, ProvisioningURI: s.provisioningURI(u.Email, secret)}, nil
+}
+
+// Verify turns two-factor on once the user proves their app has the secret.
+func (s *TwoFactorService) Verify(ctx context.Context, userUUID, code string) error {
+	state, err := s.repo.GetTOTP(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if state.EnabledAt != nil {
+		return ErrTOTPAlreadyEnabled
+	}
+	return s.check(ctx, userUUID, state, code)
+}
+
+// Disable turns two-factor off. An enabled second factor needs a current code.
+func (s *TwoFactorService) Disable(ctx context.Context, userUUID, code string) error {
+	state, err := s.repo.GetTOTP(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if state.EnabledAt != nil {
+		if err := s.check(ctx, userUUID, state, code); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteTOTP(ctx, userUUID)
+}
+
+// CheckLogin is called after the password is accepted. Users without two-factor
+// pass; others need a valid code.
+func (s *TwoFactorService) CheckLogin(ctx context.Context, userUUID, code string) error {
+	state, err := s.repo.GetTOTP(ctx, userUUID)
+	if errors.Is(err, ErrTOTPNotEnrolled) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if state.EnabledAt == nil {
+		return nil
+	}
+	if code == "" {
+		return ErrTOTPRequired
+	}
+	return s.check(ctx, userUUID, state, code)
+}
+
+func (s *TwoFactorService) check(ctx context.Context, userUUID string, state TOTPState, code string) error {
+	now := s.now()
+	if state.FailedAttempts >= maxTOTPFailures && state.LastFailedAt != nil && now.Sub(*state.LastFailedAt) < totpLockout {
+		return ErrTOTPLocked
+	}
+	if step, ok := matchTOTP(state.Secret, code, now); ok && step > state.LastStep {
+		used, err := s.repo.UseTOTPStep(ctx, userUUID, step)
+		if err != nil {
+			return err
+		}
+		if used {
+			return nil
+		}
+	}
+	if err := s.repo.RecordTOTPFailure(ctx, userUUID, now); err != nil {
+		return err
+	}
+	return ErrInvalidTOTPCode
+}
+
+// provisioningURI follows the Key Uri Format understood by authenticator apps.
+// https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+func (s *TwoFactorService) provisioningURI(email, secret string) string {
+	q := url.Values{
+		"secret":    {secret},
+		"issuer":    {s.issuer},
+		"algorithm": {"SHA1"},
+		"digits":    {fmt.Sprint(totpDigits)},
+		"period":    {fmt.Sprint(totpPeriod)},
+	}
+	return "otpauth://totp/" + url.PathEscape(s.issuer+":"+email) + "?" + q.Encode()
+}
+
+// matchTOTP returns the time step code is valid for, within totpSkew of now.
+func matchTOTP(secret, code string, now time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpCode is the RFC 4226 HOTP value for counter step.
+func totpCode(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1_000_000)
+}
+
+type postgresTwoFactorRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresTwoFactorRepository(pool *pgxpool.Pool) TwoFactorRepository {
+	return &postgresTwoFactorRepository{pool: pool}
+}
+
+func (r *postgresTwoFactorRepository) SaveTOTPSecret(ctx context.Context, userUUID, secret string) error {
+	cmd, err := r.pool.Exec(ctx, `
+		INSERT INTO user_totp (user_uuid, secret) VALUES ($1, $2)
+		ON CONFLICT (user_uuid) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, failed_attempts = 0, last_failed_at = NULL, created_at = NOW()
+		WHERE user_totp.enabled_at IS NULL`, userUUID, secret)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrTOTPAlreadyEnabled
+	}
+	return nil
+}
+
+func (r *postgresTwoFactorRepository) GetTOTP(ctx context.Context, userUUID string) (TOTPState, error) {
+	var state TOTPState
+	err := r.pool.QueryRow(ctx, `
+		SELECT secret, enabled_at, last_used_step, failed_attempts, last_failed_at
+		FROM user_totp WHERE user_uuid = $1`, userUUID).
+		Scan(&state.Secret, &state.EnabledAt, &state.LastStep, &state.FailedAttempts, &state.LastFailedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return TOTPState{}, ErrTOTPNotEnrolled
+	}
+	return state, err
+}
+
+func (r *postgresTwoFactorRepository) UseTOTPStep(ctx context.Context, userUUID string, step int64) (bool, error) {
+	cmd, err := r.pool.Exec(ctx, `
+		UPDATE user_totp
+		SET last_used_step = $2, enabled_at = COALESCE(enabled_at, NOW()), failed_attempts = 0, last_failed_at = NULL
+		WHERE user_uuid = $1 AND last_used_step < $2`, userUUID, step)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+func (r *postgresTwoFactorRepository) RecordTOTPFailure(ctx context.Context, userUUID string, at time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE user_totp
+		SET failed_attempts = CASE WHEN last_failed_at > $3 THEN failed_attempts + 1 ELSE 1 END, last_failed_at = $2
+		WHERE user_uuid = $1`, userUUID, at, at.Add(-totpLockout))
+	return err
+}
+
+func (r *postgresTwoFactorRepository) DeleteTOTP(ctx context.Context, userUUID string) error {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM user_totp WHERE user_uuid = $1`, userUUID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrTOTPNotEnrolled
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"grveyard/pkg/auth"
+	"grveyard/pkg/openapi"
+	"grveyard/pkg/response"
+	"grveyard/pkg/validation"
+)
+
+type TwoFactorHandler struct {
+	service *TwoFactorService
+}
+
+func NewTwoFactorHandler(service *TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{service: service}
+}
+
+func (h *TwoFactorHandler) RegisterRoutes(router *gin.Engine) {
+	group := router.Group("/users/:uuid/2fa", auth.RequireSelf("uuid"))
+	group.POST("", h.enroll)
+	group.POST("/verify", h.verify)
+	group.DELETE("", h.disable)
+}
+
+// Operations documents the routes for the generated OpenAPI spec.
+func (h *TwoFactorHandler) Operations() []openapi.Operation {
+	params := []openapi.Param{openapi.Path("uuid", "string", "User UUID")}
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/users/:uuid/2fa",
+			Tag:         "users",
+			Summary:     "Start two-factor enrollment",
+			Description: "Generates a TOTP secret. Show provisioning_uri as a QR code for an authenticator app, then confirm with POST /users/:uuid/2fa/verify. Enrolling again replaces a secret that was never verified.",
+			Params:      params,
+			Response:    TOTPEnrollment{},
+			Errors:      []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/users/:uuid/2fa/verify",
+			Tag:         "users",
+			Summary:     "Turn on two-factor authentication",
+			Description: "Checks a code from the authenticator app. From then on POST /users/login needs totp_code.",
+			Params:      params,
+			Request:     totpCodeRequest{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusTooManyRequests, http.StatusInternalServerError},
+			Auth:        true,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/users/:uuid/2fa",
+			Tag:         "users",
+			Summary:     "Turn off two-factor authentication",
+			Description: "Needs a current code from the authenticator app when two-factor is on.",
+			Params:      params,
+			Request:     totpCodeRequest{},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError},
+			Auth:        true,
+		},
+	}
+}
+
+type totpCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+func (h *TwoFactorHandler) enroll(c *gin.Context) {
+	enrollment, err := h.service.Enroll(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "two-factor enrollment started", enrollment)
+}
+
+func (h *TwoFactorHandler) verify(c *gin.Context) {
+	var req totpCodeRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	if err := h.service.Verify(c.Request.Context(), c.Param("uuid"), req.Code); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "two-factor authentication enabled", nil)
+}
+
+func (h *TwoFactorHandler) disable(c *gin.Context) {
+	var req totpCodeRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	if err := h.service.Disable(c.Request.Context(), c.Param("uuid"), req.Code); err != nil {
+		response.SendError(c, err)
+		return
+	}
+	response.SendAPIResponse(c, http.StatusOK, true, "two-factor authentication disabled", nil)
+}
@@ -0,0 +1,155 @@
+package users
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTwoFactorRepo struct {
+	states map[string]*TOTPState
+}
+
+func (f *fakeTwoFactorRepo) SaveTOTPSecret(_ context.Context, userUUID, secret string) error {
+	if st, ok := f.states[userUUID]; ok && st.EnabledAt != nil {
+		return ErrTOTPAlreadyEnabled
+	}
+	f.states[userUUID] = &TOTPState{Secret: secret}
+	return nil
+}
+
+func (f *fakeTwoFactorRepo) GetTOTP(_ context.Context, userUUID string) (TOTPState, error) {
+	st, ok := f.states[userUUID]
+	if !ok {
+		return TOTPState{}, ErrTOTPNotEnrolled
+	}
+	return *st, nil
+}
+
+func (f *fakeTwoFactorRepo) UseTOTPStep(_ context.Context, userUUID string, step int64) (bool, error) {
+	st := f.states[userUUID]
+	if step <= st.LastStep {
+		return false, nil
+	}
+	now := time.Now()
+	st.LastStep, st.FailedAttempts, st.LastFailedAt = step, 0, nil
+	if st.EnabledAt == nil {
+		st.EnabledAt = &now
+	}
+	return true, nil
+}
+
+func (f *fakeTwoFactorRepo) RecordTOTPFailure(_ context.Context, userUUID string, at time.Time) error {
+	st := f.states[userUUID]
+	if st.LastFailedAt == nil || !st.LastFailedAt.After(at.Add(-totpLockout)) {
+		st.FailedAttempts = 0
+	}
+	st.FailedAttempts++
+	st.LastFailedAt = &at
+	return nil
+}
+
+func (f *fakeTwoFactorRepo) DeleteTOTP(_ context.Context, userUUID string) error {
+	if _, ok := f.states[userUUID]; !ok {
+		return ErrTOTPNotEnrolled
+	}
+	delete(f.states, userUUID)
+	return nil
+}
+
+func newTestTwoFactorService(t *testing.T, now *time.Time) (*TwoFactorService, *fakeTwoFactorRepo) {
+	t.Helper()
+	users := new(mockUserRepository)
+	users.On("GetUserByUUID", mock.Anything, "uuid-1").Return(User{UUID: "uuid-1", Email: "a@example.com"}, nil)
+	repo := &fakeTwoFactorRepo{states: map[string]*TOTPState{}}
+	svc := NewTwoFactorService(repo, users, "Graveyard")
+	svc.now = func() time.Time { return *now }
+	return svc, repo
+}
+
+func codeAt(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	key, err := totpEncoding.DecodeString(secret)
+	require.NoError(t, err)
+	return totpCode(key, at.Unix()/totpPeriod)
+}
+
+// RFC 6238 appendix B, SHA1, truncated to 6 digits.
+func TestTOTPCode(t *testing.T) {
+	key := []byte("12345678901234567890")
+	for ts, want := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		require.Equal(t, want, totpCode(key, ts/totpPeriod), "t=%d", ts)
+	}
+}
+
+func TestTwoFactorService_EnrollAndLogin(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc, _ := newTestTwoFactorService(t, &now)
+
+	enrollment, err := svc.Enroll(ctx, "uuid-1")
+	require.NoError(t, err)
+	uri, err := url.Parse(enrollment.ProvisioningURI)
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", uri.Scheme)
+	require.Equal(t, "totp", uri.Host)
+	require.Equal(t, "/Graveyard:a@example.com", uri.Path)
+	require.Equal(t, enrollment.Secret, uri.Query().Get("secret"))
+
+	// Not enforced until verified
+	require.NoError(t, svc.CheckLogin(ctx, "uuid-1", ""))
+	require.ErrorIs(t, svc.Verify(ctx, "uuid-1", "000000"), ErrInvalidTOTPCode)
+	require.NoError(t, svc.Verify(ctx, "uuid-1", codeAt(t, enrollment.Secret, now)))
+	_, err = svc.Enroll(ctx, "uuid-1")
+	require.ErrorIs(t, err, ErrTOTPAlreadyEnabled)
+
+	now = now.Add(totpPeriod * time.Second)
+	code := codeAt(t, enrollment.Secret, now)
+	require.ErrorIs(t, svc.CheckLogin(ctx, "uuid-1", ""), ErrTOTPRequired)
+	require.NoError(t, svc.CheckLogin(ctx, "uuid-1", code))
+	// A code works once
+	require.ErrorIs(t, svc.CheckLogin(ctx, "uuid-1", code), ErrInvalidTOTPCode)
+
+	now = now.Add(totpPeriod * time.Second)
+	require.NoError(t, svc.Disable(ctx, "uuid-1", codeAt(t, enrollment.Secret, now)))
+	require.NoError(t, svc.CheckLogin(ctx, "uuid-1", ""))
+}
+
+func TestTwoFactorService_AcceptsAdjacentStep(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc, _ := newTestTwoFactorService(t, &now)
+
+	enrollment, err := svc.Enroll(ctx, "uuid-1")
+	require.NoError(t, err)
+	require.NoError(t, svc.Verify(ctx, "uuid-1", codeAt(t, enrollment.Secret, now.Add(-totpPeriod*time.Second))))
+	require.ErrorIs(t, svc.CheckLogin(ctx, "uuid-1", codeAt(t, enrollment.Secret, now.Add(-2*totpPeriod*time.Second))), ErrInvalidTOTPCode)
+}
+
+func TestTwoFactorService_LocksAfterFailures(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc, repo := newTestTwoFactorService(t, &now)
+
+	enrollment, err := svc.Enroll(ctx, "uuid-1")
+	require.NoError(t, err)
+	enabled := now
+	repo.states["uuid-1"].EnabledAt = &enabled
+
+	for range maxTOTPFailures {
+		require.ErrorIs(t, svc.CheckLogin(ctx, "uuid-1", "000000"), ErrInvalidTOTPCode)
+	}
+	require.ErrorIs(t, svc.CheckLogin(ctx, "uuid-1", codeAt(t, enrollment.Secret, now)), ErrTOTPLocked)
+
+	now = now.Add(totpLockout)
+	require.NoError(t, svc.CheckLogin(ctx, "uuid-1", codeAt(t, enrollment.Secret, now)))
+}
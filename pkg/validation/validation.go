@@ -0,0 +1,127 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"grveyard/pkg/apperr"
+	"grveyard/pkg/i18n"
+	"grveyard/pkg/response"
+)
+
+var registerOnce sync.Once
+
+// register makes validator report json field names instead of Go struct field names.
+func register() {
+	registerOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return f.Name
+			}
+			return name
+		})
+	})
+}
+
+// BindJSON decodes and validates the request body into obj. On failure it writes a
+// 400 (or 413 for oversized bodies) with field-level errors in the caller's
+// Accept-Language and returns false; the handler should just return.
+func BindJSON(c *gin.Context, obj any) bool {
+	register()
+	err := c.ShouldBindJSON(obj)
+	if err == nil {
+		return true
+	}
+
+	locale := response.Locale(c)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		response.SendError(c, apperr.New(apperr.PayloadTooLarge, i18n.T(locale, "validation.too_large")))
+		return false
+	}
+	fields, ok := Errors(err, locale)
+	if !ok {
+		code, key := apperr.InvalidJSON, "validation.invalid_json"
+		if errors.Is(err, io.EOF) {
+			code, key = apperr.EmptyBody, "validation.empty_body"
+		}
+		response.SendError(c, apperr.New(code, i18n.T(locale, key)))
+		return false
+	}
+	response.SendError(c, apperr.New(apperr.ValidationFailed, i18n.T(locale, "validation.failed")).WithDetails(response.ValidationErrors{Errors: fields}))
+	return false
+}
+
+// Errors converts validator and JSON type errors into field errors. It reports false
+// for errors that are not about a specific field, such as malformed JSON.
+func Errors(err error, locale string) ([]response.FieldError, bool) {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		out := make([]response.FieldError, 0, len(verrs))
+		for _, fe := range verrs {
+			out = append(out, response.FieldError{Field: fe.Field(), Rule: fe.Tag(), Message: message(locale, fe)})
+		}
+		return out, true
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []response.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: i18n.T(locale, "validation.type."+kindName(typeErr.Type.Kind()), typeErr.Field),
+		}}, true
+	}
+	return nil, false
+}
+
+func message(locale string, fe validator.FieldError) string {
+	field := fe.Field()
+	switch fe.Tag() {
+	case "required", "email", "url", "uuid":
+		return i18n.T(locale, "validation."+fe.Tag(), field)
+	case "min", "max":
+		if fe.Kind() == reflect.String {
+			return i18n.T(locale, "validation."+fe.Tag()+"_len", field, fe.Param())
+		}
+		return i18n.T(locale, "validation."+fe.Tag(), field, fe.Param())
+	case "gte", "lte", "gt", "lt":
+		return i18n.T(locale, "validation."+fe.Tag(), field, fe.Param())
+	case "oneof":
+		return i18n.T(locale, "validation.oneof", field, strings.ReplaceAll(fe.Param(), " ", ", "))
+	default:
+		return i18n.T(locale, "validation.invalid", field)
+	}
+}
+
+func kindName(k reflect.Kind) string {
+	switch k {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return "value"
+	}
+}
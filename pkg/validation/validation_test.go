@@ -0,0 +1,130 @@
+package validation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"grveyard/pkg/response"
+)
+
+type signupRequest struct {
+	Name  string  `json:"name" binding:"required,max=5"`
+	Email string  `json:"email" binding:"required,email"`
+	Price float64 `json:"price" binding:"gte=0"`
+}
+
+func setupRouter(middleware ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware...)
+	handler := func(c *gin.Context) {
+		var req signupRequest
+		if !BindJSON(c, &req) {
+			return
+		}
+		response.SendAPIResponse(c, http.StatusOK, true, "ok", nil)
+	}
+	r.POST("/signup", handler)
+	r.POST("/email/unsubscribe", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func send(r *gin.Engine, path, contentType, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func decodeErrors(t *testing.T, w *httptest.ResponseRecorder) (string, []response.FieldError) {
+	var resp struct {
+		Message string                    `json:"message"`
+		Data    response.ValidationErrors `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Message, resp.Data.Errors
+}
+
+func TestBindJSON_FieldErrors(t *testing.T) {
+	r := setupRouter()
+
+	w := send(r, "/signup", "application/json", `{"name":"Alexander","email":"nope","price":-1}`)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	msg, errs := decodeErrors(t, w)
+	require.Equal(t, "validation failed", msg)
+	require.Equal(t, []response.FieldError{
+		{Field: "name", Rule: "max", Message: "name must be at most 5 characters"},
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+		{Field: "price", Rule: "gte", Message: "price must be at least 0"},
+	}, errs)
+}
+
+func TestBindJSON_Translated(t *testing.T) {
+	r := setupRouter()
+
+	w := send(r, "/signup", "application/json", `{"email":"a@example.com"}`, "Accept-Language", "hi-IN")
+
+	msg, errs := decodeErrors(t, w)
+	require.Equal(t, "सत्यापन विफल रहा", msg)
+	require.Equal(t, "name आवश्यक है", errs[0].Message)
+}
+
+func TestBindJSON_TypeMismatch(t *testing.T) {
+	r := setupRouter()
+
+	w := send(r, "/signup", "application/json", `{"name":"a","email":"a@example.com","price":"ten"}`)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	_, errs := decodeErrors(t, w)
+	require.Equal(t, []response.FieldError{{Field: "price", Rule: "type", Message: "price must be a number"}}, errs)
+}
+
+func TestBindJSON_MalformedAndEmpty(t *testing.T) {
+	r := setupRouter()
+
+	msg, errs := decodeErrors(t, send(r, "/signup", "application/json", `{"name":`))
+	require.Equal(t, "request body is not valid JSON", msg)
+	require.Empty(t, errs)
+
+	msg, _ = decodeErrors(t, send(r, "/signup", "application/json", ``))
+	require.Equal(t, "request body is required", msg)
+}
+
+func TestBodyLimit(t *testing.T) {
+	r := setupRouter(BodyLimit(16))
+
+	w := send(r, "/signup", "application/json", `{"name":"a","email":"a@example.com"}`)
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	// Chunked bodies have no declared length and are cut off while decoding
+	req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(`{"name":"a","email":"a@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = -1
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestRequireJSON(t *testing.T) {
+	r := setupRouter(RequireJSON("/email/unsubscribe"))
+
+	w := send(r, "/signup", "text/plain", `{"name":"a","email":"a@example.com"}`)
+	require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	w = send(r, "/signup", "application/json; charset=utf-8", `{"name":"a","email":"a@example.com"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = send(r, "/email/unsubscribe", "application/x-www-form-urlencoded", "List-Unsubscribe=One-Click")
+	require.Equal(t, http.StatusOK, w.Code)
+}